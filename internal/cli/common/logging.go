@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
+)
+
+const (
+	logFormatFlag      = "log-format"
+	logDestinationFlag = "log-destination"
+	logLevelFlag       = "log-level"
+)
+
+// AddLoggingFlags adds the flags configuring the log lines of services started on their own
+func AddLoggingFlags(cmd *cobra.Command) {
+	cmd.Flags().String(logFormatFlag, config.TextLogFormat, "Format of log lines (\"text\" or \"json\")")
+	cmd.Flags().String(logDestinationFlag, config.StderrLogDestination, "Where log lines are written (\"stderr\", \"stdout\" or the path of a file)")
+	cmd.Flags().String(logLevelFlag, "info", "Minimum level of log lines (\"debug\", \"info\", \"warn\" or \"error\")")
+}
+
+// SetupLoggingFromFlags sets up logging as configured by the flags added by AddLoggingFlags
+func SetupLoggingFromFlags(cmd *cobra.Command) error {
+	var loggingConfig config.Logging
+	var err error
+	loggingConfig.Format, err = cmd.Flags().GetString(logFormatFlag)
+	if err != nil {
+		return err
+	}
+	loggingConfig.Destination, err = cmd.Flags().GetString(logDestinationFlag)
+	if err != nil {
+		return err
+	}
+	loggingConfig.Level, err = cmd.Flags().GetString(logLevelFlag)
+	if err != nil {
+		return err
+	}
+	return utils.SetupLogging(loggingConfig)
+}
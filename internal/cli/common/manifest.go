@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/paraglider-project/paraglider/pkg/orchestrator"
+)
+
+// ReadManifest reads a YAML or JSON manifest from a file
+func ReadManifest(path string) (*orchestrator.Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return orchestrator.ParseManifest(data)
+}
+
+// PrintManifestDiff prints the changes of a diff, one per line
+func PrintManifestDiff(w io.Writer, diff *orchestrator.ManifestDiff) {
+	if diff.Empty() {
+		fmt.Fprintf(w, "No changes.\n")
+		return
+	}
+	for _, tag := range diff.Tags {
+		fmt.Fprintf(w, "tag %s:\n", tag.Name)
+		for _, member := range tag.MembersToAdd {
+			fmt.Fprintf(w, "  + member %s\n", member)
+		}
+		for _, member := range tag.MembersToDelete {
+			fmt.Fprintf(w, "  - member %s\n", member)
+		}
+	}
+	for _, resource := range diff.Resources {
+		fmt.Fprintf(w, "resource %s/%s:\n", resource.Cloud, resource.Name)
+		if resource.Create {
+			fmt.Fprintf(w, "  + create\n")
+		}
+		for _, rule := range resource.RulesToDelete {
+			fmt.Fprintf(w, "  - rule %s\n", rule)
+		}
+		for _, rule := range resource.RulesToAdd {
+			fmt.Fprintf(w, "  + rule %s\n", rule.Name)
+		}
+	}
+}
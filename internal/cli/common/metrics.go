@@ -0,0 +1,39 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
+)
+
+const metricsAddressFlag = "metrics-address"
+
+// AddMetricsFlag adds the flag for the address services started on their own serve Prometheus metrics on
+func AddMetricsFlag(cmd *cobra.Command) {
+	cmd.Flags().String(metricsAddressFlag, "", "Address to serve Prometheus metrics at /metrics on (e.g., \":9090\"), disabled if empty")
+}
+
+// ServeMetricsFromFlag starts serving the metrics if the flag added by AddMetricsFlag is set
+func ServeMetricsFromFlag(cmd *cobra.Command) error {
+	address, err := cmd.Flags().GetString(metricsAddressFlag)
+	if err != nil || address == "" {
+		return err
+	}
+	return utils.ServeMetrics(address)
+}
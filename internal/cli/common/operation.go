@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/paraglider-project/paraglider/pkg/client"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator"
+	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
+	"github.com/spf13/cobra"
+)
+
+const waitFlag = "wait"
+
+// AddWaitFlag adds the --wait flag to commands which start operations on the controller
+func AddWaitFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool(waitFlag, true, "Wait for the operation to finish (otherwise print the operation ID and return)")
+}
+
+func GetWaitFlag(cmd *cobra.Command) (bool, error) {
+	return cmd.Flags().GetBool(waitFlag)
+}
+
+// WaitForOperation waits for an operation started by cmd to finish if wait is set, printing its warnings and side
+// effects. Otherwise, it prints how to follow the operation and returns nil.
+func WaitForOperation(cmd *cobra.Command, w io.Writer, c *client.Client, operation *orchestrator.Operation, wait bool) (*orchestrator.Operation, error) {
+	if !wait {
+		fmt.Fprintf(w, "Operation %s started.\nRun `glide operation get %s` to check its status.\n", operation.Id, operation.Id)
+		return nil, nil
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	operation, err := c.WaitForOperation(ctx, operation)
+	if operation != nil {
+		PrintNotices(w, &operation.RequestNotices)
+	}
+	return operation, err
+}
+
+// PrintNotices prints the warnings and side effects of a request
+func PrintNotices(w io.Writer, notices *orchestrator.RequestNotices) {
+	for _, warning := range notices.Warnings {
+		fmt.Fprintf(w, "Warning: %s\n", formatNotice(warning))
+	}
+	for _, sideEffect := range notices.SideEffects {
+		fmt.Fprintf(w, "Side effect: %s\n", formatNotice(sideEffect))
+	}
+}
+
+func formatNotice(notice *paragliderpb.Notice) string {
+	if notice.Resource == "" || strings.Contains(notice.Message, notice.Resource) {
+		return notice.Message
+	}
+	return fmt.Sprintf("%s (%s)", notice.Message, notice.Resource)
+}
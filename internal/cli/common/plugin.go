@@ -0,0 +1,43 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/spf13/cobra"
+)
+
+const advertiseAddressFlag = "advertise-address"
+
+// AddAdvertiseAddressFlag adds the flag for the address cloud plugins register with the controller
+func AddAdvertiseAddressFlag(cmd *cobra.Command) {
+	cmd.Flags().String(advertiseAddressFlag, "", "Address the controller reaches the plugin at (e.g., \"plugin.example.com:1000\"). If set, the plugin listens on all interfaces, otherwise only on localhost")
+}
+
+// GetAdvertiseAddressFlag returns the address set with the flag added by AddAdvertiseAddressFlag
+func GetAdvertiseAddressFlag(cmd *cobra.Command) (string, error) {
+	address, err := cmd.Flags().GetString(advertiseAddressFlag)
+	if err != nil || address == "" {
+		return address, err
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return "", fmt.Errorf("invalid advertise address: %w", err)
+	}
+	return address, nil
+}
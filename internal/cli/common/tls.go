@@ -0,0 +1,50 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+)
+
+const (
+	tlsCertFlag = "tls-cert"
+	tlsKeyFlag  = "tls-key"
+	tlsCAFlag   = "tls-ca"
+)
+
+// AddTLSFlags adds the flags for the certificates used on the gRPC links between services
+func AddTLSFlags(cmd *cobra.Command) {
+	cmd.Flags().String(tlsCertFlag, "", "Certificate presented to other services (enables mutual TLS)")
+	cmd.Flags().String(tlsKeyFlag, "", "Private key of the certificate")
+	cmd.Flags().String(tlsCAFlag, "", "CA bundle used to verify other services")
+	cmd.MarkFlagsRequiredTogether(tlsCertFlag, tlsKeyFlag, tlsCAFlag)
+}
+
+func GetTLSFlags(cmd *cobra.Command) (config.TLS, error) {
+	var tlsConfig config.TLS
+	var err error
+	if tlsConfig.CertFile, err = cmd.Flags().GetString(tlsCertFlag); err != nil {
+		return tlsConfig, err
+	}
+	if tlsConfig.KeyFile, err = cmd.Flags().GetString(tlsKeyFlag); err != nil {
+		return tlsConfig, err
+	}
+	tlsConfig.CAFile, err = cmd.Flags().GetString(tlsCAFlag)
+	return tlsConfig, err
+}
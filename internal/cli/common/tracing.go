@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
+)
+
+const (
+	traceExporterFlag = "trace-exporter"
+	traceEndpointFlag = "trace-endpoint"
+	traceInsecureFlag = "trace-insecure"
+)
+
+// AddTracingFlags adds the flags configuring where services started on their own export their traces to
+func AddTracingFlags(cmd *cobra.Command) {
+	cmd.Flags().String(traceExporterFlag, "", "Exporter of the spans of traces (\"otlp\" or \"stdout\"), disabled if empty")
+	cmd.Flags().String(traceEndpointFlag, "", "Address of the OTLP collector (default localhost:4317)")
+	cmd.Flags().Bool(traceInsecureFlag, false, "Connect to the OTLP collector without TLS")
+}
+
+// SetupTracingFromFlags sets up tracing as configured by the flags added by AddTracingFlags.
+// The returned function flushes the spans which haven't been exported yet.
+func SetupTracingFromFlags(cmd *cobra.Command, serviceName string) (func(context.Context) error, error) {
+	var tracingConfig config.Tracing
+	var err error
+	tracingConfig.Exporter, err = cmd.Flags().GetString(traceExporterFlag)
+	if err != nil {
+		return nil, err
+	}
+	tracingConfig.Endpoint, err = cmd.Flags().GetString(traceEndpointFlag)
+	if err != nil {
+		return nil, err
+	}
+	tracingConfig.Insecure, err = cmd.Flags().GetBool(traceInsecureFlag)
+	if err != nil {
+		return nil, err
+	}
+	return utils.SetupTracing(context.Background(), serviceName, tracingConfig)
+}
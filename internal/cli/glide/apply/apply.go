@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	common "github.com/paraglider-project/paraglider/internal/cli/common"
+	"github.com/paraglider-project/paraglider/internal/cli/glide/config"
+	"github.com/paraglider-project/paraglider/pkg/client"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator"
+	"github.com/spf13/cobra"
+)
+
+func NewCommand() (*cobra.Command, *executor) {
+	executor := &executor{writer: os.Stdout, cliSettings: config.ActiveConfig.Settings, wait: true}
+	cmd := &cobra.Command{
+		Use:     "apply -f <manifest_file>",
+		Short:   "Bring a namespace to the state described by a manifest",
+		Args:    cobra.NoArgs,
+		PreRunE: executor.Validate,
+		RunE:    executor.Execute,
+	}
+	cmd.Flags().StringP("filename", "f", "", "The YAML or JSON manifest to apply")
+	common.AddWaitFlag(cmd)
+	return cmd, executor
+}
+
+type executor struct {
+	common.CommandExecutor
+	writer      io.Writer
+	cliSettings config.CliSettings
+	manifest    *orchestrator.Manifest
+	wait        bool
+}
+
+func (e *executor) SetOutput(w io.Writer) {
+	e.writer = w
+}
+
+func (e *executor) Validate(cmd *cobra.Command, args []string) error {
+	filename, err := cmd.Flags().GetString("filename")
+	if err != nil {
+		return err
+	}
+	if filename == "" {
+		return fmt.Errorf("a manifest file must be provided with -f")
+	}
+	e.manifest, err = common.ReadManifest(filename)
+	if err != nil {
+		return err
+	}
+
+	e.wait, err = common.GetWaitFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (e *executor) Execute(cmd *cobra.Command, args []string) error {
+	namespace := e.manifest.Namespace
+	if namespace == "" {
+		namespace = e.cliSettings.ActiveNamespace
+	}
+
+	fmt.Fprintf(e.writer, "Applying manifest to %s namespace\n", namespace)
+	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr, Credentials: e.cliSettings.Credentials}
+	operation, err := c.StartApplyManifest(namespace, e.manifest)
+	if err == nil {
+		operation, err = common.WaitForOperation(cmd, e.writer, &c, operation, e.wait)
+	}
+	if err != nil {
+		fmt.Fprintf(e.writer, "Failed to apply manifest: %v\n", err)
+		return err
+	}
+	if operation == nil {
+		return nil
+	}
+
+	diff := &orchestrator.ManifestDiff{}
+	if err := json.Unmarshal(operation.Result, diff); err != nil {
+		return err
+	}
+	common.PrintManifestDiff(e.writer, diff)
+
+	return nil
+}
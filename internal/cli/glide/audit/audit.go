@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	common "github.com/paraglider-project/paraglider/internal/cli/common"
+	"github.com/paraglider-project/paraglider/internal/cli/glide/config"
+	"github.com/paraglider-project/paraglider/pkg/client"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator"
+	"github.com/spf13/cobra"
+)
+
+func NewCommand() (*cobra.Command, *executor) {
+	executor := &executor{writer: os.Stdout, cliSettings: config.ActiveConfig.Settings}
+	cmd := &cobra.Command{
+		Use:     "audit [--principal <principal>] [--action <action>] [--namespace <namespace>] [--cloud <cloud>] [--resource <resource>] [--tag <tag>] [--status <status>] [--since <time>] [--until <time>] [--limit <n>]",
+		Short:   "Show the audit log of changes made through the controller",
+		Args:    cobra.NoArgs,
+		PreRunE: executor.Validate,
+		RunE:    executor.Execute,
+	}
+	cmd.Flags().String("principal", "", "Only show changes made by this principal")
+	cmd.Flags().String("action", "", "Only show changes of this kind (e.g., AddPermitListRules)")
+	cmd.Flags().String("namespace", "", "Only show changes to this namespace")
+	cmd.Flags().String("cloud", "", "Only show changes to this cloud")
+	cmd.Flags().String("resource", "", "Only show changes to this resource (name or URI)")
+	cmd.Flags().String("tag", "", "Only show changes to this tag or to rules targeting it")
+	cmd.Flags().String("status", "", "Only show changes which SUCCEEDED or FAILED")
+	cmd.Flags().String("since", "", "Only show changes made after this time (RFC 3339 time or duration ago, e.g., 24h)")
+	cmd.Flags().String("until", "", "Only show changes made before this time (RFC 3339 time or duration ago, e.g., 1h)")
+	cmd.Flags().Int("limit", 0, "Only show the most recent changes")
+	return cmd, executor
+}
+
+type executor struct {
+	common.CommandExecutor
+	writer      io.Writer
+	cliSettings config.CliSettings
+	filter      orchestrator.AuditFilter
+}
+
+func (e *executor) SetOutput(w io.Writer) {
+	e.writer = w
+}
+
+// Parses a time given either in RFC 3339 format or as a duration before now
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: must be an RFC 3339 time or a duration", value)
+	}
+	return t, nil
+}
+
+func (e *executor) Validate(cmd *cobra.Command, args []string) error {
+	e.filter = orchestrator.AuditFilter{}
+	for flag, value := range map[string]*string{
+		"principal": &e.filter.Principal,
+		"action":    &e.filter.Action,
+		"namespace": &e.filter.Namespace,
+		"cloud":     &e.filter.Cloud,
+		"resource":  &e.filter.Resource,
+		"tag":       &e.filter.Tag,
+	} {
+		var err error
+		if *value, err = cmd.Flags().GetString(flag); err != nil {
+			return err
+		}
+	}
+
+	status, err := cmd.Flags().GetString("status")
+	if err != nil {
+		return err
+	}
+	e.filter.Status = orchestrator.AuditStatus(strings.ToUpper(status))
+	if e.filter.Status != "" && e.filter.Status != orchestrator.AuditSucceeded && e.filter.Status != orchestrator.AuditFailed {
+		return fmt.Errorf("invalid status %q: must be %s or %s", status, orchestrator.AuditSucceeded, orchestrator.AuditFailed)
+	}
+
+	for flag, t := range map[string]*time.Time{"since": &e.filter.Since, "until": &e.filter.Until} {
+		value, err := cmd.Flags().GetString(flag)
+		if err != nil {
+			return err
+		}
+		if *t, err = parseTime(value); err != nil {
+			return err
+		}
+	}
+
+	e.filter.Limit, err = cmd.Flags().GetInt("limit")
+	if err != nil {
+		return err
+	}
+	if e.filter.Limit < 0 {
+		return fmt.Errorf("invalid limit %d: must not be negative", e.filter.Limit)
+	}
+	return nil
+}
+
+// Describes what a request changed (e.g., "fakenamespace/fakecloud/vm-1" or "tag web")
+func eventTarget(event *orchestrator.AuditEvent) string {
+	if event.Tag != "" {
+		return "tag " + event.Tag
+	}
+	parts := []string{}
+	for _, part := range []string{event.Namespace, event.Cloud, event.Resource} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+func (e *executor) Execute(cmd *cobra.Command, args []string) error {
+	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr, Credentials: e.cliSettings.Credentials}
+	events, err := c.ListAuditEvents(e.filter)
+	if err != nil {
+		return err
+	}
+
+	if len(events) == 0 {
+		fmt.Fprintf(e.writer, "No audit events found.\n")
+		return nil
+	}
+	for _, event := range events {
+		principal := event.Principal
+		if principal == "" {
+			principal = "-"
+		}
+		fmt.Fprintf(e.writer, "%s\t%s\t%s\t%s\t%s\n", event.Time.Format(time.RFC3339), principal, event.Action, event.Status, eventTarget(event))
+		if len(event.Request) > 0 && string(event.Request) != "null" {
+			fmt.Fprintf(e.writer, "  request: %s\n", event.Request)
+		}
+		for _, resource := range event.Resources {
+			fmt.Fprintf(e.writer, "  %s %s/%s/%s\n", resource.Method, resource.Namespace, resource.Cloud, resource.Uri)
+		}
+		if event.Error != "" {
+			fmt.Fprintf(e.writer, "  error: %s\n", event.Error)
+		}
+	}
+
+	return nil
+}
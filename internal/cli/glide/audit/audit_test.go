@@ -0,0 +1,88 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/paraglider-project/paraglider/internal/cli/glide/config"
+	fake "github.com/paraglider-project/paraglider/pkg/fake/orchestrator/rest"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditValidate(t *testing.T) {
+	err := config.ReadOrCreateConfig()
+	require.Nil(t, err)
+
+	cmd, executor := NewCommand()
+	require.Nil(t, cmd.Flags().Set("principal", "alice"))
+	require.Nil(t, cmd.Flags().Set("status", "failed"))
+	require.Nil(t, cmd.Flags().Set("since", "2024-01-01T00:00:00Z"))
+	require.Nil(t, cmd.Flags().Set("until", "1h"))
+	require.Nil(t, cmd.Flags().Set("limit", "5"))
+
+	err = executor.Validate(cmd, []string{})
+
+	require.Nil(t, err)
+	assert.Equal(t, "alice", executor.filter.Principal)
+	assert.Equal(t, orchestrator.AuditFailed, executor.filter.Status)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), executor.filter.Since)
+	assert.WithinDuration(t, time.Now().Add(-time.Hour), executor.filter.Until, time.Minute)
+	assert.Equal(t, 5, executor.filter.Limit)
+
+	require.Nil(t, cmd.Flags().Set("since", "yesterday"))
+	assert.NotNil(t, executor.Validate(cmd, []string{}))
+
+	cmd, executor = NewCommand()
+	require.Nil(t, cmd.Flags().Set("status", "pending"))
+	assert.NotNil(t, executor.Validate(cmd, []string{}))
+}
+
+func TestAuditExecute(t *testing.T) {
+	server := &fake.FakeOrchestratorRESTServer{}
+	serverAddr := server.SetupFakeOrchestratorRESTServer()
+
+	err := config.ReadOrCreateConfig()
+	assert.Nil(t, err)
+
+	cmd, executor := NewCommand()
+	executor.cliSettings = config.CliSettings{ServerAddr: serverAddr}
+	var output bytes.Buffer
+	executor.writer = &output
+
+	err = executor.Execute(cmd, []string{})
+
+	assert.Nil(t, err)
+	assert.Contains(t, output.String(), "alice\tAddPermitListRules\tSUCCEEDED\t"+fake.Namespace+"/"+fake.CloudName+"/"+fake.ResourceName)
+	assert.Contains(t, output.String(), "AddPermitListRules "+fake.Namespace+"/"+fake.CloudName+"/"+fake.Uri)
+	assert.Contains(t, output.String(), "bob\tDeleteTag\tFAILED\ttag tag")
+	assert.Contains(t, output.String(), "error: tag not found")
+
+	// Filtered
+	output.Reset()
+	executor.filter = orchestrator.AuditFilter{Principal: "nobody"}
+	err = executor.Execute(cmd, []string{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "No audit events found.\n", output.String())
+}
@@ -20,6 +20,8 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+
+	"github.com/paraglider-project/paraglider/pkg/client"
 )
 
 const (
@@ -38,8 +40,9 @@ type CliConfig struct {
 }
 
 type CliSettings struct {
-	ServerAddr      string `json:"serverAddr"`
-	ActiveNamespace string `json:"activeNamespace"`
+	ServerAddr      string             `json:"serverAddr"`
+	ActiveNamespace string             `json:"activeNamespace"`
+	Credentials     client.Credentials `json:"credentials,omitempty"`
 }
 
 func ReadOrCreateConfig() error {
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	common "github.com/paraglider-project/paraglider/internal/cli/common"
+	"github.com/paraglider-project/paraglider/internal/cli/glide/config"
+	"github.com/paraglider-project/paraglider/pkg/client"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator"
+	"github.com/spf13/cobra"
+)
+
+func NewCommand() (*cobra.Command, *executor) {
+	executor := &executor{writer: os.Stdout, cliSettings: config.ActiveConfig.Settings}
+	cmd := &cobra.Command{
+		Use:     "diff -f <manifest_file>",
+		Short:   "Show the changes applying a manifest would make",
+		Args:    cobra.NoArgs,
+		PreRunE: executor.Validate,
+		RunE:    executor.Execute,
+	}
+	cmd.Flags().StringP("filename", "f", "", "The YAML or JSON manifest to compare against")
+	return cmd, executor
+}
+
+type executor struct {
+	common.CommandExecutor
+	writer      io.Writer
+	cliSettings config.CliSettings
+	manifest    *orchestrator.Manifest
+}
+
+func (e *executor) SetOutput(w io.Writer) {
+	e.writer = w
+}
+
+func (e *executor) Validate(cmd *cobra.Command, args []string) error {
+	filename, err := cmd.Flags().GetString("filename")
+	if err != nil {
+		return err
+	}
+	if filename == "" {
+		return fmt.Errorf("a manifest file must be provided with -f")
+	}
+	e.manifest, err = common.ReadManifest(filename)
+	return err
+}
+
+func (e *executor) Execute(cmd *cobra.Command, args []string) error {
+	namespace := e.manifest.Namespace
+	if namespace == "" {
+		namespace = e.cliSettings.ActiveNamespace
+	}
+
+	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr, Credentials: e.cliSettings.Credentials}
+	diff, err := c.DiffManifest(namespace, e.manifest)
+	if err != nil {
+		return err
+	}
+
+	common.PrintManifestDiff(e.writer, diff)
+
+	return nil
+}
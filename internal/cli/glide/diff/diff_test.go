@@ -0,0 +1,66 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paraglider-project/paraglider/internal/cli/glide/config"
+	fake "github.com/paraglider-project/paraglider/pkg/fake/orchestrator/rest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffExecute(t *testing.T) {
+	server := &fake.FakeOrchestratorRESTServer{}
+	serverAddr := server.SetupFakeOrchestratorRESTServer()
+
+	err := config.ReadOrCreateConfig()
+	assert.Nil(t, err)
+
+	cmd, executor := NewCommand()
+	executor.cliSettings = config.CliSettings{ServerAddr: serverAddr, ActiveNamespace: fake.Namespace}
+	var output bytes.Buffer
+	executor.writer = &output
+
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	manifest := `{"resources": [{"cloud": "` + fake.CloudName + `", "name": "` + fake.ResourceName + `", "description": {"name": "vm"}, "permitList": []}]}`
+	require.Nil(t, os.WriteFile(path, []byte(manifest), 0o600))
+	require.Nil(t, cmd.Flags().Set("filename", path))
+	require.Nil(t, executor.Validate(cmd, []string{}))
+
+	err = executor.Execute(cmd, []string{})
+
+	assert.Nil(t, err)
+	assert.Contains(t, output.String(), "resource "+fake.CloudName+"/"+fake.ResourceName)
+	assert.Contains(t, output.String(), "+ create")
+
+	// Empty manifests change nothing
+	require.Nil(t, os.WriteFile(path, []byte(`{}`), 0o600))
+	require.Nil(t, executor.Validate(cmd, []string{}))
+	output.Reset()
+
+	err = executor.Execute(cmd, []string{})
+
+	assert.Nil(t, err)
+	assert.Contains(t, output.String(), "No changes.")
+}
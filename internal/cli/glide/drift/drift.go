@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	common "github.com/paraglider-project/paraglider/internal/cli/common"
+	"github.com/paraglider-project/paraglider/internal/cli/glide/config"
+	"github.com/paraglider-project/paraglider/pkg/client"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator"
+	"github.com/spf13/cobra"
+)
+
+func NewCommand() (*cobra.Command, *executor) {
+	executor := &executor{writer: os.Stdout, cliSettings: config.ActiveConfig.Settings, wait: true}
+	cmd := &cobra.Command{
+		Use:     "drift [--check] [--reconcile]",
+		Short:   "Show permit list rules which drifted from what their tags resolve to",
+		Args:    cobra.NoArgs,
+		PreRunE: executor.Validate,
+		RunE:    executor.Execute,
+	}
+	cmd.Flags().Bool("check", false, "Check for drift now instead of showing the latest report")
+	cmd.Flags().Bool("reconcile", false, "Check for drift now and re-apply the expected rules")
+	common.AddWaitFlag(cmd)
+	return cmd, executor
+}
+
+type executor struct {
+	common.CommandExecutor
+	writer      io.Writer
+	cliSettings config.CliSettings
+	check       bool
+	reconcile   bool
+	wait        bool
+}
+
+func (e *executor) SetOutput(w io.Writer) {
+	e.writer = w
+}
+
+func (e *executor) Validate(cmd *cobra.Command, args []string) error {
+	var err error
+	e.check, err = cmd.Flags().GetBool("check")
+	if err != nil {
+		return err
+	}
+	e.reconcile, err = cmd.Flags().GetBool("reconcile")
+	if err != nil {
+		return err
+	}
+	e.wait, err = common.GetWaitFlag(cmd)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (e *executor) Execute(cmd *cobra.Command, args []string) error {
+	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr, Credentials: e.cliSettings.Credentials}
+
+	var report *orchestrator.DriftReport
+	if e.check || e.reconcile {
+		operation, err := c.StartCheckDrift(e.reconcile)
+		if err == nil {
+			operation, err = common.WaitForOperation(cmd, e.writer, &c, operation, e.wait)
+		}
+		if err != nil {
+			fmt.Fprintf(e.writer, "Failed to check for drift: %v\n", err)
+			return err
+		}
+		if operation == nil {
+			return nil
+		}
+		report = &orchestrator.DriftReport{}
+		if err := json.Unmarshal(operation.Result, report); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		report, err = c.GetDriftReport()
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(e.writer, "Checked %d resources at %s.\n", report.CheckedResources, report.CheckedAt.Format(time.RFC3339))
+	if len(report.Resources) == 0 {
+		fmt.Fprintf(e.writer, "No drift detected.\n")
+		return nil
+	}
+	for _, resource := range report.Resources {
+		fmt.Fprintf(e.writer, "resource %s/%s/%s:\n", resource.Namespace, resource.Cloud, resource.Uri)
+		for _, rule := range resource.Rules {
+			switch rule.Status {
+			case orchestrator.RuleDriftMissing:
+				fmt.Fprintf(e.writer, "  rule %s: missing, expected [%s]\n", rule.Name, strings.Join(rule.ExpectedTargets, ", "))
+			case orchestrator.RuleDriftUnexpected:
+				fmt.Fprintf(e.writer, "  rule %s: unexpected, found [%s]\n", rule.Name, strings.Join(rule.ActualTargets, ", "))
+			default:
+				fmt.Fprintf(e.writer, "  rule %s: expected [%s], found [%s]", rule.Name, strings.Join(rule.ExpectedTargets, ", "), strings.Join(rule.ActualTargets, ", "))
+				if len(rule.ChangedSettings) > 0 {
+					fmt.Fprintf(e.writer, " (changed %s)", strings.Join(rule.ChangedSettings, ", "))
+				}
+				fmt.Fprintln(e.writer)
+			}
+		}
+		if resource.Reconciled {
+			fmt.Fprintf(e.writer, "  reconciled\n")
+		}
+		if resource.Error != "" {
+			fmt.Fprintf(e.writer, "  error: %s\n", resource.Error)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package create
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	common "github.com/paraglider-project/paraglider/internal/cli/common"
+	"github.com/paraglider-project/paraglider/internal/cli/glide/config"
+	"github.com/paraglider-project/paraglider/pkg/client"
+	orchestratorconfig "github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+	"github.com/spf13/cobra"
+)
+
+func NewCommand() (*cobra.Command, *executor) {
+	executor := &executor{writer: os.Stdout, cliSettings: config.ActiveConfig.Settings}
+	cmd := &cobra.Command{
+		Use:     "create <namespace> [--cloud <cloud>=<deployment>]",
+		Short:   "Create a namespace on the controller",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: executor.Validate,
+		RunE:    executor.Execute,
+	}
+	cmd.Flags().StringSlice("cloud", []string{}, "Cloud deployments of the namespace as <cloud>=<deployment> (e.g., gcp=projects/my-project)")
+	return cmd, executor
+}
+
+type executor struct {
+	common.CommandExecutor
+	writer      io.Writer
+	cliSettings config.CliSettings
+	clouds      []orchestratorconfig.CloudDeployment
+}
+
+func (e *executor) SetOutput(w io.Writer) {
+	e.writer = w
+}
+
+func (e *executor) Validate(cmd *cobra.Command, args []string) error {
+	clouds, err := cmd.Flags().GetStringSlice("cloud")
+	if err != nil {
+		return err
+	}
+
+	e.clouds = []orchestratorconfig.CloudDeployment{}
+	for _, cloud := range clouds {
+		name, deployment, ok := strings.Cut(cloud, "=")
+		if !ok || name == "" || deployment == "" {
+			return fmt.Errorf("invalid cloud deployment %q: expected <cloud>=<deployment>", cloud)
+		}
+		e.clouds = append(e.clouds, orchestratorconfig.CloudDeployment{Name: name, Deployment: deployment})
+	}
+	return nil
+}
+
+func (e *executor) Execute(cmd *cobra.Command, args []string) error {
+	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr, Credentials: e.cliSettings.Credentials}
+
+	err := c.CreateNamespace(args[0], e.clouds)
+	if err != nil {
+		fmt.Fprintf(e.writer, "Failed to create namespace: %v\n", err)
+		return err
+	}
+
+	fmt.Fprintf(e.writer, "Namespace %s created.\n", args[0])
+
+	return nil
+}
@@ -0,0 +1,68 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package create
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/paraglider-project/paraglider/internal/cli/glide/config"
+	fake "github.com/paraglider-project/paraglider/pkg/fake/orchestrator/rest"
+	orchestratorconfig "github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespaceCreateValidate(t *testing.T) {
+	err := config.ReadOrCreateConfig()
+	assert.Nil(t, err)
+
+	cmd, executor := NewCommand()
+	err = cmd.Flags().Set("cloud", fake.CloudName+"=deployment")
+	assert.Nil(t, err)
+	err = executor.Validate(cmd, []string{fake.Namespace})
+	assert.Nil(t, err)
+	assert.Equal(t, []orchestratorconfig.CloudDeployment{{Name: fake.CloudName, Deployment: "deployment"}}, executor.clouds)
+
+	// Deployments must be given as <cloud>=<deployment>
+	cmd, executor = NewCommand()
+	err = cmd.Flags().Set("cloud", fake.CloudName)
+	assert.Nil(t, err)
+	err = executor.Validate(cmd, []string{fake.Namespace})
+	assert.NotNil(t, err)
+}
+
+func TestNamespaceCreateExecute(t *testing.T) {
+	server := &fake.FakeOrchestratorRESTServer{}
+	serverAddr := server.SetupFakeOrchestratorRESTServer()
+
+	err := config.ReadOrCreateConfig()
+	assert.Nil(t, err)
+
+	cmd, executor := NewCommand()
+	executor.cliSettings = config.CliSettings{ServerAddr: serverAddr, ActiveNamespace: fake.Namespace}
+	executor.clouds = []orchestratorconfig.CloudDeployment{{Name: fake.CloudName, Deployment: "deployment"}}
+
+	var output bytes.Buffer
+	executor.writer = &output
+
+	err = executor.Execute(cmd, []string{"new-namespace"})
+
+	assert.Nil(t, err)
+	assert.Contains(t, output.String(), "new-namespace")
+}
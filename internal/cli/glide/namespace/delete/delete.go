@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package delete
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	common "github.com/paraglider-project/paraglider/internal/cli/common"
+	"github.com/paraglider-project/paraglider/internal/cli/glide/config"
+	"github.com/paraglider-project/paraglider/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+func NewCommand() (*cobra.Command, *executor) {
+	executor := &executor{writer: os.Stdout, cliSettings: config.ActiveConfig.Settings, wait: true}
+	cmd := &cobra.Command{
+		Use:     "delete <namespace> [--cascade]",
+		Short:   "Delete a namespace from the controller",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: executor.Validate,
+		RunE:    executor.Execute,
+	}
+	cmd.Flags().Bool("cascade", false, "Also delete the VPCs, firewalls and VPN gateways Paraglider created for the namespace in the clouds")
+	common.AddWaitFlag(cmd)
+	return cmd, executor
+}
+
+type executor struct {
+	common.CommandExecutor
+	writer      io.Writer
+	cliSettings config.CliSettings
+	cascade     bool
+	wait        bool
+}
+
+func (e *executor) SetOutput(w io.Writer) {
+	e.writer = w
+}
+
+func (e *executor) Validate(cmd *cobra.Command, args []string) error {
+	var err error
+	e.cascade, err = cmd.Flags().GetBool("cascade")
+	if err != nil {
+		return err
+	}
+	e.wait, err = common.GetWaitFlag(cmd)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (e *executor) Execute(cmd *cobra.Command, args []string) error {
+	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr, Credentials: e.cliSettings.Credentials}
+
+	operation, err := c.StartDeleteNamespace(args[0], e.cascade)
+	if err == nil {
+		operation, err = common.WaitForOperation(cmd, e.writer, &c, operation, e.wait)
+	}
+	if err != nil {
+		fmt.Fprintf(e.writer, "Failed to delete namespace: %v\n", err)
+		return err
+	}
+	if operation == nil {
+		return nil
+	}
+
+	fmt.Fprintf(e.writer, "Namespace %s deleted.\n", args[0])
+
+	return nil
+}
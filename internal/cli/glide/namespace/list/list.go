@@ -54,7 +54,7 @@ func (e *executor) Validate(cmd *cobra.Command, args []string) error {
 }
 
 func (e *executor) Execute(cmd *cobra.Command, args []string) error {
-	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr}
+	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr, Credentials: e.cliSettings.Credentials}
 	namespaces, err := c.ListNamespaces()
 
 	if err != nil {
@@ -17,6 +17,8 @@ limitations under the License.
 package namespace
 
 import (
+	"github.com/paraglider-project/paraglider/internal/cli/glide/namespace/create"
+	"github.com/paraglider-project/paraglider/internal/cli/glide/namespace/delete"
 	"github.com/paraglider-project/paraglider/internal/cli/glide/namespace/get"
 	"github.com/paraglider-project/paraglider/internal/cli/glide/namespace/list"
 	"github.com/paraglider-project/paraglider/internal/cli/glide/namespace/set"
@@ -36,6 +38,10 @@ func NewCommand() *cobra.Command {
 	cmd.AddCommand(setCmd)
 	listCmd, _ := list.NewCommand()
 	cmd.AddCommand(listCmd)
+	createCmd, _ := create.NewCommand()
+	cmd.AddCommand(createCmd)
+	deleteCmd, _ := delete.NewCommand()
+	cmd.AddCommand(deleteCmd)
 
 	return cmd
 }
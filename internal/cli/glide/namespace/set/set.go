@@ -51,7 +51,7 @@ func (e *executor) SetOutput(w io.Writer) {
 
 func (e *executor) Validate(cmd *cobra.Command, args []string) error {
 	// Get all namespaces from the orchestrator and confirm that the given string is one of them
-	c := &client.Client{ControllerAddress: e.cliSettings.ServerAddr}
+	c := &client.Client{ControllerAddress: e.cliSettings.ServerAddr, Credentials: e.cliSettings.Credentials}
 	namespaces, err := c.ListNamespaces()
 
 	if err != nil {
@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package get
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	common "github.com/paraglider-project/paraglider/internal/cli/common"
+	"github.com/paraglider-project/paraglider/internal/cli/glide/config"
+	"github.com/paraglider-project/paraglider/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+func NewCommand() (*cobra.Command, *executor) {
+	executor := &executor{writer: os.Stdout, cliSettings: config.ActiveConfig.Settings}
+	cmd := &cobra.Command{
+		Use:     "get <operation_id>",
+		Short:   "Get the status of an operation",
+		Args:    cobra.ExactArgs(1),
+		PreRunE: executor.Validate,
+		RunE:    executor.Execute,
+	}
+	return cmd, executor
+}
+
+type executor struct {
+	common.CommandExecutor
+	writer      io.Writer
+	cliSettings config.CliSettings
+}
+
+func (e *executor) SetOutput(w io.Writer) {
+	e.writer = w
+}
+
+func (e *executor) Validate(cmd *cobra.Command, args []string) error {
+	return nil
+}
+
+func (e *executor) Execute(cmd *cobra.Command, args []string) error {
+	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr, Credentials: e.cliSettings.Credentials}
+	operation, err := c.GetOperation(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.writer, "id: %s\ntype: %s\nstatus: %s\n", operation.Id, operation.Type, operation.Status)
+	if operation.Progress != "" {
+		fmt.Fprintf(e.writer, "progress: %s\n", operation.Progress)
+	}
+	if operation.Error != "" {
+		fmt.Fprintf(e.writer, "error: %s\n", operation.Error)
+	}
+	if len(operation.Result) > 0 {
+		fmt.Fprintf(e.writer, "result: %s\n", operation.Result)
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operation
+
+import (
+	"github.com/paraglider-project/paraglider/internal/cli/glide/operation/cancel"
+	"github.com/paraglider-project/paraglider/internal/cli/glide/operation/get"
+	"github.com/paraglider-project/paraglider/internal/cli/glide/operation/list"
+
+	"github.com/spf13/cobra"
+)
+
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "operation",
+		Short: "Perform operations on long-running controller operations",
+	}
+
+	getCmd, _ := get.NewCommand()
+	cmd.AddCommand(getCmd)
+	listCmd, _ := list.NewCommand()
+	cmd.AddCommand(listCmd)
+	cancelCmd, _ := cancel.NewCommand()
+	cmd.AddCommand(cancelCmd)
+
+	return cmd
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package attach
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -25,11 +26,12 @@ import (
 	"github.com/paraglider-project/paraglider/internal/cli/glide/config"
 	"github.com/paraglider-project/paraglider/pkg/client"
 	"github.com/paraglider-project/paraglider/pkg/orchestrator"
+	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
 	"github.com/spf13/cobra"
 )
 
 func NewCommand() (*cobra.Command, *executor) {
-	executor := &executor{writer: os.Stdout, cliSettings: config.ActiveConfig.Settings}
+	executor := &executor{writer: os.Stdout, cliSettings: config.ActiveConfig.Settings, wait: true}
 	cmd := &cobra.Command{
 		Use:     "attach <cloud> <resource_id>",
 		Short:   "Attach a resource to active namespace",
@@ -37,6 +39,7 @@ func NewCommand() (*cobra.Command, *executor) {
 		PreRunE: executor.Validate,
 		RunE:    executor.Execute,
 	}
+	common.AddWaitFlag(cmd)
 	return cmd, executor
 }
 
@@ -44,6 +47,7 @@ type executor struct {
 	common.CommandExecutor
 	writer      io.Writer
 	cliSettings config.CliSettings
+	wait        bool
 }
 
 func (e *executor) SetOutput(w io.Writer) {
@@ -51,21 +55,34 @@ func (e *executor) SetOutput(w io.Writer) {
 }
 
 func (e *executor) Validate(cmd *cobra.Command, args []string) error {
-	return nil
+	var err error
+	e.wait, err = common.GetWaitFlag(cmd)
+	return err
 }
 
 func (e *executor) Execute(cmd *cobra.Command, args []string) error {
 	fmt.Fprintf(e.writer, "Attaching resource to %s namespace\n", e.cliSettings.ActiveNamespace)
-	paragliderClient := client.Client{ControllerAddress: e.cliSettings.ServerAddr}
+	paragliderClient := client.Client{ControllerAddress: e.cliSettings.ServerAddr, Credentials: e.cliSettings.Credentials}
 
 	resource := &orchestrator.ResourceID{Id: args[1]}
-	resourceInfo, err := paragliderClient.AttachResource(e.cliSettings.ActiveNamespace, args[0], resource)
+	operation, err := paragliderClient.StartAttachResource(e.cliSettings.ActiveNamespace, args[0], resource)
+	if err == nil {
+		operation, err = common.WaitForOperation(cmd, e.writer, &paragliderClient, operation, e.wait)
+	}
 	if err != nil {
 		fmt.Fprintf(e.writer, "Failed to attach resource: %v\n", err)
 		return err
 	}
+	if operation == nil {
+		return nil
+	}
+
+	resourceInfo := &paragliderpb.AttachResourceResponse{}
+	if err := json.Unmarshal(operation.Result, resourceInfo); err != nil {
+		return err
+	}
 
-	fmt.Fprintf(e.writer, "Resource Attached.\ntag: %s\nuri: %s\nip: %s\n", resourceInfo["name"], resourceInfo["uri"], resourceInfo["ip"])
+	fmt.Fprintf(e.writer, "Resource Attached.\ntag: %s\nuri: %s\nip: %s\n", resourceInfo.Name, resourceInfo.Uri, resourceInfo.Ip)
 
 	return nil
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package create
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -29,7 +30,7 @@ import (
 )
 
 func NewCommand() (*cobra.Command, *executor) {
-	executor := &executor{writer: os.Stdout, cliSettings: config.ActiveConfig.Settings}
+	executor := &executor{writer: os.Stdout, cliSettings: config.ActiveConfig.Settings, wait: true}
 	cmd := &cobra.Command{
 		Use:     "create <cloud> <resource_name> <resource_description_file>",
 		Short:   "Create a resource in the active namespace",
@@ -38,6 +39,7 @@ func NewCommand() (*cobra.Command, *executor) {
 		RunE:    executor.Execute,
 	}
 	cmd.Flags().String("uri", "", "Resource URI if necessary for creation")
+	common.AddWaitFlag(cmd)
 	return cmd, executor
 }
 
@@ -47,6 +49,7 @@ type executor struct {
 	cliSettings config.CliSettings
 	description []byte
 	uri         string
+	wait        bool
 }
 
 func (e *executor) SetOutput(w io.Writer) {
@@ -69,6 +72,11 @@ func (e *executor) Validate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	e.wait, err = common.GetWaitFlag(cmd)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -76,15 +84,25 @@ func (e *executor) Execute(cmd *cobra.Command, args []string) error {
 	resource := &paragliderpb.ResourceDescriptionString{Description: string(e.description)}
 
 	fmt.Fprintf(e.writer, "Creating resource: %v\n", args[1])
-	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr}
-	resourceInfo, err := c.CreateResource(e.cliSettings.ActiveNamespace, args[0], args[1], resource)
-
+	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr, Credentials: e.cliSettings.Credentials}
+	operation, err := c.StartCreateResource(e.cliSettings.ActiveNamespace, args[0], args[1], resource)
+	if err == nil {
+		operation, err = common.WaitForOperation(cmd, e.writer, &c, operation, e.wait)
+	}
 	if err != nil {
 		fmt.Fprintf(e.writer, "Failed to create resource: %v\n", err)
 		return err
 	}
+	if operation == nil {
+		return nil
+	}
+
+	resourceInfo := &paragliderpb.CreateResourceResponse{}
+	if err := json.Unmarshal(operation.Result, resourceInfo); err != nil {
+		return err
+	}
 
-	fmt.Fprintf(e.writer, "Resource Created.\ntag: %s\nuri: %s\nip: %s\n", resourceInfo["name"], resourceInfo["uri"], resourceInfo["ip"])
+	fmt.Fprintf(e.writer, "Resource Created.\ntag: %s\nuri: %s\nip: %s\n", resourceInfo.Name, resourceInfo.Uri, resourceInfo.Ip)
 
 	return nil
 }
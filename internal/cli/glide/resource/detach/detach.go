@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package detach
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	common "github.com/paraglider-project/paraglider/internal/cli/common"
+	"github.com/paraglider-project/paraglider/internal/cli/glide/config"
+	"github.com/paraglider-project/paraglider/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+func NewCommand() (*cobra.Command, *executor) {
+	executor := &executor{writer: os.Stdout, cliSettings: config.ActiveConfig.Settings, wait: true}
+	cmd := &cobra.Command{
+		Use:     "detach <cloud> <resource_name>",
+		Short:   "Detach a resource from the active namespace without deleting it",
+		Args:    cobra.ExactArgs(2),
+		PreRunE: executor.Validate,
+		RunE:    executor.Execute,
+	}
+	common.AddWaitFlag(cmd)
+	return cmd, executor
+}
+
+type executor struct {
+	common.CommandExecutor
+	writer      io.Writer
+	cliSettings config.CliSettings
+	wait        bool
+}
+
+func (e *executor) SetOutput(w io.Writer) {
+	e.writer = w
+}
+
+func (e *executor) Validate(cmd *cobra.Command, args []string) error {
+	var err error
+	e.wait, err = common.GetWaitFlag(cmd)
+	return err
+}
+
+func (e *executor) Execute(cmd *cobra.Command, args []string) error {
+	paragliderClient := client.Client{ControllerAddress: e.cliSettings.ServerAddr, Credentials: e.cliSettings.Credentials}
+
+	operation, err := paragliderClient.StartDetachResource(e.cliSettings.ActiveNamespace, args[0], args[1])
+	if err == nil {
+		operation, err = common.WaitForOperation(cmd, e.writer, &paragliderClient, operation, e.wait)
+	}
+	if err != nil {
+		fmt.Fprintf(e.writer, "Failed to detach resource: %v\n", err)
+		return err
+	}
+	if operation == nil {
+		return nil
+	}
+
+	fmt.Fprintf(e.writer, "Resource %s detached.\n", args[1])
+
+	return nil
+}
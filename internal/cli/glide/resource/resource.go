@@ -19,6 +19,8 @@ package resource
 import (
 	"github.com/paraglider-project/paraglider/internal/cli/glide/resource/attach"
 	"github.com/paraglider-project/paraglider/internal/cli/glide/resource/create"
+	"github.com/paraglider-project/paraglider/internal/cli/glide/resource/delete"
+	"github.com/paraglider-project/paraglider/internal/cli/glide/resource/detach"
 	"github.com/spf13/cobra"
 )
 
@@ -34,5 +36,11 @@ func NewCommand() *cobra.Command {
 	attachCmd, _ := attach.NewCommand()
 	cmd.AddCommand(attachCmd)
 
+	deleteCmd, _ := delete.NewCommand()
+	cmd.AddCommand(deleteCmd)
+
+	detachCmd, _ := detach.NewCommand()
+	cmd.AddCommand(detachCmd)
+
 	return cmd
 }
@@ -24,8 +24,14 @@ import (
 	"syscall"
 
 	common "github.com/paraglider-project/paraglider/internal/cli/common"
+	"github.com/paraglider-project/paraglider/internal/cli/glide/apply"
+	"github.com/paraglider-project/paraglider/internal/cli/glide/audit"
 	"github.com/paraglider-project/paraglider/internal/cli/glide/config"
+	"github.com/paraglider-project/paraglider/internal/cli/glide/diff"
+	"github.com/paraglider-project/paraglider/internal/cli/glide/drift"
 	"github.com/paraglider-project/paraglider/internal/cli/glide/namespace"
+	"github.com/paraglider-project/paraglider/internal/cli/glide/operation"
+	"github.com/paraglider-project/paraglider/internal/cli/glide/plugin"
 	"github.com/paraglider-project/paraglider/internal/cli/glide/resource"
 	"github.com/paraglider-project/paraglider/internal/cli/glide/rule"
 	"github.com/paraglider-project/paraglider/internal/cli/glide/server"
@@ -53,6 +59,16 @@ func init() {
 	rootCmd.AddCommand(common.NewVersionCommand())
 	rootCmd.AddCommand(server.NewCommand())
 	rootCmd.AddCommand(namespace.NewCommand())
+	rootCmd.AddCommand(operation.NewCommand())
+	rootCmd.AddCommand(plugin.NewCommand())
+	applyCmd, _ := apply.NewCommand()
+	rootCmd.AddCommand(applyCmd)
+	diffCmd, _ := diff.NewCommand()
+	rootCmd.AddCommand(diffCmd)
+	driftCmd, _ := drift.NewCommand()
+	rootCmd.AddCommand(driftCmd)
+	auditCmd, _ := audit.NewCommand()
+	rootCmd.AddCommand(auditCmd)
 }
 
 func Execute() {
@@ -22,18 +22,20 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	common "github.com/paraglider-project/paraglider/internal/cli/common"
 	"github.com/paraglider-project/paraglider/internal/cli/glide/config"
 	"github.com/paraglider-project/paraglider/pkg/client"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator"
 	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
 	"github.com/spf13/cobra"
 )
 
 func NewCommand() (*cobra.Command, *executor) {
-	executor := &executor{writer: os.Stdout, cliSettings: config.ActiveConfig.Settings}
+	executor := &executor{writer: os.Stdout, cliSettings: config.ActiveConfig.Settings, wait: true}
 	cmd := &cobra.Command{
-		Use:     "add [<cloud> <resource name> | <tag>] [--rulefile <path to rule json file>] [--ping <tag>] [--ssh <tag>]",
+		Use:     "add [<cloud> <resource name> | <tag>] [--rulefile <path to rule json file>] [--ping <tag>] [--ssh <tag>] [--ttl <duration>]",
 		Short:   "Add a rule to a resource's permit list or to the permit list of every resource within a tag",
 		Args:    cobra.RangeArgs(1, 2),
 		PreRunE: executor.Validate,
@@ -42,6 +44,8 @@ func NewCommand() (*cobra.Command, *executor) {
 	cmd.Flags().String("rulefile", "", "The file containing the rules to add")
 	cmd.Flags().String("ping", "", "IP/tag to allow ping to")
 	cmd.Flags().String("ssh", "", "IP/tag to allow SSH to")
+	cmd.Flags().Duration("ttl", 0, "How long the rules last before they are deleted (e.g., 2h), or 0 to keep them until deleted")
+	common.AddWaitFlag(cmd)
 	return cmd, executor
 }
 
@@ -52,6 +56,8 @@ type executor struct {
 	ruleFile    string
 	pingTag     string
 	sshTag      string
+	ttl         time.Duration
+	wait        bool
 }
 
 func (e *executor) SetOutput(w io.Writer) {
@@ -72,6 +78,17 @@ func (e *executor) Validate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	e.ttl, err = cmd.Flags().GetDuration("ttl")
+	if err != nil {
+		return err
+	}
+	if e.ttl < 0 {
+		return fmt.Errorf("TTL must not be negative")
+	}
+	e.wait, err = common.GetWaitFlag(cmd)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -106,17 +123,28 @@ func (e *executor) Execute(cmd *cobra.Command, args []string) error {
 		rules = append(rules, &paragliderpb.PermitListRule{Name: "ssh-in-" + ruleName, Tags: []string{e.sshTag}, Protocol: 6, Direction: 0, DstPort: 22, SrcPort: -1})
 		rules = append(rules, &paragliderpb.PermitListRule{Name: "ssh-out-" + ruleName, Tags: []string{e.sshTag}, Protocol: 6, Direction: 1, DstPort: -1, SrcPort: 22})
 	}
+	if e.ttl > 0 {
+		// The controller deletes the rules once their TTL runs out
+		for _, rule := range rules {
+			rule.TtlSeconds = int64(e.ttl.Seconds())
+		}
+	}
 
-	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr}
+	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr, Credentials: e.cliSettings.Credentials}
 
+	var operation *orchestrator.Operation
 	var err error
 	if len(args) == 1 {
-		err = c.AddPermitListRulesTag(args[0], rules)
+		operation, err = c.StartAddPermitListRulesTag(args[0], rules)
 	} else {
 		fmt.Fprintf(e.writer, "Adding permit list rule\n")
-		err = c.AddPermitListRules(e.cliSettings.ActiveNamespace, args[0], args[1], rules)
+		operation, err = c.StartAddPermitListRules(e.cliSettings.ActiveNamespace, args[0], args[1], rules)
+	}
+	if err != nil {
+		return err
 	}
 
+	_, err = common.WaitForOperation(cmd, e.writer, &c, operation, e.wait)
 	return err
 }
 
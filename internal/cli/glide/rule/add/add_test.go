@@ -19,7 +19,11 @@ limitations under the License.
 package add
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/paraglider-project/paraglider/internal/cli/glide/config"
 	fake "github.com/paraglider-project/paraglider/pkg/fake/orchestrator/rest"
@@ -58,6 +62,20 @@ func TestRuleAddValidate(t *testing.T) {
 	assert.Equal(t, executor.ruleFile, ruleFile)
 	assert.Equal(t, executor.pingTag, tag)
 	assert.Equal(t, executor.sshTag, tag)
+
+	// TTL
+	err = cmd.Flags().Set("ttl", "2h")
+	require.Nil(t, err)
+	err = executor.Validate(cmd, args)
+
+	assert.Nil(t, err)
+	assert.Equal(t, executor.ttl, 2*time.Hour)
+
+	err = cmd.Flags().Set("ttl", "-1h")
+	require.Nil(t, err)
+	err = executor.Validate(cmd, args)
+
+	assert.NotNil(t, err)
 }
 
 func TestRuleAddExecute(t *testing.T) {
@@ -83,4 +101,17 @@ func TestRuleAddExecute(t *testing.T) {
 	err = executor.Execute(cmd, args)
 
 	assert.Nil(t, err)
+
+	// Warnings are printed
+	ruleFile := filepath.Join(t.TempDir(), "rules.json")
+	err = os.WriteFile(ruleFile, []byte(`[{"name": "rule", "tags": ["tag"], "targets": ["1.2.3.4"]}]`), 0644)
+	require.Nil(t, err)
+	var output bytes.Buffer
+	executor.writer = &output
+	executor.ruleFile = ruleFile
+	args = []string{fake.CloudName, "uri"}
+	err = executor.Execute(cmd, args)
+
+	assert.Nil(t, err)
+	assert.Contains(t, output.String(), "Warning: targets for rule rule ignored ("+fake.Uri+")")
 }
@@ -27,7 +27,7 @@ import (
 )
 
 func NewCommand() (*cobra.Command, *executor) {
-	executor := &executor{writer: os.Stdout, cliSettings: config.ActiveConfig.Settings}
+	executor := &executor{writer: os.Stdout, cliSettings: config.ActiveConfig.Settings, wait: true}
 	cmd := &cobra.Command{
 		Use:     "delete <cloud> <resource name> --rules <rule names>",
 		Short:   "Delete a rule from a resource permit list",
@@ -36,6 +36,7 @@ func NewCommand() (*cobra.Command, *executor) {
 		RunE:    executor.Execute,
 	}
 	cmd.Flags().StringSlice("rules", []string{}, "The names of the rules to delete")
+	common.AddWaitFlag(cmd)
 	return cmd, executor
 }
 
@@ -44,6 +45,7 @@ type executor struct {
 	writer      io.Writer
 	cliSettings config.CliSettings
 	ruleNames   []string
+	wait        bool
 }
 
 func (e *executor) SetOutput(w io.Writer) {
@@ -56,12 +58,20 @@ func (e *executor) Validate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	e.wait, err = common.GetWaitFlag(cmd)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
 func (e *executor) Execute(cmd *cobra.Command, args []string) error {
 	// Send the rules to the server
-	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr}
-	err := c.DeletePermitListRules(e.cliSettings.ActiveNamespace, args[0], args[1], e.ruleNames)
+	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr, Credentials: e.cliSettings.Credentials}
+	operation, err := c.StartDeletePermitListRules(e.cliSettings.ActiveNamespace, args[0], args[1], e.ruleNames)
+	if err != nil {
+		return err
+	}
+	_, err = common.WaitForOperation(cmd, e.writer, &c, operation, e.wait)
 	return err
 }
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	common "github.com/paraglider-project/paraglider/internal/cli/common"
 	"github.com/paraglider-project/paraglider/internal/cli/glide/config"
@@ -55,7 +56,7 @@ func (e *executor) Validate(cmd *cobra.Command, args []string) error {
 
 func (e *executor) Execute(cmd *cobra.Command, args []string) error {
 	// Get the rules from the server
-	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr}
+	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr, Credentials: e.cliSettings.Credentials}
 	permitList, err := c.GetPermitList(e.cliSettings.ActiveNamespace, args[0], args[1])
 	if err != nil {
 		return err
@@ -63,9 +64,22 @@ func (e *executor) Execute(cmd *cobra.Command, args []string) error {
 
 	// Print the rules
 	fmt.Fprintf(e.writer, "Permit list for %s:\n", args[1])
+	now := time.Now()
 	for i, rule := range permitList {
-		fmt.Fprintf(e.writer, "%d. %v\n", i, rule)
+		fmt.Fprintf(e.writer, "%d. %v%s\n", i, rule, formatLifetime(rule.ExpiresAt, now))
 	}
 
 	return nil
 }
+
+// Describe how long a rule expiring at expiresAt has left (nothing if it doesn't expire)
+func formatLifetime(expiresAt int64, now time.Time) string {
+	if expiresAt == 0 {
+		return ""
+	}
+	remaining := time.Unix(expiresAt, 0).Sub(now).Truncate(time.Second)
+	if remaining <= 0 {
+		return " (expired, pending deletion)"
+	}
+	return fmt.Sprintf(" (expires in %s)", remaining)
+}
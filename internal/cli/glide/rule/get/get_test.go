@@ -21,6 +21,7 @@ package get
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"github.com/paraglider-project/paraglider/internal/cli/glide/config"
 	fake "github.com/paraglider-project/paraglider/pkg/fake/orchestrator/rest"
@@ -45,3 +46,11 @@ func TestRuleGetExecute(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Contains(t, output.String(), fake.GetFakePermitListRules()[0].Name)
 }
+
+func TestFormatLifetime(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	assert.Equal(t, "", formatLifetime(0, now))
+	assert.Equal(t, " (expires in 2h0m0s)", formatLifetime(8200, now))
+	assert.Equal(t, " (expired, pending deletion)", formatLifetime(1000, now))
+}
@@ -55,5 +55,15 @@ func (e *executor) Validate(cmd *cobra.Command, args []string) error {
 
 func (e *executor) Execute(cmd *cobra.Command, args []string) error {
 	fmt.Fprintln(e.writer, "Server address: ", e.cliSettings.ServerAddr)
+	credentials := e.cliSettings.Credentials
+	if credentials.Token != "" {
+		fmt.Fprintln(e.writer, "Token: ", "(set)")
+	}
+	if credentials.CertFile != "" {
+		fmt.Fprintln(e.writer, "Client certificate: ", credentials.CertFile)
+	}
+	if credentials.CAFile != "" {
+		fmt.Fprintln(e.writer, "CA: ", credentials.CAFile)
+	}
 	return nil
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package set
 
 import (
+	"fmt"
 	"io"
 	"os"
 
@@ -25,15 +26,26 @@ import (
 	"github.com/spf13/cobra"
 )
 
+const (
+	tokenFlag = "token"
+	certFlag  = "cert"
+	keyFlag   = "key"
+	caFlag    = "ca"
+)
+
 func NewCommand() (*cobra.Command, *executor) {
 	executor := &executor{writer: os.Stdout, cliSettings: &config.ActiveConfig.Settings}
 	cmd := &cobra.Command{
-		Use:     "set <server address>",
+		Use:     "set [<server address>] [--token <token>] [--cert <file> --key <file>] [--ca <file>]",
 		Short:   "Set the server config",
-		Args:    cobra.ExactArgs(1),
+		Args:    cobra.MaximumNArgs(1),
 		PreRunE: executor.Validate,
 		RunE:    executor.Execute,
 	}
+	cmd.Flags().String(tokenFlag, "", "API token or OIDC ID token to authenticate with (empty to remove)")
+	cmd.Flags().String(certFlag, "", "Client certificate file to authenticate with (empty to remove)")
+	cmd.Flags().String(keyFlag, "", "Key file of the client certificate")
+	cmd.Flags().String(caFlag, "", "File with the CAs which sign the server certificate (defaults to the system CAs)")
 	return cmd, executor
 }
 
@@ -49,11 +61,30 @@ func (e *executor) SetOutput(w io.Writer) {
 
 func (e *executor) Validate(cmd *cobra.Command, args []string) error {
 	// TODO @smcclure20: Validate it is a valid address to some extent
+	if len(args) == 0 && cmd.Flags().NFlag() == 0 {
+		return fmt.Errorf("a server address or credentials must be provided")
+	}
+	if cmd.Flags().Changed(certFlag) != cmd.Flags().Changed(keyFlag) {
+		return fmt.Errorf("--cert and --key must be provided together")
+	}
 	return nil
 }
 
 func (e *executor) Execute(cmd *cobra.Command, args []string) error {
-	e.cliSettings.ServerAddr = args[0]
+	if len(args) > 0 {
+		e.cliSettings.ServerAddr = args[0]
+	}
+	// Only the credentials provided are changed
+	for flag, value := range map[string]*string{
+		tokenFlag: &e.cliSettings.Credentials.Token,
+		certFlag:  &e.cliSettings.Credentials.CertFile,
+		keyFlag:   &e.cliSettings.Credentials.KeyFile,
+		caFlag:    &e.cliSettings.Credentials.CAFile,
+	} {
+		if cmd.Flags().Changed(flag) {
+			*value, _ = cmd.Flags().GetString(flag)
+		}
+	}
 	err := config.SaveActiveConfig()
 	if err != nil {
 		return err
@@ -38,3 +38,28 @@ func TestServerSetExecute(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, newAddr, executor.cliSettings.ServerAddr)
 }
+
+func TestServerSetCredentials(t *testing.T) {
+	err := config.ReadOrCreateConfig()
+	assert.Nil(t, err)
+
+	cmd, executor := NewCommand()
+	executor.cliSettings = &config.CliSettings{ServerAddr: "serverAddr"}
+	assert.Nil(t, cmd.Flags().Set(tokenFlag, "token"))
+	assert.Nil(t, cmd.Flags().Set(caFlag, "ca.pem"))
+
+	err = executor.Validate(cmd, []string{})
+	assert.Nil(t, err)
+	err = executor.Execute(cmd, []string{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "serverAddr", executor.cliSettings.ServerAddr)
+	assert.Equal(t, "token", executor.cliSettings.Credentials.Token)
+	assert.Equal(t, "ca.pem", executor.cliSettings.Credentials.CAFile)
+	assert.Empty(t, executor.cliSettings.Credentials.CertFile)
+
+	// The certificate and key go together
+	assert.Nil(t, cmd.Flags().Set(certFlag, "cert.pem"))
+	err = executor.Validate(cmd, []string{})
+	assert.NotNil(t, err)
+}
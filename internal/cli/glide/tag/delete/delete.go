@@ -61,7 +61,7 @@ func (e *executor) Validate(cmd *cobra.Command, args []string) error {
 
 func (e *executor) Execute(cmd *cobra.Command, args []string) error {
 	// Delete the tag from the server
-	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr}
+	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr, Credentials: e.cliSettings.Credentials}
 	if e.member == "" {
 		err := c.DeleteTag(args[0])
 		return err
@@ -62,7 +62,7 @@ func (e *executor) Validate(cmd *cobra.Command, args []string) error {
 
 func (e *executor) Execute(cmd *cobra.Command, args []string) error {
 	// Get the tag from the server
-	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr}
+	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr, Credentials: e.cliSettings.Credentials}
 
 	if e.resolveFlag {
 		tagMappings, err := c.ResolveTag(args[0])
@@ -46,7 +46,7 @@ type executor struct {
 
 func (e *executor) Execute(cmd *cobra.Command, args []string) error {
 
-	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr}
+	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr, Credentials: e.cliSettings.Credentials}
 	tagMappings, err := c.ListTags()
 	if err != nil {
 		return err
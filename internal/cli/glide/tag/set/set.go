@@ -95,7 +95,7 @@ func (e *executor) Execute(cmd *cobra.Command, args []string) error {
 
 	tagMapping := &tagservicepb.TagMapping{Name: args[0], ChildTags: e.children, Uri: uri, Ip: ip}
 
-	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr}
+	c := client.Client{ControllerAddress: e.cliSettings.ServerAddr, Credentials: e.cliSettings.Credentials}
 	err := c.SetTag(args[0], tagMapping)
 	return err
 }
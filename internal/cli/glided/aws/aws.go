@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	common "github.com/paraglider-project/paraglider/internal/cli/common"
+	aws "github.com/paraglider-project/paraglider/pkg/aws"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+)
+
+func NewCommand() *cobra.Command {
+	executor := &executor{}
+	cmd := &cobra.Command{
+		Use:     "aws <port> <orchestrator address>",
+		Aliases: []string{"aws"},
+		Short:   "Starts the AWS plugin server with given config file",
+		Args:    cobra.ExactArgs(2),
+		PreRunE: executor.Validate,
+		RunE:    executor.Execute,
+	}
+	common.AddTLSFlags(cmd)
+	common.AddAdvertiseAddressFlag(cmd)
+	common.AddMetricsFlag(cmd)
+	common.AddLoggingFlags(cmd)
+	common.AddTracingFlags(cmd)
+	return cmd
+}
+
+type executor struct {
+	port             int
+	tlsConfig        config.TLS
+	advertiseAddress string
+}
+
+func (e *executor) Validate(cmd *cobra.Command, args []string) error {
+	var err error
+	e.port, err = strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid port")
+	}
+	e.tlsConfig, err = common.GetTLSFlags(cmd)
+	if err != nil {
+		return err
+	}
+	e.advertiseAddress, err = common.GetAdvertiseAddressFlag(cmd)
+	return err
+}
+
+func (e *executor) Execute(cmd *cobra.Command, args []string) error {
+	if err := common.SetupLoggingFromFlags(cmd); err != nil {
+		return err
+	}
+	if err := common.ServeMetricsFromFlag(cmd); err != nil {
+		return err
+	}
+	shutdownTracing, err := common.SetupTracingFromFlags(cmd, "paraglider-aws-plugin")
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing(context.Background())
+	aws.SetupWithAdvertiseAddress(e.port, args[1], e.tlsConfig, e.advertiseAddress)
+	return nil
+}
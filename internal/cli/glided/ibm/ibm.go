@@ -17,17 +17,20 @@ limitations under the License.
 package ibm
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
 	"github.com/spf13/cobra"
 
+	common "github.com/paraglider-project/paraglider/internal/cli/common"
 	ibm "github.com/paraglider-project/paraglider/pkg/ibm"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
 )
 
 func NewCommand() *cobra.Command {
 	executor := &executor{}
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:     "ibm <port> <central controller address>",
 		Aliases: []string{"ibm"},
 		Short:   "Starts the IBM plugin server on given port",
@@ -35,10 +38,18 @@ func NewCommand() *cobra.Command {
 		PreRunE: executor.Validate,
 		RunE:    executor.Execute,
 	}
+	common.AddTLSFlags(cmd)
+	common.AddAdvertiseAddressFlag(cmd)
+	common.AddMetricsFlag(cmd)
+	common.AddLoggingFlags(cmd)
+	common.AddTracingFlags(cmd)
+	return cmd
 }
 
 type executor struct {
-	port int
+	port             int
+	tlsConfig        config.TLS
+	advertiseAddress string
 }
 
 func (e *executor) Validate(cmd *cobra.Command, args []string) error {
@@ -47,10 +58,26 @@ func (e *executor) Validate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("invalid port")
 	}
-	return nil
+	e.tlsConfig, err = common.GetTLSFlags(cmd)
+	if err != nil {
+		return err
+	}
+	e.advertiseAddress, err = common.GetAdvertiseAddressFlag(cmd)
+	return err
 }
 
 func (e *executor) Execute(cmd *cobra.Command, args []string) error {
-	ibm.Setup(e.port, args[1])
+	if err := common.SetupLoggingFromFlags(cmd); err != nil {
+		return err
+	}
+	if err := common.ServeMetricsFromFlag(cmd); err != nil {
+		return err
+	}
+	shutdownTracing, err := common.SetupTracingFromFlags(cmd, "paraglider-ibm-plugin")
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing(context.Background())
+	ibm.SetupWithAdvertiseAddress(e.port, args[1], e.tlsConfig, e.advertiseAddress)
 	return nil
 }
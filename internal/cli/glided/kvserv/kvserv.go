@@ -17,16 +17,19 @@ limitations under the License.
 package kvserv
 
 import (
+	"context"
 	"strconv"
 
 	"github.com/spf13/cobra"
 
-	tagservice "github.com/paraglider-project/paraglider/pkg/tag_service"
+	common "github.com/paraglider-project/paraglider/internal/cli/common"
+	kvstore "github.com/paraglider-project/paraglider/pkg/kvstore"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
 )
 
 func NewCommand() *cobra.Command {
 	executor := &executor{}
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:     "kvserv <database port> <server port> <clear keys>",
 		Aliases: []string{"kvserv"},
 		Short:   "Starts the key-value store server on given ports",
@@ -34,12 +37,18 @@ func NewCommand() *cobra.Command {
 		PreRunE: executor.Validate,
 		RunE:    executor.Execute,
 	}
+	common.AddTLSFlags(cmd)
+	common.AddMetricsFlag(cmd)
+	common.AddLoggingFlags(cmd)
+	common.AddTracingFlags(cmd)
+	return cmd
 }
 
 type executor struct {
 	dbPort     int
 	serverPort int
 	clearKeys  bool
+	tlsConfig  config.TLS
 }
 
 func (e *executor) Validate(cmd *cobra.Command, args []string) error {
@@ -57,10 +66,22 @@ func (e *executor) Validate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	return nil
+	e.tlsConfig, err = common.GetTLSFlags(cmd)
+	return err
 }
 
 func (e *executor) Execute(cmd *cobra.Command, args []string) error {
-	tagservice.Setup(e.dbPort, e.serverPort, e.clearKeys)
+	if err := common.SetupLoggingFromFlags(cmd); err != nil {
+		return err
+	}
+	if err := common.ServeMetricsFromFlag(cmd); err != nil {
+		return err
+	}
+	shutdownTracing, err := common.SetupTracingFromFlags(cmd, "paraglider-kvstore")
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing(context.Background())
+	kvstore.Setup(e.dbPort, e.serverPort, e.clearKeys, e.tlsConfig)
 	return nil
 }
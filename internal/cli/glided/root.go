@@ -21,6 +21,7 @@ import (
 	"os"
 
 	common "github.com/paraglider-project/paraglider/internal/cli/common"
+	"github.com/paraglider-project/paraglider/internal/cli/glided/aws"
 	"github.com/paraglider-project/paraglider/internal/cli/glided/az"
 	"github.com/paraglider-project/paraglider/internal/cli/glided/gcp"
 	"github.com/paraglider-project/paraglider/internal/cli/glided/ibm"
@@ -38,6 +39,7 @@ var rootCmd = &cobra.Command{
 }
 
 func init() {
+	rootCmd.AddCommand(aws.NewCommand())
 	rootCmd.AddCommand(az.NewCommand())
 	rootCmd.AddCommand(gcp.NewCommand())
 	rootCmd.AddCommand(ibm.NewCommand())
@@ -17,12 +17,15 @@ limitations under the License.
 package startup
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
 
+	aws "github.com/paraglider-project/paraglider/pkg/aws"
 	az "github.com/paraglider-project/paraglider/pkg/azure"
 	gcp "github.com/paraglider-project/paraglider/pkg/gcp"
 	ibm "github.com/paraglider-project/paraglider/pkg/ibm"
@@ -31,6 +34,7 @@ import (
 	orchestrator "github.com/paraglider-project/paraglider/pkg/orchestrator"
 	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
 	tagservice "github.com/paraglider-project/paraglider/pkg/tag_service"
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
 )
 
 func NewCommand() *cobra.Command {
@@ -43,7 +47,7 @@ func NewCommand() *cobra.Command {
 		PreRunE: executor.Validate,
 		RunE:    executor.Execute,
 	}
-	cmd.Flags().Bool("clearkeys", false, "Clears all the keys in the redis database")
+	cmd.Flags().Bool("clearkeys", false, "Clears all the keys in the tag service and KV store databases")
 	return cmd
 }
 
@@ -53,8 +57,17 @@ type executor struct {
 	azPort           int
 	gcpPort          int
 	ibmPort          int
+	awsPort          int
 	orchestratorAddr string
+	storage          config.Storage
 	clearKeys        bool
+	tagTLS           config.TLS
+	kvTLS            config.TLS
+	azTLS            config.TLS
+	gcpTLS           config.TLS
+	ibmTLS           config.TLS
+	awsTLS           config.TLS
+	logging          config.Logging
 }
 
 func (e *executor) Validate(cmd *cobra.Command, args []string) error {
@@ -73,6 +86,9 @@ func (e *executor) Validate(cmd *cobra.Command, args []string) error {
 	}
 
 	e.orchestratorAddr = cfg.Server.Host + ":" + cfg.Server.RpcPort
+	e.logging = cfg.Logging
+	e.tagTLS = cfg.TagService.TLS
+	e.kvTLS = cfg.KVStore.TLS
 
 	e.tagPort, err = strconv.Atoi(cfg.TagService.Port)
 	if err != nil {
@@ -86,22 +102,53 @@ func (e *executor) Validate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	e.storage = cfg.Storage
+	switch e.storage.Type {
+	case "", config.RedisStorageType:
+	case config.BoltStorageType:
+		if e.storage.Path == "" {
+			return fmt.Errorf("storage path is required for the %s storage", config.BoltStorageType)
+		}
+	default:
+		return fmt.Errorf("invalid storage type: %s", e.storage.Type)
+	}
+
 	for _, cloud := range cfg.CloudPlugins {
 		if cloud.Name == "gcp" {
 			e.gcpPort, err = strconv.Atoi(cloud.Port)
 			if err != nil {
 				return err
 			}
+			e.gcpTLS = cloud.TLS
 		} else if cloud.Name == "azure" {
 			e.azPort, err = strconv.Atoi(cloud.Port)
 			if err != nil {
 				return err
 			}
+			e.azTLS = cloud.TLS
 		} else if cloud.Name == "ibm" {
 			e.ibmPort, err = strconv.Atoi(cloud.Port)
 			if err != nil {
 				return err
 			}
+			e.ibmTLS = cloud.TLS
+		} else if cloud.Name == "aws" {
+			e.awsPort, err = strconv.Atoi(cloud.Port)
+			if err != nil {
+				return err
+			}
+			e.awsTLS = cloud.TLS
+		}
+	}
+
+	// A service with TLS can't talk to one without it
+	tlsConfigs := []config.TLS{cfg.Server.TLS, cfg.TagService.TLS, cfg.KVStore.TLS}
+	for _, cloud := range cfg.CloudPlugins {
+		tlsConfigs = append(tlsConfigs, cloud.TLS)
+	}
+	for _, tlsConfig := range tlsConfigs {
+		if tlsConfig.Enabled() != cfg.Server.TLS.Enabled() {
+			return fmt.Errorf("TLS must be configured for either all services or none")
 		}
 	}
 
@@ -114,26 +161,57 @@ func (e *executor) Validate(cmd *cobra.Command, args []string) error {
 }
 
 func (e *executor) Execute(cmd *cobra.Command, args []string) error {
-	go func() {
-		tagservice.Setup(6379, e.tagPort, e.clearKeys)
-	}()
+	// Set up logging before starting the services so their first log lines are written as configured as well
+	if err := utils.SetupLogging(e.logging); err != nil {
+		return err
+	}
 
-	go func() {
-		kvservice.Setup(6379, e.kvPort, e.clearKeys)
-	}()
+	if e.storage.Type == config.BoltStorageType {
+		// Both services run in this process, so no external database is needed
+		err := tagservice.SetupWithBoltStore(filepath.Join(e.storage.Path, "tags.db"), e.tagPort, e.clearKeys, e.tagTLS)
+		if err != nil {
+			return err
+		}
+		err = kvservice.SetupWithBoltStore(filepath.Join(e.storage.Path, "kvstore.db"), e.kvPort, e.clearKeys, e.kvTLS)
+		if err != nil {
+			return err
+		}
+	} else {
+		go func() {
+			tagservice.Setup(6379, e.tagPort, e.clearKeys, e.tagTLS)
+		}()
+
+		go func() {
+			kvservice.Setup(6379, e.kvPort, e.clearKeys, e.kvTLS)
+		}()
+	}
+
+	// Plugins are only started if they are configured since they register with the controller
+	if e.gcpPort != 0 {
+		go func() {
+			gcp.Setup(e.gcpPort, e.orchestratorAddr, e.gcpTLS)
+		}()
+	}
 
-	go func() {
-		gcp.Setup(e.gcpPort, e.orchestratorAddr)
-	}()
+	if e.azPort != 0 {
+		go func() {
+			az.Setup(e.azPort, e.orchestratorAddr, e.azTLS)
+		}()
+	}
 
-	go func() {
-		az.Setup(e.azPort, e.orchestratorAddr)
-	}()
+	if e.ibmPort != 0 {
+		go func() {
+			ibm.Setup(e.ibmPort, e.orchestratorAddr, e.ibmTLS)
+		}()
+	}
 
-	go func() {
-		ibm.Setup(e.ibmPort, e.orchestratorAddr)
-	}()
+	if e.awsPort != 0 {
+		go func() {
+			aws.Setup(e.awsPort, e.orchestratorAddr, e.awsTLS)
+		}()
+	}
 
+	// The controller sets up tracing for the process, so the spans of the other services are exported along with its own
 	orchestrator.SetupWithFile(args[0], false)
 
 	return nil
@@ -22,12 +22,14 @@ import (
 
 	"github.com/spf13/cobra"
 
+	common "github.com/paraglider-project/paraglider/internal/cli/common"
 	ibm "github.com/paraglider-project/paraglider/pkg/ibm"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
 )
 
 func NewCommand() *cobra.Command {
 	executor := &executor{}
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:     "ibm <port> <central controller address>",
 		Aliases: []string{"ibm"},
 		Short:   "Starts the IBM plugin server on given port",
@@ -35,10 +37,13 @@ func NewCommand() *cobra.Command {
 		PreRunE: executor.Validate,
 		RunE:    executor.Execute,
 	}
+	common.AddTLSFlags(cmd)
+	return cmd
 }
 
 type executor struct {
-	port int
+	port      int
+	tlsConfig config.TLS
 }
 
 func (e *executor) Validate(cmd *cobra.Command, args []string) error {
@@ -47,10 +52,11 @@ func (e *executor) Validate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("invalid port")
 	}
-	return nil
+	e.tlsConfig, err = common.GetTLSFlags(cmd)
+	return err
 }
 
 func (e *executor) Execute(cmd *cobra.Command, args []string) error {
-	ibm.Setup(e.port, args[1])
+	ibm.Setup(e.port, args[1], e.tlsConfig)
 	return nil
 }
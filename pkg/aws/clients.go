@@ -17,10 +17,39 @@ limitations under the License.
 package aws
 
 import (
+	"context"
+	"net/http"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
 )
 
+// Loads the default config for a region, recording the requests made with it as spans of the trace of the plugin call
+func loadConfig(ctx context.Context, region string) (aws.Config, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return cfg, err
+	}
+	// The SDK configures its own client (e.g., with a custom CA bundle), so it's wrapped rather than replaced
+	cfg.HTTPClient = &http.Client{
+		Transport:     utils.TracingTransport(httpClientTransport{client: cfg.HTTPClient}),
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	return cfg, nil
+}
+
+// Sends requests with an AWS HTTP client so they can go through other round trippers
+type httpClientTransport struct {
+	client aws.HTTPClient
+}
+
+func (t httpClientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.client.Do(req)
+}
+
 type awsClients struct {
 	ec2Client *ec2.Client
 }
@@ -18,14 +18,18 @@ package aws
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 )
 
 const (
-	paragliderPrefix         = "para"    // Prefix for all resources
-	defaultSecurityGroupName = "default" // Default security group name as set by AWS
+	paragliderPrefix         = "para"      // Prefix for all resources
+	defaultSecurityGroupName = "default"   // Default security group name as set by AWS
+	defaultRegion            = "us-east-1" // Region used for calls that aren't tied to a specific region
+	vpnRegion                = "us-east-1" // Region of the VPN gateway
 )
 
 // getDescribeFilter returns a filter for a resource to use for getting (e.g., Describe...).
@@ -51,12 +55,7 @@ func getTagSpecificationsForCreateResource(namespace string, name string, resour
 
 // getNameTag returns the value of the tag with key "Name" for a resource.
 func getNameTag(tags []types.Tag) string {
-	for _, tag := range tags {
-		if *tag.Key == "Name" {
-			return *tag.Value
-		}
-	}
-	return ""
+	return getTagValue(tags, "Name")
 }
 
 // getRegionFromAvailabilityZone returns the region from an availability zone.
@@ -85,7 +84,45 @@ func getSecurityGroupName(namespace string, instanceName string) string {
 	return fmt.Sprintf("%s-%s-%s", getNamespacePrefix(namespace), instanceName, "sg")
 }
 
+// getVpnGatewayName returns the name of the VPN gateway in namespace.
+func getVpnGatewayName(namespace string) string {
+	return fmt.Sprintf("%s-%s", getNamespacePrefix(namespace), "vpn-gw")
+}
+
+// getCustomerGatewayName returns the name of the customer gateway for the i-th VPN connection to cloud.
+func getCustomerGatewayName(namespace string, cloud string, i int) string {
+	return fmt.Sprintf("%s-%s-cgw-%d", getNamespacePrefix(namespace), cloud, i)
+}
+
+// getVpnConnectionName returns the name of the i-th VPN connection to cloud.
+func getVpnConnectionName(namespace string, cloud string, i int) string {
+	return fmt.Sprintf("%s-%s-vpn-%d", getNamespacePrefix(namespace), cloud, i)
+}
+
+// getTagValue returns the value of the tag with key for a resource.
+func getTagValue(tags []types.Tag, key string) string {
+	for _, tag := range tags {
+		if *tag.Key == key {
+			return *tag.Value
+		}
+	}
+	return ""
+}
+
 // getInstanceArn returns the ARN of an instance.
 func getInstanceArn(accountId string, region string, instanceId string) string {
 	return fmt.Sprintf("arn:aws:ec2:%s:%s:instance/%s", region, accountId, instanceId)
 }
+
+// parseInstanceArn returns the region and instance ID from the ARN of an instance.
+func parseInstanceArn(instanceArn string) (string, string, error) {
+	parsedArn, err := arn.Parse(instanceArn)
+	if err != nil {
+		return "", "", err
+	}
+	instanceId, found := strings.CutPrefix(parsedArn.Resource, "instance/")
+	if parsedArn.Service != "ec2" || !found {
+		return "", "", fmt.Errorf("ARN %s does not refer to an instance", instanceArn)
+	}
+	return parsedArn.Region, instanceId, nil
+}
@@ -19,21 +19,37 @@ package aws
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/netip"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"github.com/paraglider-project/paraglider/internal/version"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
 	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	vpcIpv6CidrBlockPollInterval       = 1 * time.Second
+	vpcIpv6CidrBlockAssociationTimeout = 1 * time.Minute
 )
 
 type AwsPluginServer struct {
 	paragliderpb.UnimplementedCloudPluginServer
-	orchestratorServerAddr string
+	orchestratorServerAddr  string
+	orchestratorCredentials credentials.TransportCredentials // Used to dial the orchestrator (plaintext if nil)
 }
 
 func (s *AwsPluginServer) CreateResource(ctx context.Context, req *paragliderpb.CreateResourceRequest) (*paragliderpb.CreateResourceResponse, error) {
@@ -58,17 +74,18 @@ func (s *AwsPluginServer) _CreateResource(ctx context.Context, req *paragliderpb
 	if runInstancesInput.SubnetId != nil {
 		return nil, fmt.Errorf("resource description should not contain subnet ID")
 	}
-	if runInstancesInput.PrivateIpAddress != nil || runInstancesInput.EnablePrimaryIpv6 != nil ||
-		runInstancesInput.Ipv6AddressCount != nil || len(runInstancesInput.Ipv6Addresses) > 0 {
+	// IPv6 addresses can be requested (e.g., with Ipv6AddressCount) but not picked since the subnet is chosen by Paraglider
+	if runInstancesInput.PrivateIpAddress != nil || len(runInstancesInput.Ipv6Addresses) > 0 {
 		return nil, fmt.Errorf("resource description should not contain IP address information")
 	}
+	requestsIpv6 := aws.ToInt32(runInstancesInput.Ipv6AddressCount) > 0 || aws.ToBool(runInstancesInput.EnablePrimaryIpv6)
 
 	// Get region
 	availabilityZone := *runInstancesInput.Placement.AvailabilityZone
 	region := getRegionFromAvailabilityZone(availabilityZone)
 
 	// Load config and setup clients
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	cfg, err := loadConfig(ctx, region)
 	if err != nil {
 		return nil, fmt.Errorf("unable to load config: %w", err)
 	}
@@ -76,6 +93,7 @@ func (s *AwsPluginServer) _CreateResource(ctx context.Context, req *paragliderpb
 
 	// Get existing VPC
 	var vpc *types.Vpc
+	var vpcIpv6CidrBlock string
 	var subnet *types.Subnet
 	vpcName := getVpcName(req.Deployment.Namespace, region)
 	describeVpcsOutput, err := ec2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
@@ -87,9 +105,13 @@ func (s *AwsPluginServer) _CreateResource(ctx context.Context, req *paragliderpb
 	if len(describeVpcsOutput.Vpcs) <= 1 {
 		if len(describeVpcsOutput.Vpcs) == 1 {
 			vpc = &describeVpcsOutput.Vpcs[0]
+			vpcIpv6CidrBlock, err = getVpcIpv6CidrBlock(ctx, ec2Client, vpc)
+			if err != nil {
+				return nil, err
+			}
 		} else {
 			// Find unused address spaces from orchestrator
-			orchestratorConn, err := grpc.NewClient(s.orchestratorServerAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+			orchestratorConn, err := grpc.NewClient(s.orchestratorServerAddr, utils.DialCredentials(s.orchestratorCredentials), utils.TracingDialOption())
 			if err != nil {
 				return nil, fmt.Errorf("unable to establish connection with orchestrator: %w", err)
 			}
@@ -102,16 +124,21 @@ func (s *AwsPluginServer) _CreateResource(ctx context.Context, req *paragliderpb
 			}
 			vpcCidrBlock := findUnusedAddressSpacesResp.AddressSpaces[0]
 
-			// Create VPC
+			// Create VPC (dual-stack with an Amazon-provided IPv6 CIDR block since AWS doesn't allow private IPv6 ranges)
 			createVpcInput := &ec2.CreateVpcInput{
-				CidrBlock:         aws.String(vpcCidrBlock),
-				TagSpecifications: getTagSpecificationsForCreateResource(req.Deployment.Namespace, vpcName, types.ResourceTypeVpc),
+				CidrBlock:                   aws.String(vpcCidrBlock),
+				AmazonProvidedIpv6CidrBlock: aws.Bool(true),
+				TagSpecifications:           getTagSpecificationsForCreateResource(req.Deployment.Namespace, vpcName, types.ResourceTypeVpc),
 			}
 			createVpcOutput, err := ec2Client.CreateVpc(ctx, createVpcInput)
 			if err != nil {
 				return nil, fmt.Errorf("unable to create VPC: %w", err)
 			}
 			vpc = createVpcOutput.Vpc
+			vpcIpv6CidrBlock, err = getVpcIpv6CidrBlock(ctx, ec2Client, vpc)
+			if err != nil {
+				return nil, err
+			}
 
 			// Wait until default security group is available
 			securityGroupExistsWaiter := ec2.NewSecurityGroupExistsWaiter(ec2Client)
@@ -153,13 +180,8 @@ func (s *AwsPluginServer) _CreateResource(ctx context.Context, req *paragliderpb
 					return nil, fmt.Errorf("unable to revoke default inbound security group rule: %w", err)
 				}
 				revokeSecurityGroupEgressInput := &ec2.RevokeSecurityGroupEgressInput{
-					GroupId: securityGroup.GroupId,
-					IpPermissions: []types.IpPermission{
-						{
-							IpProtocol: aws.String("-1"),
-							IpRanges:   []types.IpRange{{CidrIp: aws.String("0.0.0.0/0")}},
-						},
-					},
+					GroupId:       securityGroup.GroupId,
+					IpPermissions: []types.IpPermission{getDefaultEgressIpPermission(vpcIpv6CidrBlock != "")},
 				}
 				_, err = ec2Client.RevokeSecurityGroupEgress(ctx, revokeSecurityGroupEgressInput)
 				if err != nil {
@@ -189,6 +211,13 @@ func (s *AwsPluginServer) _CreateResource(ctx context.Context, req *paragliderpb
 				AvailabilityZone:  aws.String(availabilityZone),
 				TagSpecifications: getTagSpecificationsForCreateResource(req.Deployment.Namespace, subnetName, types.ResourceTypeSubnet),
 			}
+			if vpcIpv6CidrBlock != "" {
+				subnetIpv6CidrBlock, err := utils.GetIPv6SubnetPrefix(vpcIpv6CidrBlock)
+				if err != nil {
+					return nil, fmt.Errorf("unable to get IPv6 CIDR block of subnet: %w", err)
+				}
+				createSubnetInput.Ipv6CidrBlock = aws.String(subnetIpv6CidrBlock)
+			}
 			createSubnetOutput, err := ec2Client.CreateSubnet(ctx, createSubnetInput)
 			if err != nil {
 				return nil, fmt.Errorf("unable to create subnet: %w", err)
@@ -198,6 +227,10 @@ func (s *AwsPluginServer) _CreateResource(ctx context.Context, req *paragliderpb
 			return nil, fmt.Errorf("found more than one subnet")
 		}
 
+		if requestsIpv6 && len(subnet.Ipv6CidrBlockAssociationSet) == 0 {
+			return nil, fmt.Errorf("resource requests IPv6 addresses but subnet %s has no IPv6 CIDR block", *subnet.SubnetId)
+		}
+
 		// Set subnet ID for resource
 		runInstancesInput.SubnetId = subnet.SubnetId
 	} else {
@@ -220,13 +253,8 @@ func (s *AwsPluginServer) _CreateResource(ctx context.Context, req *paragliderpb
 
 	// Remove default outbound rule from security group
 	revokeSecurityGroupEgressInput := &ec2.RevokeSecurityGroupEgressInput{
-		GroupId: createSecurityGroupOutput.GroupId,
-		IpPermissions: []types.IpPermission{
-			{
-				IpProtocol: aws.String("-1"),
-				IpRanges:   []types.IpRange{{CidrIp: aws.String("0.0.0.0/0")}},
-			},
-		},
+		GroupId:       createSecurityGroupOutput.GroupId,
+		IpPermissions: []types.IpPermission{getDefaultEgressIpPermission(vpcIpv6CidrBlock != "")},
 	}
 	_, err = ec2Client.RevokeSecurityGroupEgress(ctx, revokeSecurityGroupEgressInput)
 	if err != nil {
@@ -256,3 +284,1173 @@ func (s *AwsPluginServer) _CreateResource(ctx context.Context, req *paragliderpb
 	}
 	return resp, nil
 }
+
+func (s *AwsPluginServer) DeleteResource(ctx context.Context, req *paragliderpb.DeleteResourceRequest) (*paragliderpb.DeleteResourceResponse, error) {
+	return s._DeleteResource(ctx, req, &awsClients{})
+}
+
+func (s *AwsPluginServer) _DeleteResource(ctx context.Context, req *paragliderpb.DeleteResourceRequest, awsClients *awsClients) (*paragliderpb.DeleteResourceResponse, error) {
+	region, instanceId, err := parseInstanceArn(req.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse resource URI: %w", err)
+	}
+
+	// Load config and setup clients
+	cfg, err := loadConfig(ctx, region)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load config: %w", err)
+	}
+	ec2Client := awsClients.getOrCreateEc2Client(cfg)
+
+	instance, err := getInstance(ctx, ec2Client, req.Namespace, instanceId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Terminate instance
+	_, err = ec2Client.TerminateInstances(ctx, &ec2.TerminateInstancesInput{InstanceIds: []string{instanceId}})
+	if err != nil {
+		return nil, fmt.Errorf("unable to terminate instance: %w", err)
+	}
+	// Wait until instance is terminated since its security group can't be deleted while in use
+	instanceTerminatedWaiter := ec2.NewInstanceTerminatedWaiter(ec2Client)
+	err = instanceTerminatedWaiter.Wait(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []string{instanceId},
+	}, 3*time.Minute)
+	if err != nil {
+		return nil, fmt.Errorf("unable to wait for instance to be terminated: %w", err)
+	}
+
+	// Delete security group
+	securityGroupName := getSecurityGroupName(req.Namespace, getNameTag(instance.Tags))
+	describeSecurityGroupsOutput, err := ec2Client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+		Filters: getDescribeFilter(req.Namespace, securityGroupName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get security groups: %w", err)
+	}
+	for _, securityGroup := range describeSecurityGroupsOutput.SecurityGroups {
+		_, err = ec2Client.DeleteSecurityGroup(ctx, &ec2.DeleteSecurityGroupInput{GroupId: securityGroup.GroupId})
+		if err != nil {
+			return nil, fmt.Errorf("unable to delete security group: %w", err)
+		}
+	}
+
+	return &paragliderpb.DeleteResourceResponse{}, nil
+}
+
+func (s *AwsPluginServer) DeleteNamespace(ctx context.Context, req *paragliderpb.DeleteNamespaceRequest) (*paragliderpb.DeleteNamespaceResponse, error) {
+	return s._DeleteNamespace(ctx, req, &awsClients{})
+}
+
+// _DeleteNamespace deletes the VPN and the VPCs Paraglider created for a namespace.
+// VPCs brought in by AttachResource are left in place, and instances have to be deleted beforehand.
+func (s *AwsPluginServer) _DeleteNamespace(ctx context.Context, req *paragliderpb.DeleteNamespaceRequest, awsClients *awsClients) (*paragliderpb.DeleteNamespaceResponse, error) {
+	namespace := req.Deployment.Namespace
+	cfg, err := loadConfig(ctx, vpnRegion)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load config: %w", err)
+	}
+	ec2Client := awsClients.getOrCreateEc2Client(cfg)
+
+	// The transit gateway has to go first since it's attached to the VPCs
+	err = deleteVpn(ctx, ec2Client, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	describeRegionsOutput, err := ec2Client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get regions: %w", err)
+	}
+	for _, region := range describeRegionsOutput.Regions {
+		regionName := *region.RegionName
+		describeVpcsOutput, err := ec2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
+			Filters: getDescribeFilter(namespace, getVpcName(namespace, regionName)),
+		}, withRegion(regionName))
+		if err != nil {
+			return nil, fmt.Errorf("unable to get VPCs in region %s: %w", regionName, err)
+		}
+		for _, vpc := range describeVpcsOutput.Vpcs {
+			vpcFilter := []types.Filter{{Name: aws.String("vpc-id"), Values: []string{*vpc.VpcId}}}
+
+			describeSecurityGroupsOutput, err := ec2Client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{Filters: vpcFilter}, withRegion(regionName))
+			if err != nil {
+				return nil, fmt.Errorf("unable to get security groups: %w", err)
+			}
+			for _, securityGroup := range describeSecurityGroupsOutput.SecurityGroups {
+				// The default security group is deleted along with the VPC
+				if securityGroup.GroupName != nil && *securityGroup.GroupName == defaultSecurityGroupName {
+					continue
+				}
+				_, err = ec2Client.DeleteSecurityGroup(ctx, &ec2.DeleteSecurityGroupInput{GroupId: securityGroup.GroupId}, withRegion(regionName))
+				if err != nil {
+					return nil, fmt.Errorf("unable to delete security group %s: %w", *securityGroup.GroupId, err)
+				}
+			}
+
+			describeSubnetsOutput, err := ec2Client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{Filters: vpcFilter}, withRegion(regionName))
+			if err != nil {
+				return nil, fmt.Errorf("unable to get subnets: %w", err)
+			}
+			for _, subnet := range describeSubnetsOutput.Subnets {
+				_, err = ec2Client.DeleteSubnet(ctx, &ec2.DeleteSubnetInput{SubnetId: subnet.SubnetId}, withRegion(regionName))
+				if err != nil {
+					return nil, fmt.Errorf("unable to delete subnet %s: %w", *subnet.SubnetId, err)
+				}
+			}
+
+			_, err = ec2Client.DeleteVpc(ctx, &ec2.DeleteVpcInput{VpcId: vpc.VpcId}, withRegion(regionName))
+			if err != nil {
+				return nil, fmt.Errorf("unable to delete VPC %s: %w", *vpc.VpcId, err)
+			}
+		}
+	}
+
+	return &paragliderpb.DeleteNamespaceResponse{}, nil
+}
+
+func (s *AwsPluginServer) GetPermitList(ctx context.Context, req *paragliderpb.GetPermitListRequest) (*paragliderpb.GetPermitListResponse, error) {
+	return s._GetPermitList(ctx, req, &awsClients{})
+}
+
+func (s *AwsPluginServer) _GetPermitList(ctx context.Context, req *paragliderpb.GetPermitListRequest, awsClients *awsClients) (*paragliderpb.GetPermitListResponse, error) {
+	ec2Client, securityGroupId, err := setupInstanceSecurityGroup(ctx, req.Namespace, req.Resource, awsClients)
+	if err != nil {
+		return nil, err
+	}
+
+	securityGroupRules, err := getSecurityGroupRules(ctx, ec2Client, securityGroupId)
+	if err != nil {
+		return nil, err
+	}
+	permitListRules, err := securityGroupRulesToPermitListRules(securityGroupRules)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert security group rules to permit list rules: %w", err)
+	}
+
+	return &paragliderpb.GetPermitListResponse{Rules: permitListRules}, nil
+}
+
+func (s *AwsPluginServer) AddPermitListRules(ctx context.Context, req *paragliderpb.AddPermitListRulesRequest) (*paragliderpb.AddPermitListRulesResponse, error) {
+	return s._AddPermitListRules(ctx, req, &awsClients{})
+}
+
+func (s *AwsPluginServer) _AddPermitListRules(ctx context.Context, req *paragliderpb.AddPermitListRulesRequest, awsClients *awsClients) (*paragliderpb.AddPermitListRulesResponse, error) {
+	for _, rule := range req.Rules {
+		if rule.Action == paragliderpb.RuleAction_DENY {
+			return nil, fmt.Errorf("rule %s: AWS security groups do not support deny rules", rule.Name)
+		}
+	}
+
+	ec2Client, securityGroupId, err := setupInstanceSecurityGroup(ctx, req.Namespace, req.Resource, awsClients)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get existing rules
+	securityGroupRules, err := getSecurityGroupRules(ctx, ec2Client, securityGroupId)
+	if err != nil {
+		return nil, err
+	}
+	existingSecurityGroupRules := getSecurityGroupRulesByName(securityGroupRules)
+
+	// Get used address spaces of all clouds
+	orchestratorConn, err := grpc.NewClient(s.orchestratorServerAddr, utils.DialCredentials(s.orchestratorCredentials), utils.TracingDialOption())
+	if err != nil {
+		return nil, fmt.Errorf("unable to establish connection with orchestrator: %w", err)
+	}
+	defer orchestratorConn.Close()
+	orchestratorClient := paragliderpb.NewControllerClient(orchestratorConn)
+	getUsedAddressSpacesResp, err := orchestratorClient.GetUsedAddressSpaces(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get used address spaces: %w", err)
+	}
+
+	for _, permitListRule := range req.Rules {
+		ipPermission, err := permitListRuleToIpPermission(permitListRule)
+		if err != nil {
+			return nil, fmt.Errorf("unable to convert permit list rule to security group rule: %w", err)
+		}
+
+		isEgress := permitListRule.Direction == paragliderpb.Direction_OUTBOUND
+		var keptRules, staleRules []types.SecurityGroupRule
+		if existingRules, ok := existingSecurityGroupRules[permitListRule.Name]; ok {
+			existingPermitListRules, err := securityGroupRulesToPermitListRules(existingRules)
+			if err != nil {
+				return nil, fmt.Errorf("unable to convert security group rules to permit list rules: %w", err)
+			}
+			if isPermitListRuleEqual(existingPermitListRules[0], permitListRule) {
+				// Rule already exists and is equivalent to the provided permit list rule
+				continue
+			}
+			// Security group rules can't be modified in place, so the old ones are replaced. Old rules which allow the same
+			// traffic as a new one are kept since AWS rejects duplicates, and the others are only revoked once the new ones exist.
+			keptRules, staleRules, ipPermission = splitSecurityGroupRules(existingRules, isEgress, ipPermission)
+		}
+
+		// Get all peering cloud infos
+		peeringCloudInfos, err := utils.GetPermitListRulePeeringCloudInfo(permitListRule, getUsedAddressSpacesResp.AddressSpaceMappings)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get peering cloud infos: %w", err)
+		}
+		for _, peeringCloudInfo := range peeringCloudInfos {
+			if peeringCloudInfo == nil {
+				// TODO: setup a NAT gateway for public IP address targets
+				continue
+			}
+			if peeringCloudInfo.Cloud != utils.AWS {
+				// Create VPN connections
+				connectCloudsReq := &paragliderpb.ConnectCloudsRequest{
+					CloudA:          utils.AWS,
+					CloudANamespace: req.Namespace,
+					CloudB:          peeringCloudInfo.Cloud,
+					CloudBNamespace: peeringCloudInfo.Namespace,
+				}
+				connectCloudsResp, err := orchestratorClient.ConnectClouds(ctx, connectCloudsReq)
+				if err != nil {
+					return nil, fmt.Errorf("unable to connect clouds: %w", err)
+				}
+				utils.AddResponseNotices(ctx, connectCloudsResp)
+			} else if peeringCloudInfo.Namespace != req.Namespace {
+				return nil, fmt.Errorf("permit list rule targets in other AWS namespaces are not supported")
+			}
+		}
+
+		if len(ipPermission.IpRanges) != 0 || len(ipPermission.Ipv6Ranges) != 0 {
+			if !isEgress {
+				_, err = ec2Client.AuthorizeSecurityGroupIngress(ctx, &ec2.AuthorizeSecurityGroupIngressInput{
+					GroupId:       aws.String(securityGroupId),
+					IpPermissions: []types.IpPermission{*ipPermission},
+				})
+			} else {
+				_, err = ec2Client.AuthorizeSecurityGroupEgress(ctx, &ec2.AuthorizeSecurityGroupEgressInput{
+					GroupId:       aws.String(securityGroupId),
+					IpPermissions: []types.IpPermission{*ipPermission},
+				})
+			}
+			if err != nil {
+				return nil, fmt.Errorf("unable to create security group rule: %w", err)
+			}
+		}
+
+		if len(keptRules) != 0 {
+			description, err := getRuleDescription(permitListRule.Name, permitListRule.Tags)
+			if err != nil {
+				return nil, err
+			}
+			err = updateSecurityGroupRuleDescriptions(ctx, ec2Client, securityGroupId, isEgress, keptRules, description)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if len(staleRules) != 0 {
+			err = revokeSecurityGroupRules(ctx, ec2Client, securityGroupId, staleRules)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &paragliderpb.AddPermitListRulesResponse{}, nil
+}
+
+func (s *AwsPluginServer) DeletePermitListRules(ctx context.Context, req *paragliderpb.DeletePermitListRulesRequest) (*paragliderpb.DeletePermitListRulesResponse, error) {
+	return s._DeletePermitListRules(ctx, req, &awsClients{})
+}
+
+func (s *AwsPluginServer) _DeletePermitListRules(ctx context.Context, req *paragliderpb.DeletePermitListRulesRequest, awsClients *awsClients) (*paragliderpb.DeletePermitListRulesResponse, error) {
+	ec2Client, securityGroupId, err := setupInstanceSecurityGroup(ctx, req.Namespace, req.Resource, awsClients)
+	if err != nil {
+		return nil, err
+	}
+
+	securityGroupRules, err := getSecurityGroupRules(ctx, ec2Client, securityGroupId)
+	if err != nil {
+		return nil, err
+	}
+	existingSecurityGroupRules := getSecurityGroupRulesByName(securityGroupRules)
+
+	for _, ruleName := range req.RuleNames {
+		existingRules, ok := existingSecurityGroupRules[ruleName]
+		if !ok {
+			return nil, fmt.Errorf("permit list rule %s not found", ruleName)
+		}
+		err = revokeSecurityGroupRules(ctx, ec2Client, securityGroupId, existingRules)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &paragliderpb.DeletePermitListRulesResponse{}, nil
+}
+
+func (s *AwsPluginServer) GetUsedAddressSpaces(ctx context.Context, req *paragliderpb.GetUsedAddressSpacesRequest) (*paragliderpb.GetUsedAddressSpacesResponse, error) {
+	return s._GetUsedAddressSpaces(ctx, req, &awsClients{})
+}
+
+func (s *AwsPluginServer) _GetUsedAddressSpaces(ctx context.Context, req *paragliderpb.GetUsedAddressSpacesRequest, awsClients *awsClients) (*paragliderpb.GetUsedAddressSpacesResponse, error) {
+	cfg, err := loadConfig(ctx, defaultRegion)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load config: %w", err)
+	}
+	ec2Client := awsClients.getOrCreateEc2Client(cfg)
+
+	// VPCs are regional, so every enabled region has to be checked
+	describeRegionsOutput, err := ec2Client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get regions: %w", err)
+	}
+
+	resp := &paragliderpb.GetUsedAddressSpacesResponse{}
+	resp.AddressSpaceMappings = make([]*paragliderpb.AddressSpaceMapping, len(req.Deployments))
+	for i, deployment := range req.Deployments {
+		resp.AddressSpaceMappings[i] = &paragliderpb.AddressSpaceMapping{
+			AddressSpaces: []string{},
+			Cloud:         utils.AWS,
+			Namespace:     deployment.Namespace,
+		}
+		for _, region := range describeRegionsOutput.Regions {
+			describeVpcsOutput, err := ec2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
+				Filters: []types.Filter{{Name: aws.String("tag:Namespace"), Values: []string{deployment.Namespace}}},
+			}, withRegion(*region.RegionName))
+			if err != nil {
+				return nil, fmt.Errorf("unable to get VPCs in region %s: %w", *region.RegionName, err)
+			}
+			for _, vpc := range describeVpcsOutput.Vpcs {
+				resp.AddressSpaceMappings[i].AddressSpaces = append(resp.AddressSpaceMappings[i].AddressSpaces, getVpcCidrBlocks(&vpc)...)
+			}
+		}
+	}
+	return resp, nil
+}
+
+func (s *AwsPluginServer) GetUsedAsns(ctx context.Context, req *paragliderpb.GetUsedAsnsRequest) (*paragliderpb.GetUsedAsnsResponse, error) {
+	return s._GetUsedAsns(ctx, req, &awsClients{})
+}
+
+func (s *AwsPluginServer) _GetUsedAsns(ctx context.Context, req *paragliderpb.GetUsedAsnsRequest, awsClients *awsClients) (*paragliderpb.GetUsedAsnsResponse, error) {
+	cfg, err := loadConfig(ctx, vpnRegion)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load config: %w", err)
+	}
+	ec2Client := awsClients.getOrCreateEc2Client(cfg)
+
+	resp := &paragliderpb.GetUsedAsnsResponse{}
+	for _, deployment := range req.Deployments {
+		describeTransitGatewaysOutput, err := ec2Client.DescribeTransitGateways(ctx, &ec2.DescribeTransitGatewaysInput{
+			Filters: getDescribeFilter(deployment.Namespace, getVpnGatewayName(deployment.Namespace)),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to get transit gateways: %w", err)
+		}
+		for _, transitGateway := range describeTransitGatewaysOutput.TransitGateways {
+			if transitGateway.Options != nil && transitGateway.Options.AmazonSideAsn != nil {
+				resp.Asns = append(resp.Asns, uint32(*transitGateway.Options.AmazonSideAsn))
+			}
+		}
+	}
+	return resp, nil
+}
+
+// Add an existing AWS instance to a Paraglider deployment
+func (s *AwsPluginServer) AttachResource(ctx context.Context, req *paragliderpb.AttachResourceRequest) (*paragliderpb.AttachResourceResponse, error) {
+	return s._AttachResource(ctx, req, &awsClients{})
+}
+
+func (s *AwsPluginServer) _AttachResource(ctx context.Context, req *paragliderpb.AttachResourceRequest, awsClients *awsClients) (*paragliderpb.AttachResourceResponse, error) {
+	region, instanceId, err := parseInstanceArn(req.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse resource URI: %w", err)
+	}
+	parsedArn, err := arn.Parse(req.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse resource URI: %w", err)
+	}
+
+	// Load config and setup clients
+	cfg, err := loadConfig(ctx, region)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load config: %w", err)
+	}
+	ec2Client := awsClients.getOrCreateEc2Client(cfg)
+
+	describeInstancesOutput, err := ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{InstanceIds: []string{instanceId}})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get instance: %w", err)
+	}
+	if len(describeInstancesOutput.Reservations) != 1 || len(describeInstancesOutput.Reservations[0].Instances) != 1 {
+		return nil, fmt.Errorf("instance %s not found", instanceId)
+	}
+	instance := describeInstancesOutput.Reservations[0].Instances[0]
+	if instance.VpcId == nil || instance.PrivateIpAddress == nil {
+		return nil, fmt.Errorf("instance %s is not in a VPC", instanceId)
+	}
+
+	describeVpcsOutput, err := ec2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{VpcIds: []string{*instance.VpcId}})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get VPC: %w", err)
+	}
+	if len(describeVpcsOutput.Vpcs) != 1 {
+		return nil, fmt.Errorf("VPC %s not found", *instance.VpcId)
+	}
+	vpc := describeVpcsOutput.Vpcs[0]
+
+	// The VPC's address space must not overlap with any address space already used by Paraglider (unless it's already part of the namespace)
+	if getTagValue(vpc.Tags, "Namespace") != req.Namespace {
+		orchestratorConn, err := grpc.NewClient(s.orchestratorServerAddr, utils.DialCredentials(s.orchestratorCredentials), utils.TracingDialOption())
+		if err != nil {
+			return nil, fmt.Errorf("unable to establish connection with orchestrator: %w", err)
+		}
+		defer orchestratorConn.Close()
+		orchestratorClient := paragliderpb.NewControllerClient(orchestratorConn)
+		getUsedAddressSpacesResp, err := orchestratorClient.GetUsedAddressSpaces(ctx, &emptypb.Empty{})
+		if err != nil {
+			return nil, fmt.Errorf("unable to get used address spaces: %w", err)
+		}
+		for _, addressSpaceMapping := range getUsedAddressSpacesResp.AddressSpaceMappings {
+			for _, addressSpace := range addressSpaceMapping.AddressSpaces {
+				for _, vpcCidrBlock := range getVpcCidrBlocks(&vpc) {
+					doesOverlap, err := utils.DoCIDROverlap(vpcCidrBlock, addressSpace)
+					if err != nil {
+						return nil, fmt.Errorf("unable to check address space overlap: %w", err)
+					}
+					if doesOverlap {
+						return nil, fmt.Errorf("VPC address space %s overlaps with address space %s used by Paraglider", vpcCidrBlock, addressSpace)
+					}
+				}
+			}
+		}
+	}
+
+	// Instances are looked up by their name, so unnamed instances are named after their ID
+	instanceName := getNameTag(instance.Tags)
+	instanceTags := []types.Tag{{Key: aws.String("Namespace"), Value: aws.String(req.Namespace)}}
+	if instanceName == "" {
+		instanceName = instanceId
+		instanceTags = append(instanceTags, types.Tag{Key: aws.String("Name"), Value: aws.String(instanceName)})
+	}
+
+	// Create security group
+	securityGroupName := getSecurityGroupName(req.Namespace, instanceName)
+	createSecurityGroupOutput, err := ec2Client.CreateSecurityGroup(ctx, &ec2.CreateSecurityGroupInput{
+		VpcId:             instance.VpcId,
+		GroupName:         aws.String(securityGroupName),
+		Description:       aws.String("Security group for Paraglider"),
+		TagSpecifications: getTagSpecificationsForCreateResource(req.Namespace, securityGroupName, types.ResourceTypeSecurityGroup),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create security group: %w", err)
+	}
+
+	// Remove default outbound rule from security group (the IPv6 one only exists in dual-stack VPCs, which instances with IPv6 addresses are in)
+	_, err = ec2Client.RevokeSecurityGroupEgress(ctx, &ec2.RevokeSecurityGroupEgressInput{
+		GroupId:       createSecurityGroupOutput.GroupId,
+		IpPermissions: []types.IpPermission{getDefaultEgressIpPermission(hasIpv6Address(&instance))},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to revoke default outbound security group rule: %w", err)
+	}
+
+	// Add the security group to the instance while keeping its existing ones
+	securityGroupIds := []string{*createSecurityGroupOutput.GroupId}
+	for _, securityGroup := range instance.SecurityGroups {
+		securityGroupIds = append(securityGroupIds, *securityGroup.GroupId)
+	}
+	_, err = ec2Client.ModifyInstanceAttribute(ctx, &ec2.ModifyInstanceAttributeInput{
+		InstanceId: aws.String(instanceId),
+		Groups:     securityGroupIds,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to add security group to instance: %w", err)
+	}
+
+	// Tag the instance and its VPC with the namespace
+	_, err = ec2Client.CreateTags(ctx, &ec2.CreateTagsInput{
+		Resources: []string{instanceId},
+		Tags:      instanceTags,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to tag instance: %w", err)
+	}
+	_, err = ec2Client.CreateTags(ctx, &ec2.CreateTagsInput{
+		Resources: []string{*instance.VpcId},
+		Tags:      []types.Tag{{Key: aws.String("Namespace"), Value: aws.String(req.Namespace)}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to tag VPC: %w", err)
+	}
+
+	resp := &paragliderpb.AttachResourceResponse{
+		Name: instanceName,
+		Uri:  getInstanceArn(parsedArn.AccountID, region, instanceId),
+		Ip:   *instance.PrivateIpAddress,
+	}
+	return resp, nil
+}
+
+func (s *AwsPluginServer) GetUsedBgpPeeringIpAddresses(ctx context.Context, req *paragliderpb.GetUsedBgpPeeringIpAddressesRequest) (*paragliderpb.GetUsedBgpPeeringIpAddressesResponse, error) {
+	return s._GetUsedBgpPeeringIpAddresses(ctx, req, &awsClients{})
+}
+
+func (s *AwsPluginServer) _GetUsedBgpPeeringIpAddresses(ctx context.Context, req *paragliderpb.GetUsedBgpPeeringIpAddressesRequest, awsClients *awsClients) (*paragliderpb.GetUsedBgpPeeringIpAddressesResponse, error) {
+	cfg, err := loadConfig(ctx, vpnRegion)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load config: %w", err)
+	}
+	ec2Client := awsClients.getOrCreateEc2Client(cfg)
+
+	resp := &paragliderpb.GetUsedBgpPeeringIpAddressesResponse{}
+	for _, deployment := range req.Deployments {
+		describeVpnConnectionsOutput, err := ec2Client.DescribeVpnConnections(ctx, &ec2.DescribeVpnConnectionsInput{
+			Filters: []types.Filter{
+				{Name: aws.String("tag:Namespace"), Values: []string{deployment.Namespace}},
+				{Name: aws.String("state"), Values: []string{"pending", "available"}},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to get VPN connections: %w", err)
+		}
+		for _, vpnConnection := range describeVpnConnectionsOutput.VpnConnections {
+			if vpnConnection.Options == nil {
+				continue
+			}
+			// Both tunnels are reported since AWS assigns an inside CIDR to the tunnel not used by Paraglider as well
+			for _, tunnelOption := range vpnConnection.Options.TunnelOptions {
+				if tunnelOption.TunnelInsideCidr == nil {
+					continue
+				}
+				tunnelInsideCidr, err := netip.ParsePrefix(*tunnelOption.TunnelInsideCidr)
+				if err != nil {
+					return nil, fmt.Errorf("unable to parse tunnel inside CIDR: %w", err)
+				}
+				resp.IpAddresses = append(resp.IpAddresses, tunnelInsideCidr.Addr().Next().String())
+			}
+		}
+	}
+	return resp, nil
+}
+
+func (s *AwsPluginServer) CreateVpnGateway(ctx context.Context, req *paragliderpb.CreateVpnGatewayRequest) (*paragliderpb.CreateVpnGatewayResponse, error) {
+	return s._CreateVpnGateway(ctx, req, &awsClients{})
+}
+
+// _CreateVpnGateway creates a transit gateway for the namespace and attaches the namespace's VPCs to it.
+// Gateway IP addresses are only known once VPN connections are created, so they're returned by CreateVpnConnections instead.
+func (s *AwsPluginServer) _CreateVpnGateway(ctx context.Context, req *paragliderpb.CreateVpnGatewayRequest, awsClients *awsClients) (*paragliderpb.CreateVpnGatewayResponse, error) {
+	cfg, err := loadConfig(ctx, vpnRegion)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load config: %w", err)
+	}
+	ec2Client := awsClients.getOrCreateEc2Client(cfg)
+
+	transitGateway, err := getTransitGateway(ctx, ec2Client, req.Deployment.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if transitGateway == nil {
+		// Find unused ASN
+		orchestratorConn, err := grpc.NewClient(s.orchestratorServerAddr, utils.DialCredentials(s.orchestratorCredentials), utils.TracingDialOption())
+		if err != nil {
+			return nil, fmt.Errorf("unable to establish connection with orchestrator: %w", err)
+		}
+		defer orchestratorConn.Close()
+		orchestratorClient := paragliderpb.NewControllerClient(orchestratorConn)
+		findUnusedAsnResp, err := orchestratorClient.FindUnusedAsn(ctx, &paragliderpb.FindUnusedAsnRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("unable to find unused ASN: %w", err)
+		}
+
+		// Create transit gateway
+		createTransitGatewayOutput, err := ec2Client.CreateTransitGateway(ctx, &ec2.CreateTransitGatewayInput{
+			Description: aws.String("Paraglider VPN gateway for multicloud connections"),
+			Options: &types.TransitGatewayRequestOptions{
+				AmazonSideAsn:                aws.Int64(int64(findUnusedAsnResp.Asn)),
+				AutoAcceptSharedAttachments:  types.AutoAcceptSharedAttachmentsValueDisable,
+				DefaultRouteTableAssociation: types.DefaultRouteTableAssociationValueEnable,
+				DefaultRouteTablePropagation: types.DefaultRouteTablePropagationValueEnable,
+				VpnEcmpSupport:               types.VpnEcmpSupportValueEnable,
+			},
+			TagSpecifications: getTagSpecificationsForCreateResource(req.Deployment.Namespace, getVpnGatewayName(req.Deployment.Namespace), types.ResourceTypeTransitGateway),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to create transit gateway: %w", err)
+		}
+		transitGateway = createTransitGatewayOutput.TransitGateway
+		utils.AddSideEffect(ctx, getVpnGatewayName(req.Deployment.Namespace), fmt.Sprintf("created VPN gateway %s (transit gateway)", getVpnGatewayName(req.Deployment.Namespace)))
+	}
+	if transitGateway.State != types.TransitGatewayStateAvailable {
+		err = waitForTransitGateway(ctx, ec2Client, *transitGateway.TransitGatewayId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = attachVpcsToTransitGateway(ctx, ec2Client, req.Deployment.Namespace, *transitGateway.TransitGatewayId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &paragliderpb.CreateVpnGatewayResponse{Asn: uint32(*transitGateway.Options.AmazonSideAsn)}, nil
+}
+
+func (s *AwsPluginServer) CreateVpnConnections(ctx context.Context, req *paragliderpb.CreateVpnConnectionsRequest) (*paragliderpb.CreateVpnConnectionsResponse, error) {
+	return s._CreateVpnConnections(ctx, req, &awsClients{})
+}
+
+func (s *AwsPluginServer) _CreateVpnConnections(ctx context.Context, req *paragliderpb.CreateVpnConnectionsRequest, awsClients *awsClients) (*paragliderpb.CreateVpnConnectionsResponse, error) {
+	if req.IsBgpDisabled {
+		return nil, fmt.Errorf("VPN connections without BGP are not supported by AWS")
+	}
+	vpnNumConnections := utils.GetNumVpnConnections(req.Cloud, utils.AWS)
+	if len(req.GatewayIpAddresses) < vpnNumConnections || len(req.BgpIpAddresses) < vpnNumConnections {
+		return nil, fmt.Errorf("expected %d gateway and BGP IP addresses", vpnNumConnections)
+	}
+
+	cfg, err := loadConfig(ctx, vpnRegion)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load config: %w", err)
+	}
+	ec2Client := awsClients.getOrCreateEc2Client(cfg)
+
+	transitGateway, err := getTransitGateway(ctx, ec2Client, req.Deployment.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if transitGateway == nil {
+		return nil, fmt.Errorf("transit gateway not found")
+	}
+
+	resp := &paragliderpb.CreateVpnConnectionsResponse{GatewayIpAddresses: make([]string, vpnNumConnections)}
+	for i := 0; i < vpnNumConnections; i++ {
+		// Create customer gateway (i.e., the VPN gateway interface in the remote cloud)
+		customerGatewayName := getCustomerGatewayName(req.Deployment.Namespace, req.Cloud, i)
+		customerGatewayFilters := getDescribeFilter(req.Deployment.Namespace, customerGatewayName)
+		customerGatewayFilters = append(customerGatewayFilters, types.Filter{Name: aws.String("state"), Values: []string{"pending", "available"}})
+		describeCustomerGatewaysOutput, err := ec2Client.DescribeCustomerGateways(ctx, &ec2.DescribeCustomerGatewaysInput{Filters: customerGatewayFilters})
+		if err != nil {
+			return nil, fmt.Errorf("unable to get customer gateways: %w", err)
+		}
+		var customerGateway *types.CustomerGateway
+		if len(describeCustomerGatewaysOutput.CustomerGateways) > 0 {
+			customerGateway = &describeCustomerGatewaysOutput.CustomerGateways[0]
+		} else {
+			createCustomerGatewayInput := &ec2.CreateCustomerGatewayInput{
+				Type:              types.GatewayTypeIpsec1,
+				IpAddress:         aws.String(req.GatewayIpAddresses[i]),
+				TagSpecifications: getTagSpecificationsForCreateResource(req.Deployment.Namespace, customerGatewayName, types.ResourceTypeCustomerGateway),
+			}
+			if req.Asn > math.MaxInt32 {
+				createCustomerGatewayInput.BgpAsnExtended = aws.Int64(int64(req.Asn))
+			} else {
+				createCustomerGatewayInput.BgpAsn = aws.Int32(int32(req.Asn))
+			}
+			createCustomerGatewayOutput, err := ec2Client.CreateCustomerGateway(ctx, createCustomerGatewayInput)
+			if err != nil {
+				return nil, fmt.Errorf("unable to create customer gateway: %w", err)
+			}
+			customerGateway = createCustomerGatewayOutput.CustomerGateway
+		}
+
+		// Create VPN connection. Like other clouds, existing connections are kept as is since a new shared key is generated on every call.
+		vpnConnectionName := getVpnConnectionName(req.Deployment.Namespace, req.Cloud, i)
+		vpnConnectionFilters := getDescribeFilter(req.Deployment.Namespace, vpnConnectionName)
+		vpnConnectionFilters = append(vpnConnectionFilters, types.Filter{Name: aws.String("state"), Values: []string{"pending", "available"}})
+		describeVpnConnectionsOutput, err := ec2Client.DescribeVpnConnections(ctx, &ec2.DescribeVpnConnectionsInput{Filters: vpnConnectionFilters})
+		if err != nil {
+			return nil, fmt.Errorf("unable to get VPN connections: %w", err)
+		}
+		var vpnConnectionId string
+		if len(describeVpnConnectionsOutput.VpnConnections) > 0 {
+			vpnConnectionId = *describeVpnConnectionsOutput.VpnConnections[0].VpnConnectionId
+		} else {
+			tunnelInsideCidr, err := getTunnelInsideCidr(req.BgpIpAddresses[i])
+			if err != nil {
+				return nil, err
+			}
+			tagSpecifications := getTagSpecificationsForCreateResource(req.Deployment.Namespace, vpnConnectionName, types.ResourceTypeVpnConnection)
+			tagSpecifications[0].Tags = append(tagSpecifications[0].Tags, types.Tag{Key: aws.String(tunnelInsideCidrTagKey), Value: aws.String(tunnelInsideCidr)})
+			createVpnConnectionOutput, err := ec2Client.CreateVpnConnection(ctx, &ec2.CreateVpnConnectionInput{
+				CustomerGatewayId: customerGateway.CustomerGatewayId,
+				TransitGatewayId:  transitGateway.TransitGatewayId,
+				Type:              aws.String(string(types.GatewayTypeIpsec1)),
+				Options: &types.VpnConnectionOptionsSpecification{
+					StaticRoutesOnly: aws.Bool(false),
+					TunnelOptions: []types.VpnTunnelOptionsSpecification{
+						{TunnelInsideCidr: aws.String(tunnelInsideCidr), PreSharedKey: aws.String(req.SharedKey)},
+					},
+				},
+				TagSpecifications: tagSpecifications,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("unable to create VPN connection: %w", err)
+			}
+			vpnConnectionId = *createVpnConnectionOutput.VpnConnection.VpnConnectionId
+			utils.AddSideEffect(ctx, vpnConnectionName, fmt.Sprintf("created VPN connection %s to %s", vpnConnectionName, req.Cloud))
+		}
+
+		// Wait until VPN connection is available for the tunnel outside IP addresses
+		vpnConnectionAvailableWaiter := ec2.NewVpnConnectionAvailableWaiter(ec2Client)
+		describeVpnConnectionsOutput, err = vpnConnectionAvailableWaiter.WaitForOutput(ctx, &ec2.DescribeVpnConnectionsInput{
+			VpnConnectionIds: []string{vpnConnectionId},
+		}, vpnConnectionAvailableTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("unable to wait for VPN connection to be available: %w", err)
+		}
+		resp.GatewayIpAddresses[i], err = getTunnelOutsideIpAddress(&describeVpnConnectionsOutput.VpnConnections[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Route the address spaces of the remote cloud to the transit gateway
+	orchestratorConn, err := grpc.NewClient(s.orchestratorServerAddr, utils.DialCredentials(s.orchestratorCredentials), utils.TracingDialOption())
+	if err != nil {
+		return nil, fmt.Errorf("unable to establish connection with orchestrator: %w", err)
+	}
+	defer orchestratorConn.Close()
+	orchestratorClient := paragliderpb.NewControllerClient(orchestratorConn)
+	getUsedAddressSpacesResp, err := orchestratorClient.GetUsedAddressSpaces(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get used address spaces: %w", err)
+	}
+	remoteAddressSpaces := []string{}
+	for _, addressSpaceMapping := range getUsedAddressSpacesResp.AddressSpaceMappings {
+		if addressSpaceMapping.Cloud == req.Cloud {
+			remoteAddressSpaces = append(remoteAddressSpaces, addressSpaceMapping.AddressSpaces...)
+		}
+	}
+	err = addTransitGatewayRoutes(ctx, ec2Client, req.Deployment.Namespace, *transitGateway.TransitGatewayId, remoteAddressSpaces)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// GetNetworkAddressSpaces returns the address spaces of the VPC containing the provided address space
+func (s *AwsPluginServer) GetNetworkAddressSpaces(ctx context.Context, req *paragliderpb.GetNetworkAddressSpacesRequest) (*paragliderpb.GetNetworkAddressSpacesResponse, error) {
+	return s._GetNetworkAddressSpaces(ctx, req, &awsClients{})
+}
+
+func (s *AwsPluginServer) _GetNetworkAddressSpaces(ctx context.Context, req *paragliderpb.GetNetworkAddressSpacesRequest, awsClients *awsClients) (*paragliderpb.GetNetworkAddressSpacesResponse, error) {
+	addressSpace, err := getTargetCidr(req.AddressSpace)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := loadConfig(ctx, defaultRegion)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load config: %w", err)
+	}
+	ec2Client := awsClients.getOrCreateEc2Client(cfg)
+
+	describeRegionsOutput, err := ec2Client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get regions: %w", err)
+	}
+	for _, region := range describeRegionsOutput.Regions {
+		describeVpcsOutput, err := ec2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
+			Filters: []types.Filter{{Name: aws.String("tag:Namespace"), Values: []string{req.Deployment.Namespace}}},
+		}, withRegion(*region.RegionName))
+		if err != nil {
+			return nil, fmt.Errorf("unable to get VPCs in region %s: %w", *region.RegionName, err)
+		}
+		for _, vpc := range describeVpcsOutput.Vpcs {
+			vpcCidrBlocks := getVpcCidrBlocks(&vpc)
+			for _, vpcCidrBlock := range vpcCidrBlocks {
+				isSubset, err := utils.IsCIDRSubset(addressSpace, vpcCidrBlock)
+				if err != nil {
+					return nil, fmt.Errorf("unable to check address space: %w", err)
+				}
+				if isSubset {
+					return &paragliderpb.GetNetworkAddressSpacesResponse{AddressSpaces: vpcCidrBlocks}, nil
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("failed to locate VPC containing address space: %v", req.AddressSpace)
+}
+
+// GetCapabilities reports which resources, rule features and VPN modes the plugin supports
+func (s *AwsPluginServer) GetCapabilities(ctx context.Context, req *paragliderpb.GetCapabilitiesRequest) (*paragliderpb.GetCapabilitiesResponse, error) {
+	return &paragliderpb.GetCapabilitiesResponse{
+		ResourceTypes:  []paragliderpb.ResourceType{paragliderpb.ResourceType_INSTANCE},
+		AttachResource: true,
+		RuleFeatures:   &paragliderpb.RuleFeatures{PortRanges: true, Ipv6: true, Icmp: true},
+		VpnModes:       []paragliderpb.VpnMode{paragliderpb.VpnMode_BGP},
+		Limits:         &paragliderpb.CapabilityLimits{}, // Security group quotas count addresses rather than rules
+	}, nil
+}
+
+// CloudPlugin RPCs the plugin implements, as reported when registering with the controller
+var capabilities = []string{
+	"CreateResource",
+	"AttachResource",
+	"DeleteResource",
+	"DeleteNamespace",
+	"GetPermitList",
+	"AddPermitListRules",
+	"DeletePermitListRules",
+	"GetUsedAddressSpaces",
+	"GetUsedAsns",
+	"GetUsedBgpPeeringIpAddresses",
+	"CreateVpnGateway",
+	"CreateVpnConnections",
+	"GetNetworkAddressSpaces",
+	"GetCapabilities",
+}
+
+func Setup(port int, orchestratorServerAddr string, tlsConfig config.TLS) *AwsPluginServer {
+	return SetupWithAdvertiseAddress(port, orchestratorServerAddr, tlsConfig, "")
+}
+
+// SetupWithAdvertiseAddress starts the plugin server, which registers with the controller at the advertised address (localhost if empty)
+func SetupWithAdvertiseAddress(port int, orchestratorServerAddr string, tlsConfig config.TLS, advertiseAddress string) *AwsPluginServer {
+	creds, err := utils.LoadTLSCredentials(tlsConfig)
+	if err != nil {
+		slog.Error("Failed to setup TLS", "error", err)
+		return nil
+	}
+	listenAddress, registeredAddress := utils.GetPluginAddresses(port, advertiseAddress)
+	lis, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		slog.Error("Failed to listen", "error", err)
+	}
+	grpcServer := grpc.NewServer(grpc.Creds(creds), grpc.ChainUnaryInterceptor(utils.MetricsServerInterceptor, utils.LogFieldsServerInterceptor(utils.CloudLogKey, utils.AWS), utils.NoticesServerInterceptor), utils.TracingServerOption())
+	awsServer := &AwsPluginServer{}
+	awsServer.orchestratorServerAddr = orchestratorServerAddr
+	awsServer.orchestratorCredentials = creds
+	paragliderpb.RegisterCloudPluginServer(grpcServer, awsServer)
+	utils.RegisterHealthService(grpcServer)
+	slog.Info("Starting AWS plugin server", "port", port)
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			slog.Error("AWS plugin server stopped", "error", err)
+		}
+	}()
+	go utils.RegisterPlugin(context.Background(), orchestratorServerAddr, creds, &paragliderpb.RegisterPluginRequest{
+		Name:         utils.AWS,
+		Address:      registeredAddress,
+		Version:      version.Version(),
+		Capabilities: capabilities,
+	})
+	return awsServer
+}
+
+// getInstance returns the instance with the given ID if it belongs to the namespace.
+func getInstance(ctx context.Context, ec2Client *ec2.Client, namespace string, instanceId string) (*types.Instance, error) {
+	describeInstancesOutput, err := ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []string{instanceId},
+		Filters:     []types.Filter{{Name: aws.String("tag:Namespace"), Values: []string{namespace}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get instance: %w", err)
+	}
+	if len(describeInstancesOutput.Reservations) != 1 || len(describeInstancesOutput.Reservations[0].Instances) != 1 {
+		return nil, fmt.Errorf("instance %s not found in namespace %s", instanceId, namespace)
+	}
+	return &describeInstancesOutput.Reservations[0].Instances[0], nil
+}
+
+// setupInstanceSecurityGroup returns an EC2 client and the ID of the Paraglider security group of the instance referred to by resourceUri.
+func setupInstanceSecurityGroup(ctx context.Context, namespace string, resourceUri string, awsClients *awsClients) (*ec2.Client, string, error) {
+	region, instanceId, err := parseInstanceArn(resourceUri)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to parse resource URI: %w", err)
+	}
+
+	// Load config and setup clients
+	cfg, err := loadConfig(ctx, region)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to load config: %w", err)
+	}
+	ec2Client := awsClients.getOrCreateEc2Client(cfg)
+
+	instance, err := getInstance(ctx, ec2Client, namespace, instanceId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	securityGroupName := getSecurityGroupName(namespace, getNameTag(instance.Tags))
+	describeSecurityGroupsOutput, err := ec2Client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+		Filters: getDescribeFilter(namespace, securityGroupName),
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to get security groups: %w", err)
+	}
+	if len(describeSecurityGroupsOutput.SecurityGroups) != 1 {
+		return nil, "", fmt.Errorf("security group %s not found", securityGroupName)
+	}
+	return ec2Client, *describeSecurityGroupsOutput.SecurityGroups[0].GroupId, nil
+}
+
+// getSecurityGroupRules returns all rules of a security group.
+func getSecurityGroupRules(ctx context.Context, ec2Client *ec2.Client, securityGroupId string) ([]types.SecurityGroupRule, error) {
+	var securityGroupRules []types.SecurityGroupRule
+	paginator := ec2.NewDescribeSecurityGroupRulesPaginator(ec2Client, &ec2.DescribeSecurityGroupRulesInput{
+		Filters: []types.Filter{{Name: aws.String("group-id"), Values: []string{securityGroupId}}},
+	})
+	for paginator.HasMorePages() {
+		describeSecurityGroupRulesOutput, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get security group rules: %w", err)
+		}
+		securityGroupRules = append(securityGroupRules, describeSecurityGroupRulesOutput.SecurityGroupRules...)
+	}
+	return securityGroupRules, nil
+}
+
+// getSecurityGroupRulesByName groups Paraglider security group rules by the name of the permit list rule they belong to.
+func getSecurityGroupRulesByName(securityGroupRules []types.SecurityGroupRule) map[string][]types.SecurityGroupRule {
+	securityGroupRulesByName := map[string][]types.SecurityGroupRule{}
+	for _, securityGroupRule := range securityGroupRules {
+		if securityGroupRule.Description == nil {
+			continue
+		}
+		if ruleName, _, ok := parseRuleDescription(*securityGroupRule.Description); ok {
+			securityGroupRulesByName[ruleName] = append(securityGroupRulesByName[ruleName], securityGroupRule)
+		}
+	}
+	return securityGroupRulesByName
+}
+
+// revokeSecurityGroupRules deletes the given rules from a security group.
+func revokeSecurityGroupRules(ctx context.Context, ec2Client *ec2.Client, securityGroupId string, securityGroupRules []types.SecurityGroupRule) error {
+	var ingressRuleIds, egressRuleIds []string
+	for _, securityGroupRule := range securityGroupRules {
+		if securityGroupRule.IsEgress != nil && *securityGroupRule.IsEgress {
+			egressRuleIds = append(egressRuleIds, *securityGroupRule.SecurityGroupRuleId)
+		} else {
+			ingressRuleIds = append(ingressRuleIds, *securityGroupRule.SecurityGroupRuleId)
+		}
+	}
+	if len(ingressRuleIds) > 0 {
+		_, err := ec2Client.RevokeSecurityGroupIngress(ctx, &ec2.RevokeSecurityGroupIngressInput{
+			GroupId:              aws.String(securityGroupId),
+			SecurityGroupRuleIds: ingressRuleIds,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to delete inbound security group rules: %w", err)
+		}
+	}
+	if len(egressRuleIds) > 0 {
+		_, err := ec2Client.RevokeSecurityGroupEgress(ctx, &ec2.RevokeSecurityGroupEgressInput{
+			GroupId:              aws.String(securityGroupId),
+			SecurityGroupRuleIds: egressRuleIds,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to delete outbound security group rules: %w", err)
+		}
+	}
+	return nil
+}
+
+// splitSecurityGroupRules splits the existing security group rules of a permit list rule into the ones which allow the same
+// traffic as one of the IP ranges of its new IP permission (and can be kept) and the stale ones. The IP permission is
+// returned without the IP ranges covered by the kept rules.
+func splitSecurityGroupRules(securityGroupRules []types.SecurityGroupRule, isEgress bool, ipPermission *types.IpPermission) ([]types.SecurityGroupRule, []types.SecurityGroupRule, *types.IpPermission) {
+	remaining := &types.IpPermission{IpProtocol: ipPermission.IpProtocol, FromPort: ipPermission.FromPort, ToPort: ipPermission.ToPort}
+	keptCidrs := map[string]bool{}
+	var keptRules, staleRules []types.SecurityGroupRule
+	for _, securityGroupRule := range securityGroupRules {
+		cidr := aws.ToString(securityGroupRule.CidrIpv4) + aws.ToString(securityGroupRule.CidrIpv6)
+		if !keptCidrs[cidr] && securityGroupRuleMatches(securityGroupRule, isEgress, ipPermission, cidr) {
+			keptCidrs[cidr] = true
+			keptRules = append(keptRules, securityGroupRule)
+		} else {
+			staleRules = append(staleRules, securityGroupRule)
+		}
+	}
+	for _, ipRange := range ipPermission.IpRanges {
+		if !keptCidrs[aws.ToString(ipRange.CidrIp)] {
+			remaining.IpRanges = append(remaining.IpRanges, ipRange)
+		}
+	}
+	for _, ipv6Range := range ipPermission.Ipv6Ranges {
+		if !keptCidrs[aws.ToString(ipv6Range.CidrIpv6)] {
+			remaining.Ipv6Ranges = append(remaining.Ipv6Ranges, ipv6Range)
+		}
+	}
+	return keptRules, staleRules, remaining
+}
+
+// securityGroupRuleMatches returns true if a security group rule allows the same traffic as the IP range of an IP permission with the given CIDR.
+func securityGroupRuleMatches(securityGroupRule types.SecurityGroupRule, isEgress bool, ipPermission *types.IpPermission, cidr string) bool {
+	if aws.ToBool(securityGroupRule.IsEgress) != isEgress || getSecurityGroupRulePort(securityGroupRule.FromPort) != getSecurityGroupRulePort(ipPermission.FromPort) ||
+		getSecurityGroupRulePort(securityGroupRule.ToPort) != getSecurityGroupRulePort(ipPermission.ToPort) {
+		return false
+	}
+	if aws.ToString(securityGroupRule.IpProtocol) == allProtocols || aws.ToString(ipPermission.IpProtocol) == allProtocols {
+		if aws.ToString(securityGroupRule.IpProtocol) != aws.ToString(ipPermission.IpProtocol) {
+			return false
+		}
+	} else {
+		protocol, err := getProtocolNumber(aws.ToString(securityGroupRule.IpProtocol))
+		if err != nil {
+			return false
+		}
+		newProtocol, err := getProtocolNumber(aws.ToString(ipPermission.IpProtocol))
+		if err != nil || protocol != newProtocol {
+			return false
+		}
+	}
+	for _, ipRange := range ipPermission.IpRanges {
+		if aws.ToString(ipRange.CidrIp) == cidr {
+			return true
+		}
+	}
+	for _, ipv6Range := range ipPermission.Ipv6Ranges {
+		if aws.ToString(ipv6Range.CidrIpv6) == cidr {
+			return true
+		}
+	}
+	return false
+}
+
+// getSecurityGroupRulePort returns the port of a security group rule, which AWS reports as -1 for protocols without ports.
+func getSecurityGroupRulePort(port *int32) int32 {
+	if port == nil {
+		return -1
+	}
+	return *port
+}
+
+// updateSecurityGroupRuleDescriptions sets the description of the given rules of a security group.
+func updateSecurityGroupRuleDescriptions(ctx context.Context, ec2Client *ec2.Client, securityGroupId string, isEgress bool, securityGroupRules []types.SecurityGroupRule, description string) error {
+	var ruleDescriptions []types.SecurityGroupRuleDescription
+	for _, securityGroupRule := range securityGroupRules {
+		if aws.ToString(securityGroupRule.Description) != description {
+			ruleDescriptions = append(ruleDescriptions, types.SecurityGroupRuleDescription{SecurityGroupRuleId: securityGroupRule.SecurityGroupRuleId, Description: aws.String(description)})
+		}
+	}
+	if len(ruleDescriptions) == 0 {
+		return nil
+	}
+
+	var err error
+	if !isEgress {
+		_, err = ec2Client.UpdateSecurityGroupRuleDescriptionsIngress(ctx, &ec2.UpdateSecurityGroupRuleDescriptionsIngressInput{
+			GroupId:                       aws.String(securityGroupId),
+			SecurityGroupRuleDescriptions: ruleDescriptions,
+		})
+	} else {
+		_, err = ec2Client.UpdateSecurityGroupRuleDescriptionsEgress(ctx, &ec2.UpdateSecurityGroupRuleDescriptionsEgressInput{
+			GroupId:                       aws.String(securityGroupId),
+			SecurityGroupRuleDescriptions: ruleDescriptions,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("unable to update security group rule descriptions: %w", err)
+	}
+	return nil
+}
+
+// isPermitListRuleEqual returns true if two permit list rules are equivalent regardless of the order of their targets.
+func isPermitListRuleEqual(rule1 *paragliderpb.PermitListRule, rule2 *paragliderpb.PermitListRule) bool {
+	if rule1.Name != rule2.Name || rule1.Direction != rule2.Direction || rule1.Protocol != rule2.Protocol ||
+		!utils.PortRangesEqual(utils.DstPortRanges(rule1), utils.DstPortRanges(rule2)) || len(rule1.Targets) != len(rule2.Targets) || len(rule1.Tags) != len(rule2.Tags) {
+		return false
+	}
+	targets := map[string]bool{}
+	for _, target := range rule1.Targets {
+		targets[target] = true
+	}
+	for _, target := range rule2.Targets {
+		cidr, err := getTargetCidr(target)
+		if err != nil || !targets[cidr] {
+			return false
+		}
+	}
+	for i := range rule1.Tags {
+		if rule1.Tags[i] != rule2.Tags[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// getVpcCidrBlocks returns all IPv4 CIDR blocks associated with a VPC.
+func getVpcCidrBlocks(vpc *types.Vpc) []string {
+	cidrBlocks := []string{}
+	for _, cidrBlockAssociation := range vpc.CidrBlockAssociationSet {
+		if cidrBlockAssociation.CidrBlock != nil {
+			cidrBlocks = append(cidrBlocks, *cidrBlockAssociation.CidrBlock)
+		}
+	}
+	if len(cidrBlocks) == 0 && vpc.CidrBlock != nil {
+		cidrBlocks = append(cidrBlocks, *vpc.CidrBlock)
+	}
+	return cidrBlocks
+}
+
+// getVpcIpv6CidrBlock returns the IPv6 CIDR block of a VPC, waiting for it to be associated if needed.
+// An empty string is returned for VPCs without one (e.g., VPCs created before dual-stack support).
+func getVpcIpv6CidrBlock(ctx context.Context, ec2Client *ec2.Client, vpc *types.Vpc) (string, error) {
+	deadline := time.Now().Add(vpcIpv6CidrBlockAssociationTimeout)
+	for {
+		associating := false
+		for _, association := range vpc.Ipv6CidrBlockAssociationSet {
+			if association.Ipv6CidrBlockState == nil {
+				continue
+			}
+			switch association.Ipv6CidrBlockState.State {
+			case types.VpcCidrBlockStateCodeAssociated:
+				return aws.ToString(association.Ipv6CidrBlock), nil
+			case types.VpcCidrBlockStateCodeAssociating:
+				associating = true
+			}
+		}
+		if !associating {
+			return "", nil
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("timed out waiting for IPv6 CIDR block of VPC %s to be associated", *vpc.VpcId)
+		}
+		time.Sleep(vpcIpv6CidrBlockPollInterval)
+
+		describeVpcsOutput, err := ec2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{VpcIds: []string{*vpc.VpcId}})
+		if err != nil {
+			return "", fmt.Errorf("unable to get VPC: %w", err)
+		}
+		if len(describeVpcsOutput.Vpcs) != 1 {
+			return "", fmt.Errorf("unable to find VPC %s", *vpc.VpcId)
+		}
+		vpc = &describeVpcsOutput.Vpcs[0]
+	}
+}
+
+// getDefaultEgressIpPermission returns the IP permission of the default outbound rules of security groups.
+func getDefaultEgressIpPermission(ipv6 bool) types.IpPermission {
+	ipPermission := types.IpPermission{
+		IpProtocol: aws.String("-1"),
+		IpRanges:   []types.IpRange{{CidrIp: aws.String("0.0.0.0/0")}},
+	}
+	if ipv6 {
+		ipPermission.Ipv6Ranges = []types.Ipv6Range{{CidrIpv6: aws.String("::/0")}}
+	}
+	return ipPermission
+}
+
+// hasIpv6Address returns true if any network interface of an instance has an IPv6 address.
+func hasIpv6Address(instance *types.Instance) bool {
+	for _, networkInterface := range instance.NetworkInterfaces {
+		if len(networkInterface.Ipv6Addresses) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// isErrorCode returns true if err is an AWS API error with code.
+func isErrorCode(err error, code string) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == code
+}
+
+// withRegion overrides the region of a single EC2 API call.
+func withRegion(region string) func(*ec2.Options) {
+	return func(o *ec2.Options) {
+		o.Region = region
+	}
+}
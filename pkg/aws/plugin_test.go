@@ -19,20 +19,27 @@ limitations under the License.
 package aws
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	orchestrator "github.com/paraglider-project/paraglider/pkg/fake/orchestrator/rpc"
 	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
 	"github.com/paraglider-project/paraglider/pkg/utils"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestCreateResource(t *testing.T) {
 	testCases := []struct {
 		name            string
 		fakeServerState fakeServerState
+		requestsIpv6    bool
 		shouldError     bool
 	}{
 		{
@@ -59,6 +66,28 @@ func TestCreateResource(t *testing.T) {
 			},
 			shouldError: true,
 		},
+		{
+			name: "DualStackNetwork",
+			fakeServerState: fakeServerState{
+				vpc: fakeDualStackVpc,
+				subnet: &types.Subnet{
+					SubnetId:                    aws.String(fakeSubnetId),
+					AvailabilityZone:            aws.String(fakeAvailabilityZone1),
+					Ipv6CidrBlockAssociationSet: []types.SubnetIpv6CidrBlockAssociation{{Ipv6CidrBlock: aws.String(fakeSubnetIpv6CidrBlock)}},
+				},
+			},
+			requestsIpv6: true,
+			shouldError:  false,
+		},
+		{
+			name: "Ipv6WithoutDualStackNetwork",
+			fakeServerState: fakeServerState{
+				vpc:    fakeVpc,
+				subnet: fakeSubnet,
+			},
+			requestsIpv6: true,
+			shouldError:  true,
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -77,7 +106,11 @@ func TestCreateResource(t *testing.T) {
 			awsPluginServer := &AwsPluginServer{orchestratorServerAddr: fakeOrchestratorServerAddr}
 
 			// Create instance
-			testInstanceJson, err := getTestInstanceInputJson(fakeAvailabilityZone1)
+			testInstanceInput := getTestInstanceInput(fakeAvailabilityZone1)
+			if testCase.requestsIpv6 {
+				testInstanceInput.Ipv6AddressCount = aws.Int32(1)
+			}
+			testInstanceJson, err := json.Marshal(testInstanceInput)
 			if err != nil {
 				t.Fatalf("unable to get test instance JSON: %v", err)
 			}
@@ -98,3 +131,702 @@ func TestCreateResource(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateResourceIpv6Address(t *testing.T) {
+	ctx, fakeAwsClients, err := setupTest(fakeServerState{})
+	if err != nil {
+		t.Fatalf("unable to setup test: %v", err)
+	}
+	awsPluginServer := &AwsPluginServer{}
+
+	// Specific IPv6 addresses can't be requested
+	testInstanceInput := getTestInstanceInput(fakeAvailabilityZone1)
+	testInstanceInput.Ipv6Addresses = []types.InstanceIpv6Address{{Ipv6Address: aws.String("2600:1f16::1")}}
+	testInstanceJson, err := json.Marshal(testInstanceInput)
+	if err != nil {
+		t.Fatalf("unable to get test instance JSON: %v", err)
+	}
+	createResourceReq := &paragliderpb.CreateResourceRequest{
+		Deployment:  &paragliderpb.ParagliderDeployment{Namespace: fakeNamespace, Id: fakeAccountId},
+		Name:        fakeInstanceName,
+		Description: testInstanceJson,
+	}
+	_, err = awsPluginServer._CreateResource(ctx, createResourceReq, fakeAwsClients)
+	require.Error(t, err)
+}
+
+func TestGetVpcIpv6CidrBlock(t *testing.T) {
+	ctx, fakeAwsClients, err := setupTest(fakeServerState{vpc: fakeDualStackVpc})
+	if err != nil {
+		t.Fatalf("unable to setup test: %v", err)
+	}
+	cfg, err := loadConfig(ctx, fakeRegion)
+	if err != nil {
+		t.Fatalf("unable to load config: %v", err)
+	}
+	ec2Client := fakeAwsClients.getOrCreateEc2Client(cfg)
+
+	// VPC without an IPv6 CIDR block
+	ipv6CidrBlock, err := getVpcIpv6CidrBlock(ctx, ec2Client, fakeVpc)
+	require.NoError(t, err)
+	require.Empty(t, ipv6CidrBlock)
+
+	// VPC whose IPv6 CIDR block is still being associated
+	associatingVpc := &types.Vpc{
+		VpcId: aws.String(fakeVpcId),
+		Ipv6CidrBlockAssociationSet: []types.VpcIpv6CidrBlockAssociation{
+			{Ipv6CidrBlockState: &types.VpcCidrBlockState{State: types.VpcCidrBlockStateCodeAssociating}},
+		},
+	}
+	ipv6CidrBlock, err = getVpcIpv6CidrBlock(ctx, ec2Client, associatingVpc)
+	require.NoError(t, err)
+	require.Equal(t, fakeVpcIpv6CidrBlock, ipv6CidrBlock)
+}
+
+func TestDeleteResource(t *testing.T) {
+	testCases := []struct {
+		name        string
+		resourceUri string
+		shouldError bool
+	}{
+		{
+			name:        "Success",
+			resourceUri: getInstanceArn(fakeAccountId, fakeRegion, fakeInstanceId),
+			shouldError: false,
+		},
+		{
+			name:        "InvalidUri",
+			resourceUri: fakeInstanceId,
+			shouldError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// Setup test with an instance that has already terminated so the waiter returns immediately
+			ctx, fakeAwsClients, err := setupTest(fakeServerState{
+				instance: &types.Instance{
+					InstanceId: aws.String(fakeInstanceId),
+					Tags:       []types.Tag{{Key: aws.String("Name"), Value: aws.String(fakeInstanceName)}},
+					State:      &types.InstanceState{Name: types.InstanceStateNameTerminated},
+				},
+			})
+			if err != nil {
+				t.Fatalf("unable to setup test: %v", err)
+			}
+			awsPluginServer := &AwsPluginServer{}
+
+			deleteResourceReq := &paragliderpb.DeleteResourceRequest{Namespace: fakeNamespace, Resource: testCase.resourceUri}
+			deleteResourceResp, err := awsPluginServer._DeleteResource(ctx, deleteResourceReq, fakeAwsClients)
+			if testCase.shouldError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, deleteResourceResp)
+			}
+		})
+	}
+}
+
+func TestDeleteNamespace(t *testing.T) {
+	ctx, fakeAwsClients, err := setupTest(fakeServerState{
+		vpc:             fakeVpc,
+		subnet:          fakeSubnet,
+		transitGateway:  fakeTransitGateway,
+		customerGateway: &types.CustomerGateway{CustomerGatewayId: aws.String(fakeCustomerGatewayId)},
+		vpnConnection:   fakeVpnConnection,
+	})
+	if err != nil {
+		t.Fatalf("unable to setup test: %v", err)
+	}
+	awsPluginServer := &AwsPluginServer{}
+
+	req := &paragliderpb.DeleteNamespaceRequest{
+		Deployment: &paragliderpb.ParagliderDeployment{Id: fakeAccountId, Namespace: fakeNamespace},
+	}
+	resp, err := awsPluginServer._DeleteNamespace(ctx, req, fakeAwsClients)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+}
+
+// getFakeSecurityGroupRules returns the security group rules for a permit list rule as they'd be returned by AWS.
+func getFakeSecurityGroupRules(rule *paragliderpb.PermitListRule) []types.SecurityGroupRule {
+	ipPermission, err := permitListRuleToIpPermission(rule)
+	if err != nil {
+		panic(err)
+	}
+	securityGroupRules := []types.SecurityGroupRule{}
+	for i, ipRange := range ipPermission.IpRanges {
+		securityGroupRules = append(securityGroupRules, types.SecurityGroupRule{
+			SecurityGroupRuleId: aws.String(fmt.Sprintf("sgr-%s-%d", rule.Name, i)),
+			GroupId:             aws.String(fakeSecurityGroupId),
+			IsEgress:            aws.Bool(rule.Direction == paragliderpb.Direction_OUTBOUND),
+			IpProtocol:          ipPermission.IpProtocol,
+			FromPort:            ipPermission.FromPort,
+			ToPort:              ipPermission.ToPort,
+			CidrIpv4:            ipRange.CidrIp,
+			Description:         ipRange.Description,
+		})
+	}
+	return securityGroupRules
+}
+
+var (
+	fakePermitListRule1 = &paragliderpb.PermitListRule{
+		Name:      "rule1",
+		Direction: paragliderpb.Direction_INBOUND,
+		SrcPort:   -1,
+		DstPort:   443,
+		Protocol:  6,
+		Targets:   []string{"1.2.3.4/32", "5.6.7.0/24"},
+		Tags:      []string{"tag1", "tag2"},
+	}
+	fakePermitListRule2 = &paragliderpb.PermitListRule{
+		Name:      "rule2",
+		Direction: paragliderpb.Direction_OUTBOUND,
+		SrcPort:   -1,
+		DstPort:   -1,
+		Protocol:  1,
+		Targets:   []string{"8.8.8.8/32"},
+	}
+)
+
+func TestGetPermitList(t *testing.T) {
+	securityGroupRules := append(getFakeSecurityGroupRules(fakePermitListRule1), getFakeSecurityGroupRules(fakePermitListRule2)...)
+	// Rules not created by Paraglider are ignored
+	securityGroupRules = append(securityGroupRules, types.SecurityGroupRule{
+		SecurityGroupRuleId: aws.String("sgr-other"),
+		IsEgress:            aws.Bool(false),
+		IpProtocol:          aws.String("tcp"),
+		CidrIpv4:            aws.String("0.0.0.0/0"),
+		Description:         aws.String("SSH"),
+	})
+
+	ctx, fakeAwsClients, err := setupTest(fakeServerState{securityGroupRules: securityGroupRules})
+	if err != nil {
+		t.Fatalf("unable to setup test: %v", err)
+	}
+	awsPluginServer := &AwsPluginServer{}
+
+	req := &paragliderpb.GetPermitListRequest{Namespace: fakeNamespace, Resource: getInstanceArn(fakeAccountId, fakeRegion, fakeInstanceId)}
+	resp, err := awsPluginServer._GetPermitList(ctx, req, fakeAwsClients)
+	require.NoError(t, err)
+	require.Len(t, resp.Rules, 2)
+	require.Equal(t, fakePermitListRule1, resp.Rules[0])
+	require.Equal(t, fakePermitListRule2, resp.Rules[1])
+}
+
+func TestAddPermitListRules(t *testing.T) {
+	testCases := []struct {
+		name            string
+		fakeServerState fakeServerState
+		rules           []*paragliderpb.PermitListRule
+		shouldError     bool
+	}{
+		{
+			name:            "NewRules",
+			fakeServerState: fakeServerState{},
+			rules:           []*paragliderpb.PermitListRule{fakePermitListRule1, fakePermitListRule2},
+			shouldError:     false,
+		},
+		{
+			name:            "ExistingRule",
+			fakeServerState: fakeServerState{securityGroupRules: getFakeSecurityGroupRules(fakePermitListRule1)},
+			rules:           []*paragliderpb.PermitListRule{fakePermitListRule1},
+			shouldError:     false,
+		},
+		{
+			name:            "ModifiedRule",
+			fakeServerState: fakeServerState{securityGroupRules: getFakeSecurityGroupRules(fakePermitListRule1)},
+			rules: []*paragliderpb.PermitListRule{
+				{Name: "rule1", Direction: paragliderpb.Direction_INBOUND, SrcPort: -1, DstPort: 80, Protocol: 6, Targets: []string{"1.2.3.4"}},
+			},
+			shouldError: false,
+		},
+		{
+			name:            "PortWithoutTcpOrUdp",
+			fakeServerState: fakeServerState{},
+			rules: []*paragliderpb.PermitListRule{
+				{Name: "rule1", Direction: paragliderpb.Direction_INBOUND, SrcPort: -1, DstPort: 80, Protocol: 1, Targets: []string{"1.2.3.4"}},
+			},
+			shouldError: true,
+		},
+		{
+			name:            "DenyRule",
+			fakeServerState: fakeServerState{},
+			rules: []*paragliderpb.PermitListRule{
+				{Name: "rule1", Direction: paragliderpb.Direction_INBOUND, SrcPort: -1, DstPort: 80, Protocol: 6, Targets: []string{"1.2.3.4"}, Action: paragliderpb.RuleAction_DENY},
+			},
+			shouldError: true,
+		},
+		{
+			name:            "SrcPort",
+			fakeServerState: fakeServerState{},
+			rules: []*paragliderpb.PermitListRule{
+				{Name: "rule1", Direction: paragliderpb.Direction_INBOUND, SrcPort: 8080, DstPort: 80, Protocol: 6, Targets: []string{"1.2.3.4"}},
+			},
+			shouldError: true,
+		},
+		{
+			name:            "PortRange",
+			fakeServerState: fakeServerState{},
+			rules: []*paragliderpb.PermitListRule{
+				{Name: "rule1", Direction: paragliderpb.Direction_INBOUND, SrcPort: -1, DstPorts: []*paragliderpb.PortRange{{Start: 8000, End: 8100}}, Protocol: 6, Targets: []string{"1.2.3.4"}},
+			},
+			shouldError: false,
+		},
+		{
+			name:            "SeveralPortRanges",
+			fakeServerState: fakeServerState{},
+			rules: []*paragliderpb.PermitListRule{
+				{Name: "rule1", Direction: paragliderpb.Direction_INBOUND, SrcPort: -1, DstPorts: []*paragliderpb.PortRange{{Start: 80, End: 80}, {Start: 8000, End: 8100}}, Protocol: 6, Targets: []string{"1.2.3.4"}},
+			},
+			shouldError: true,
+		},
+		{
+			name:            "NameWithSeparator",
+			fakeServerState: fakeServerState{},
+			rules: []*paragliderpb.PermitListRule{
+				{Name: "rule:1", Direction: paragliderpb.Direction_INBOUND, SrcPort: -1, DstPort: 80, Protocol: 6, Targets: []string{"1.2.3.4"}},
+			},
+			shouldError: true,
+		},
+		{
+			name:            "TagWithSpace",
+			fakeServerState: fakeServerState{},
+			rules: []*paragliderpb.PermitListRule{
+				{Name: "rule1", Direction: paragliderpb.Direction_INBOUND, SrcPort: -1, DstPort: 80, Protocol: 6, Targets: []string{"1.2.3.4"}, Tags: []string{"tag 1"}},
+			},
+			shouldError: true,
+		},
+		{
+			name:            "InvalidDescriptionCharacters",
+			fakeServerState: fakeServerState{},
+			rules: []*paragliderpb.PermitListRule{
+				{Name: "rule\"1", Direction: paragliderpb.Direction_INBOUND, SrcPort: -1, DstPort: 80, Protocol: 6, Targets: []string{"1.2.3.4"}},
+			},
+			shouldError: true,
+		},
+		{
+			name:            "DescriptionTooLong",
+			fakeServerState: fakeServerState{},
+			rules: []*paragliderpb.PermitListRule{
+				{Name: strings.Repeat("a", maxRuleDescriptionLength), Direction: paragliderpb.Direction_INBOUND, SrcPort: -1, DstPort: 80, Protocol: 6, Targets: []string{"1.2.3.4"}},
+			},
+			shouldError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx, fakeAwsClients, err := setupTest(testCase.fakeServerState)
+			if err != nil {
+				t.Fatalf("unable to setup test: %v", err)
+			}
+			_, fakeOrchestratorServerAddr, err := orchestrator.SetupFakeOrchestratorRPCServer(utils.AWS)
+			if err != nil {
+				t.Fatalf("unable to setup fake orchestrator: %v", err)
+			}
+			awsPluginServer := &AwsPluginServer{orchestratorServerAddr: fakeOrchestratorServerAddr}
+
+			req := &paragliderpb.AddPermitListRulesRequest{
+				Namespace: fakeNamespace,
+				Resource:  getInstanceArn(fakeAccountId, fakeRegion, fakeInstanceId),
+				Rules:     testCase.rules,
+			}
+			resp, err := awsPluginServer._AddPermitListRules(ctx, req, fakeAwsClients)
+			if testCase.shouldError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, resp)
+			}
+		})
+	}
+}
+
+func TestPermitListRuleToIpPermissionPorts(t *testing.T) {
+	// Port ranges map to the range of the security group rule and are read back as such
+	rule := &paragliderpb.PermitListRule{Name: "rule1", Direction: paragliderpb.Direction_INBOUND, SrcPort: -1, DstPorts: []*paragliderpb.PortRange{{Start: 8000, End: 8100}}, Protocol: 6, Targets: []string{"1.2.3.4/32"}}
+	ipPermission, err := permitListRuleToIpPermission(rule)
+	require.NoError(t, err)
+	require.Equal(t, int32(8000), *ipPermission.FromPort)
+	require.Equal(t, int32(8100), *ipPermission.ToPort)
+	permitListRules, err := securityGroupRulesToPermitListRules(getFakeSecurityGroupRules(rule))
+	require.NoError(t, err)
+	require.Len(t, permitListRules, 1)
+	require.True(t, isPermitListRuleEqual(permitListRules[0], rule))
+
+	// Rules with ports security groups can't express are rejected rather than applied without them
+	_, err = permitListRuleToIpPermission(&paragliderpb.PermitListRule{Name: "rule1", SrcPort: -1, DstPorts: []*paragliderpb.PortRange{{Start: 80, End: 80}, {Start: 8000, End: 8100}}, Protocol: 6})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = permitListRuleToIpPermission(&paragliderpb.PermitListRule{Name: "rule1", SrcPort: -1, SrcPorts: []*paragliderpb.PortRange{{Start: 8000, End: 8100}}, DstPort: 80, Protocol: 6})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestAddPermitListRulesReplacesRules(t *testing.T) {
+	requests := []any{}
+	ctx, fakeAwsClients, err := setupTest(fakeServerState{securityGroupRules: getFakeSecurityGroupRules(fakePermitListRule1), requests: &requests})
+	if err != nil {
+		t.Fatalf("unable to setup test: %v", err)
+	}
+	_, fakeOrchestratorServerAddr, err := orchestrator.SetupFakeOrchestratorRPCServer(utils.AWS)
+	if err != nil {
+		t.Fatalf("unable to setup fake orchestrator: %v", err)
+	}
+	awsPluginServer := &AwsPluginServer{orchestratorServerAddr: fakeOrchestratorServerAddr}
+
+	// Keeps 1.2.3.4/32, replaces 5.6.7.0/24 with 9.9.9.9/32 and drops the tags
+	rule := &paragliderpb.PermitListRule{Name: "rule1", Direction: paragliderpb.Direction_INBOUND, SrcPort: -1, DstPort: 443, Protocol: 6, Targets: []string{"1.2.3.4/32", "9.9.9.9"}}
+	req := &paragliderpb.AddPermitListRulesRequest{
+		Namespace: fakeNamespace,
+		Resource:  getInstanceArn(fakeAccountId, fakeRegion, fakeInstanceId),
+		Rules:     []*paragliderpb.PermitListRule{rule},
+	}
+	_, err = awsPluginServer._AddPermitListRules(ctx, req, fakeAwsClients)
+	require.NoError(t, err)
+
+	var securityGroupRuleRequests []any
+	for _, request := range requests {
+		switch request.(type) {
+		case *ec2.AuthorizeSecurityGroupIngressInput, *ec2.UpdateSecurityGroupRuleDescriptionsIngressInput, *ec2.RevokeSecurityGroupIngressInput:
+			securityGroupRuleRequests = append(securityGroupRuleRequests, request)
+		}
+	}
+	require.Len(t, securityGroupRuleRequests, 3)
+
+	// The new rule is created before the stale one is revoked
+	authorizeInput, ok := securityGroupRuleRequests[0].(*ec2.AuthorizeSecurityGroupIngressInput)
+	require.True(t, ok)
+	require.Len(t, authorizeInput.IpPermissions, 1)
+	require.Len(t, authorizeInput.IpPermissions[0].IpRanges, 1)
+	require.Equal(t, "9.9.9.9/32", *authorizeInput.IpPermissions[0].IpRanges[0].CidrIp)
+
+	updateInput, ok := securityGroupRuleRequests[1].(*ec2.UpdateSecurityGroupRuleDescriptionsIngressInput)
+	require.True(t, ok)
+	require.Len(t, updateInput.SecurityGroupRuleDescriptions, 1)
+	require.Equal(t, "sgr-rule1-0", *updateInput.SecurityGroupRuleDescriptions[0].SecurityGroupRuleId)
+	require.Equal(t, "paraglider rule:rule1", *updateInput.SecurityGroupRuleDescriptions[0].Description)
+
+	revokeInput, ok := securityGroupRuleRequests[2].(*ec2.RevokeSecurityGroupIngressInput)
+	require.True(t, ok)
+	require.Equal(t, []string{"sgr-rule1-1"}, revokeInput.SecurityGroupRuleIds)
+}
+
+func TestDeletePermitListRules(t *testing.T) {
+	testCases := []struct {
+		name        string
+		ruleNames   []string
+		shouldError bool
+	}{
+		{
+			name:        "Success",
+			ruleNames:   []string{fakePermitListRule1.Name, fakePermitListRule2.Name},
+			shouldError: false,
+		},
+		{
+			name:        "RuleNotFound",
+			ruleNames:   []string{"rule3"},
+			shouldError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			securityGroupRules := append(getFakeSecurityGroupRules(fakePermitListRule1), getFakeSecurityGroupRules(fakePermitListRule2)...)
+			ctx, fakeAwsClients, err := setupTest(fakeServerState{securityGroupRules: securityGroupRules})
+			if err != nil {
+				t.Fatalf("unable to setup test: %v", err)
+			}
+			awsPluginServer := &AwsPluginServer{}
+
+			req := &paragliderpb.DeletePermitListRulesRequest{
+				Namespace: fakeNamespace,
+				Resource:  getInstanceArn(fakeAccountId, fakeRegion, fakeInstanceId),
+				RuleNames: testCase.ruleNames,
+			}
+			resp, err := awsPluginServer._DeletePermitListRules(ctx, req, fakeAwsClients)
+			if testCase.shouldError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, resp)
+			}
+		})
+	}
+}
+
+func TestGetUsedAddressSpaces(t *testing.T) {
+	ctx, fakeAwsClients, err := setupTest(fakeServerState{vpc: fakeVpc})
+	if err != nil {
+		t.Fatalf("unable to setup test: %v", err)
+	}
+	awsPluginServer := &AwsPluginServer{}
+
+	req := &paragliderpb.GetUsedAddressSpacesRequest{
+		Deployments: []*paragliderpb.ParagliderDeployment{{Id: fakeAccountId, Namespace: fakeNamespace}},
+	}
+	resp, err := awsPluginServer._GetUsedAddressSpaces(ctx, req, fakeAwsClients)
+	require.NoError(t, err)
+	require.Len(t, resp.AddressSpaceMappings, 1)
+	require.Equal(t, utils.AWS, resp.AddressSpaceMappings[0].Cloud)
+	require.Equal(t, fakeNamespace, resp.AddressSpaceMappings[0].Namespace)
+	require.ElementsMatch(t, []string{fakeVpcCidrBlock}, resp.AddressSpaceMappings[0].AddressSpaces)
+}
+
+func TestGetUsedAsns(t *testing.T) {
+	ctx, fakeAwsClients, err := setupTest(fakeServerState{
+		transitGateway: &types.TransitGateway{Options: &types.TransitGatewayOptions{AmazonSideAsn: aws.Int64(fakeTransitGatewayAsn)}},
+	})
+	if err != nil {
+		t.Fatalf("unable to setup test: %v", err)
+	}
+	awsPluginServer := &AwsPluginServer{}
+
+	req := &paragliderpb.GetUsedAsnsRequest{
+		Deployments: []*paragliderpb.ParagliderDeployment{{Id: fakeAccountId, Namespace: fakeNamespace}},
+	}
+	resp, err := awsPluginServer._GetUsedAsns(ctx, req, fakeAwsClients)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uint32{fakeTransitGatewayAsn}, resp.Asns)
+}
+
+func TestAttachResource(t *testing.T) {
+	testCases := []struct {
+		name              string
+		usedAddressSpaces int
+		expectedName      string
+		instanceTags      []types.Tag
+		shouldError       bool
+	}{
+		{
+			name:         "Success",
+			instanceTags: []types.Tag{{Key: aws.String("Name"), Value: aws.String(fakeInstanceName)}},
+			expectedName: fakeInstanceName,
+			shouldError:  false,
+		},
+		{
+			name:         "UnnamedInstance",
+			expectedName: fakeInstanceId,
+			shouldError:  false,
+		},
+		{
+			name:              "OverlappingAddressSpace",
+			usedAddressSpaces: 1,
+			shouldError:       true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx, fakeAwsClients, err := setupTest(fakeServerState{
+				vpc: fakeVpc,
+				instance: &types.Instance{
+					InstanceId:       aws.String(fakeInstanceId),
+					PrivateIpAddress: aws.String(fakeInstancePrivateIpAddress),
+					VpcId:            aws.String(fakeVpcId),
+					Tags:             testCase.instanceTags,
+					SecurityGroups:   []types.GroupIdentifier{{GroupId: aws.String("sg-existing")}},
+				},
+			})
+			if err != nil {
+				t.Fatalf("unable to setup test: %v", err)
+			}
+			fakeOrchestratorServer, fakeOrchestratorServerAddr, err := orchestrator.SetupFakeOrchestratorRPCServer(utils.AWS)
+			if err != nil {
+				t.Fatalf("unable to setup fake orchestrator: %v", err)
+			}
+			fakeOrchestratorServer.Counter = testCase.usedAddressSpaces
+			awsPluginServer := &AwsPluginServer{orchestratorServerAddr: fakeOrchestratorServerAddr}
+
+			resourceUri := getInstanceArn(fakeAccountId, fakeRegion, fakeInstanceId)
+			req := &paragliderpb.AttachResourceRequest{Namespace: fakeNamespace, Resource: resourceUri}
+			resp, err := awsPluginServer._AttachResource(ctx, req, fakeAwsClients)
+			if testCase.shouldError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, testCase.expectedName, resp.Name)
+				require.Equal(t, resourceUri, resp.Uri)
+				require.Equal(t, fakeInstancePrivateIpAddress, resp.Ip)
+			}
+		})
+	}
+}
+
+func TestGetUsedBgpPeeringIpAddresses(t *testing.T) {
+	ctx, fakeAwsClients, err := setupTest(fakeServerState{vpnConnection: fakeVpnConnection})
+	if err != nil {
+		t.Fatalf("unable to setup test: %v", err)
+	}
+	awsPluginServer := &AwsPluginServer{}
+
+	req := &paragliderpb.GetUsedBgpPeeringIpAddressesRequest{
+		Deployments: []*paragliderpb.ParagliderDeployment{{Id: fakeAccountId, Namespace: fakeNamespace}},
+	}
+	resp, err := awsPluginServer._GetUsedBgpPeeringIpAddresses(ctx, req, fakeAwsClients)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"169.254.21.1", "169.254.100.1"}, resp.IpAddresses)
+}
+
+func TestCreateVpnGateway(t *testing.T) {
+	testCases := []struct {
+		name            string
+		fakeServerState fakeServerState
+	}{
+		{
+			name:            "FromScratch",
+			fakeServerState: fakeServerState{vpc: fakeVpc, subnet: fakeSubnet},
+		},
+		{
+			name:            "ExistingTransitGateway",
+			fakeServerState: fakeServerState{vpc: fakeVpc, subnet: fakeSubnet, transitGateway: fakeTransitGateway},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx, fakeAwsClients, err := setupTest(testCase.fakeServerState)
+			if err != nil {
+				t.Fatalf("unable to setup test: %v", err)
+			}
+			_, fakeOrchestratorServerAddr, err := orchestrator.SetupFakeOrchestratorRPCServer(utils.AWS)
+			if err != nil {
+				t.Fatalf("unable to setup fake orchestrator: %v", err)
+			}
+			awsPluginServer := &AwsPluginServer{orchestratorServerAddr: fakeOrchestratorServerAddr}
+
+			req := &paragliderpb.CreateVpnGatewayRequest{
+				Deployment:            &paragliderpb.ParagliderDeployment{Id: fakeAccountId, Namespace: fakeNamespace},
+				Cloud:                 utils.GCP,
+				BgpPeeringIpAddresses: []string{"169.254.21.1", "169.254.22.1"},
+			}
+			resp, err := awsPluginServer._CreateVpnGateway(ctx, req, fakeAwsClients)
+			require.NoError(t, err)
+			require.Equal(t, uint32(fakeTransitGatewayAsn), resp.Asn)
+			require.Empty(t, resp.GatewayIpAddresses)
+		})
+	}
+}
+
+func TestCreateVpnConnections(t *testing.T) {
+	testCases := []struct {
+		name            string
+		fakeServerState fakeServerState
+		bgpIpAddresses  []string
+		isBgpDisabled   bool
+		shouldError     bool
+	}{
+		{
+			name:            "FromScratch",
+			fakeServerState: fakeServerState{vpc: fakeVpc, transitGateway: fakeTransitGateway},
+			bgpIpAddresses:  []string{"169.254.21.2", "169.254.21.6"},
+		},
+		{
+			name: "ExistingConnections",
+			fakeServerState: fakeServerState{
+				vpc:             fakeVpc,
+				transitGateway:  fakeTransitGateway,
+				customerGateway: &types.CustomerGateway{CustomerGatewayId: aws.String(fakeCustomerGatewayId)},
+				vpnConnection:   fakeVpnConnection,
+			},
+			bgpIpAddresses: []string{"169.254.21.2", "169.254.21.6"},
+		},
+		{
+			name:            "InvalidBgpIpAddress",
+			fakeServerState: fakeServerState{vpc: fakeVpc, transitGateway: fakeTransitGateway},
+			bgpIpAddresses:  []string{"169.254.21.1", "169.254.21.5"},
+			shouldError:     true,
+		},
+		{
+			name:            "MissingTransitGateway",
+			fakeServerState: fakeServerState{vpc: fakeVpc},
+			bgpIpAddresses:  []string{"169.254.21.2", "169.254.21.6"},
+			shouldError:     true,
+		},
+		{
+			name:            "BgpDisabled",
+			fakeServerState: fakeServerState{vpc: fakeVpc, transitGateway: fakeTransitGateway},
+			bgpIpAddresses:  []string{"169.254.21.2", "169.254.21.6"},
+			isBgpDisabled:   true,
+			shouldError:     true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx, fakeAwsClients, err := setupTest(testCase.fakeServerState)
+			if err != nil {
+				t.Fatalf("unable to setup test: %v", err)
+			}
+			_, fakeOrchestratorServerAddr, err := orchestrator.SetupFakeOrchestratorRPCServer(utils.GCP)
+			if err != nil {
+				t.Fatalf("unable to setup fake orchestrator: %v", err)
+			}
+			awsPluginServer := &AwsPluginServer{orchestratorServerAddr: fakeOrchestratorServerAddr}
+
+			req := &paragliderpb.CreateVpnConnectionsRequest{
+				Deployment:         &paragliderpb.ParagliderDeployment{Id: fakeAccountId, Namespace: fakeNamespace},
+				Cloud:              utils.GCP,
+				Asn:                65000,
+				GatewayIpAddresses: []string{"3.3.3.3", "4.4.4.4"},
+				BgpIpAddresses:     testCase.bgpIpAddresses,
+				SharedKey:          "abc",
+				IsBgpDisabled:      testCase.isBgpDisabled,
+			}
+			resp, err := awsPluginServer._CreateVpnConnections(ctx, req, fakeAwsClients)
+			if testCase.shouldError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, []string{fakeTunnelOutsideIpAddress, fakeTunnelOutsideIpAddress}, resp.GatewayIpAddresses)
+			}
+		})
+	}
+}
+
+func TestGetNetworkAddressSpaces(t *testing.T) {
+	testCases := []struct {
+		name         string
+		addressSpace string
+		shouldError  bool
+	}{
+		{
+			name:         "Ip",
+			addressSpace: fakeInstancePrivateIpAddress,
+			shouldError:  false,
+		},
+		{
+			name:         "Cidr",
+			addressSpace: "10.0.1.0/24",
+			shouldError:  false,
+		},
+		{
+			name:         "NotFound",
+			addressSpace: "10.1.0.1",
+			shouldError:  true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx, fakeAwsClients, err := setupTest(fakeServerState{vpc: fakeVpc})
+			if err != nil {
+				t.Fatalf("unable to setup test: %v", err)
+			}
+			awsPluginServer := &AwsPluginServer{}
+
+			req := &paragliderpb.GetNetworkAddressSpacesRequest{
+				Deployment:   &paragliderpb.ParagliderDeployment{Id: fakeAccountId, Namespace: fakeNamespace},
+				AddressSpace: testCase.addressSpace,
+			}
+			resp, err := awsPluginServer._GetNetworkAddressSpaces(ctx, req, fakeAwsClients)
+			if testCase.shouldError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, []string{fakeVpcCidrBlock}, resp.AddressSpaces)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	securityGroupRuleDescriptionPrefix = "paraglider rule" // Prefix of the description of every Paraglider security group rule
+	allProtocols                       = "-1"              // AWS protocol value matching all protocols
+	minPort                            = 0
+	maxPort                            = 65535
+	maxRuleDescriptionLength           = 255 // Maximum length of AWS security group rule descriptions
+)
+
+// Characters AWS allows in security group rule descriptions
+var ruleDescriptionRegexp = regexp.MustCompile(`^[a-zA-Z0-9. _\-:/()#,@\[\]+=&;{}!$*]*$`)
+
+// Maps protocol names that can appear in AWS security group rules to IANA numbers
+var awsProtocolNumberMap = map[string]int32{
+	"tcp":    6,
+	"udp":    17,
+	"icmp":   1,
+	"icmpv6": 58,
+}
+
+// getRuleDescription formats the description of a security group rule to keep the name and tags of the permit list rule it belongs to.
+// AWS security group rules have no name, so every rule created for a permit list rule carries the same description.
+// Names and tags which could not be parsed back from the description or which AWS would reject are refused.
+func getRuleDescription(ruleName string, tags []string) (string, error) {
+	if strings.Contains(ruleName, ":") {
+		return "", status.Errorf(codes.InvalidArgument, "rule %s: names of AWS rules can't contain \":\"", ruleName)
+	}
+	for _, tag := range tags {
+		if strings.Contains(tag, " ") {
+			return "", status.Errorf(codes.InvalidArgument, "rule %s: tags of AWS rules can't contain spaces (tag %q)", ruleName, tag)
+		}
+	}
+
+	description := fmt.Sprintf("%s:%s", securityGroupRuleDescriptionPrefix, ruleName)
+	if len(tags) != 0 {
+		description = fmt.Sprintf("%s:%v", description, tags)
+	}
+	if len(description) > maxRuleDescriptionLength {
+		return "", status.Errorf(codes.InvalidArgument, "rule %s: name and tags are too long to fit in an AWS security group rule description (%d characters)", ruleName, maxRuleDescriptionLength)
+	}
+	if !ruleDescriptionRegexp.MatchString(description) {
+		return "", status.Errorf(codes.InvalidArgument, "rule %s: names and tags of AWS rules can only contain letters, numbers, spaces and ._-:/()#,@[]+=&;{}!$*", ruleName)
+	}
+	return description, nil
+}
+
+// parseRuleDescription returns the permit list rule name and tags stored in a security group rule description.
+// The last return value is false if the description does not belong to a Paraglider rule.
+func parseRuleDescription(description string) (string, []string, bool) {
+	trimmedDescription, found := strings.CutPrefix(description, securityGroupRuleDescriptionPrefix+":")
+	if !found {
+		return "", nil, false
+	}
+	ruleName, tagsStr, hasTags := strings.Cut(trimmedDescription, ":")
+	var tags []string
+	if hasTags {
+		tagsStr = strings.TrimPrefix(tagsStr, "[")
+		tagsStr = strings.TrimSuffix(tagsStr, "]")
+		tags = strings.Split(tagsStr, " ")
+	}
+	return ruleName, tags, true
+}
+
+// getProtocolNumber returns the protocol number from an AWS protocol (either a name like "tcp" or an int-string like "6")
+func getProtocolNumber(ipProtocol string) (int32, error) {
+	protocolNumber, ok := awsProtocolNumberMap[ipProtocol]
+	if !ok {
+		parsedProtocolNumber, err := strconv.Atoi(ipProtocol)
+		if err != nil {
+			return 0, fmt.Errorf("could not convert AWS protocol %s to protocol number", ipProtocol)
+		}
+		protocolNumber = int32(parsedProtocolNumber)
+	}
+	return protocolNumber, nil
+}
+
+// getTargetCidr returns the target as a CIDR since AWS security group rules don't accept plain IP addresses
+func getTargetCidr(target string) (string, error) {
+	if strings.Contains(target, "/") {
+		return target, nil
+	}
+	addr, err := netip.ParseAddr(target)
+	if err != nil {
+		return "", fmt.Errorf("invalid target %s: %w", target, err)
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()).String(), nil
+}
+
+// permitListRuleToIpPermission converts a permit list rule to an AWS IP permission with one IP range per target
+func permitListRuleToIpPermission(rule *paragliderpb.PermitListRule) (*types.IpPermission, error) {
+	if len(utils.SrcPortRanges(rule)) != 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "rule %s: source ports can't be specified since AWS security groups don't support rules based on source ports", rule.Name)
+	}
+	dstPorts := utils.DstPortRanges(rule)
+	if len(dstPorts) > 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "rule %s has several port ranges, but AWS security group rules only support one", rule.Name)
+	}
+	description, err := getRuleDescription(rule.Name, rule.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	ipPermission := &types.IpPermission{IpProtocol: aws.String(strconv.Itoa(int(rule.Protocol)))}
+
+	// Ports can only be specified for TCP and UDP. Other protocols either don't use ports or use the fields for other purposes (e.g., ICMP type and code).
+	if rule.Protocol == awsProtocolNumberMap["tcp"] || rule.Protocol == awsProtocolNumberMap["udp"] {
+		if len(dstPorts) == 0 {
+			ipPermission.FromPort = aws.Int32(minPort)
+			ipPermission.ToPort = aws.Int32(maxPort)
+		} else {
+			ipPermission.FromPort = aws.Int32(dstPorts[0].Start)
+			ipPermission.ToPort = aws.Int32(dstPorts[0].End)
+		}
+	} else if len(dstPorts) != 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "rule %s: destination ports can only be specified for TCP and UDP", rule.Name)
+	}
+
+	for _, target := range rule.Targets {
+		cidr, err := getTargetCidr(target)
+		if err != nil {
+			return nil, err
+		}
+		if strings.Contains(cidr, ":") {
+			ipPermission.Ipv6Ranges = append(ipPermission.Ipv6Ranges, types.Ipv6Range{CidrIpv6: aws.String(cidr), Description: aws.String(description)})
+		} else {
+			ipPermission.IpRanges = append(ipPermission.IpRanges, types.IpRange{CidrIp: aws.String(cidr), Description: aws.String(description)})
+		}
+	}
+	return ipPermission, nil
+}
+
+// securityGroupRulesToPermitListRules converts Paraglider security group rules to permit list rules.
+// Security group rules sharing the same description are merged into a single permit list rule with multiple targets.
+func securityGroupRulesToPermitListRules(securityGroupRules []types.SecurityGroupRule) ([]*paragliderpb.PermitListRule, error) {
+	permitListRules := []*paragliderpb.PermitListRule{}
+	permitListRulesByName := map[string]*paragliderpb.PermitListRule{}
+	for _, securityGroupRule := range securityGroupRules {
+		if securityGroupRule.Description == nil {
+			continue
+		}
+		ruleName, tags, ok := parseRuleDescription(*securityGroupRule.Description)
+		if !ok {
+			continue
+		}
+
+		var target string
+		if securityGroupRule.CidrIpv4 != nil {
+			target = *securityGroupRule.CidrIpv4
+		} else if securityGroupRule.CidrIpv6 != nil {
+			target = *securityGroupRule.CidrIpv6
+		} else {
+			continue
+		}
+
+		if permitListRule, ok := permitListRulesByName[ruleName]; ok {
+			permitListRule.Targets = append(permitListRule.Targets, target)
+			continue
+		}
+
+		protocol := int32(-1)
+		if *securityGroupRule.IpProtocol != allProtocols {
+			var err error
+			protocol, err = getProtocolNumber(*securityGroupRule.IpProtocol)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		var dstPorts []*paragliderpb.PortRange
+		if (protocol == awsProtocolNumberMap["tcp"] || protocol == awsProtocolNumberMap["udp"]) &&
+			securityGroupRule.FromPort != nil && securityGroupRule.ToPort != nil {
+			dstPorts = []*paragliderpb.PortRange{{Start: *securityGroupRule.FromPort, End: *securityGroupRule.ToPort}}
+		}
+
+		direction := paragliderpb.Direction_INBOUND
+		if securityGroupRule.IsEgress != nil && *securityGroupRule.IsEgress {
+			direction = paragliderpb.Direction_OUTBOUND
+		}
+
+		permitListRule := &paragliderpb.PermitListRule{
+			Name:      ruleName,
+			Targets:   []string{target},
+			Direction: direction,
+			SrcPort:   -1,
+			Protocol:  protocol,
+			Tags:      tags,
+		} // SrcPort not specified since AWS doesn't support rules based on source ports
+		utils.SetDstPortRanges(permitListRule, dstPorts)
+		permitListRulesByName[ruleName] = permitListRule
+		permitListRules = append(permitListRules, permitListRule)
+	}
+	return permitListRules, nil
+}
@@ -44,6 +44,15 @@ const (
 	fakeSubnetId                 = "fake-subnet-id"
 	fakeVpcId                    = "fake-vpc-id"
 	fakeVpcCidrBlock             = "10.0.0.0/16"
+	fakeVpcIpv6CidrBlock         = "2600:1f16:0:aa00::/56"
+	fakeSubnetIpv6CidrBlock      = "2600:1f16:0:aa00::/64"
+	fakeTransitGatewayId         = "fake-tgw-id"
+	fakeTransitGatewayAsn        = 64512
+	fakeCustomerGatewayId        = "fake-cgw-id"
+	fakeVpnConnectionId          = "fake-vpn-id"
+	fakeTunnelInsideCidr         = "169.254.21.0/30"
+	fakeTunnelOutsideIpAddress   = "1.1.1.1"
+	fakeRouteTableId             = "fake-rtb-id"
 )
 
 // Fake AWS parameters for testing
@@ -56,18 +65,50 @@ var (
 		VpcId:     aws.String(fakeVpcId),
 		CidrBlock: aws.String(fakeVpcCidrBlock),
 	}
+	fakeDualStackVpc = &types.Vpc{
+		VpcId:     aws.String(fakeVpcId),
+		CidrBlock: aws.String(fakeVpcCidrBlock),
+		Ipv6CidrBlockAssociationSet: []types.VpcIpv6CidrBlockAssociation{
+			{
+				Ipv6CidrBlock:      aws.String(fakeVpcIpv6CidrBlock),
+				Ipv6CidrBlockState: &types.VpcCidrBlockState{State: types.VpcCidrBlockStateCodeAssociated},
+			},
+		},
+	}
 	fakeInstance = &types.Instance{
 		InstanceId:       aws.String(fakeInstanceId),
 		PrivateIpAddress: aws.String(fakeInstancePrivateIpAddress),
 		Tags:             []types.Tag{{Key: aws.String("Name"), Value: aws.String(fakeInstanceName)}},
 		State:            &types.InstanceState{Name: types.InstanceStateNameRunning},
 	} // NOTE: this fakeInstance is only intended to be used as part of fakeServerState.
+	fakeTransitGateway = &types.TransitGateway{
+		TransitGatewayId: aws.String(fakeTransitGatewayId),
+		State:            types.TransitGatewayStateAvailable,
+		Options:          &types.TransitGatewayOptions{AmazonSideAsn: aws.Int64(fakeTransitGatewayAsn)},
+	}
+	fakeVpnConnection = &types.VpnConnection{
+		VpnConnectionId: aws.String(fakeVpnConnectionId),
+		State:           types.VpnStateAvailable,
+		Tags:            []types.Tag{{Key: aws.String(tunnelInsideCidrTagKey), Value: aws.String(fakeTunnelInsideCidr)}},
+		Options: &types.VpnConnectionOptions{
+			TunnelOptions: []types.TunnelOption{
+				{TunnelInsideCidr: aws.String(fakeTunnelInsideCidr), OutsideIpAddress: aws.String(fakeTunnelOutsideIpAddress)},
+				{TunnelInsideCidr: aws.String("169.254.100.0/30"), OutsideIpAddress: aws.String("2.2.2.2")},
+			},
+		},
+	}
 )
 
 // fakeServerState represents the fake state of the AWS server during testing.
 type fakeServerState struct {
-	vpc    *types.Vpc
-	subnet *types.Subnet
+	vpc                *types.Vpc
+	subnet             *types.Subnet
+	instance           *types.Instance // Defaults to fakeInstance if not set
+	securityGroupRules []types.SecurityGroupRule
+	transitGateway     *types.TransitGateway
+	customerGateway    *types.CustomerGateway
+	vpnConnection      *types.VpnConnection
+	requests           *[]any // Records the inputs of the AWS API calls made if set
 }
 
 // fakeServerStateContextKey is an empty struct to be used as a key for context values.
@@ -92,7 +133,10 @@ var fakeDeserializeMiddleware = middleware.DeserializeMiddlewareFunc("FakeOutput
 	out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
 ) {
 	fakeServerState := ctx.Value(&fakeServerStateContextKey{}).(fakeServerState)
-	switch ctx.Value(&requestContextKey{}).(type) {
+	if fakeServerState.requests != nil {
+		*fakeServerState.requests = append(*fakeServerState.requests, ctx.Value(&requestContextKey{}))
+	}
+	switch input := ctx.Value(&requestContextKey{}).(type) {
 	// VPCs
 	case *ec2.CreateVpcInput:
 		out.Result = &ec2.CreateVpcOutput{Vpc: fakeVpc}
@@ -104,6 +148,10 @@ var fakeDeserializeMiddleware = middleware.DeserializeMiddlewareFunc("FakeOutput
 		out.Result = describeVpcsOutput
 	case *ec2.CreateSubnetInput:
 		out.Result = &ec2.CreateSubnetOutput{Subnet: fakeSubnet}
+	case *ec2.DeleteVpcInput:
+		out.Result = &ec2.DeleteVpcOutput{}
+	case *ec2.DeleteSubnetInput:
+		out.Result = &ec2.DeleteSubnetOutput{}
 	case *ec2.DescribeSubnetsInput:
 		describeSubnetsOutput := &ec2.DescribeSubnetsOutput{}
 		if fakeServerState.subnet != nil {
@@ -119,11 +167,89 @@ var fakeDeserializeMiddleware = middleware.DeserializeMiddlewareFunc("FakeOutput
 		out.Result = &ec2.RevokeSecurityGroupIngressOutput{Return: aws.Bool(true)}
 	case *ec2.RevokeSecurityGroupEgressInput:
 		out.Result = &ec2.RevokeSecurityGroupEgressOutput{Return: aws.Bool(true)}
+	case *ec2.DeleteSecurityGroupInput:
+		out.Result = &ec2.DeleteSecurityGroupOutput{}
+	case *ec2.DescribeSecurityGroupRulesInput:
+		out.Result = &ec2.DescribeSecurityGroupRulesOutput{SecurityGroupRules: fakeServerState.securityGroupRules}
+	case *ec2.AuthorizeSecurityGroupIngressInput:
+		out.Result = &ec2.AuthorizeSecurityGroupIngressOutput{Return: aws.Bool(true)}
+	case *ec2.AuthorizeSecurityGroupEgressInput:
+		out.Result = &ec2.AuthorizeSecurityGroupEgressOutput{Return: aws.Bool(true)}
+	case *ec2.UpdateSecurityGroupRuleDescriptionsIngressInput:
+		out.Result = &ec2.UpdateSecurityGroupRuleDescriptionsIngressOutput{Return: aws.Bool(true)}
+	case *ec2.UpdateSecurityGroupRuleDescriptionsEgressInput:
+		out.Result = &ec2.UpdateSecurityGroupRuleDescriptionsEgressOutput{Return: aws.Bool(true)}
 	// Instances
 	case *ec2.RunInstancesInput:
 		out.Result = &ec2.RunInstancesOutput{Instances: []types.Instance{*fakeInstance}}
 	case *ec2.DescribeInstancesInput:
-		out.Result = &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{{Instances: []types.Instance{*fakeInstance}}}}
+		instance := fakeInstance
+		if fakeServerState.instance != nil {
+			instance = fakeServerState.instance
+		}
+		out.Result = &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{{Instances: []types.Instance{*instance}}}}
+	case *ec2.TerminateInstancesInput:
+		out.Result = &ec2.TerminateInstancesOutput{}
+	case *ec2.ModifyInstanceAttributeInput:
+		out.Result = &ec2.ModifyInstanceAttributeOutput{}
+	// Transit Gateways
+	case *ec2.DescribeTransitGatewaysInput:
+		describeTransitGatewaysOutput := &ec2.DescribeTransitGatewaysOutput{}
+		if fakeServerState.transitGateway != nil {
+			describeTransitGatewaysOutput.TransitGateways = []types.TransitGateway{*fakeServerState.transitGateway}
+		}
+		out.Result = describeTransitGatewaysOutput
+	case *ec2.CreateTransitGatewayInput:
+		out.Result = &ec2.CreateTransitGatewayOutput{TransitGateway: &types.TransitGateway{
+			TransitGatewayId: aws.String(fakeTransitGatewayId),
+			State:            types.TransitGatewayStateAvailable,
+			Options:          &types.TransitGatewayOptions{AmazonSideAsn: input.Options.AmazonSideAsn},
+		}}
+	case *ec2.DescribeTransitGatewayVpcAttachmentsInput:
+		out.Result = &ec2.DescribeTransitGatewayVpcAttachmentsOutput{}
+	case *ec2.CreateTransitGatewayVpcAttachmentInput:
+		out.Result = &ec2.CreateTransitGatewayVpcAttachmentOutput{}
+	case *ec2.DeleteTransitGatewayVpcAttachmentInput:
+		out.Result = &ec2.DeleteTransitGatewayVpcAttachmentOutput{}
+	case *ec2.DescribeTransitGatewayAttachmentsInput:
+		out.Result = &ec2.DescribeTransitGatewayAttachmentsOutput{}
+	case *ec2.DeleteTransitGatewayInput:
+		out.Result = &ec2.DeleteTransitGatewayOutput{}
+	case *ec2.DescribeRouteTablesInput:
+		out.Result = &ec2.DescribeRouteTablesOutput{RouteTables: []types.RouteTable{{RouteTableId: aws.String(fakeRouteTableId)}}}
+	case *ec2.CreateRouteInput:
+		out.Result = &ec2.CreateRouteOutput{Return: aws.Bool(true)}
+	case *ec2.DeleteRouteInput:
+		out.Result = &ec2.DeleteRouteOutput{}
+	// VPN Connections
+	case *ec2.DescribeCustomerGatewaysInput:
+		describeCustomerGatewaysOutput := &ec2.DescribeCustomerGatewaysOutput{}
+		if fakeServerState.customerGateway != nil {
+			describeCustomerGatewaysOutput.CustomerGateways = []types.CustomerGateway{*fakeServerState.customerGateway}
+		}
+		out.Result = describeCustomerGatewaysOutput
+	case *ec2.DeleteCustomerGatewayInput:
+		out.Result = &ec2.DeleteCustomerGatewayOutput{}
+	case *ec2.CreateCustomerGatewayInput:
+		out.Result = &ec2.CreateCustomerGatewayOutput{CustomerGateway: &types.CustomerGateway{CustomerGatewayId: aws.String(fakeCustomerGatewayId)}}
+	case *ec2.DescribeVpnConnectionsInput:
+		describeVpnConnectionsOutput := &ec2.DescribeVpnConnectionsOutput{}
+		if len(input.VpnConnectionIds) > 0 {
+			// Describing by ID is only done when waiting for a VPN connection to be available
+			describeVpnConnectionsOutput.VpnConnections = []types.VpnConnection{*fakeVpnConnection}
+		} else if fakeServerState.vpnConnection != nil {
+			describeVpnConnectionsOutput.VpnConnections = []types.VpnConnection{*fakeServerState.vpnConnection}
+		}
+		out.Result = describeVpnConnectionsOutput
+	case *ec2.DeleteVpnConnectionInput:
+		out.Result = &ec2.DeleteVpnConnectionOutput{}
+	case *ec2.CreateVpnConnectionInput:
+		out.Result = &ec2.CreateVpnConnectionOutput{VpnConnection: &types.VpnConnection{VpnConnectionId: aws.String(fakeVpnConnectionId), State: types.VpnStatePending}}
+	// Misc
+	case *ec2.DescribeRegionsInput:
+		out.Result = &ec2.DescribeRegionsOutput{Regions: []types.Region{{RegionName: aws.String(fakeRegion)}}}
+	case *ec2.CreateTagsInput:
+		out.Result = &ec2.CreateTagsOutput{}
 	}
 	return
 })
@@ -0,0 +1,306 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+const (
+	tunnelInsideCidrTagKey        = "TunnelInsideCidr" // Tag on VPN connections recording the inside CIDR of the tunnel used by Paraglider
+	transitGatewayPollInterval    = 10 * time.Second
+	transitGatewayCreateTimeout   = 10 * time.Minute
+	transitGatewayDeleteTimeout   = 10 * time.Minute
+	vpnConnectionAvailableTimeout = 10 * time.Minute
+)
+
+// getTransitGateway returns the transit gateway of a namespace or nil if it doesn't exist.
+func getTransitGateway(ctx context.Context, ec2Client *ec2.Client, namespace string) (*types.TransitGateway, error) {
+	filters := getDescribeFilter(namespace, getVpnGatewayName(namespace))
+	filters = append(filters, types.Filter{Name: aws.String("state"), Values: []string{"pending", "available", "modifying"}})
+	describeTransitGatewaysOutput, err := ec2Client.DescribeTransitGateways(ctx, &ec2.DescribeTransitGatewaysInput{Filters: filters})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get transit gateways: %w", err)
+	}
+	if len(describeTransitGatewaysOutput.TransitGateways) == 0 {
+		return nil, nil
+	}
+	return &describeTransitGatewaysOutput.TransitGateways[0], nil
+}
+
+// waitForTransitGateway waits until a transit gateway is available.
+// The EC2 SDK doesn't provide a waiter for transit gateways.
+func waitForTransitGateway(ctx context.Context, ec2Client *ec2.Client, transitGatewayId string) error {
+	deadline := time.Now().Add(transitGatewayCreateTimeout)
+	for {
+		describeTransitGatewaysOutput, err := ec2Client.DescribeTransitGateways(ctx, &ec2.DescribeTransitGatewaysInput{
+			TransitGatewayIds: []string{transitGatewayId},
+		})
+		if err != nil {
+			return fmt.Errorf("unable to get transit gateway: %w", err)
+		}
+		if len(describeTransitGatewaysOutput.TransitGateways) == 1 && describeTransitGatewaysOutput.TransitGateways[0].State == types.TransitGatewayStateAvailable {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for transit gateway %s to be available", transitGatewayId)
+		}
+		time.Sleep(transitGatewayPollInterval)
+	}
+}
+
+// getNamespaceVpcs returns all VPCs in the VPN region that belong to a namespace (including attached ones).
+func getNamespaceVpcs(ctx context.Context, ec2Client *ec2.Client, namespace string) ([]types.Vpc, error) {
+	describeVpcsOutput, err := ec2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
+		Filters: []types.Filter{{Name: aws.String("tag:Namespace"), Values: []string{namespace}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get VPCs: %w", err)
+	}
+	return describeVpcsOutput.Vpcs, nil
+}
+
+// attachVpcsToTransitGateway attaches all VPCs of a namespace in the VPN region to its transit gateway.
+// TODO: VPCs in other regions require inter-region transit gateway peering.
+func attachVpcsToTransitGateway(ctx context.Context, ec2Client *ec2.Client, namespace string, transitGatewayId string) error {
+	vpcs, err := getNamespaceVpcs(ctx, ec2Client, namespace)
+	if err != nil {
+		return err
+	}
+	for _, vpc := range vpcs {
+		describeAttachmentsOutput, err := ec2Client.DescribeTransitGatewayVpcAttachments(ctx, &ec2.DescribeTransitGatewayVpcAttachmentsInput{
+			Filters: []types.Filter{
+				{Name: aws.String("transit-gateway-id"), Values: []string{transitGatewayId}},
+				{Name: aws.String("vpc-id"), Values: []string{*vpc.VpcId}},
+				{Name: aws.String("state"), Values: []string{"pendingAcceptance", "pending", "available", "modifying"}},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("unable to get transit gateway VPC attachments: %w", err)
+		}
+		if len(describeAttachmentsOutput.TransitGatewayVpcAttachments) > 0 {
+			continue
+		}
+
+		// An attachment can only have one subnet per availability zone
+		describeSubnetsOutput, err := ec2Client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
+			Filters: []types.Filter{{Name: aws.String("vpc-id"), Values: []string{*vpc.VpcId}}},
+		})
+		if err != nil {
+			return fmt.Errorf("unable to get subnets: %w", err)
+		}
+		subnetIds := []string{}
+		availabilityZones := map[string]bool{}
+		for _, subnet := range describeSubnetsOutput.Subnets {
+			if !availabilityZones[*subnet.AvailabilityZone] {
+				availabilityZones[*subnet.AvailabilityZone] = true
+				subnetIds = append(subnetIds, *subnet.SubnetId)
+			}
+		}
+		if len(subnetIds) == 0 {
+			return fmt.Errorf("VPC %s has no subnets to attach to the transit gateway", *vpc.VpcId)
+		}
+
+		_, err = ec2Client.CreateTransitGatewayVpcAttachment(ctx, &ec2.CreateTransitGatewayVpcAttachmentInput{
+			TransitGatewayId:  aws.String(transitGatewayId),
+			VpcId:             vpc.VpcId,
+			SubnetIds:         subnetIds,
+			TagSpecifications: getTagSpecificationsForCreateResource(namespace, getVpnGatewayName(namespace), types.ResourceTypeTransitGatewayAttachment),
+		})
+		if err != nil {
+			return fmt.Errorf("unable to attach VPC %s to transit gateway: %w", *vpc.VpcId, err)
+		}
+	}
+	return nil
+}
+
+// addTransitGatewayRoutes routes the given address spaces from all VPCs of a namespace in the VPN region to its transit gateway.
+// Unlike virtual private gateways, transit gateways can't propagate BGP routes into VPC route tables.
+func addTransitGatewayRoutes(ctx context.Context, ec2Client *ec2.Client, namespace string, transitGatewayId string, addressSpaces []string) error {
+	vpcs, err := getNamespaceVpcs(ctx, ec2Client, namespace)
+	if err != nil {
+		return err
+	}
+	for _, vpc := range vpcs {
+		describeRouteTablesOutput, err := ec2Client.DescribeRouteTables(ctx, &ec2.DescribeRouteTablesInput{
+			Filters: []types.Filter{{Name: aws.String("vpc-id"), Values: []string{*vpc.VpcId}}},
+		})
+		if err != nil {
+			return fmt.Errorf("unable to get route tables: %w", err)
+		}
+		for _, routeTable := range describeRouteTablesOutput.RouteTables {
+			for _, addressSpace := range addressSpaces {
+				_, err := ec2Client.CreateRoute(ctx, &ec2.CreateRouteInput{
+					RouteTableId:         routeTable.RouteTableId,
+					DestinationCidrBlock: aws.String(addressSpace),
+					TransitGatewayId:     aws.String(transitGatewayId),
+				})
+				if err != nil && !isErrorCode(err, "RouteAlreadyExists") {
+					return fmt.Errorf("unable to create route to %s: %w", addressSpace, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// getTunnelInsideCidr returns the /30 inside CIDR of the tunnel whose customer side (i.e., remote cloud) has bgpIpAddress.
+func getTunnelInsideCidr(bgpIpAddress string) (string, error) {
+	addr, err := netip.ParseAddr(bgpIpAddress)
+	if err != nil {
+		return "", fmt.Errorf("invalid BGP IP address %s: %w", bgpIpAddress, err)
+	}
+	prefix, err := addr.Prefix(30)
+	if err != nil {
+		return "", err
+	}
+	// AWS always takes the first usable address of the inside CIDR and assigns the second to the customer gateway
+	if prefix.Addr().Next().Next() != addr {
+		return "", fmt.Errorf("BGP IP address %s must be the second usable address of its /30 subnet", bgpIpAddress)
+	}
+	return prefix.String(), nil
+}
+
+// getTunnelOutsideIpAddress returns the outside IP address of the tunnel of a VPN connection used by Paraglider.
+func getTunnelOutsideIpAddress(vpnConnection *types.VpnConnection) (string, error) {
+	tunnelInsideCidr := getTagValue(vpnConnection.Tags, tunnelInsideCidrTagKey)
+	if vpnConnection.Options != nil {
+		for _, tunnelOption := range vpnConnection.Options.TunnelOptions {
+			if tunnelOption.TunnelInsideCidr != nil && *tunnelOption.TunnelInsideCidr == tunnelInsideCidr && tunnelOption.OutsideIpAddress != nil {
+				return *tunnelOption.OutsideIpAddress, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("unable to find tunnel with inside CIDR %s in VPN connection %s", tunnelInsideCidr, *vpnConnection.VpnConnectionId)
+}
+
+// waitForTransitGatewayAttachmentsDeleted waits until a transit gateway has no VPC or VPN attachments left.
+func waitForTransitGatewayAttachmentsDeleted(ctx context.Context, ec2Client *ec2.Client, transitGatewayId string) error {
+	deadline := time.Now().Add(transitGatewayDeleteTimeout)
+	for {
+		describeAttachmentsOutput, err := ec2Client.DescribeTransitGatewayAttachments(ctx, &ec2.DescribeTransitGatewayAttachmentsInput{
+			Filters: []types.Filter{
+				{Name: aws.String("transit-gateway-id"), Values: []string{transitGatewayId}},
+				{Name: aws.String("state"), Values: []string{"initiating", "pendingAcceptance", "pending", "available", "modifying", "deleting"}},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("unable to get transit gateway attachments: %w", err)
+		}
+		if len(describeAttachmentsOutput.TransitGatewayAttachments) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for the attachments of transit gateway %s to be deleted", transitGatewayId)
+		}
+		time.Sleep(transitGatewayPollInterval)
+	}
+}
+
+// deleteVpn deletes the VPN connections, customer gateways, and transit gateway of a namespace along with the routes to the transit gateway.
+func deleteVpn(ctx context.Context, ec2Client *ec2.Client, namespace string) error {
+	describeVpnConnectionsOutput, err := ec2Client.DescribeVpnConnections(ctx, &ec2.DescribeVpnConnectionsInput{
+		Filters: []types.Filter{
+			{Name: aws.String("tag:Namespace"), Values: []string{namespace}},
+			{Name: aws.String("state"), Values: []string{"pending", "available"}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to get VPN connections: %w", err)
+	}
+	for _, vpnConnection := range describeVpnConnectionsOutput.VpnConnections {
+		_, err = ec2Client.DeleteVpnConnection(ctx, &ec2.DeleteVpnConnectionInput{VpnConnectionId: vpnConnection.VpnConnectionId})
+		if err != nil {
+			return fmt.Errorf("unable to delete VPN connection %s: %w", *vpnConnection.VpnConnectionId, err)
+		}
+	}
+
+	transitGateway, err := getTransitGateway(ctx, ec2Client, namespace)
+	if err != nil {
+		return err
+	}
+	if transitGateway != nil {
+		// Routes to the transit gateway would be left as blackholes in attached VPCs
+		describeRouteTablesOutput, err := ec2Client.DescribeRouteTables(ctx, &ec2.DescribeRouteTablesInput{
+			Filters: []types.Filter{{Name: aws.String("route.transit-gateway-id"), Values: []string{*transitGateway.TransitGatewayId}}},
+		})
+		if err != nil {
+			return fmt.Errorf("unable to get route tables: %w", err)
+		}
+		for _, routeTable := range describeRouteTablesOutput.RouteTables {
+			for _, route := range routeTable.Routes {
+				if route.TransitGatewayId == nil || *route.TransitGatewayId != *transitGateway.TransitGatewayId {
+					continue
+				}
+				_, err := ec2Client.DeleteRoute(ctx, &ec2.DeleteRouteInput{RouteTableId: routeTable.RouteTableId, DestinationCidrBlock: route.DestinationCidrBlock})
+				if err != nil && !isErrorCode(err, "InvalidRoute.NotFound") {
+					return fmt.Errorf("unable to delete route to %s: %w", *route.DestinationCidrBlock, err)
+				}
+			}
+		}
+
+		describeAttachmentsOutput, err := ec2Client.DescribeTransitGatewayVpcAttachments(ctx, &ec2.DescribeTransitGatewayVpcAttachmentsInput{
+			Filters: []types.Filter{
+				{Name: aws.String("transit-gateway-id"), Values: []string{*transitGateway.TransitGatewayId}},
+				{Name: aws.String("state"), Values: []string{"pendingAcceptance", "pending", "available", "modifying"}},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("unable to get transit gateway VPC attachments: %w", err)
+		}
+		for _, attachment := range describeAttachmentsOutput.TransitGatewayVpcAttachments {
+			_, err = ec2Client.DeleteTransitGatewayVpcAttachment(ctx, &ec2.DeleteTransitGatewayVpcAttachmentInput{TransitGatewayAttachmentId: attachment.TransitGatewayAttachmentId})
+			if err != nil {
+				return fmt.Errorf("unable to delete transit gateway VPC attachment %s: %w", *attachment.TransitGatewayAttachmentId, err)
+			}
+		}
+
+		// The transit gateway can only be deleted once the VPN connections and VPCs are detached
+		err = waitForTransitGatewayAttachmentsDeleted(ctx, ec2Client, *transitGateway.TransitGatewayId)
+		if err != nil {
+			return err
+		}
+		_, err = ec2Client.DeleteTransitGateway(ctx, &ec2.DeleteTransitGatewayInput{TransitGatewayId: transitGateway.TransitGatewayId})
+		if err != nil {
+			return fmt.Errorf("unable to delete transit gateway: %w", err)
+		}
+	}
+
+	describeCustomerGatewaysOutput, err := ec2Client.DescribeCustomerGateways(ctx, &ec2.DescribeCustomerGatewaysInput{
+		Filters: []types.Filter{
+			{Name: aws.String("tag:Namespace"), Values: []string{namespace}},
+			{Name: aws.String("state"), Values: []string{"pending", "available"}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to get customer gateways: %w", err)
+	}
+	for _, customerGateway := range describeCustomerGatewaysOutput.CustomerGateways {
+		_, err = ec2Client.DeleteCustomerGateway(ctx, &ec2.DeleteCustomerGatewayInput{CustomerGatewayId: customerGateway.CustomerGatewayId})
+		if err != nil {
+			return fmt.Errorf("unable to delete customer gateway %s: %w", *customerGateway.CustomerGatewayId, err)
+		}
+	}
+	return nil
+}
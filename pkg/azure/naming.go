@@ -58,6 +58,15 @@ func getVnetFromSubnetId(subnetId string) string {
 	return parts[8] // TODO @smcclure20: do this in a less brittle way
 }
 
+// Extract the network interface name from the ID of one of its IP configurations, or an empty string if the IP configuration isn't a network interface's
+func getNicFromIpConfigId(ipConfigId string) string {
+	parts := strings.Split(ipConfigId, "/")
+	if len(parts) < 11 || parts[7] != "networkInterfaces" || parts[9] != "ipConfigurations" {
+		return ""
+	}
+	return parts[8]
+}
+
 // getResourceIDInfo parses the resourceID to extract subscriptionID and resourceGroupName (and VM name if needed)
 // and returns a ResourceIDInfo object filled with the extracted values
 // a valid resourceID should be in the format of '/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/...'
@@ -19,16 +19,18 @@ package azure
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net"
-	"os"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"github.com/paraglider-project/paraglider/internal/version"
+	config "github.com/paraglider-project/paraglider/pkg/orchestrator/config"
 	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
 	utils "github.com/paraglider-project/paraglider/pkg/utils"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -37,8 +39,9 @@ const maxPriority = 4096
 
 type azurePluginServer struct {
 	paragliderpb.UnimplementedCloudPluginServer
-	orchestratorServerAddr string
-	azureCredentialGetter  IAzureCredentialGetter
+	orchestratorServerAddr  string
+	orchestratorCredentials credentials.TransportCredentials // Used to dial the orchestrator (plaintext if nil)
+	azureCredentialGetter   IAzureCredentialGetter
 }
 
 const (
@@ -51,14 +54,15 @@ func (s *azurePluginServer) setupAzureHandler(resourceIdInfo ResourceIDInfo, nam
 	var azureHandler AzureSDKHandler
 	cred, err := s.azureCredentialGetter.GetAzureCredentials()
 	if err != nil {
-		utils.Log.Printf("An error occured while getting azure credentials:%+v", err)
+		slog.Error("An error occured while getting azure credentials", "error", err)
 		return nil, err
 	}
 	azureHandler.SetSubIdAndResourceGroup(resourceIdInfo.SubscriptionID, resourceIdInfo.ResourceGroupName)
 	azureHandler.paragliderNamespace = namespace
+	azureHandler.orchestratorCredentials = s.orchestratorCredentials
 	err = azureHandler.InitializeClients(cred)
 	if err != nil {
-		utils.Log.Printf("An error occured while initializing azure clients: %+v", err)
+		slog.Error("An error occured while initializing azure clients", "error", err)
 		return nil, err
 	}
 
@@ -71,7 +75,7 @@ func (s *azurePluginServer) GetPermitList(ctx context.Context, req *paragliderpb
 	resourceId := req.Resource
 	resourceIdInfo, err := getResourceIDInfo(resourceId)
 	if err != nil {
-		utils.Log.Printf("An error occured while getting resource ID info: %+v", err)
+		slog.ErrorContext(ctx, "An error occured while getting resource ID info", "error", err)
 		return nil, err
 	}
 	azureHandler, err := s.setupAzureHandler(resourceIdInfo, req.Namespace)
@@ -90,10 +94,10 @@ func (s *azurePluginServer) GetPermitList(ctx context.Context, req *paragliderpb
 
 	// get the NSG rules
 	for _, rule := range nsg.Properties.SecurityRules {
-		if !strings.HasPrefix(*rule.Name, denyAllNsgRulePrefix) && strings.HasPrefix(*rule.Name, paragliderPrefix) {
+		if isParagliderPermitListRule(rule) {
 			plRule, err := azureHandler.GetPermitListRuleFromNSGRule(rule)
 			if err != nil {
-				utils.Log.Printf("An error occured while getting Paraglider rule from NSG rule: %+v", err)
+				slog.ErrorContext(ctx, "An error occured while getting Paraglider rule from NSG rule", "error", err)
 				return nil, err
 			}
 			plRule.Name = getRuleNameFromNSGRuleName(plRule.Name)
@@ -110,7 +114,7 @@ func (s *azurePluginServer) AddPermitListRules(ctx context.Context, req *paragli
 	resourceID := req.GetResource()
 	resourceIdInfo, err := getResourceIDInfo(resourceID)
 	if err != nil {
-		utils.Log.Printf("An error occured while getting resource ID info: %+v", err)
+		slog.ErrorContext(ctx, "An error occured while getting resource ID info", "error", err)
 		return nil, err
 	}
 	azureHandler, err := s.setupAzureHandler(resourceIdInfo, req.Namespace)
@@ -128,20 +132,17 @@ func (s *azurePluginServer) AddPermitListRules(ctx context.Context, req *paragli
 	var reservedPrioritiesOutbound map[int32]*armnetwork.SecurityRule = make(map[int32]*armnetwork.SecurityRule)
 	err = setupMaps(reservedPrioritiesInbound, reservedPrioritiesOutbound, existingRulePriorities, netInfo.NSG)
 	if err != nil {
-		utils.Log.Printf("An error occured during setup: %+v", err)
+		slog.ErrorContext(ctx, "An error occured during setup", "error", err)
 		return nil, err
 	}
-	var outboundPriority int32 = 100
-	var inboundPriority int32 = 100
-
 	// Get used address spaces of all clouds
-	orchestratorConn, err := grpc.NewClient(s.orchestratorServerAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	orchestratorConn, err := grpc.NewClient(s.orchestratorServerAddr, utils.DialCredentials(s.orchestratorCredentials), utils.TracingDialOption())
 	if err != nil {
 		return nil, fmt.Errorf("unable to establish connection with orchestrator: %w", err)
 	}
 	defer orchestratorConn.Close()
 	orchestratorClient := paragliderpb.NewControllerClient(orchestratorConn)
-	getUsedAddressSpacesResp, err := orchestratorClient.GetUsedAddressSpaces(context.Background(), &emptypb.Empty{})
+	getUsedAddressSpacesResp, err := orchestratorClient.GetUsedAddressSpaces(ctx, &emptypb.Empty{})
 	if err != nil {
 		return nil, fmt.Errorf("unable to get used address spaces: %w", err)
 	}
@@ -150,7 +151,7 @@ func (s *azurePluginServer) AddPermitListRules(ctx context.Context, req *paragli
 	// get the vnet to be able to get both the address space as well as the peering when needed
 	resourceVnet, err := azureHandler.GetVnet(ctx, vnetName)
 	if err != nil {
-		utils.Log.Printf("An error occured while getting paraglider vnets address spaces:%+v", err)
+		slog.ErrorContext(ctx, "An error occured while getting paraglider vnets address spaces", "error", err)
 		return nil, err
 	}
 
@@ -165,69 +166,95 @@ func (s *azurePluginServer) AddPermitListRules(ctx context.Context, req *paragli
 
 	// Add the rules to the NSG
 	for _, rule := range req.GetRules() {
-		// Get all peering cloud infos
-		peeringCloudInfos, err := utils.GetPermitListRulePeeringCloudInfo(rule, getUsedAddressSpacesResp.AddressSpaceMappings)
+		isIpv6, err := utils.IsPermitListRuleIPv6(rule)
 		if err != nil {
-			return nil, fmt.Errorf("unable to get peering cloud infos: %w", err)
+			return nil, fmt.Errorf("invalid targets for rule %s: %w", rule.Name, err)
+		}
+		if isIpv6 && netInfo.Ipv6Address == "" {
+			return nil, fmt.Errorf("rule %s has IPv6 targets but resource %s has no IPv6 address", rule.Name, req.Resource)
 		}
 
-		for i, peeringCloudInfo := range peeringCloudInfos {
-			if peeringCloudInfo == nil {
-				// Setup NAT gateway for public IP addresses
-				_, err = getOrCreateNatGateway(ctx, azureHandler, req.Namespace, *resourceVnet.Location)
-				if err != nil {
-					return nil, fmt.Errorf("unable to setup NAT gateway: %w", err)
-				}
-			} else if peeringCloudInfo.Cloud != utils.AZURE {
-				address := rule.Targets[i]
-				// Create VPN connections
-				connectCloudsReq := &paragliderpb.ConnectCloudsRequest{
-					CloudA:              utils.AZURE,
-					CloudANamespace:     req.Namespace,
-					CloudB:              peeringCloudInfo.Cloud,
-					CloudBNamespace:     peeringCloudInfo.Namespace,
-					AddressSpacesCloudA: localVnetAddressSpaces,
-					AddressSpacesCloudB: []string{address},
-				}
-				_, err := orchestratorClient.ConnectClouds(ctx, connectCloudsReq)
-				if err != nil {
-					return nil, fmt.Errorf("unable to connect clouds : %w", err)
-				}
-			} else {
-				isLocal, err := utils.IsPermitListRuleTagInAddressSpace(rule.Targets[i], localVnetAddressSpaces)
-				if err != nil {
-					return nil, fmt.Errorf("unable to determine if tag is in local vnet address space: %w", err)
-				}
-				if !isLocal {
-					// Create VPC network peering (remote is in a different region or namespace)
-					err = s.createPeering(ctx, *azureHandler, resourceIdInfo, vnetName, peeringCloudInfo, rule.Targets[i])
+		// Connectivity is only set up for traffic which is allowed since blocking traffic doesn't need any
+		if rule.Action != paragliderpb.RuleAction_DENY {
+			// Get all peering cloud infos
+			peeringCloudInfos, err := utils.GetPermitListRulePeeringCloudInfo(rule, getUsedAddressSpacesResp.AddressSpaceMappings)
+			if err != nil {
+				return nil, fmt.Errorf("unable to get peering cloud infos: %w", err)
+			}
+
+			for i, peeringCloudInfo := range peeringCloudInfos {
+				if peeringCloudInfo == nil {
+					// Setup NAT gateway for public IP addresses
+					_, err = getOrCreateNatGateway(ctx, azureHandler, req.Namespace, *resourceVnet.Location)
 					if err != nil {
-						return nil, fmt.Errorf("unable to create vnet peering: %w", err)
+						return nil, fmt.Errorf("unable to setup NAT gateway: %w", err)
+					}
+				} else if peeringCloudInfo.Cloud != utils.AZURE {
+					if isIpv6 {
+						return nil, fmt.Errorf("IPv6 targets in other clouds are not supported since VPN connections are IPv4-only")
+					}
+					address := rule.Targets[i]
+					// Create VPN connections
+					connectCloudsReq := &paragliderpb.ConnectCloudsRequest{
+						CloudA:              utils.AZURE,
+						CloudANamespace:     req.Namespace,
+						CloudB:              peeringCloudInfo.Cloud,
+						CloudBNamespace:     peeringCloudInfo.Namespace,
+						AddressSpacesCloudA: localVnetAddressSpaces,
+						AddressSpacesCloudB: []string{address},
+					}
+					connectCloudsResp, err := orchestratorClient.ConnectClouds(ctx, connectCloudsReq)
+					if err != nil {
+						return nil, fmt.Errorf("unable to connect clouds : %w", err)
+					}
+					utils.AddResponseNotices(ctx, connectCloudsResp)
+				} else {
+					isLocal, err := utils.IsPermitListRuleTagInAddressSpace(rule.Targets[i], localVnetAddressSpaces)
+					if err != nil {
+						return nil, fmt.Errorf("unable to determine if tag is in local vnet address space: %w", err)
+					}
+					if !isLocal {
+						// Create VPC network peering (remote is in a different region or namespace)
+						err = s.createPeering(ctx, *azureHandler, resourceIdInfo, vnetName, peeringCloudInfo, rule.Targets[i])
+						if err != nil {
+							return nil, fmt.Errorf("unable to create vnet peering: %w", err)
+						}
 					}
 				}
 			}
 		}
 
 		// To avoid conflicted priorities, we need to check whether the priority is already used by other rules
-		// if the priority is already used, we need to find the next available priority
+		// if the priority is already used, we need to find the next available priority.
+		// Rules are kept within the priority band of their action and relative priority so that they're evaluated in order.
+		reservedPriorities := reservedPrioritiesInbound
+		if rule.Direction == paragliderpb.Direction_OUTBOUND {
+			reservedPriorities = reservedPrioritiesOutbound
+		}
+		bandStart, bandEnd := getPriorityBand(rule)
 		priority, ok := existingRulePriorities[getNSGRuleName(rule.Name)]
-		if !ok {
-			if rule.Direction == paragliderpb.Direction_INBOUND {
-				priority = getNextAvailablePriority(reservedPrioritiesInbound, inboundPriority, maxPriority, true)
-				inboundPriority = priority + 1
-			} else if rule.Direction == paragliderpb.Direction_OUTBOUND {
-				priority = getNextAvailablePriority(reservedPrioritiesOutbound, outboundPriority, maxPriority, true)
-				outboundPriority = priority + 1
+		if !ok || priority < bandStart || priority >= bandEnd {
+			if ok {
+				// The rule moves to another band, freeing its current priority
+				delete(reservedPriorities, priority)
+			}
+			priority = getNextAvailablePriority(reservedPriorities, bandStart, bandEnd, true)
+			if priority == bandEnd {
+				return nil, fmt.Errorf("no NSG priority left for rule %s with priority %d", rule.Name, rule.Priority)
 			}
 		}
 
-		// Create the NSG rule
-		securityRule, err := azureHandler.CreateSecurityRuleFromPermitList(ctx, rule, *netInfo.NSG.Name, getNSGRuleName(rule.Name), netInfo.Address, priority, allowRule)
+		// Create the NSG rule for the resource address of the same IP version as the targets
+		resourceAddress := netInfo.Address
+		if isIpv6 {
+			resourceAddress = netInfo.Ipv6Address
+		}
+		securityRule, err := azureHandler.CreateSecurityRuleFromPermitList(ctx, rule, *netInfo.NSG.Name, getNSGRuleName(rule.Name), resourceAddress, priority, paragliderToAzureAccess[rule.Action])
 		if err != nil {
-			utils.Log.Printf("An error occured while creating security rule:%+v", err)
+			slog.ErrorContext(ctx, "An error occured while creating security rule", "error", err)
 			return nil, err
 		}
-		utils.Log.Printf("Successfully created network security rule: %s", *securityRule.ID)
+		slog.InfoContext(ctx, "Successfully created network security rule", "securityRule", *securityRule.ID)
 	}
 
 	return &paragliderpb.AddPermitListRulesResponse{}, nil
@@ -238,7 +265,7 @@ func (s *azurePluginServer) DeletePermitListRules(c context.Context, req *paragl
 	resourceID := req.GetResource()
 	resourceIdInfo, err := getResourceIDInfo(resourceID)
 	if err != nil {
-		utils.Log.Printf("An error occured while getting resource ID info: %+v", err)
+		slog.ErrorContext(c, "An error occured while getting resource ID info", "error", err)
 		return nil, err
 	}
 	azureHandler, err := s.setupAzureHandler(resourceIdInfo, req.Namespace)
@@ -254,10 +281,10 @@ func (s *azurePluginServer) DeletePermitListRules(c context.Context, req *paragl
 	for _, rule := range req.GetRuleNames() {
 		err := azureHandler.DeleteSecurityRule(c, *netInfo.NSG.Name, getNSGRuleName(rule))
 		if err != nil {
-			utils.Log.Printf("An error occured while deleting security rule:%+v", err)
+			slog.ErrorContext(c, "An error occured while deleting security rule", "error", err)
 			return nil, err
 		}
-		utils.Log.Printf("Successfully deleted network security rule: %s", rule)
+		slog.InfoContext(c, "Successfully deleted network security rule", "securityRule", rule)
 	}
 
 	return &paragliderpb.DeletePermitListRulesResponse{}, nil
@@ -269,13 +296,13 @@ func (s *azurePluginServer) DeletePermitListRules(c context.Context, req *paragl
 func (s *azurePluginServer) CreateResource(ctx context.Context, resourceDesc *paragliderpb.CreateResourceRequest) (*paragliderpb.CreateResourceResponse, error) {
 	resourceDescInfo, err := GetResourceInfoFromResourceDesc(ctx, resourceDesc)
 	if err != nil {
-		utils.Log.Printf("Resource description is invalid:%+v", err)
+		slog.ErrorContext(ctx, "Resource description is invalid", "error", err)
 		return nil, err
 	}
 
 	resourceIdInfo, err := getResourceIDInfo(resourceDesc.Deployment.Id)
 	if err != nil {
-		utils.Log.Printf("An error occured while getting resource id info:%+v", err)
+		slog.ErrorContext(ctx, "An error occured while getting resource id info", "error", err)
 		return nil, err
 	}
 
@@ -287,7 +314,7 @@ func (s *azurePluginServer) CreateResource(ctx context.Context, resourceDesc *pa
 	vnetName := getVnetName(resourceDescInfo.Location, resourceDesc.Deployment.Namespace)
 	paragliderVnet, err := azureHandler.GetParagliderVnet(ctx, vnetName, resourceDescInfo.Location, resourceDesc.Deployment.Namespace, s.orchestratorServerAddr)
 	if err != nil {
-		utils.Log.Printf("An error occured while getting paraglider vnet:%+v", err)
+		slog.ErrorContext(ctx, "An error occured while getting paraglider vnet", "error", err)
 		return nil, err
 	}
 
@@ -306,7 +333,7 @@ func (s *azurePluginServer) CreateResource(ctx context.Context, resourceDesc *pa
 		if !subnetExists {
 			resourceSubnet, err = azureHandler.AddSubnetToParagliderVnet(ctx, resourceDesc.Deployment.Namespace, vnetName, getSubnetName(resourceDescInfo.ResourceName), s.orchestratorServerAddr)
 			if err != nil {
-				utils.Log.Printf("An error occured while creating subnet:%+v", err)
+				slog.ErrorContext(ctx, "An error occured while creating subnet", "error", err)
 				return nil, err
 			}
 		}
@@ -315,17 +342,17 @@ func (s *azurePluginServer) CreateResource(ctx context.Context, resourceDesc *pa
 	additionalAddrs := []string{}
 	if resourceDescInfo.NumAdditionalAddressSpaces > 0 {
 		// Create additional address spaces
-		conn, err := grpc.NewClient(s.orchestratorServerAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		conn, err := grpc.NewClient(s.orchestratorServerAddr, utils.DialCredentials(s.orchestratorCredentials), utils.TracingDialOption())
 		if err != nil {
-			utils.Log.Printf("Could not dial the orchestrator")
+			slog.ErrorContext(ctx, "Could not dial the orchestrator", "error", err)
 			return nil, err
 		}
 		defer conn.Close()
 		client := paragliderpb.NewControllerClient(conn)
 		reqAddressSpaces := make([]int32, resourceDescInfo.NumAdditionalAddressSpaces)
-		response, err := client.FindUnusedAddressSpaces(context.Background(), &paragliderpb.FindUnusedAddressSpacesRequest{Sizes: reqAddressSpaces})
+		response, err := client.FindUnusedAddressSpaces(ctx, &paragliderpb.FindUnusedAddressSpacesRequest{Sizes: reqAddressSpaces})
 		if err != nil {
-			utils.Log.Printf("Failed to find unused address spaces: %v", err)
+			slog.ErrorContext(ctx, "Failed to find unused address spaces", "error", err)
 			return nil, err
 		}
 		additionalAddrs = response.AddressSpaces
@@ -334,7 +361,7 @@ func (s *azurePluginServer) CreateResource(ctx context.Context, resourceDesc *pa
 	// Create the resource
 	ip, err := ReadAndProvisionResource(ctx, resourceDesc, resourceSubnet, &resourceIdInfo, azureHandler, additionalAddrs)
 	if err != nil {
-		utils.Log.Printf("An error occured while creating resource:%+v", err)
+		slog.ErrorContext(ctx, "An error occured while creating resource", "error", err)
 		return nil, err
 	}
 
@@ -343,7 +370,7 @@ func (s *azurePluginServer) CreateResource(ctx context.Context, resourceDesc *pa
 	// Note that vnets are free, so this is not a problem.
 	vpnGwVnet, err := GetOrCreateVpnGatewayVNet(ctx, azureHandler, resourceDesc.Deployment.Namespace)
 	if err != nil {
-		utils.Log.Printf("An error occured while getting or creating VPN gateway vnet:%+v", err)
+		slog.ErrorContext(ctx, "An error occured while getting or creating VPN gateway vnet", "error", err)
 		return nil, err
 	}
 
@@ -354,7 +381,7 @@ func (s *azurePluginServer) CreateResource(ctx context.Context, resourceDesc *pa
 	// - If the VPN gateway hasn't been created, then the gateway transit relationship will be established on VPN gateway creation.
 	err = CreateGatewayVnetPeering(ctx, azureHandler, vnetName, *vpnGwVnet.Name, resourceDesc.Deployment.Namespace)
 	if err != nil {
-		utils.Log.Printf("An error occured while creating VPN gateway vnet peering:%+v", err)
+		slog.ErrorContext(ctx, "An error occured while creating VPN gateway vnet peering", "error", err)
 		return nil, err
 	}
 
@@ -372,7 +399,7 @@ func (s *azurePluginServer) GetUsedAddressSpaces(ctx context.Context, req *parag
 		}
 		resourceIdInfo, err := getResourceIDInfo(deployment.Id)
 		if err != nil {
-			utils.Log.Printf("An error occured while getting resource ID info: %+v", err)
+			slog.ErrorContext(ctx, "An error occured while getting resource ID info", "error", err)
 			return nil, err
 		}
 		azureHandler, err := s.setupAzureHandler(resourceIdInfo, deployment.Namespace)
@@ -382,7 +409,7 @@ func (s *azurePluginServer) GetUsedAddressSpaces(ctx context.Context, req *parag
 
 		addressSpaces, err := azureHandler.GetAllVnetsAddressSpaces(ctx, deployment.Namespace)
 		if err != nil {
-			utils.Log.Printf("An error occured while getting address spaces:%+v", err)
+			slog.ErrorContext(ctx, "An error occured while getting address spaces", "error", err)
 			return nil, err
 		}
 		paragliderAddressList := []string{}
@@ -402,7 +429,7 @@ func (s *azurePluginServer) GetUsedAsns(ctx context.Context, req *paragliderpb.G
 	for _, deployment := range req.Deployments {
 		resourceIdInfo, err := getResourceIDInfo(deployment.Id)
 		if err != nil {
-			utils.Log.Printf("An error occured while getting resource ID info: %+v", err)
+			slog.ErrorContext(ctx, "An error occured while getting resource ID info", "error", err)
 			return nil, err
 		}
 		azureHandler, err := s.setupAzureHandler(resourceIdInfo, deployment.Namespace)
@@ -429,7 +456,7 @@ func (s *azurePluginServer) GetUsedBgpPeeringIpAddresses(ctx context.Context, re
 	for _, deployment := range req.Deployments {
 		resourceIdInfo, err := getResourceIDInfo(deployment.Id)
 		if err != nil {
-			utils.Log.Printf("An error occured while getting resource ID info: %+v", err)
+			slog.ErrorContext(ctx, "An error occured while getting resource ID info", "error", err)
 			return nil, err
 		}
 		azureHandler, err := s.setupAzureHandler(resourceIdInfo, deployment.Namespace)
@@ -506,7 +533,7 @@ func (s *azurePluginServer) CreateVpnGateway(ctx context.Context, req *paraglide
 				return nil, fmt.Errorf("unable to get VPN gateway subnet: %w", err)
 			}
 
-			conn, err := grpc.NewClient(s.orchestratorServerAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+			conn, err := grpc.NewClient(s.orchestratorServerAddr, utils.DialCredentials(s.orchestratorCredentials), utils.TracingDialOption())
 			if err != nil {
 				return nil, fmt.Errorf("unable to establish connection with orchestrator: %w", err)
 			}
@@ -576,6 +603,7 @@ func (s *azurePluginServer) CreateVpnGateway(ctx context.Context, req *paraglide
 			if err != nil {
 				return nil, fmt.Errorf("unable to update virtual network gateway with BGP IP addresses: %w", err)
 			}
+			utils.AddSideEffect(ctx, virtualNetworkGatewayName, fmt.Sprintf("created VPN gateway %s", virtualNetworkGatewayName))
 
 			// Update existing peerings with gateway transit relationship
 			gatewayVnetPeerings, err := azureHandler.ListVirtualNetworkPeerings(ctx, gatewayVnetName)
@@ -708,6 +736,7 @@ func (s *azurePluginServer) CreateVpnConnections(ctx context.Context, req *parag
 				if err != nil {
 					return nil, fmt.Errorf("unable to create virtual network gateway connection: %w", err)
 				}
+				utils.AddSideEffect(ctx, virtualNetworkGatewayconnectionName, fmt.Sprintf("created VPN connection %s to %s", virtualNetworkGatewayconnectionName, req.Cloud))
 			} else {
 				return nil, fmt.Errorf("unable to get virtual network gateway connection: %w", err)
 			}
@@ -739,7 +768,10 @@ func (s *azurePluginServer) createPeering(ctx context.Context, azureHandler Azur
 			return fmt.Errorf("unable to check if tag is in vnet address space")
 		}
 		if contained {
-			// Create peering
+			// Create peering (reporting it only if it didn't exist yet since it is re-applied for every rule)
+			peeringName := getPeeringName(resourceVnetName, peeringVnetName)
+			_, err = azureHandler.GetVirtualNetworkPeering(ctx, resourceVnetName, peeringName)
+			created := isErrorNotFound(err)
 			err = azureHandler.CreateVnetPeeringOneWay(ctx, resourceVnetName, peeringVnetName, peeringCloudResourceIDInfo.SubscriptionID, peeringCloudResourceIDInfo.ResourceGroupName)
 			if err != nil {
 				return fmt.Errorf("unable to create vnet peering: %w", err)
@@ -748,6 +780,9 @@ func (s *azurePluginServer) createPeering(ctx context.Context, azureHandler Azur
 			if err != nil {
 				return fmt.Errorf("unable to create vnet peering: %w", err)
 			}
+			if created {
+				utils.AddSideEffect(ctx, peeringName, fmt.Sprintf("created VNet peering %s", peeringName))
+			}
 			break
 		}
 	}
@@ -800,7 +835,7 @@ func (s *azurePluginServer) AttachResource(ctx context.Context, attachResourceRe
 	resourceId := attachResourceReq.GetResource()
 	resourceIdInfo, err := getResourceIDInfo(resourceId)
 	if err != nil {
-		utils.Log.Printf("An error occured while getting resource id info:%+v", err)
+		slog.ErrorContext(ctx, "An error occured while getting resource id info", "error", err)
 		return nil, err
 	}
 
@@ -811,14 +846,14 @@ func (s *azurePluginServer) AttachResource(ctx context.Context, attachResourceRe
 
 	resource, networkInfo, err := ValidateResourceCompliesWithParagliderRequirements(ctx, resourceId, azureHandler, s)
 	if err != nil {
-		utils.Log.Printf("An error occured while validating resource:%+v", err)
+		slog.ErrorContext(ctx, "An error occured while validating resource", "error", err)
 		return nil, err
 	}
 
 	// Create VPN gateway vnet if not already created
 	vpnGwVnet, err := GetOrCreateVpnGatewayVNet(ctx, azureHandler, namespace)
 	if err != nil {
-		utils.Log.Printf("An error occured while getting or creating VPN gateway vnet:%+v", err)
+		slog.ErrorContext(ctx, "An error occured while getting or creating VPN gateway vnet", "error", err)
 		return nil, err
 	}
 
@@ -826,13 +861,13 @@ func (s *azurePluginServer) AttachResource(ctx context.Context, attachResourceRe
 	// Create peering between the VPN gateway vnet and VM vnet. If the VPN gateway already exists, then establish a VPN gateway transit relationship where the vnet can use the gatewayVnet's VPN gateway.
 	err = CreateGatewayVnetPeering(ctx, azureHandler, vnetName, *vpnGwVnet.Name, namespace)
 	if err != nil {
-		utils.Log.Printf("An error occured while creating VPN gateway vnet peering:%+v", err)
+		slog.ErrorContext(ctx, "An error occured while creating VPN gateway vnet peering", "error", err)
 		return nil, err
 	}
 
 	vnet, err := azureHandler.GetVirtualNetwork(ctx, vnetName)
 	if err != nil {
-		utils.Log.Printf("An error occured while getting vnet:%+v", err)
+		slog.ErrorContext(ctx, "An error occured while getting vnet", "error", err)
 		return nil, err
 	}
 
@@ -840,30 +875,221 @@ func (s *azurePluginServer) AttachResource(ctx context.Context, attachResourceRe
 	azureHandler.createParagliderNamespaceTag(&vnet.Tags)
 	_, err = azureHandler.CreateOrUpdateVirtualNetwork(ctx, vnetName, *vnet)
 	if err != nil {
-		utils.Log.Printf("An error occured while creating vnet:%+v", err)
+		slog.ErrorContext(ctx, "An error occured while creating vnet", "error", err)
 		return nil, err
 	}
 
 	return &paragliderpb.AttachResourceResponse{Name: *resource.Name, Uri: *resource.ID, Ip: networkInfo.Address}, nil
 }
 
-func Setup(port int, orchestratorServerAddr string) *azurePluginServer {
-	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", port))
+// DeleteResource deletes a Paraglider resource along with its Paraglider NSG rules and the networking resources created for it
+func (s *azurePluginServer) DeleteResource(ctx context.Context, req *paragliderpb.DeleteResourceRequest) (*paragliderpb.DeleteResourceResponse, error) {
+	resourceId := req.GetResource()
+	resourceIdInfo, err := getResourceIDInfo(resourceId)
+	if err != nil {
+		slog.ErrorContext(ctx, "An error occured while getting resource id info", "error", err)
+		return nil, err
+	}
+
+	azureHandler, err := s.setupAzureHandler(resourceIdInfo, req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+
+	netInfo, err := GetAndCheckResourceState(ctx, azureHandler, resourceId, req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove the Paraglider rules so that a shared NSG is left clean
+	for _, rule := range netInfo.NSG.Properties.SecurityRules {
+		if isParagliderPermitListRule(rule) {
+			err := azureHandler.DeleteSecurityRule(ctx, *netInfo.NSG.Name, *rule.Name)
+			if err != nil {
+				slog.ErrorContext(ctx, "An error occured while deleting security rule", "error", err)
+				return nil, err
+			}
+		}
+	}
+
+	resource, err := azureHandler.GetResource(ctx, resourceId)
+	if err != nil {
+		slog.ErrorContext(ctx, "An error occured while getting resource", "resourceId", resourceId, "error", err)
+		return nil, err
+	}
+	resourceHandler, err := getResourceHandler(resourceId)
+	if err != nil {
+		return nil, err
+	}
+	err = resourceHandler.deleteResource(ctx, resource, netInfo, azureHandler)
+	if err != nil {
+		slog.ErrorContext(ctx, "An error occured while deleting resource", "resourceId", resourceId, "error", err)
+		return nil, err
+	}
+	slog.InfoContext(ctx, "Successfully deleted resource", "resourceId", resourceId)
+
+	return &paragliderpb.DeleteResourceResponse{}, nil
+}
+
+// DeleteNamespace deletes the vnets, VPN gateway, and NAT gateways Paraglider created for the namespace in the deployment's resource group
+func (s *azurePluginServer) DeleteNamespace(ctx context.Context, req *paragliderpb.DeleteNamespaceRequest) (*paragliderpb.DeleteNamespaceResponse, error) {
+	resourceIdInfo, err := getResourceIDInfo(req.Deployment.Id)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get resource ID info: %w", err)
+	}
+	azureHandler, err := s.setupAzureHandler(resourceIdInfo, req.Deployment.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("unable to setup azure handler: %w", err)
+	}
+
+	err = azureHandler.DeleteNamespaceResources(ctx, req.Deployment.Namespace)
+	if err != nil {
+		slog.ErrorContext(ctx, "An error occured while deleting namespace", "error", err)
+		return nil, err
+	}
+	slog.InfoContext(ctx, "Successfully deleted namespace")
+
+	return &paragliderpb.DeleteNamespaceResponse{}, nil
+}
+
+// DetachResource removes a resource from a Paraglider deployment without deleting it.
+// The Paraglider NSG rules are removed and, for vnets which were not created by Paraglider, so are the VPN gateway peerings and the namespace tag added on attach
+// once no other Paraglider resource is left in the vnet.
+func (s *azurePluginServer) DetachResource(ctx context.Context, req *paragliderpb.DetachResourceRequest) (*paragliderpb.DetachResourceResponse, error) {
+	resourceId := req.GetResource()
+	resourceIdInfo, err := getResourceIDInfo(resourceId)
+	if err != nil {
+		slog.ErrorContext(ctx, "An error occured while getting resource id info", "error", err)
+		return nil, err
+	}
+
+	azureHandler, err := s.setupAzureHandler(resourceIdInfo, req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+
+	netInfo, err := GetAndCheckResourceState(ctx, azureHandler, resourceId, req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+
+	// Remove all Paraglider rules, including the deny all rules added to make the NSG compliant
+	for _, rule := range netInfo.NSG.Properties.SecurityRules {
+		if strings.HasPrefix(*rule.Name, paragliderPrefix) {
+			err := azureHandler.DeleteSecurityRule(ctx, *netInfo.NSG.Name, *rule.Name)
+			if err != nil {
+				slog.ErrorContext(ctx, "An error occured while deleting security rule", "error", err)
+				return nil, err
+			}
+		}
+	}
+
+	// Paraglider vnets are shared by all resources in the namespace, so only vnets brought in by AttachResource are cleaned up
+	vnetName := getVnetFromSubnetId(netInfo.SubnetID)
+	if !strings.HasPrefix(vnetName, getParagliderNamespacePrefix(req.GetNamespace())) {
+		vnet, err := azureHandler.GetVirtualNetwork(ctx, vnetName)
+		if err != nil {
+			slog.ErrorContext(ctx, "An error occured while getting vnet", "error", err)
+			return nil, err
+		}
+
+		// Other attached resources in the vnet still need the peering and the tag
+		inUse, err := isVnetUsedByParagliderResources(ctx, azureHandler, vnet, *netInfo.NSG.Name)
+		if err != nil {
+			slog.ErrorContext(ctx, "An error occured while checking for other resources in the vnet", "error", err)
+			return nil, err
+		}
+		if inUse {
+			slog.InfoContext(ctx, "Successfully detached resource, keeping vnet attached for other resources", "resourceId", resourceId, "vnet", vnetName)
+			return &paragliderpb.DetachResourceResponse{}, nil
+		}
+
+		err = DeleteGatewayVnetPeering(ctx, azureHandler, vnetName, getVpnGatewayVnetName(req.GetNamespace()))
+		if err != nil {
+			slog.ErrorContext(ctx, "An error occured while deleting VPN gateway vnet peering", "error", err)
+			return nil, err
+		}
+
+		if _, ok := vnet.Tags[namespaceTagKey]; ok {
+			delete(vnet.Tags, namespaceTagKey)
+			_, err = azureHandler.CreateOrUpdateVirtualNetwork(ctx, vnetName, *vnet)
+			if err != nil {
+				slog.ErrorContext(ctx, "An error occured while updating vnet", "error", err)
+				return nil, err
+			}
+		}
+	}
+	slog.InfoContext(ctx, "Successfully detached resource", "resourceId", resourceId)
+
+	return &paragliderpb.DetachResourceResponse{}, nil
+}
+
+// GetCapabilities reports which resources, rule features and VPN modes the plugin supports
+func (s *azurePluginServer) GetCapabilities(ctx context.Context, req *paragliderpb.GetCapabilitiesRequest) (*paragliderpb.GetCapabilitiesResponse, error) {
+	return &paragliderpb.GetCapabilitiesResponse{
+		ResourceTypes:  []paragliderpb.ResourceType{paragliderpb.ResourceType_INSTANCE, paragliderpb.ResourceType_CLUSTER},
+		AttachResource: true,
+		RuleFeatures:   &paragliderpb.RuleFeatures{PortRanges: true, PortLists: true, Ipv6: true, Icmp: true, DenyRules: true},
+		VpnModes:       []paragliderpb.VpnMode{paragliderpb.VpnMode_BGP, paragliderpb.VpnMode_STATIC},
+		Limits:         &paragliderpb.CapabilityLimits{MaxPermitListRules: 1000}, // Security rules per NSG
+	}, nil
+}
+
+// CloudPlugin RPCs the plugin implements, as reported when registering with the controller
+var capabilities = []string{
+	"CreateResource",
+	"AttachResource",
+	"DeleteResource",
+	"DetachResource",
+	"DeleteNamespace",
+	"GetPermitList",
+	"AddPermitListRules",
+	"DeletePermitListRules",
+	"GetUsedAddressSpaces",
+	"GetUsedAsns",
+	"GetUsedBgpPeeringIpAddresses",
+	"CreateVpnGateway",
+	"CreateVpnConnections",
+	"GetNetworkAddressSpaces",
+	"GetCapabilities",
+}
+
+func Setup(port int, orchestratorServerAddr string, tlsConfig config.TLS) *azurePluginServer {
+	return SetupWithAdvertiseAddress(port, orchestratorServerAddr, tlsConfig, "")
+}
+
+// SetupWithAdvertiseAddress starts the plugin server, which registers with the controller at the advertised address (localhost if empty)
+func SetupWithAdvertiseAddress(port int, orchestratorServerAddr string, tlsConfig config.TLS, advertiseAddress string) *azurePluginServer {
+	creds, err := utils.LoadTLSCredentials(tlsConfig)
+	if err != nil {
+		slog.Error("Failed to setup TLS", "error", err)
+		return nil
+	}
+	listenAddress, registeredAddress := utils.GetPluginAddresses(port, advertiseAddress)
+	lis, err := net.Listen("tcp", listenAddress)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to listen: %v", err)
+		slog.Error("Failed to listen", "error", err)
 	}
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(grpc.Creds(creds), grpc.ChainUnaryInterceptor(utils.MetricsServerInterceptor, utils.LogFieldsServerInterceptor(utils.CloudLogKey, utils.AZURE), utils.NoticesServerInterceptor), utils.TracingServerOption())
 	azureServer := &azurePluginServer{
-		orchestratorServerAddr: orchestratorServerAddr,
-		azureCredentialGetter:  &AzureCredentialGetter{},
+		orchestratorServerAddr:  orchestratorServerAddr,
+		orchestratorCredentials: creds,
+		azureCredentialGetter:   &AzureCredentialGetter{},
 	}
 	paragliderpb.RegisterCloudPluginServer(grpcServer, azureServer)
-	fmt.Println("Starting server on port: ", port)
+	utils.RegisterHealthService(grpcServer)
+	slog.Info("Starting Azure plugin server", "port", port)
 
 	go func() {
 		if err := grpcServer.Serve(lis); err != nil {
-			fmt.Println(err.Error())
+			slog.Error("Azure plugin server stopped", "error", err)
 		}
 	}()
+	go utils.RegisterPlugin(context.Background(), orchestratorServerAddr, creds, &paragliderpb.RegisterPluginRequest{
+		Name:         utils.AZURE,
+		Address:      registeredAddress,
+		Version:      version.Version(),
+		Capabilities: capabilities,
+	})
 	return azureServer
 }
@@ -165,7 +165,7 @@ func TestCrossNamespaces(t *testing.T) {
 	orchestrator.Setup(orchestratorServerConfig, true)
 
 	// Setup Azure plugin server
-	azureServer := Setup(azureServerPort, orchestratorServerAddr)
+	azureServer := Setup(azureServerPort, orchestratorServerAddr, config.TLS{})
 	ctx := context.Background()
 
 	// Create vm1 in rg1
@@ -283,7 +283,7 @@ func TestMultipleRegionsIntraNamespace(t *testing.T) {
 	orchestrator.Setup(orchestratorServerConfig, true)
 
 	// Setup Azure plugin server
-	azureServer := Setup(azureServerPort, orchestratorServerAddr)
+	azureServer := Setup(azureServerPort, orchestratorServerAddr, config.TLS{})
 	ctx := context.Background()
 
 	// Create 2 VMs in different regions
@@ -400,7 +400,7 @@ func TestAttachResourceIntegration(t *testing.T) {
 	orchestrator.Setup(orchestratorServerConfig, true)
 
 	// Setup Azure plugin server
-	azureServer := Setup(azureServerPort, orchestratorServerAddr)
+	azureServer := Setup(azureServerPort, orchestratorServerAddr, config.TLS{})
 
 	vmLocation := "westus"
 	externalVmParameters := GetTestVmParameters(vmLocation)
@@ -416,7 +416,7 @@ func TestAttachResourceIntegration(t *testing.T) {
 	// Find unused address spaces for external address
 	conn, err := grpc.NewClient(azureServer.orchestratorServerAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
-		utils.Log.Printf("Azure Integration test: Could not dial the orchestrator")
+		t.Logf("Azure Integration test: Could not dial the orchestrator")
 		return
 	}
 	defer conn.Close()
@@ -424,7 +424,7 @@ func TestAttachResourceIntegration(t *testing.T) {
 	reqAddressSpaces := make([]int32, 1)
 	response, err := client.FindUnusedAddressSpaces(context.Background(), &paragliderpb.FindUnusedAddressSpacesRequest{Sizes: reqAddressSpaces})
 	if err != nil {
-		utils.Log.Printf("Failed to find unused address spaces: %v", err)
+		t.Logf("Failed to find unused address spaces: %v", err)
 		return
 	}
 	assert.Greater(t, len(response.AddressSpaces), 0)
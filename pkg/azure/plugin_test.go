@@ -27,6 +27,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	fake "github.com/paraglider-project/paraglider/pkg/fake/orchestrator/rpc"
 	"github.com/paraglider-project/paraglider/pkg/orchestrator"
 	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
@@ -359,6 +360,157 @@ func TestAddPermitListRules(t *testing.T) {
 		require.NotNil(t, resp)
 	})
 
+	// Successful with a deny rule
+	t.Run("Deny Rule", func(t *testing.T) {
+		fakeServerState := &fakeServerState{
+			subId:  subID,
+			rgName: rgName,
+			nsg:    fakeNsg,
+			nic:    fakeNic,
+			vnet:   fakeVnet,
+			vm:     to.Ptr(getFakeVirtualMachine(true)),
+		}
+		fakeServer, ctx := SetupFakeAzureServer(t, fakeServerState)
+		defer Teardown(fakeServer)
+
+		server, _ := setupTestAzurePluginServer()
+		server.orchestratorServerAddr = fakeOrchestratorServerAddr
+		resp, err := server.AddPermitListRules(ctx, &paragliderpb.AddPermitListRulesRequest{
+			Rules: []*paragliderpb.PermitListRule{
+				{
+					Name:      "deny-cloudflare-outbound",
+					Targets:   []string{"1.1.1.1"},
+					SrcPort:   -1,
+					DstPort:   -1,
+					Protocol:  6,
+					Direction: paragliderpb.Direction_OUTBOUND,
+					Action:    paragliderpb.RuleAction_DENY,
+					Priority:  2,
+				},
+			},
+			Namespace: namespace,
+			Resource:  fakeResource,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+	})
+
+	// Successful with a deny rule targeting another cloud without connecting the clouds
+	t.Run("Deny Rule Remote Cloud Target", func(t *testing.T) {
+		fakeServerState := &fakeServerState{
+			subId:  subID,
+			rgName: rgName,
+			nsg:    fakeNsg,
+			nic:    fakeNic,
+			vnet:   fakeVnet,
+			vm:     to.Ptr(getFakeVirtualMachine(true)),
+		}
+		fakeServer, ctx := SetupFakeAzureServer(t, fakeServerState)
+		defer Teardown(fakeServer)
+
+		remoteOrchestratorServer, remoteOrchestratorServerAddr, err := fake.SetupFakeOrchestratorRPCServer(utils.AZURE)
+		require.NoError(t, err)
+		remoteOrchestratorServer.Counter = 1
+		remoteOrchestratorServer.RemoteAddressSpaceMappings = []*paragliderpb.AddressSpaceMapping{
+			{AddressSpaces: []string{"10.250.0.0/16"}, Cloud: utils.GCP, Namespace: namespace},
+		}
+
+		server, _ := setupTestAzurePluginServer()
+		server.orchestratorServerAddr = remoteOrchestratorServerAddr
+		resp, err := server.AddPermitListRules(ctx, &paragliderpb.AddPermitListRulesRequest{
+			Rules: []*paragliderpb.PermitListRule{
+				{
+					Name:      "deny-gcp-outbound",
+					Targets:   []string{"10.250.0.5"},
+					SrcPort:   -1,
+					DstPort:   -1,
+					Protocol:  6,
+					Direction: paragliderpb.Direction_OUTBOUND,
+					Action:    paragliderpb.RuleAction_DENY,
+					Priority:  2,
+				},
+			},
+			Namespace: namespace,
+			Resource:  fakeResource,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		assert.Zero(t, remoteOrchestratorServer.ConnectCloudsCalls)
+	})
+
+	// Successful with an IPv6 target on a dual-stack resource
+	t.Run("IPv6 Target", func(t *testing.T) {
+		dualStackNic := getFakeParagliderInterface()
+		dualStackNic.Properties.IPConfigurations = append(dualStackNic.Properties.IPConfigurations, &armnetwork.InterfaceIPConfiguration{
+			Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+				PrivateIPAddress:        to.Ptr("fd20:a::4"),
+				PrivateIPAddressVersion: to.Ptr(armnetwork.IPVersionIPv6),
+			},
+		})
+		fakeServerState := &fakeServerState{
+			subId:  subID,
+			rgName: rgName,
+			nsg:    fakeNsg,
+			nic:    dualStackNic,
+			vnet:   fakeVnet,
+			vm:     to.Ptr(getFakeVirtualMachine(true)),
+		}
+		fakeServer, ctx := SetupFakeAzureServer(t, fakeServerState)
+		defer Teardown(fakeServer)
+
+		server, _ := setupTestAzurePluginServer()
+		server.orchestratorServerAddr = fakeOrchestratorServerAddr
+		resp, err := server.AddPermitListRules(ctx, &paragliderpb.AddPermitListRulesRequest{
+			Rules: []*paragliderpb.PermitListRule{
+				{
+					Name:      "cloudflare-ipv6-outbound",
+					Targets:   []string{"2606:4700:4700::1111"},
+					SrcPort:   -1,
+					DstPort:   443,
+					Protocol:  6,
+					Direction: paragliderpb.Direction_OUTBOUND,
+				},
+			},
+			Namespace: namespace,
+			Resource:  fakeResource,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+	})
+
+	// Failure with an IPv6 target on an IPv4-only resource
+	t.Run("IPv6 Target Without IPv6 Address", func(t *testing.T) {
+		fakeServerState := &fakeServerState{
+			subId:  subID,
+			rgName: rgName,
+			nsg:    fakeNsg,
+			nic:    fakeNic,
+			vnet:   fakeVnet,
+			vm:     to.Ptr(getFakeVirtualMachine(true)),
+		}
+		fakeServer, ctx := SetupFakeAzureServer(t, fakeServerState)
+		defer Teardown(fakeServer)
+
+		server, _ := setupTestAzurePluginServer()
+		server.orchestratorServerAddr = fakeOrchestratorServerAddr
+		resp, err := server.AddPermitListRules(ctx, &paragliderpb.AddPermitListRulesRequest{
+			Rules: []*paragliderpb.PermitListRule{
+				{
+					Name:      "cloudflare-ipv6-outbound",
+					Targets:   []string{"2606:4700:4700::1111"},
+					SrcPort:   -1,
+					DstPort:   443,
+					Protocol:  6,
+					Direction: paragliderpb.Direction_OUTBOUND,
+				},
+			},
+			Namespace: namespace,
+			Resource:  fakeResource,
+		})
+		require.Error(t, err)
+		require.Nil(t, resp)
+	})
+
 	// Failed while getting NIC
 	t.Run("AddPermitListRules: Failure while getting NIC", func(t *testing.T) {
 		serverState := &fakeServerState{
@@ -540,6 +692,215 @@ func TestDeleteDeletePermitListRules(t *testing.T) {
 	})
 }
 
+func TestDeleteResource(t *testing.T) {
+	fakeNsg := getFakeNsgWithRules(validSecurityGroupID, validSecurityGroupName)
+	req := &paragliderpb.DeleteResourceRequest{
+		Namespace: namespace,
+		Resource:  vmURI,
+	}
+
+	t.Run("DeleteResource: Success", func(t *testing.T) {
+		serverState := &fakeServerState{
+			subId:  subID,
+			rgName: rgName,
+			nsg:    fakeNsg,
+			nic:    getFakeParagliderInterface(),
+			vm:     to.Ptr(getFakeVirtualMachine(true)),
+		}
+		fakeServer, ctx := SetupFakeAzureServer(t, serverState)
+		defer Teardown(fakeServer)
+
+		server, _ := setupTestAzurePluginServer()
+
+		resp, err := server.DeleteResource(ctx, req)
+
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+	})
+
+	t.Run("DeleteResource: Failure while getting NIC", func(t *testing.T) {
+		serverState := &fakeServerState{
+			subId:  subID,
+			rgName: rgName,
+			nsg:    fakeNsg,
+			vm:     to.Ptr(getFakeVirtualMachine(true)),
+		}
+		fakeServer, ctx := SetupFakeAzureServer(t, serverState)
+		defer Teardown(fakeServer)
+
+		server, _ := setupTestAzurePluginServer()
+
+		resp, err := server.DeleteResource(ctx, req)
+
+		require.Error(t, err)
+		require.Nil(t, resp)
+	})
+
+	t.Run("DeleteResource: Fail due to mismatching namespace", func(t *testing.T) {
+		fakeNic := getFakeParagliderInterface()
+		fakeNic.Properties.IPConfigurations[0].Properties.Subnet.ID = to.Ptr(validSubnetId)
+		serverState := &fakeServerState{
+			subId:  subID,
+			rgName: rgName,
+			nsg:    fakeNsg,
+			nic:    fakeNic,
+			vm:     to.Ptr(getFakeVirtualMachine(true)),
+		}
+		fakeServer, ctx := SetupFakeAzureServer(t, serverState)
+		defer Teardown(fakeServer)
+
+		server, _ := setupTestAzurePluginServer()
+
+		resp, err := server.DeleteResource(ctx, req)
+
+		require.Error(t, err)
+		require.Nil(t, resp)
+	})
+}
+
+func TestDetachResource(t *testing.T) {
+	fakeNsg := getFakeNsgWithRules(validSecurityGroupID, validSecurityGroupName)
+	req := &paragliderpb.DetachResourceRequest{
+		Namespace: namespace,
+		Resource:  vmURI,
+	}
+
+	t.Run("DetachResource: Success", func(t *testing.T) {
+		serverState := &fakeServerState{
+			subId:  subID,
+			rgName: rgName,
+			nsg:    fakeNsg,
+			nic:    getFakeParagliderInterface(),
+			vm:     to.Ptr(getFakeVirtualMachine(true)),
+		}
+		fakeServer, ctx := SetupFakeAzureServer(t, serverState)
+		defer Teardown(fakeServer)
+
+		server, _ := setupTestAzurePluginServer()
+
+		resp, err := server.DetachResource(ctx, req)
+
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+	})
+
+	t.Run("DetachResource: Success with attached vnet", func(t *testing.T) {
+		fakeNic := getFakeParagliderInterface()
+		fakeNic.Properties.IPConfigurations[0].Properties.Subnet.ID = to.Ptr(validSubnetId)
+		serverState := &fakeServerState{
+			subId:  subID,
+			rgName: rgName,
+			nsg:    fakeNsg,
+			nic:    fakeNic,
+			vm:     to.Ptr(getFakeVirtualMachine(true)),
+			vnet: &armnetwork.VirtualNetwork{
+				Name:     to.Ptr(validVnetName),
+				Location: to.Ptr(testLocation),
+				Tags: map[string]*string{
+					namespaceTagKey: to.Ptr(namespace),
+				},
+			},
+			vnetPeering: &armnetwork.VirtualNetworkPeering{},
+		}
+		fakeServer, ctx := SetupFakeAzureServer(t, serverState)
+		defer Teardown(fakeServer)
+
+		server, _ := setupTestAzurePluginServer()
+
+		resp, err := server.DetachResource(ctx, req)
+
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		assert.True(t, hasFakeRequest(serverState, "DELETE", "/virtualNetworkPeerings/"))
+		assert.True(t, hasFakeRequest(serverState, "PUT", "/virtualNetworks/"+validVnetName))
+	})
+
+	t.Run("DetachResource: Success with another resource in the attached vnet", func(t *testing.T) {
+		fakeNic := getFakeParagliderInterface()
+		fakeNic.Properties.IPConfigurations[0].Properties.Subnet.ID = to.Ptr(validSubnetId)
+		otherNsgName := "other-nic" + nsgNameSuffix
+		otherNic := &armnetwork.Interface{
+			Name: to.Ptr("other-nic"),
+			Properties: &armnetwork.InterfacePropertiesFormat{
+				NetworkSecurityGroup: &armnetwork.SecurityGroup{ID: to.Ptr(uriPrefix + "Microsoft.Network/networkSecurityGroups/" + otherNsgName)},
+			},
+		}
+		serverState := &fakeServerState{
+			subId:  subID,
+			rgName: rgName,
+			nsg:    fakeNsg,
+			nic:    fakeNic,
+			vm:     to.Ptr(getFakeVirtualMachine(true)),
+			vnet: &armnetwork.VirtualNetwork{
+				Name:     to.Ptr(validVnetName),
+				Location: to.Ptr(testLocation),
+				Tags: map[string]*string{
+					namespaceTagKey: to.Ptr(namespace),
+				},
+				Properties: &armnetwork.VirtualNetworkPropertiesFormat{
+					Subnets: []*armnetwork.Subnet{
+						{
+							ID: to.Ptr(validSubnetId),
+							Properties: &armnetwork.SubnetPropertiesFormat{
+								IPConfigurations: []*armnetwork.IPConfiguration{
+									{ID: to.Ptr(validNicId + "/ipConfigurations/ip-config-name")},
+									{ID: to.Ptr(uriPrefix + "Microsoft.Network/networkInterfaces/other-nic/ipConfigurations/ip-config-name")},
+								},
+							},
+						},
+					},
+				},
+			},
+			vnetPeering: &armnetwork.VirtualNetworkPeering{},
+			nics:        map[string]*armnetwork.Interface{"other-nic": otherNic},
+			nsgs:        map[string]*armnetwork.SecurityGroup{otherNsgName: getFakeNsgWithRules(uriPrefix+"Microsoft.Network/networkSecurityGroups/"+otherNsgName, otherNsgName)},
+		}
+		fakeServer, ctx := SetupFakeAzureServer(t, serverState)
+		defer Teardown(fakeServer)
+
+		server, _ := setupTestAzurePluginServer()
+
+		resp, err := server.DetachResource(ctx, req)
+
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		// The other resource is still attached, so the vnet keeps its peering and tag
+		assert.False(t, hasFakeRequest(serverState, "DELETE", "/virtualNetworkPeerings/"))
+		assert.False(t, hasFakeRequest(serverState, "PUT", "/virtualNetworks/"+validVnetName))
+
+		// Once the other resource is detached too, the vnet is cleaned up
+		serverState.nsgs[otherNsgName].Properties.SecurityRules = nil
+		serverState.requests = nil
+		resp, err = server.DetachResource(ctx, req)
+
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		assert.True(t, hasFakeRequest(serverState, "DELETE", "/virtualNetworkPeerings/"))
+		assert.True(t, hasFakeRequest(serverState, "PUT", "/virtualNetworks/"+validVnetName))
+	})
+
+	t.Run("DetachResource: Fail due to mismatching namespace", func(t *testing.T) {
+		fakeNic := getFakeParagliderInterface()
+		fakeNic.Properties.IPConfigurations[0].Properties.Subnet.ID = to.Ptr(validSubnetId)
+		serverState := &fakeServerState{
+			subId:  subID,
+			rgName: rgName,
+			nsg:    fakeNsg,
+			nic:    fakeNic,
+			vm:     to.Ptr(getFakeVirtualMachine(true)),
+		}
+		fakeServer, ctx := SetupFakeAzureServer(t, serverState)
+		defer Teardown(fakeServer)
+
+		server, _ := setupTestAzurePluginServer()
+
+		resp, err := server.DetachResource(ctx, req)
+
+		require.Error(t, err)
+		require.Nil(t, resp)
+	})
+}
+
 func TestGetUsedAddressSpaces(t *testing.T) {
 	serverState := &fakeServerState{
 		subId:  subID,
@@ -732,6 +1093,33 @@ func TestCreateVpnConnections(t *testing.T) {
 	require.NotNil(t, resp)
 }
 
+func TestDeleteNamespace(t *testing.T) {
+	serverState := &fakeServerState{
+		subId:  subID,
+		rgName: rgName,
+		resources: []*armresources.GenericResourceExpanded{
+			{Name: to.Ptr(getVirtualNetworkGatewayConnectionName(namespace, "cloudname", 0)), Type: to.Ptr(connectionTypeName)},
+			{Name: to.Ptr(getVpnGatewayName(namespace)), Type: to.Ptr(virtualNetworkGatewayTypeName)},
+			{Name: to.Ptr(getVPNGatewayIPAddressName(namespace, 0)), Type: to.Ptr(publicIPAddressTypeName)},
+			{Name: to.Ptr(getLocalNetworkGatewayName(namespace, "cloudname", 0)), Type: to.Ptr(localNetworkGatewayTypeName)},
+			{Name: to.Ptr(getVnetName(testLocation, namespace)), Type: to.Ptr(virtualNetworkTypeName)},
+			{Name: to.Ptr(getNatGatewayName(namespace, testLocation)), Type: to.Ptr(natGatewayTypeName)},
+			{Name: to.Ptr(validVnetName), Type: to.Ptr(virtualNetworkTypeName)}, // Attached vnet
+		},
+	}
+	fakeServer, ctx := SetupFakeAzureServer(t, serverState)
+	defer Teardown(fakeServer)
+
+	server, _ := setupTestAzurePluginServer()
+
+	req := &paragliderpb.DeleteNamespaceRequest{
+		Deployment: &paragliderpb.ParagliderDeployment{Id: deploymentId, Namespace: namespace},
+	}
+	resp, err := server.DeleteNamespace(ctx, req)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+}
+
 func TestAttachResource(t *testing.T) {
 	fakeNsg := getFakeNsgWithRules(validSecurityGroupID, validSecurityGroupName)
 	pluginServer, _ := setupTestAzurePluginServer()
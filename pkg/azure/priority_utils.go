@@ -18,8 +18,14 @@ package azure
 
 import (
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+
+	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
 )
 
+// Number of NSG priorities in each band. Paraglider rules are split in bands by their rank (see utils.RuleRank), in evaluation order.
+const priorityBandSize = (maxPriority - minPriority) / utils.NumRuleRanks
+
 // setupMaps fills the reservedPrioritiesInbound and reservedPrioritiesOutbound maps with the priorities of the existing rules in the NSG
 // This is done to avoid priorities conflicts when creating new rules
 // Existing rules map is filled to ensure that rules that just need their contents updated do not get recreated with new priorities
@@ -60,3 +66,15 @@ func getNextAvailablePriority(reservedPriorities map[int32]*armnetwork.SecurityR
 
 	return i
 }
+
+// getPriorityBand returns the range [start, end) of NSG priorities for rules with the same action and relative priority as the given rule
+func getPriorityBand(rule *paragliderpb.PermitListRule) (int32, int32) {
+	start := minPriority + utils.RuleRank(rule)*priorityBandSize
+	return start, start + priorityBandSize
+}
+
+// getRelativePriority returns the relative priority of the Paraglider rule with the given NSG priority
+func getRelativePriority(priority int32) int32 {
+	rank := (priority - minPriority) / priorityBandSize
+	return utils.PriorityOfRank(max(0, min(rank, utils.NumRuleRanks-1)))
+}
@@ -23,6 +23,9 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 	"github.com/stretchr/testify/assert"
+
+	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
 )
 
 func TestGetNextAvailabilityPriority(t *testing.T) {
@@ -42,3 +45,22 @@ func TestGetNextAvailabilityPriority(t *testing.T) {
 		assert.Equal(t, int32(maxPriority), priority)
 	})
 }
+
+func TestGetPriorityBand(t *testing.T) {
+	allowStart, allowEnd := getPriorityBand(&paragliderpb.PermitListRule{Priority: 1})
+	denyStart, denyEnd := getPriorityBand(&paragliderpb.PermitListRule{Priority: 1, Action: paragliderpb.RuleAction_DENY})
+	lastStart, lastEnd := getPriorityBand(&paragliderpb.PermitListRule{Priority: utils.MaxRulePriority})
+
+	// Deny rules come right before allow rules with the same priority, and all bands fit before the deny all rule
+	assert.Equal(t, denyEnd, allowStart)
+	assert.Less(t, denyStart, allowStart)
+	assert.Equal(t, priorityBandSize, allowEnd-allowStart)
+	assert.LessOrEqual(t, lastEnd, int32(maxPriority))
+
+	assert.Equal(t, int32(1), getRelativePriority(allowStart))
+	assert.Equal(t, int32(1), getRelativePriority(denyEnd-1))
+	assert.Equal(t, utils.MaxRulePriority, getRelativePriority(lastStart))
+	// Rules outside of the bands, such as the ones created before rules had priorities, are clamped
+	assert.Equal(t, utils.MaxRulePriority, getRelativePriority(maxPriority))
+	assert.Equal(t, int32(0), getRelativePriority(minPriority))
+}
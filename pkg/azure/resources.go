@@ -20,10 +20,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
-	utils "github.com/paraglider-project/paraglider/pkg/utils"
+	"github.com/paraglider-project/paraglider/pkg/utils"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
@@ -33,10 +34,11 @@ import (
 )
 
 type resourceNetworkInfo struct {
-	SubnetID string
-	Address  string
-	Location string
-	NSG      *armnetwork.SecurityGroup
+	SubnetID    string
+	Address     string
+	Ipv6Address string // Empty if the resource is IPv4-only
+	Location    string
+	NSG         *armnetwork.SecurityGroup
 }
 
 type resourceInfo struct {
@@ -108,19 +110,19 @@ func GetNetworkInfoFromResource(ctx context.Context, handler *AzureSDKHandler, r
 	// get a generic resource
 	resource, err := handler.GetResource(ctx, resourceID)
 	if err != nil {
-		utils.Log.Printf("An error occured while getting resource %s: %+v", resourceID, err)
+		slog.ErrorContext(ctx, "An error occured while getting resource", "resourceId", resourceID, "error", err)
 		return nil, err
 	}
 
 	// get the network info using network handler
 	resourceHandler, err := getResourceHandler(resourceID)
 	if err != nil {
-		utils.Log.Printf("An error occured while getting the resource handler for resource %s: %+v", resourceID, err)
+		slog.ErrorContext(ctx, "An error occured while getting the resource handler", "resourceId", resourceID, "error", err)
 		return nil, fmt.Errorf("Getting Resource Handler error: %w", err)
 	}
 	networkInfo, err := resourceHandler.getNetworkInfo(ctx, resource, handler)
 	if err != nil {
-		utils.Log.Printf("An error occured while getting network info for resource %s: %+v", resourceID, err)
+		slog.ErrorContext(ctx, "An error occured while getting network info", "resourceId", resourceID, "error", err)
 		return nil, err
 	}
 	return networkInfo, nil
@@ -203,6 +205,8 @@ type AzureResourceHandler interface {
 	getResourceInfoFromDescription(ctx context.Context, resource *paragliderpb.CreateResourceRequest) (*resourceInfo, error)
 	// Reads the resource description and provisions the resource with the given subnet
 	readAndProvisionResource(ctx context.Context, resource *paragliderpb.CreateResourceRequest, subnet *armnetwork.Subnet, resourceInfo *ResourceIDInfo, sdkHandler *AzureSDKHandler, additionalAddressSpaces []string) (string, error)
+	// Deletes the resource and the networking resources Paraglider created for it
+	deleteResource(ctx context.Context, resource *armresources.GenericResource, netInfo *resourceNetworkInfo, sdkHandler *AzureSDKHandler) error
 }
 
 // VM implementation of the AzureResourceHandler interface
@@ -217,16 +221,13 @@ func (r *azureResourceHandlerVM) getNetworkInfo(ctx context.Context, resource *a
 		return nil, fmt.Errorf("failed to read resource.Properties")
 	}
 
-	netprofile := properties["networkProfile"].(map[string]interface{})
-	nicID := netprofile["networkInterfaces"].([]interface{})[0].(map[string]interface{})["id"].(string)
-
-	nicName, err := GetLastSegment(nicID)
+	nicName, err := getVmNicName(properties)
 	if err != nil {
 		return nil, err
 	}
 	nic, err := sdkHandler.GetNetworkInterface(ctx, nicName)
 	if err != nil {
-		utils.Log.Printf("An error occured while getting the network interface:%+v", err)
+		slog.ErrorContext(ctx, "An error occured while getting the network interface", "error", err)
 		return nil, err
 	}
 
@@ -236,19 +237,43 @@ func (r *azureResourceHandlerVM) getNetworkInfo(ctx context.Context, resource *a
 	}
 	nsg, err := sdkHandler.GetSecurityGroup(ctx, nsgName)
 	if err != nil {
-		utils.Log.Printf("An error occured while getting the network security group:%+v", err)
+		slog.ErrorContext(ctx, "An error occured while getting the network security group", "error", err)
 		return nil, err
 	}
 
 	info := resourceNetworkInfo{
-		SubnetID: *nic.Properties.IPConfigurations[0].Properties.Subnet.ID,
-		Address:  *nic.Properties.IPConfigurations[0].Properties.PrivateIPAddress,
-		Location: *resource.Location,
-		NSG:      nsg,
+		SubnetID:    *nic.Properties.IPConfigurations[0].Properties.Subnet.ID,
+		Address:     *nic.Properties.IPConfigurations[0].Properties.PrivateIPAddress,
+		Ipv6Address: getNicIpv6Address(nic),
+		Location:    *resource.Location,
+		NSG:         nsg,
 	}
 	return &info, nil
 }
 
+// Gets the private IPv6 address of a network interface, or an empty string if it doesn't have one
+func getNicIpv6Address(nic *armnetwork.Interface) string {
+	for _, ipConfig := range nic.Properties.IPConfigurations {
+		if ipConfig.Properties.PrivateIPAddressVersion != nil && *ipConfig.Properties.PrivateIPAddressVersion == armnetwork.IPVersionIPv6 && ipConfig.Properties.PrivateIPAddress != nil {
+			return *ipConfig.Properties.PrivateIPAddress
+		}
+	}
+	return ""
+}
+
+// Gets the IPv6 address prefix of a subnet, or an empty string if the subnet is IPv4-only
+func getSubnetIpv6Prefix(subnet *armnetwork.Subnet) string {
+	if subnet.Properties == nil {
+		return ""
+	}
+	for _, addressPrefix := range subnet.Properties.AddressPrefixes {
+		if utils.IsIPv6(*addressPrefix) {
+			return *addressPrefix
+		}
+	}
+	return ""
+}
+
 // Gets the resource information from the description
 func (r *azureResourceHandlerVM) getResourceInfoFromDescription(ctx context.Context, resource *paragliderpb.CreateResourceRequest) (*resourceInfo, error) {
 	vm, err := r.fromResourceDecription(resource.Description)
@@ -284,9 +309,9 @@ func (r *azureResourceHandlerVM) getNetworkRequirements() (bool, int) {
 // Creates a virtual machine with the given subnet
 // Returns the private IP address of the virtual machine
 func (r *azureResourceHandlerVM) createWithNetwork(ctx context.Context, vm *armcompute.VirtualMachine, subnet *armnetwork.Subnet, resourceName string, sdkHandler *AzureSDKHandler, additionalAddressSpaces []string) (string, error) {
-	nic, err := sdkHandler.CreateNetworkInterface(ctx, *subnet.ID, *vm.Location, getParagliderResourceName("nic"))
+	nic, err := sdkHandler.CreateNetworkInterface(ctx, *subnet.ID, *vm.Location, getParagliderResourceName("nic"), getSubnetIpv6Prefix(subnet) != "")
 	if err != nil {
-		utils.Log.Printf("An error occured while creating network interface:%+v", err)
+		slog.ErrorContext(ctx, "An error occured while creating network interface", "error", err)
 		return "", err
 	}
 
@@ -300,7 +325,7 @@ func (r *azureResourceHandlerVM) createWithNetwork(ctx context.Context, vm *armc
 
 	vm, err = sdkHandler.CreateVirtualMachine(ctx, *vm, resourceName)
 	if err != nil {
-		utils.Log.Printf("An error occured while creating the virtual machine:%+v", err)
+		slog.ErrorContext(ctx, "An error occured while creating the virtual machine", "error", err)
 		return "", err
 	}
 
@@ -311,13 +336,55 @@ func (r *azureResourceHandlerVM) createWithNetwork(ctx context.Context, vm *armc
 
 	nic, err = sdkHandler.GetNetworkInterface(ctx, nicName)
 	if err != nil {
-		utils.Log.Printf("An error occured while getting the network interface:%+v", err)
+		slog.ErrorContext(ctx, "An error occured while getting the network interface", "error", err)
 		return "", err
 	}
 
 	return *nic.Properties.IPConfigurations[0].Properties.PrivateIPAddress, nil
 }
 
+// Deletes a virtual machine along with the network interface and NSG created for it
+// The network interface and NSG are only deleted if Paraglider created them (i.e., not for attached resources)
+func (r *azureResourceHandlerVM) deleteResource(ctx context.Context, resource *armresources.GenericResource, netInfo *resourceNetworkInfo, sdkHandler *AzureSDKHandler) error {
+	properties, ok := resource.Properties.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("failed to read resource.Properties")
+	}
+	nicName, err := getVmNicName(properties)
+	if err != nil {
+		return err
+	}
+
+	err = sdkHandler.DeleteVirtualMachine(ctx, *resource.Name)
+	if err != nil {
+		slog.ErrorContext(ctx, "An error occured while deleting the virtual machine", "error", err)
+		return err
+	}
+
+	if strings.HasPrefix(nicName, paragliderPrefix) {
+		err = sdkHandler.DeleteNetworkInterface(ctx, nicName)
+		if err != nil {
+			slog.ErrorContext(ctx, "An error occured while deleting the network interface", "error", err)
+			return err
+		}
+	}
+	if strings.HasPrefix(*netInfo.NSG.Name, paragliderPrefix) {
+		err = sdkHandler.DeleteSecurityGroup(ctx, *netInfo.NSG.Name)
+		if err != nil {
+			slog.ErrorContext(ctx, "An error occured while deleting the network security group", "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// Gets the name of the first network interface of a virtual machine from its generic resource properties
+func getVmNicName(properties map[string]interface{}) (string, error) {
+	netprofile := properties["networkProfile"].(map[string]interface{})
+	nicID := netprofile["networkInterfaces"].([]interface{})[0].(map[string]interface{})["id"].(string)
+	return GetLastSegment(nicID)
+}
+
 // Converts the resource description to a virtual machine object
 func (r *azureResourceHandlerVM) fromResourceDecription(resourceDesc []byte) (*armcompute.VirtualMachine, error) {
 	vm := &armcompute.VirtualMachine{}
@@ -387,7 +454,7 @@ func (r *azureResourceHandlerAKS) getNetworkInfo(ctx context.Context, resource *
 	subnetID := firstProfile["vnetSubnetID"].(string)
 	subnet, err := sdkHandler.GetSubnetByID(context.Background(), subnetID)
 	if err != nil {
-		utils.Log.Printf("An error occured while getting the subnet:%+v", err)
+		slog.ErrorContext(ctx, "An error occured while getting the subnet", "error", err)
 		return nil, err
 	}
 	nsgName, err := GetLastSegment(*subnet.Properties.NetworkSecurityGroup.ID)
@@ -396,7 +463,7 @@ func (r *azureResourceHandlerAKS) getNetworkInfo(ctx context.Context, resource *
 	}
 	nsg, err := sdkHandler.GetSecurityGroup(context.Background(), nsgName)
 	if err != nil {
-		utils.Log.Printf("An error occured while getting the network security group:%+v", err)
+		slog.ErrorContext(ctx, "An error occured while getting the network security group", "error", err)
 		return nil, err
 	}
 
@@ -425,7 +492,7 @@ func (r *azureResourceHandlerAKS) createWithNetwork(ctx context.Context, resourc
 	// Create the AKS cluster
 	_, err := sdkHandler.CreateAKSCluster(ctx, *resource, resourceName)
 	if err != nil {
-		utils.Log.Printf("An error occured while creating the AKS cluster:%+v", err)
+		slog.ErrorContext(ctx, "An error occured while creating the AKS cluster", "error", err)
 		return "", err
 	}
 
@@ -433,19 +500,49 @@ func (r *azureResourceHandlerAKS) createWithNetwork(ctx context.Context, resourc
 	allowedAddrs := map[string]string{"localsubnet": *subnet.Properties.AddressPrefix} // TODO @smcclure20: change with support for kubenet (include pod cidr)
 	nsg, err := sdkHandler.CreateSecurityGroup(ctx, resourceName, *resource.Location, allowedAddrs)
 	if err != nil {
-		utils.Log.Printf("An error occured while creating the network security group:%+v", err)
+		slog.ErrorContext(ctx, "An error occured while creating the network security group", "error", err)
 		return "", err
 	}
 
 	err = sdkHandler.AssociateNSGWithSubnet(ctx, *subnet.ID, *nsg.ID)
 	if err != nil {
-		utils.Log.Printf("An error occured while associating the network security group with the subnet:%+v", err)
+		slog.ErrorContext(ctx, "An error occured while associating the network security group with the subnet", "error", err)
 		return "", err
 	}
 
 	return *subnet.Properties.AddressPrefix, nil // TODO @smcclure20: change with support for kubenet
 }
 
+// Deletes an AKS cluster along with the subnet and NSG created for it
+func (r *azureResourceHandlerAKS) deleteResource(ctx context.Context, resource *armresources.GenericResource, netInfo *resourceNetworkInfo, sdkHandler *AzureSDKHandler) error {
+	err := sdkHandler.DeleteAKSCluster(ctx, *resource.Name)
+	if err != nil {
+		slog.ErrorContext(ctx, "An error occured while deleting the AKS cluster", "error", err)
+		return err
+	}
+
+	// The subnet must be removed before its NSG can be deleted
+	subnetName, err := GetLastSegment(netInfo.SubnetID)
+	if err != nil {
+		return err
+	}
+	if subnetName == getSubnetName(*resource.Name) {
+		err = sdkHandler.DeleteSubnet(ctx, getVnetFromSubnetId(netInfo.SubnetID), subnetName)
+		if err != nil {
+			slog.ErrorContext(ctx, "An error occured while deleting the subnet", "error", err)
+			return err
+		}
+	}
+	if *netInfo.NSG.Name == *resource.Name+nsgNameSuffix {
+		err = sdkHandler.DeleteSecurityGroup(ctx, *netInfo.NSG.Name)
+		if err != nil {
+			slog.ErrorContext(ctx, "An error occured while deleting the network security group", "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
 // Converts the resource description to an AKS cluster object
 func (r *azureResourceHandlerAKS) fromResourceDecription(resourceDesc []byte) (*armcontainerservice.ManagedCluster, error) {
 	aks := &armcontainerservice.ManagedCluster{}
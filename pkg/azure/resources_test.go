@@ -24,6 +24,7 @@ import (
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -278,6 +279,27 @@ func TestAzureVMGetNetworkInfo(t *testing.T) {
 	assert.Equal(t, *netInfo.NSG.ID, *getFakeNSG().ID)
 }
 
+func TestGetNicIpv6Address(t *testing.T) {
+	nic := getFakeParagliderInterface()
+	assert.Empty(t, getNicIpv6Address(nic))
+
+	nic.Properties.IPConfigurations = append(nic.Properties.IPConfigurations, &armnetwork.InterfaceIPConfiguration{
+		Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+			PrivateIPAddress:        to.Ptr("fd20:a::4"),
+			PrivateIPAddressVersion: to.Ptr(armnetwork.IPVersionIPv6),
+		},
+	})
+	assert.Equal(t, "fd20:a::4", getNicIpv6Address(nic))
+}
+
+func TestGetSubnetIpv6Prefix(t *testing.T) {
+	subnet := getFakeParagliderSubnet()
+	assert.Empty(t, getSubnetIpv6Prefix(subnet))
+
+	subnet.Properties.AddressPrefixes = []*string{to.Ptr(validAddressSpace), to.Ptr("fd20:a::/64")}
+	assert.Equal(t, "fd20:a::/64", getSubnetIpv6Prefix(subnet))
+}
+
 func TestAzureVMFromResourceDecription(t *testing.T) {
 	vmHandler := &azureResourceHandlerVM{}
 	fakeVm := getFakeVirtualMachine(false)
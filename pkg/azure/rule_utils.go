@@ -18,10 +18,11 @@ package azure
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
-	utils "github.com/paraglider-project/paraglider/pkg/utils"
 )
 
 const (
@@ -33,7 +34,7 @@ const (
 
 // Checks that the NSG rules are conformant. Such that
 //  1. A deny all rule is present and has the lowest priority (i.e. highest priority number)
-//  2. All rules with higher priority (i.e. lower priority number) are allow rules
+//  2. All rules with higher priority (i.e. lower priority number) are allow rules or Paraglider deny rules
 //
 // Creates a deny all rule if there's none to ensure conformant rules for condition 1. (Given condition 2 is met)
 func CheckSecurityRulesCompliance(ctx context.Context, azureHandler *AzureSDKHandler, nsg *armnetwork.SecurityGroup) (bool, error) {
@@ -41,7 +42,7 @@ func CheckSecurityRulesCompliance(ctx context.Context, azureHandler *AzureSDKHan
 	reservedPrioritiesOutbound := make(map[int32]*armnetwork.SecurityRule)
 	err := setupMaps(reservedPrioritiesInbound, reservedPrioritiesOutbound, nil, nsg)
 	if err != nil {
-		utils.Log.Printf("An error occured during setup: %+v", err)
+		slog.ErrorContext(ctx, "An error occured during setup", "error", err)
 		return false, err
 	}
 
@@ -94,6 +95,11 @@ func validateSecurityRulesConform(reservedPriorities map[int32]*armnetwork.Secur
 			highestAllowPriorityNum = priority
 		}
 
+		// Deny rules from permit lists are ordered among the allow rules, so they don't count as the deny all rule
+		if access == armnetwork.SecurityRuleAccessDeny && isParagliderPermitListRule(rule) {
+			continue
+		}
+
 		if (access == armnetwork.SecurityRuleAccessDeny) && (priority <= lowestDenyPriorityNum) {
 			// Any deny rule must be a deny all rule
 			if !isDenyAllRule(rule) {
@@ -162,6 +168,11 @@ func setupDenyAllRuleWithPriority(priority int32, direction armnetwork.SecurityR
 	}
 }
 
+// Returns true if the rule was created by Paraglider for a permit list rule, false otherwise
+func isParagliderPermitListRule(rule *armnetwork.SecurityRule) bool {
+	return rule.Name != nil && strings.HasPrefix(*rule.Name, paragliderPrefix) && !strings.HasPrefix(*rule.Name, denyAllNsgRulePrefix)
+}
+
 // Returns true if the rule is a deny all rule, false otherwise
 func isDenyAllRule(rule *armnetwork.SecurityRule) bool {
 	var anyDestPrefix, anySourcePrefix bool
@@ -191,8 +202,8 @@ func isDenyAllRule(rule *armnetwork.SecurityRule) bool {
 	}
 
 	return anyDestPrefix && anySourcePrefix &&
-		*rule.Properties.SourcePortRange == azureSecurityRuleAsterisk &&
-		*rule.Properties.DestinationPortRange == azureSecurityRuleAsterisk &&
+		rule.Properties.SourcePortRange != nil && *rule.Properties.SourcePortRange == azureSecurityRuleAsterisk &&
+		rule.Properties.DestinationPortRange != nil && *rule.Properties.DestinationPortRange == azureSecurityRuleAsterisk &&
 		*rule.Properties.Protocol == armnetwork.SecurityRuleProtocolAsterisk &&
 		*rule.Properties.Access == armnetwork.SecurityRuleAccessDeny
 }
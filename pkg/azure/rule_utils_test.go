@@ -104,6 +104,32 @@ func TestValidateSecurityRulesConform(t *testing.T) {
 		// -1 is the priority returned when the rules are out of order
 		assert.Equal(t, int32(-1), priority)
 	})
+
+	t.Run("TestValidateSecurityRulesConform: Paraglider deny rule above allow rule", func(t *testing.T) {
+		inboundDenyAllRule := setupDenyAllRuleWithPriority(maxPriority, inboundDirectionRule)
+		inboundDenyRule := &armnetwork.SecurityRule{
+			Name: to.Ptr(getNSGRuleName("deny-host")),
+			Properties: &armnetwork.SecurityRulePropertiesFormat{
+				Access:                to.Ptr(denyRule),
+				SourceAddressPrefixes: []*string{to.Ptr("10.0.0.5")},
+			},
+		}
+		inboundAllowRule := &armnetwork.SecurityRule{
+			Name: to.Ptr(getNSGRuleName("allow-network")),
+			Properties: &armnetwork.SecurityRulePropertiesFormat{
+				Access:                to.Ptr(allowRule),
+				SourceAddressPrefixes: []*string{to.Ptr("10.0.0.0/8")},
+			},
+		}
+
+		reservedPriorities := make(map[int32]*armnetwork.SecurityRule)
+		reservedPriorities[maxPriority] = inboundDenyAllRule
+		reservedPriorities[int32(200)] = inboundDenyRule
+		reservedPriorities[int32(300)] = inboundAllowRule
+		priority, err := validateSecurityRulesConform(reservedPriorities)
+		assert.Nil(t, err)
+		assert.Equal(t, int32(maxPriority), priority)
+	})
 }
 
 func TestCheckSecurityRulesCompliance(t *testing.T) {
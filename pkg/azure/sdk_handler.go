@@ -20,16 +20,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
-	"strconv"
 	"strings"
 
 	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
 	utils "github.com/paraglider-project/paraglider/pkg/utils"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/credentials"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v4"
@@ -63,6 +66,7 @@ type AzureSDKHandler struct {
 	subscriptionID                         string
 	resourceGroupName                      string
 	paragliderNamespace                    string
+	orchestratorCredentials                credentials.TransportCredentials // Used to dial the orchestrator (plaintext if nil)
 }
 
 type IAzureCredentialGetter interface {
@@ -73,7 +77,6 @@ const (
 	VirtualMachineResourceType = "Microsoft.Compute/virtualMachines"
 	nsgNameSuffix              = "-default-nsg"
 	azureSecurityRuleAsterisk  = "*"
-	permitListPortAny          = -1
 	denyAllNsgRulePrefix       = "paraglider-deny-all"
 	nsgRuleDescriptionPrefix   = "paraglider rule"
 	virtualNetworkResourceID   = "/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/virtualNetworks/%s"
@@ -111,25 +114,39 @@ var azureToParagliderDirection = map[armnetwork.SecurityRuleDirection]paraglider
 	armnetwork.SecurityRuleDirectionOutbound: paragliderpb.Direction_OUTBOUND,
 }
 
+// mapping from paraglider rule action to Azure SecurityRuleAccess
+var paragliderToAzureAccess = map[paragliderpb.RuleAction]armnetwork.SecurityRuleAccess{
+	paragliderpb.RuleAction_ALLOW: armnetwork.SecurityRuleAccessAllow,
+	paragliderpb.RuleAction_DENY:  armnetwork.SecurityRuleAccessDeny,
+}
+
+// mapping from Azure SecurityRuleAccess to paraglider rule action
+var azureToParagliderAccess = map[armnetwork.SecurityRuleAccess]paragliderpb.RuleAction{
+	armnetwork.SecurityRuleAccessAllow: paragliderpb.RuleAction_ALLOW,
+	armnetwork.SecurityRuleAccessDeny:  paragliderpb.RuleAction_DENY,
+}
+
 // InitializeClients initializes the necessary azure clients for the necessary operations
 func (h *AzureSDKHandler) InitializeClients(cred azcore.TokenCredential) error {
+	// Requests to Azure are recorded as spans of the trace of the plugin call making them
+	options := &arm.ClientOptions{ClientOptions: policy.ClientOptions{Transport: &http.Client{Transport: utils.TracingTransport(http.DefaultTransport)}}}
 	var err error
-	h.resourcesClientFactory, err = armresources.NewClientFactory(h.subscriptionID, cred, nil)
+	h.resourcesClientFactory, err = armresources.NewClientFactory(h.subscriptionID, cred, options)
 	if err != nil {
 		return err
 	}
 
-	h.networkClientFactory, err = armnetwork.NewClientFactory(h.subscriptionID, cred, nil)
+	h.networkClientFactory, err = armnetwork.NewClientFactory(h.subscriptionID, cred, options)
 	if err != nil {
 		return err
 	}
 
-	h.computeClientFactory, err = armcompute.NewClientFactory(h.subscriptionID, cred, nil)
+	h.computeClientFactory, err = armcompute.NewClientFactory(h.subscriptionID, cred, options)
 	if err != nil {
 		return err
 	}
 
-	h.containerServiceClientFactory, err = armcontainerservice.NewClientFactory(h.subscriptionID, cred, nil)
+	h.containerServiceClientFactory, err = armcontainerservice.NewClientFactory(h.subscriptionID, cred, options)
 	if err != nil {
 		return err
 	}
@@ -178,7 +195,7 @@ func (h *AzureSDKHandler) GetResource(ctx context.Context, resourceID string) (*
 
 	resource, err := h.resourcesClient.GetByID(ctx, resourceID, apiVersion, &options)
 	if err != nil {
-		utils.Log.Printf("Failed to get resource: %v", err)
+		slog.ErrorContext(ctx, "Failed to get resource", "error", err)
 		return nil, err
 	}
 
@@ -188,7 +205,7 @@ func (h *AzureSDKHandler) GetResource(ctx context.Context, resourceID string) (*
 func (h *AzureSDKHandler) GetNetworkInterface(ctx context.Context, nicName string) (*armnetwork.Interface, error) {
 	nicResponse, err := h.interfacesClient.Get(ctx, h.resourceGroupName, nicName, &armnetwork.InterfacesClientGetOptions{Expand: nil})
 	if err != nil {
-		utils.Log.Printf("Failed to get NIC: %v", err)
+		slog.ErrorContext(ctx, "Failed to get NIC", "nic", nicName, "error", err)
 		return nil, err
 	}
 	resourceNic := &nicResponse.Interface
@@ -198,30 +215,21 @@ func (h *AzureSDKHandler) GetNetworkInterface(ctx context.Context, nicName strin
 // GetPermitListRuleFromNSGRulecurityRule creates a new security rule in a network security group (NSG).
 func (h *AzureSDKHandler) CreateSecurityRuleFromPermitList(ctx context.Context, plRule *paragliderpb.PermitListRule, nsgName string, ruleName string, resourceIpAddress string, priority int32, accessType armnetwork.SecurityRuleAccess) (*armnetwork.SecurityRule, error) {
 	sourceIP, destIP := getIPs(plRule, resourceIpAddress)
-	var srcPort, dstPort string
-
-	if plRule.SrcPort == permitListPortAny {
-		srcPort = azureSecurityRuleAsterisk
-	} else {
-		srcPort = strconv.Itoa(int(plRule.SrcPort))
-	}
-
-	if plRule.DstPort == permitListPortAny {
-		dstPort = azureSecurityRuleAsterisk
-	} else {
-		dstPort = strconv.Itoa(int(plRule.DstPort))
-	}
+	srcPortRange, srcPortRanges := getAzurePortRanges(utils.SrcPortRanges(plRule))
+	dstPortRange, dstPortRanges := getAzurePortRanges(utils.DstPortRanges(plRule))
 
 	securityRule := &armnetwork.SecurityRule{
 		Properties: &armnetwork.SecurityRulePropertiesFormat{
 			Access:                     to.Ptr(accessType),
 			DestinationAddressPrefixes: destIP,
-			DestinationPortRange:       to.Ptr(dstPort),
+			DestinationPortRange:       dstPortRange,
+			DestinationPortRanges:      dstPortRanges,
 			Direction:                  to.Ptr(paragliderToAzureDirection[plRule.Direction]),
 			Priority:                   to.Ptr(priority),
 			Protocol:                   to.Ptr(paragliderToAzureprotocol[plRule.Protocol]),
 			SourceAddressPrefixes:      sourceIP,
-			SourcePortRange:            to.Ptr(srcPort),
+			SourcePortRange:            srcPortRange,
+			SourcePortRanges:           srcPortRanges,
 			Description:                to.Ptr(getRuleDescription(plRule.Tags)),
 		},
 	}
@@ -282,13 +290,12 @@ func (h *AzureSDKHandler) DeleteSecurityRule(ctx context.Context, nsgName string
 		return err
 	}
 
-	resp, err := pollerResp.PollUntilDone(ctx, nil)
+	_, err = pollerResp.PollUntilDone(ctx, nil)
 	if err != nil {
 		return err
 	}
 
-	// resp of type SecurityRulesClientDeleteResponse is currently a placeholder in the sdk
-	utils.Log.Printf("Successfully deleted security rule: %v", resp)
+	slog.DebugContext(ctx, "Successfully deleted security rule", "securityGroup", nsgName, "securityRule", ruleName)
 	return nil
 }
 
@@ -441,26 +448,29 @@ func (h *AzureSDKHandler) ListVirtualNetworkPeerings(ctx context.Context, virtua
 	return virtualNetworkPeerings, nil
 }
 
+// DeleteVirtualNetworkPeering deletes the peering with the given name from the given virtual network
+func (h *AzureSDKHandler) DeleteVirtualNetworkPeering(ctx context.Context, virtualNetworkName string, virtualNetworkPeeringName string) error {
+	pollerResponse, err := h.networkPeeringClient.BeginDelete(ctx, h.resourceGroupName, virtualNetworkName, virtualNetworkPeeringName, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = pollerResponse.PollUntilDone(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 // GetPermitListRuleFromNSGRule returns a permit list rule from a network security group (NSG) rule.
 func (h *AzureSDKHandler) GetPermitListRuleFromNSGRule(rule *armnetwork.SecurityRule) (*paragliderpb.PermitListRule, error) {
-	var srcPort, dstPort int
-	var err error
-	if *rule.Properties.SourcePortRange == azureSecurityRuleAsterisk {
-		srcPort = permitListPortAny
-	} else {
-		srcPort, err = strconv.Atoi(*rule.Properties.SourcePortRange)
-		if err != nil {
-			return nil, fmt.Errorf("cannot convert source port range to int: %v", err)
-		}
+	srcPorts, err := parseAzurePortRanges(rule.Properties.SourcePortRange, rule.Properties.SourcePortRanges)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse source port range: %v", err)
 	}
-
-	if *rule.Properties.DestinationPortRange == azureSecurityRuleAsterisk {
-		dstPort = permitListPortAny
-	} else {
-		dstPort, err = strconv.Atoi(*rule.Properties.DestinationPortRange)
-		if err != nil {
-			return nil, fmt.Errorf("cannot convert destination port range to int: %v", err)
-		}
+	dstPorts, err := parseAzurePortRanges(rule.Properties.DestinationPortRange, rule.Properties.DestinationPortRanges)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse destination port range: %v", err)
 	}
 
 	// create permit list rule object
@@ -468,14 +478,54 @@ func (h *AzureSDKHandler) GetPermitListRuleFromNSGRule(rule *armnetwork.Security
 		Name:      *rule.Name,
 		Targets:   getTargets(rule),
 		Direction: azureToParagliderDirection[*rule.Properties.Direction],
-		SrcPort:   int32(srcPort),
-		DstPort:   int32(dstPort),
 		Protocol:  azureToParagliderProtocol[*rule.Properties.Protocol],
 		Tags:      parseDescriptionTags(rule.Properties.Description),
 	}
+	if rule.Properties.Access != nil {
+		permitListRule.Action = azureToParagliderAccess[*rule.Properties.Access]
+	}
+	if rule.Properties.Priority != nil {
+		permitListRule.Priority = getRelativePriority(*rule.Properties.Priority)
+	}
+	utils.SetSrcPortRanges(permitListRule, srcPorts)
+	utils.SetDstPortRanges(permitListRule, dstPorts)
 	return permitListRule, nil
 }
 
+// Returns the port range of a security rule allowing the given ports (nil for all ports), or its port ranges if there are several
+func getAzurePortRanges(ranges []*paragliderpb.PortRange) (*string, []*string) {
+	if len(ranges) == 0 {
+		return to.Ptr(azureSecurityRuleAsterisk), nil
+	}
+	if len(ranges) == 1 {
+		return to.Ptr(utils.FormatPortRange(ranges[0])), nil
+	}
+	portRanges := make([]*string, len(ranges))
+	for i, portRange := range ranges {
+		portRanges[i] = to.Ptr(utils.FormatPortRange(portRange))
+	}
+	return nil, portRanges
+}
+
+// Returns the ports allowed by the port range or port ranges of a security rule (nil for all ports)
+func parseAzurePortRanges(portRange *string, portRanges []*string) ([]*paragliderpb.PortRange, error) {
+	if portRange != nil {
+		portRanges = append([]*string{portRange}, portRanges...)
+	}
+	ranges := []*paragliderpb.PortRange{}
+	for _, value := range portRanges {
+		if *value == azureSecurityRuleAsterisk {
+			return nil, nil
+		}
+		parsed, err := utils.ParsePortRange(*value)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, parsed)
+	}
+	return ranges, nil
+}
+
 // GetSecurityGroup reutrns the network security group object given the nsg name
 func (h *AzureSDKHandler) GetSecurityGroup(ctx context.Context, nsgName string) (*armnetwork.SecurityGroup, error) {
 	nsgResp, err := h.securityGroupsClient.Get(ctx, h.resourceGroupName, nsgName, &armnetwork.SecurityGroupsClientGetOptions{Expand: nil})
@@ -496,18 +546,27 @@ func (h *AzureSDKHandler) GetParagliderVnet(ctx context.Context, vnetName string
 		if isErrorNotFound(err) {
 			// Create the virtual network if it doesn't exist
 			// Get the address space from the orchestrator service
-			conn, err := grpc.NewClient(orchestratorAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+			conn, err := grpc.NewClient(orchestratorAddr, utils.DialCredentials(h.orchestratorCredentials), utils.TracingDialOption())
 			if err != nil {
-				utils.Log.Printf("could not dial the orchestrator")
+				slog.ErrorContext(ctx, "Could not dial the orchestrator", "error", err)
 				return nil, err
 			}
 			defer conn.Close()
 			client := paragliderpb.NewControllerClient(conn)
-			response, err := client.FindUnusedAddressSpaces(context.Background(), &paragliderpb.FindUnusedAddressSpacesRequest{})
+			response, err := client.FindUnusedAddressSpaces(ctx, &paragliderpb.FindUnusedAddressSpacesRequest{})
+			if err != nil {
+				return nil, err
+			}
+			// The vnet is dual-stack if the orchestrator hands out IPv6 address spaces
+			ipv6Response, err := client.FindUnusedAddressSpaces(ctx, &paragliderpb.FindUnusedAddressSpacesRequest{Ipv6: true})
 			if err != nil {
 				return nil, err
 			}
-			vnet, err := h.CreateParagliderVirtualNetwork(ctx, location, vnetName, response.AddressSpaces[0])
+			ipv6AddressSpace := ""
+			if len(ipv6Response.AddressSpaces) > 0 {
+				ipv6AddressSpace = ipv6Response.AddressSpaces[0]
+			}
+			vnet, err := h.CreateParagliderVirtualNetwork(ctx, location, vnetName, response.AddressSpaces[0], ipv6AddressSpace)
 			return vnet, err
 		} else {
 			// Return the error if it's not ResourceNotFound
@@ -521,15 +580,15 @@ func (h *AzureSDKHandler) GetParagliderVnet(ctx context.Context, vnetName string
 // AddSubnetToParagliderVnet adds a subnet to an paraglider vnet
 func (h *AzureSDKHandler) AddSubnetToParagliderVnet(ctx context.Context, namespace string, vnetName string, subnetName string, orchestratorAddr string) (*armnetwork.Subnet, error) {
 	// Get a new address space
-	conn, err := grpc.NewClient(orchestratorAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(orchestratorAddr, utils.DialCredentials(h.orchestratorCredentials), utils.TracingDialOption())
 	if err != nil {
-		utils.Log.Printf("could not dial the orchestrator")
+		slog.ErrorContext(ctx, "Could not dial the orchestrator", "error", err)
 		return nil, err
 	}
 	defer conn.Close()
 
 	client := paragliderpb.NewControllerClient(conn)
-	response, err := client.FindUnusedAddressSpaces(context.Background(), &paragliderpb.FindUnusedAddressSpacesRequest{})
+	response, err := client.FindUnusedAddressSpaces(ctx, &paragliderpb.FindUnusedAddressSpacesRequest{})
 
 	if err != nil {
 		return nil, err
@@ -556,23 +615,30 @@ func (h *AzureSDKHandler) AddSubnetToParagliderVnet(ctx context.Context, namespa
 }
 
 // CreateParagliderVirtualNetwork creates a new paraglider virtual network with a default subnet with the same address
-// space as the vnet
-func (h *AzureSDKHandler) CreateParagliderVirtualNetwork(ctx context.Context, location string, vnetName string, addressSpace string) (*armnetwork.VirtualNetwork, error) {
+// space as the vnet. If an IPv6 address space is given, the vnet and its default subnet are dual-stack with the
+// subnet taking the first /64 of the IPv6 address space.
+func (h *AzureSDKHandler) CreateParagliderVirtualNetwork(ctx context.Context, location string, vnetName string, addressSpace string, ipv6AddressSpace string) (*armnetwork.VirtualNetwork, error) {
 	// TODO @seankimkdy: delete and consolidate calls to this method with CreateParagliderVirtualNetwork
+	addressPrefixes := []*string{to.Ptr(addressSpace)}
+	subnetProperties := &armnetwork.SubnetPropertiesFormat{AddressPrefix: to.Ptr(addressSpace)}
+	if ipv6AddressSpace != "" {
+		ipv6SubnetPrefix, err := utils.GetIPv6SubnetPrefix(ipv6AddressSpace)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get IPv6 subnet prefix: %w", err)
+		}
+		addressPrefixes = append(addressPrefixes, to.Ptr(ipv6AddressSpace))
+		subnetProperties = &armnetwork.SubnetPropertiesFormat{AddressPrefixes: []*string{to.Ptr(addressSpace), to.Ptr(ipv6SubnetPrefix)}}
+	}
 	parameters := armnetwork.VirtualNetwork{
 		Location: to.Ptr(location),
 		Properties: &armnetwork.VirtualNetworkPropertiesFormat{
 			AddressSpace: &armnetwork.AddressSpace{
-				AddressPrefixes: []*string{
-					to.Ptr(addressSpace),
-				},
+				AddressPrefixes: addressPrefixes,
 			},
 			Subnets: []*armnetwork.Subnet{
 				{
-					Name: to.Ptr("default"),
-					Properties: &armnetwork.SubnetPropertiesFormat{
-						AddressPrefix: to.Ptr(addressSpace),
-					},
+					Name:       to.Ptr("default"),
+					Properties: subnetProperties,
 				},
 			},
 		},
@@ -664,25 +730,41 @@ func (h *AzureSDKHandler) CreateSecurityGroup(ctx context.Context, resourceName
 }
 
 // CreateNetworkInterface creates a new network interface with a dynamic private IP address
-func (h *AzureSDKHandler) CreateNetworkInterface(ctx context.Context, subnetID string, location string, nicName string) (*armnetwork.Interface, error) {
+// (and a dynamic private IPv6 address as well if ipv6 is set)
+func (h *AzureSDKHandler) CreateNetworkInterface(ctx context.Context, subnetID string, location string, nicName string, ipv6 bool) (*armnetwork.Interface, error) {
 	nsg, err := h.CreateSecurityGroup(ctx, nicName, location, map[string]string{})
 	if err != nil {
 		return nil, err
 	}
+	ipConfigurations := []*armnetwork.InterfaceIPConfiguration{
+		{
+			Name: to.Ptr("ipConfig"),
+			Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+				PrivateIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodDynamic),
+				Subnet: &armnetwork.Subnet{
+					ID: to.Ptr(subnetID),
+				},
+			},
+		},
+	}
+	if ipv6 {
+		// Azure requires the IPv4 configuration to be the primary one
+		ipConfigurations[0].Properties.Primary = to.Ptr(true)
+		ipConfigurations = append(ipConfigurations, &armnetwork.InterfaceIPConfiguration{
+			Name: to.Ptr("ipConfigV6"),
+			Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
+				PrivateIPAddressVersion:   to.Ptr(armnetwork.IPVersionIPv6),
+				PrivateIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodDynamic),
+				Subnet: &armnetwork.Subnet{
+					ID: to.Ptr(subnetID),
+				},
+			},
+		})
+	}
 	parameters := armnetwork.Interface{
 		Location: to.Ptr(location),
 		Properties: &armnetwork.InterfacePropertiesFormat{
-			IPConfigurations: []*armnetwork.InterfaceIPConfiguration{
-				{
-					Name: to.Ptr("ipConfig"),
-					Properties: &armnetwork.InterfaceIPConfigurationPropertiesFormat{
-						PrivateIPAllocationMethod: to.Ptr(armnetwork.IPAllocationMethodDynamic),
-						Subnet: &armnetwork.Subnet{
-							ID: to.Ptr(subnetID),
-						},
-					},
-				},
-			},
+			IPConfigurations: ipConfigurations,
 			NetworkSecurityGroup: &armnetwork.SecurityGroup{
 				ID: nsg.ID,
 			},
@@ -733,6 +815,137 @@ func (h *AzureSDKHandler) CreateAKSCluster(ctx context.Context, parameters armco
 	return &resp.ManagedCluster, nil
 }
 
+// DeleteVirtualMachine deletes the virtual machine with the given name
+func (h *AzureSDKHandler) DeleteVirtualMachine(ctx context.Context, vmName string) error {
+	pollerResponse, err := h.virtualMachinesClient.BeginDelete(ctx, h.resourceGroupName, vmName, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = pollerResponse.PollUntilDone(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteAKSCluster deletes the AKS cluster with the given name
+func (h *AzureSDKHandler) DeleteAKSCluster(ctx context.Context, clusterName string) error {
+	pollerResponse, err := h.managedClustersClient.BeginDelete(ctx, h.resourceGroupName, clusterName, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = pollerResponse.PollUntilDone(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteNetworkInterface deletes the network interface with the given name
+func (h *AzureSDKHandler) DeleteNetworkInterface(ctx context.Context, nicName string) error {
+	pollerResponse, err := h.interfacesClient.BeginDelete(ctx, h.resourceGroupName, nicName, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = pollerResponse.PollUntilDone(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteSecurityGroup deletes the network security group with the given name
+func (h *AzureSDKHandler) DeleteSecurityGroup(ctx context.Context, nsgName string) error {
+	pollerResponse, err := h.securityGroupsClient.BeginDelete(ctx, h.resourceGroupName, nsgName, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = pollerResponse.PollUntilDone(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteSubnet deletes the subnet with the given name from the given virtual network
+func (h *AzureSDKHandler) DeleteSubnet(ctx context.Context, virtualNetworkName string, subnetName string) error {
+	pollerResponse, err := h.subnetsClient.BeginDelete(ctx, h.resourceGroupName, virtualNetworkName, subnetName, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = pollerResponse.PollUntilDone(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteNamespaceResources deletes the networking resources (e.g., vnets, VPN gateways, and NAT gateways) Paraglider created for the namespace in the resource group.
+// Resources still using the vnets (e.g., VMs) must be deleted beforehand.
+func (h *AzureSDKHandler) DeleteNamespaceResources(ctx context.Context, namespace string) error {
+	pager := h.resourcesClient.NewListByResourceGroupPager(h.resourceGroupName, &armresources.ClientListByResourceGroupOptions{
+		Filter: to.Ptr(fmt.Sprintf("tagName eq '%s' and tagValue eq '%s'", namespaceTagKey, namespace)),
+	})
+	resourceTypeToNames := make(map[string][]string)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, resource := range page.Value {
+			// Vnets brought in by AttachResource carry the namespace tag too, but aren't named after the namespace
+			if !strings.HasPrefix(*resource.Name, getParagliderNamespacePrefix(namespace)+"-") {
+				continue
+			}
+			resourceTypeToNames[*resource.Type] = append(resourceTypeToNames[*resource.Type], *resource.Name)
+		}
+	}
+
+	// Resources are deleted in dependency order (e.g., connections before the gateways they connect)
+	deletionOrder := []struct {
+		resourceType string
+		delete       func(name string) error
+	}{
+		{connectionTypeName, func(name string) error {
+			poller, err := h.virtualNetworkGatewayConnectionsClient.BeginDelete(ctx, h.resourceGroupName, name, nil)
+			return waitForDeletion(ctx, poller, err)
+		}},
+		{virtualNetworkGatewayTypeName, func(name string) error {
+			poller, err := h.virtualNetworkGatewaysClient.BeginDelete(ctx, h.resourceGroupName, name, nil)
+			return waitForDeletion(ctx, poller, err)
+		}},
+		{localNetworkGatewayTypeName, func(name string) error {
+			poller, err := h.localNetworkGatewaysClient.BeginDelete(ctx, h.resourceGroupName, name, nil)
+			return waitForDeletion(ctx, poller, err)
+		}},
+		{virtualNetworkTypeName, func(name string) error {
+			poller, err := h.virtualNetworksClient.BeginDelete(ctx, h.resourceGroupName, name, nil)
+			return waitForDeletion(ctx, poller, err)
+		}},
+		{natGatewayTypeName, func(name string) error {
+			poller, err := h.natGatewaysClient.BeginDelete(ctx, h.resourceGroupName, name, nil)
+			return waitForDeletion(ctx, poller, err)
+		}},
+		{publicIPAddressTypeName, func(name string) error {
+			poller, err := h.publicIPAddressesClient.BeginDelete(ctx, h.resourceGroupName, name, nil)
+			return waitForDeletion(ctx, poller, err)
+		}},
+	}
+	for _, step := range deletionOrder {
+		for _, name := range resourceTypeToNames[step.resourceType] {
+			if err := step.delete(name); err != nil {
+				return fmt.Errorf("unable to delete %s %s: %w", step.resourceType, name, err)
+			}
+		}
+	}
+	return nil
+}
+
 // GetVnet returns the virtual network with the given name
 func (h *AzureSDKHandler) GetVnet(ctx context.Context, vnetName string) (*armnetwork.VirtualNetwork, error) {
 	vnet, err := h.virtualNetworksClient.Get(ctx, h.resourceGroupName, vnetName, nil)
@@ -963,6 +1176,18 @@ func isErrorNotFound(err error) bool {
 	return ok && azError.StatusCode == http.StatusNotFound
 }
 
+// Waits for a delete operation to finish, treating resources which don't exist as already deleted
+func waitForDeletion[T any](ctx context.Context, poller *runtime.Poller[T], err error) error {
+	if err != nil {
+		if isErrorNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	_, err = poller.PollUntilDone(ctx, nil)
+	return err
+}
+
 // Returns peering name from local vnet to remote vnet
 func getPeeringName(localVnetName string, remoteVnetName string) string {
 	return localVnetName + "-to-" + remoteVnetName
@@ -489,7 +489,15 @@ func TestCreateNetworkInterface(t *testing.T) {
 	// Test case: Success
 	t.Run("CreateNetworkInterface: Success", func(t *testing.T) {
 		// Call the function to test
-		nic, err := handler.CreateNetworkInterface(ctx, "", testLocation, validNicName)
+		nic, err := handler.CreateNetworkInterface(ctx, "", testLocation, validNicName, false)
+
+		require.NoError(t, err)
+		require.NotNil(t, nic)
+	})
+
+	// Test case: Success (dual-stack)
+	t.Run("CreateNetworkInterface: Success Dual-Stack", func(t *testing.T) {
+		nic, err := handler.CreateNetworkInterface(ctx, "", testLocation, validNicName, true)
 
 		require.NoError(t, err)
 		require.NotNil(t, nic)
@@ -512,11 +520,27 @@ func TestCreateParagliderVirtualNetwork(t *testing.T) {
 	// Test case: Success
 	t.Run("CreateParagliderVirtualNetwork: Success", func(t *testing.T) {
 		// Call the function to test
-		vnet, err := handler.CreateParagliderVirtualNetwork(ctx, testLocation, validParagliderVnetName, validAddressSpace)
+		vnet, err := handler.CreateParagliderVirtualNetwork(ctx, testLocation, validParagliderVnetName, validAddressSpace, "")
 
 		require.NoError(t, err)
 		require.NotNil(t, vnet)
 	})
+
+	// Test case: Success (dual-stack)
+	t.Run("CreateParagliderVirtualNetwork: Success Dual-Stack", func(t *testing.T) {
+		vnet, err := handler.CreateParagliderVirtualNetwork(ctx, testLocation, validParagliderVnetName, validAddressSpace, "fd20:a::/48")
+
+		require.NoError(t, err)
+		require.NotNil(t, vnet)
+	})
+
+	// Test case: Failure (invalid IPv6 address space)
+	t.Run("CreateParagliderVirtualNetwork: Failure Invalid IPv6 Address Space", func(t *testing.T) {
+		vnet, err := handler.CreateParagliderVirtualNetwork(ctx, testLocation, validParagliderVnetName, validAddressSpace, "fd20:a::/72")
+
+		require.Error(t, err)
+		require.Nil(t, vnet)
+	})
 }
 
 func TestGetVnet(t *testing.T) {
@@ -645,6 +669,31 @@ func TestGetPermitListRuleFromNSGRule(t *testing.T) {
 		require.Equal(t, expectedRule, result)
 	})
 
+	// Test case: deny rule with a priority
+	t.Run("DenyWithPriority", func(t *testing.T) {
+		start, _ := getPriorityBand(&paragliderpb.PermitListRule{Action: paragliderpb.RuleAction_DENY, Priority: 3})
+		denyRule := &armnetwork.SecurityRule{
+			ID:   to.Ptr("security/rule/id"),
+			Name: to.Ptr("paraglider-rulename"),
+			Properties: &armnetwork.SecurityRulePropertiesFormat{
+				Access:                to.Ptr(armnetwork.SecurityRuleAccessDeny),
+				Direction:             to.Ptr(armnetwork.SecurityRuleDirectionInbound),
+				Priority:              to.Ptr(start + 1),
+				SourcePortRange:       to.Ptr("*"),
+				DestinationPortRange:  to.Ptr("22"),
+				Protocol:              to.Ptr(armnetwork.SecurityRuleProtocolTCP),
+				SourceAddressPrefixes: []*string{to.Ptr("10.5.1.7")},
+			},
+		}
+
+		result, err := handler.GetPermitListRuleFromNSGRule(denyRule)
+
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, paragliderpb.RuleAction_DENY, result.Action)
+		assert.Equal(t, int32(3), result.Priority)
+	})
+
 	// Test case: success, any port
 	t.Run("Success:AnyPort", func(t *testing.T) {
 		anyPortRule := &armnetwork.SecurityRule{
@@ -714,6 +763,48 @@ func TestGetPermitListRuleFromNSGRule(t *testing.T) {
 		// Compare the result with the expected rule
 		require.Equal(t, expectedRule, result)
 	})
+
+	// Test case: success, port ranges
+	t.Run("Success:PortRanges", func(t *testing.T) {
+		portRangeRule := &armnetwork.SecurityRule{
+			ID:   to.Ptr("security/rule/id"),
+			Name: to.Ptr("paraglider-rulename"),
+			Properties: &armnetwork.SecurityRulePropertiesFormat{
+				Direction:                  to.Ptr(armnetwork.SecurityRuleDirectionOutbound),
+				SourcePortRange:            to.Ptr("1000-2000"),
+				DestinationPortRanges:      []*string{to.Ptr("80"), to.Ptr("8000-8100")},
+				Protocol:                   to.Ptr(armnetwork.SecurityRuleProtocolTCP),
+				DestinationAddressPrefixes: []*string{to.Ptr("10.3.1.0")},
+			},
+		}
+
+		// Call the function to test
+		result, err := handler.GetPermitListRuleFromNSGRule(portRangeRule)
+
+		// Expected permit list rule
+		expectedRule := &paragliderpb.PermitListRule{
+			Name:      "paraglider-rulename",
+			Targets:   []string{"10.3.1.0"},
+			Direction: paragliderpb.Direction_OUTBOUND,
+			SrcPorts:  []*paragliderpb.PortRange{{Start: 1000, End: 2000}},
+			DstPorts:  []*paragliderpb.PortRange{{Start: 80, End: 80}, {Start: 8000, End: 8100}},
+			Protocol:  6,
+		}
+
+		require.NoError(t, err)
+		require.NotNil(t, result)
+
+		// Compare the result with the expected rule
+		require.Equal(t, expectedRule, result)
+
+		// Converting the rule back gives the same port ranges
+		srcPortRange, srcPortRanges := getAzurePortRanges(utils.SrcPortRanges(result))
+		dstPortRange, dstPortRanges := getAzurePortRanges(utils.DstPortRanges(result))
+		require.Equal(t, portRangeRule.Properties.SourcePortRange, srcPortRange)
+		require.Nil(t, srcPortRanges)
+		require.Nil(t, dstPortRange)
+		require.Equal(t, portRangeRule.Properties.DestinationPortRanges, dstPortRanges)
+	})
 }
 
 func TestCreateOrUpdateVirtualNetworkGateway(t *testing.T) {
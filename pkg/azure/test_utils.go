@@ -92,6 +92,7 @@ func getFakeServerHandler(fakeServerState *fakeServerState) http.HandlerFunc {
 	// Keep in mind these unit tests should rely as little as possible on the functionality of this fake server.
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		path := r.URL.Path
+		fakeServerState.requests = append(fakeServerState.requests, r.Method+" "+path)
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("unsupported request: %s %s", r.Method, path), http.StatusBadRequest)
@@ -99,6 +100,12 @@ func getFakeServerHandler(fakeServerState *fakeServerState) http.HandlerFunc {
 		}
 		urlPrefix := fmt.Sprintf(urlFormat, fakeServerState.subId, fakeServerState.rgName)
 		switch {
+		// Resources
+		case path == fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/resources", fakeServerState.subId, fakeServerState.rgName):
+			if r.Method == "GET" {
+				sendResponse(w, &armresources.ResourceListResult{Value: fakeServerState.resources})
+				return
+			}
 		// NSGs
 		case strings.HasPrefix(path, urlPrefix+"/Microsoft.Network/networkSecurityGroups/"):
 			if strings.Contains(path, "/securityRules") {
@@ -119,6 +126,10 @@ func getFakeServerHandler(fakeServerState *fakeServerState) http.HandlerFunc {
 					return
 				}
 			} else {
+				if nsg, ok := fakeServerState.nsgs[path[strings.LastIndex(path, "/")+1:]]; ok && r.Method == "GET" {
+					sendResponse(w, nsg)
+					return
+				}
 				if r.Method == "GET" {
 					if fakeServerState.nsg == nil {
 						http.Error(w, "nsg not found", http.StatusNotFound)
@@ -137,6 +148,10 @@ func getFakeServerHandler(fakeServerState *fakeServerState) http.HandlerFunc {
 					sendResponse(w, fakeServerState.nsg) // Return server state NSG so that it can have server-side fields in it
 					return
 				}
+				if r.Method == "DELETE" {
+					w.WriteHeader(http.StatusOK)
+					return
+				}
 			}
 		// VMs
 		case strings.HasPrefix(path, urlPrefix+"/Microsoft.Compute/virtualMachines/"):
@@ -158,8 +173,16 @@ func getFakeServerHandler(fakeServerState *fakeServerState) http.HandlerFunc {
 				sendResponse(w, fakeServerState.vm) // Return server state VM so that it can have server-side fields in it
 				return
 			}
+			if r.Method == "DELETE" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
 		// NICs
 		case strings.HasPrefix(path, urlPrefix+"/Microsoft.Network/networkInterfaces/"):
+			if nic, ok := fakeServerState.nics[path[strings.LastIndex(path, "/")+1:]]; ok && r.Method == "GET" {
+				sendResponse(w, nic)
+				return
+			}
 			if r.Method == "GET" {
 				if fakeServerState.nic == nil {
 					http.Error(w, "nic not found", http.StatusNotFound)
@@ -178,6 +201,10 @@ func getFakeServerHandler(fakeServerState *fakeServerState) http.HandlerFunc {
 				sendResponse(w, nic)
 				return
 			}
+			if r.Method == "DELETE" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
 		// Virtual Networks (and their sub-resources)
 		case strings.HasPrefix(path, urlPrefix+"/Microsoft.Network/virtualNetworks"):
 			if strings.Contains(path, "/virtualNetworkPeerings/") { // VirtualNetworkPeerings
@@ -199,6 +226,14 @@ func getFakeServerHandler(fakeServerState *fakeServerState) http.HandlerFunc {
 					sendResponse(w, peering)
 					return
 				}
+				if r.Method == "DELETE" {
+					if fakeServerState.vnetPeering == nil {
+						http.Error(w, "vnet peering not found", http.StatusNotFound)
+						return
+					}
+					w.WriteHeader(http.StatusOK)
+					return
+				}
 			} else if strings.Contains(path, "/subnets/") { // Subnets
 				if r.Method == "GET" {
 					if fakeServerState.subnet == nil {
@@ -218,6 +253,10 @@ func getFakeServerHandler(fakeServerState *fakeServerState) http.HandlerFunc {
 					sendResponse(w, fakeServerState.subnet) // Return server state subnet so that it can have server-side fields in it
 					return
 				}
+				if r.Method == "DELETE" {
+					w.WriteHeader(http.StatusOK)
+					return
+				}
 			} else {
 				if r.Method == "GET" && strings.HasSuffix(path, "/virtualNetworks") {
 					if fakeServerState.vnet == nil {
@@ -247,6 +286,10 @@ func getFakeServerHandler(fakeServerState *fakeServerState) http.HandlerFunc {
 					sendResponse(w, fakeServerState.vnet) // Return server state vnet so that it can have server-side fields in it
 					return
 				}
+				if r.Method == "DELETE" {
+					w.WriteHeader(http.StatusOK)
+					return
+				}
 			}
 		// VirtualNetworkGateways
 		case strings.HasPrefix(path, urlPrefix+"/Microsoft.Network/virtualNetworkGateways/"):
@@ -268,6 +311,10 @@ func getFakeServerHandler(fakeServerState *fakeServerState) http.HandlerFunc {
 				sendResponse(w, fakeServerState.vpnGw) // Return server state gateway so that it can have server-side fields in it
 				return
 			}
+			if r.Method == "DELETE" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
 		// PublicIPAddresses
 		case strings.HasPrefix(path, urlPrefix+"/Microsoft.Network/publicIPAddresses/"):
 			if r.Method == "GET" {
@@ -288,6 +335,10 @@ func getFakeServerHandler(fakeServerState *fakeServerState) http.HandlerFunc {
 				sendResponse(w, fakeServerState.publicIP) // Return server state public IP so that it can have server-side fields in it
 				return
 			}
+			if r.Method == "DELETE" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
 		// LocalNetworkGateways
 		case strings.HasPrefix(path, urlPrefix+"/Microsoft.Network/localNetworkGateways/"):
 			if r.Method == "GET" {
@@ -308,6 +359,10 @@ func getFakeServerHandler(fakeServerState *fakeServerState) http.HandlerFunc {
 				sendResponse(w, localGateway)
 				return
 			}
+			if r.Method == "DELETE" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
 		// VirtualNetworkGatewayConnections
 		case strings.HasPrefix(path, urlPrefix+"/Microsoft.Network/connections/"):
 			if r.Method == "GET" {
@@ -328,6 +383,10 @@ func getFakeServerHandler(fakeServerState *fakeServerState) http.HandlerFunc {
 				sendResponse(w, vpnConnection)
 				return
 			}
+			if r.Method == "DELETE" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
 		// ManagedClusters
 		case strings.HasPrefix(path, urlPrefix+"/Microsoft.ContainerService/managedClusters/"):
 			if r.Method == "GET" {
@@ -348,6 +407,10 @@ func getFakeServerHandler(fakeServerState *fakeServerState) http.HandlerFunc {
 				sendResponse(w, fakeServerState.cluster) // Return server state cluster so that it can have server-side fields in it
 				return
 			}
+			if r.Method == "DELETE" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
 		// NatGateways
 		case strings.HasPrefix(path, urlPrefix+"/Microsoft.Network/natGateways/"):
 			if r.Method == "GET" {
@@ -368,6 +431,10 @@ func getFakeServerHandler(fakeServerState *fakeServerState) http.HandlerFunc {
 				sendResponse(w, fakeServerState.natGateway)
 				return
 			}
+			if r.Method == "DELETE" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
 		}
 		fmt.Printf("unsupported request: %s %s\n", r.Method, path)
 	})
@@ -389,6 +456,21 @@ type fakeServerState struct {
 	vnetPeering   *armnetwork.VirtualNetworkPeering
 	cluster       *armcontainerservice.ManagedCluster
 	natGateway    *armnetwork.NatGateway
+	resources     []*armresources.GenericResourceExpanded
+	nics          map[string]*armnetwork.Interface     // Additional NICs by name, for resources sharing a vnet
+	nsgs          map[string]*armnetwork.SecurityGroup // Additional NSGs by name, for resources sharing a vnet
+	requests      []string                             // Method and path of every request received
+}
+
+// Returns true if the fake server received a request with the method and a path containing pathPart
+func hasFakeRequest(fakeServerState *fakeServerState, method string, pathPart string) bool {
+	for _, request := range fakeServerState.requests {
+		requestMethod, path, _ := strings.Cut(request, " ")
+		if requestMethod == method && strings.Contains(path, pathPart) {
+			return true
+		}
+	}
+	return false
 }
 
 // Sets up fake http server
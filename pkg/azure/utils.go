@@ -44,6 +44,7 @@ const (
 	virtualNetworkGatewayTypeName = "Microsoft.Network/virtualNetworkGateways"
 	virtualNetworkTypeName        = "Microsoft.Network/virtualNetworks"
 	networkWatcherTypeName        = "Microsoft.Network/networkWatchers"
+	natGatewayTypeName            = "Microsoft.Network/natGateways"
 )
 
 // Gets subscription ID defined in environment variable
@@ -395,6 +396,90 @@ func CreateGatewayVnetPeering(ctx context.Context, azureHandler *AzureSDKHandler
 	return nil
 }
 
+// Delete both directions of the peering between the VPN gateway vnet and the VM vnet created by CreateGatewayVnetPeering.
+// Peerings which do not exist are skipped so that this can be called on partially attached vnets.
+func DeleteGatewayVnetPeering(ctx context.Context, azureHandler *AzureSDKHandler, vnetName string, vpnGwVnetName string) error {
+	peerings := [][]string{{vnetName, getPeeringName(vnetName, vpnGwVnetName)}, {vpnGwVnetName, getPeeringName(vpnGwVnetName, vnetName)}}
+	for _, peering := range peerings {
+		err := azureHandler.DeleteVirtualNetworkPeering(ctx, peering[0], peering[1])
+		if err != nil && !isErrorNotFound(err) {
+			return fmt.Errorf("unable to delete vnet peering %s: %w", peering[1], err)
+		}
+	}
+	return nil
+}
+
+// Returns true if a network interface or subnet in the vnet is secured by an NSG with Paraglider rules (i.e., is still part of a Paraglider deployment).
+// The excluded NSG is skipped since it belongs to the resource being detached.
+func isVnetUsedByParagliderResources(ctx context.Context, azureHandler *AzureSDKHandler, vnet *armnetwork.VirtualNetwork, excludedNsgName string) (bool, error) {
+	if vnet.Properties == nil {
+		return false, nil
+	}
+	nsgHasParagliderRules := map[string]bool{excludedNsgName: false}
+	checkNsg := func(nsgID *string) (bool, error) {
+		if nsgID == nil {
+			return false, nil
+		}
+		nsgName, err := GetLastSegment(*nsgID)
+		if err != nil {
+			return false, err
+		}
+		if hasRules, ok := nsgHasParagliderRules[nsgName]; ok {
+			return hasRules, nil
+		}
+		nsg, err := azureHandler.GetSecurityGroup(ctx, nsgName)
+		if err != nil {
+			return false, fmt.Errorf("unable to get NSG %s: %w", nsgName, err)
+		}
+		hasRules := false
+		if nsg.Properties != nil {
+			for _, rule := range nsg.Properties.SecurityRules {
+				if strings.HasPrefix(*rule.Name, paragliderPrefix) {
+					hasRules = true
+					break
+				}
+			}
+		}
+		nsgHasParagliderRules[nsgName] = hasRules
+		return hasRules, nil
+	}
+
+	for _, subnet := range vnet.Properties.Subnets {
+		if subnet.Properties == nil {
+			continue
+		}
+		// Clusters are secured by the NSG of their subnet
+		if subnet.Properties.NetworkSecurityGroup != nil {
+			inUse, err := checkNsg(subnet.Properties.NetworkSecurityGroup.ID)
+			if err != nil || inUse {
+				return inUse, err
+			}
+		}
+		// VMs are secured by the NSG of their network interface
+		for _, ipConfig := range subnet.Properties.IPConfigurations {
+			if ipConfig.ID == nil {
+				continue
+			}
+			nicName := getNicFromIpConfigId(*ipConfig.ID)
+			if nicName == "" {
+				continue
+			}
+			nic, err := azureHandler.GetNetworkInterface(ctx, nicName)
+			if err != nil {
+				return false, fmt.Errorf("unable to get network interface %s: %w", nicName, err)
+			}
+			if nic.Properties == nil || nic.Properties.NetworkSecurityGroup == nil {
+				continue
+			}
+			inUse, err := checkNsg(nic.Properties.NetworkSecurityGroup.ID)
+			if err != nil || inUse {
+				return inUse, err
+			}
+		}
+	}
+	return false, nil
+}
+
 // Returns true if the specified Vnet's address space overlaps with any of the used address spaces. Otherwise, returns false.
 func DoesVnetOverlapWithParaglider(ctx context.Context, handler *AzureSDKHandler, vnetName string, server *azurePluginServer) (bool, error) {
 	vnetAddressSpace, err := handler.GetVnetAddressSpace(ctx, vnetName)
@@ -492,6 +577,7 @@ func getOrCreateNatGateway(ctx context.Context, handler *AzureSDKHandler, namesp
 			if err != nil {
 				return nil, fmt.Errorf("unable to create NAT gateway: %w", err)
 			}
+			utils.AddSideEffect(ctx, natGatewayName, fmt.Sprintf("created NAT gateway %s", natGatewayName))
 			return natGateway, nil
 		} else {
 			return nil, fmt.Errorf("unable to get NAT gateway: %w", err)
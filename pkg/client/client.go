@@ -18,11 +18,16 @@ package client
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/paraglider-project/paraglider/pkg/orchestrator"
 	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
@@ -34,8 +39,8 @@ type ParagliderControllerClient interface {
 	GetPermitList(namespace string, cloud string, resourceName string) ([]*paragliderpb.PermitListRule, error)
 	AddPermitListRules(namespace string, cloud string, resourceName string, rules []*paragliderpb.PermitListRule) error
 	DeletePermitListRules(namespace string, cloud string, resourceName string, rules []string) error
-	CreateResource(namespace string, cloud string, resourceName string, resource *paragliderpb.ResourceDescriptionString) (map[string]string, error)
-	AttachResource(namespace string, cloud string, resource *orchestrator.ResourceID) (map[string]string, error)
+	CreateResource(namespace string, cloud string, resourceName string, resource *paragliderpb.ResourceDescriptionString) (*paragliderpb.CreateResourceResponse, error)
+	AttachResource(namespace string, cloud string, resource *orchestrator.ResourceID) (*paragliderpb.AttachResourceResponse, error)
 	AddPermitListRulesTag(tag string, rules []*paragliderpb.PermitListRule) error
 	DeletePermitListRulesTag(tag string, rules []string) error
 	GetTag(tag string) (*tagservicepb.TagMapping, error)
@@ -44,11 +49,58 @@ type ParagliderControllerClient interface {
 	DeleteTag(tag string) error
 	DeleteTagMembers(tag string, members []string) error
 	ListNamespaces() (map[string][]config.CloudDeployment, error)
+	ApplyManifest(namespace string, manifest *orchestrator.Manifest) (*orchestrator.ManifestDiff, error)
+	DiffManifest(namespace string, manifest *orchestrator.Manifest) (*orchestrator.ManifestDiff, error)
+	GetDriftReport() (*orchestrator.DriftReport, error)
+	CheckDrift(reconcile bool) (*orchestrator.DriftReport, error)
+	GetOperation(id string) (*orchestrator.Operation, error)
+	ListOperations() ([]*orchestrator.Operation, error)
+	CancelOperation(id string) (*orchestrator.Operation, error)
+	ListAuditEvents(filter orchestrator.AuditFilter) ([]*orchestrator.AuditEvent, error)
+}
+
+const defaultOperationPollInterval = 2 * time.Second
+
+// Credentials authenticate the client with the controller
+type Credentials struct {
+	Token    string `json:"token,omitempty"`    // API token or OIDC ID token sent as a bearer token
+	CertFile string `json:"certFile,omitempty"` // Client certificate (for mutual TLS)
+	KeyFile  string `json:"keyFile,omitempty"`  // Key of the client certificate
+	CAFile   string `json:"caFile,omitempty"`   // CAs which sign the controller's certificate (defaults to the system CAs)
 }
 
 type Client struct {
 	ParagliderControllerClient
-	ControllerAddress string
+	ControllerAddress     string
+	Credentials           Credentials
+	OperationPollInterval time.Duration // How often WaitForOperation polls (defaults to 2 seconds)
+}
+
+// Create the HTTP client to send requests with (configured with the client certificate and CAs, if any)
+func (c *Client) httpClient() (*http.Client, error) {
+	if c.Credentials.CertFile == "" && c.Credentials.CAFile == "" {
+		return &http.Client{}, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.Credentials.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.Credentials.CertFile, c.Credentials.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if c.Credentials.CAFile != "" {
+		caPEM, err := os.ReadFile(c.Credentials.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read CAs: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in %s", c.Credentials.CAFile)
+		}
+	}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}, nil
 }
 
 // Proccess the response from the controller and return the body
@@ -58,7 +110,7 @@ func (c *Client) processResponse(resp *http.Response) ([]byte, error) {
 		return nil, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
 		return bodyBytes, fmt.Errorf("Request failed with status code %d: %s", resp.StatusCode, string(bodyBytes))
 	}
 
@@ -67,19 +119,29 @@ func (c *Client) processResponse(resp *http.Response) ([]byte, error) {
 
 // Send a request to the controller and return the response body
 func (c *Client) sendRequest(url string, method string, body io.Reader) ([]byte, error) {
-	client := &http.Client{}
+	client, err := c.httpClient()
+	if err != nil {
+		return nil, err
+	}
 
 	url = c.ControllerAddress + url
 
-	// Prepend with http to make net/http happy
+	// Prepend with http (or https if TLS is configured) to make net/http happy
 	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
-		url = "http://" + url
+		if c.Credentials.CertFile != "" || c.Credentials.CAFile != "" {
+			url = "https://" + url
+		} else {
+			url = "http://" + url
+		}
 	}
 
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return nil, err
 	}
+	if c.Credentials.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Credentials.Token)
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -144,7 +206,7 @@ func (c *Client) DeletePermitListRules(namespace string, cloud string, resourceN
 }
 
 // Create a resource
-func (c *Client) CreateResource(namespace string, cloud string, resourceName string, resource *paragliderpb.ResourceDescriptionString) (map[string]string, error) {
+func (c *Client) CreateResource(namespace string, cloud string, resourceName string, resource *paragliderpb.ResourceDescriptionString) (*paragliderpb.CreateResourceResponse, error) {
 	path := fmt.Sprintf(orchestrator.GetFormatterString(orchestrator.CreateResourcePUTURL), namespace, cloud, resourceName)
 
 	reqBody, err := json.Marshal(resource)
@@ -157,17 +219,17 @@ func (c *Client) CreateResource(namespace string, cloud string, resourceName str
 		return nil, fmt.Errorf("failed to create resource: %w", err)
 	}
 
-	resourceDict := map[string]string{}
-	err = json.Unmarshal(response, &resourceDict)
+	resourceResp := &paragliderpb.CreateResourceResponse{}
+	err = json.Unmarshal(response, resourceResp)
 	if err != nil {
 		return nil, err
 	}
 
-	return resourceDict, nil
+	return resourceResp, nil
 }
 
 // Attach a resource
-func (c *Client) AttachResource(namespace string, cloud string, resource *orchestrator.ResourceID) (map[string]string, error) {
+func (c *Client) AttachResource(namespace string, cloud string, resource *orchestrator.ResourceID) (*paragliderpb.AttachResourceResponse, error) {
 	path := fmt.Sprintf(orchestrator.GetFormatterString(orchestrator.CreateOrAttachResourcePOSTURL), namespace, cloud)
 
 	reqBody, err := json.Marshal(resource)
@@ -180,13 +242,37 @@ func (c *Client) AttachResource(namespace string, cloud string, resource *orches
 		return nil, fmt.Errorf("failed to attach resource: %w", err)
 	}
 
-	resourceDict := map[string]string{}
-	err = json.Unmarshal(response, &resourceDict)
+	resourceResp := &paragliderpb.AttachResourceResponse{}
+	err = json.Unmarshal(response, resourceResp)
 	if err != nil {
 		return nil, err
 	}
 
-	return resourceDict, nil
+	return resourceResp, nil
+}
+
+// Delete a resource
+func (c *Client) DeleteResource(namespace string, cloud string, resourceName string) error {
+	path := fmt.Sprintf(orchestrator.GetFormatterString(orchestrator.DeleteResourceURL), namespace, cloud, resourceName)
+
+	_, err := c.sendRequest(path, http.MethodDelete, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete resource: %w", err)
+	}
+
+	return nil
+}
+
+// Detach a resource from Paraglider without deleting it
+func (c *Client) DetachResource(namespace string, cloud string, resourceName string) error {
+	path := fmt.Sprintf(orchestrator.GetFormatterString(orchestrator.DetachResourceURL), namespace, cloud, resourceName)
+
+	_, err := c.sendRequest(path, http.MethodPost, nil)
+	if err != nil {
+		return fmt.Errorf("failed to detach resource: %w", err)
+	}
+
+	return nil
 }
 
 // Add permit list rules to a tag
@@ -333,3 +419,371 @@ func (c *Client) ListNamespaces() (map[string][]config.CloudDeployment, error) {
 
 	return namespaces, nil
 }
+
+// Create a namespace with the given cloud deployments
+func (c *Client) CreateNamespace(namespace string, clouds []config.CloudDeployment) error {
+	reqBody, err := json.Marshal(&orchestrator.Namespace{Name: namespace, Clouds: clouds})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.sendRequest(orchestrator.CreateNamespaceURL, http.MethodPost, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create namespace: %w", err)
+	}
+
+	return nil
+}
+
+// Bind a cloud deployment to a namespace
+func (c *Client) SetNamespaceCloud(namespace string, cloud string, deployment string) error {
+	path := fmt.Sprintf(orchestrator.GetFormatterString(orchestrator.SetNamespaceCloudURL), namespace, cloud)
+
+	reqBody, err := json.Marshal(&orchestrator.NamespaceCloud{Deployment: deployment})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.sendRequest(path, http.MethodPut, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to set namespace cloud: %w", err)
+	}
+
+	return nil
+}
+
+// Get the path to delete a namespace
+func getDeleteNamespacePath(namespace string, cascade bool) string {
+	path := fmt.Sprintf(orchestrator.GetFormatterString(orchestrator.DeleteNamespaceURL), namespace)
+	if cascade {
+		path += "?" + orchestrator.CascadeQueryParam + "=true"
+	}
+	return path
+}
+
+// Delete a namespace (and the infrastructure Paraglider created for it in the clouds if cascade is set)
+func (c *Client) DeleteNamespace(namespace string, cascade bool) error {
+	_, err := c.sendRequest(getDeleteNamespacePath(namespace, cascade), http.MethodDelete, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete namespace: %w", err)
+	}
+
+	return nil
+}
+
+// List the cloud plugins known to the controller along with their health
+func (c *Client) ListPlugins() ([]orchestrator.Plugin, error) {
+	respBytes, err := c.sendRequest(orchestrator.ListPluginsURL, http.MethodGet, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	plugins := []orchestrator.Plugin{}
+	err = json.Unmarshal(respBytes, &plugins)
+	if err != nil {
+		return nil, err
+	}
+
+	return plugins, nil
+}
+
+// Send a manifest to the controller and return the changes made (or to be made if dryRun is set)
+func (c *Client) sendManifest(namespace string, manifest *orchestrator.Manifest, dryRun bool) (*orchestrator.ManifestDiff, error) {
+	path := fmt.Sprintf(orchestrator.GetFormatterString(orchestrator.ApplyManifestURL), namespace)
+	if dryRun {
+		path += "?" + orchestrator.DryRunQueryParam + "=true"
+	}
+
+	reqBody, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	respBytes, err := c.sendRequest(path, http.MethodPost, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &orchestrator.ManifestDiff{}
+	err = json.Unmarshal(respBytes, diff)
+	if err != nil {
+		return nil, err
+	}
+
+	return diff, nil
+}
+
+// Bring a namespace to the state described by a manifest and return the changes made
+func (c *Client) ApplyManifest(namespace string, manifest *orchestrator.Manifest) (*orchestrator.ManifestDiff, error) {
+	return c.sendManifest(namespace, manifest, false)
+}
+
+// Get the changes applying a manifest to a namespace would make
+func (c *Client) DiffManifest(namespace string, manifest *orchestrator.Manifest) (*orchestrator.ManifestDiff, error) {
+	return c.sendManifest(namespace, manifest, true)
+}
+
+// Get the latest drift report
+func (c *Client) GetDriftReport() (*orchestrator.DriftReport, error) {
+	respBytes, err := c.sendRequest(orchestrator.DriftURL, http.MethodGet, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &orchestrator.DriftReport{}
+	err = json.Unmarshal(respBytes, report)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// Get the path to check for drift
+func getCheckDriftPath(reconcile bool) string {
+	if reconcile {
+		return orchestrator.CheckDriftURL + "?" + orchestrator.ReconcileQueryParam + "=true"
+	}
+	return orchestrator.CheckDriftURL
+}
+
+// Check for drift now (re-applying the expected rules if reconcile is set)
+func (c *Client) CheckDrift(reconcile bool) (*orchestrator.DriftReport, error) {
+	respBytes, err := c.sendRequest(getCheckDriftPath(reconcile), http.MethodPost, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &orchestrator.DriftReport{}
+	err = json.Unmarshal(respBytes, report)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// List the audit events selected by a filter, oldest first
+func (c *Client) ListAuditEvents(filter orchestrator.AuditFilter) ([]*orchestrator.AuditEvent, error) {
+	path := orchestrator.AuditURL
+	if query := filter.Encode(); query != "" {
+		path += "?" + query
+	}
+	respBytes, err := c.sendRequest(path, http.MethodGet, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []*orchestrator.AuditEvent{}
+	err = json.Unmarshal(respBytes, &events)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Start a request as an operation which runs in the background on the controller
+func (c *Client) startOperation(path string, method string, body io.Reader) (*orchestrator.Operation, error) {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	respBytes, err := c.sendRequest(path+separator+orchestrator.AsyncQueryParam+"=true", method, body)
+	if err != nil {
+		return nil, err
+	}
+
+	operation := &orchestrator.Operation{}
+	err = json.Unmarshal(respBytes, operation)
+	if err != nil {
+		return nil, err
+	}
+
+	return operation, nil
+}
+
+// Start creating a resource (the result of the operation is the created resource)
+func (c *Client) StartCreateResource(namespace string, cloud string, resourceName string, resource *paragliderpb.ResourceDescriptionString) (*orchestrator.Operation, error) {
+	path := fmt.Sprintf(orchestrator.GetFormatterString(orchestrator.CreateResourcePUTURL), namespace, cloud, resourceName)
+
+	reqBody, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.startOperation(path, http.MethodPut, bytes.NewBuffer(reqBody))
+}
+
+// Start attaching a resource (the result of the operation is the attached resource)
+func (c *Client) StartAttachResource(namespace string, cloud string, resource *orchestrator.ResourceID) (*orchestrator.Operation, error) {
+	path := fmt.Sprintf(orchestrator.GetFormatterString(orchestrator.CreateOrAttachResourcePOSTURL), namespace, cloud)
+
+	reqBody, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.startOperation(path, http.MethodPost, bytes.NewBuffer(reqBody))
+}
+
+// Start deleting a resource
+func (c *Client) StartDeleteResource(namespace string, cloud string, resourceName string) (*orchestrator.Operation, error) {
+	path := fmt.Sprintf(orchestrator.GetFormatterString(orchestrator.DeleteResourceURL), namespace, cloud, resourceName)
+	return c.startOperation(path, http.MethodDelete, nil)
+}
+
+// Start detaching a resource
+func (c *Client) StartDetachResource(namespace string, cloud string, resourceName string) (*orchestrator.Operation, error) {
+	path := fmt.Sprintf(orchestrator.GetFormatterString(orchestrator.DetachResourceURL), namespace, cloud, resourceName)
+	return c.startOperation(path, http.MethodPost, nil)
+}
+
+// Start adding permit list rules to a resource
+func (c *Client) StartAddPermitListRules(namespace string, cloud string, resourceName string, rules []*paragliderpb.PermitListRule) (*orchestrator.Operation, error) {
+	path := fmt.Sprintf(orchestrator.GetFormatterString(orchestrator.AddPermitListRulesURL), namespace, cloud, resourceName)
+
+	reqBody, err := json.Marshal(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.startOperation(path, http.MethodPost, bytes.NewBuffer(reqBody))
+}
+
+// Start deleting permit list rules from a resource
+func (c *Client) StartDeletePermitListRules(namespace string, cloud string, resourceName string, rules []string) (*orchestrator.Operation, error) {
+	path := fmt.Sprintf(orchestrator.GetFormatterString(orchestrator.DeletePermitListRulesURL), namespace, cloud, resourceName)
+
+	reqBody, err := json.Marshal(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.startOperation(path, http.MethodPost, bytes.NewBuffer(reqBody))
+}
+
+// Start adding permit list rules to every resource within a tag
+func (c *Client) StartAddPermitListRulesTag(tag string, rules []*paragliderpb.PermitListRule) (*orchestrator.Operation, error) {
+	path := fmt.Sprintf(orchestrator.GetFormatterString(orchestrator.RuleOnTagURL), tag)
+
+	reqBody, err := json.Marshal(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.startOperation(path, http.MethodPost, bytes.NewBuffer(reqBody))
+}
+
+// Start deleting permit list rules from every resource within a tag
+func (c *Client) StartDeletePermitListRulesTag(tag string, rules []string) (*orchestrator.Operation, error) {
+	path := fmt.Sprintf(orchestrator.GetFormatterString(orchestrator.RuleOnTagURL), tag)
+
+	reqBody, err := json.Marshal(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.startOperation(path, http.MethodDelete, bytes.NewBuffer(reqBody))
+}
+
+// Start applying a manifest to a namespace (the result of the operation is the diff which was applied)
+func (c *Client) StartApplyManifest(namespace string, manifest *orchestrator.Manifest) (*orchestrator.Operation, error) {
+	path := fmt.Sprintf(orchestrator.GetFormatterString(orchestrator.ApplyManifestURL), namespace)
+
+	reqBody, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.startOperation(path, http.MethodPost, bytes.NewBuffer(reqBody))
+}
+
+// Start checking for drift (the result of the operation is the drift report)
+func (c *Client) StartCheckDrift(reconcile bool) (*orchestrator.Operation, error) {
+	return c.startOperation(getCheckDriftPath(reconcile), http.MethodPost, nil)
+}
+
+// Start deleting a namespace (and the infrastructure Paraglider created for it in the clouds if cascade is set)
+func (c *Client) StartDeleteNamespace(namespace string, cascade bool) (*orchestrator.Operation, error) {
+	return c.startOperation(getDeleteNamespacePath(namespace, cascade), http.MethodDelete, nil)
+}
+
+// Get the status of an operation
+func (c *Client) GetOperation(id string) (*orchestrator.Operation, error) {
+	path := fmt.Sprintf(orchestrator.GetFormatterString(orchestrator.GetOperationURL), id)
+
+	respBytes, err := c.sendRequest(path, http.MethodGet, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	operation := &orchestrator.Operation{}
+	err = json.Unmarshal(respBytes, operation)
+	if err != nil {
+		return nil, err
+	}
+
+	return operation, nil
+}
+
+// List all operations known to the controller
+func (c *Client) ListOperations() ([]*orchestrator.Operation, error) {
+	respBytes, err := c.sendRequest(orchestrator.ListOperationsURL, http.MethodGet, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	operations := []*orchestrator.Operation{}
+	err = json.Unmarshal(respBytes, &operations)
+	if err != nil {
+		return nil, err
+	}
+
+	return operations, nil
+}
+
+// Cancel a running operation
+func (c *Client) CancelOperation(id string) (*orchestrator.Operation, error) {
+	path := fmt.Sprintf(orchestrator.GetFormatterString(orchestrator.CancelOperationURL), id)
+
+	respBytes, err := c.sendRequest(path, http.MethodPost, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	operation := &orchestrator.Operation{}
+	err = json.Unmarshal(respBytes, operation)
+	if err != nil {
+		return nil, err
+	}
+
+	return operation, nil
+}
+
+// Wait for an operation to finish. An error is returned if it failed or was cancelled.
+func (c *Client) WaitForOperation(ctx context.Context, operation *orchestrator.Operation) (*orchestrator.Operation, error) {
+	pollInterval := c.OperationPollInterval
+	if pollInterval == 0 {
+		pollInterval = defaultOperationPollInterval
+	}
+
+	for !operation.Done() {
+		select {
+		case <-ctx.Done():
+			return operation, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+
+		var err error
+		operation, err = c.GetOperation(operation.Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if operation.Status != orchestrator.OperationSucceeded {
+		return operation, fmt.Errorf("operation %s %s: %s", operation.Id, strings.ToLower(string(operation.Status)), operation.Error)
+	}
+	return operation, nil
+}
@@ -19,12 +19,22 @@ limitations under the License.
 package client
 
 import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	fake "github.com/paraglider-project/paraglider/pkg/fake/orchestrator/rest"
 	"github.com/paraglider-project/paraglider/pkg/orchestrator"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
 	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func setupClientWithFakeOrchestratorServer() Client {
@@ -80,9 +90,11 @@ func TestCreateResource(t *testing.T) {
 
 	resource, err := client.CreateResource(fake.Namespace, fake.CloudName, fake.ResourceName, &paragliderpb.ResourceDescriptionString{Name: fake.ResourceName, Description: fake.ResourceDesc})
 	assert.Nil(t, err)
-	assert.Equal(t, fake.ResourceName, resource["name"])
-	assert.Equal(t, fake.Uri, resource["uri"])
-	assert.Equal(t, fake.Ip, resource["ip"])
+	assert.Equal(t, fake.ResourceName, resource.Name)
+	assert.Equal(t, fake.Uri, resource.Uri)
+	assert.Equal(t, fake.Ip, resource.Ip)
+	require.Len(t, resource.Warnings, 1)
+	assert.Equal(t, fake.ResourceWarning, resource.Warnings[0].Message)
 }
 
 func TestAttachResource(t *testing.T) {
@@ -90,9 +102,23 @@ func TestAttachResource(t *testing.T) {
 
 	resource, err := client.AttachResource(fake.Namespace, fake.CloudName, &orchestrator.ResourceID{Id: fake.Uri})
 	assert.Nil(t, err)
-	assert.Equal(t, fake.Uri, resource["uri"])
-	assert.Equal(t, fake.Ip, resource["ip"])
-	assert.Equal(t, fake.ResourceName, resource["name"])
+	assert.Equal(t, fake.Uri, resource.Uri)
+	assert.Equal(t, fake.Ip, resource.Ip)
+	assert.Equal(t, fake.ResourceName, resource.Name)
+}
+
+func TestDeleteResource(t *testing.T) {
+	client := setupClientWithFakeOrchestratorServer()
+
+	err := client.DeleteResource(fake.Namespace, fake.CloudName, fake.ResourceName)
+	assert.Nil(t, err)
+}
+
+func TestDetachResource(t *testing.T) {
+	client := setupClientWithFakeOrchestratorServer()
+
+	err := client.DetachResource(fake.Namespace, fake.CloudName, fake.ResourceName)
+	assert.Nil(t, err)
 }
 
 func TestGetTag(t *testing.T) {
@@ -152,3 +178,206 @@ func TestSetNamespace(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, fake.GetFakeNamespaces(), namespaces)
 }
+
+func TestCreateNamespace(t *testing.T) {
+	client := setupClientWithFakeOrchestratorServer()
+
+	err := client.CreateNamespace(fake.Namespace, []config.CloudDeployment{{Name: fake.CloudName, Deployment: "deployment"}})
+	assert.Nil(t, err)
+
+	err = client.SetNamespaceCloud(fake.Namespace, fake.CloudName, "deployment")
+	assert.Nil(t, err)
+}
+
+func TestDeleteNamespace(t *testing.T) {
+	client := setupClientWithFakeOrchestratorServer()
+
+	err := client.DeleteNamespace(fake.Namespace, false)
+	assert.Nil(t, err)
+
+	operation, err := client.StartDeleteNamespace(fake.Namespace, true)
+	require.Nil(t, err)
+	assert.Equal(t, orchestrator.OperationSucceeded, operation.Status)
+}
+
+func TestListPlugins(t *testing.T) {
+	client := setupClientWithFakeOrchestratorServer()
+
+	plugins, err := client.ListPlugins()
+
+	assert.Nil(t, err)
+	assert.Equal(t, fake.GetFakePlugins(), plugins)
+}
+
+func TestApplyManifest(t *testing.T) {
+	client := setupClientWithFakeOrchestratorServer()
+
+	manifest := &orchestrator.Manifest{
+		Resources: []orchestrator.ManifestResource{{Cloud: fake.CloudName, Name: fake.ResourceName, PermitList: fake.GetFakePermitListRules()}},
+		Tags:      []orchestrator.ManifestTag{{Name: "tag", ChildTags: []string{"member"}}},
+	}
+	diff, err := client.ApplyManifest(fake.Namespace, manifest)
+	require.Nil(t, err)
+	require.Len(t, diff.Resources, 1)
+	assert.Equal(t, fake.ResourceName, diff.Resources[0].Name)
+	require.Len(t, diff.Tags, 1)
+	assert.Equal(t, []string{"member"}, diff.Tags[0].MembersToAdd)
+
+	diff, err = client.DiffManifest(fake.Namespace, manifest)
+	require.Nil(t, err)
+	assert.Len(t, diff.Resources, 1)
+
+	operation, err := client.StartApplyManifest(fake.Namespace, manifest)
+	require.Nil(t, err)
+	assert.Equal(t, orchestrator.OperationSucceeded, operation.Status)
+	require.Nil(t, json.Unmarshal(operation.Result, diff))
+	assert.Len(t, diff.Tags, 1)
+}
+
+func TestDrift(t *testing.T) {
+	client := setupClientWithFakeOrchestratorServer()
+
+	report, err := client.GetDriftReport()
+	require.Nil(t, err)
+	assert.Equal(t, fake.GetFakeDriftReport(), report)
+
+	report, err = client.CheckDrift(true)
+	require.Nil(t, err)
+	require.Len(t, report.Resources, 1)
+	assert.True(t, report.Resources[0].Reconciled)
+
+	// Query parameters are kept when starting an operation
+	operation, err := client.StartCheckDrift(true)
+	require.Nil(t, err)
+	require.Nil(t, json.Unmarshal(operation.Result, report))
+	assert.True(t, report.Resources[0].Reconciled)
+}
+
+func TestStartCreateResource(t *testing.T) {
+	client := setupClientWithFakeOrchestratorServer()
+
+	operation, err := client.StartCreateResource(fake.Namespace, fake.CloudName, fake.ResourceName, &paragliderpb.ResourceDescriptionString{Name: fake.ResourceName, Description: fake.ResourceDesc})
+	require.Nil(t, err)
+	assert.Equal(t, orchestrator.OperationSucceeded, operation.Status)
+
+	resource := &paragliderpb.CreateResourceResponse{}
+	require.Nil(t, json.Unmarshal(operation.Result, resource))
+	assert.Equal(t, fake.ResourceName, resource.Name)
+	assert.Equal(t, fake.Uri, resource.Uri)
+	require.Len(t, operation.Warnings, 1)
+	assert.Equal(t, fake.ResourceWarning, operation.Warnings[0].Message)
+}
+
+func TestStartDeleteResource(t *testing.T) {
+	client := setupClientWithFakeOrchestratorServer()
+
+	operation, err := client.StartDeleteResource(fake.Namespace, fake.CloudName, fake.ResourceName)
+	require.Nil(t, err)
+	assert.Equal(t, orchestrator.OperationSucceeded, operation.Status)
+
+	// Errors are returned before any operation starts
+	_, err = client.StartDeleteResource(fake.Namespace, "wrongcloud", fake.ResourceName)
+	assert.NotNil(t, err)
+}
+
+func TestGetOperation(t *testing.T) {
+	client := setupClientWithFakeOrchestratorServer()
+
+	operation, err := client.GetOperation(fake.RunningOperationId)
+	require.Nil(t, err)
+	assert.Equal(t, orchestrator.OperationRunning, operation.Status)
+
+	_, err = client.GetOperation("missing")
+	assert.NotNil(t, err)
+}
+
+func TestListOperations(t *testing.T) {
+	client := setupClientWithFakeOrchestratorServer()
+
+	started, err := client.StartAddPermitListRules(fake.Namespace, fake.CloudName, "resourceName", fake.GetFakePermitListRules())
+	require.Nil(t, err)
+
+	operations, err := client.ListOperations()
+	require.Nil(t, err)
+	require.Len(t, operations, 2)
+	assert.Equal(t, fake.RunningOperationId, operations[0].Id)
+	assert.Equal(t, started.Id, operations[1].Id)
+}
+
+func TestCancelOperation(t *testing.T) {
+	client := setupClientWithFakeOrchestratorServer()
+
+	operation, err := client.CancelOperation(fake.RunningOperationId)
+	require.Nil(t, err)
+	assert.Equal(t, fake.RunningOperationId, operation.Id)
+
+	// Finished operations can't be cancelled
+	operation, err = client.StartDetachResource(fake.Namespace, fake.CloudName, fake.ResourceName)
+	require.Nil(t, err)
+	_, err = client.CancelOperation(operation.Id)
+	assert.NotNil(t, err)
+}
+
+func TestWaitForOperation(t *testing.T) {
+	client := setupClientWithFakeOrchestratorServer()
+	client.OperationPollInterval = time.Millisecond
+
+	// Finished operation
+	operation, err := client.StartDeletePermitListRules(fake.Namespace, fake.CloudName, "resourceName", fake.GetFakePermitListRuleNames())
+	require.Nil(t, err)
+	operation, err = client.WaitForOperation(context.Background(), operation)
+	require.Nil(t, err)
+	assert.Equal(t, orchestrator.OperationSucceeded, operation.Status)
+
+	// Failed operation
+	_, err = client.WaitForOperation(context.Background(), &orchestrator.Operation{Id: "failed", Status: orchestrator.OperationFailed, Error: "plugin unavailable"})
+	assert.ErrorContains(t, err, "plugin unavailable")
+
+	// Operation which doesn't finish before the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	operation, err = client.WaitForOperation(ctx, &orchestrator.Operation{Id: fake.RunningOperationId, Status: orchestrator.OperationRunning})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, orchestrator.OperationRunning, operation.Status)
+}
+
+func TestCredentials(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+
+	// Without a scheme, https is used since a CA is configured
+	client := Client{ControllerAddress: server.Listener.Addr().String(), Credentials: Credentials{Token: "secret", CAFile: caFile}}
+	_, err := client.ListNamespaces()
+	require.NoError(t, err)
+
+	client.Credentials.Token = "wrong"
+	_, err = client.ListNamespaces()
+	require.Error(t, err)
+
+	// The server certificate isn't trusted without the CA
+	client = Client{ControllerAddress: server.URL, Credentials: Credentials{Token: "secret"}}
+	_, err = client.ListNamespaces()
+	require.Error(t, err)
+}
+
+func TestListAuditEvents(t *testing.T) {
+	client := setupClientWithFakeOrchestratorServer()
+
+	events, err := client.ListAuditEvents(orchestrator.AuditFilter{})
+	require.Nil(t, err)
+	assert.Equal(t, fake.GetFakeAuditEvents(), events)
+
+	events, err = client.ListAuditEvents(orchestrator.AuditFilter{Principal: "bob", Limit: 10})
+	require.Nil(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, orchestrator.DeleteTagAction, events[0].Action)
+}
@@ -24,7 +24,10 @@ import (
 
 	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
 	"github.com/paraglider-project/paraglider/pkg/tag_service/tagservicepb"
+	"github.com/paraglider-project/paraglider/pkg/utils"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	fake "github.com/paraglider-project/paraglider/pkg/fake/tagservice"
 )
@@ -32,8 +35,18 @@ import (
 const AddressSpaceAddress = "10.0.0.0/16"
 const Asn = 64512
 
+// URI of a resource which doesn't exist (e.g., deleted outside of Paraglider)
+const MissingResourceUri = "missing-resource"
+
 var BgpPeeringIpAddresses = []string{"169.254.21.1", "169.254.22.1"}
 var ExampleRule = &paragliderpb.PermitListRule{Name: "example-rule", Tags: []string{fake.ValidTagName, "1.2.3.4"}, SrcPort: 1, DstPort: 1, Protocol: 1, Direction: paragliderpb.Direction_INBOUND}
+var Capabilities = &paragliderpb.GetCapabilitiesResponse{
+	ResourceTypes:  []paragliderpb.ResourceType{paragliderpb.ResourceType_INSTANCE},
+	AttachResource: true,
+	RuleFeatures:   &paragliderpb.RuleFeatures{PortRanges: true, PortLists: true, Ipv6: true, Icmp: true, DenyRules: true},
+	VpnModes:       []paragliderpb.VpnMode{paragliderpb.VpnMode_BGP, paragliderpb.VpnMode_STATIC},
+	Limits:         &paragliderpb.CapabilityLimits{},
+}
 
 // Mock Cloud Plugin Server
 type fakeCloudPluginServer struct {
@@ -42,6 +55,9 @@ type fakeCloudPluginServer struct {
 }
 
 func (s *fakeCloudPluginServer) GetPermitList(c context.Context, req *paragliderpb.GetPermitListRequest) (*paragliderpb.GetPermitListResponse, error) {
+	if req.Resource == MissingResourceUri {
+		return nil, status.Errorf(codes.NotFound, "resource %s not found", req.Resource)
+	}
 	return &paragliderpb.GetPermitListResponse{Rules: []*paragliderpb.PermitListRule{ExampleRule}}, nil
 }
 
@@ -50,6 +66,9 @@ func (s *fakeCloudPluginServer) AddPermitListRules(c context.Context, req *parag
 }
 
 func (s *fakeCloudPluginServer) DeletePermitListRules(c context.Context, req *paragliderpb.DeletePermitListRulesRequest) (*paragliderpb.DeletePermitListRulesResponse, error) {
+	if req.Resource == MissingResourceUri {
+		return nil, status.Errorf(codes.NotFound, "resource %s not found", req.Resource)
+	}
 	return &paragliderpb.DeletePermitListRulesResponse{}, nil
 }
 
@@ -61,6 +80,18 @@ func (s *fakeCloudPluginServer) AttachResource(c context.Context, req *paraglide
 	return &paragliderpb.AttachResourceResponse{Name: "resource_name", Uri: "resource_uri", Ip: "1.1.1.1"}, nil
 }
 
+func (s *fakeCloudPluginServer) DeleteResource(c context.Context, req *paragliderpb.DeleteResourceRequest) (*paragliderpb.DeleteResourceResponse, error) {
+	return &paragliderpb.DeleteResourceResponse{}, nil
+}
+
+func (s *fakeCloudPluginServer) DetachResource(c context.Context, req *paragliderpb.DetachResourceRequest) (*paragliderpb.DetachResourceResponse, error) {
+	return &paragliderpb.DetachResourceResponse{}, nil
+}
+
+func (s *fakeCloudPluginServer) DeleteNamespace(c context.Context, req *paragliderpb.DeleteNamespaceRequest) (*paragliderpb.DeleteNamespaceResponse, error) {
+	return &paragliderpb.DeleteNamespaceResponse{}, nil
+}
+
 func (s *fakeCloudPluginServer) GetUsedAddressSpaces(c context.Context, req *paragliderpb.GetUsedAddressSpacesRequest) (*paragliderpb.GetUsedAddressSpacesResponse, error) {
 	resp := &paragliderpb.GetUsedAddressSpacesResponse{
 		AddressSpaceMappings: []*paragliderpb.AddressSpaceMapping{
@@ -82,6 +113,10 @@ func (s *fakeCloudPluginServer) GetUsedBgpPeeringIpAddresses(c context.Context,
 	return &paragliderpb.GetUsedBgpPeeringIpAddressesResponse{IpAddresses: BgpPeeringIpAddresses}, nil
 }
 
+func (s *fakeCloudPluginServer) GetCapabilities(c context.Context, req *paragliderpb.GetCapabilitiesRequest) (*paragliderpb.GetCapabilitiesResponse, error) {
+	return Capabilities, nil
+}
+
 func NewFakePluginServer() *fakeCloudPluginServer {
 	s := &fakeCloudPluginServer{}
 	return s
@@ -95,6 +130,7 @@ func SetupFakePluginServer(port int) {
 	grpcServer := grpc.NewServer()
 	paragliderpb.RegisterCloudPluginServer(grpcServer, NewFakePluginServer())
 	tagservicepb.RegisterTagServiceServer(grpcServer, fake.NewFakeTagServer())
+	utils.RegisterHealthService(grpcServer)
 	go func() {
 		if err := grpcServer.Serve(lis); err != nil {
 			fmt.Println(err.Error())
@@ -23,7 +23,10 @@ import (
 	"net"
 
 	"github.com/paraglider-project/paraglider/pkg/kvstore/storepb"
+	"github.com/paraglider-project/paraglider/pkg/utils"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -39,7 +42,7 @@ func (s *FakeKVStoreServer) Get(c context.Context, req *storepb.GetRequest) (*st
 	if req.Key == ValidKey {
 		return &storepb.GetResponse{Value: ValidValue}, nil
 	}
-	return nil, fmt.Errorf("Get: Invalid key")
+	return nil, status.Errorf(codes.NotFound, "key %s not found", req.Key)
 }
 
 func (s *FakeKVStoreServer) Set(c context.Context, req *storepb.SetRequest) (*storepb.SetResponse, error) {
@@ -68,6 +71,7 @@ func SetupFakeTagServer(port int) {
 	}
 	grpcServer := grpc.NewServer()
 	storepb.RegisterKVStoreServer(grpcServer, NewFakeKVStoreServer())
+	utils.RegisterHealthService(grpcServer)
 	go func() {
 		if err := grpcServer.Serve(lis); err != nil {
 			fmt.Println(err.Error())
@@ -22,7 +22,10 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/paraglider-project/paraglider/pkg/orchestrator"
 	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
@@ -39,10 +42,16 @@ const (
 	Ip           = "fakeIp"
 	Uri          = "fakeID"
 	ResourceDesc = "fakeResourceDescription"
+
+	ResourceWarning = "fakeResourceWarning" // Warning returned when creating a resource with PUT
+
+	RunningOperationId = "fakeRunningOperation" // Operation which never finishes on its own
 )
 
 type FakeOrchestratorRESTServer struct {
-	server *httptest.Server
+	server     *httptest.Server
+	lock       sync.Mutex
+	operations []*orchestrator.Operation
 }
 
 func urlMatches(url string, pattern string) bool {
@@ -134,6 +143,50 @@ func ListFakeTagMapping() []*tagservicepb.TagMapping {
 	}
 }
 
+func GetFakeDriftReport() *orchestrator.DriftReport {
+	return &orchestrator.DriftReport{
+		CheckedResources: 2,
+		Resources: []orchestrator.ResourceDrift{
+			{
+				Namespace: Namespace,
+				Cloud:     CloudName,
+				Uri:       Uri,
+				Rules: []orchestrator.RuleDrift{
+					{Name: "name", Status: orchestrator.RuleDriftChanged, ChangedSettings: []string{"protocol"}, ExpectedTargets: []string{"1.1.1.1/32"}, ActualTargets: []string{"2.2.2.2/32"}},
+					{Name: "missing", Status: orchestrator.RuleDriftMissing, ExpectedTargets: []string{"1.1.1.1/32"}, ActualTargets: []string{}},
+					{Name: "unexpected", Status: orchestrator.RuleDriftUnexpected, ExpectedTargets: []string{}, ActualTargets: []string{"2.2.2.2/32"}},
+				},
+			},
+		},
+	}
+}
+
+func GetFakeAuditEvents() []*orchestrator.AuditEvent {
+	return []*orchestrator.AuditEvent{
+		{
+			Id:        "audit-event-1",
+			Time:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Principal: "alice",
+			Action:    orchestrator.AddPermitListRulesOperation,
+			Namespace: Namespace,
+			Cloud:     CloudName,
+			Resource:  ResourceName,
+			Request:   json.RawMessage(`[{"name":"name","tags":["tag"],"src_port":1,"dst_port":2,"protocol":6}]`),
+			Status:    orchestrator.AuditSucceeded,
+			Resources: []orchestrator.AuditResource{{Cloud: CloudName, Namespace: Namespace, Uri: Uri, Method: "AddPermitListRules"}},
+		},
+		{
+			Id:        "audit-event-2",
+			Time:      time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			Principal: "bob",
+			Action:    orchestrator.DeleteTagAction,
+			Tag:       "tag",
+			Status:    orchestrator.AuditFailed,
+			Error:     "tag not found",
+		},
+	}
+}
+
 func GetFakeNamespaces() map[string][]config.CloudDeployment {
 	return map[string][]config.CloudDeployment{
 		"namespace1": {
@@ -145,6 +198,19 @@ func GetFakeNamespaces() map[string][]config.CloudDeployment {
 	}
 }
 
+func GetFakePlugins() []orchestrator.Plugin {
+	return []orchestrator.Plugin{
+		{
+			Name:         CloudName,
+			Address:      "localhost:1000",
+			Version:      "v1",
+			Capabilities: []string{"CreateResource"},
+			Registered:   true,
+			Status:       orchestrator.PluginHealthy,
+		},
+	}
+}
+
 func (s *FakeOrchestratorRESTServer) writeResponse(w http.ResponseWriter, resp any) error {
 	bytes, err := json.Marshal(resp)
 	if err != nil {
@@ -157,6 +223,63 @@ func (s *FakeOrchestratorRESTServer) writeResponse(w http.ResponseWriter, resp a
 	return nil
 }
 
+// Serves the operation endpoints and runs requests with async=true as operations which finish immediately
+func (s *FakeOrchestratorRESTServer) handleOperations(handler http.Handler) http.Handler {
+	s.operations = []*orchestrator.Operation{{Id: RunningOperationId, Type: orchestrator.CreateResourceOperation, Status: orchestrator.OperationRunning}}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		path := r.URL.Path
+		switch {
+		case urlMatches(path, orchestrator.ListOperationsURL) && r.Method == http.MethodGet:
+			if err := s.writeResponse(w, s.operations); err != nil {
+				http.Error(w, fmt.Sprintf("error writing response: %s", err), http.StatusInternalServerError)
+			}
+			return
+		case (urlMatches(path, orchestrator.GetOperationURL) && r.Method == http.MethodGet) || (urlMatches(path, orchestrator.CancelOperationURL) && r.Method == http.MethodPost):
+			id := strings.Split(path, "/")[2]
+			for _, operation := range s.operations {
+				if operation.Id == id {
+					if r.Method == http.MethodPost && operation.Done() {
+						http.Error(w, fmt.Sprintf("operation %s has already finished", id), http.StatusBadRequest)
+						return
+					}
+					if err := s.writeResponse(w, operation); err != nil {
+						http.Error(w, fmt.Sprintf("error writing response: %s", err), http.StatusInternalServerError)
+					}
+					return
+				}
+			}
+			http.Error(w, fmt.Sprintf("operation %s not found", id), http.StatusNotFound)
+			return
+		}
+
+		if async, _ := strconv.ParseBool(r.URL.Query().Get(orchestrator.AsyncQueryParam)); !async {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, r)
+		if recorder.Code != http.StatusOK {
+			http.Error(w, recorder.Body.String(), recorder.Code)
+			return
+		}
+		operation := &orchestrator.Operation{Id: fmt.Sprintf("fakeOperation%d", len(s.operations)), Status: orchestrator.OperationSucceeded}
+		if recorder.Body.Len() > 0 {
+			operation.Result = recorder.Body.Bytes()
+			// The notices of the request are reported with the operation as well
+			_ = json.Unmarshal(operation.Result, &operation.RequestNotices)
+		}
+		s.operations = append(s.operations, operation)
+		w.WriteHeader(http.StatusAccepted)
+		if err := s.writeResponse(w, operation); err != nil {
+			http.Error(w, fmt.Sprintf("error writing response: %s", err), http.StatusInternalServerError)
+		}
+	})
+}
+
 func (s *FakeOrchestratorRESTServer) SetupFakeOrchestratorRESTServer() string {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		path := r.URL.Path
@@ -175,6 +298,44 @@ func (s *FakeOrchestratorRESTServer) SetupFakeOrchestratorRESTServer() string {
 				return
 			}
 			return
+		// List Plugins
+		case urlMatches(path, orchestrator.ListPluginsURL) && r.Method == http.MethodGet:
+			err := s.writeResponse(w, GetFakePlugins())
+			if err != nil {
+				http.Error(w, fmt.Sprintf("error writing response: %s", err), http.StatusInternalServerError)
+			}
+			return
+		// Create Namespace
+		case urlMatches(path, orchestrator.CreateNamespaceURL) && r.Method == http.MethodPost:
+			namespace := &orchestrator.Namespace{}
+			err := json.Unmarshal(body, namespace)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("error unmarshalling request body: %s", err), http.StatusBadRequest)
+				return
+			}
+			err = s.writeResponse(w, namespace)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("error writing response: %s", err), http.StatusInternalServerError)
+			}
+			return
+		// Set Namespace Cloud
+		case urlMatches(path, orchestrator.SetNamespaceCloudURL) && r.Method == http.MethodPut:
+			namespaceCloud := &orchestrator.NamespaceCloud{}
+			err := json.Unmarshal(body, namespaceCloud)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("error unmarshalling request body: %s", err), http.StatusBadRequest)
+				return
+			}
+			params := getURLParams(path, orchestrator.SetNamespaceCloudURL)
+			err = s.writeResponse(w, &orchestrator.Namespace{Name: params["namespace"], Clouds: []config.CloudDeployment{{Name: params["cloud"], Deployment: namespaceCloud.Deployment}}})
+			if err != nil {
+				http.Error(w, fmt.Sprintf("error writing response: %s", err), http.StatusInternalServerError)
+			}
+			return
+		// Delete Namespace
+		case urlMatches(path, orchestrator.DeleteNamespaceURL) && r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusOK)
+			return
 		// Create Resources (PUT)
 		case urlMatches(path, orchestrator.CreateResourcePUTURL) && r.Method == http.MethodPut:
 			resource := &paragliderpb.ResourceDescriptionString{}
@@ -182,7 +343,12 @@ func (s *FakeOrchestratorRESTServer) SetupFakeOrchestratorRESTServer() string {
 			if err != nil {
 				http.Error(w, fmt.Sprintf("error unmarshalling request body: %s", err), http.StatusBadRequest)
 			}
-			err = s.writeResponse(w, &paragliderpb.CreateResourceResponse{Name: strings.Split(path, "/")[len(strings.Split(path, "/"))-1], Uri: Uri, Ip: Ip})
+			err = s.writeResponse(w, &paragliderpb.CreateResourceResponse{
+				Name:     strings.Split(path, "/")[len(strings.Split(path, "/"))-1],
+				Uri:      Uri,
+				Ip:       Ip,
+				Warnings: []*paragliderpb.Notice{{Message: ResourceWarning, Resource: Uri}},
+			})
 			if err != nil {
 				http.Error(w, fmt.Sprintf("error writing response: %s", err), http.StatusInternalServerError)
 			}
@@ -224,6 +390,14 @@ func (s *FakeOrchestratorRESTServer) SetupFakeOrchestratorRESTServer() string {
 				}
 			}
 			return
+		// Delete Resource
+		case urlMatches(path, orchestrator.DeleteResourceURL) && r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusOK)
+			return
+		// Detach Resource
+		case urlMatches(path, orchestrator.DetachResourceURL) && r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusOK)
+			return
 		// Add Permit List Rules
 		case urlMatches(path, orchestrator.AddPermitListRulesURL) && (r.Method == http.MethodPost):
 			rules := []*paragliderpb.PermitListRule{}
@@ -232,6 +406,18 @@ func (s *FakeOrchestratorRESTServer) SetupFakeOrchestratorRESTServer() string {
 				http.Error(w, fmt.Sprintf("error unmarshalling request body: %s", err), http.StatusBadRequest)
 				return
 			}
+			// Targets are ignored like the controller does
+			notices := &orchestrator.RequestNotices{}
+			for _, rule := range rules {
+				if len(rule.Targets) != 0 {
+					notices.Warnings = append(notices.Warnings, &paragliderpb.Notice{Message: fmt.Sprintf("targets for rule %s ignored", rule.Name), Resource: Uri})
+				}
+			}
+			if len(notices.Warnings) != 0 {
+				if err := s.writeResponse(w, notices); err != nil {
+					http.Error(w, fmt.Sprintf("error writing response: %s", err), http.StatusInternalServerError)
+				}
+			}
 			return
 		// Delete Permit List Rules
 		case urlMatches(path, orchestrator.DeletePermitListRulesURL) && (r.Method == http.MethodPost):
@@ -291,6 +477,57 @@ func (s *FakeOrchestratorRESTServer) SetupFakeOrchestratorRESTServer() string {
 				return
 			}
 			return
+		// Apply Manifest (every resource and tag in the manifest is reported as new)
+		case urlMatches(path, orchestrator.ApplyManifestURL) && r.Method == http.MethodPost:
+			manifest := &orchestrator.Manifest{}
+			err := json.Unmarshal(body, manifest)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("error unmarshalling request body: %s", err), http.StatusBadRequest)
+				return
+			}
+			diff := &orchestrator.ManifestDiff{}
+			for _, resource := range manifest.Resources {
+				diff.Resources = append(diff.Resources, orchestrator.ResourceDiff{Cloud: resource.Cloud, Name: resource.Name, Create: len(resource.Description) > 0, RulesToAdd: resource.PermitList})
+			}
+			for _, tag := range manifest.Tags {
+				diff.Tags = append(diff.Tags, orchestrator.TagDiff{Name: tag.Name, MembersToAdd: tag.ChildTags})
+			}
+			err = s.writeResponse(w, diff)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("error writing response: %s", err), http.StatusInternalServerError)
+			}
+			return
+		// Get Drift Report
+		case urlMatches(path, orchestrator.DriftURL) && r.Method == http.MethodGet:
+			err := s.writeResponse(w, GetFakeDriftReport())
+			if err != nil {
+				http.Error(w, fmt.Sprintf("error writing response: %s", err), http.StatusInternalServerError)
+			}
+			return
+		// Check Drift
+		case urlMatches(path, orchestrator.CheckDriftURL) && r.Method == http.MethodPost:
+			report := GetFakeDriftReport()
+			if reconcile, _ := strconv.ParseBool(r.URL.Query().Get(orchestrator.ReconcileQueryParam)); reconcile {
+				report.Resources[0].Reconciled = true
+			}
+			err := s.writeResponse(w, report)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("error writing response: %s", err), http.StatusInternalServerError)
+			}
+			return
+		// List Audit Events
+		case urlMatches(path, orchestrator.AuditURL) && r.Method == http.MethodGet:
+			events := []*orchestrator.AuditEvent{}
+			for _, event := range GetFakeAuditEvents() {
+				if principal := r.URL.Query().Get(orchestrator.AuditPrincipalQueryParam); principal == "" || event.Principal == principal {
+					events = append(events, event)
+				}
+			}
+			err := s.writeResponse(w, events)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("error writing response: %s", err), http.StatusInternalServerError)
+			}
+			return
 		// Tag List
 		case urlMatches(path, orchestrator.ListTagURL):
 			if r.Method == http.MethodGet {
@@ -345,8 +582,8 @@ func (s *FakeOrchestratorRESTServer) SetupFakeOrchestratorRESTServer() string {
 		http.Error(w, fmt.Sprintf("unsupported request: %s %s", r.Method, path), http.StatusBadRequest)
 	})
 
-	server := httptest.NewServer(handler)
-	return server.URL
+	s.server = httptest.NewServer(s.handleOperations(handler))
+	return s.server.URL
 }
 
 func (s *FakeOrchestratorRESTServer) TeardownServer() {
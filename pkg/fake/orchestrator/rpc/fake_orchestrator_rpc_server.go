@@ -25,6 +25,8 @@ import (
 	"github.com/paraglider-project/paraglider/pkg/orchestrator"
 	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -35,7 +37,11 @@ type FakeOrchestratorRPCServer struct {
 	paragliderpb.UnimplementedControllerServer
 	Cloud   string
 	Counter int
+	Ipv6    bool // Whether IPv6 address spaces are handed out (i.e., namespaces are dual-stack)
 	kvStore map[string]string
+
+	RemoteAddressSpaceMappings []*paragliderpb.AddressSpaceMapping // Address spaces of other clouds reported alongside the plugin's own
+	ConnectCloudsCalls         int                                 // Number of ConnectClouds requests received
 }
 
 func (f *FakeOrchestratorRPCServer) FindUnusedAddressSpaces(ctx context.Context, req *paragliderpb.FindUnusedAddressSpacesRequest) (*paragliderpb.FindUnusedAddressSpacesResponse, error) {
@@ -43,12 +49,18 @@ func (f *FakeOrchestratorRPCServer) FindUnusedAddressSpaces(ctx context.Context,
 	if req.Sizes != nil {
 		numAddresses = len(req.Sizes)
 	}
+	if req.Ipv6 && !f.Ipv6 {
+		return &paragliderpb.FindUnusedAddressSpacesResponse{}, nil
+	}
 	addresses := make([]string, numAddresses)
 	for i := 0; i < numAddresses; i++ {
 		if f.Counter == 256 {
 			return nil, fmt.Errorf("ran out of address spaces")
 		}
 		address := fmt.Sprintf("10.%d.0.0/16", f.Counter)
+		if req.Ipv6 {
+			address = fmt.Sprintf("fd20:a:%x::/48", f.Counter)
+		}
 		f.Counter = f.Counter + 1
 		addresses[i] = address
 	}
@@ -69,10 +81,16 @@ func (f *FakeOrchestratorRPCServer) GetUsedAddressSpaces(ctx context.Context, _
 			{AddressSpaces: addressSpaces, Cloud: f.Cloud, Namespace: "default", Deployment: proto.String("test-deployment")},
 		},
 	}
+	resp.AddressSpaceMappings = append(resp.AddressSpaceMappings, f.RemoteAddressSpaceMappings...)
 
 	return resp, nil
 }
 
+func (f *FakeOrchestratorRPCServer) ConnectClouds(ctx context.Context, _ *paragliderpb.ConnectCloudsRequest) (*paragliderpb.ConnectCloudsResponse, error) {
+	f.ConnectCloudsCalls++
+	return &paragliderpb.ConnectCloudsResponse{}, nil
+}
+
 func (f *FakeOrchestratorRPCServer) SetValue(ctx context.Context, in *paragliderpb.SetValueRequest) (*paragliderpb.SetValueResponse, error) {
 	fullKey := kvstore.GetFullKey(in.Key, in.Cloud, in.Namespace)
 	f.kvStore[fullKey] = in.Value
@@ -86,7 +104,7 @@ func (f *FakeOrchestratorRPCServer) GetValue(ctx context.Context, in *paraglider
 		return &paragliderpb.GetValueResponse{Value: val}, nil
 	}
 
-	return &paragliderpb.GetValueResponse{}, nil
+	return nil, status.Errorf(codes.NotFound, "key %s not found", in.Key)
 }
 
 func (f *FakeOrchestratorRPCServer) DeleteValue(ctx context.Context, in *paragliderpb.DeleteValueRequest) (*paragliderpb.DeleteValueResponse, error) {
@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"github.com/paraglider-project/paraglider/pkg/tag_service/tagservicepb"
+	"github.com/paraglider-project/paraglider/pkg/utils"
 	"google.golang.org/grpc"
 )
 
@@ -66,12 +67,20 @@ func (s *FakeTagServiceServer) ResolveTag(c context.Context, req *tagservicepb.R
 	return nil, fmt.Errorf("ResolveTag: Invalid tag name")
 }
 
+func (s *FakeTagServiceServer) ListTags(c context.Context, req *tagservicepb.ListTagsRequest) (*tagservicepb.ListTagsResponse, error) {
+	resourceTagName := SubscriberNamespace + "." + SubscriberCloudName + "." + ValidLastLevelTagName
+	return &tagservicepb.ListTagsResponse{Tags: []*tagservicepb.TagMapping{
+		{Name: ValidTagName, ChildTags: []string{"child", resourceTagName}},
+		{Name: resourceTagName, Uri: &TagUri, Ip: &TagIp},
+	}}, nil
+}
+
 func (s *FakeTagServiceServer) SetTag(c context.Context, tagMapping *tagservicepb.SetTagRequest) (*tagservicepb.SetTagResponse, error) {
 	return &tagservicepb.SetTagResponse{}, nil
 }
 
 func (s *FakeTagServiceServer) DeleteTag(c context.Context, req *tagservicepb.DeleteTagRequest) (*tagservicepb.DeleteTagResponse, error) {
-	if strings.HasPrefix(req.TagName, ValidTagName) {
+	if strings.HasPrefix(req.TagName, ValidTagName) || strings.HasSuffix(req.TagName, ValidLastLevelTagName) {
 		return &tagservicepb.DeleteTagResponse{}, nil
 	}
 	return &tagservicepb.DeleteTagResponse{}, fmt.Errorf("tag does not exist")
@@ -99,6 +108,9 @@ func (s *FakeTagServiceServer) GetSubscribers(c context.Context, req *tagservice
 	if strings.HasPrefix(req.TagName, ValidTagName) {
 		return &tagservicepb.GetSubscribersResponse{Subscribers: []string{SubscriberNamespace + ">" + SubscriberCloudName + ">uri"}}, nil
 	}
+	if strings.HasSuffix(req.TagName, ValidLastLevelTagName) {
+		return &tagservicepb.GetSubscribersResponse{Subscribers: []string{}}, nil
+	}
 	return nil, fmt.Errorf("tag does not exist")
 }
 
@@ -114,6 +126,7 @@ func SetupFakeTagServer(port int) {
 	}
 	grpcServer := grpc.NewServer()
 	tagservicepb.RegisterTagServiceServer(grpcServer, NewFakeTagServer())
+	utils.RegisterHealthService(grpcServer)
 	go func() {
 		if err := grpcServer.Serve(lis); err != nil {
 			fmt.Println(err.Error())
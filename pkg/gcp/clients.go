@@ -24,6 +24,8 @@ import (
 	container "cloud.google.com/go/container/apiv1"
 )
 
+// The Google Cloud clients instrument their requests with OpenTelemetry themselves, so their spans join the trace of the
+// plugin call making them once tracing is set up
 type GCPClients struct {
 	instancesClient           *compute.InstancesClient
 	clustersClient            *container.ClusterManagerClient
@@ -37,6 +39,8 @@ type GCPClients struct {
 	addressesClient           *compute.AddressesClient
 	forwardingClient          *compute.ForwardingRulesClient
 	serviceAttachmentClient   *compute.ServiceAttachmentsClient
+	targetVpnGatewaysClient   *compute.TargetVpnGatewaysClient
+	routesClient              *compute.RoutesClient
 }
 
 func (c *GCPClients) GetOrCreateInstancesClient(ctx context.Context) (*compute.InstancesClient, error) {
@@ -171,6 +175,28 @@ func (c *GCPClients) GetOrCreateServiceAttachmentsClient(ctx context.Context) (*
 	return c.serviceAttachmentClient, nil
 }
 
+func (c *GCPClients) GetOrCreateTargetVpnGatewaysClient(ctx context.Context) (*compute.TargetVpnGatewaysClient, error) {
+	if c.targetVpnGatewaysClient == nil {
+		targetVpnGatewaysClient, err := compute.NewTargetVpnGatewaysRESTClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create TargetVpnGatewaysClient: %w", err)
+		}
+		c.targetVpnGatewaysClient = targetVpnGatewaysClient
+	}
+	return c.targetVpnGatewaysClient, nil
+}
+
+func (c *GCPClients) GetOrCreateRoutesClient(ctx context.Context) (*compute.RoutesClient, error) {
+	if c.routesClient == nil {
+		routesClient, err := compute.NewRoutesRESTClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create RoutesClient: %w", err)
+		}
+		c.routesClient = routesClient
+	}
+	return c.routesClient, nil
+}
+
 func (c *GCPClients) Close() {
 	if c.instancesClient != nil {
 		c.instancesClient.Close()
@@ -208,4 +234,10 @@ func (c *GCPClients) Close() {
 	if c.serviceAttachmentClient != nil {
 		c.serviceAttachmentClient.Close()
 	}
+	if c.targetVpnGatewaysClient != nil {
+		c.targetVpnGatewaysClient.Close()
+	}
+	if c.routesClient != nil {
+		c.routesClient.Close()
+	}
 }
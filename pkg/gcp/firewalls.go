@@ -23,6 +23,7 @@ import (
 
 	computepb "cloud.google.com/go/compute/apiv1/computepb"
 	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -33,6 +34,9 @@ const (
 	targetTypeAddress             = "ADDRESS"
 )
 
+// Firewall priority of rules with the default relative priority (GCP's default priority). Higher relative priorities are added to it.
+const firewallPriorityBase int32 = 1000
+
 // Maps between of GCP and Paraglider traffic direction terminologies
 var (
 	firewallDirectionMapGCPToParaglider = map[string]paragliderpb.Direction{
@@ -69,10 +73,22 @@ func isParagliderPermitListRule(namespace string, firewall *computepb.Firewall)
 
 // Converts a GCP firewall rule to a Paraglider permit list rule
 func firewallRuleToParagliderRule(namespace string, fw *computepb.Firewall) (*paragliderpb.PermitListRule, error) {
-	if len(fw.Allowed) != 1 {
-		return nil, fmt.Errorf("firewall rule has more than one allowed protocol")
+	action := paragliderpb.RuleAction_ALLOW
+	var ipProtocol *string
+	var ports []string
+	if isDenyFirewall(fw) {
+		if len(fw.Denied) != 1 {
+			return nil, fmt.Errorf("firewall rule has more than one denied protocol")
+		}
+		action = paragliderpb.RuleAction_DENY
+		ipProtocol, ports = fw.Denied[0].IPProtocol, fw.Denied[0].Ports
+	} else {
+		if len(fw.Allowed) != 1 {
+			return nil, fmt.Errorf("firewall rule has more than one allowed protocol")
+		}
+		ipProtocol, ports = fw.Allowed[0].IPProtocol, fw.Allowed[0].Ports
 	}
-	protocolNumber, err := getProtocolNumber(*fw.Allowed[0].IPProtocol)
+	protocolNumber, err := getProtocolNumber(*ipProtocol)
 	if err != nil {
 		return nil, fmt.Errorf("could not get protocol number: %w", err)
 	}
@@ -86,14 +102,13 @@ func firewallRuleToParagliderRule(namespace string, fw *computepb.Firewall) (*pa
 		targets = fw.DestinationRanges
 	}
 
-	var dstPort int
-	if len(fw.Allowed[0].Ports) == 0 {
-		dstPort = -1
-	} else {
-		dstPort, err = strconv.Atoi(fw.Allowed[0].Ports[0])
+	var dstPorts []*paragliderpb.PortRange
+	for _, port := range ports {
+		portRange, err := utils.ParsePortRange(port)
 		if err != nil {
-			return nil, fmt.Errorf("could not convert port to int")
+			return nil, fmt.Errorf("could not parse firewall port: %w", err)
 		}
+		dstPorts = append(dstPorts, portRange)
 	}
 
 	var tags []string
@@ -104,27 +119,43 @@ func firewallRuleToParagliderRule(namespace string, fw *computepb.Firewall) (*pa
 	rule := &paragliderpb.PermitListRule{
 		Name:      parseFirewallName(namespace, *fw.Name),
 		Direction: firewallDirectionMapGCPToParaglider[*fw.Direction],
-		SrcPort:   -1,
-		DstPort:   int32(dstPort),
+		SrcPort:   utils.PortAny,
 		Protocol:  int32(protocolNumber),
 		Targets:   targets,
 		Tags:      tags,
+		Action:    action,
 	} // SrcPort not specified since GCP doesn't support rules based on source ports
+	utils.SetDstPortRanges(rule, dstPorts)
+	// Firewalls outside of the range used by Paraglider (e.g., created before rules had priorities) keep the default priority
+	if fw.Priority != nil && *fw.Priority > firewallPriorityBase && *fw.Priority <= firewallPriorityBase+utils.MaxRulePriority {
+		rule.Priority = *fw.Priority - firewallPriorityBase
+	}
 	return rule, nil
 }
 
+// Checks if a GCP firewall rule denies traffic rather than allowing it
+func isDenyFirewall(fw *computepb.Firewall) bool {
+	return len(fw.Denied) > 0
+}
+
 // Converts a Paraglider permit list rule to a GCP firewall rule
 func paragliderRuleToFirewallRule(namespace string, project string, firewallName string, target firewallTarget, rule *paragliderpb.PermitListRule) (*computepb.Firewall, error) {
+	// Ranges of a firewall must all be of the same IP version
+	isIpv6, err := utils.IsPermitListRuleIPv6(rule)
+	if err != nil {
+		return nil, err
+	}
+	if isIpv6 && target.TargetType == targetTypeAddress {
+		return nil, fmt.Errorf("IPv6 targets are not supported for resources matched by their IPv4 address")
+	}
+
 	firewall := &computepb.Firewall{
-		Allowed: []*computepb.Allowed{
-			{
-				IPProtocol: proto.String(strconv.Itoa(int(rule.Protocol))),
-			},
-		},
 		Description: proto.String(getRuleDescription(rule.Tags)),
 		Direction:   proto.String(firewallDirectionMapParagliderToGCP[rule.Direction]),
 		Name:        proto.String(firewallName),
 		Network:     proto.String(getVpcUrl(project, namespace)),
+		// GCP evaluates firewalls by increasing priority and lets deny rules win ties, as Paraglider does
+		Priority: proto.Int32(firewallPriorityBase + rule.Priority),
 	}
 
 	// Associate with a tag if possible, otherwise match on IP
@@ -138,10 +169,17 @@ func paragliderRuleToFirewallRule(namespace string, project string, firewallName
 		}
 	}
 
-	if rule.DstPort != -1 {
-		// Users must explicitly set DstPort to -1 if they want it to apply to all ports since proto can't
-		// differentiate between empty and 0 for an int field. Ports of 0 are valid for protocols like TCP/UDP.
-		firewall.Allowed[0].Ports = []string{strconv.Itoa(int(rule.DstPort))}
+	// Users must explicitly set DstPort to -1 if they want it to apply to all ports since proto can't
+	// differentiate between empty and 0 for an int field. Ports of 0 are valid for protocols like TCP/UDP.
+	var ports []string
+	for _, portRange := range utils.DstPortRanges(rule) {
+		ports = append(ports, utils.FormatPortRange(portRange))
+	}
+	protocol := proto.String(strconv.Itoa(int(rule.Protocol)))
+	if rule.Action == paragliderpb.RuleAction_DENY {
+		firewall.Denied = []*computepb.Denied{{IPProtocol: protocol, Ports: ports}}
+	} else {
+		firewall.Allowed = []*computepb.Allowed{{IPProtocol: protocol, Ports: ports}}
 	}
 	if rule.Direction == paragliderpb.Direction_INBOUND {
 		// TODO @seankimkdy: use SourceTags as well once we start supporting tags
@@ -173,8 +211,10 @@ func isFirewallEqPermitListRule(namespace string, firewall *computepb.Firewall,
 	return paragliderVersion.Name == rule.Name &&
 		paragliderVersion.Direction == rule.Direction &&
 		paragliderVersion.Protocol == rule.Protocol &&
-		paragliderVersion.DstPort == rule.DstPort &&
-		paragliderVersion.SrcPort == rule.SrcPort, nil
+		paragliderVersion.Action == rule.Action &&
+		paragliderVersion.Priority == rule.Priority &&
+		utils.PortRangesEqual(utils.DstPortRanges(paragliderVersion), utils.DstPortRanges(rule)) &&
+		utils.PortRangesEqual(utils.SrcPortRanges(paragliderVersion), utils.SrcPortRanges(rule)), nil
 }
 
 // Gets protocol number from GCP specificiation (either a name like "tcp" or an int-string like "6")
@@ -206,6 +246,11 @@ func getDenyAllIngressFirewallName(namespace string) string {
 	return getParagliderNamespacePrefix(namespace) + "-deny-all-egress"
 }
 
+// Returns name of firewall for denying all IPv6 egress traffic of dual-stack VPCs
+func getDenyAllIpv6EgressFirewallName(namespace string) string {
+	return getParagliderNamespacePrefix(namespace) + "-deny-all-ipv6-egress"
+}
+
 // Format the description to keep metadata about tags
 func getRuleDescription(tags []string) string {
 	if len(tags) == 0 {
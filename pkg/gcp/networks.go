@@ -23,6 +23,8 @@ import (
 	compute "cloud.google.com/go/compute/apiv1"
 	computepb "cloud.google.com/go/compute/apiv1/computepb"
 	"google.golang.org/protobuf/proto"
+
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
 )
 
 // Gets a GCP VPC name
@@ -50,6 +52,36 @@ func getVpcUrl(project string, namespace string) string {
 	return computeUrlPrefix + fmt.Sprintf("projects/%s/global/networks/%s", project, getVpcName(namespace))
 }
 
+// Returns the address spaces (including secondary ranges) of all subnetworks in the VPC network of a namespace
+func getVpcAddressSpaces(ctx context.Context, networksClient *compute.NetworksClient, subnetworksClient *compute.SubnetworksClient, project string, namespace string) ([]string, error) {
+	getNetworkReq := &computepb.GetNetworkRequest{
+		Network: getVpcName(namespace),
+		Project: project,
+	}
+	network, err := networksClient.Get(ctx, getNetworkReq)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get paraglider vpc network: %w", err)
+	}
+	addressSpaces := []string{}
+	for _, subnetURL := range network.Subnetworks {
+		parsedSubnetURL := parseUrl(subnetURL)
+		getSubnetworkReq := &computepb.GetSubnetworkRequest{
+			Project:    project,
+			Region:     parsedSubnetURL["regions"],
+			Subnetwork: parsedSubnetURL["subnetworks"],
+		}
+		subnetwork, err := subnetworksClient.Get(ctx, getSubnetworkReq)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get paraglider subnetwork: %w", err)
+		}
+		addressSpaces = append(addressSpaces, *subnetwork.IpCidrRange)
+		for _, secondaryRange := range subnetwork.SecondaryIpRanges {
+			addressSpaces = append(addressSpaces, *secondaryRange.IpCidrRange)
+		}
+	}
+	return addressSpaces, nil
+}
+
 // Creates bi-directional peering between two VPC networks
 func peerVpcNetwork(ctx context.Context, networksClient *compute.NetworksClient, currentProject string, currentNamespace string, peerProject string, peerNamespace string) error {
 	// Check if peering already exists
@@ -90,6 +122,7 @@ func peerVpcNetwork(ctx context.Context, networksClient *compute.NetworksClient,
 	if err = addPeeringNetworkOp.Wait(ctx); err != nil {
 		return fmt.Errorf("unable to wait for the add peering operation: %w", err)
 	}
+	utils.AddSideEffect(ctx, networkPeeringName, fmt.Sprintf("created VPC network peering %s to %s", networkPeeringName, getVpcName(peerNamespace)))
 	return nil
 }
 
@@ -156,3 +189,68 @@ func setupNatGateway(ctx context.Context, routersClient *compute.RoutersClient,
 	}
 	return nil
 }
+
+// deleteVpcNetwork deletes the firewalls and subnetworks of a namespace's VPC followed by the VPC itself.
+// The VPC must no longer contain any resources.
+func deleteVpcNetwork(ctx context.Context, project string, namespace string, clients *GCPClients) error {
+	networksClient, err := clients.GetOrCreateNetworksClient(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get networks client: %w", err)
+	}
+	network, err := networksClient.Get(ctx, &computepb.GetNetworkRequest{Project: project, Network: getVpcName(namespace)})
+	if err != nil {
+		if isErrorNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("unable to get vpc: %w", err)
+	}
+
+	firewallsClient, err := clients.GetOrCreateFirewallsClient(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get firewalls client: %w", err)
+	}
+	listFirewallsReq := &computepb.ListFirewallsRequest{
+		Project: project,
+		Filter:  proto.String(fmt.Sprintf("network eq \"%s\"", getVpcUrl(project, namespace))),
+	}
+	firewallsIterator := firewallsClient.List(ctx, listFirewallsReq)
+	for {
+		firewall, err := firewallsIterator.Next()
+		if firewall == nil {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("unable to list firewalls: %w", err)
+		}
+		if firewall.GetNetwork() != getVpcUrl(project, namespace) {
+			continue
+		}
+		deleteFirewallOp, err := firewallsClient.Delete(ctx, &computepb.DeleteFirewallRequest{Project: project, Firewall: firewall.GetName()})
+		if err = waitForDeletion(ctx, deleteFirewallOp, err); err != nil {
+			return fmt.Errorf("unable to delete firewall %s: %w", firewall.GetName(), err)
+		}
+	}
+
+	subnetworksClient, err := clients.GetOrCreateSubnetworksClient(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get subnetworks client: %w", err)
+	}
+	for _, subnetworkUrl := range network.Subnetworks {
+		parsedSubnetworkUrl := parseUrl(subnetworkUrl)
+		deleteSubnetworkReq := &computepb.DeleteSubnetworkRequest{
+			Project:    project,
+			Region:     parsedSubnetworkUrl["regions"],
+			Subnetwork: parsedSubnetworkUrl["subnetworks"],
+		}
+		deleteSubnetworkOp, err := subnetworksClient.Delete(ctx, deleteSubnetworkReq)
+		if err = waitForDeletion(ctx, deleteSubnetworkOp, err); err != nil {
+			return fmt.Errorf("unable to delete subnetwork %s: %w", subnetworkUrl, err)
+		}
+	}
+
+	deleteNetworkOp, err := networksClient.Delete(ctx, &computepb.DeleteNetworkRequest{Project: project, Network: getVpcName(namespace)})
+	if err = waitForDeletion(ctx, deleteNetworkOp, err); err != nil {
+		return fmt.Errorf("unable to delete vpc: %w", err)
+	}
+	return nil
+}
@@ -19,22 +19,25 @@ package gcp
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net"
-	"os"
 
 	compute "cloud.google.com/go/compute/apiv1"
 	computepb "cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/paraglider-project/paraglider/internal/version"
+	config "github.com/paraglider-project/paraglider/pkg/orchestrator/config"
 	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
 	utils "github.com/paraglider-project/paraglider/pkg/utils"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 type GCPPluginServer struct {
 	paragliderpb.UnimplementedCloudPluginServer
-	orchestratorServerAddr string
+	orchestratorServerAddr  string
+	orchestratorCredentials credentials.TransportCredentials // Used to dial the orchestrator (plaintext if nil)
 }
 
 func (s *GCPPluginServer) GetPermitList(ctx context.Context, req *paragliderpb.GetPermitListRequest) (*paragliderpb.GetPermitListResponse, error) {
@@ -117,13 +120,13 @@ func (s *GCPPluginServer) _AddPermitListRules(ctx context.Context, req *paraglid
 	}
 
 	// Get used address spaces of all clouds
-	orchestratorConn, err := grpc.NewClient(s.orchestratorServerAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	orchestratorConn, err := grpc.NewClient(s.orchestratorServerAddr, utils.DialCredentials(s.orchestratorCredentials), utils.TracingDialOption())
 	if err != nil {
 		return nil, fmt.Errorf("unable to establish connection with orchestrator: %w", err)
 	}
 	defer orchestratorConn.Close()
 	orchestratorClient := paragliderpb.NewControllerClient(orchestratorConn)
-	getUsedAddressSpacesResp, err := orchestratorClient.GetUsedAddressSpaces(context.Background(), &emptypb.Empty{})
+	getUsedAddressSpacesResp, err := orchestratorClient.GetUsedAddressSpaces(ctx, &emptypb.Empty{})
 	if err != nil {
 		return nil, fmt.Errorf("unable to get used address spaces: %w", err)
 	}
@@ -133,6 +136,7 @@ func (s *GCPPluginServer) _AddPermitListRules(ctx context.Context, req *paraglid
 		firewallName := getFirewallName(req.Namespace, permitListRule.Name, netInfo.ResourceID)
 
 		patchRequired := false
+		recreateRequired := false
 		if existingFw, ok := existingFirewalls[firewallName]; ok {
 			equivalent, err := isFirewallEqPermitListRule(req.Namespace, existingFw, permitListRule)
 			if err != nil {
@@ -145,54 +149,79 @@ func (s *GCPPluginServer) _AddPermitListRules(ctx context.Context, req *paraglid
 				// Firewall already exists but is not equivalent to the provided permit list rule
 				// We should patch the rule, but we need to still check if any new infrastructure is needed
 				patchRequired = true
+				// Firewall rules can't switch between allowing and denying traffic, so those have to be recreated instead
+				recreateRequired = isDenyFirewall(existingFw) != (permitListRule.Action == paragliderpb.RuleAction_DENY)
 			}
 		}
 
-		// Get all peering cloud infos
-		peeringCloudInfos, err := utils.GetPermitListRulePeeringCloudInfo(permitListRule, getUsedAddressSpacesResp.AddressSpaceMappings)
-		if err != nil {
-			return nil, fmt.Errorf("unable to get peering cloud infos: %w", err)
-		}
+		// Connectivity is only set up for traffic which is allowed since blocking traffic doesn't need any
+		if permitListRule.Action != paragliderpb.RuleAction_DENY {
+			// Get all peering cloud infos
+			peeringCloudInfos, err := utils.GetPermitListRulePeeringCloudInfo(permitListRule, getUsedAddressSpacesResp.AddressSpaceMappings)
+			if err != nil {
+				return nil, fmt.Errorf("unable to get peering cloud infos: %w", err)
+			}
 
-		for _, peeringCloudInfo := range peeringCloudInfos {
-			if peeringCloudInfo == nil {
-				// Setup NAT gateways for public IP address targets
-				routersClient, err := clients.GetOrCreateRoutersClient(ctx)
-				if err != nil {
-					return nil, fmt.Errorf("unable to get routers client: %w", err)
-				}
-				err = setupNatGateway(ctx, routersClient, resourceInfo.Project, req.Namespace)
-				if err != nil {
-					return nil, fmt.Errorf("unable to setup NAT gateway: %w", err)
-				}
-			} else if peeringCloudInfo.Cloud != utils.GCP {
-				// Create VPN connections
-				connectCloudsReq := &paragliderpb.ConnectCloudsRequest{
-					CloudA:          utils.GCP,
-					CloudANamespace: req.Namespace,
-					CloudB:          peeringCloudInfo.Cloud,
-					CloudBNamespace: peeringCloudInfo.Namespace,
-				}
-				_, err := orchestratorClient.ConnectClouds(ctx, connectCloudsReq)
-				if err != nil {
-					return nil, fmt.Errorf("unable to connect clouds : %w", err)
-				}
-			} else {
-				if peeringCloudInfo.Namespace != req.Namespace {
-					// Create VPC network peering (in both directions) for different namespaces
+			for i, peeringCloudInfo := range peeringCloudInfos {
+				if peeringCloudInfo == nil {
+					// Setup NAT gateways for public IP address targets
+					routersClient, err := clients.GetOrCreateRoutersClient(ctx)
+					if err != nil {
+						return nil, fmt.Errorf("unable to get routers client: %w", err)
+					}
+					err = setupNatGateway(ctx, routersClient, resourceInfo.Project, req.Namespace)
+					if err != nil {
+						return nil, fmt.Errorf("unable to setup NAT gateway: %w", err)
+					}
+				} else if peeringCloudInfo.Cloud != utils.GCP {
+					if utils.IsIPv6(permitListRule.Targets[i]) {
+						return nil, fmt.Errorf("IPv6 targets in other clouds are not supported since VPN connections are IPv4-only")
+					}
+					// Address spaces of the local VPC are needed by clouds which don't support BGP (e.g., IBM)
 					networksClient, err := clients.GetOrCreateNetworksClient(ctx)
 					if err != nil {
 						return nil, fmt.Errorf("unable to get networks client: %w", err)
 					}
-
-					peerProject := parseUrl(peeringCloudInfo.Deployment)["projects"]
-					err = peerVpcNetwork(ctx, networksClient, resourceInfo.Project, req.Namespace, peerProject, peeringCloudInfo.Namespace)
+					subnetworksClient, err := clients.GetOrCreateSubnetworksClient(ctx)
+					if err != nil {
+						return nil, fmt.Errorf("unable to get subnetworks client: %w", err)
+					}
+					vpcAddressSpaces, err := getVpcAddressSpaces(ctx, networksClient, subnetworksClient, resourceInfo.Project, req.Namespace)
 					if err != nil {
-						return nil, fmt.Errorf("unable to create peering from %s to %s: %w", req.Namespace, peeringCloudInfo.Namespace, err)
+						return nil, fmt.Errorf("unable to get vpc address spaces: %w", err)
+					}
+
+					// Create VPN connections
+					connectCloudsReq := &paragliderpb.ConnectCloudsRequest{
+						CloudA:              utils.GCP,
+						CloudANamespace:     req.Namespace,
+						CloudB:              peeringCloudInfo.Cloud,
+						CloudBNamespace:     peeringCloudInfo.Namespace,
+						AddressSpacesCloudA: vpcAddressSpaces,
+						AddressSpacesCloudB: []string{permitListRule.Targets[i]},
 					}
-					err = peerVpcNetwork(ctx, networksClient, peerProject, peeringCloudInfo.Namespace, resourceInfo.Project, req.Namespace)
+					connectCloudsResp, err := orchestratorClient.ConnectClouds(ctx, connectCloudsReq)
 					if err != nil {
-						return nil, fmt.Errorf("unable to create peering from %s to %s: %w", peeringCloudInfo.Namespace, req.Namespace, err)
+						return nil, fmt.Errorf("unable to connect clouds : %w", err)
+					}
+					utils.AddResponseNotices(ctx, connectCloudsResp)
+				} else {
+					if peeringCloudInfo.Namespace != req.Namespace {
+						// Create VPC network peering (in both directions) for different namespaces
+						networksClient, err := clients.GetOrCreateNetworksClient(ctx)
+						if err != nil {
+							return nil, fmt.Errorf("unable to get networks client: %w", err)
+						}
+
+						peerProject := parseUrl(peeringCloudInfo.Deployment)["projects"]
+						err = peerVpcNetwork(ctx, networksClient, resourceInfo.Project, req.Namespace, peerProject, peeringCloudInfo.Namespace)
+						if err != nil {
+							return nil, fmt.Errorf("unable to create peering from %s to %s: %w", req.Namespace, peeringCloudInfo.Namespace, err)
+						}
+						err = peerVpcNetwork(ctx, networksClient, peerProject, peeringCloudInfo.Namespace, resourceInfo.Project, req.Namespace)
+						if err != nil {
+							return nil, fmt.Errorf("unable to create peering from %s to %s: %w", peeringCloudInfo.Namespace, req.Namespace, err)
+						}
 					}
 				}
 			}
@@ -208,7 +237,21 @@ func (s *GCPPluginServer) _AddPermitListRules(ctx context.Context, req *paraglid
 			return nil, fmt.Errorf("unable to get firewalls client: %w", err)
 		}
 
-		if patchRequired {
+		if recreateRequired {
+			deleteFirewallReq := &computepb.DeleteFirewallRequest{
+				Firewall: firewallName,
+				Project:  resourceInfo.Project,
+			}
+			deleteFirewallOp, err := firewallsClient.Delete(ctx, deleteFirewallReq)
+			if err != nil {
+				return nil, fmt.Errorf("unable to delete firewall rule: %w", err)
+			}
+			if err = deleteFirewallOp.Wait(ctx); err != nil {
+				return nil, fmt.Errorf("unable to wait for the operation: %w", err)
+			}
+		}
+
+		if patchRequired && !recreateRequired {
 			patchFirewallReq := &computepb.PatchFirewallRequest{
 				Firewall:         firewallName,
 				FirewallResource: firewall,
@@ -321,6 +364,11 @@ func (s *GCPPluginServer) _CreateResource(ctx context.Context, resourceDescripti
 	getNetworkResp, err := networksClient.Get(ctx, getNetworkReq)
 	if err != nil {
 		if isErrorNotFound(err) {
+			// The VPC is dual-stack if the orchestrator hands out IPv6 address spaces
+			ipv6AddressSpace, err := s.findUnusedIpv6AddressSpace(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("unable to find unused IPv6 address space: %w", err)
+			}
 			insertNetworkRequest := &computepb.InsertNetworkRequest{
 				Project: project,
 				NetworkResource: &computepb.Network{
@@ -332,6 +380,11 @@ func (s *GCPPluginServer) _CreateResource(ctx context.Context, resourceDescripti
 					},
 				},
 			}
+			if ipv6AddressSpace != "" {
+				insertNetworkRequest.NetworkResource.EnableUlaInternalIpv6 = proto.Bool(true)
+				insertNetworkRequest.NetworkResource.InternalIpv6Range = proto.String(ipv6AddressSpace)
+				resourceInfo.DualStack = true
+			}
 			insertNetworkOp, err := networksClient.Insert(ctx, insertNetworkRequest)
 			if err != nil {
 				return nil, fmt.Errorf("unable to insert network: %w", err)
@@ -345,33 +398,41 @@ func (s *GCPPluginServer) _CreateResource(ctx context.Context, resourceDescripti
 				return nil, fmt.Errorf("unable to get firewalls client: %w", err)
 			}
 
-			insertFirewallReq := &computepb.InsertFirewallRequest{
-				Project: project,
-				FirewallResource: &computepb.Firewall{
-					Denied: []*computepb.Denied{
-						{
-							IPProtocol: proto.String("all"),
+			// A firewall can't mix IPv4 and IPv6 ranges, so IPv6 egress is denied by a separate firewall
+			denyAllEgressFirewallNames := map[string]string{"0.0.0.0/0": getDenyAllIngressFirewallName(resourceDescription.Deployment.Namespace)}
+			if resourceInfo.DualStack {
+				denyAllEgressFirewallNames["::/0"] = getDenyAllIpv6EgressFirewallName(resourceDescription.Deployment.Namespace)
+			}
+			for destinationRange, firewallName := range denyAllEgressFirewallNames {
+				insertFirewallReq := &computepb.InsertFirewallRequest{
+					Project: project,
+					FirewallResource: &computepb.Firewall{
+						Denied: []*computepb.Denied{
+							{
+								IPProtocol: proto.String("all"),
+							},
 						},
+						Description:       proto.String("Paraglider deny all traffic"),
+						DestinationRanges: []string{destinationRange},
+						Direction:         proto.String(computepb.Firewall_EGRESS.String()),
+						Name:              proto.String(firewallName),
+						Network:           proto.String(getVpcUrl(project, resourceDescription.Deployment.Namespace)),
+						Priority:          proto.Int32(65534),
 					},
-					Description:       proto.String("Paraglider deny all traffic"),
-					DestinationRanges: []string{"0.0.0.0/0"},
-					Direction:         proto.String(computepb.Firewall_EGRESS.String()),
-					Name:              proto.String(getDenyAllIngressFirewallName(resourceDescription.Deployment.Namespace)),
-					Network:           proto.String(getVpcUrl(project, resourceDescription.Deployment.Namespace)),
-					Priority:          proto.Int32(65534),
-				},
-			}
-			insertFirewallOp, err := firewallsClient.Insert(ctx, insertFirewallReq)
-			if err != nil {
-				return nil, fmt.Errorf("unable to create firewall rule: %w", err)
-			}
-			if err = insertFirewallOp.Wait(ctx); err != nil {
-				return nil, fmt.Errorf("unable to wait for the operation: %w", err)
+				}
+				insertFirewallOp, err := firewallsClient.Insert(ctx, insertFirewallReq)
+				if err != nil {
+					return nil, fmt.Errorf("unable to create firewall rule: %w", err)
+				}
+				if err = insertFirewallOp.Wait(ctx); err != nil {
+					return nil, fmt.Errorf("unable to wait for the operation: %w", err)
+				}
 			}
 		} else {
 			return nil, fmt.Errorf("failed to get paraglider vpc network: %w", err)
 		}
 	} else {
+		resourceInfo.DualStack = getNetworkResp.GetEnableUlaInternalIpv6()
 		// Check if there is a subnet in the region that resource will be placed in
 		for _, subnetURL := range getNetworkResp.Subnetworks {
 			parsedSubnetURL := parseUrl(subnetURL)
@@ -386,7 +447,7 @@ func (s *GCPPluginServer) _CreateResource(ctx context.Context, resourceDescripti
 	addressSpaces := []string{}
 	numAddressSpacesNeeded := int32(resourceInfo.NumAdditionalAddressSpaces)
 	if !subnetExists || resourceInfo.NumAdditionalAddressSpaces > 0 {
-		conn, err := grpc.NewClient(s.orchestratorServerAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		conn, err := grpc.NewClient(s.orchestratorServerAddr, utils.DialCredentials(s.orchestratorCredentials), utils.TracingDialOption())
 		if err != nil {
 			return nil, fmt.Errorf("unable to establish connection with orchestrator: %w", err)
 		}
@@ -399,7 +460,7 @@ func (s *GCPPluginServer) _CreateResource(ctx context.Context, resourceDescripti
 
 		reqAddressSpaces := make([]int32, numAddressSpacesNeeded)
 
-		response, err := client.FindUnusedAddressSpaces(context.Background(), &paragliderpb.FindUnusedAddressSpacesRequest{Sizes: reqAddressSpaces})
+		response, err := client.FindUnusedAddressSpaces(ctx, &paragliderpb.FindUnusedAddressSpacesRequest{Sizes: reqAddressSpaces})
 
 		if err != nil {
 			return nil, fmt.Errorf("unable to find unused address space: %w", err)
@@ -424,6 +485,11 @@ func (s *GCPPluginServer) _CreateResource(ctx context.Context, resourceDescripti
 				IpCidrRange: proto.String(addressSpaces[0]),
 			},
 		}
+		if resourceInfo.DualStack {
+			// The IPv6 prefix of the subnet is assigned by GCP from the internal IPv6 range of the VPC
+			insertSubnetworkRequest.SubnetworkResource.StackType = proto.String(computepb.Subnetwork_IPV4_IPV6.String())
+			insertSubnetworkRequest.SubnetworkResource.Ipv6AccessType = proto.String(computepb.Subnetwork_INTERNAL.String())
+		}
 		insertSubnetworkOp, err := subnetworksClient.Insert(ctx, insertSubnetworkRequest)
 		if err != nil {
 			return nil, fmt.Errorf("unable to insert subnetwork: %w", err)
@@ -443,10 +509,122 @@ func (s *GCPPluginServer) _CreateResource(ctx context.Context, resourceDescripti
 	return &paragliderpb.CreateResourceResponse{Name: resourceInfo.Name, Uri: url, Ip: ip}, nil
 }
 
+// Gets an IPv6 address space for a dual-stack VPC from the orchestrator, or an empty string if namespaces are IPv4-only
+func (s *GCPPluginServer) findUnusedIpv6AddressSpace(ctx context.Context) (string, error) {
+	conn, err := grpc.NewClient(s.orchestratorServerAddr, utils.DialCredentials(s.orchestratorCredentials), utils.TracingDialOption())
+	if err != nil {
+		return "", fmt.Errorf("unable to establish connection with orchestrator: %w", err)
+	}
+	defer conn.Close()
+	client := paragliderpb.NewControllerClient(conn)
+	response, err := client.FindUnusedAddressSpaces(ctx, &paragliderpb.FindUnusedAddressSpacesRequest{Ipv6: true})
+	if err != nil {
+		return "", err
+	}
+	if len(response.AddressSpaces) == 0 {
+		return "", nil
+	}
+	return response.AddressSpaces[0], nil
+}
+
 func (s *GCPPluginServer) AttachResource(ctx context.Context, req *paragliderpb.AttachResourceRequest) (*paragliderpb.AttachResourceResponse, error) {
 	return nil, fmt.Errorf("not implemented")
 }
 
+func (s *GCPPluginServer) DeleteResource(ctx context.Context, req *paragliderpb.DeleteResourceRequest) (*paragliderpb.DeleteResourceResponse, error) {
+	// Lazy client initialization since necessary clients vary depending on the resource
+	clients := &GCPClients{}
+	defer clients.Close()
+
+	return s._DeleteResource(ctx, req, clients)
+}
+
+func (s *GCPPluginServer) _DeleteResource(ctx context.Context, req *paragliderpb.DeleteResourceRequest, clients *GCPClients) (*paragliderpb.DeleteResourceResponse, error) {
+	resourceInfo, err := parseResourceUrl(req.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse resource URL: %w", err)
+	}
+	resourceInfo.Namespace = req.Namespace
+
+	netInfo, err := GetResourceNetworkInfo(ctx, resourceInfo, clients)
+	if err != nil {
+		return nil, err
+	}
+
+	// Delete firewalls corresponding to the resource's permit list
+	if err = deletePermitListFirewalls(ctx, resourceInfo, netInfo, clients); err != nil {
+		return nil, err
+	}
+
+	// Delete the resource itself
+	handler, err := getResourceHandler(ctx, resourceInfo.ResourceType, clients)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get resource handler: %w", err)
+	}
+	if err = handler.deleteResource(ctx, resourceInfo, netInfo); err != nil {
+		return nil, fmt.Errorf("unable to delete resource: %w", err)
+	}
+
+	return &paragliderpb.DeleteResourceResponse{}, nil
+}
+
+func (s *GCPPluginServer) DetachResource(ctx context.Context, req *paragliderpb.DetachResourceRequest) (*paragliderpb.DetachResourceResponse, error) {
+	// Lazy client initialization since necessary clients vary depending on the resource
+	clients := &GCPClients{}
+	defer clients.Close()
+
+	return s._DetachResource(ctx, req, clients)
+}
+
+func (s *GCPPluginServer) _DetachResource(ctx context.Context, req *paragliderpb.DetachResourceRequest, clients *GCPClients) (*paragliderpb.DetachResourceResponse, error) {
+	resourceInfo, err := parseResourceUrl(req.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse resource URL: %w", err)
+	}
+	resourceInfo.Namespace = req.Namespace
+
+	netInfo, err := GetResourceNetworkInfo(ctx, resourceInfo, clients)
+	if err != nil {
+		return nil, err
+	}
+
+	// Delete firewalls corresponding to the resource's permit list
+	if err = deletePermitListFirewalls(ctx, resourceInfo, netInfo, clients); err != nil {
+		return nil, err
+	}
+
+	// Remove the remaining Paraglider artifacts from the resource
+	handler, err := getResourceHandler(ctx, resourceInfo.ResourceType, clients)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get resource handler: %w", err)
+	}
+	if err = handler.detachResource(ctx, resourceInfo, netInfo); err != nil {
+		return nil, fmt.Errorf("unable to detach resource: %w", err)
+	}
+
+	return &paragliderpb.DetachResourceResponse{}, nil
+}
+
+func (s *GCPPluginServer) DeleteNamespace(ctx context.Context, req *paragliderpb.DeleteNamespaceRequest) (*paragliderpb.DeleteNamespaceResponse, error) {
+	clients := &GCPClients{}
+	defer clients.Close()
+
+	return s._DeleteNamespace(ctx, req, clients)
+}
+
+func (s *GCPPluginServer) _DeleteNamespace(ctx context.Context, req *paragliderpb.DeleteNamespaceRequest, clients *GCPClients) (*paragliderpb.DeleteNamespaceResponse, error) {
+	project := parseUrl(req.Deployment.Id)["projects"]
+
+	// The VPN has to go first since its gateways and router are attached to the VPC
+	if err := deleteVpn(ctx, project, req.Deployment.Namespace, clients); err != nil {
+		return nil, err
+	}
+	if err := deleteVpcNetwork(ctx, project, req.Deployment.Namespace, clients); err != nil {
+		return nil, err
+	}
+	return &paragliderpb.DeleteNamespaceResponse{}, nil
+}
+
 func (s *GCPPluginServer) GetUsedAddressSpaces(ctx context.Context, req *paragliderpb.GetUsedAddressSpacesRequest) (*paragliderpb.GetUsedAddressSpacesResponse, error) {
 	clients := &GCPClients{}
 	networksClient, err := clients.GetOrCreateNetworksClient(ctx)
@@ -490,6 +668,10 @@ func (s *GCPPluginServer) _GetUsedAddressSpaces(ctx context.Context, req *paragl
 			}
 		}
 		resp.AddressSpaceMappings[i].AddressSpaces = []string{}
+		// IPv6 prefixes of subnets are all carved out of the internal IPv6 range of the VPC
+		if getNetworkResp.InternalIpv6Range != nil {
+			resp.AddressSpaceMappings[i].AddressSpaces = append(resp.AddressSpaceMappings[i].AddressSpaces, *getNetworkResp.InternalIpv6Range)
+		}
 		for _, subnetURL := range getNetworkResp.Subnetworks {
 			parsedSubnetURL := parseUrl(subnetURL)
 			getSubnetworkRequest := &computepb.GetSubnetworkRequest{
@@ -603,6 +785,25 @@ func (s *GCPPluginServer) _GetUsedBgpPeeringIpAddresses(ctx context.Context, req
 func (s *GCPPluginServer) CreateVpnGateway(ctx context.Context, req *paragliderpb.CreateVpnGatewayRequest) (*paragliderpb.CreateVpnGatewayResponse, error) {
 	clients := &GCPClients{}
 
+	// HA VPN requires BGP, so a Classic VPN with static routes is used for clouds that don't support it
+	if req.Cloud == utils.IBM {
+		addressesClient, err := clients.GetOrCreateAddressesClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get addresses client: %w", err)
+		}
+		targetVpnGatewaysClient, err := clients.GetOrCreateTargetVpnGatewaysClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get target vpn gateways client: %w", err)
+		}
+		forwardingRulesClient, err := clients.GetOrCreateForwardingClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get forwarding rules client: %w", err)
+		}
+		defer clients.Close()
+
+		return s._CreateClassicVpnGateway(ctx, req, addressesClient, targetVpnGatewaysClient, forwardingRulesClient)
+	}
+
 	vpnGatewaysClient, err := clients.GetOrCreateVpnGatewaysClient(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get vpn gateways client: %w", err)
@@ -638,10 +839,11 @@ func (s *GCPPluginServer) _CreateVpnGateway(ctx context.Context, req *paraglider
 		if err = insertVpnGatewayOp.Wait(ctx); err != nil {
 			return nil, fmt.Errorf("unable to wait on insert vpn gateway operation: %w", err)
 		}
+		utils.AddSideEffect(ctx, getVpnGwName(req.Deployment.Namespace), fmt.Sprintf("created VPN gateway %s", getVpnGwName(req.Deployment.Namespace)))
 	}
 
 	// Find unused ASN
-	conn, err := grpc.NewClient(s.orchestratorServerAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(s.orchestratorServerAddr, utils.DialCredentials(s.orchestratorCredentials), utils.TracingDialOption())
 	if err != nil {
 		return nil, fmt.Errorf("unable to establish connection with orchestrator: %w", err)
 	}
@@ -738,8 +940,107 @@ func (s *GCPPluginServer) _CreateVpnGateway(ctx context.Context, req *paraglider
 	return resp, nil
 }
 
+// Creates a Classic VPN gateway (i.e., a target VPN gateway with a static IP address and the forwarding rules required for IPsec)
+func (s *GCPPluginServer) _CreateClassicVpnGateway(ctx context.Context, req *paragliderpb.CreateVpnGatewayRequest, addressesClient *compute.AddressesClient, targetVpnGatewaysClient *compute.TargetVpnGatewaysClient, forwardingRulesClient *compute.ForwardingRulesClient) (*paragliderpb.CreateVpnGatewayResponse, error) {
+	project := parseUrl(req.Deployment.Id)["projects"]
+
+	// Reserve static IP address
+	insertAddressReq := &computepb.InsertAddressRequest{
+		Project: project,
+		Region:  vpnRegion,
+		AddressResource: &computepb.Address{
+			Name:        proto.String(getClassicVpnGwAddressName(req.Deployment.Namespace)),
+			Description: proto.String("Paraglider Classic VPN gateway IP address"),
+		},
+	}
+	insertAddressOp, err := addressesClient.Insert(ctx, insertAddressReq)
+	if err != nil {
+		if !isErrorDuplicate(err) {
+			return nil, fmt.Errorf("unable to insert address: %w", err)
+		}
+	} else {
+		if err = insertAddressOp.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("unable to wait on insert address operation: %w", err)
+		}
+	}
+	getAddressReq := &computepb.GetAddressRequest{
+		Project: project,
+		Region:  vpnRegion,
+		Address: getClassicVpnGwAddressName(req.Deployment.Namespace),
+	}
+	address, err := addressesClient.Get(ctx, getAddressReq)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get address: %w", err)
+	}
+
+	// Create target VPN gateway
+	insertTargetVpnGatewayReq := &computepb.InsertTargetVpnGatewayRequest{
+		Project: project,
+		Region:  vpnRegion,
+		TargetVpnGatewayResource: &computepb.TargetVpnGateway{
+			Name:        proto.String(getClassicVpnGwName(req.Deployment.Namespace)),
+			Description: proto.String("Paraglider Classic VPN gateway for multicloud connections without BGP"),
+			Network:     proto.String(getVpcUrl(project, req.Deployment.Namespace)),
+		},
+	}
+	insertTargetVpnGatewayOp, err := targetVpnGatewaysClient.Insert(ctx, insertTargetVpnGatewayReq)
+	if err != nil {
+		if !isErrorDuplicate(err) {
+			return nil, fmt.Errorf("unable to insert target vpn gateway: %w", err)
+		}
+	} else {
+		if err = insertTargetVpnGatewayOp.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("unable to wait on insert target vpn gateway operation: %w", err)
+		}
+		utils.AddSideEffect(ctx, getClassicVpnGwName(req.Deployment.Namespace), fmt.Sprintf("created Classic VPN gateway %s", getClassicVpnGwName(req.Deployment.Namespace)))
+	}
+
+	// Forward ESP, IKE (UDP 500) and NAT-T (UDP 4500) traffic to the gateway
+	forwardingRules := []*computepb.ForwardingRule{
+		{Name: proto.String(getClassicVpnGwForwardingRuleName(req.Deployment.Namespace, "esp")), IPProtocol: proto.String("ESP")},
+		{Name: proto.String(getClassicVpnGwForwardingRuleName(req.Deployment.Namespace, "udp500")), IPProtocol: proto.String("UDP"), PortRange: proto.String("500")},
+		{Name: proto.String(getClassicVpnGwForwardingRuleName(req.Deployment.Namespace, "udp4500")), IPProtocol: proto.String("UDP"), PortRange: proto.String("4500")},
+	}
+	for _, forwardingRule := range forwardingRules {
+		forwardingRule.IPAddress = address.Address
+		forwardingRule.Target = proto.String(getTargetVpnGatewayUrl(project, vpnRegion, getClassicVpnGwName(req.Deployment.Namespace)))
+		insertForwardingRuleReq := &computepb.InsertForwardingRuleRequest{
+			Project:                project,
+			Region:                 vpnRegion,
+			ForwardingRuleResource: forwardingRule,
+		}
+		insertForwardingRuleOp, err := forwardingRulesClient.Insert(ctx, insertForwardingRuleReq)
+		if err != nil {
+			if !isErrorDuplicate(err) {
+				return nil, fmt.Errorf("unable to insert forwarding rule: %w", err)
+			}
+		} else {
+			if err = insertForwardingRuleOp.Wait(ctx); err != nil {
+				return nil, fmt.Errorf("unable to wait on insert forwarding rule operation: %w", err)
+			}
+		}
+	}
+
+	// No ASN is returned since BGP isn't used
+	return &paragliderpb.CreateVpnGatewayResponse{GatewayIpAddresses: []string{*address.Address}}, nil
+}
+
 func (s *GCPPluginServer) CreateVpnConnections(ctx context.Context, req *paragliderpb.CreateVpnConnectionsRequest) (*paragliderpb.CreateVpnConnectionsResponse, error) {
 	clients := &GCPClients{}
+
+	if req.IsBgpDisabled {
+		vpnTunnelsClient, err := clients.GetOrCreateVpnTunnelsClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get vpn tunnels client: %w", err)
+		}
+		routesClient, err := clients.GetOrCreateRoutesClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get routes client: %w", err)
+		}
+		defer clients.Close()
+		return s._CreateClassicVpnConnections(ctx, req, vpnTunnelsClient, routesClient)
+	}
+
 	externalVpnGatewaysClient, err := clients.GetOrCreateExternalVpnGatewaysClient(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get external vpn gateways client: %w", err)
@@ -813,6 +1114,8 @@ func (s *GCPPluginServer) _CreateVpnConnections(ctx context.Context, req *paragl
 			if err = insertVpnTunnelOp.Wait(ctx); err != nil {
 				return nil, fmt.Errorf("unable to wait on insert vpn tunnel operation: %w", err)
 			}
+			vpnTunnelName := getVpnTunnelName(req.Deployment.Namespace, req.Cloud, i)
+			utils.AddSideEffect(ctx, vpnTunnelName, fmt.Sprintf("created VPN tunnel %s to %s", vpnTunnelName, req.Cloud))
 		}
 	}
 
@@ -860,25 +1163,170 @@ func (s *GCPPluginServer) _CreateVpnConnections(ctx context.Context, req *paragl
 	return &paragliderpb.CreateVpnConnectionsResponse{}, nil
 }
 
+// Creates Classic VPN tunnels to each peer gateway IP address and static routes to the remote address spaces through them
+func (s *GCPPluginServer) _CreateClassicVpnConnections(ctx context.Context, req *paragliderpb.CreateVpnConnectionsRequest, vpnTunnelsClient *compute.VpnTunnelsClient, routesClient *compute.RoutesClient) (*paragliderpb.CreateVpnConnectionsResponse, error) {
+	if len(req.RemoteAddresses) == 0 {
+		return nil, fmt.Errorf("remote addresses are required for VPN connections without BGP")
+	}
+	project := parseUrl(req.Deployment.Id)["projects"]
+
+	for i, peerIpAddress := range req.GatewayIpAddresses {
+		// Insert VPN tunnel (route-based, so all traffic is selected and routing is done through static routes)
+		vpnTunnelName := getVpnTunnelName(req.Deployment.Namespace, req.Cloud, i)
+		insertVpnTunnelReq := &computepb.InsertVpnTunnelRequest{
+			Project: project,
+			Region:  vpnRegion,
+			VpnTunnelResource: &computepb.VpnTunnel{
+				Name:                  proto.String(vpnTunnelName),
+				Description:           proto.String(fmt.Sprintf("Paraglider VPN tunnel to %s (peer %s)", req.Cloud, peerIpAddress)),
+				PeerIp:                proto.String(peerIpAddress),
+				IkeVersion:            proto.Int32(ikeVersion),
+				SharedSecret:          proto.String(req.SharedKey),
+				TargetVpnGateway:      proto.String(getTargetVpnGatewayUrl(project, vpnRegion, getClassicVpnGwName(req.Deployment.Namespace))),
+				LocalTrafficSelector:  []string{"0.0.0.0/0"},
+				RemoteTrafficSelector: []string{"0.0.0.0/0"},
+			},
+		}
+		insertVpnTunnelOp, err := vpnTunnelsClient.Insert(ctx, insertVpnTunnelReq)
+		if err != nil {
+			if !isErrorDuplicate(err) {
+				return nil, fmt.Errorf("unable to insert vpn tunnel: %w", err)
+			}
+		} else {
+			if err = insertVpnTunnelOp.Wait(ctx); err != nil {
+				return nil, fmt.Errorf("unable to wait on insert vpn tunnel operation: %w", err)
+			}
+			utils.AddSideEffect(ctx, vpnTunnelName, fmt.Sprintf("created VPN tunnel %s to %s", vpnTunnelName, req.Cloud))
+		}
+
+		// Insert static routes
+		for _, remoteAddress := range req.RemoteAddresses {
+			insertRouteReq := &computepb.InsertRouteRequest{
+				Project: project,
+				RouteResource: &computepb.Route{
+					Name:             proto.String(getVpnRouteName(req.Deployment.Namespace, req.Cloud, i, remoteAddress)),
+					Description:      proto.String(fmt.Sprintf("Paraglider route to %s through VPN tunnel to %s", remoteAddress, req.Cloud)),
+					Network:          proto.String(getVpcUrl(project, req.Deployment.Namespace)),
+					DestRange:        proto.String(remoteAddress),
+					NextHopVpnTunnel: proto.String(getVpnTunnelUrl(project, vpnRegion, vpnTunnelName)),
+				},
+			}
+			insertRouteOp, err := routesClient.Insert(ctx, insertRouteReq)
+			if err != nil {
+				if !isErrorDuplicate(err) {
+					return nil, fmt.Errorf("unable to insert route: %w", err)
+				}
+			} else {
+				if err = insertRouteOp.Wait(ctx); err != nil {
+					return nil, fmt.Errorf("unable to wait on insert route operation: %w", err)
+				}
+			}
+		}
+	}
+
+	return &paragliderpb.CreateVpnConnectionsResponse{}, nil
+}
+
 // GetNetworkAddressSpaces returns the address spaces in the virtual network containing the provided address space
 func (s *GCPPluginServer) GetNetworkAddressSpaces(ctx context.Context, req *paragliderpb.GetNetworkAddressSpacesRequest) (*paragliderpb.GetNetworkAddressSpacesResponse, error) {
-	return nil, fmt.Errorf("GetNetworkAddressSpaces is currently not implemented by GCP, implying plugin does not support BGP disabled VPN connections")
+	clients := &GCPClients{}
+	networksClient, err := clients.GetOrCreateNetworksClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get networks client: %w", err)
+	}
+	subnetworksClient, err := clients.GetOrCreateSubnetworksClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get subnetworks client: %w", err)
+	}
+	defer clients.Close()
+	return s._GetNetworkAddressSpaces(ctx, req, networksClient, subnetworksClient)
+}
+
+func (s *GCPPluginServer) _GetNetworkAddressSpaces(ctx context.Context, req *paragliderpb.GetNetworkAddressSpacesRequest, networksClient *compute.NetworksClient, subnetworksClient *compute.SubnetworksClient) (*paragliderpb.GetNetworkAddressSpacesResponse, error) {
+	addressSpace := req.AddressSpace
+	if net.ParseIP(addressSpace) != nil {
+		addressSpace += "/32"
+	}
+
+	project := parseUrl(req.Deployment.Id)["projects"]
+	vpcAddressSpaces, err := getVpcAddressSpaces(ctx, networksClient, subnetworksClient, project, req.Deployment.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get vpc address spaces: %w", err)
+	}
+	// All subnetworks belong to the same (global) VPC network, so all of them are returned
+	for _, vpcAddressSpace := range vpcAddressSpaces {
+		isSubset, err := utils.IsCIDRSubset(addressSpace, vpcAddressSpace)
+		if err != nil {
+			return nil, fmt.Errorf("unable to check address space: %w", err)
+		}
+		if isSubset {
+			return &paragliderpb.GetNetworkAddressSpacesResponse{AddressSpaces: vpcAddressSpaces}, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to locate VPC containing address space: %v", req.AddressSpace)
 }
 
-func Setup(port int, orchestratorServerAddr string) *GCPPluginServer {
-	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", port))
+// GetCapabilities reports which resources, rule features and VPN modes the plugin supports
+func (s *GCPPluginServer) GetCapabilities(ctx context.Context, req *paragliderpb.GetCapabilitiesRequest) (*paragliderpb.GetCapabilitiesResponse, error) {
+	return &paragliderpb.GetCapabilitiesResponse{
+		ResourceTypes: []paragliderpb.ResourceType{paragliderpb.ResourceType_INSTANCE, paragliderpb.ResourceType_CLUSTER, paragliderpb.ResourceType_PRIVATE_ENDPOINT},
+		RuleFeatures:  &paragliderpb.RuleFeatures{PortRanges: true, PortLists: true, Ipv6: true, Icmp: true, DenyRules: true},
+		VpnModes:      []paragliderpb.VpnMode{paragliderpb.VpnMode_BGP, paragliderpb.VpnMode_STATIC},
+		Limits:        &paragliderpb.CapabilityLimits{}, // Firewall rules are limited by a project-wide quota
+	}, nil
+}
+
+// CloudPlugin RPCs the plugin implements, as reported when registering with the controller
+var capabilities = []string{
+	"CreateResource",
+	"DeleteResource",
+	"DetachResource",
+	"DeleteNamespace",
+	"GetPermitList",
+	"AddPermitListRules",
+	"DeletePermitListRules",
+	"GetUsedAddressSpaces",
+	"GetUsedAsns",
+	"GetUsedBgpPeeringIpAddresses",
+	"CreateVpnGateway",
+	"CreateVpnConnections",
+	"GetNetworkAddressSpaces",
+	"GetCapabilities",
+}
+
+func Setup(port int, orchestratorServerAddr string, tlsConfig config.TLS) *GCPPluginServer {
+	return SetupWithAdvertiseAddress(port, orchestratorServerAddr, tlsConfig, "")
+}
+
+// SetupWithAdvertiseAddress starts the plugin server, which registers with the controller at the advertised address (localhost if empty)
+func SetupWithAdvertiseAddress(port int, orchestratorServerAddr string, tlsConfig config.TLS, advertiseAddress string) *GCPPluginServer {
+	creds, err := utils.LoadTLSCredentials(tlsConfig)
+	if err != nil {
+		slog.Error("Failed to setup TLS", "error", err)
+		return nil
+	}
+	listenAddress, registeredAddress := utils.GetPluginAddresses(port, advertiseAddress)
+	lis, err := net.Listen("tcp", listenAddress)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to listen: %v", err)
+		slog.Error("Failed to listen", "error", err)
 	}
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(grpc.Creds(creds), grpc.ChainUnaryInterceptor(utils.MetricsServerInterceptor, utils.LogFieldsServerInterceptor(utils.CloudLogKey, utils.GCP), utils.NoticesServerInterceptor), utils.TracingServerOption())
 	gcpServer := &GCPPluginServer{}
 	gcpServer.orchestratorServerAddr = orchestratorServerAddr
+	gcpServer.orchestratorCredentials = creds
 	paragliderpb.RegisterCloudPluginServer(grpcServer, gcpServer)
-	fmt.Println("Starting server on port :", port)
+	utils.RegisterHealthService(grpcServer)
+	slog.Info("Starting GCP plugin server", "port", port)
 	go func() {
 		if err := grpcServer.Serve(lis); err != nil {
-			fmt.Println(err.Error())
+			slog.Error("GCP plugin server stopped", "error", err)
 		}
 	}()
+	go utils.RegisterPlugin(context.Background(), orchestratorServerAddr, creds, &paragliderpb.RegisterPluginRequest{
+		Name:         utils.GCP,
+		Address:      registeredAddress,
+		Version:      version.Version(),
+		Capabilities: capabilities,
+	})
 	return gcpServer
 }
@@ -268,7 +268,7 @@ func TestCrossNamespace(t *testing.T) {
 	orchestrator.Setup(orchestratorServerConfig, true)
 
 	// Setup GCP plugin server
-	gcpServer := Setup(gcpServerPort, orchestratorServerAddr)
+	gcpServer := Setup(gcpServerPort, orchestratorServerAddr, config.TLS{})
 	ctx := context.Background()
 
 	// Create vm1 in project1
@@ -335,6 +335,168 @@ func TestDeletePermitListRulesWrongNamespace(t *testing.T) {
 	require.Nil(t, resp)
 }
 
+func TestDeleteResource(t *testing.T) {
+	fakeServerState := &fakeServerState{
+		instance: getFakeInstance(true),
+		firewallMap: map[string]*computepb.Firewall{
+			*fakeFirewallRule1.Name: fakeFirewallRule1,
+			*fakeFirewallRule2.Name: fakeFirewallRule2,
+		},
+	}
+	fakeServer, ctx, fakeClients, fakeGRPCServer := setup(t, fakeServerState)
+	defer teardown(fakeServer, fakeClients, fakeGRPCServer)
+
+	s := &GCPPluginServer{}
+	request := &paragliderpb.DeleteResourceRequest{
+		Resource:  fakeResourceId,
+		Namespace: fakeNamespace,
+	}
+
+	resp, err := s._DeleteResource(ctx, request, fakeClients)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+}
+
+func TestDeleteResourceCluster(t *testing.T) {
+	fakeServer, ctx, fakeClients, fakeGRPCServer := setup(t, &fakeServerState{cluster: getFakeCluster(true)})
+	defer teardown(fakeServer, fakeClients, fakeGRPCServer)
+
+	s := &GCPPluginServer{}
+	request := &paragliderpb.DeleteResourceRequest{
+		Resource:  getClusterUrl(fakeProject, fakeZone, fakeClusterName),
+		Namespace: fakeNamespace,
+	}
+
+	resp, err := s._DeleteResource(ctx, request, fakeClients)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+}
+
+func TestDeleteResourceMissingInstance(t *testing.T) {
+	fakeServer, ctx, fakeClients, fakeGRPCServer := setup(t, &fakeServerState{})
+	defer teardown(fakeServer, fakeClients, fakeGRPCServer)
+
+	s := &GCPPluginServer{}
+	request := &paragliderpb.DeleteResourceRequest{
+		Resource:  fakeMissingResourceId,
+		Namespace: fakeNamespace,
+	}
+
+	resp, err := s._DeleteResource(ctx, request, fakeClients)
+	require.Error(t, err)
+	require.Nil(t, resp)
+}
+
+func TestDetachResource(t *testing.T) {
+	fakeServerState := &fakeServerState{
+		instance: getFakeInstance(true),
+		firewallMap: map[string]*computepb.Firewall{
+			*fakeFirewallRule1.Name: fakeFirewallRule1,
+			*fakeFirewallRule2.Name: fakeFirewallRule2,
+		},
+	}
+	fakeServer, ctx, fakeClients, fakeGRPCServer := setup(t, fakeServerState)
+	defer teardown(fakeServer, fakeClients, fakeGRPCServer)
+
+	s := &GCPPluginServer{}
+	request := &paragliderpb.DetachResourceRequest{
+		Resource:  fakeResourceId,
+		Namespace: fakeNamespace,
+	}
+
+	resp, err := s._DetachResource(ctx, request, fakeClients)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+}
+
+func TestDetachResourceCluster(t *testing.T) {
+	fakeServer, ctx, fakeClients, fakeGRPCServer := setup(t, &fakeServerState{cluster: getFakeCluster(true)})
+	defer teardown(fakeServer, fakeClients, fakeGRPCServer)
+
+	s := &GCPPluginServer{}
+	request := &paragliderpb.DetachResourceRequest{
+		Resource:  getClusterUrl(fakeProject, fakeZone, fakeClusterName),
+		Namespace: fakeNamespace,
+	}
+
+	resp, err := s._DetachResource(ctx, request, fakeClients)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+}
+
+func TestDetachResourceMissingInstance(t *testing.T) {
+	fakeServer, ctx, fakeClients, fakeGRPCServer := setup(t, &fakeServerState{})
+	defer teardown(fakeServer, fakeClients, fakeGRPCServer)
+
+	s := &GCPPluginServer{}
+	request := &paragliderpb.DetachResourceRequest{
+		Resource:  fakeMissingResourceId,
+		Namespace: fakeNamespace,
+	}
+
+	resp, err := s._DetachResource(ctx, request, fakeClients)
+	require.Error(t, err)
+	require.Nil(t, resp)
+}
+
+func TestDeleteNamespace(t *testing.T) {
+	fakeServerState := &fakeServerState{
+		firewallMap: map[string]*computepb.Firewall{
+			*fakeFirewallRule1.Name: fakeFirewallRule1,
+			*fakeFirewallRule2.Name: fakeFirewallRule2,
+		},
+		network: &computepb.Network{
+			Name:        proto.String(getVpcName(fakeNamespace)),
+			Subnetworks: []string{fakeSubnetId},
+		},
+		routes: []*computepb.Route{
+			{Name: proto.String(getVpnRouteName(fakeNamespace, utils.IBM, 0, "10.0.0.0/16")), Network: proto.String(getVpcUrl(fakeProject, fakeNamespace))},
+		},
+		vpnTunnels: []*computepb.VpnTunnel{
+			{
+				Name:                proto.String(getVpnTunnelName(fakeNamespace, utils.AZURE, 0)),
+				VpnGateway:          proto.String(getVpnGatewayUrl(fakeProject, fakeRegion, getVpnGwName(fakeNamespace))),
+				PeerExternalGateway: proto.String(getPeerGatewayUrl(fakeProject, getPeerGwName(fakeNamespace, utils.AZURE))),
+			},
+			{
+				Name:             proto.String(getVpnTunnelName(fakeNamespace, utils.IBM, 0)),
+				TargetVpnGateway: proto.String(getTargetVpnGatewayUrl(fakeProject, fakeRegion, getClassicVpnGwName(fakeNamespace))),
+			},
+			{
+				Name:       proto.String(getVpnTunnelName("other", utils.AZURE, 0)),
+				VpnGateway: proto.String(getVpnGatewayUrl(fakeProject, fakeRegion, getVpnGwName("other"))),
+			},
+		},
+	}
+	fakeServer, ctx, fakeClients, fakeGRPCServer := setup(t, fakeServerState)
+	defer teardown(fakeServer, fakeClients, fakeGRPCServer)
+
+	s := &GCPPluginServer{}
+	vpnRegion = fakeRegion
+
+	req := &paragliderpb.DeleteNamespaceRequest{
+		Deployment: &paragliderpb.ParagliderDeployment{Id: fmt.Sprintf("projects/%s", fakeProject), Namespace: fakeNamespace},
+	}
+	resp, err := s._DeleteNamespace(ctx, req, fakeClients)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+}
+
+func TestDeleteNamespaceMissingNetwork(t *testing.T) {
+	fakeServer, ctx, fakeClients, fakeGRPCServer := setup(t, &fakeServerState{})
+	defer teardown(fakeServer, fakeClients, fakeGRPCServer)
+
+	s := &GCPPluginServer{}
+	vpnRegion = fakeRegion
+
+	req := &paragliderpb.DeleteNamespaceRequest{
+		Deployment: &paragliderpb.ParagliderDeployment{Id: fmt.Sprintf("projects/%s", fakeProject), Namespace: fakeNamespace},
+	}
+	resp, err := s._DeleteNamespace(ctx, req, fakeClients)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+}
+
 func TestCreateResource(t *testing.T) {
 	fakeServerState := &fakeServerState{
 		instance: getFakeInstance(true), // Include instance in server state since CreateResource will fetch after creating to add the tag
@@ -433,6 +595,36 @@ func TestCreateResourceMissingNetwork(t *testing.T) {
 	require.NotNil(t, resp)
 }
 
+func TestCreateResourceMissingNetworkDualStack(t *testing.T) {
+	// Include instance in server state since CreateResource will fetch after creating to add the tag
+	fakeServer, ctx, fakeClients, fakeGRPCServer := setup(t, &fakeServerState{instance: getFakeInstance(true)})
+	defer teardown(fakeServer, fakeClients, fakeGRPCServer)
+
+	fakeOrchestratorServer, fakeOrchestratorServerAddr, err := fake.SetupFakeOrchestratorRPCServer(utils.GCP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeOrchestratorServer.Ipv6 = true
+	s := &GCPPluginServer{orchestratorServerAddr: fakeOrchestratorServerAddr}
+	description, err := json.Marshal(&computepb.InsertInstanceRequest{
+		Project:          fakeProject,
+		Zone:             fakeZone,
+		InstanceResource: getFakeInstance(false),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resource := &paragliderpb.CreateResourceRequest{
+		Deployment:  &paragliderpb.ParagliderDeployment{Id: "projects/" + fakeProject, Namespace: fakeNamespace},
+		Name:        fakeInstanceName,
+		Description: description,
+	}
+
+	resp, err := s._CreateResource(ctx, resource, fakeClients)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+}
+
 func TestCreateResourceMissingSubnetwork(t *testing.T) {
 	fakeServerState := &fakeServerState{
 		instance: getFakeInstance(true), // Include instance in server state since CreateResource will fetch after creating to add the tag
@@ -501,6 +693,44 @@ func TestGetUsedAddressSpaces(t *testing.T) {
 	assert.ElementsMatch(t, expectedAddressSpaceMappings, resp.AddressSpaceMappings)
 }
 
+func TestGetUsedAddressSpacesDualStack(t *testing.T) {
+	fakeServerState := &fakeServerState{
+		network: &computepb.Network{
+			Name:                  proto.String(getVpcName(fakeNamespace)),
+			EnableUlaInternalIpv6: proto.Bool(true),
+			InternalIpv6Range:     proto.String("fd20:a::/48"),
+			Subnetworks: []string{
+				"https://www.googleapis.com/compute/v1/projects/paraglider-playground/regions/us-fake1/subnetworks/paraglider-us-fake1-subnet",
+			},
+		},
+		subnetwork: &computepb.Subnetwork{
+			IpCidrRange:        proto.String("10.1.2.0/24"),
+			InternalIpv6Prefix: proto.String("fd20:a:0:1::/64"),
+		},
+	}
+	fakeServer, ctx, fakeClients, fakeGRPCServer := setup(t, fakeServerState)
+	defer teardown(fakeServer, fakeClients, fakeGRPCServer)
+
+	s := &GCPPluginServer{}
+
+	expectedAddressSpaceMappings := []*paragliderpb.AddressSpaceMapping{
+		{
+			AddressSpaces: []string{"fd20:a::/48", "10.1.2.0/24"},
+			Cloud:         utils.GCP,
+			Namespace:     fakeNamespace,
+		},
+	}
+	req := &paragliderpb.GetUsedAddressSpacesRequest{
+		Deployments: []*paragliderpb.ParagliderDeployment{
+			{Id: "projects/" + fakeProject, Namespace: fakeNamespace},
+		},
+	}
+	resp, err := s._GetUsedAddressSpaces(ctx, req, fakeClients.networksClient, fakeClients.subnetworksClient, fakeClients.addressesClient)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	assert.ElementsMatch(t, expectedAddressSpaceMappings, resp.AddressSpaceMappings)
+}
+
 func TestGetUsedAsns(t *testing.T) {
 	fakeServerState := &fakeServerState{
 		router: &computepb.Router{
@@ -605,3 +835,245 @@ func TestCreateVpnConnections(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, resp)
 }
+
+func TestCreateClassicVpnGateway(t *testing.T) {
+	fakeServerState := &fakeServerState{address: getFakeAddress()}
+	fakeServer, ctx, fakeClients, fakeGRPCServer := setup(t, fakeServerState)
+	defer teardown(fakeServer, fakeClients, fakeGRPCServer)
+
+	s := &GCPPluginServer{}
+	vpnRegion = fakeRegion
+
+	req := &paragliderpb.CreateVpnGatewayRequest{
+		Deployment: &paragliderpb.ParagliderDeployment{Id: fmt.Sprintf("projects/%s/regions/%s", fakeProject, fakeRegion)},
+		Cloud:      utils.IBM,
+	}
+	resp, err := s._CreateClassicVpnGateway(ctx, req, fakeClients.addressesClient, fakeClients.targetVpnGatewaysClient, fakeClients.forwardingClient)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	require.Zero(t, resp.Asn)
+	require.ElementsMatch(t, []string{fakeIpAddress}, resp.GatewayIpAddresses)
+}
+
+func TestCreateClassicVpnConnections(t *testing.T) {
+	fakeServerState := &fakeServerState{}
+	fakeServer, ctx, fakeClients, fakeGRPCServer := setup(t, fakeServerState)
+	defer teardown(fakeServer, fakeClients, fakeGRPCServer)
+
+	s := &GCPPluginServer{}
+	vpnRegion = fakeRegion
+
+	req := &paragliderpb.CreateVpnConnectionsRequest{
+		Deployment:         &paragliderpb.ParagliderDeployment{Id: fmt.Sprintf("projects/%s/regions/%s", fakeProject, fakeRegion)},
+		Cloud:              utils.IBM,
+		GatewayIpAddresses: []string{"1.1.1.1", "2.2.2.2"},
+		SharedKey:          "abcd",
+		RemoteAddresses:    []string{"10.2.0.0/16"},
+		IsBgpDisabled:      true,
+	}
+	resp, err := s._CreateClassicVpnConnections(ctx, req, fakeClients.vpnTunnelsClient, fakeClients.routesClient)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	// Remote addresses are required to create static routes
+	req.RemoteAddresses = nil
+	resp, err = s._CreateClassicVpnConnections(ctx, req, fakeClients.vpnTunnelsClient, fakeClients.routesClient)
+	require.Error(t, err)
+	require.Nil(t, resp)
+}
+
+func TestGetNetworkAddressSpaces(t *testing.T) {
+	fakeServerState := &fakeServerState{
+		network: &computepb.Network{
+			Name:        proto.String(getVpcName(fakeNamespace)),
+			Subnetworks: []string{fakeSubnetId},
+		},
+		subnetwork: &computepb.Subnetwork{
+			IpCidrRange: proto.String("10.1.2.0/24"),
+		},
+	}
+	fakeServer, ctx, fakeClients, fakeGRPCServer := setup(t, fakeServerState)
+	defer teardown(fakeServer, fakeClients, fakeGRPCServer)
+
+	s := &GCPPluginServer{}
+	deployment := &paragliderpb.ParagliderDeployment{Id: "projects/" + fakeProject, Namespace: fakeNamespace}
+
+	// IP address within the VPC
+	req := &paragliderpb.GetNetworkAddressSpacesRequest{Deployment: deployment, AddressSpace: "10.1.2.3"}
+	resp, err := s._GetNetworkAddressSpaces(ctx, req, fakeClients.networksClient, fakeClients.subnetworksClient)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	assert.ElementsMatch(t, []string{"10.1.2.0/24"}, resp.AddressSpaces)
+
+	// Address space outside the VPC
+	req = &paragliderpb.GetNetworkAddressSpacesRequest{Deployment: deployment, AddressSpace: "10.3.0.0/16"}
+	resp, err = s._GetNetworkAddressSpaces(ctx, req, fakeClients.networksClient, fakeClients.subnetworksClient)
+	require.Error(t, err)
+	require.Nil(t, resp)
+}
+
+func TestFirewallRulePortRanges(t *testing.T) {
+	rule := &paragliderpb.PermitListRule{
+		Name:      "rule-name",
+		Direction: paragliderpb.Direction_INBOUND,
+		SrcPort:   -1,
+		DstPorts:  []*paragliderpb.PortRange{{Start: 443, End: 443}, {Start: 8000, End: 8100}},
+		Protocol:  6,
+		Targets:   []string{"10.1.2.0/24"},
+		Tags:      []string{"tag1"},
+	}
+	firewallName := getFirewallName(fakeNamespace, rule.Name, convertIntIdToString(fakeInstanceId))
+	firewall, err := paragliderRuleToFirewallRule(fakeNamespace, fakeProject, firewallName, firewallTarget{TargetType: targetTypeTag, Target: fakeNetworkTag}, rule)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"443", "8000-8100"}, firewall.Allowed[0].Ports)
+
+	converted, err := firewallRuleToParagliderRule(fakeNamespace, firewall)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(rule, converted))
+
+	equal, err := isFirewallEqPermitListRule(fakeNamespace, firewall, rule)
+	require.NoError(t, err)
+	assert.True(t, equal)
+
+	rule.DstPorts = rule.DstPorts[:1]
+	equal, err = isFirewallEqPermitListRule(fakeNamespace, firewall, rule)
+	require.NoError(t, err)
+	assert.False(t, equal)
+}
+
+func TestFirewallRuleDenyAndPriority(t *testing.T) {
+	rule := &paragliderpb.PermitListRule{
+		Name:      "rule-name",
+		Direction: paragliderpb.Direction_INBOUND,
+		SrcPort:   -1,
+		DstPort:   22,
+		Protocol:  6,
+		Targets:   []string{"10.1.2.3"},
+		Tags:      []string{"10.1.2.3"},
+		Action:    paragliderpb.RuleAction_DENY,
+		Priority:  2,
+	}
+	firewallName := getFirewallName(fakeNamespace, rule.Name, convertIntIdToString(fakeInstanceId))
+	firewall, err := paragliderRuleToFirewallRule(fakeNamespace, fakeProject, firewallName, firewallTarget{TargetType: targetTypeTag, Target: fakeNetworkTag}, rule)
+	require.NoError(t, err)
+	assert.Empty(t, firewall.Allowed)
+	require.Len(t, firewall.Denied, 1)
+	assert.Equal(t, []string{"22"}, firewall.Denied[0].Ports)
+	assert.Equal(t, firewallPriorityBase+2, *firewall.Priority)
+
+	converted, err := firewallRuleToParagliderRule(fakeNamespace, firewall)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(rule, converted))
+
+	rule.Action = paragliderpb.RuleAction_ALLOW
+	equal, err := isFirewallEqPermitListRule(fakeNamespace, firewall, rule)
+	require.NoError(t, err)
+	assert.False(t, equal)
+
+	// Firewalls with priorities Paraglider doesn't use are read as having the default priority
+	firewall.Priority = proto.Int32(500)
+	converted, err = firewallRuleToParagliderRule(fakeNamespace, firewall)
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), converted.Priority)
+}
+
+func TestFirewallRuleIpv6(t *testing.T) {
+	rule := &paragliderpb.PermitListRule{
+		Name:      "rule-name",
+		Direction: paragliderpb.Direction_OUTBOUND,
+		SrcPort:   -1,
+		DstPort:   443,
+		Protocol:  6,
+		Targets:   []string{"2606:4700:4700::1111/128"},
+	}
+	firewallName := getFirewallName(fakeNamespace, rule.Name, convertIntIdToString(fakeInstanceId))
+	firewall, err := paragliderRuleToFirewallRule(fakeNamespace, fakeProject, firewallName, firewallTarget{TargetType: targetTypeTag, Target: fakeNetworkTag}, rule)
+	require.NoError(t, err)
+	assert.Equal(t, rule.Targets, firewall.DestinationRanges)
+
+	// Resources matched by their IPv4 address can't have IPv6 rules
+	_, err = paragliderRuleToFirewallRule(fakeNamespace, fakeProject, firewallName, firewallTarget{TargetType: targetTypeAddress, Target: "10.1.2.3"}, rule)
+	require.Error(t, err)
+
+	// Firewalls can't mix IPv4 and IPv6 ranges
+	rule.Targets = append(rule.Targets, "1.1.1.1/32")
+	_, err = paragliderRuleToFirewallRule(fakeNamespace, fakeProject, firewallName, firewallTarget{TargetType: targetTypeTag, Target: fakeNetworkTag}, rule)
+	require.Error(t, err)
+}
+
+func TestAddPermitListRulesExistingRuleDeny(t *testing.T) {
+	fakeServerState := &fakeServerState{
+		instance: getFakeInstance(true),
+		subnetwork: &computepb.Subnetwork{
+			IpCidrRange: proto.String("10.0.0.0/16"),
+		},
+		firewallMap: map[string]*computepb.Firewall{
+			*fakeFirewallRule1.Name: fakeFirewallRule1,
+		},
+		network: &computepb.Network{
+			Name: proto.String(getVpcName(fakeNamespace)),
+		},
+	}
+	fakeServer, ctx, fakeClients, fakeGRPCServer := setup(t, fakeServerState)
+	defer teardown(fakeServer, fakeClients, fakeGRPCServer)
+
+	fakeOrchestratorServer, fakeOrchestratorServerAddr, err := fake.SetupFakeOrchestratorRPCServer(utils.GCP)
+	fakeOrchestratorServer.Counter = 1
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &GCPPluginServer{orchestratorServerAddr: fakeOrchestratorServerAddr}
+	// The existing firewall allows traffic, so it gets recreated to deny it
+	denyRule := proto.Clone(fakePermitListRule1).(*paragliderpb.PermitListRule)
+	denyRule.Action = paragliderpb.RuleAction_DENY
+	denyRule.Targets = []string{"10.0.0.1"}
+	request := &paragliderpb.AddPermitListRulesRequest{
+		Resource:  fakeResourceId,
+		Rules:     []*paragliderpb.PermitListRule{denyRule},
+		Namespace: fakeNamespace,
+	}
+
+	resp, err := s._AddPermitListRules(ctx, request, fakeClients)
+
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+}
+
+func TestAddPermitListRulesDenyRemoteCloudTarget(t *testing.T) {
+	fakeServerState := &fakeServerState{
+		instance: getFakeInstance(true),
+		subnetwork: &computepb.Subnetwork{
+			IpCidrRange: proto.String("10.0.0.0/16"),
+		},
+		network: &computepb.Network{
+			Name: proto.String(getVpcName(fakeNamespace)),
+		},
+	}
+	fakeServer, ctx, fakeClients, fakeGRPCServer := setup(t, fakeServerState)
+	defer teardown(fakeServer, fakeClients, fakeGRPCServer)
+
+	fakeOrchestratorServer, fakeOrchestratorServerAddr, err := fake.SetupFakeOrchestratorRPCServer(utils.GCP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeOrchestratorServer.Counter = 1
+	fakeOrchestratorServer.RemoteAddressSpaceMappings = []*paragliderpb.AddressSpaceMapping{
+		{AddressSpaces: []string{"10.250.0.0/16"}, Cloud: utils.AZURE, Namespace: fakeNamespace},
+	}
+	s := &GCPPluginServer{orchestratorServerAddr: fakeOrchestratorServerAddr}
+	// Blocking traffic to another cloud doesn't need the clouds to be connected
+	denyRule := proto.Clone(fakePermitListRule1).(*paragliderpb.PermitListRule)
+	denyRule.Action = paragliderpb.RuleAction_DENY
+	denyRule.Targets = []string{"10.250.0.5"}
+	request := &paragliderpb.AddPermitListRulesRequest{
+		Resource:  fakeResourceId,
+		Rules:     []*paragliderpb.PermitListRule{denyRule},
+		Namespace: fakeNamespace,
+	}
+
+	resp, err := s._AddPermitListRules(ctx, request, fakeClients)
+
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	assert.Zero(t, fakeOrchestratorServer.ConnectCloudsCalls)
+}
@@ -234,6 +234,9 @@ func getFakeServerHandler(fakeServerState *fakeServerState) http.HandlerFunc {
 			if r.Method == "GET" {
 				sendResponse(w, fakeServerState.instance)
 				return
+			} else if r.Method == "DELETE" {
+				sendResponseFakeOperation(w)
+				return
 			}
 		case path == urlProject+urlZone+"/instances":
 			if r.Method == "POST" {
@@ -279,7 +282,7 @@ func getFakeServerHandler(fakeServerState *fakeServerState) http.HandlerFunc {
 					http.Error(w, "no network found", http.StatusNotFound)
 				}
 				return
-			} else if r.Method == "POST" {
+			} else if r.Method == "POST" || r.Method == "DELETE" {
 				sendResponseFakeOperation(w)
 				return
 			}
@@ -291,7 +294,7 @@ func getFakeServerHandler(fakeServerState *fakeServerState) http.HandlerFunc {
 					http.Error(w, "no subnetwork found", http.StatusNotFound)
 				}
 				return
-			} else if r.Method == "POST" {
+			} else if r.Method == "POST" || r.Method == "DELETE" {
 				sendResponseFakeOperation(w)
 				return
 			}
@@ -304,13 +307,13 @@ func getFakeServerHandler(fakeServerState *fakeServerState) http.HandlerFunc {
 					http.Error(w, "no address found", http.StatusNotFound)
 				}
 				return
-			} else if r.Method == "POST" {
+			} else if r.Method == "POST" || r.Method == "DELETE" {
 				sendResponseFakeOperation(w)
 				return
 			}
 		// Forwarding Rules
 		case strings.HasPrefix(path, urlProject+urlRegion+"/forwardingRules"):
-			if r.Method == "POST" {
+			if r.Method == "POST" || r.Method == "DELETE" {
 				sendResponseFakeOperation(w)
 				return
 			} else if r.Method == "GET" {
@@ -330,25 +333,43 @@ func getFakeServerHandler(fakeServerState *fakeServerState) http.HandlerFunc {
 					http.Error(w, "no vpn gateway found", http.StatusNotFound)
 				}
 				return
-			} else if r.Method == "POST" {
+			} else if r.Method == "POST" || r.Method == "DELETE" {
+				sendResponseFakeOperation(w)
+				return
+			}
+		// Target (Classic) VPN Gateways
+		case strings.HasPrefix(path, urlProject+urlRegion+"/targetVpnGateways"):
+			if r.Method == "POST" || r.Method == "DELETE" {
+				sendResponseFakeOperation(w)
+				return
+			}
+		// Routes
+		case strings.HasPrefix(path, urlProject+"/global/routes"):
+			if r.Method == "POST" || r.Method == "DELETE" {
 				sendResponseFakeOperation(w)
 				return
+			} else if r.Method == "GET" {
+				sendResponse(w, &computepb.RouteList{Items: fakeServerState.routes})
+				return
 			}
 		// External VPN Gateways
 		case strings.HasPrefix(path, urlProject+"/global/externalVpnGateways"):
-			if r.Method == "POST" {
+			if r.Method == "POST" || r.Method == "DELETE" {
 				sendResponseFakeOperation(w)
 				return
 			}
 		// VPN Tunnels
 		case strings.HasPrefix(path, urlProject+urlRegion+"/vpnTunnels"):
-			if r.Method == "POST" {
+			if r.Method == "POST" || r.Method == "DELETE" {
 				sendResponseFakeOperation(w)
 				return
+			} else if r.Method == "GET" {
+				sendResponse(w, &computepb.VpnTunnelList{Items: fakeServerState.vpnTunnels})
+				return
 			}
 		// Routers
 		case strings.HasPrefix(path, urlProject+urlRegion+"/routers"):
-			if r.Method == "POST" || r.Method == "PATCH" {
+			if r.Method == "POST" || r.Method == "PATCH" || r.Method == "DELETE" {
 				sendResponseFakeOperation(w)
 				return
 			} else if r.Method == "GET" {
@@ -396,6 +417,10 @@ func (f *fakeClusterManagerServer) CreateCluster(ctx context.Context, req *conta
 	return &containerpb.Operation{Name: fakeOperation}, nil
 }
 
+func (f *fakeClusterManagerServer) DeleteCluster(ctx context.Context, req *containerpb.DeleteClusterRequest) (*containerpb.Operation, error) {
+	return &containerpb.Operation{Name: fakeOperation}, nil
+}
+
 func (f *fakeClusterManagerServer) UpdateCluster(ctx context.Context, req *containerpb.UpdateClusterRequest) (*containerpb.Operation, error) {
 	return &containerpb.Operation{Name: fakeOperation}, nil
 }
@@ -411,6 +436,8 @@ type fakeServerState struct {
 	cluster        *containerpb.Cluster
 	address        *computepb.Address
 	forwardingRule *computepb.ForwardingRule
+	routes         []*computepb.Route
+	vpnTunnels     []*computepb.VpnTunnel
 }
 
 // Sets up fake http server and fake GCP compute clients
@@ -477,6 +504,16 @@ func setup(t *testing.T, fakeServerState *fakeServerState) (fakeServer *httptest
 		t.Fatal(err)
 	}
 
+	fakeClients.targetVpnGatewaysClient, err = compute.NewTargetVpnGatewaysRESTClient(ctx, clientOptions...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fakeClients.routesClient, err = compute.NewRoutesRESTClient(ctx, clientOptions...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	l, err := net.Listen("tcp", "localhost:0")
 	if err != nil {
 		t.Fatal(err)
@@ -276,3 +276,14 @@ func isErrorDuplicate(err error) bool {
 	ok := errors.As(err, &e)
 	return ok && e.Code == http.StatusConflict
 }
+
+// Waits for a delete operation to finish, treating resources which don't exist as already deleted
+func waitForDeletion(ctx context.Context, op *compute.Operation, err error) error {
+	if err != nil {
+		if isErrorNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return op.Wait(ctx)
+}
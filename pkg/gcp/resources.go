@@ -52,6 +52,7 @@ type resourceInfo struct {
 	ResourceType               string
 	CreatesOwnSubnet           bool
 	NumAdditionalAddressSpaces int
+	DualStack                  bool // Whether the resource is placed in a dual-stack subnet
 }
 
 type resourceNetworkInfo struct {
@@ -76,6 +77,17 @@ func getNetworkTag(namespace string, resourceType string, resourceId string) str
 	return getParagliderNamespacePrefix(namespace) + "-" + resourceType + "-" + resourceId
 }
 
+// Filters out the Paraglider network tags of a namespace
+func removeParagliderNetworkTags(namespace string, tags []string) []string {
+	remainingTags := []string{}
+	for _, tag := range tags {
+		if !strings.HasPrefix(tag, getParagliderNamespacePrefix(namespace)+"-") {
+			remainingTags = append(remainingTags, tag)
+		}
+	}
+	return remainingTags
+}
+
 // Get name for an IP address resource
 func getAddressName(resourceName string) string {
 	return paragliderPrefix + "-" + resourceName + "-address"
@@ -109,6 +121,11 @@ func getClusterNodeTag(namespace string, clusterName string, clusterId string) s
 	return getParagliderNamespacePrefix(namespace) + "-gke-" + clusterName + "-" + shortenClusterId(clusterId) + "-node"
 }
 
+// Get the name of the firewall rule allowing traffic to/from a cluster's control plane
+func getControlPlaneFirewallName(direction string, clusterName string) string {
+	return "paraglider-allow-control-plane-" + strings.ToLower(direction) + "-" + clusterName
+}
+
 // getInstanceUrl returns a fully qualified URL for an instance
 func getInstanceUrl(project, zone, instance string) string {
 	return computeUrlPrefix + fmt.Sprintf("projects/%s/zones/%s/instances/%s", project, zone, instance)
@@ -147,6 +164,35 @@ func getFirewallRules(ctx context.Context, project string, resourceID string, cl
 	return firewallRules, nil
 }
 
+// Delete the firewall rules corresponding to a resource's Paraglider permit list
+func deletePermitListFirewalls(ctx context.Context, resourceInfo *resourceInfo, netInfo *resourceNetworkInfo, clients *GCPClients) error {
+	firewalls, err := getFirewallRules(ctx, resourceInfo.Project, netInfo.ResourceID, clients)
+	if err != nil {
+		return fmt.Errorf("unable to get firewalls: %w", err)
+	}
+	firewallsClient, err := clients.GetOrCreateFirewallsClient(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get firewalls client: %w", err)
+	}
+	for _, firewall := range firewalls {
+		if !isParagliderPermitListRule(resourceInfo.Namespace, firewall) {
+			continue
+		}
+		deleteFirewallReq := &computepb.DeleteFirewallRequest{
+			Firewall: *firewall.Name,
+			Project:  resourceInfo.Project,
+		}
+		deleteFirewallOp, err := firewallsClient.Delete(ctx, deleteFirewallReq)
+		if err != nil {
+			return fmt.Errorf("unable to delete firewall: %w", err)
+		}
+		if err = deleteFirewallOp.Wait(ctx); err != nil {
+			return fmt.Errorf("unable to wait for the operation: %w", err)
+		}
+	}
+	return nil
+}
+
 // parseResourceUrl parses the resource URL and returns information about the resource (such as project, zone, name, and type)
 func parseResourceUrl(resourceUrl string) (*resourceInfo, error) {
 	parsedResourceId := parseUrl(resourceUrl)
@@ -265,6 +311,10 @@ type GCPResourceHandler interface {
 	initClients(ctx context.Context, clients *GCPClients) error
 	// Get target for firewall rules
 	getFirewallTarget(resourceInfo *resourceInfo, netInfo *resourceNetworkInfo) firewallTarget
+	// Delete the resource along with any supporting infrastructure created alongside it
+	deleteResource(ctx context.Context, resourceInfo *resourceInfo, netInfo *resourceNetworkInfo) error
+	// Remove Paraglider artifacts (e.g., network tags) from the resource while leaving it running
+	detachResource(ctx context.Context, resourceInfo *resourceInfo, netInfo *resourceNetworkInfo) error
 }
 
 // GCP instance resource handler
@@ -346,6 +396,9 @@ func (r *instanceHandler) createWithNetwork(ctx context.Context, instance *compu
 			Subnetwork: proto.String(getSubnetworkUrl(resourceInfo.Project, resourceInfo.Region, subnetName)),
 		},
 	}
+	if resourceInfo.DualStack {
+		instance.InstanceResource.NetworkInterfaces[0].StackType = proto.String(computepb.NetworkInterface_IPV4_IPV6.String())
+	}
 
 	// Insert instance
 	insertInstanceOp, err := r.client.Insert(ctx, instance)
@@ -393,6 +446,57 @@ func (r *instanceHandler) createWithNetwork(ctx context.Context, instance *compu
 	return getInstanceUrl(resourceInfo.Project, resourceInfo.Zone, instanceName), *getInstanceResp.NetworkInterfaces[0].NetworkIP, nil
 }
 
+// Delete an instance
+func (r *instanceHandler) deleteResource(ctx context.Context, resourceInfo *resourceInfo, netInfo *resourceNetworkInfo) error {
+	deleteInstanceReq := &computepb.DeleteInstanceRequest{
+		Instance: resourceInfo.Name,
+		Project:  resourceInfo.Project,
+		Zone:     resourceInfo.Zone,
+	}
+	deleteInstanceOp, err := r.client.Delete(ctx, deleteInstanceReq)
+	if err != nil {
+		return fmt.Errorf("unable to delete instance: %w", err)
+	}
+	if err = deleteInstanceOp.Wait(ctx); err != nil {
+		return fmt.Errorf("unable to wait for the operation: %w", err)
+	}
+	return nil
+}
+
+// Remove the Paraglider network tags from an instance
+func (r *instanceHandler) detachResource(ctx context.Context, resourceInfo *resourceInfo, netInfo *resourceNetworkInfo) error {
+	getInstanceReq := &computepb.GetInstanceRequest{
+		Instance: resourceInfo.Name,
+		Project:  resourceInfo.Project,
+		Zone:     resourceInfo.Zone,
+	}
+	getInstanceResp, err := r.client.Get(ctx, getInstanceReq)
+	if err != nil {
+		return fmt.Errorf("unable to get instance: %w", err)
+	}
+	if getInstanceResp.Tags == nil {
+		return nil
+	}
+
+	setTagsReq := &computepb.SetTagsInstanceRequest{
+		Instance: resourceInfo.Name,
+		Project:  resourceInfo.Project,
+		Zone:     resourceInfo.Zone,
+		TagsResource: &computepb.Tags{
+			Items:       removeParagliderNetworkTags(resourceInfo.Namespace, getInstanceResp.Tags.Items),
+			Fingerprint: getInstanceResp.Tags.Fingerprint,
+		},
+	}
+	setTagsOp, err := r.client.SetTags(ctx, setTagsReq)
+	if err != nil {
+		return fmt.Errorf("unable to set tags: %w", err)
+	}
+	if err = setTagsOp.Wait(ctx); err != nil {
+		return fmt.Errorf("unable to wait for the operation: %w", err)
+	}
+	return nil
+}
+
 // Parse the resource description and return the instance request
 func (r *instanceHandler) fromResourceDecription(resourceDesc []byte) (*computepb.InsertInstanceRequest, error) {
 	insertInstanceRequest := &computepb.InsertInstanceRequest{}
@@ -522,7 +626,7 @@ func (r *clusterHandler) createWithNetwork(ctx context.Context, cluster *contain
 				},
 				Description: proto.String("Paraglider allow cluster egress traffic"),
 				Direction:   proto.String(direction),
-				Name:        proto.String("paraglider-allow-control-plane-" + strings.ToLower(direction) + "-" + resourceInfo.Name),
+				Name:        proto.String(getControlPlaneFirewallName(direction, resourceInfo.Name)),
 				Network:     proto.String(getVpcUrl(resourceInfo.Project, resourceInfo.Namespace)),
 				Priority:    proto.Int32(65500),
 				TargetTags:  []string{getClusterNodeTag(resourceInfo.Namespace, getClusterResp.Name, getClusterResp.Id)},
@@ -569,6 +673,60 @@ func (r *clusterHandler) createWithNetwork(ctx context.Context, cluster *contain
 	return getClusterUrl(resourceInfo.Project, resourceInfo.Zone, getClusterResp.Name), getClusterResp.ClusterIpv4Cidr, nil
 }
 
+// Delete a cluster and the firewall rules allowing traffic to/from its control plane
+func (r *clusterHandler) deleteResource(ctx context.Context, resourceInfo *resourceInfo, netInfo *resourceNetworkInfo) error {
+	directions := []string{computepb.Firewall_INGRESS.String(), computepb.Firewall_EGRESS.String()}
+	for _, direction := range directions {
+		deleteFirewallReq := &computepb.DeleteFirewallRequest{
+			Firewall: getControlPlaneFirewallName(direction, resourceInfo.Name),
+			Project:  resourceInfo.Project,
+		}
+		deleteFirewallOp, err := r.firewallsClient.Delete(ctx, deleteFirewallReq)
+		if err != nil {
+			if isErrorNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("unable to delete firewall rule: %w", err)
+		}
+		if err = deleteFirewallOp.Wait(ctx); err != nil {
+			return fmt.Errorf("unable to wait for the operation: %w", err)
+		}
+	}
+
+	deleteClusterReq := &containerpb.DeleteClusterRequest{
+		Name: fmt.Sprintf(clusterNameFormat, resourceInfo.Project, resourceInfo.Zone, resourceInfo.Name),
+	}
+	_, err := r.client.DeleteCluster(ctx, deleteClusterReq)
+	if err != nil {
+		return fmt.Errorf("unable to delete cluster: %w", err)
+	}
+	return nil
+}
+
+// Remove the Paraglider network tags from a cluster's nodes
+// The control plane firewall rules are kept since the cluster relies on them to function
+func (r *clusterHandler) detachResource(ctx context.Context, resourceInfo *resourceInfo, netInfo *resourceNetworkInfo) error {
+	clusterName := fmt.Sprintf(clusterNameFormat, resourceInfo.Project, resourceInfo.Zone, resourceInfo.Name)
+	getClusterResp, err := r.client.GetCluster(ctx, &containerpb.GetClusterRequest{Name: clusterName})
+	if err != nil {
+		return fmt.Errorf("unable to get cluster: %w", err)
+	}
+
+	updateClusterRequest := &containerpb.UpdateClusterRequest{
+		Name: clusterName,
+		Update: &containerpb.ClusterUpdate{
+			DesiredNodePoolAutoConfigNetworkTags: &containerpb.NetworkTags{
+				Tags: removeParagliderNetworkTags(resourceInfo.Namespace, getClusterResp.NodePools[0].Config.Tags),
+			},
+		},
+	}
+	_, err = r.client.UpdateCluster(ctx, updateClusterRequest)
+	if err != nil {
+		return fmt.Errorf("unable to set tags: %w", err)
+	}
+	return nil
+}
+
 // Parse the resource description and return the cluster request
 func (r *clusterHandler) fromResourceDecription(resourceDesc []byte) (*containerpb.CreateClusterRequest, error) {
 	createClusterRequest := &containerpb.CreateClusterRequest{}
@@ -772,3 +930,38 @@ func (r *privateServiceHandler) createWithNetwork(ctx context.Context, service S
 
 	return *forwardingRule.SelfLink, *addr.Address, nil
 }
+
+// Delete a private service connect endpoint and release its reserved address
+func (r *privateServiceHandler) deleteResource(ctx context.Context, resourceInfo *resourceInfo, netInfo *resourceNetworkInfo) error {
+	deleteForwardingRuleReq := &computepb.DeleteForwardingRuleRequest{
+		Project:        resourceInfo.Project,
+		Region:         resourceInfo.Region,
+		ForwardingRule: getForwardingRuleName(resourceInfo.Name),
+	}
+	deleteForwardingRuleOp, err := r.forwardingClient.Delete(ctx, deleteForwardingRuleReq)
+	if err != nil {
+		return fmt.Errorf("unable to delete forwarding rule: %w", err)
+	}
+	if err = deleteForwardingRuleOp.Wait(ctx); err != nil {
+		return fmt.Errorf("unable to wait for the operation: %w", err)
+	}
+
+	deleteAddressReq := &computepb.DeleteAddressRequest{
+		Project: resourceInfo.Project,
+		Region:  resourceInfo.Region,
+		Address: getAddressName(resourceInfo.Name),
+	}
+	deleteAddressOp, err := r.addressesClient.Delete(ctx, deleteAddressReq)
+	if err != nil {
+		return fmt.Errorf("unable to delete address: %w", err)
+	}
+	if err = deleteAddressOp.Wait(ctx); err != nil {
+		return fmt.Errorf("unable to wait for the operation: %w", err)
+	}
+	return nil
+}
+
+// Private service connect endpoints have no Paraglider artifacts beyond their permit list
+func (r *privateServiceHandler) detachResource(ctx context.Context, resourceInfo *resourceInfo, netInfo *resourceNetworkInfo) error {
+	return nil
+}
@@ -502,3 +502,11 @@ func TestPrivateServiceCreateWithNetwork(t *testing.T) {
 	assert.Equal(t, *getFakeForwardingRule().SelfLink, url)
 	assert.Equal(t, *getFakeAddress().Address, ip)
 }
+
+func TestRemoveParagliderNetworkTags(t *testing.T) {
+	otherNamespaceTag := getNetworkTag("other", instanceTypeName, "1234")
+	tags := []string{"user-tag", fakeNetworkTag, otherNamespaceTag}
+
+	remainingTags := removeParagliderNetworkTags(fakeNamespace, tags)
+	assert.ElementsMatch(t, []string{"user-tag", otherNamespaceTag}, remainingTags)
+}
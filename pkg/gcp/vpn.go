@@ -17,8 +17,12 @@ limitations under the License.
 package gcp
 
 import (
+	"context"
 	"fmt"
 	"strconv"
+
+	computepb "cloud.google.com/go/compute/apiv1/computepb"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
@@ -36,6 +40,26 @@ func getRouterName(namespace string) string {
 	return getParagliderNamespacePrefix(namespace) + "-router"
 }
 
+// Returns a Classic VPN gateway name, used when connecting to clouds without BGP support
+func getClassicVpnGwName(namespace string) string {
+	return getParagliderNamespacePrefix(namespace) + "-classic-vpn-gw"
+}
+
+// Returns the name of the static external IP address of a Classic VPN gateway
+func getClassicVpnGwAddressName(namespace string) string {
+	return getClassicVpnGwName(namespace) + "-ip"
+}
+
+// Returns the name of a forwarding rule of a Classic VPN gateway (e.g., "esp", "udp500", "udp4500")
+func getClassicVpnGwForwardingRuleName(namespace string, suffix string) string {
+	return getClassicVpnGwName(namespace) + "-" + suffix
+}
+
+// Returns a name for a static route to a remote address space through a VPN tunnel
+func getVpnRouteName(namespace string, cloud string, tunnelIdx int, addressSpace string) string {
+	return getVpnTunnelName(namespace, cloud, tunnelIdx) + "-route-" + hash(addressSpace)[:8]
+}
+
 // Returns a peer gateway name when connecting to another cloud
 func getPeerGwName(namespace string, cloud string) string {
 	return getParagliderNamespacePrefix(namespace) + "-" + cloud + "-peer-gw"
@@ -66,6 +90,11 @@ func getVpnGatewayUrl(project, region, vpnGatewayName string) string {
 	return computeUrlPrefix + fmt.Sprintf("projects/%s/regions/%s/vpnGateways/%s", project, region, vpnGatewayName)
 }
 
+// getTargetVpnGatewayUrl returns a fully qualified URL for a Classic VPN gateway
+func getTargetVpnGatewayUrl(project, region, targetVpnGatewayName string) string {
+	return computeUrlPrefix + fmt.Sprintf("projects/%s/regions/%s/targetVpnGateways/%s", project, region, targetVpnGatewayName)
+}
+
 // getRouterUrl returns a fully qualified URL for a router
 func getRouterUrl(project, region, routerName string) string {
 	return computeUrlPrefix + fmt.Sprintf("projects/%s/regions/%s/routers/%s", project, region, routerName)
@@ -80,3 +109,124 @@ func getVpnTunnelUrl(project, region, vpnTunnelName string) string {
 func getPeerGatewayUrl(project, peerGatewayName string) string {
 	return computeUrlPrefix + fmt.Sprintf("projects/%s/global/externalVpnGateways/%s", project, peerGatewayName)
 }
+
+// deleteVpn deletes the VPN tunnels, routes, gateways, and router of a namespace. Resources which don't exist are skipped.
+func deleteVpn(ctx context.Context, project string, namespace string, clients *GCPClients) error {
+	vpnTunnelsClient, err := clients.GetOrCreateVpnTunnelsClient(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get vpn tunnels client: %w", err)
+	}
+	routesClient, err := clients.GetOrCreateRoutesClient(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get routes client: %w", err)
+	}
+
+	// Static routes of Classic VPN tunnels have to be deleted before the tunnels
+	vpcUrl := getVpcUrl(project, namespace)
+	listRoutesReq := &computepb.ListRoutesRequest{
+		Project: project,
+		Filter:  proto.String(fmt.Sprintf("name eq \"%s-.*\"", getParagliderNamespacePrefix(namespace))),
+	}
+	routesIterator := routesClient.List(ctx, listRoutesReq)
+	for {
+		route, err := routesIterator.Next()
+		if route == nil {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("unable to list routes: %w", err)
+		}
+		if route.GetNetwork() != vpcUrl {
+			continue
+		}
+		deleteRouteOp, err := routesClient.Delete(ctx, &computepb.DeleteRouteRequest{Project: project, Route: route.GetName()})
+		if err = waitForDeletion(ctx, deleteRouteOp, err); err != nil {
+			return fmt.Errorf("unable to delete route %s: %w", route.GetName(), err)
+		}
+	}
+
+	// Tunnels are matched by their gateway since tunnel names don't identify the namespace unambiguously
+	vpnGatewayUrl := getVpnGatewayUrl(project, vpnRegion, getVpnGwName(namespace))
+	targetVpnGatewayUrl := getTargetVpnGatewayUrl(project, vpnRegion, getClassicVpnGwName(namespace))
+	peerGateways := []string{}
+	tunnelsIterator := vpnTunnelsClient.List(ctx, &computepb.ListVpnTunnelsRequest{Project: project, Region: vpnRegion})
+	for {
+		tunnel, err := tunnelsIterator.Next()
+		if tunnel == nil {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("unable to list vpn tunnels: %w", err)
+		}
+		if tunnel.GetVpnGateway() != vpnGatewayUrl && tunnel.GetTargetVpnGateway() != targetVpnGatewayUrl {
+			continue
+		}
+		deleteTunnelOp, err := vpnTunnelsClient.Delete(ctx, &computepb.DeleteVpnTunnelRequest{Project: project, Region: vpnRegion, VpnTunnel: tunnel.GetName()})
+		if err = waitForDeletion(ctx, deleteTunnelOp, err); err != nil {
+			return fmt.Errorf("unable to delete vpn tunnel %s: %w", tunnel.GetName(), err)
+		}
+		if tunnel.PeerExternalGateway != nil {
+			peerGateways = append(peerGateways, parseUrl(tunnel.GetPeerExternalGateway())["externalVpnGateways"])
+		}
+	}
+
+	// Classic VPN gateway along with its forwarding rules and address
+	forwardingRulesClient, err := clients.GetOrCreateForwardingClient(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get forwarding rules client: %w", err)
+	}
+	for _, suffix := range []string{"esp", "udp500", "udp4500"} {
+		name := getClassicVpnGwForwardingRuleName(namespace, suffix)
+		deleteForwardingRuleOp, err := forwardingRulesClient.Delete(ctx, &computepb.DeleteForwardingRuleRequest{Project: project, Region: vpnRegion, ForwardingRule: name})
+		if err = waitForDeletion(ctx, deleteForwardingRuleOp, err); err != nil {
+			return fmt.Errorf("unable to delete forwarding rule %s: %w", name, err)
+		}
+	}
+	targetVpnGatewaysClient, err := clients.GetOrCreateTargetVpnGatewaysClient(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get target vpn gateways client: %w", err)
+	}
+	deleteTargetVpnGatewayOp, err := targetVpnGatewaysClient.Delete(ctx, &computepb.DeleteTargetVpnGatewayRequest{Project: project, Region: vpnRegion, TargetVpnGateway: getClassicVpnGwName(namespace)})
+	if err = waitForDeletion(ctx, deleteTargetVpnGatewayOp, err); err != nil {
+		return fmt.Errorf("unable to delete classic vpn gateway: %w", err)
+	}
+	addressesClient, err := clients.GetOrCreateAddressesClient(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get addresses client: %w", err)
+	}
+	deleteAddressOp, err := addressesClient.Delete(ctx, &computepb.DeleteAddressRequest{Project: project, Region: vpnRegion, Address: getClassicVpnGwAddressName(namespace)})
+	if err = waitForDeletion(ctx, deleteAddressOp, err); err != nil {
+		return fmt.Errorf("unable to delete classic vpn gateway address: %w", err)
+	}
+
+	// HA VPN gateway and the peer gateways its tunnels connected to
+	vpnGatewaysClient, err := clients.GetOrCreateVpnGatewaysClient(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get vpn gateways client: %w", err)
+	}
+	deleteVpnGatewayOp, err := vpnGatewaysClient.Delete(ctx, &computepb.DeleteVpnGatewayRequest{Project: project, Region: vpnRegion, VpnGateway: getVpnGwName(namespace)})
+	if err = waitForDeletion(ctx, deleteVpnGatewayOp, err); err != nil {
+		return fmt.Errorf("unable to delete vpn gateway: %w", err)
+	}
+	externalVpnGatewaysClient, err := clients.GetOrCreateExternalVpnGatewaysClient(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get external vpn gateways client: %w", err)
+	}
+	for _, peerGateway := range peerGateways {
+		deletePeerGatewayOp, err := externalVpnGatewaysClient.Delete(ctx, &computepb.DeleteExternalVpnGatewayRequest{Project: project, ExternalVpnGateway: peerGateway})
+		if err = waitForDeletion(ctx, deletePeerGatewayOp, err); err != nil {
+			return fmt.Errorf("unable to delete peer gateway %s: %w", peerGateway, err)
+		}
+	}
+
+	// The router also holds the NAT gateway
+	routersClient, err := clients.GetOrCreateRoutersClient(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get routers client: %w", err)
+	}
+	deleteRouterOp, err := routersClient.Delete(ctx, &computepb.DeleteRouterRequest{Project: project, Region: vpnRegion, Router: getRouterName(namespace)})
+	if err = waitForDeletion(ctx, deleteRouterOp, err); err != nil {
+		return fmt.Errorf("unable to delete router: %w", err)
+	}
+	return nil
+}
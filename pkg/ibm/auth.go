@@ -22,6 +22,7 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
@@ -29,8 +30,6 @@ import (
 	"github.com/IBM/go-sdk-core/v5/core"
 	"github.com/IBM/vpc-go-sdk/vpcv1"
 	"golang.org/x/crypto/ssh"
-
-	utils "github.com/paraglider-project/paraglider/pkg/utils"
 )
 
 const keyType = "key"
@@ -57,14 +56,14 @@ func (c *CloudClient) setupAuth() (string, error) {
 
 	if err != nil {
 		if strings.Contains(err.Error(), "fingerprint already exists") {
-			utils.Log.Println("Reusing registered local SSH key")
+			slog.Info("Reusing registered local SSH key")
 			keyID, err = c.getKeyByPublicKey(publicKeyData)
 			if err != nil {
-				utils.Log.Println("Failed to reuse registered local SSH key")
+				slog.Error("Failed to reuse registered local SSH key", "error", err)
 				return "", err
 			}
 		} else {
-			utils.Log.Println("Failed to register SSH key\n", err)
+			slog.Error("Failed to register SSH key", "error", err)
 			return "", err
 		}
 
@@ -83,13 +82,13 @@ func (c *CloudClient) getKeyByPublicKey(publicKeyData string) (string, error) {
 
 	keys, _, err := c.vpcService.ListKeys(listKeysOptions)
 	if err != nil {
-		utils.Log.Println(err)
+		slog.Error("Failed to list SSH keys", "error", err)
 		return "", nil
 	}
 
 	for _, key := range keys.Keys {
 		if *key.PublicKey == publicKeyData {
-			utils.Log.Println("Found matching registered key:", *key.ID)
+			slog.Info("Found matching registered key", "keyId", *key.ID)
 			return *key.ID, nil
 		}
 	}
@@ -121,14 +120,14 @@ func getLocalPubKey() (string, error) {
 	var publicKeyData string
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		utils.Log.Println("Failed to generate home path: \n", err)
+		slog.Error("Failed to generate home path", "error", err)
 		return "", err
 	}
 
 	pubKeyPath := filepath.Join(homeDir, publicSSHKey)
 	err = os.MkdirAll(filepath.Dir(filepath.Join(homeDir, publicSSHKey)), 0700)
 	if err != nil {
-		utils.Log.Println("Failed to create ssh key folder\n", err)
+		slog.Error("Failed to create ssh key folder", "error", err)
 		return "", err
 	}
 
@@ -141,18 +140,18 @@ func getLocalPubKey() (string, error) {
 			data, keyGenErr := createSSHKeys(filepath.Join(homeDir, privateSSHKey))
 			publicKeyData = data
 			if keyGenErr != nil {
-				utils.Log.Println("Failed to generate ssh keys.\nError:", keyGenErr)
+				slog.Error("Failed to generate ssh keys", "error", keyGenErr)
 				return "", err
 			}
 		} else { // Non expected error
-			utils.Log.Println("Failed to verify if ssh keys exist", err)
+			slog.Error("Failed to verify if ssh keys exist", "error", err)
 			return "", err
 		}
 	} else { // ssh keys exist
 		data, err := os.ReadFile(pubKeyPath)
 		publicKeyData = string(data)
 		if err != nil { // failed to read public ssh key data
-			utils.Log.Println(err)
+			slog.Error("Failed to read public ssh key", "error", err)
 			return "", err
 		}
 	}
@@ -197,6 +196,6 @@ func createSSHKeys(privateKeyPath string) (string, error) {
 		return "", err
 	}
 
-	utils.Log.Println("Created SSH keys at ", filepath.Dir(privateKeyPath))
+	slog.Info("Created SSH keys", "path", filepath.Dir(privateKeyPath))
 	return pubKeyStr, nil
 }
@@ -19,24 +19,27 @@ package ibm
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net"
-	"os"
-	"strings"
 
 	"github.com/IBM/vpc-go-sdk/vpcv1"
-	redis "github.com/redis/go-redis/v9"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 
+	"github.com/paraglider-project/paraglider/internal/version"
+	config "github.com/paraglider-project/paraglider/pkg/orchestrator/config"
 	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
 	utils "github.com/paraglider-project/paraglider/pkg/utils"
 )
 
 type IBMPluginServer struct {
 	paragliderpb.UnimplementedCloudPluginServer
-	cloudClient            map[string]*CloudClient
-	orchestratorServerAddr string
+	cloudClient             map[string]*CloudClient
+	orchestratorServerAddr  string
+	orchestratorCredentials credentials.TransportCredentials // Used to dial the orchestrator (plaintext if nil)
 }
 
 var defaultRegion = "us-east"
@@ -50,7 +53,7 @@ func (s *IBMPluginServer) setupCloudClient(resourceGroupID, region string) (*Clo
 	}
 	client, err := NewIBMCloudClient(resourceGroupID, region)
 	if err != nil {
-		utils.Log.Println("Failed to set up IBM clients with error:", err)
+		slog.Error("Failed to set up IBM clients", "error", err)
 		return nil, err
 	}
 	s.cloudClient[clientKey] = client
@@ -80,7 +83,7 @@ func (s *IBMPluginServer) getAllClientsForVPCs(cloudClient *CloudClient, resourc
 func (s *IBMPluginServer) CreateResource(c context.Context, resourceDesc *paragliderpb.CreateResourceRequest) (*paragliderpb.CreateResourceResponse, error) {
 	var vpcID *string
 	var subnetID string
-	utils.Log.Printf("Creating resource %s in deployment %s\n", resourceDesc.Name, resourceDesc.Deployment.Id)
+	slog.InfoContext(c, "Creating resource", "name", resourceDesc.Name, "deployment", resourceDesc.Deployment.Id)
 	zone, err := getZoneFromDesc(resourceDesc.Description)
 	if err != nil {
 		return nil, err
@@ -97,7 +100,7 @@ func (s *IBMPluginServer) CreateResource(c context.Context, resourceDesc *paragl
 
 	cloudClient, err := s.setupCloudClient(rInfo.ResourceGroup, region)
 	if err != nil {
-		utils.Log.Printf("Failed to create resource in resource group %v and region %v, with error: %+v", rInfo.ResourceGroup, region, err)
+		slog.ErrorContext(c, "Failed to create resource", "resourceGroup", rInfo.ResourceGroup, "region", region, "error", err)
 		return nil, err
 	}
 
@@ -122,7 +125,7 @@ func (s *IBMPluginServer) CreateResource(c context.Context, resourceDesc *paragl
 	}
 
 	if vpcID == nil {
-		utils.Log.Printf("Creating a VPC (exclusive=%v).\n", res.IsExclusiveNetworkNeeded())
+		slog.InfoContext(c, "Creating a VPC", "exclusive", res.IsExclusiveNetworkNeeded())
 		vpc, err := cloudClient.CreateVPC([]string{resourceDesc.Deployment.Namespace}, res.IsExclusiveNetworkNeeded())
 		if err != nil {
 			return nil, err
@@ -139,20 +142,20 @@ func (s *IBMPluginServer) CreateResource(c context.Context, resourceDesc *paragl
 	}
 	if len(subnetsData) == 0 {
 		// No existing subnets in the specified VPC
-		utils.Log.Printf("Getting address space from orchestrator\n")
+		slog.DebugContext(c, "Getting address space from orchestrator")
 
 		// Find unused address space and create a subnet in it.
-		conn, err := grpc.NewClient(s.orchestratorServerAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		conn, err := grpc.NewClient(s.orchestratorServerAddr, utils.DialCredentials(s.orchestratorCredentials), utils.TracingDialOption())
 		if err != nil {
 			return nil, err
 		}
 		defer conn.Close()
 		client := paragliderpb.NewControllerClient(conn)
-		resp, err := client.FindUnusedAddressSpaces(context.Background(), &paragliderpb.FindUnusedAddressSpacesRequest{Sizes: []int32{0}})
+		resp, err := client.FindUnusedAddressSpaces(c, &paragliderpb.FindUnusedAddressSpacesRequest{Sizes: []int32{0}})
 		if err != nil {
 			return nil, err
 		}
-		utils.Log.Printf("Using %s address space", resp.AddressSpaces[0])
+		slog.InfoContext(c, "Using address space", "addressSpace", resp.AddressSpaces[0])
 		subnet, err := cloudClient.CreateSubnet(*vpcID, zone, resp.AddressSpaces[0], requiredTags)
 		if err != nil {
 			return nil, err
@@ -176,6 +179,159 @@ func (s *IBMPluginServer) AttachResource(ctx context.Context, req *paragliderpb.
 	return nil, fmt.Errorf("not implemented")
 }
 
+// DeleteResource deletes the specified resource along with its paraglider security group and rules
+func (s *IBMPluginServer) DeleteResource(ctx context.Context, req *paragliderpb.DeleteResourceRequest) (*paragliderpb.DeleteResourceResponse, error) {
+	rInfo, err := getResourceMeta(req.Resource)
+	if err != nil {
+		return nil, err
+	}
+	region, err := ZoneToRegion(rInfo.Zone)
+	if err != nil {
+		return nil, err
+	}
+
+	cloudClient, err := s.setupCloudClient(rInfo.ResourceGroup, region)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := cloudClient.GetResourceHandlerFromID(req.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	// verify specified resource match the specified namespace
+	if isInNamespace, err := res.IsInNamespace(req.Namespace, region); !isInNamespace || err != nil {
+		return nil, fmt.Errorf("specified resource %v doesn't exist in namespace: %v",
+			rInfo.ResourceID, req.Namespace)
+	}
+
+	paragliderSgsData, err := cloudClient.GetParagliderTaggedResources(SG, []string{res.GetID()}, resourceQuery{Region: region})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sgData := range paragliderSgsData {
+		err = s.deleteSecurityGroupRules(ctx, cloudClient, sgData.ID, req.Namespace)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = res.DeleteResource()
+	if err != nil {
+		return nil, err
+	}
+
+	// security groups can only be deleted once no resource is attached to them
+	for _, sgData := range paragliderSgsData {
+		err = cloudClient.DeleteSecurityGroup(sgData.ID)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to delete security group", "securityGroupId", sgData.ID, "error", err)
+		}
+	}
+
+	return &paragliderpb.DeleteResourceResponse{}, nil
+}
+
+// DetachResource removes the resource from paraglider's management without deleting it.
+// The rules of its paraglider security groups are removed and the paraglider tags are detached from the resource.
+func (s *IBMPluginServer) DetachResource(ctx context.Context, req *paragliderpb.DetachResourceRequest) (*paragliderpb.DetachResourceResponse, error) {
+	rInfo, err := getResourceMeta(req.Resource)
+	if err != nil {
+		return nil, err
+	}
+	region, err := ZoneToRegion(rInfo.Zone)
+	if err != nil {
+		return nil, err
+	}
+
+	cloudClient, err := s.setupCloudClient(rInfo.ResourceGroup, region)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := cloudClient.GetResourceHandlerFromID(req.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	// verify specified resource match the specified namespace
+	if isInNamespace, err := res.IsInNamespace(req.Namespace, region); !isInNamespace || err != nil {
+		return nil, fmt.Errorf("specified resource %v doesn't exist in namespace: %v",
+			rInfo.ResourceID, req.Namespace)
+	}
+
+	paragliderSgsData, err := cloudClient.GetParagliderTaggedResources(SG, []string{res.GetID()}, resourceQuery{Region: region})
+	if err != nil {
+		return nil, err
+	}
+
+	// the security groups stay attached to the resource, but no longer reference it
+	for _, sgData := range paragliderSgsData {
+		err = s.deleteSecurityGroupRules(ctx, cloudClient, sgData.ID, req.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		err = cloudClient.detachTag(&sgData.CRN, []string{res.GetID()})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	vpc, err := res.GetVPC()
+	if err != nil {
+		return nil, err
+	}
+	resCRN, err := res.GetCRN()
+	if err != nil {
+		return nil, err
+	}
+	err = cloudClient.detachTag(&resCRN, []string{*vpc.ID, req.Namespace, paragliderTag})
+	if err != nil {
+		return nil, err
+	}
+
+	return &paragliderpb.DetachResourceResponse{}, nil
+}
+
+// deleteSecurityGroupRules removes all rules of the specified security group along with their kv store references
+func (s *IBMPluginServer) deleteSecurityGroupRules(ctx context.Context, cloudClient *CloudClient, sgID, namespace string) error {
+	conn, err := grpc.NewClient(s.orchestratorServerAddr, utils.DialCredentials(s.orchestratorCredentials), utils.TracingDialOption())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	client := paragliderpb.NewControllerClient(conn)
+
+	sgRules, err := cloudClient.GetSecurityRulesOfSG(sgID)
+	if err != nil {
+		return err
+	}
+	for _, rule := range sgRules {
+		err = cloudClient.DeleteSecurityGroupRule(sgID, rule.ID)
+		if err != nil {
+			return err
+		}
+		ruleName, err := getRuleValFromStore(ctx, client, rule.ID, namespace)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return fmt.Errorf("failed to get from kv store %v", err)
+		}
+		if ruleName == "" {
+			continue
+		}
+		err = delRuleValFromStore(ctx, client, ruleName, namespace)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to delete rule from KV store", "key", ruleName)
+		}
+		err = delRuleValFromStore(ctx, client, rule.ID, namespace)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to delete rule from KV store", "key", rule.ID)
+		}
+	}
+	return nil
+}
+
 // GetUsedAddressSpaces returns a list of address spaces used by either user's or paraglider' subnets,
 // for each paraglider vpc.
 func (s *IBMPluginServer) GetUsedAddressSpaces(ctx context.Context, req *paragliderpb.GetUsedAddressSpacesRequest) (*paragliderpb.GetUsedAddressSpacesResponse, error) {
@@ -203,7 +359,7 @@ func (s *IBMPluginServer) GetUsedAddressSpaces(ctx context.Context, req *paragli
 		// get all VPCs and corresponding clients to collect all address spaces
 		clients, err := s.getAllClientsForVPCs(cloudClient, rInfo.ResourceGroup)
 		if err != nil {
-			utils.Log.Print("Failed to get paraglider tagged VPCs\n")
+			slog.ErrorContext(ctx, "Failed to get paraglider tagged VPCs", "error", err)
 			return nil, err
 		}
 		for vpcID, client := range clients {
@@ -220,6 +376,87 @@ func (s *IBMPluginServer) GetUsedAddressSpaces(ctx context.Context, req *paragli
 	return resp, nil
 }
 
+// DeleteNamespace deletes the VPNs and VPCs (along with their subnets and security groups) of the namespace,
+// and removes the VPCs from the transit gateway.
+func (s *IBMPluginServer) DeleteNamespace(ctx context.Context, req *paragliderpb.DeleteNamespaceRequest) (*paragliderpb.DeleteNamespaceResponse, error) {
+	rInfo, err := getResourceMeta(req.Deployment.Id)
+	if err != nil {
+		return nil, err
+	}
+	namespace := req.Deployment.Namespace
+	cloudClient, err := s.setupCloudClient(rInfo.ResourceGroup, defaultRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	// VPNs have to be deleted before the subnets they're deployed in
+	vpnsData, err := cloudClient.GetVPNsInNamespaceRegion(namespace, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, vpnData := range vpnsData {
+		vpnClient, err := s.setupCloudClient(rInfo.ResourceGroup, vpnData.Region)
+		if err != nil {
+			return nil, err
+		}
+		err = vpnClient.DeleteVPN(vpnData.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	vpcsData, err := cloudClient.GetParagliderTaggedResources(VPC, []string{namespace}, resourceQuery{})
+	if err != nil {
+		return nil, err
+	}
+	transitGWs, err := cloudClient.GetParagliderTaggedResources(GATEWAY, []string{}, resourceQuery{})
+	if err != nil {
+		return nil, err
+	}
+	for _, vpcData := range vpcsData {
+		// the transit gateway is shared by all namespaces, so only the connection of the VPC is removed
+		for _, gw := range transitGWs {
+			connections, err := cloudClient.GetTransitGWConnections(gw.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, connection := range connections {
+				if connection.VPCCRN != vpcData.CRN {
+					continue
+				}
+				err = cloudClient.RemoveTransitGWConnection(connection.ID, gw.ID)
+				if err != nil {
+					return nil, err
+				}
+				if conDeleted, err := cloudClient.pollConnectionDeleted(connection.ID, gw.ID); !conDeleted || err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		vpcClient, err := s.setupCloudClient(rInfo.ResourceGroup, vpcData.Region)
+		if err != nil {
+			return nil, err
+		}
+		sgsData, err := vpcClient.GetParagliderTaggedResources(SG, []string{vpcData.ID}, resourceQuery{Region: vpcData.Region})
+		if err != nil {
+			return nil, err
+		}
+		for _, sgData := range sgsData {
+			err = vpcClient.DeleteSecurityGroup(sgData.ID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		err = vpcClient.DeleteVPC(vpcData.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &paragliderpb.DeleteNamespaceResponse{}, nil
+}
+
 // GetPermitList returns security rules of security groups associated with the specified resource.
 func (s *IBMPluginServer) GetPermitList(ctx context.Context, req *paragliderpb.GetPermitListRequest) (*paragliderpb.GetPermitListResponse, error) {
 	rInfo, err := getResourceMeta(req.Resource)
@@ -245,7 +482,7 @@ func (s *IBMPluginServer) GetPermitList(ctx context.Context, req *paragliderpb.G
 		return nil, fmt.Errorf("specified resource %v doesn't exist in namespace: %v",
 			rInfo.ResourceID, req.Namespace)
 	}
-	utils.Log.Printf("Getting permit lists for resource: %s\n", rInfo.ResourceID)
+	slog.DebugContext(ctx, "Getting permit list")
 
 	securityGroupID, err := res.GetSecurityGroupID()
 	if err != nil {
@@ -260,7 +497,7 @@ func (s *IBMPluginServer) GetPermitList(ctx context.Context, req *paragliderpb.G
 		return nil, err
 	}
 
-	conn, err := grpc.NewClient(s.orchestratorServerAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(s.orchestratorServerAddr, utils.DialCredentials(s.orchestratorCredentials), utils.TracingDialOption())
 	if err != nil {
 		return nil, err
 	}
@@ -272,9 +509,9 @@ func (s *IBMPluginServer) GetPermitList(ctx context.Context, req *paragliderpb.G
 		//IBM rule ID is transiently stored in rule name during translation
 		ruleName, err := getRuleValFromStore(ctx, client, rule.Name, req.Namespace)
 		if err != nil {
-			utils.Log.Printf("Failed to get value from KVstore for rule %s: %v.", ruleName, err)
+			slog.ErrorContext(ctx, "Failed to get value from KV store", "rule", ruleName, "error", err)
 		}
-		utils.Log.Printf("Got %s rule name for ID : %s", ruleName, rule.Name)
+		slog.DebugContext(ctx, "Got rule name for security group rule", "rule", ruleName, "securityGroupRuleId", rule.Name)
 		rule.Name = ruleName
 	}
 	return &paragliderpb.GetPermitListResponse{Rules: paragliderRules}, nil
@@ -283,20 +520,26 @@ func (s *IBMPluginServer) GetPermitList(ctx context.Context, req *paragliderpb.G
 // AddPermitListRules attaches security group rules to the specified resource in PermitList.AssociatedResource.
 func (s *IBMPluginServer) AddPermitListRules(ctx context.Context, req *paragliderpb.AddPermitListRulesRequest) (*paragliderpb.AddPermitListRulesResponse, error) {
 
-	utils.Log.Printf("Adding PermitListRules %v, %v. namespace :%s \n ", req.Resource, req.Rules, req.Namespace)
+	slog.DebugContext(ctx, "Adding permit list rules", "permitList", req.Rules)
+	// Security groups can only allow traffic (see the capabilities reported by GetCapabilities)
+	for _, rule := range req.Rules {
+		if rule.Action == paragliderpb.RuleAction_DENY {
+			return nil, fmt.Errorf("rule %s: IBM security groups do not support deny rules", rule.Name)
+		}
+	}
 	rInfo, err := getResourceMeta(req.Resource)
 	if err != nil {
 		return nil, err
 	}
 	region, err := ZoneToRegion(rInfo.Zone)
 	if err != nil {
-		utils.Log.Printf("Failed to convert zone to region: %v\n", err)
+		slog.ErrorContext(ctx, "Failed to convert zone to region", "error", err)
 		return nil, err
 	}
-	utils.Log.Printf("%s, %s, %s\n", rInfo.ResourceGroup, region, rInfo.ResourceID)
+	slog.DebugContext(ctx, "Resolved resource", "resourceGroup", rInfo.ResourceGroup, "region", region, "resourceId", rInfo.ResourceID)
 	cloudClient, err := s.setupCloudClient(rInfo.ResourceGroup, region)
 	if err != nil {
-		utils.Log.Printf("Failed to get cloud client: %v\n", err)
+		slog.ErrorContext(ctx, "Failed to get cloud client", "error", err)
 		return nil, err
 	}
 
@@ -313,11 +556,11 @@ func (s *IBMPluginServer) AddPermitListRules(ctx context.Context, req *paraglide
 	// get security group of the resource
 	paragliderSgsData, err := cloudClient.GetParagliderTaggedResources(SG, []string{res.GetID()}, resourceQuery{Region: region})
 	if err != nil {
-		utils.Log.Printf("Failed to get paraglider tagged resources %v: %v.\n", res.GetID(), err)
+		slog.ErrorContext(ctx, "Failed to get paraglider tagged resources", "resourceId", res.GetID(), "error", err)
 		return nil, err
 	}
 	if len(paragliderSgsData) == 0 {
-		utils.Log.Printf("No security groups were found for resource %v\n", res.GetID())
+		slog.ErrorContext(ctx, "No security groups were found for resource", "resourceId", res.GetID())
 		return nil, fmt.Errorf("no security groups were found for resource %v", res.GetID())
 	}
 	// up to a single paraglider security group can exist per resource (queried resource by tag=resourceID)
@@ -326,7 +569,7 @@ func (s *IBMPluginServer) AddPermitListRules(ctx context.Context, req *paraglide
 	// get VPC of the resource specified in the request
 	requestVPCData, err := res.GetVPC()
 	if err != nil {
-		utils.Log.Printf("Failed to get VPC: %v.\n", err)
+		slog.ErrorContext(ctx, "Failed to get VPC", "error", err)
 		return nil, err
 	}
 
@@ -338,18 +581,18 @@ func (s *IBMPluginServer) AddPermitListRules(ctx context.Context, req *paraglide
 	// get current rules in SG and record their hash values
 	sgRules, err := cloudClient.GetSecurityRulesOfSG(requestSGID)
 	if err != nil {
-		utils.Log.Printf("Failed to fetch current SG rules of resource %v, while adding permit rules, with error: %+v", rInfo.ResourceID, err)
+		slog.ErrorContext(ctx, "Failed to fetch current security group rules of resource while adding permit rules", "resourceId", rInfo.ResourceID, "error", err)
 		return nil, err
 	}
 
 	// Get used address spaces of all clouds
-	orchestratorConn, err := grpc.NewClient(s.orchestratorServerAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	orchestratorConn, err := grpc.NewClient(s.orchestratorServerAddr, utils.DialCredentials(s.orchestratorCredentials), utils.TracingDialOption())
 	if err != nil {
 		return nil, fmt.Errorf("unable to establish connection with orchestrator: %w", err)
 	}
 	defer orchestratorConn.Close()
 	controllerClient := paragliderpb.NewControllerClient(orchestratorConn)
-	addressSpaceMappings, err := controllerClient.GetUsedAddressSpaces(context.Background(), &emptypb.Empty{})
+	addressSpaceMappings, err := controllerClient.GetUsedAddressSpaces(ctx, &emptypb.Empty{})
 	if err != nil {
 		return nil, fmt.Errorf("unable to get used address spaces: %w", err)
 	}
@@ -359,7 +602,7 @@ func (s *IBMPluginServer) AddPermitListRules(ctx context.Context, req *paraglide
 		// multiple ibm rules can be returned due to multiple possible targets
 		ibmRules, err := ParagliderToIBMRule(requestSGID, paragliderRule)
 		if err != nil {
-			utils.Log.Printf("Failed to get remote vpc: %v.\n", err)
+			slog.ErrorContext(ctx, "Failed to get remote VPC", "error", err)
 			return nil, err
 		}
 
@@ -374,7 +617,7 @@ func (s *IBMPluginServer) AddPermitListRules(ctx context.Context, req *paraglide
 				// If peering cloud info is unidentified, the target is a public IP endpoint
 				subnets, err := cloudClient.GetSubnetsInVpcRegionBound(*requestVPCData.ID)
 				if err != nil {
-					utils.Log.Printf("error while fetching subnets of VPC: %+v", err)
+					slog.ErrorContext(ctx, "Error while fetching subnets of VPC", "error", err)
 					return nil, err
 				}
 				err = s.connectToPublicGateway(cloudClient, rInfo.ResourceGroup, *requestVPCData.ID, rInfo.Zone, region, subnets)
@@ -395,10 +638,11 @@ func (s *IBMPluginServer) AddPermitListRules(ctx context.Context, req *paraglide
 				if len(ruleTargetAddress) == 0 {
 					return nil, fmt.Errorf("Missing remote address for rule %+v", ibmRules[i])
 				}
-				_, err := controllerClient.ConnectClouds(ctx, connectCloudsReq)
+				connectCloudsResp, err := controllerClient.ConnectClouds(ctx, connectCloudsReq)
 				if err != nil {
 					return nil, fmt.Errorf("unable to connect clouds : %w", err)
 				}
+				utils.AddResponseNotices(ctx, connectCloudsResp)
 			} else {
 				// if rule targets a VPC on IBM, connect them via a transit gateway
 				err = s.connectToTransitGatewayIfNeeded(cloudClient, ibmRules[i], gwID, rInfo.ResourceGroup, *requestVPCData.CRN, region)
@@ -413,37 +657,37 @@ func (s *IBMPluginServer) AddPermitListRules(ctx context.Context, req *paraglide
 			rulesHashValues := make(map[uint64]bool)
 			_, err = cloudClient.GetUniqueSGRules(sgRules, rulesHashValues)
 			if err != nil {
-				utils.Log.Printf("Failed to get unique sg rules: %v.\n", err)
+				slog.ErrorContext(ctx, "Failed to get unique security group rules", "error", err)
 				return nil, err
 			}
 			// compute hash value of rules, disregarding the ID field.
 			ruleHashValue, err := getStructHash(ibmRule, []string{"ID"})
 			if err != nil {
-				utils.Log.Printf("Failed to compute hash: %v.\n", err)
+				slog.ErrorContext(ctx, "Failed to compute hash", "error", err)
 				return nil, err
 			}
 			// avoid adding duplicate rules (when hash values match)
 			if rulesHashValues[ruleHashValue] {
-				utils.Log.Printf("Rule %+v already exists for security group ID %v.\n", ibmRule, requestSGID)
+				slog.InfoContext(ctx, "Rule already exists for security group", "securityGroupRule", ibmRule, "securityGroupId", requestSGID)
 				return &paragliderpb.AddPermitListRulesResponse{}, nil
 			}
 
 			ruleID, err := cloudClient.AddSecurityGroupRule(ibmRule)
 			if err != nil {
-				utils.Log.Printf("Failed to add security group rule: %v.\n", err)
+				slog.ErrorContext(ctx, "Failed to add security group rule", "error", err)
 				return nil, err
 			}
-			utils.Log.Printf("Attached rule %s(%s), %+v\n", ruleID, ibmRule.ID, ibmRule)
+			slog.InfoContext(ctx, "Attached rule", "securityGroupRuleId", ruleID, "rule", ibmRule.ID, "securityGroupRule", ibmRule)
 
 			// Check if there exists a rule with the permitlist name
 			oldRuleID, err := getRuleValFromStore(ctx, controllerClient, ibmRule.ID, req.Namespace)
-			if err != nil && !strings.Contains(err.Error(), string(redis.Nil)) {
+			if err != nil && status.Code(err) != codes.NotFound {
 				// In case of failure to get/set KV from store, ensure the existing ruled is deleted
 				// to ensure, there are no zombie rules
-				utils.Log.Printf("Failed to retrieve from KV store for rule %s: %v.", ibmRule.ID, err)
+				slog.ErrorContext(ctx, "Failed to retrieve rule from KV store", "rule", ibmRule.ID, "error", err)
 				err = cloudClient.DeleteSecurityGroupRule(requestSGID, ruleID)
 				if err != nil {
-					utils.Log.Printf("Error occurred while deleting SG rule %v, after failing to retrieve it from the KV store: %+v", ruleID, err)
+					slog.ErrorContext(ctx, "Error occurred while deleting security group rule after failing to retrieve it from the KV store", "securityGroupRuleId", ruleID, "error", err)
 					return nil, err
 				}
 				return nil, fmt.Errorf("failed to get from kv store %v", err)
@@ -453,15 +697,15 @@ func (s *IBMPluginServer) AddPermitListRules(ctx context.Context, req *paraglide
 				// Existing rule found with the same permitlist name
 				err = cloudClient.DeleteSecurityGroupRule(requestSGID, oldRuleID)
 				if err != nil {
-					utils.Log.Printf("Error occurred while deleting SG rule %v, after a rule in KV store with identical name %v: %+v", ruleID, ibmRule.ID, err)
+					slog.ErrorContext(ctx, "Error occurred while deleting security group rule after finding a rule in KV store with identical name", "securityGroupRuleId", ruleID, "rule", ibmRule.ID, "error", err)
 					return nil, err
 				}
-				utils.Log.Printf("Cleaning up old rule %s with same permitlist name %s", oldRuleID, ibmRule.ID)
+				slog.InfoContext(ctx, "Cleaning up old rule with same permit list rule name", "securityGroupRuleId", oldRuleID, "rule", ibmRule.ID)
 			}
 			// The intermediate representation ibmRule.ID stores the permitlist name
 			err = setRuleValToStore(ctx, controllerClient, ibmRule.ID, ruleID, req.Namespace)
 			if err != nil {
-				utils.Log.Printf("Failed to set KV store for rule %s: %v.", ibmRule.ID, err)
+				slog.ErrorContext(ctx, "Failed to set KV store for rule", "rule", ibmRule.ID, "error", err)
 				err = cloudClient.DeleteSecurityGroupRule(requestSGID, ruleID)
 				if err != nil {
 					return nil, err
@@ -471,7 +715,7 @@ func (s *IBMPluginServer) AddPermitListRules(ctx context.Context, req *paraglide
 			// Store the reverse representation to be used to retrieve permitlist name for getpermitlist requests
 			err = setRuleValToStore(ctx, controllerClient, ruleID, ibmRule.ID, req.Namespace)
 			if err != nil {
-				utils.Log.Printf("Failed to set KV store for rule %s: %v.", ibmRule.ID, err)
+				slog.ErrorContext(ctx, "Failed to set KV store for rule", "rule", ibmRule.ID, "error", err)
 				err = cloudClient.DeleteSecurityGroupRule(requestSGID, ruleID)
 				if err != nil {
 					return nil, err
@@ -495,7 +739,7 @@ func (s *IBMPluginServer) connectToPublicGateway(cloudClient *CloudClient, resou
 	if len(publicGatewayRes) == 1 {
 		// a paraglider deployment has a single public gateway per region
 		publicGatewayID = publicGatewayRes[0].ID
-		utils.Log.Printf("Using existing public gateway : %s\n", publicGatewayID)
+		slog.Info("Using existing public gateway", "publicGatewayId", publicGatewayID)
 	} else if len(publicGatewayRes) == 0 {
 		// create floating IP for the gateway
 		floatingIPOptions := &vpcv1.CreateFloatingIPOptions{
@@ -519,7 +763,7 @@ func (s *IBMPluginServer) connectToPublicGateway(cloudClient *CloudClient, resou
 			return err
 		}
 		publicGatewayID = *publicGateway.ID
-		utils.Log.Printf("Creating a new public gateway : %s\n", publicGatewayID)
+		slog.Info("Creating a new public gateway", "publicGatewayId", publicGatewayID)
 		// tag the public gateway
 		err = cloudClient.attachTag(publicGateway.CRN, []string{})
 		if err != nil {
@@ -535,7 +779,7 @@ func (s *IBMPluginServer) connectToPublicGateway(cloudClient *CloudClient, resou
 			return err
 		}
 	}
-	utils.Log.Printf("Successfully setup subnet for public gateway\n")
+	slog.Info("Successfully setup subnet for public gateway")
 	return nil
 }
 
@@ -546,7 +790,7 @@ func (s *IBMPluginServer) connectToTransitGatewayIfNeeded(cloudClient *CloudClie
 	// get the VPCs and clients to search if the remote IP resides in any of them
 	clients, err := s.getAllClientsForVPCs(cloudClient, resourceGroup)
 	if err != nil {
-		utils.Log.Printf("Failed to get cloud client for resource group %v, while connecting to transit gateway, with error: %+v", resourceGroup, err)
+		slog.Error("Failed to get cloud client while connecting to transit gateway", "resourceGroup", resourceGroup, "error", err)
 		return err
 	}
 	remoteVPC := ""
@@ -561,32 +805,32 @@ func (s *IBMPluginServer) connectToTransitGatewayIfNeeded(cloudClient *CloudClie
 	vpcID := crn2Id(vpcCRN)
 	// if the remote resides inside an paraglider VPC that isn't the request VM's VPC, connect them
 	if remoteVPC != "" && remoteVPC != vpcID {
-		utils.Log.Printf("The following rule's remote is targeting a different IBM VPC\nRule: %+v\nVPC:%+v", ibmRule, remoteVPC)
+		slog.Info("Rule's remote is targeting a different IBM VPC", "securityGroupRule", ibmRule, "vpc", remoteVPC)
 		// fetch or create transit gateway
 		if len(gwID) == 0 { // lookup optimization, use the already fetched gateway ID if possible
 			gwID, err = cloudClient.GetOrCreateTransitGateway(region)
 			if err != nil {
-				utils.Log.Printf("Failed to get/create a transit gateway in region %v with error: %+v", region, err)
+				slog.Error("Failed to get/create a transit gateway", "region", region, "error", err)
 				return err
 			}
 		}
 		// connect the VPC of the request's VM to the transit gateway.
 		err = cloudClient.ConnectVPC(gwID, vpcCRN)
 		if err != nil {
-			utils.Log.Printf("Failed to connect VPC %v to transit gateway %v, with error: %+v", vpcID, gwID, err)
+			slog.Error("Failed to connect VPC to transit gateway", "vpcId", vpcID, "transitGatewayId", gwID, "error", err)
 			return err
 		}
 
 		remoteVPC, err := remoteVPCClient.GetVPCByID(remoteVPC)
 		if err != nil {
-			utils.Log.Printf("Failed to get remote VPC data of VPC %v, attempting to connect to transit gateway %v, with error: %+v", remoteVPC, gwID, err)
+			slog.Error("Failed to get remote VPC data while connecting to transit gateway", "vpc", remoteVPC, "transitGatewayId", gwID, "error", err)
 			return err
 		}
 
 		// connect remote VPC to the transit gateway.
 		err = remoteVPCClient.ConnectVPC(gwID, *remoteVPC.CRN)
 		if err != nil {
-			utils.Log.Printf("Failed to connect remote VPC %v to transit gateway %v, with error: %+v", remoteVPC, gwID, err)
+			slog.Error("Failed to connect remote VPC to transit gateway", "vpc", remoteVPC, "transitGatewayId", gwID, "error", err)
 			return err
 		}
 	}
@@ -630,7 +874,7 @@ func (s *IBMPluginServer) DeletePermitListRules(ctx context.Context, req *paragl
 	// assuming up to a single paraglider subnet can exist per zone
 	paragliderSgID := paragliderSgsData[0].ID
 
-	conn, err := grpc.NewClient(s.orchestratorServerAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(s.orchestratorServerAddr, utils.DialCredentials(s.orchestratorCredentials), utils.TracingDialOption())
 	if err != nil {
 		return nil, err
 	}
@@ -639,29 +883,29 @@ func (s *IBMPluginServer) DeletePermitListRules(ctx context.Context, req *paragl
 
 	for _, ruleName := range req.RuleNames {
 		ruleID, err := getRuleValFromStore(ctx, client, ruleName, req.Namespace)
-		if err != nil && !strings.Contains(err.Error(), string(redis.Nil)) {
+		if err != nil && status.Code(err) != codes.NotFound {
 			return nil, fmt.Errorf("failed to get from kv store %v", err)
 		}
 		if ruleID == "" {
-			utils.Log.Printf("Rule %s not found in KVstore.", ruleName)
+			slog.WarnContext(ctx, "Rule not found in KV store", "rule", ruleName)
 			continue
 		}
-		utils.Log.Printf("Got %s rule ID for name : %s", ruleID, ruleName)
+		slog.DebugContext(ctx, "Got security group rule ID for rule", "securityGroupRuleId", ruleID, "rule", ruleName)
 
 		err = cloudClient.DeleteSecurityGroupRule(paragliderSgID, ruleID)
 		if err != nil {
 			return nil, err
 		}
-		utils.Log.Printf("Deleted rule %v", ruleID)
+		slog.InfoContext(ctx, "Deleted rule", "securityGroupRuleId", ruleID)
 
 		// delete the references form the kv store
 		err = delRuleValFromStore(ctx, client, ruleName, req.Namespace)
 		if err != nil {
-			utils.Log.Printf("Failed to delete %s from kvstore", ruleName)
+			slog.ErrorContext(ctx, "Failed to delete rule from KV store", "key", ruleName)
 		}
 		err = delRuleValFromStore(ctx, client, ruleID, req.Namespace)
 		if err != nil {
-			utils.Log.Printf("Failed to delete %s from kvstore", ruleID)
+			slog.ErrorContext(ctx, "Failed to delete rule from KV store", "key", ruleID)
 		}
 	}
 
@@ -697,23 +941,23 @@ func (s *IBMPluginServer) CreateVpnGateway(ctx context.Context, req *paragliderp
 func (s *IBMPluginServer) getRegionOfAddressSpace(resourceGroup, namespace, addressSpace string) (string, error) {
 	client, err := s.setupCloudClient(resourceGroup, defaultRegion)
 	if err != nil {
-		utils.Log.Printf("Failed to setup cloud client while trying to get region of address space %v in namespace %v with error: %+v", addressSpace, namespace, err)
+		slog.Error("Failed to setup cloud client while trying to get region of address space", "addressSpace", addressSpace, utils.NamespaceLogKey, namespace, "error", err)
 		return "", err
 	}
 	vpcsData, err := client.GetParagliderTaggedResources(VPC, []string{namespace}, resourceQuery{})
 	if err != nil {
-		utils.Log.Printf("Failed to fetch VPCs while trying to get region of address space %v in namespace %v with error: %+v", addressSpace, namespace, err)
+		slog.Error("Failed to fetch VPCs while trying to get region of address space", "addressSpace", addressSpace, utils.NamespaceLogKey, namespace, "error", err)
 		return "", err
 	}
 	for _, vpcData := range vpcsData {
 		client, err := s.setupCloudClient(resourceGroup, vpcData.Region)
 		if err != nil {
-			utils.Log.Printf("Failed to setup cloud client in region %v, while trying to get region of address space %v in namespace %v with error: %+v", vpcData.Region, addressSpace, namespace, err)
+			slog.Error("Failed to setup cloud client while trying to get region of address space", "region", vpcData.Region, "addressSpace", addressSpace, utils.NamespaceLogKey, namespace, "error", err)
 			return "", err
 		}
 		VPCFound, err := client.IsRemoteInVPC(vpcData.ID, addressSpace)
 		if err != nil {
-			utils.Log.Printf("Error occurred while checking whether VPC %v contains address space %v, in region %v: %+v", vpcData.ID, vpcData.Region, addressSpace, err)
+			slog.Error("Error occurred while checking whether VPC contains address space", "vpcId", vpcData.ID, "region", vpcData.Region, "addressSpace", addressSpace, "error", err)
 			return "", err
 		}
 		if VPCFound {
@@ -730,13 +974,13 @@ func (s *IBMPluginServer) CreateVpnConnections(ctx context.Context, req *paragli
 	}
 	rInfo, err := getResourceMeta(req.Deployment.Id)
 	if err != nil {
-		utils.Log.Printf("Failed to get ResourceIDInfo from deployment %v, while creating VPN connections, with error: %+v", req.Deployment.Id, err)
+		slog.ErrorContext(ctx, "Failed to get ResourceIDInfo from deployment while creating VPN connections", "deployment", req.Deployment.Id, "error", err)
 		return nil, err
 	}
 	// deduce region of VPC containing the provided address space
 	region, err := s.getRegionOfAddressSpace(rInfo.ResourceGroup, req.Deployment.Namespace, req.AddressSpace)
 	if err != nil {
-		utils.Log.Printf("Failed to get region of address space %v, while creating VPN connections, with error: %+v", req.AddressSpace, err)
+		slog.ErrorContext(ctx, "Failed to get region of address space while creating VPN connections", "addressSpace", req.AddressSpace, "error", err)
 		return nil, err
 	}
 	if region == "" {
@@ -749,7 +993,7 @@ func (s *IBMPluginServer) CreateVpnConnections(ctx context.Context, req *paragli
 	// get VPN in the namespace and region
 	vpns, err := cloudClient.GetVPNsInNamespaceRegion(req.Deployment.Namespace, region)
 	if err != nil {
-		utils.Log.Printf("Failed to get VPNs in namespace %v and region %v, while creating VPN connections, with error: %+v", req.Deployment.Namespace, region, err)
+		slog.ErrorContext(ctx, "Failed to get VPNs while creating VPN connections", "region", region, "error", err)
 		return nil, err
 	}
 
@@ -765,7 +1009,7 @@ func (s *IBMPluginServer) CreateVpnConnections(ctx context.Context, req *paragli
 	for _, peerVPNIPAddress := range req.GatewayIpAddresses {
 		err := cloudClient.CreateVPNConnectionRouteBased(vpn.ID, peerVPNIPAddress, req.SharedKey, req.Cloud, req.RemoteAddresses)
 		if err != nil {
-			utils.Log.Printf("Failed to create VPN connection in VPN %v to peer VPN at %v, with error: %+v", vpn.ID, peerVPNIPAddress, err)
+			slog.ErrorContext(ctx, "Failed to create VPN connection", "vpnId", vpn.ID, "peerAddress", peerVPNIPAddress, "error", err)
 			return nil, err
 		}
 	}
@@ -787,29 +1031,29 @@ func (s *IBMPluginServer) GetUsedAsns(ctx context.Context, req *paragliderpb.Get
 func (s *IBMPluginServer) GetNetworkAddressSpaces(ctx context.Context, req *paragliderpb.GetNetworkAddressSpacesRequest) (*paragliderpb.GetNetworkAddressSpacesResponse, error) {
 	rInfo, err := getResourceMeta(req.Deployment.Id)
 	if err != nil {
-		utils.Log.Printf("Failed to get ResourceIDInfo from deployment %v, while fetching address spaces of VPC containing address space: %v, with error: %+v", req.Deployment.Id, req.AddressSpace, err)
+		slog.ErrorContext(ctx, "Failed to get ResourceIDInfo from deployment while fetching address spaces of VPC containing address space", "deployment", req.Deployment.Id, "addressSpace", req.AddressSpace, "error", err)
 		return nil, err
 	}
 
 	client, err := s.setupCloudClient(rInfo.ResourceGroup, defaultRegion)
 	if err != nil {
-		utils.Log.Printf("Failed to setup cloud client with default region %v while fetching address spaces of VPC containing address space: %v with error: %+v", defaultRegion, req.AddressSpace, err)
+		slog.ErrorContext(ctx, "Failed to setup cloud client while fetching address spaces of VPC containing address space", "region", defaultRegion, "addressSpace", req.AddressSpace, "error", err)
 		return nil, err
 	}
 	vpcsData, err := client.GetParagliderTaggedResources(VPC, []string{req.Deployment.Namespace}, resourceQuery{})
 	if err != nil {
-		utils.Log.Printf("Failed to fetch VPCs, while trying to get region of address space %v in namespace %v with error: %+v", req.AddressSpace, req.Deployment.Namespace, err)
+		slog.ErrorContext(ctx, "Failed to fetch VPCs while trying to get region of address space", "addressSpace", req.AddressSpace, "error", err)
 		return nil, err
 	}
 	for _, vpcData := range vpcsData {
 		client, err := s.setupCloudClient(rInfo.ResourceGroup, vpcData.Region)
 		if err != nil {
-			utils.Log.Printf("Failed to setup cloud client in region %v, while trying to get region of address space %v in namespace %v with error: %+v", vpcData.Region, req.AddressSpace, req.Deployment.Namespace, err)
+			slog.ErrorContext(ctx, "Failed to setup cloud client while trying to get region of address space", "region", vpcData.Region, "addressSpace", req.AddressSpace, "error", err)
 			return nil, err
 		}
 		VPCFound, err := client.IsRemoteInVPC(vpcData.ID, req.AddressSpace)
 		if err != nil {
-			utils.Log.Printf("Error occurred while checking whether VPC %v contains address space %v, in region %v: %+v", vpcData.ID, vpcData.Region, req.AddressSpace, err)
+			slog.ErrorContext(ctx, "Error occurred while checking whether VPC contains address space", "vpcId", vpcData.ID, "region", vpcData.Region, "addressSpace", req.AddressSpace, "error", err)
 			return nil, err
 		}
 		if VPCFound {
@@ -823,25 +1067,71 @@ func (s *IBMPluginServer) GetNetworkAddressSpaces(ctx context.Context, req *para
 	return nil, fmt.Errorf("failed to locate VPC containing address space: %v", req.AddressSpace)
 }
 
+// GetCapabilities reports which resources, rule features and VPN modes the plugin supports
+func (s *IBMPluginServer) GetCapabilities(ctx context.Context, req *paragliderpb.GetCapabilitiesRequest) (*paragliderpb.GetCapabilitiesResponse, error) {
+	return &paragliderpb.GetCapabilitiesResponse{
+		ResourceTypes: []paragliderpb.ResourceType{paragliderpb.ResourceType_INSTANCE, paragliderpb.ResourceType_CLUSTER, paragliderpb.ResourceType_PRIVATE_ENDPOINT},
+		RuleFeatures:  &paragliderpb.RuleFeatures{PortRanges: true, Icmp: true},
+		VpnModes:      []paragliderpb.VpnMode{paragliderpb.VpnMode_STATIC},     // IBM VPN gateways don't support BGP
+		Limits:        &paragliderpb.CapabilityLimits{MaxPermitListRules: 250}, // Rules per security group
+	}, nil
+}
+
+// CloudPlugin RPCs the plugin implements, as reported when registering with the controller
+var capabilities = []string{
+	"CreateResource",
+	"DeleteResource",
+	"DetachResource",
+	"DeleteNamespace",
+	"GetPermitList",
+	"AddPermitListRules",
+	"DeletePermitListRules",
+	"GetUsedAddressSpaces",
+	"GetUsedAsns",
+	"GetUsedBgpPeeringIpAddresses",
+	"CreateVpnGateway",
+	"CreateVpnConnections",
+	"GetNetworkAddressSpaces",
+	"GetCapabilities",
+}
+
 // Setup starts up the plugin server and stores the orchestrator server address.
-func Setup(port int, orchestratorServerAddr string) *IBMPluginServer {
-	pluginServerAddress := "localhost"
-	lis, err := net.Listen("tcp", fmt.Sprintf("%v:%d", pluginServerAddress, port))
+func Setup(port int, orchestratorServerAddr string, tlsConfig config.TLS) *IBMPluginServer {
+	return SetupWithAdvertiseAddress(port, orchestratorServerAddr, tlsConfig, "")
+}
+
+// SetupWithAdvertiseAddress starts up the plugin server, which registers with the controller at the advertised address (localhost if empty).
+func SetupWithAdvertiseAddress(port int, orchestratorServerAddr string, tlsConfig config.TLS, advertiseAddress string) *IBMPluginServer {
+	creds, err := utils.LoadTLSCredentials(tlsConfig)
+	if err != nil {
+		slog.Error("Failed to setup TLS", "error", err)
+		return nil
+	}
+	listenAddress, registeredAddress := utils.GetPluginAddresses(port, advertiseAddress)
+	lis, err := net.Listen("tcp", listenAddress)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to listen: %v", err)
+		slog.Error("Failed to listen", "error", err)
 	}
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(grpc.Creds(creds), grpc.ChainUnaryInterceptor(utils.MetricsServerInterceptor, utils.LogFieldsServerInterceptor(utils.CloudLogKey, utils.IBM), utils.NoticesServerInterceptor), utils.TracingServerOption())
 	ibmServer := &IBMPluginServer{
-		cloudClient:            make(map[string]*CloudClient),
-		orchestratorServerAddr: orchestratorServerAddr,
+		cloudClient:             make(map[string]*CloudClient),
+		orchestratorServerAddr:  orchestratorServerAddr,
+		orchestratorCredentials: creds,
 	}
 	paragliderpb.RegisterCloudPluginServer(grpcServer, ibmServer)
-	utils.Log.Printf("\nStarting IBM plugin server on: %v:%v\n", pluginServerAddress, port)
+	utils.RegisterHealthService(grpcServer)
+	slog.Info("Starting IBM plugin server", "address", listenAddress)
 
 	go func() {
 		if err := grpcServer.Serve(lis); err != nil {
-			fmt.Println(err.Error())
+			slog.Error("IBM plugin server stopped", "error", err)
 		}
 	}()
+	go utils.RegisterPlugin(context.Background(), orchestratorServerAddr, creds, &paragliderpb.RegisterPluginRequest{
+		Name:         utils.IBM,
+		Address:      registeredAddress,
+		Version:      version.Version(),
+		Capabilities: capabilities,
+	})
 	return ibmServer
 }
@@ -174,13 +174,13 @@ func TestAddPermitRulesIntegration(t *testing.T) {
 
 	// start ibm plugin server
 	fmt.Println("Setting up IBM server")
-	ibmServer := Setup(IBMServerPort, orchestratorServerAddr)
+	ibmServer := Setup(IBMServerPort, orchestratorServerAddr, config.TLS{})
 
 	fmt.Println("Setting up kv store server")
-	tagging.Setup(dbPort, taggingPort, true)
+	tagging.Setup(dbPort, taggingPort, true, config.TLS{})
 
 	fmt.Println("Setting up kv tagging server")
-	kvstore.Setup(dbPort, kvstorePort, true)
+	kvstore.Setup(dbPort, kvstorePort, true, config.TLS{})
 
 	// Create IBM VM
 	fmt.Println("\nCreating IBM VM...")
@@ -219,7 +219,7 @@ func TestAddPermitRulesIntegration(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, resp)
 
-	utils.Log.Printf("Test response: %+v", resp)
+	t.Logf("Test response: %+v", resp)
 }
 
 // TestCreateVpnGateway creates resource, in which it deploys a vpn gateway with vpn connections.
@@ -277,13 +277,13 @@ func TestCreateVpnGateway(t *testing.T) {
 
 	// start ibm plugin server
 	fmt.Println("Setting up IBM server")
-	ibmServer := Setup(IBMServerPort, orchestratorServerAddr)
+	ibmServer := Setup(IBMServerPort, orchestratorServerAddr, config.TLS{})
 
 	fmt.Println("Setting up kv store server")
-	tagging.Setup(dbPort, taggingPort, true)
+	tagging.Setup(dbPort, taggingPort, true, config.TLS{})
 
 	fmt.Println("Setting up kv tagging server")
-	kvstore.Setup(dbPort, kvstorePort, true)
+	kvstore.Setup(dbPort, kvstorePort, true, config.TLS{})
 
 	// Create IBM VM
 	fmt.Println("\nCreating IBM VM...")
@@ -321,7 +321,7 @@ func TestCreateVpnGateway(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, vpnGatewayResp)
 
-	utils.Log.Printf("VPN gateway creation response: %v", vpnGatewayResp)
+	t.Logf("VPN gateway creation response: %v", vpnGatewayResp)
 
 	// random addresses of peer resource on remote cloud.
 	// To test connectivity with existing deployment on remote cloud replace below values.
@@ -342,5 +342,5 @@ func TestCreateVpnGateway(t *testing.T) {
 	vpnConnectionResp, err := ibmServer.CreateVpnConnections(context.Background(), createVPNConnectionRequest)
 	require.NoError(t, err)
 
-	utils.Log.Printf("VPN connection creation response: %v", vpnConnectionResp)
+	t.Logf("VPN connection creation response: %v", vpnConnectionResp)
 }
@@ -35,6 +35,10 @@ import (
 	"github.com/IBM/platform-services-go-sdk/globaltaggingv1"
 	"github.com/IBM/vpc-go-sdk/vpcv1"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 
 	fake "github.com/paraglider-project/paraglider/pkg/fake/orchestrator/rpc"
 	"github.com/paraglider-project/paraglider/pkg/kvstore"
@@ -359,6 +363,18 @@ func getFakeIBMServerHandler(fakeIBMServerState *fakeIBMServerState) http.Handle
 				sendFakeResponse(w, tagResult)
 				return
 			}
+		case path == "/v3/tags/detach":
+			if r.Method == http.MethodPost { // Detach tag from a resource
+				tagResult := globaltaggingv1.TagResults{
+					Results: []globaltaggingv1.TagResultsItem{
+						{
+							IsError: core.BoolPtr(false),
+						},
+					},
+				}
+				sendFakeResponse(w, tagResult)
+				return
+			}
 		case path == "/vpcs/"+fakeID+"/address_prefixes":
 			if r.Method == http.MethodPost { // Create Address Prefix
 				var newVPCPrefix vpcv1.AddressPrefix
@@ -489,6 +505,11 @@ func getFakeIBMServerHandler(fakeIBMServerState *fakeIBMServerState) http.Handle
 				sendFakeResponse(w, sg)
 				return
 			}
+		case path == "/security_groups/"+fakeID:
+			if r.Method == http.MethodDelete { // Delete a security group
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
 		case strings.Contains(path, "/security_groups/"+fakeID+"/rules/"):
 			if r.Method == http.MethodDelete { // Delete a rule
 				w.WriteHeader(http.StatusOK)
@@ -518,6 +539,11 @@ func getFakeIBMServerHandler(fakeIBMServerState *fakeIBMServerState) http.Handle
 				sendFakeResponse(w, fakeIBMServerState.Instance)
 				return
 			}
+			if r.Method == http.MethodDelete { // Delete an instance
+				fakeIBMServerState.Instance = nil // Deletion is awaited by polling the instance
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
 		case path == "/instances/"+fakeID+"/network_interfaces":
 			if r.Method == http.MethodGet { // List an Instance's network interfaces
 				if fakeIBMServerState.Instance == nil {
@@ -959,6 +985,29 @@ func TestAddPermitListRulesExisting(t *testing.T) {
 	require.NotNil(t, resp)
 }
 
+func TestAddPermitListRulesDeny(t *testing.T) {
+	s := &IBMPluginServer{}
+
+	denyRule := &paragliderpb.PermitListRule{
+		Name:      fakeRuleName1,
+		Direction: paragliderpb.Direction_INBOUND,
+		SrcPort:   -1,
+		DstPort:   -1,
+		Protocol:  6,
+		Targets:   []string{"10.0.0.5"},
+		Action:    paragliderpb.RuleAction_DENY,
+	}
+	addRulesRequest := &paragliderpb.AddPermitListRulesRequest{
+		Namespace: fakeNamespace,
+		Resource:  fakeInstanceID,
+		Rules:     []*paragliderpb.PermitListRule{denyRule},
+	}
+
+	resp, err := s.AddPermitListRules(context.Background(), addRulesRequest)
+	require.ErrorContains(t, err, "do not support deny rules")
+	require.Nil(t, resp)
+}
+
 func TestAddPermitListRulesMissingInstance(t *testing.T) {
 	_, fakeControllerServerAddr, err := fake.SetupFakeOrchestratorRPCServer(utils.IBM)
 	if err != nil {
@@ -1205,6 +1254,49 @@ func TestDeletePermitListRules(t *testing.T) {
 	require.NotNil(t, resp)
 }
 
+func TestDeletePermitListRulesMissingKey(t *testing.T) {
+	// Rules missing from the KV store are skipped
+	_, fakeControllerServerAddr, err := fake.SetupFakeOrchestratorRPCServer(utils.IBM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fakeIBMServerState := &fakeIBMServerState{
+		Instance:      createFakeInstance(),
+		SecurityGroup: createFakeSecurityGroup(true),
+	}
+	fakeServer, ctx, fakeClient := setup(t, fakeIBMServerState)
+	defer fakeServer.Close()
+	s := &IBMPluginServer{
+		cloudClient: map[string]*CloudClient{
+			getClientMapKey(fakeID, fakeRegion): fakeClient,
+		},
+		orchestratorServerAddr: fakeControllerServerAddr,
+	}
+
+	deleteRulesRequest := &paragliderpb.DeletePermitListRulesRequest{
+		Namespace: fakeNamespace,
+		Resource:  fakeInstanceID,
+		RuleNames: []string{fakePermitListVPC[0].Name},
+	}
+
+	resp, err := s.DeletePermitListRules(ctx, deleteRulesRequest)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+}
+
+func TestGetRuleValFromStoreMissingKey(t *testing.T) {
+	_, fakeControllerServerAddr, err := fake.SetupFakeOrchestratorRPCServer(utils.IBM)
+	require.NoError(t, err)
+	conn, err := grpc.NewClient(fakeControllerServerAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	value, err := getRuleValFromStore(context.Background(), paragliderpb.NewControllerClient(conn), fakeRuleName1, fakeNamespace)
+	require.Equal(t, codes.NotFound, status.Code(err))
+	require.Empty(t, value)
+}
+
 func TestDeletePermitListRulesMissingInstance(t *testing.T) {
 	_, fakeControllerServerAddr, err := fake.SetupFakeOrchestratorRPCServer(utils.IBM)
 	if err != nil {
@@ -1262,6 +1354,102 @@ func TestDeletePermitListRulesWrongNamespace(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, resp)
 }
+func TestDeleteResource(t *testing.T) {
+	store := map[string]string{
+		kvstore.GetFullKey(fakeID, utils.IBM, fakeNamespace):                    fakePermitListVPC[0].Name,
+		kvstore.GetFullKey(fakePermitListVPC[0].Name, utils.IBM, fakeNamespace): fakeID,
+	}
+	_, fakeControllerServerAddr, err := fake.SetupFakeOrchestratorRPCServerWithStore(utils.IBM, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// fakeIBMServerState with an instance and security group with rules
+	fakeIBMServerState := &fakeIBMServerState{
+		Instance:      createFakeInstance(),
+		SecurityGroup: createFakeSecurityGroup(true),
+	}
+	fakeServer, ctx, fakeClient := setup(t, fakeIBMServerState)
+	defer fakeServer.Close()
+	s := &IBMPluginServer{
+		cloudClient: map[string]*CloudClient{
+			getClientMapKey(fakeID, fakeRegion): fakeClient,
+		},
+		orchestratorServerAddr: fakeControllerServerAddr,
+	}
+
+	deleteResourceRequest := &paragliderpb.DeleteResourceRequest{
+		Namespace: fakeNamespace,
+		Resource:  fakeInstanceID,
+	}
+
+	resp, err := s.DeleteResource(ctx, deleteResourceRequest)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	require.Nil(t, fakeIBMServerState.Instance)
+}
+
+func TestDeleteResourceMissingInstance(t *testing.T) {
+	_, fakeControllerServerAddr, err := fake.SetupFakeOrchestratorRPCServer(utils.IBM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// fakeIBMServerState without an instance
+	fakeIBMServerState := &fakeIBMServerState{}
+	fakeServer, ctx, fakeClient := setup(t, fakeIBMServerState)
+	defer fakeServer.Close()
+	s := &IBMPluginServer{
+		cloudClient: map[string]*CloudClient{
+			getClientMapKey(fakeID, fakeRegion): fakeClient,
+		},
+		orchestratorServerAddr: fakeControllerServerAddr,
+	}
+
+	deleteResourceRequest := &paragliderpb.DeleteResourceRequest{
+		Namespace: fakeNamespace,
+		Resource:  fakeInstanceID,
+	}
+
+	resp, err := s.DeleteResource(ctx, deleteResourceRequest)
+	require.Error(t, err)
+	require.Nil(t, resp)
+}
+
+func TestDetachResource(t *testing.T) {
+	store := map[string]string{
+		kvstore.GetFullKey(fakeID, utils.IBM, fakeNamespace):                    fakePermitListVPC[0].Name,
+		kvstore.GetFullKey(fakePermitListVPC[0].Name, utils.IBM, fakeNamespace): fakeID,
+	}
+	_, fakeControllerServerAddr, err := fake.SetupFakeOrchestratorRPCServerWithStore(utils.IBM, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// fakeIBMServerState with an instance and security group with rules
+	fakeIBMServerState := &fakeIBMServerState{
+		Instance:      createFakeInstance(),
+		SecurityGroup: createFakeSecurityGroup(true),
+	}
+	fakeServer, ctx, fakeClient := setup(t, fakeIBMServerState)
+	defer fakeServer.Close()
+	s := &IBMPluginServer{
+		cloudClient: map[string]*CloudClient{
+			getClientMapKey(fakeID, fakeRegion): fakeClient,
+		},
+		orchestratorServerAddr: fakeControllerServerAddr,
+	}
+
+	detachResourceRequest := &paragliderpb.DetachResourceRequest{
+		Namespace: fakeNamespace,
+		Resource:  fakeInstanceID,
+	}
+
+	resp, err := s.DetachResource(ctx, detachResourceRequest)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	require.NotNil(t, fakeIBMServerState.Instance)
+}
+
 func TestGetPermitList(t *testing.T) {
 	store := map[string]string{
 		kvstore.GetFullKey(fakeID, utils.IBM, fakeNamespace):  fakePermitListVPC[0].Name,
@@ -1381,3 +1569,41 @@ func TestGetPermitListWrongNamespace(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, resp)
 }
+
+func TestParagliderToIBMRulePortRanges(t *testing.T) {
+	rule := &paragliderpb.PermitListRule{
+		Name:      "rule-name",
+		Targets:   []string{"10.0.0.0/24"},
+		Direction: paragliderpb.Direction_INBOUND,
+		SrcPorts:  []*paragliderpb.PortRange{{Start: 8000, End: 8100}},
+		DstPorts:  []*paragliderpb.PortRange{{Start: 8000, End: 8100}},
+		Protocol:  6,
+	}
+	sgRules, err := ParagliderToIBMRule("sg-id", rule)
+	require.NoError(t, err)
+	require.Len(t, sgRules, 1)
+	require.Equal(t, int64(8000), sgRules[0].PortMin)
+	require.Equal(t, int64(8100), sgRules[0].PortMax)
+
+	paragliderRules, err := IBMToParagliderRules(sgRules)
+	require.NoError(t, err)
+	require.Len(t, paragliderRules, 1)
+	require.Equal(t, rule.SrcPorts, paragliderRules[0].SrcPorts)
+	require.Equal(t, rule.DstPorts, paragliderRules[0].DstPorts)
+
+	// All ports
+	rule.SrcPorts, rule.DstPorts = nil, nil
+	rule.SrcPort, rule.DstPort = -1, -1
+	sgRules, err = ParagliderToIBMRule("sg-id", rule)
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), sgRules[0].PortMin)
+	paragliderRules, err = IBMToParagliderRules(sgRules)
+	require.NoError(t, err)
+	require.Equal(t, int32(-1), paragliderRules[0].SrcPort)
+	require.Equal(t, int32(-1), paragliderRules[0].DstPort)
+
+	// Security group rules only hold a single port range
+	rule.SrcPorts = []*paragliderpb.PortRange{{Start: 80, End: 80}, {Start: 443, End: 443}}
+	_, err = ParagliderToIBMRule("sg-id", rule)
+	require.Error(t, err)
+}
@@ -19,14 +19,13 @@ package ibm
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
 	k8sv1 "github.com/IBM-Cloud/container-services-go-sdk/kubernetesserviceapiv1"
 	"github.com/IBM/go-sdk-core/v5/core"
 	"github.com/IBM/vpc-go-sdk/vpcv1"
-
-	utils "github.com/paraglider-project/paraglider/pkg/utils"
 )
 
 const (
@@ -66,6 +65,8 @@ type ResourceIntf interface {
 	GetID() string
 	GetSecurityGroupID() (string, error)
 	GetVPC() (*vpcv1.VPCReference, error)
+	DeleteResource() error
+	GetCRN() (string, error)
 }
 
 // ResourceInstanceType is the handler for instance type resources
@@ -151,18 +152,18 @@ func (i *ResourceInstanceType) waitForReady() (bool, error) {
 func (i *ResourceInstanceType) CreateResource(name, vpcID, subnetID string, tags []string, resourceDesc []byte) (*ResourceResponse, error) {
 	instanceOptions, err := i.getResourceOptions(resourceDesc)
 	if err != nil {
-		utils.Log.Println("failed to get create instance options: ", err)
+		slog.Error("Failed to get create instance options", "error", err)
 		return nil, err
 	}
 	keyID, err := i.client.setupAuth()
 	if err != nil {
-		utils.Log.Println("failed to setup authentication: ", err)
+		slog.Error("Failed to setup authentication", "error", err)
 		return nil, err
 	}
 
 	securityGroup, err := i.client.createSecurityGroup(vpcID)
 	if err != nil {
-		utils.Log.Println("Failed to create security group for instance with error: ", err)
+		slog.Error("Failed to create security group for instance", "error", err)
 		return nil, err
 	}
 
@@ -183,24 +184,24 @@ func (i *ResourceInstanceType) CreateResource(name, vpcID, subnetID string, tags
 	proto.(*vpcv1.InstancePrototypeInstanceByImage).PrimaryNetworkInterface = &nicPrototype
 	proto.(*vpcv1.InstancePrototypeInstanceByImage).ResourceGroup = i.client.resourceGroup
 
-	utils.Log.Printf("Creating instance : %+v", instanceOptions.InstancePrototype)
+	slog.Debug("Creating instance", "instance", instanceOptions.InstancePrototype)
 
 	instance, _, err := i.client.vpcService.CreateInstance(instanceOptions)
 	if err != nil {
 		return nil, err
 	}
-	utils.Log.Printf("Instance %s was launched with ID: %v", *instance.Name, *instance.ID)
+	slog.Info("Instance was launched", "instance", *instance.Name, "instanceId", *instance.ID)
 
 	i.ID = *instance.ID
 	err = i.client.attachTag(instance.CRN, tags)
 	if err != nil {
-		utils.Log.Print("Failed to tag instance with error:", err)
+		slog.Error("Failed to tag instance", "error", err)
 		return nil, err
 	}
 	// add instance ID tag to security group
 	err = i.client.attachTag(securityGroup.CRN, []string{*instance.ID})
 	if err != nil {
-		utils.Log.Print("Failed to tag SG with error:", err)
+		slog.Error("Failed to tag security group", "error", err)
 		return nil, err
 	}
 
@@ -283,6 +284,28 @@ func (i *ResourceInstanceType) GetVPC() (*vpcv1.VPCReference, error) {
 	return instance.VPC, nil
 }
 
+// DeleteResource deletes the instance and waits for its removal
+func (i *ResourceInstanceType) DeleteResource() error {
+	_, err := i.client.vpcService.DeleteInstance(&vpcv1.DeleteInstanceOptions{ID: &i.ID})
+	if err != nil {
+		return err
+	}
+	if !i.client.waitForInstanceRemoval(i.ID) {
+		return fmt.Errorf("failed to remove instance within the alloted time frame")
+	}
+	slog.Info("Deleted instance", "instanceId", i.ID)
+	return nil
+}
+
+// GetCRN returns the CRN of the instance
+func (i *ResourceInstanceType) GetCRN() (string, error) {
+	instance, err := i.getCRN()
+	if err != nil {
+		return "", err
+	}
+	return *instance.CRN, nil
+}
+
 func (c *ResourceClusterType) createURI(resGroup, zone, resName string) string {
 	return fmt.Sprintf("/resourcegroup/%s/zone/%s/%s/%s", resGroup, zone, ClusterResourceType, resName)
 }
@@ -329,7 +352,7 @@ func (c *ResourceClusterType) waitForReady() (bool, error) {
 func (c *ResourceClusterType) CreateResource(name, vpcID, subnetID string, tags []string, resourceDesc []byte) (*ResourceResponse, error) {
 	clusterOptions, err := c.getResourceOptions(resourceDesc)
 	if err != nil {
-		utils.Log.Println("failed to get create instance options: ", err)
+		slog.Error("Failed to get create cluster options", "error", err)
 		return nil, err
 	}
 
@@ -340,48 +363,48 @@ func (c *ResourceClusterType) CreateResource(name, vpcID, subnetID string, tags
 	clusterOptions.WorkerPool.Zones[0].SubnetID = &subnetID
 
 	// TODO @praveingk : Support multi-zone Kubernetes
-	utils.Log.Printf("Creating cluster : %+v", clusterOptions)
+	slog.Debug("Creating cluster", "cluster", clusterOptions)
 
 	cluster, resp, err := c.client.k8sService.VpcCreateCluster(clusterOptions)
 	if err != nil {
-		fmt.Printf("Failed to create cluster %+v :\n %s\n", *resp, err.Error())
+		slog.Error("Failed to create cluster", "error", err, "response", resp)
 		return nil, err
 	}
-	utils.Log.Printf("Created Cluster : %s\n", *cluster.ClusterID)
+	slog.Info("Created cluster", "clusterId", *cluster.ClusterID)
 
 	c.ID = *cluster.ClusterID
 	clusterCRN, err := c.getCRN()
 	if err != nil {
-		utils.Log.Print("Failed to get CRN of cluster:", err)
+		slog.Error("Failed to get CRN of cluster", "error", err)
 		return nil, err
 	}
 	err = c.client.attachTag(&clusterCRN, tags)
 	if err != nil {
-		utils.Log.Print("Failed to tag cluster with error:", err)
+		slog.Error("Failed to tag cluster", "error", err)
 		return nil, err
 	}
 
 	// Get Cluster VPC Security group
 	vpcSg, err := c.client.getDefaultSecurityGroup(vpcID)
 	if err != nil {
-		utils.Log.Print("Failed to get SG CRN:", err)
+		slog.Error("Failed to get security group CRN", "error", err)
 		return nil, err
 	}
 
 	err = c.client.attachTag(vpcSg.CRN, []string{*cluster.ClusterID, vpcID})
 	if err != nil {
-		utils.Log.Print("Failed to tag SG with error:", err)
+		slog.Error("Failed to tag security group", "error", err)
 		return nil, err
 	}
 
 	clusterCIDR, err := c.client.GetSubnetCIDR(subnetID)
 	if err != nil {
-		utils.Log.Print("Failed to get subnet CIDR:", err)
+		slog.Error("Failed to get subnet CIDR", "error", err)
 		return nil, err
 	}
 
 	if clusterReady, err := c.waitForReady(); !clusterReady || err != nil {
-		utils.Log.Print("Failed to get cluster to ready state:", err)
+		slog.Error("Failed to get cluster to ready state", "error", err)
 		return nil, fmt.Errorf("cluster not ready %v", err.Error())
 	}
 
@@ -445,6 +468,23 @@ func (c *ResourceClusterType) GetVPC() (*vpcv1.VPCReference, error) {
 	return nil, fmt.Errorf("unable to find the VPC of cluster %s", c.ID)
 }
 
+// DeleteResource deletes the cluster. Cluster removal is asynchronous and isn't awaited.
+func (c *ResourceClusterType) DeleteResource() error {
+	options := c.client.k8sService.NewRemoveClusterOptions(c.ID)
+	options.XAuthResourceGroup = c.client.resourceGroup.ID
+	_, err := c.client.k8sService.RemoveCluster(options)
+	if err != nil {
+		return err
+	}
+	slog.Info("Issued removal of cluster", "clusterId", c.ID)
+	return nil
+}
+
+// GetCRN returns the CRN of the cluster
+func (c *ResourceClusterType) GetCRN() (string, error) {
+	return c.getCRN()
+}
+
 func (e *ResourcePrivateEndpointType) createURI(resGroup, zone, resName string) string {
 	return fmt.Sprintf("/resourcegroup/%s/zone/%s/%s/%s", resGroup, zone, PrivateEndpointResourceType, resName)
 }
@@ -504,13 +544,13 @@ func (e *ResourcePrivateEndpointType) waitForReady() (bool, error) {
 func (e *ResourcePrivateEndpointType) CreateResource(name, vpcID, subnetID string, tags []string, resourceDesc []byte) (*ResourceResponse, error) {
 	endpointGatewayOptions, err := e.getResourceOptions(resourceDesc)
 	if err != nil {
-		utils.Log.Println("failed to get create private endpoint gateway options: ", err)
+		slog.Error("Failed to get create private endpoint gateway options", "error", err)
 		return nil, err
 	}
 
 	securityGroup, err := e.client.createSecurityGroup(vpcID)
 	if err != nil {
-		utils.Log.Println("Failed to create security group for instance with error: ", err)
+		slog.Error("Failed to create security group for private endpoint gateway", "error", err)
 		return nil, err
 	}
 	endpointGatewayOptions.Name = &name
@@ -527,30 +567,30 @@ func (e *ResourcePrivateEndpointType) CreateResource(name, vpcID, subnetID strin
 	if err != nil {
 		return nil, err
 	}
-	utils.Log.Printf("Created IP %s with id %s\n", ipName, *resIP.ID)
+	slog.Info("Created IP", "ip", ipName, "ipId", *resIP.ID)
 	endpointGatewayOptions.Ips = []vpcv1.EndpointGatewayReservedIPIntf{
 		&vpcv1.EndpointGatewayReservedIPReservedIPIdentity{ID: resIP.ID}}
 
-	utils.Log.Printf("Creating private endpoint : %+v", endpointGatewayOptions.Target)
+	slog.Debug("Creating private endpoint gateway", "target", endpointGatewayOptions.Target)
 
 	endpointGateway, _, err := e.client.vpcService.CreateEndpointGateway(endpointGatewayOptions)
 	if err != nil {
 		return nil, err
 	}
 
-	utils.Log.Printf("Private Endpoint gateway %s was launched with ID: %v", *endpointGateway.Name, *endpointGateway.ID)
+	slog.Info("Private endpoint gateway was launched", "endpointGateway", *endpointGateway.Name, "endpointGatewayId", *endpointGateway.ID)
 
 	e.ID = *endpointGateway.ID
 	err = e.client.attachTag(endpointGateway.CRN, tags)
 	if err != nil {
-		utils.Log.Print("Failed to tag instance with error:", err)
+		slog.Error("Failed to tag private endpoint gateway", "error", err)
 		return nil, err
 	}
 
 	// add endpoint gateway ID tag to security group
 	err = e.client.attachTag(securityGroup.CRN, []string{*endpointGateway.ID})
 	if err != nil {
-		utils.Log.Print("Failed to tag SG with error:", err)
+		slog.Error("Failed to tag security group", "error", err)
 		return nil, err
 	}
 
@@ -634,6 +674,21 @@ func (e *ResourcePrivateEndpointType) GetVPC() (*vpcv1.VPCReference, error) {
 	return res.VPC, nil
 }
 
+// DeleteResource deletes the endpoint gateway
+func (e *ResourcePrivateEndpointType) DeleteResource() error {
+	_, err := e.client.vpcService.DeleteEndpointGateway(e.client.vpcService.NewDeleteEndpointGatewayOptions(e.ID))
+	if err != nil {
+		return err
+	}
+	slog.Info("Deleted endpoint gateway", "endpointGatewayId", e.ID)
+	return nil
+}
+
+// GetCRN returns the CRN of the endpoint gateway
+func (e *ResourcePrivateEndpointType) GetCRN() (string, error) {
+	return e.getCRN()
+}
+
 // NOTE: Currently not in use, as public ips are not provisioned.
 // deletes floating ips marked recyclable, that are attached to
 // any interface associated with given VM
@@ -644,15 +699,15 @@ func (c *CloudClient) deleteFloatingIPsOfVM(vm *vpcv1.Instance) {
 		options := c.vpcService.NewListInstanceNetworkInterfaceFloatingIpsOptions(*vm.ID, *nic.ID)
 		ips, _, err := c.vpcService.ListInstanceNetworkInterfaceFloatingIps(options)
 		if err != nil {
-			utils.Log.Println(err)
+			slog.Error("Failed to list floating IPs", "error", err)
 		}
 		for _, ip := range ips.FloatingIps {
 			if strings.Contains(recyclableResource, *ip.Name) {
 				_, err := c.vpcService.DeleteFloatingIP(c.vpcService.NewDeleteFloatingIPOptions(*ip.ID))
 				if err != nil {
-					utils.Log.Println(err)
+					slog.Error("Failed to delete floating IP", "error", err)
 				}
-				utils.Log.Println("Deleted recyclable IP: ", *ip.Address)
+				slog.Info("Deleted recyclable IP", "ip", *ip.Address)
 			}
 		}
 	}
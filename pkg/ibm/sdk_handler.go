@@ -18,6 +18,7 @@ package ibm
 
 import (
 	"fmt"
+	"log/slog"
 
 	k8sv1 "github.com/IBM-Cloud/container-services-go-sdk/kubernetesserviceapiv1"
 	"github.com/IBM/go-sdk-core/v5/core"
@@ -72,7 +73,7 @@ func NewIBMCloudClient(resourceGroupID, region string) (*CloudClient, error) {
 		URL:           endpointURL(region),
 	})
 	if err != nil {
-		utils.Log.Println("Failed to create vpc service client with error:\n", err)
+		slog.Error("Failed to create vpc service client", "error", err)
 		return nil, err
 	}
 
@@ -80,7 +81,7 @@ func NewIBMCloudClient(resourceGroupID, region string) (*CloudClient, error) {
 		Authenticator: authenticator,
 	})
 	if err != nil {
-		utils.Log.Println("Failed to create k8s service client with error:\n", err)
+		slog.Error("Failed to create k8s service client", "error", err)
 		return nil, err
 	}
 
@@ -88,7 +89,7 @@ func NewIBMCloudClient(resourceGroupID, region string) (*CloudClient, error) {
 		Authenticator: authenticator,
 	})
 	if err != nil {
-		utils.Log.Println("Failed to create global search client with error:\n", err)
+		slog.Error("Failed to create global search client", "error", err)
 		return nil, err
 	}
 
@@ -97,7 +98,7 @@ func NewIBMCloudClient(resourceGroupID, region string) (*CloudClient, error) {
 	})
 
 	if err != nil {
-		utils.Log.Println("Failed to create tagging client with error:\n", err)
+		slog.Error("Failed to create tagging client", "error", err)
 		return nil, err
 	}
 
@@ -113,6 +114,10 @@ func NewIBMCloudClient(resourceGroupID, region string) (*CloudClient, error) {
 		return nil, err
 	}
 
+	for _, service := range []*core.BaseService{vpcService.Service, k8sService.Service, globalSearch.Service, taggingService.Service, transitGatewayService.Service} {
+		traceRequests(service)
+	}
+
 	client := CloudClient{
 		vpcService:     vpcService,
 		k8sService:     k8sService,
@@ -125,6 +130,12 @@ func NewIBMCloudClient(resourceGroupID, region string) (*CloudClient, error) {
 	return &client, nil
 }
 
+// Records the requests of an IBM service client as spans of the trace of the plugin call making them
+func traceRequests(service *core.BaseService) {
+	httpClient := service.GetHTTPClient()
+	httpClient.Transport = utils.TracingTransport(httpClient.Transport)
+}
+
 // FakeIBMCloudClient returns a fake/mock CloudClient instance without auth, that needs to be handled in the URL
 func FakeIBMCloudClient(fakeURL, fakeResGroupID, fakeRegion string) (*CloudClient, error) {
 	noAuth, err := core.NewNoAuthAuthenticator()
@@ -144,7 +155,7 @@ func FakeIBMCloudClient(fakeURL, fakeResGroupID, fakeRegion string) (*CloudClien
 		URL:           fakeURL,
 	})
 	if err != nil {
-		utils.Log.Println("Failed to create k8s service client with error:\n", err)
+		slog.Error("Failed to create k8s service client", "error", err)
 		return nil, err
 	}
 
@@ -161,7 +172,7 @@ func FakeIBMCloudClient(fakeURL, fakeResGroupID, fakeRegion string) (*CloudClien
 		URL:           fakeURL,
 	})
 	if err != nil {
-		utils.Log.Println("Failed to create tagging client with error:\n", err)
+		slog.Error("Failed to create tagging client", "error", err)
 		return nil, err
 	}
 
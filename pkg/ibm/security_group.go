@@ -19,6 +19,7 @@ package ibm
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net"
 
 	"github.com/IBM/go-sdk-core/v5/core"
@@ -95,14 +96,14 @@ func (c *CloudClient) createSecurityGroup(
 	}
 	sg, resp, err := c.vpcService.CreateSecurityGroup(&options)
 	if err != nil {
-		utils.Log.Printf("%s", resp)
+		slog.Error("Failed to create security group", "error", err, "response", resp)
 		return nil, err
 	}
-	utils.Log.Printf("Created security group %v with id %v", sgName, *sg.ID)
+	slog.Info("Created security group", "securityGroup", sgName, "securityGroupId", *sg.ID)
 
 	err = c.attachTag(sg.CRN, sgTags)
 	if err != nil {
-		utils.Log.Print("Failed to tag SG with error:", err)
+		slog.Error("Failed to tag security group", "error", err)
 		return nil, err
 	}
 	return sg, nil
@@ -401,6 +402,12 @@ func (c *CloudClient) DeleteSecurityGroupRule(sgID, ruleID string) error {
 	return err
 }
 
+// DeleteSecurityGroup deletes the security group with the specified ID
+func (c *CloudClient) DeleteSecurityGroup(sgID string) error {
+	_, err := c.vpcService.DeleteSecurityGroup(c.vpcService.NewDeleteSecurityGroupOptions(sgID))
+	return err
+}
+
 // IsRemoteInCIDR returns true if remote is contained in the CIDR's IP range.
 // remote could be either an IP or a CIDR block.
 func IsRemoteInCIDR(remote, cidr string) (bool, error) {
@@ -503,22 +510,21 @@ func IBMToParagliderRules(rules []SecurityGroupRule) ([]*paragliderpb.PermitList
 	var paragliderRules []*paragliderpb.PermitListRule
 
 	for _, rule := range rules {
-		if rule.PortMin != rule.PortMax {
-			return nil, fmt.Errorf("SG rules with port ranges aren't currently supported")
+		var ports []*paragliderpb.PortRange
+		if rule.PortMin != -1 || rule.PortMax != -1 {
+			ports = []*paragliderpb.PortRange{{Start: int32(rule.PortMin), End: int32(rule.PortMax)}}
 		}
-		// PortMin=PortMax since port ranges aren't supported.
-		// srcPort=dstPort since ibm security rules are stateful,
-		// i.e. they automatically also permit the reverse traffic.
-		srcPort, dstPort := rule.PortMin, rule.PortMin
 
 		permitListRule := &paragliderpb.PermitListRule{
 			Targets:   []string{rule.Remote},
 			Name:      rule.ID,
 			Direction: ibmToParagliderDirection[rule.Egress],
-			SrcPort:   int32(srcPort),
-			DstPort:   int32(dstPort),
 			Protocol:  ibmToParagliderProtocol[rule.Protocol],
 		}
+		// source ports=destination ports since ibm security rules are stateful,
+		// i.e. they automatically also permit the reverse traffic.
+		utils.SetSrcPortRanges(permitListRule, ports)
+		utils.SetDstPortRanges(permitListRule, ports)
 		paragliderRules = append(paragliderRules, permitListRule)
 
 	}
@@ -534,6 +540,10 @@ func ParagliderToIBMRules(securityGroupID string, rules []*paragliderpb.PermitLi
 		if len(rule.Targets) == 0 {
 			return nil, fmt.Errorf("PermitListRule is missing Tag value. Rule:%+v", rule)
 		}
+		portMin, portMax, err := getIBMPortRange(rule)
+		if err != nil {
+			return nil, err
+		}
 		for _, target := range rule.Targets {
 			remote := target
 			remoteType, err := GetRemoteType(remote)
@@ -546,8 +556,8 @@ func ParagliderToIBMRules(securityGroupID string, rules []*paragliderpb.PermitLi
 				Protocol:   paragliderToIBMprotocol[rule.Protocol],
 				Remote:     remote,
 				RemoteType: remoteType,
-				PortMin:    int64(rule.SrcPort),
-				PortMax:    int64(rule.SrcPort),
+				PortMin:    portMin,
+				PortMax:    portMax,
 				Egress:     paragliderToIBMDirection[rule.Direction],
 			}
 
@@ -572,6 +582,10 @@ func ParagliderToIBMRule(securityGroupID string, pgRule *paragliderpb.PermitList
 	if len(pgRule.Targets) == 0 {
 		return nil, fmt.Errorf("PermitListRule is missing target value. Rule:%+v", pgRule)
 	}
+	portMin, portMax, err := getIBMPortRange(pgRule)
+	if err != nil {
+		return nil, err
+	}
 	sgRules := make([]SecurityGroupRule, len(pgRule.Targets))
 	for i, target := range pgRule.Targets {
 		remote := target
@@ -585,8 +599,8 @@ func ParagliderToIBMRule(securityGroupID string, pgRule *paragliderpb.PermitList
 			Protocol:   paragliderToIBMprotocol[pgRule.Protocol],
 			Remote:     remote,
 			RemoteType: remoteType,
-			PortMin:    int64(pgRule.SrcPort),
-			PortMax:    int64(pgRule.SrcPort),
+			PortMin:    portMin,
+			PortMax:    portMax,
 			Egress:     paragliderToIBMDirection[pgRule.Direction],
 		}
 
@@ -601,3 +615,17 @@ func ParagliderToIBMRule(securityGroupID string, pgRule *paragliderpb.PermitList
 
 	return sgRules, nil
 }
+
+// returns the port range of the IBM security group rules translated from the specified paraglider rule (-1 for all ports).
+// NOTE: a security group rule holds a single port range, so rules with several ports or port ranges can't be translated.
+func getIBMPortRange(pgRule *paragliderpb.PermitListRule) (int64, int64, error) {
+	ports := utils.SrcPortRanges(pgRule)
+	switch len(ports) {
+	case 0:
+		return -1, -1, nil
+	case 1:
+		return int64(ports[0].Start), int64(ports[0].End), nil
+	default:
+		return 0, 0, fmt.Errorf("PermitListRule %s has several port ranges, but IBM security group rules only support one", pgRule.Name)
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package ibm
 
 import (
+	"log/slog"
 	"strings"
 
 	"github.com/IBM/vpc-go-sdk/vpcv1"
@@ -57,14 +58,14 @@ func (c *CloudClient) CreateSubnet(
 	options := vpcv1.CreateSubnetOptions{SubnetPrototype: &subnetPrototype}
 	subnet, _, err := c.vpcService.CreateSubnet(&options)
 	if err != nil {
-		utils.Log.Println("Failed to create subnet with error:\n", err)
+		slog.Error("Failed to create subnet", "error", err)
 		return nil, err
 	}
-	utils.Log.Printf("Created subnet %v with id %v", subnetName, *subnet.ID)
+	slog.Info("Created subnet", "subnet", subnetName, "subnetId", *subnet.ID)
 
 	err = c.attachTag(subnet.CRN, tags)
 	if err != nil {
-		utils.Log.Print("Failed to tag subnet with error:", err)
+		slog.Error("Failed to tag subnet", "error", err)
 		return nil, err
 	}
 
@@ -93,7 +94,7 @@ func (c *CloudClient) GetSubnetsInVpcRegionBound(vpcID string) ([]vpcv1.Subnet,
 	subnetOptions := &vpcv1.ListSubnetsOptions{VPCID: &vpcID}
 	subnets, resp, err := c.vpcService.ListSubnets(subnetOptions)
 	if err != nil {
-		utils.Log.Printf("error fetching subnets: %+v", resp)
+		slog.Error("Error fetching subnets", "error", err, "response", resp)
 		return nil, err
 	}
 	return subnets.Subnets, nil
@@ -156,11 +157,10 @@ func (c *CloudClient) DeleteSubnets(vpcID string) error {
 		options := &vpcv1.DeleteSubnetOptions{ID: subnet.ID}
 		_, err := c.vpcService.DeleteSubnet(options)
 		if err != nil {
-			utils.Log.Printf("Failed to delete subnet %v with error:%v",
-				subnet.ID, err)
+			slog.Error("Failed to delete subnet", "subnetId", *subnet.ID, "error", err)
 			return err
 		}
-		utils.Log.Printf("deleted subnet with ID: %v", subnet.ID)
+		slog.Info("Deleted subnet", "subnetId", *subnet.ID)
 	}
 	return nil
 }
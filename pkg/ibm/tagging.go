@@ -18,13 +18,13 @@ package ibm
 
 import (
 	"fmt"
+	"log/slog"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/IBM/platform-services-go-sdk/globalsearchv2"
 	"github.com/IBM/platform-services-go-sdk/globaltaggingv1"
-	utils "github.com/paraglider-project/paraglider/pkg/utils"
 )
 
 func (c *CloudClient) attachTag(CRN *string, tags []string) error {
@@ -49,24 +49,48 @@ func (c *CloudClient) attachTag(CRN *string, tags []string) error {
 		if _, doesHeaderExist := response.Headers["X-Correlation-Id"]; doesHeaderExist {
 			xCorrelationId = response.Headers["X-Correlation-Id"][0]
 		}
-		utils.Log.Printf("Tagging attempt %v on resource CRN: %v with transaction ID: %v and err: %+v\n", attempt, *CRN, xCorrelationId, err)
+		slog.Debug("Tagging attempt", "attempt", attempt, "crn", *CRN, "transactionId", xCorrelationId, "error", err)
 		if !*result.Results[0].IsError {
 			// verify whether resource's tags are updated
 			if err := c.areTagsAttached(CRN, tags); err == nil {
-				utils.Log.Printf("Successfully tagged resource CRN: %v on attempt %v\n", attempt, *CRN)
+				slog.Info("Successfully tagged resource", "crn", *CRN, "attempt", attempt)
 				return nil
 			} else {
-				utils.Log.Printf("Tags were created successfully, but failed to be associated with resource CRN in the alloted time-span: %v\n", *CRN)
+				slog.Error("Tags were created successfully, but failed to be associated with resource in the alloted time-span", "crn", *CRN)
 				return err
 			}
 		}
 		// sleep to avoid busy waiting
 		time.Sleep(5 * time.Second)
 	}
-	utils.Log.Println("Failed to tag resource CRN", *CRN)
+	slog.Error("Failed to tag resource", "crn", *CRN)
 	return fmt.Errorf("failed to tag resource CRN %v", *CRN)
 }
 
+// detachTag removes the specified tags from the resource
+func (c *CloudClient) detachTag(CRN *string, tags []string) error {
+	userTypeTag := globaltaggingv1.DetachTagOptionsTagTypeUserConst
+	resourceModel := &globaltaggingv1.Resource{
+		ResourceID:   CRN,
+		ResourceType: &userTypeTag,
+	}
+
+	detachTagOptions := c.taggingService.NewDetachTagOptions(
+		[]globaltaggingv1.Resource{*resourceModel},
+	)
+	detachTagOptions.SetTagNames(tags)
+
+	result, _, err := c.taggingService.DetachTag(detachTagOptions)
+	if err != nil {
+		return err
+	}
+	if len(result.Results) != 0 && *result.Results[0].IsError {
+		return fmt.Errorf("failed to detach tags %v from resource CRN %v", tags, *CRN)
+	}
+	slog.Info("Detached tags from resource", "tags", tags, "crn", *CRN)
+	return nil
+}
+
 // areTagsAttached returns an error if resource's tags aren't updated (visible to global search service) in the alloted time
 func (c *CloudClient) areTagsAttached(CRN *string, tags []string) error {
 	maxAttempts := 30 // retries number to tag a resource
@@ -184,13 +208,13 @@ func (c *CloudClient) getTaggedResources(query string) (*globalsearchv2.ScanResu
 		res, _, err := c.globalSearch.Search(searchOptions)
 		if err != nil {
 			// keeping unique transaction ID to identify possible recurring errors related to the tagging service.
-			utils.Log.Printf("Tags search with query %v was invalid at attempt %v with error:%+v\n", query, attempt, err)
+			slog.Warn("Tags search query was invalid", "query", query, "attempt", attempt, "error", err)
 		} else {
 			return res, nil
 		}
 		// sleep to avoid busy waiting
 		time.Sleep(5 * time.Second)
 	}
-	utils.Log.Printf("Failed to fetch tagged resource with with query %v", query)
+	slog.Error("Failed to fetch tagged resource", "query", query)
 	return nil, fmt.Errorf("Failed to fetch tagged resource")
 }
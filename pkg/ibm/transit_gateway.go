@@ -18,13 +18,13 @@ package ibm
 
 import (
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/IBM/go-sdk-core/v5/core"
 	"github.com/IBM/networking-go-sdk/transitgatewayapisv1"
 	"github.com/google/uuid"
-	utils "github.com/paraglider-project/paraglider/pkg/utils"
 )
 
 type TransitConnection struct {
@@ -49,7 +49,7 @@ func (c *CloudClient) CreateTransitGW(region string) (*transitgatewayapisv1.Tran
 	if err != nil {
 		return nil, err
 	}
-	utils.Log.Printf("created transit GW named %v with ID: %v", *transitGateway.Name, *transitGateway.ID)
+	slog.Info("Created transit gateway", "transitGateway", *transitGateway.Name, "transitGatewayId", *transitGateway.ID)
 
 	// tag the transitGW with the namespace
 	err = c.attachTag(transitGateway.Crn, []string{})
@@ -89,7 +89,7 @@ func (c *CloudClient) AddTransitGWConnection(transitGatewayID string, vpcCRN str
 	if err != nil {
 		return TransitConnection{}, err
 	}
-	utils.Log.Printf("Added a connection to TGW ID: %v with result: %+v", transitGatewayID, res)
+	slog.Info("Added a connection to transit gateway", "transitGatewayId", transitGatewayID, "result", res)
 
 	return TransitConnection{ID: *res.ID, Name: *res.Name, VPCCRN: *res.NetworkID}, nil
 }
@@ -108,10 +108,10 @@ func (c *CloudClient) DeleteTransitGW(gwID string) error {
 	}
 	res, err := c.transitGW.DeleteTransitGateway(deleteTransitGatewayOptions)
 	if err != nil {
-		utils.Log.Printf("failed to delete transit gateway %v with error: %v and response:\n%+v", gwID, err, res)
+		slog.Error("Failed to delete transit gateway", "transitGatewayId", gwID, "error", err, "response", res)
 		return err
 	}
-	utils.Log.Printf("deleted transit gateway %v with result:\n%+v", gwID, res)
+	slog.Info("Deleted transit gateway", "transitGatewayId", gwID, "result", res)
 	return nil
 }
 
@@ -149,7 +149,7 @@ func (c *CloudClient) pollConnectionDeleted(connectionID string, gwID string) (b
 		_, _, err := c.transitGW.GetTransitGatewayConnection(transitGatewayConnectionOptions)
 		if err != nil {
 			// connection deleted successfully, hence not found error raised
-			utils.Log.Printf("connection deleted successfully in attempt No. %v", attempt)
+			slog.Info("Connection deleted successfully", "attempt", attempt)
 			return true, nil
 		}
 		// sleep to avoid busy waiting
@@ -181,7 +181,7 @@ func (c *CloudClient) ConnectVPC(gatewayID string, vpcCRN string) error {
 	}
 	// must check error message, since error returned isn't a custom type.
 	if strings.Contains(err.Error(), "network is already connected") {
-		utils.Log.Printf("VPC %v is already connected to gateway ID %v", vpcCRN, gatewayID)
+		slog.Info("VPC is already connected to transit gateway", "vpcCrn", vpcCRN, "transitGatewayId", gatewayID)
 		return nil
 	}
 	// failed to connect vpc to the transit gateway due to unexpected reason
@@ -198,7 +198,7 @@ func (c *CloudClient) GetOrCreateTransitGateway(region string) (string, error) {
 	}
 	if len(TransitGatewayRes) == 1 {
 		// a paraglider deployment has a single Transit gateway
-		utils.Log.Printf("Found an existing transit gateway %+v", TransitGatewayRes[0])
+		slog.Info("Found an existing transit gateway", "transitGateway", TransitGatewayRes[0])
 		return TransitGatewayRes[0].ID, nil
 	} else if len(TransitGatewayRes) == 0 {
 		// create a transit gateway
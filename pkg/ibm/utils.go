@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"reflect"
@@ -98,7 +99,8 @@ func endpointURL(region string) string {
 func crn2Id(crn string) string {
 	index := strings.LastIndex(crn, ":")
 	if index == -1 {
-		utils.Log.Fatalf("CRN: %v isn't of valid format", crn)
+		slog.Error("CRN isn't of valid format", "crn", crn)
+		os.Exit(1)
 	}
 	return crn[index+1:]
 }
@@ -116,7 +118,7 @@ func isParagliderResource(name string) bool {
 // TODO cleanup k8s clusters
 func TerminateParagliderDeployments(region string) error {
 	if os.Getenv("INVISINETS_TEST_PERSIST") == "1" {
-		utils.Log.Printf("Skipped IBM resource cleanup function - INVISINETS_TEST_PERSIST is set to 1")
+		slog.Info("Skipped IBM resource cleanup function - INVISINETS_TEST_PERSIST is set to 1")
 		return nil
 	}
 	resGroupID := GetIBMResourceGroupID()
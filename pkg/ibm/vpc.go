@@ -18,10 +18,9 @@ package ibm
 
 import (
 	"fmt"
+	"log/slog"
 
 	"github.com/IBM/vpc-go-sdk/vpcv1"
-
-	utils "github.com/paraglider-project/paraglider/pkg/utils"
 )
 
 const vpcType = "vpc"
@@ -49,16 +48,15 @@ func (c *CloudClient) CreateVPC(tags []string, exclusive bool) (*vpcv1.VPC, erro
 
 	vpc, response, err := c.vpcService.CreateVPC(&options)
 	if err != nil {
-		utils.Log.Println("Failed to create VPC with error:", err,
-			"\nResponse:\n", response)
+		slog.Error("Failed to create VPC", "error", err, "response", response)
 		return nil, err
 	}
 	err = c.attachTag(vpc.CRN, tags)
 	if err != nil {
-		utils.Log.Print("Failed to tag VPC with error:", err)
+		slog.Error("Failed to tag VPC", "error", err)
 		return nil, err
 	}
-	utils.Log.Printf("Created VPC: %v with ID: %v", *vpc.Name, *vpc.ID)
+	slog.Info("Created VPC", "vpc", *vpc.Name, "vpcId", *vpc.ID)
 	return vpc, nil
 }
 
@@ -88,7 +86,7 @@ func (c *CloudClient) TerminateVPC(vpcID string) error {
 		if !c.waitForInstanceRemoval(*instance.ID) {
 			return fmt.Errorf("failed to remove instance within the alloted time frame")
 		}
-		utils.Log.Printf("Deleted instance with ID: %v", *instance.ID)
+		slog.Info("Deleted instance", "instanceId", *instance.ID)
 	}
 
 	err = c.DeleteSubnets(vpcID)
@@ -104,7 +102,44 @@ func (c *CloudClient) TerminateVPC(vpcID string) error {
 		return err
 	}
 
-	utils.Log.Printf("VPC %v deleted successfully", vpcID)
+	slog.Info("VPC deleted successfully", "vpcId", vpcID)
+	return nil
+}
+
+// DeleteVPC deletes a vpc along with its subnets and public gateways. Instances must be deleted beforehand.
+// NOTE: before invoking this function Set VPC client to the region the VPC is located in.
+func (c *CloudClient) DeleteVPC(vpcID string) error {
+	err := c.DeleteSubnets(vpcID)
+	if err != nil {
+		return err
+	}
+
+	// public gateways can only be deleted once no subnet is attached to them
+	publicGateways, _, err := c.vpcService.ListPublicGateways(&vpcv1.ListPublicGatewaysOptions{
+		ResourceGroupID: c.resourceGroup.ID,
+	})
+	if err != nil {
+		return err
+	}
+	for _, publicGateway := range publicGateways.PublicGateways {
+		if publicGateway.VPC == nil || *publicGateway.VPC.ID != vpcID {
+			continue
+		}
+		_, err = c.vpcService.DeletePublicGateway(&vpcv1.DeletePublicGatewayOptions{ID: publicGateway.ID})
+		if err != nil {
+			slog.Error("Failed to delete public gateway", "publicGatewayId", *publicGateway.ID, "error", err)
+			return err
+		}
+	}
+
+	_, err = c.vpcService.DeleteVPC(&vpcv1.DeleteVPCOptions{
+		ID: &vpcID,
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("VPC deleted successfully", "vpcId", vpcID)
 	return nil
 }
 
@@ -114,7 +149,7 @@ func (c *CloudClient) GetVPCByID(vpcID string) (*vpcv1.VPC, error) {
 		ID: &vpcID,
 	})
 	if err != nil {
-		utils.Log.Println("Failed to retrieve VPC, Error: ", err, "\nResponse\n", response)
+		slog.Error("Failed to retrieve VPC", "error", err, "response", response)
 		return nil, err
 	}
 	return vpc, nil
@@ -125,14 +160,14 @@ func (c *CloudClient) GetVpcCIDR(vpcID string) ([]string, error) {
 	// aggregate addresses of subnets in VPC
 	subnets, err := c.GetSubnetsInVpcRegionBound(vpcID)
 	if err != nil {
-		utils.Log.Printf("error while aggregating addresses of subnets to fetch VPC's CIDR: %+v", err)
+		slog.Error("Error while aggregating addresses of subnets to fetch VPC's CIDR", "error", err)
 		return nil, err
 	}
 	var addresses = make([]string, len(subnets))
 	for i, subnet := range subnets {
 		address, err := c.GetSubnetCIDR(*subnet.ID)
 		if err != nil {
-			utils.Log.Printf("error while fetching subnets CIDRs in VPC: %+v", err)
+			slog.Error("Error while fetching subnets CIDRs in VPC", "error", err)
 			return nil, err
 		}
 		addresses[i] = address
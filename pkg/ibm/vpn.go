@@ -18,6 +18,7 @@ package ibm
 
 import (
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -35,7 +36,7 @@ func (c *CloudClient) CreateRouteBasedVPN(namespace string) ([]string, error) {
 	// fetch the the specified namespace's VPC in the region
 	vpcData, err := c.GetParagliderTaggedResources(VPC, []string{namespace}, resourceQuery{Region: c.region})
 	if err != nil {
-		utils.Log.Printf("Failed to get VPC data for VPN deployment with error: %v", err)
+		slog.Error("Failed to get VPC data for VPN deployment", "error", err)
 		return nil, err
 	}
 	if len(vpcData) == 0 {
@@ -44,7 +45,7 @@ func (c *CloudClient) CreateRouteBasedVPN(namespace string) ([]string, error) {
 
 	subnets, err := c.GetSubnetsInVpcRegionBound(vpcData[0].ID)
 	if err != nil {
-		utils.Log.Printf("Failed to get subnets for VPN deployments with error: %+v", err)
+		slog.Error("Failed to get subnets for VPN deployment", "error", err)
 		return nil, err
 	}
 	if len(subnets) == 0 {
@@ -59,31 +60,31 @@ func (c *CloudClient) CreateRouteBasedVPN(namespace string) ([]string, error) {
 		Subnet:        &vpcv1.SubnetIdentity{ID: &subnetID},
 		Mode:          core.StringPtr(vpcv1.VPNGatewayPrototypeVPNGatewayRouteModePrototypeModeRouteConst),
 	}
-	utils.Log.Printf("Creating VPN at %v", c.region)
+	slog.Info("Creating VPN", "region", c.region)
 	vpnInterface, _, err := c.vpcService.CreateVPNGateway(&vpcv1.CreateVPNGatewayOptions{VPNGatewayPrototype: &vpnPrototype})
 	if err != nil {
 		// check if a VPN was already deployed in the VPC.
 		if strings.Contains(err.Error(), "quota") { // Note: relying on error string, since status code is shared with multiple errors.
-			utils.Log.Printf("Route based VPN has reached its max quota of 1 VPN per VPC per region in %v.\n", c.region)
+			slog.Info("Route based VPN has reached its max quota of 1 VPN per VPC per region", "region", c.region)
 			// retrieve existing VPN
 			vpn, err := c.GetVPNsInNamespaceRegion(namespace, c.region)
 			if err != nil {
-				utils.Log.Printf("Failed to get VPN in region %v with error: %+v", c.region, err)
+				slog.Error("Failed to get VPN", "region", c.region, "error", err)
 				return nil, err
 			}
 			if len(vpn) == 0 {
-				utils.Log.Printf("Failed to fetch existing VPN in region %v. Possible tagging/global search issue.", c.region)
+				slog.Error("Failed to fetch existing VPN. Possible tagging/global search issue.", "region", c.region)
 				return nil, fmt.Errorf("Failed to fetch existing VPN in region %v", c.region)
 			}
-			utils.Log.Printf("Retrieving already deployed VPN gateway of VPC %v\n", vpcData[0].ID)
+			slog.Info("Retrieving already deployed VPN gateway of VPC", "vpcId", vpcData[0].ID)
 			ipAddresses, err := c.GetVPNIPs(vpn[0].ID) // array lookup is safe since a VPN exists
 			if err != nil {
-				utils.Log.Printf("Failed to get VPN IPs of VPN ID %v in region %v with error: %+v", vpn[0].ID, c.region, err)
+				slog.Error("Failed to get VPN IPs", "vpnId", vpn[0].ID, "region", c.region, "error", err)
 				return nil, err
 			}
 			return ipAddresses, nil
 		}
-		utils.Log.Printf("Failed to create a VPN with Error: %+v", err)
+		slog.Error("Failed to create a VPN", "error", err)
 		return nil, err
 	}
 	vpnData := vpnInterface.(*vpcv1.VPNGateway)
@@ -91,20 +92,20 @@ func (c *CloudClient) CreateRouteBasedVPN(namespace string) ([]string, error) {
 
 	err = c.pollVPNStatus(vpnID, true) // wait for VPN to be ready
 	if err != nil {
-		utils.Log.Printf("VPN polling error occurred while deploying a VPN:\n%+v", err)
+		slog.Error("VPN polling error occurred while deploying a VPN", "error", err)
 		return nil, err
 	}
 
 	ipAddresses, err := c.GetVPNIPs(vpnID)
 	if err != nil {
-		utils.Log.Printf("Failed to get VPN IPs of newly created VPN with error:\n%+v", err)
+		slog.Error("Failed to get VPN IPs of newly created VPN", "error", err)
 		return nil, err
 	}
-	utils.Log.Printf("VPN %v was launched successfully at %v with assigned IPs: %+v", vpnID, c.region, ipAddresses)
+	slog.Info("VPN was launched successfully", "vpnId", vpnID, "region", c.region, "ipAddresses", ipAddresses)
 
 	err = c.attachTag(&VPNCRN, []string{namespace, vpcData[0].ID})
 	if err != nil {
-		utils.Log.Printf("Error when attaching tags to newly created VPN id %v: %+v", vpnID, err)
+		slog.Error("Error when attaching tags to newly created VPN", "vpnId", vpnID, "error", err)
 		return nil, err
 	}
 
@@ -116,7 +117,7 @@ func (c *CloudClient) CreateRouteBasedVPN(namespace string) ([]string, error) {
 func (c *CloudClient) pollVPNStatus(vpnId string, readyOrDeleted bool) error {
 	attempts := 40
 	sleepDuration := 10 * time.Second
-	utils.Log.Printf("\nPolling VPN status. Process might take up to %v seconds", attempts*(int(sleepDuration/time.Second)))
+	slog.Info("Polling VPN status", "maxSeconds", attempts*(int(sleepDuration/time.Second)))
 	for attempt := 1; attempt <= attempts; attempt += 1 {
 
 		vpnData, _, err := c.vpcService.GetVPNGateway(c.vpcService.NewGetVPNGatewayOptions(
@@ -125,7 +126,7 @@ func (c *CloudClient) pollVPNStatus(vpnId string, readyOrDeleted bool) error {
 
 		if err != nil {
 			if readyOrDeleted { // received err while waiting for ready status
-				utils.Log.Printf("Error occurred while waiting for VPN %v for status update: %+v", vpnId, err)
+				slog.Error("Error occurred while waiting for VPN status update", "vpnId", vpnId, "error", err)
 				return err
 			} else {
 				return nil // VPN can't be found, since it was deleted
@@ -134,7 +135,7 @@ func (c *CloudClient) pollVPNStatus(vpnId string, readyOrDeleted bool) error {
 
 		// VPN desired status is "ready" and so is its current status
 		if readyOrDeleted && *vpnData.(*vpcv1.VPNGateway).LifecycleState == vpcv1.RouteLifecycleStateStableConst {
-			utils.Log.Printf("\nVPN achieved status ready in attempt No. %v\n", attempt)
+			slog.Info("VPN achieved status ready", "attempt", attempt)
 			return nil
 		}
 		time.Sleep(sleepDuration)
@@ -149,7 +150,7 @@ func (c *CloudClient) GetVPNIPs(vpnId string) ([]string, error) {
 		vpnId,
 	))
 	if err != nil {
-		utils.Log.Printf("Failed to get VPN IPs for VPN %v with error: %+v", vpnId, err)
+		slog.Error("Failed to get VPN IPs", "vpnId", vpnId, "error", err)
 		return nil, err
 	}
 	vpnMembers := vpnData.(*vpcv1.VPNGateway).Members
@@ -168,7 +169,7 @@ func (c *CloudClient) GetVPNIPs(vpnId string) ([]string, error) {
 func (c *CloudClient) createRoutes(routingTableID, vpcID, VPNConnectionID string, destinationCIDRs []string) error {
 	zones, err := c.GetZonesOfRegion(c.region)
 	if err != nil {
-		utils.Log.Printf("error while translating zones from region %v", c.region)
+		slog.Error("Error while translating zones from region", "region", c.region)
 		return err
 	}
 
@@ -197,23 +198,23 @@ func (c *CloudClient) createRoutes(routingTableID, vpcID, VPNConnectionID string
 
 			ruleExists, priority, err := c.getAvailablePriority(routeConfig)
 			if err != nil {
-				utils.Log.Printf("Error occurred while getting an available rule priority to create rule %+v: %+v", routeConfig, err)
+				slog.Error("Error occurred while getting an available rule priority to create route", "route", routeConfig, "error", err)
 				return err
 			}
 			// avoid creating a duplicate rule
 			if ruleExists {
-				utils.Log.Printf("\nRoute with the following attributes already exists\n%+v", routeConfig)
+				slog.Info("Route already exists", "route", routeConfig)
 				continue
 			}
 
 			routeConfig.Priority = &priority
 			route, _, err := c.vpcService.CreateVPCRoutingTableRoute(routeConfig)
 			if err != nil {
-				utils.Log.Printf("Error occurred while creating a route with config %+v: %+v", routeConfig, err)
+				slog.Error("Error occurred while creating a route", "route", routeConfig, "error", err)
 				return err
 			}
 
-			utils.Log.Printf("\nCreated route %v in zone %v", *route.ID, zone)
+			slog.Info("Created route", "routeId", *route.ID, "zone", zone)
 		}
 	}
 	return nil
@@ -225,7 +226,7 @@ func (c *CloudClient) getVPNConnectionMatchingPeerIP(VPNGatewayID, peerGWAddress
 		&vpcv1.ListVPNGatewayConnectionsOptions{VPNGatewayID: &VPNGatewayID},
 	)
 	if err != nil {
-		utils.Log.Printf("Error occurred while getting VPN connections matching peer VPN gateway IP address %v: %+v", peerGWAddress, err)
+		slog.Error("Error occurred while getting VPN connections matching peer VPN gateway IP address", "peerAddress", peerGWAddress, "error", err)
 		return nil, err
 	}
 	// filter connections by
@@ -249,7 +250,7 @@ func (c *CloudClient) getVPNConnectionMatchingPeerIP(VPNGatewayID, peerGWAddress
 func (c *CloudClient) CreateVPNConnectionRouteBased(VPNGatewayID, peerGatewayIP, preSharedKey, peerCloud string, destinationCIDRs []string) error {
 	var connectionID string
 
-	if peerCloud != utils.AZURE {
+	if peerCloud != utils.AZURE && peerCloud != utils.GCP {
 		return fmt.Errorf("VPN connections are not yet supported between IBM and Peer cloud %v", peerCloud)
 	}
 	// get or create IKE and IPSec policies to establish a secure VPN connection
@@ -280,39 +281,39 @@ func (c *CloudClient) CreateVPNConnectionRouteBased(VPNGatewayID, peerGatewayIP,
 		if strings.Contains(err.Error(), "duplicate") { // Note: relying on error string, since status code is shared with multiple errors.
 			connection, err := c.getVPNConnectionMatchingPeerIP(VPNGatewayID, peerGatewayIP)
 			if err != nil {
-				utils.Log.Printf("Error occurred while checking for existing connections to peer IP %v in VPN %v: %+v", peerGatewayIP, VPNGatewayID, err)
+				slog.Error("Error occurred while checking for existing connections to peer IP", "peerAddress", peerGatewayIP, "vpnId", VPNGatewayID, "error", err)
 				return err
 			}
 			connectionID = *connection.ID
-			utils.Log.Printf("\nReusing VPN connection: %v", connectionID)
+			slog.Info("Reusing VPN connection", "connectionId", connectionID)
 		} else {
-			utils.Log.Printf("Failed to create VPN connection for VPN %v to peer IP %v: %+v", peerGatewayIP, VPNGatewayID, err)
+			slog.Error("Failed to create VPN connection", "vpnId", VPNGatewayID, "peerAddress", peerGatewayIP, "error", err)
 			return err
 		}
 	}
 	if len(connectionID) == 0 { // if connection doesn't exist, use the one just created
 		connectionID = *connectionInterface.(*vpcv1.VPNGatewayConnectionRouteModeVPNGatewayConnectionStaticRouteMode).ID
-		utils.Log.Printf("\nCreated VPN connection %v", connectionID)
+		slog.Info("Created VPN connection", "connectionId", connectionID)
 	}
 
 	// get the routing table of the VPC where the VPN gateway resides
 	vpnGateway, _, err := c.vpcService.GetVPNGateway(c.vpcService.NewGetVPNGatewayOptions(VPNGatewayID))
 	if err != nil {
-		utils.Log.Printf("Failed to get routing table of the VPC containing VPN gateway %v with error: %+v", VPNGatewayID, err)
+		slog.Error("Failed to get routing table of the VPC containing VPN gateway", "vpnId", VPNGatewayID, "error", err)
 		return err
 	}
 	vpcID := *vpnGateway.(*vpcv1.VPNGateway).VPC.ID
 
 	defaultRoutingTable, _, err := c.vpcService.GetVPCDefaultRoutingTable(c.vpcService.NewGetVPCDefaultRoutingTableOptions(vpcID))
 	if err != nil {
-		utils.Log.Printf("Failed to get default routing table for VPN %v with error: %+v", VPNGatewayID, err)
+		slog.Error("Failed to get default routing table for VPN", "vpnId", VPNGatewayID, "error", err)
 		return err
 	}
 
 	// create routes for all zones in the default routing table of the VPC
 	err = c.createRoutes(*defaultRoutingTable.ID, vpcID, connectionID, destinationCIDRs)
 	if err != nil {
-		utils.Log.Printf("Error occurred while creating routes after deploying VPN connections for VPN %v: %+v", VPNGatewayID, err)
+		slog.Error("Error occurred while creating routes after deploying VPN connections", "vpnId", VPNGatewayID, "error", err)
 		return err
 	}
 	return nil
@@ -329,7 +330,7 @@ func (c *CloudClient) pollVPNConnectionDeleted(VPNGatewayID, connectionID string
 		_, _, err := c.vpcService.GetVPNGatewayConnection(vpnGatewayConnectionOptions)
 		if err != nil {
 			// connection deleted successfully, hence error was raised
-			utils.Log.Printf("connection %v deleted successfully in attempt No. %v", connectionID, attempt)
+			slog.Info("Connection deleted successfully", "connectionId", connectionID, "attempt", attempt)
 			return nil
 		}
 		time.Sleep(10 * time.Second)
@@ -351,7 +352,7 @@ func (c *CloudClient) pollRouteDeleted(vpcID, routingTableID string, route vpcv1
 
 		if err != nil {
 			// route deleted successfully
-			utils.Log.Printf("route at %v deleted successfully in attempt No. %v", routeZone, attempt)
+			slog.Info("Route deleted successfully", "zone", routeZone, "attempt", attempt)
 			return nil
 		}
 		time.Sleep(10 * time.Second)
@@ -365,20 +366,20 @@ func (c *CloudClient) DeleteRoutesDependentOnConnection(VPNGatewayID string, con
 	// get the routing table of the VPC where the VPN gateway resides
 	vpnGateway, _, err := c.vpcService.GetVPNGateway(c.vpcService.NewGetVPNGatewayOptions(VPNGatewayID))
 	if err != nil {
-		utils.Log.Printf("Failed to fetch VPN gateway data for VPN ID %v, during routes deletion process, with error: %+v", VPNGatewayID, err)
+		slog.Error("Failed to fetch VPN gateway data during routes deletion process", "vpnId", VPNGatewayID, "error", err)
 		return err
 	}
 	vpcID := *vpnGateway.(*vpcv1.VPNGateway).VPC.ID
 	defaultRoutingTable, _, err := c.vpcService.GetVPCDefaultRoutingTable(c.vpcService.NewGetVPCDefaultRoutingTableOptions(vpcID))
 	if err != nil {
-		utils.Log.Printf("Failed to fetch default routing table for VPC containing VPN ID %v, during routes deletion process, with error: %+v", VPNGatewayID, err)
+		slog.Error("Failed to fetch default routing table for VPC containing VPN during routes deletion process", "vpnId", VPNGatewayID, "error", err)
 		return err
 	}
 
 	routeCollection, _, err := c.vpcService.ListVPCRoutingTableRoutes(
 		&vpcv1.ListVPCRoutingTableRoutesOptions{VPCID: &vpcID, RoutingTableID: defaultRoutingTable.ID})
 	if err != nil {
-		utils.Log.Printf("Failed to fetch routes for VPC containing VPN ID %v, during routes deletion process, with error: %+v", VPNGatewayID, err)
+		slog.Error("Failed to fetch routes for VPC containing VPN during routes deletion process", "vpnId", VPNGatewayID, "error", err)
 		return err
 	}
 
@@ -395,12 +396,12 @@ func (c *CloudClient) DeleteRoutesDependentOnConnection(VPNGatewayID string, con
 				ID:    route.ID,
 			})
 			if err != nil {
-				utils.Log.Printf("Failed to delete VPC route ID %v routing to connection %v, with error: %+v", *route.ID, *connection.ID, err)
+				slog.Error("Failed to delete VPC route routing to connection", "routeId", *route.ID, "connectionId", *connection.ID, "error", err)
 				return err
 			}
 			// keep track of routes set for deletion (directing to the specified connection)
 			deletedRoutes = append(deletedRoutes, route)
-			utils.Log.Printf("Deleted VPC route %v directing traffic from %v ", *route.ID, *route.Zone.Name)
+			slog.Info("Deleted VPC route", "routeId", *route.ID, "zone", *route.Zone.Name)
 		}
 	}
 
@@ -408,7 +409,7 @@ func (c *CloudClient) DeleteRoutesDependentOnConnection(VPNGatewayID string, con
 	for _, route := range deletedRoutes {
 		err := c.pollRouteDeleted(vpcID, *defaultRoutingTable.ID, route)
 		if err != nil {
-			utils.Log.Printf("Error occurred while polling route status for routes in routing table %v, during routes deletion process, with error: %+v", *defaultRoutingTable.ID, err)
+			slog.Error("Error occurred while polling route status during routes deletion process", "routingTableId", *defaultRoutingTable.ID, "error", err)
 			return err
 		}
 	}
@@ -421,7 +422,7 @@ func (c *CloudClient) DeleteVPN(VPNGatewayID string) error {
 	vpnConnections, _, err := c.vpcService.ListVPNGatewayConnections(
 		&vpcv1.ListVPNGatewayConnectionsOptions{VPNGatewayID: core.StringPtr(VPNGatewayID)})
 	if err != nil {
-		utils.Log.Printf("Failed to fetch VPN connections of VPN %v, during VPN deletion process, with error: %+v", VPNGatewayID, err)
+		slog.Error("Failed to fetch VPN connections during VPN deletion process", "vpnId", VPNGatewayID, "error", err)
 		return err
 	}
 
@@ -431,7 +432,7 @@ func (c *CloudClient) DeleteVPN(VPNGatewayID string) error {
 		// delete routes directing to this connection
 		err := c.DeleteRoutesDependentOnConnection(VPNGatewayID, connection)
 		if err != nil {
-			utils.Log.Printf("Failed to delete routes of VPN connection %v, during VPN deletion process, with error: %+v", *connection.ID, err)
+			slog.Error("Failed to delete routes of VPN connection during VPN deletion process", "connectionId", *connection.ID, "error", err)
 			return err
 		}
 		// set connection for deletion
@@ -439,7 +440,7 @@ func (c *CloudClient) DeleteVPN(VPNGatewayID string) error {
 			&vpcv1.DeleteVPNGatewayConnectionOptions{VPNGatewayID: &VPNGatewayID, ID: connection.ID})
 
 		if err != nil {
-			utils.Log.Printf("Failed to delete VPN connection %v, with error: %+v", *connection.ID, err)
+			slog.Error("Failed to delete VPN connection", "connectionId", *connection.ID, "error", err)
 			return err
 		}
 	}
@@ -449,17 +450,17 @@ func (c *CloudClient) DeleteVPN(VPNGatewayID string) error {
 		connectionID := *connectionInterface.(*vpcv1.VPNGatewayConnectionRouteModeVPNGatewayConnectionStaticRouteMode).ID
 		err = c.pollVPNConnectionDeleted(VPNGatewayID, connectionID)
 		if err != nil {
-			utils.Log.Printf("Error occurred while polling connection %v status, during VPN deletion process, with error: %+v", connectionID, err)
+			slog.Error("Error occurred while polling connection status during VPN deletion process", "connectionId", connectionID, "error", err)
 			return err
 		}
 	}
 
 	_, err = c.vpcService.DeleteVPNGateway(&vpcv1.DeleteVPNGatewayOptions{ID: &VPNGatewayID})
 	if err != nil {
-		utils.Log.Printf("Failed to delete VPN %v, with error: %+v", VPNGatewayID, err)
+		slog.Error("Failed to delete VPN", "vpnId", VPNGatewayID, "error", err)
 		return err
 	}
-	utils.Log.Printf("VPN gateway with ID %v was set for deletion", VPNGatewayID)
+	slog.Info("VPN gateway was set for deletion", "vpnId", VPNGatewayID)
 
 	// wait for VPN deletion (can't delete reliant resources such as subnets otherwise)
 	err = c.pollVPNStatus(VPNGatewayID, false)
@@ -477,11 +478,7 @@ func (c *CloudClient) GetVPNsInNamespaceRegion(namespace, region string) ([]reso
 	// fetch VPN of the specified namespace's region.
 	vpns, err := c.GetParagliderTaggedResources(VPN, []string{namespace}, queryFilter)
 	if err != nil {
-		errMsg := fmt.Sprintf("Failed to fetch VPNs in namespace %v ", namespace)
-		if len(region) > 0 {
-			errMsg += fmt.Sprintf("in region %v", region)
-		}
-		utils.Log.Printf(errMsg+" with error: %+v", err)
+		slog.Error("Failed to fetch VPNs", utils.NamespaceLogKey, namespace, "region", region, "error", err)
 		return nil, err
 	}
 	return vpns, nil
@@ -492,11 +489,11 @@ func (c *CloudClient) getOrCreateIKEPolicy(peerCloud string) (*string, error) {
 	// return an existing IPSec policy for the specified cloud if one exists in the region
 	existingPolicy, err := c.getIKEPolicy(peerCloud)
 	if err != nil {
-		utils.Log.Printf("Error occurred while looking for an existing IKEPolicy policy for cloud %v: %+v", peerCloud, err)
+		slog.Error("Error occurred while looking for an existing IKE policy", "peerCloud", peerCloud, "error", err)
 		return nil, err
 	}
 	if existingPolicy != nil {
-		utils.Log.Printf("Using existing IKE policy %v in region %v", *existingPolicy.Name, c.region)
+		slog.Info("Using existing IKE policy", "policy", *existingPolicy.Name, "region", c.region)
 		return existingPolicy.ID, nil
 	}
 
@@ -511,11 +508,16 @@ func (c *CloudClient) getOrCreateIKEPolicy(peerCloud string) (*string, error) {
 		config.SetDhGroup(24)
 		config.SetEncryptionAlgorithm(vpcv1.CreateIkePolicyOptionsEncryptionAlgorithmAes256Const)
 		config.SetKeyLifetime(27000)
+	} else if peerCloud == utils.GCP {
+		config.SetAuthenticationAlgorithm(vpcv1.CreateIkePolicyOptionsAuthenticationAlgorithmSha256Const)
+		config.SetDhGroup(14)
+		config.SetEncryptionAlgorithm(vpcv1.CreateIkePolicyOptionsEncryptionAlgorithmAes256Const)
+		config.SetKeyLifetime(36000) // GCP's IKE SA lifetime
 	}
 
 	ikePolicy, _, err := c.vpcService.CreateIkePolicy(config)
 	if err != nil {
-		utils.Log.Printf("Failed to create IKEPolicy policy for cloud %v, with error: %+v", peerCloud, err)
+		slog.Error("Failed to create IKE policy", "peerCloud", peerCloud, "error", err)
 		return nil, err
 	}
 
@@ -526,7 +528,7 @@ func (c *CloudClient) getOrCreateIKEPolicy(peerCloud string) (*string, error) {
 func (c *CloudClient) getIKEPolicy(peerCloud string) (*vpcv1.IkePolicy, error) {
 	ikePolicies, _, err := c.vpcService.ListIkePolicies(&vpcv1.ListIkePoliciesOptions{})
 	if err != nil {
-		utils.Log.Printf("Failed to list existing IKEPolicies policies for cloud %v, with error: %+v", peerCloud, err)
+		slog.Error("Failed to list existing IKE policies", "peerCloud", peerCloud, "error", err)
 		return nil, err
 	}
 	for _, policy := range ikePolicies.IkePolicies {
@@ -543,11 +545,11 @@ func (c *CloudClient) getOrCreateIPSecPolicy(peerCloud string) (*string, error)
 	// return an existing IPSec policy for the specified cloud if one exists in the region
 	existingPolicy, err := c.getIPSecPolicy(peerCloud)
 	if err != nil {
-		utils.Log.Printf("Error occurred while looking for an existing IPSec policy for cloud %v: %+v", peerCloud, err)
+		slog.Error("Error occurred while looking for an existing IPSec policy", "peerCloud", peerCloud, "error", err)
 		return nil, err
 	}
 	if existingPolicy != nil {
-		utils.Log.Printf("Using existing IPSec policy %v in region %v", *existingPolicy.Name, c.region)
+		slog.Info("Using existing IPSec policy", "policy", *existingPolicy.Name, "region", c.region)
 		return existingPolicy.ID, nil
 	}
 
@@ -561,10 +563,15 @@ func (c *CloudClient) getOrCreateIPSecPolicy(peerCloud string) (*string, error)
 		config.SetEncryptionAlgorithm("aes256")
 		config.SetKeyLifetime(27000)
 		config.SetPfs(vpcv1.CreateIpsecPolicyOptionsPfsDisabledConst) // disable perfect forward secrecy
+	} else if peerCloud == utils.GCP {
+		config.SetAuthenticationAlgorithm("sha256")
+		config.SetEncryptionAlgorithm("aes256")
+		config.SetKeyLifetime(10800) // GCP's IPsec SA lifetime
+		config.SetPfs(vpcv1.CreateIpsecPolicyOptionsPfsGroup14Const)
 	}
 	ipsecPolicy, _, err := c.vpcService.CreateIpsecPolicy(config)
 	if err != nil {
-		utils.Log.Printf("Failed to create IPSec policy for cloud %v, with error: %+v", peerCloud, err)
+		slog.Error("Failed to create IPSec policy", "peerCloud", peerCloud, "error", err)
 		return nil, err
 	}
 
@@ -575,7 +582,7 @@ func (c *CloudClient) getOrCreateIPSecPolicy(peerCloud string) (*string, error)
 func (c *CloudClient) getIPSecPolicy(peerCloud string) (*vpcv1.IPsecPolicy, error) {
 	ipSecPolicies, _, err := c.vpcService.ListIpsecPolicies(&vpcv1.ListIpsecPoliciesOptions{})
 	if err != nil {
-		utils.Log.Printf("Failed to list existing IPSec policies for cloud %v, with error: %+v", peerCloud, err)
+		slog.Error("Failed to list existing IPSec policies", "peerCloud", peerCloud, "error", err)
 		return nil, err
 	}
 	for _, policy := range ipSecPolicies.IpsecPolicies {
@@ -606,7 +613,7 @@ func (c *CloudClient) getAvailablePriority(routeData *vpcv1.CreateVPCRoutingTabl
 
 	routeCollection, _, err := c.vpcService.ListVPCRoutingTableRoutes(options)
 	if err != nil {
-		utils.Log.Printf("Failed to get routes of routing table %v of VPC %v,while mapping available priorities, with error: %+v", *routeData.RoutingTableID, *routeData.VPCID, err)
+		slog.Error("Failed to get routes of routing table while mapping available priorities", "routingTableId", *routeData.RoutingTableID, "vpcId", *routeData.VPCID, "error", err)
 		return false, -1, err
 	}
 	routeDestination := *routeData.Destination
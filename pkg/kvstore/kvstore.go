@@ -18,13 +18,19 @@ package kvstore
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
+	"os"
 
 	storepb "github.com/paraglider-project/paraglider/pkg/kvstore/storepb"
+	config "github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
 	redis "github.com/redis/go-redis/v9"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func GetFullKey(key string, cloud string, namespace string) string {
@@ -33,18 +39,21 @@ func GetFullKey(key string, cloud string, namespace string) string {
 
 type kvStoreServer struct {
 	storepb.UnimplementedKVStoreServer
-	client *redis.Client
+	store Store
 }
 
-func NewKVStoreServer(client *redis.Client) *kvStoreServer {
+func NewKVStoreServer(store Store) *kvStoreServer {
 	return &kvStoreServer{
-		client: client,
+		store: store,
 	}
 }
 
 func (s *kvStoreServer) Get(ctx context.Context, req *storepb.GetRequest) (*storepb.GetResponse, error) {
-	value, err := s.client.Get(ctx, GetFullKey(req.Key, req.Cloud, req.Namespace)).Result()
+	value, err := s.store.Get(ctx, GetFullKey(req.Key, req.Cloud, req.Namespace))
 	if err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return nil, status.Errorf(codes.NotFound, "key %s not found", req.Key)
+		}
 		return nil, err
 	}
 	return &storepb.GetResponse{
@@ -52,8 +61,27 @@ func (s *kvStoreServer) Get(ctx context.Context, req *storepb.GetRequest) (*stor
 	}, nil
 }
 
+// Get several keys in one request (keys which don't exist are left out of the response)
+func (s *kvStoreServer) GetMany(ctx context.Context, req *storepb.GetManyRequest) (*storepb.GetManyResponse, error) {
+	fullKeys := make([]string, len(req.Keys))
+	for i, key := range req.Keys {
+		fullKeys[i] = GetFullKey(key, req.Cloud, req.Namespace)
+	}
+	values, err := s.store.GetMany(ctx, fullKeys)
+	if err != nil {
+		return nil, err
+	}
+	resp := &storepb.GetManyResponse{Values: make(map[string]string, len(values))}
+	for i, key := range req.Keys {
+		if value, ok := values[fullKeys[i]]; ok {
+			resp.Values[key] = value
+		}
+	}
+	return resp, nil
+}
+
 func (s *kvStoreServer) Set(ctx context.Context, req *storepb.SetRequest) (*storepb.SetResponse, error) {
-	err := s.client.Set(ctx, GetFullKey(req.Key, req.Cloud, req.Namespace), req.Value, 0).Err()
+	err := s.store.Set(ctx, GetFullKey(req.Key, req.Cloud, req.Namespace), req.Value)
 	if err != nil {
 		return nil, err
 	}
@@ -61,37 +89,66 @@ func (s *kvStoreServer) Set(ctx context.Context, req *storepb.SetRequest) (*stor
 }
 
 func (s *kvStoreServer) Delete(ctx context.Context, req *storepb.DeleteRequest) (*storepb.DeleteResponse, error) {
-	err := s.client.Del(ctx, GetFullKey(req.Key, req.Cloud, req.Namespace)).Err()
+	err := s.store.Delete(ctx, GetFullKey(req.Key, req.Cloud, req.Namespace))
 	if err != nil {
 		return nil, err
 	}
 	return &storepb.DeleteResponse{}, nil
 }
 
-// Setup and run the server
-func Setup(dbPort int, serverPort int, clearKeys bool) {
+// Setup and run the server backed by the Redis database on dbPort
+func Setup(dbPort int, serverPort int, clearKeys bool, tlsConfig config.TLS) {
 	client := redis.NewClient(&redis.Options{
 		Addr:     fmt.Sprintf("localhost:%d", dbPort),
 		Password: "", // no password set
 		DB:       0,  // use default DB
 	})
+	if err := serve(NewRedisStore(client), serverPort, clearKeys, tlsConfig); err != nil {
+		slog.Error("Failed to serve", "error", err)
+		os.Exit(1)
+	}
+}
+
+// Setup and run the server backed by the embedded bbolt database at path
+func SetupWithBoltStore(path string, serverPort int, clearKeys bool, tlsConfig config.TLS) error {
+	store, err := NewBoltStore(path)
+	if err != nil {
+		return err
+	}
+	if err := serve(store, serverPort, clearKeys, tlsConfig); err != nil {
+		store.Close()
+		return err
+	}
+	return nil
+}
+
+func serve(store Store, serverPort int, clearKeys bool, tlsConfig config.TLS) error {
 	if clearKeys {
-		fmt.Printf("Flushed all keys.")
-		client.FlushAll(context.Background())
+		if err := store.FlushAll(context.Background()); err != nil {
+			return fmt.Errorf("failed to flush keys: %w", err)
+		}
+		slog.Info("Flushed all keys")
+	}
+
+	creds, err := utils.LoadTLSCredentials(tlsConfig)
+	if err != nil {
+		return fmt.Errorf("failed to setup TLS: %w", err)
 	}
 
 	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", serverPort))
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		return fmt.Errorf("failed to listen: %w", err)
 	}
-	var opts []grpc.ServerOption
+	opts := []grpc.ServerOption{grpc.Creds(creds), grpc.ChainUnaryInterceptor(utils.MetricsServerInterceptor, utils.LogFieldsServerInterceptor()), utils.TracingServerOption()}
 	grpcServer := grpc.NewServer(opts...)
-	storepb.RegisterKVStoreServer(grpcServer, NewKVStoreServer(client))
-	fmt.Printf("Serving KV Store at localhost:%d", serverPort)
-	go func(){
-		err = grpcServer.Serve(lis)
+	storepb.RegisterKVStoreServer(grpcServer, NewKVStoreServer(store))
+	utils.RegisterHealthService(grpcServer)
+	slog.Info("Serving KV Store", "address", lis.Addr().String())
+	go func() {
+		err := grpcServer.Serve(lis)
 		if err != nil {
-			fmt.Println(err.Error())
+			slog.Error("KV Store stopped", "error", err)
 		}
 	}()
+	return nil
 }
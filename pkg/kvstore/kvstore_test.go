@@ -26,11 +26,13 @@ import (
 	storepb "github.com/paraglider-project/paraglider/pkg/kvstore/storepb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestSet(t *testing.T) {
 	db, mock := redismock.NewClientMock()
-	server := NewKVStoreServer(db)
+	server := NewKVStoreServer(NewRedisStore(db))
 
 	key := "test"
 	value := "value"
@@ -50,7 +52,7 @@ func TestSet(t *testing.T) {
 
 func TestGet(t *testing.T) {
 	db, mock := redismock.NewClientMock()
-	server := NewKVStoreServer(db)
+	server := NewKVStoreServer(NewRedisStore(db))
 
 	key := "test"
 	value := "value"
@@ -69,9 +71,28 @@ func TestGet(t *testing.T) {
 	}
 }
 
+func TestGetMany(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	server := NewKVStoreServer(NewRedisStore(db))
+
+	cloud := "cloud"
+	namespace := "namespace"
+
+	// Missing keys are left out
+	mock.ExpectMGet(GetFullKey("test", cloud, namespace), GetFullKey("missing", cloud, namespace)).SetVal([]interface{}{"value", nil})
+	resp, err := server.GetMany(context.Background(), &storepb.GetManyRequest{Keys: []string{"test", "missing"}, Cloud: cloud, Namespace: namespace})
+
+	require.Nil(t, err)
+	assert.Equal(t, map[string]string{"test": "value"}, resp.Values)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestDelete(t *testing.T) {
 	db, mock := redismock.NewClientMock()
-	server := NewKVStoreServer(db)
+	server := NewKVStoreServer(NewRedisStore(db))
 
 	key := "test"
 	cloud := "cloud"
@@ -87,3 +108,22 @@ func TestDelete(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestGetMissingKey(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	server := NewKVStoreServer(NewRedisStore(db))
+
+	key := "test"
+	cloud := "cloud"
+	namespace := "namespace"
+
+	mock.ExpectGet(GetFullKey(key, cloud, namespace)).RedisNil()
+	resp, err := server.Get(context.Background(), &storepb.GetRequest{Key: key, Cloud: cloud, Namespace: namespace})
+
+	require.Nil(t, resp)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
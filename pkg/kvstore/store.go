@@ -0,0 +1,181 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	redis "github.com/redis/go-redis/v9"
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrKeyNotFound is returned by a Store when getting a key which doesn't exist
+var ErrKeyNotFound = errors.New("key not found")
+
+// Store is the database backing the KV store service
+type Store interface {
+	Get(ctx context.Context, key string) (string, error)
+	// GetMany gets several keys at once, leaving out the ones which don't exist
+	GetMany(ctx context.Context, keys []string) (map[string]string, error)
+	Set(ctx context.Context, key string, value string) error
+	Delete(ctx context.Context, key string) error
+	// FlushAll deletes all keys
+	FlushAll(ctx context.Context) error
+	Close() error
+}
+
+// redisStore keeps keys in a Redis database
+type redisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) *redisStore {
+	return &redisStore{client: client}
+}
+
+func (r *redisStore) Get(ctx context.Context, key string) (string, error) {
+	value, err := r.client.Get(ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return "", ErrKeyNotFound
+		}
+		return "", err
+	}
+	return value, nil
+}
+
+func (r *redisStore) GetMany(ctx context.Context, keys []string) (map[string]string, error) {
+	values := make(map[string]string, len(keys))
+	if len(keys) == 0 {
+		return values, nil
+	}
+	results, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, result := range results {
+		if value, ok := result.(string); ok {
+			values[keys[i]] = value
+		}
+	}
+	return values, nil
+}
+
+func (r *redisStore) Set(ctx context.Context, key string, value string) error {
+	return r.client.Set(ctx, key, value, 0).Err()
+}
+
+func (r *redisStore) Delete(ctx context.Context, key string) error {
+	return r.client.Del(ctx, key).Err()
+}
+
+func (r *redisStore) FlushAll(ctx context.Context) error {
+	return r.client.FlushAll(ctx).Err()
+}
+
+func (r *redisStore) Close() error {
+	return r.client.Close()
+}
+
+var boltBucket = []byte("kvstore")
+
+// boltStore keeps keys in an embedded bbolt database file, so no external database process is needed
+type boltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (or creates) the bbolt database at path
+func NewBoltStore(path string) (*boltStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path is required for the bolt store")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create directory for bolt store: %w", err)
+	}
+	// The timeout keeps a second process opening the same file from hanging forever on the file lock
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open bolt store %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to initialize bolt store: %w", err)
+	}
+	return &boltStore{db: db}, nil
+}
+
+func (b *boltStore) Get(ctx context.Context, key string) (string, error) {
+	var value string
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(boltBucket).Get([]byte(key))
+		if data == nil {
+			return ErrKeyNotFound
+		}
+		value = string(data)
+		return nil
+	})
+	return value, err
+}
+
+func (b *boltStore) GetMany(ctx context.Context, keys []string) (map[string]string, error) {
+	values := make(map[string]string, len(keys))
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucket)
+		for _, key := range keys {
+			if data := bucket.Get([]byte(key)); data != nil {
+				values[key] = string(data)
+			}
+		}
+		return nil
+	})
+	return values, err
+}
+
+func (b *boltStore) Set(ctx context.Context, key string, value string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put([]byte(key), []byte(value))
+	})
+}
+
+func (b *boltStore) Delete(ctx context.Context, key string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Delete([]byte(key))
+	})
+}
+
+func (b *boltStore) FlushAll(ctx context.Context) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(boltBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucket(boltBucket)
+		return err
+	})
+}
+
+func (b *boltStore) Close() error {
+	return b.db.Close()
+}
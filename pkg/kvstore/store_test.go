@@ -0,0 +1,101 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvstore
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	storepb "github.com/paraglider-project/paraglider/pkg/kvstore/storepb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBoltStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "db", "kvstore.db")
+	store, err := NewBoltStore(path)
+	require.NoError(t, err)
+
+	// Missing key
+	_, err = store.Get(ctx, "key")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	// Set and overwrite
+	require.NoError(t, store.Set(ctx, "key", "value"))
+	require.NoError(t, store.Set(ctx, "key", "new value"))
+	value, err := store.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, "new value", value)
+
+	// Delete (including a missing key)
+	require.NoError(t, store.Delete(ctx, "key"))
+	require.NoError(t, store.Delete(ctx, "key"))
+	_, err = store.Get(ctx, "key")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	// Keys survive a restart
+	require.NoError(t, store.Set(ctx, "key", "value"))
+	require.NoError(t, store.Close())
+	store, err = NewBoltStore(path)
+	require.NoError(t, err)
+	value, err = store.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, "value", value)
+
+	// Flush
+	require.NoError(t, store.FlushAll(ctx))
+	_, err = store.Get(ctx, "key")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	require.NoError(t, store.Close())
+
+	// Missing path
+	_, err = NewBoltStore("")
+	require.Error(t, err)
+}
+
+func TestServerWithBoltStore(t *testing.T) {
+	store, err := NewBoltStore(filepath.Join(t.TempDir(), "kvstore.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	server := NewKVStoreServer(store)
+
+	_, err = server.Set(context.Background(), &storepb.SetRequest{Key: "key", Value: "value", Cloud: "cloud", Namespace: "namespace"})
+	require.NoError(t, err)
+	resp, err := server.Get(context.Background(), &storepb.GetRequest{Key: "key", Cloud: "cloud", Namespace: "namespace"})
+	require.NoError(t, err)
+	assert.Equal(t, "value", resp.Value)
+
+	// Keys are scoped by cloud and namespace
+	_, err = server.Get(context.Background(), &storepb.GetRequest{Key: "key", Cloud: "cloud", Namespace: "other"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// Several keys at once, leaving out missing ones
+	getManyResp, err := server.GetMany(context.Background(), &storepb.GetManyRequest{Keys: []string{"key", "missing"}, Cloud: "cloud", Namespace: "namespace"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"key": "value"}, getManyResp.Values)
+
+	_, err = server.Delete(context.Background(), &storepb.DeleteRequest{Key: "key", Cloud: "cloud", Namespace: "namespace"})
+	require.NoError(t, err)
+	_, err = server.Get(context.Background(), &storepb.GetRequest{Key: "key", Cloud: "cloud", Namespace: "namespace"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -97,11 +97,11 @@ func TestMulticloud(t *testing.T) {
 	fmt.Println("Setup orchestrator server")
 
 	// Setup Azure
-	azureServer := azure.Setup(azurePluginPort, orchestratorServerAddr)
+	azureServer := azure.Setup(azurePluginPort, orchestratorServerAddr, config.TLS{})
 	fmt.Println("Setup Azure server")
 
 	// Setup GCP
-	gcpServer := gcp.Setup(gcpPluginPort, orchestratorServerAddr)
+	gcpServer := gcp.Setup(gcpPluginPort, orchestratorServerAddr, config.TLS{})
 	fmt.Println("Setup GCP server")
 
 	ctx := context.Background()
@@ -425,19 +425,19 @@ func TestMulticloudIBMAzure(t *testing.T) {
 
 	// start ibm plugin server
 	fmt.Println("Setting up IBM server")
-	ibmServer := ibm.Setup(IBMServerPort, orchestratorServerAddr)
+	ibmServer := ibm.Setup(IBMServerPort, orchestratorServerAddr, config.TLS{})
 
 	// start azure plugin server
 	fmt.Println("Setting up Azure server")
-	azureServer := azure.Setup(azureServerPort, orchestratorServerAddr)
+	azureServer := azure.Setup(azureServerPort, orchestratorServerAddr, config.TLS{})
 
 	// start kv store server
 	fmt.Println("Setting up kv store server")
-	tagging.Setup(dbPort, taggingPort, true)
+	tagging.Setup(dbPort, taggingPort, true, config.TLS{})
 
 	// start tagging server
 	fmt.Println("Setting up kv tagging server")
-	kvstore.Setup(dbPort, kvstorePort, true)
+	kvstore.Setup(dbPort, kvstorePort, true, config.TLS{})
 
 	ctx := context.Background()
 
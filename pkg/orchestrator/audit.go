@@ -0,0 +1,534 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/store"
+	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
+)
+
+const AuditURL string = "/audit"
+
+// Query params filtering the audit events listed
+const (
+	AuditPrincipalQueryParam string = "principal"
+	AuditActionQueryParam    string = "action"
+	AuditNamespaceQueryParam string = "namespace"
+	AuditCloudQueryParam     string = "cloud"
+	AuditResourceQueryParam  string = "resource"
+	AuditTagQueryParam       string = "tag"
+	AuditStatusQueryParam    string = "status"
+	AuditSinceQueryParam     string = "since" // RFC 3339 time
+	AuditUntilQueryParam     string = "until" // RFC 3339 time
+	AuditLimitQueryParam     string = "limit"
+)
+
+// Actions of audit events which aren't operations (operations are recorded with their operation type)
+const (
+	CreateOrAttachResourceAction = "CreateOrAttachResource" // Replaced by the operation type once it's known
+	SetTagAction                 = "SetTag"
+	DeleteTagAction              = "DeleteTag"
+	DeleteTagMemberAction        = "DeleteTagMember"
+	CreateNamespaceAction        = "CreateNamespace"
+	SetNamespaceCloudAction      = "SetNamespaceCloud"
+	CancelOperationAction        = "CancelOperation"
+	ConnectCloudsAction          = "ConnectClouds"
+	SetValueAction               = "SetValue"
+	DeleteValueAction            = "DeleteValue"
+)
+
+type AuditStatus string
+
+const (
+	AuditSucceeded AuditStatus = "SUCCEEDED"
+	AuditFailed    AuditStatus = "FAILED"
+)
+
+// AuditEvent records a state-changing request to the controller, who sent it and what it changed
+type AuditEvent struct {
+	Id          string          `json:"id"`
+	Time        time.Time       `json:"time"` // When the request was received
+	Principal   string          `json:"principal,omitempty"`
+	Action      string          `json:"action"`
+	Namespace   string          `json:"namespace,omitempty"`
+	Cloud       string          `json:"cloud,omitempty"`
+	Resource    string          `json:"resource,omitempty"` // Name of the resource the request is about
+	Tag         string          `json:"tag,omitempty"`
+	Request     json.RawMessage `json:"request,omitempty"`
+	Status      AuditStatus     `json:"status"`
+	Error       string          `json:"error,omitempty"`
+	Result      json.RawMessage `json:"result,omitempty"`
+	OperationId string          `json:"operationId,omitempty"` // Set if the request ran as an operation in the background
+	TraceId     string          `json:"traceId,omitempty"`
+	Resources   []AuditResource `json:"resources,omitempty"` // Cloud resources changed by the request
+}
+
+// AuditResource is a cloud resource changed by a request and the plugin call which changed it
+type AuditResource struct {
+	Cloud     string `json:"cloud"`
+	Namespace string `json:"namespace,omitempty"`
+	Uri       string `json:"uri"` // The deployment for changes to a whole namespace (e.g., VPN gateways)
+	Method    string `json:"method"`
+}
+
+// Action recorded for each state-changing route
+var auditedRoutes = map[string]string{
+	http.MethodPost + " " + AddPermitListRulesURL:         AddPermitListRulesOperation,
+	http.MethodPost + " " + PermitListRulePOSTURL:         AddPermitListRulesOperation,
+	http.MethodPut + " " + PermitListRulePUTURL:           AddPermitListRulesOperation,
+	http.MethodPost + " " + DeletePermitListRulesURL:      DeletePermitListRulesOperation,
+	http.MethodDelete + " " + PermitListRulePUTURL:        DeletePermitListRulesOperation,
+	http.MethodPut + " " + CreateResourcePUTURL:           CreateResourceOperation,
+	http.MethodPost + " " + CreateOrAttachResourcePOSTURL: CreateOrAttachResourceAction,
+	http.MethodDelete + " " + DeleteResourceURL:           DeleteResourceOperation,
+	http.MethodPost + " " + DetachResourceURL:             DetachResourceOperation,
+	http.MethodPost + " " + RuleOnTagURL:                  AddTagPermitListRulesOperation,
+	http.MethodDelete + " " + RuleOnTagURL:                DeleteTagPermitListRulesOperation,
+	http.MethodPost + " " + SetTagURL:                     SetTagAction,
+	http.MethodDelete + " " + DeleteTagURL:                DeleteTagAction,
+	http.MethodDelete + " " + DeleteTagMemberURL:          DeleteTagMemberAction,
+	http.MethodPost + " " + CreateNamespaceURL:            CreateNamespaceAction,
+	http.MethodPut + " " + SetNamespaceCloudURL:           SetNamespaceCloudAction,
+	http.MethodDelete + " " + DeleteNamespaceURL:          DeleteNamespaceOperation,
+	http.MethodPost + " " + ApplyManifestURL:              ApplyManifestOperation,
+	http.MethodPost + " " + CheckDriftURL:                 CheckDriftOperation,
+	http.MethodPost + " " + CancelOperationURL:            CancelOperationAction,
+}
+
+// Action recorded for each state-changing method of the Controller gRPC service
+var auditedMethods = map[string]string{
+	paragliderpb.Controller_ConnectClouds_FullMethodName: ConnectCloudsAction,
+	paragliderpb.Controller_SetValue_FullMethodName:      SetValueAction,
+	paragliderpb.Controller_DeleteValue_FullMethodName:   DeleteValueAction,
+}
+
+// Plugin RPCs which change cloud resources
+var auditedPluginMethods = map[string]bool{
+	paragliderpb.CloudPlugin_CreateResource_FullMethodName:        true,
+	paragliderpb.CloudPlugin_AttachResource_FullMethodName:        true,
+	paragliderpb.CloudPlugin_DeleteResource_FullMethodName:        true,
+	paragliderpb.CloudPlugin_DetachResource_FullMethodName:        true,
+	paragliderpb.CloudPlugin_AddPermitListRules_FullMethodName:    true,
+	paragliderpb.CloudPlugin_DeletePermitListRules_FullMethodName: true,
+	paragliderpb.CloudPlugin_CreateVpnGateway_FullMethodName:      true,
+	paragliderpb.CloudPlugin_CreateVpnConnections_FullMethodName:  true,
+	paragliderpb.CloudPlugin_DeleteNamespace_FullMethodName:       true,
+}
+
+const auditRecordContextKey = "auditRecord"
+
+type auditRecordKey struct{}
+
+// An audit event being recorded. Plugin calls made on behalf of the request may add resources to it concurrently.
+type auditRecord struct {
+	lock     sync.Mutex
+	event    AuditEvent
+	deferred bool // Recorded once the operation the request started finishes rather than when the response is sent
+}
+
+// Starts recording an audit event for a request received now
+func newAuditRecord(ctx context.Context, action string, request any) *auditRecord {
+	record := &auditRecord{event: AuditEvent{Id: uuid.New().String(), Time: time.Now(), Action: action, Request: toRawJSON(request)}}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		record.event.TraceId = spanContext.TraceID().String()
+	}
+	return record
+}
+
+func (r *auditRecord) addResource(resource AuditResource) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !slices.Contains(r.event.Resources, resource) {
+		r.event.Resources = append(r.event.Resources, resource)
+	}
+}
+
+// Sets the outcome of the request
+func (r *auditRecord) finish(result any, err error) AuditEvent {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.event.Status = AuditSucceeded
+	if err != nil {
+		r.event.Status = AuditFailed
+		r.event.Error = err.Error()
+	} else if result != nil {
+		r.event.Result = toRawJSON(result)
+	}
+	return r.event
+}
+
+// Returns the audit record of the request ctx belongs to (if it's audited)
+func getAuditRecord(ctx context.Context) *auditRecord {
+	if record, ok := ctx.Value(auditRecordKey{}).(*auditRecord); ok {
+		return record
+	}
+	// REST requests keep their record on the gin context, which contexts derived from it look up by string keys
+	record, _ := ctx.Value(auditRecordContextKey).(*auditRecord)
+	return record
+}
+
+// Marshals a value to JSON, keeping JSON documents (e.g., request bodies) as they are and other bytes as strings
+func toRawJSON(value any) json.RawMessage {
+	var data []byte
+	var err error
+	switch v := value.(type) {
+	case []byte:
+		if len(v) == 0 {
+			return nil
+		}
+		if json.Valid(v) {
+			return json.RawMessage(v)
+		}
+		data, err = json.Marshal(string(v))
+	case proto.Message:
+		data, err = protojson.Marshal(v)
+	default:
+		data, err = json.Marshal(v)
+	}
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// Appends an audit event to the audit log kept in the allocation store
+func (s *ControllerServer) recordAuditEvent(ctx context.Context, event AuditEvent) {
+	if s.allocations == nil {
+		return
+	}
+	value, err := json.Marshal(event)
+	if err == nil {
+		// The request may have been cancelled, but what it did still needs to be recorded
+		err = s.allocations.Append(context.WithoutCancel(ctx), store.AuditEventsKey, string(value))
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to record audit event", "action", event.Action, "error", err)
+	}
+}
+
+// Captures the body of responses
+type auditResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditResponseWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *auditResponseWriter) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
+
+// Records an audit event for every request to a state-changing route, including the ones which are denied or fail
+func (s *ControllerServer) auditMiddleware(c *gin.Context) {
+	action, ok := auditedRoutes[c.Request.Method+" "+c.FullPath()]
+	if !ok {
+		c.Next()
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.AbortWithStatusJSON(400, createErrorResponse(fmt.Sprintf("could not read request body: %s", err.Error())))
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	record := newAuditRecord(c.Request.Context(), action, body)
+	record.event.Namespace = c.Param("namespace")
+	record.event.Cloud = c.Param("cloud")
+	record.event.Resource = c.Param("resourceName")
+	record.event.Tag = c.Param("tag")
+	c.Set(auditRecordContextKey, record)
+	writer := &auditResponseWriter{ResponseWriter: c.Writer}
+	c.Writer = writer
+
+	c.Next()
+
+	if record.deferred {
+		return
+	}
+	if principal := getPrincipal(c); principal != nil {
+		record.event.Principal = principal.Name
+	}
+	if c.Writer.Status() >= 400 {
+		errorResponse := struct {
+			Error string `json:"error"`
+		}{}
+		if json.Unmarshal(writer.body.Bytes(), &errorResponse) != nil || errorResponse.Error == "" {
+			errorResponse.Error = http.StatusText(c.Writer.Status())
+		}
+		s.recordAuditEvent(c, record.finish(nil, fmt.Errorf("%s", errorResponse.Error)))
+		return
+	}
+	s.recordAuditEvent(c, record.finish(writer.body.Bytes(), nil))
+}
+
+// Records the audit event of the request starting an operation once the operation finishes, so the event holds its outcome
+func (s *ControllerServer) auditOperation(c *gin.Context, opType string, fn operationFunc) operationFunc {
+	record := getAuditRecord(c)
+	if record == nil {
+		return fn
+	}
+	record.deferred = true
+	record.event.Action = opType
+	if principal := getPrincipal(c); principal != nil {
+		record.event.Principal = principal.Name
+	}
+	return func(ctx context.Context) (any, error) {
+		result, err := fn(context.WithValue(ctx, auditRecordKey{}, record))
+		if op, ok := ctx.Value(operationContextKey{}).(*operationProgress); ok {
+			record.lock.Lock()
+			record.event.OperationId = op.id
+			record.lock.Unlock()
+		}
+		s.recordAuditEvent(ctx, record.finish(result, err))
+		return result, err
+	}
+}
+
+// Records an audit event for every call to a state-changing method of the Controller gRPC service
+func (s *ControllerServer) auditUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	action, ok := auditedMethods[info.FullMethod]
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	record := newAuditRecord(ctx, action, req)
+	if principal, ok := ctx.Value(principalKey{}).(string); ok {
+		record.event.Principal = principal
+	}
+	if r, ok := req.(interface{ GetNamespace() string }); ok {
+		record.event.Namespace = r.GetNamespace()
+	}
+	if r, ok := req.(interface{ GetCloud() string }); ok {
+		record.event.Cloud = r.GetCloud()
+	}
+
+	resp, err := handler(context.WithValue(ctx, auditRecordKey{}, record), req)
+	s.recordAuditEvent(ctx, record.finish(resp, err))
+	return resp, err
+}
+
+// Adds the cloud resource changed by a successful plugin call to the audit event of the request it was made for
+func recordAuditResource(ctx context.Context, cloud string, method string, req, reply any) {
+	record := getAuditRecord(ctx)
+	if record == nil || !auditedPluginMethods[method] {
+		return
+	}
+	_, methodName := utils.SplitMethodName(method)
+	resource := AuditResource{Cloud: cloud, Method: methodName}
+	if r, ok := req.(interface{ GetNamespace() string }); ok {
+		resource.Namespace = r.GetNamespace()
+	}
+	if r, ok := req.(interface{ GetResource() string }); ok {
+		resource.Uri = r.GetResource()
+	}
+	if r, ok := req.(interface {
+		GetDeployment() *paragliderpb.ParagliderDeployment
+	}); ok && r.GetDeployment() != nil {
+		resource.Namespace = r.GetDeployment().Namespace
+		resource.Uri = r.GetDeployment().Id
+	}
+	// Created resources are only known by their URI once they exist
+	if r, ok := reply.(interface{ GetUri() string }); ok && r.GetUri() != "" {
+		resource.Uri = r.GetUri()
+	}
+	record.addResource(resource)
+}
+
+// AuditFilter selects audit events. Empty fields match every event.
+type AuditFilter struct {
+	Principal string
+	Action    string
+	Namespace string
+	Cloud     string
+	Resource  string // Matches the resource name of the request or the URI of any cloud resource it changed
+	Tag       string // Matches the tag of the request or any tag its rules target
+	Status    AuditStatus
+	Since     time.Time
+	Until     time.Time
+	Limit     int // Only the most recent events are listed
+}
+
+// Encode returns the filter as the query string of a request listing audit events
+func (f AuditFilter) Encode() string {
+	values := url.Values{}
+	for param, value := range map[string]string{
+		AuditPrincipalQueryParam: f.Principal,
+		AuditActionQueryParam:    f.Action,
+		AuditNamespaceQueryParam: f.Namespace,
+		AuditCloudQueryParam:     f.Cloud,
+		AuditResourceQueryParam:  f.Resource,
+		AuditTagQueryParam:       f.Tag,
+		AuditStatusQueryParam:    string(f.Status),
+	} {
+		if value != "" {
+			values.Set(param, value)
+		}
+	}
+	if !f.Since.IsZero() {
+		values.Set(AuditSinceQueryParam, f.Since.Format(time.RFC3339))
+	}
+	if !f.Until.IsZero() {
+		values.Set(AuditUntilQueryParam, f.Until.Format(time.RFC3339))
+	}
+	if f.Limit > 0 {
+		values.Set(AuditLimitQueryParam, strconv.Itoa(f.Limit))
+	}
+	return values.Encode()
+}
+
+// Parses the filter of a request listing audit events
+func parseAuditFilter(c *gin.Context) (AuditFilter, error) {
+	filter := AuditFilter{
+		Principal: c.Query(AuditPrincipalQueryParam),
+		Action:    c.Query(AuditActionQueryParam),
+		Namespace: c.Query(AuditNamespaceQueryParam),
+		Cloud:     c.Query(AuditCloudQueryParam),
+		Resource:  c.Query(AuditResourceQueryParam),
+		Tag:       c.Query(AuditTagQueryParam),
+		Status:    AuditStatus(c.Query(AuditStatusQueryParam)),
+	}
+	for param, t := range map[string]*time.Time{AuditSinceQueryParam: &filter.Since, AuditUntilQueryParam: &filter.Until} {
+		if value := c.Query(param); value != "" {
+			var err error
+			if *t, err = time.Parse(time.RFC3339, value); err != nil {
+				return filter, fmt.Errorf("invalid %s time %q: must be in RFC 3339 format", param, value)
+			}
+		}
+	}
+	if value := c.Query(AuditLimitQueryParam); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return filter, fmt.Errorf("invalid limit %q", value)
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
+// Matches returns whether an event is selected by the filter (ignoring its limit)
+func (f AuditFilter) Matches(event AuditEvent) bool {
+	if f.Principal != "" && event.Principal != f.Principal {
+		return false
+	}
+	if f.Action != "" && event.Action != f.Action {
+		return false
+	}
+	if f.Namespace != "" && event.Namespace != f.Namespace &&
+		!slices.ContainsFunc(event.Resources, func(r AuditResource) bool { return r.Namespace == f.Namespace }) {
+		return false
+	}
+	if f.Cloud != "" && event.Cloud != f.Cloud &&
+		!slices.ContainsFunc(event.Resources, func(r AuditResource) bool { return r.Cloud == f.Cloud }) {
+		return false
+	}
+	if f.Resource != "" && event.Resource != f.Resource &&
+		!slices.ContainsFunc(event.Resources, func(r AuditResource) bool { return r.Uri == f.Resource }) {
+		return false
+	}
+	if f.Tag != "" && event.Tag != f.Tag && !slices.Contains(ruleTargets(event.Request), f.Tag) {
+		return false
+	}
+	if f.Status != "" && event.Status != f.Status {
+		return false
+	}
+	if !f.Since.IsZero() && event.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && event.Time.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// Returns the tags targeted by the rules in a request body (a rule or a list of rules)
+func ruleTargets(request json.RawMessage) []string {
+	type rule struct {
+		Tags []string `json:"tags"`
+	}
+	var rules []rule
+	if err := json.Unmarshal(request, &rules); err != nil {
+		var single rule
+		if err := json.Unmarshal(request, &single); err != nil {
+			return nil
+		}
+		rules = []rule{single}
+	}
+	targets := []string{}
+	for _, r := range rules {
+		targets = append(targets, r.Tags...)
+	}
+	return targets
+}
+
+// List the audit events selected by the filter of the request, oldest first
+func (s *ControllerServer) listAuditEvents(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
+		return
+	}
+
+	values, err := s.allocations.Log(c, store.AuditEventsKey)
+	if err != nil {
+		c.AbortWithStatusJSON(400, createErrorResponse(fmt.Sprintf("could not list audit events: %s", err.Error())))
+		return
+	}
+	events := []AuditEvent{}
+	for _, value := range values {
+		event := AuditEvent{}
+		if err := json.Unmarshal([]byte(value), &event); err != nil {
+			c.AbortWithStatusJSON(400, createErrorResponse(fmt.Sprintf("invalid stored audit event %s: %s", value, err.Error())))
+			return
+		}
+		if filter.Matches(event) {
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	if filter.Limit > 0 && len(events) > filter.Limit {
+		events = events[len(events)-filter.Limit:]
+	}
+	c.JSON(http.StatusOK, events)
+}
@@ -0,0 +1,187 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	fakeplugin "github.com/paraglider-project/paraglider/pkg/fake/cloudplugin"
+	fakekvstore "github.com/paraglider-project/paraglider/pkg/fake/kvstore"
+	faketagservice "github.com/paraglider-project/paraglider/pkg/fake/tagservice"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/store"
+	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
+)
+
+// Returns the audit events recorded by the server
+func getAuditEvents(t *testing.T, s *ControllerServer) []AuditEvent {
+	values, err := s.allocations.Log(context.Background(), store.AuditEventsKey)
+	require.NoError(t, err)
+	events := []AuditEvent{}
+	for _, value := range values {
+		event := AuditEvent{}
+		require.NoError(t, json.Unmarshal([]byte(value), &event))
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestAuditMiddleware(t *testing.T) {
+	orchestratorServer := newOrchestratorServer()
+	tagServerPort := getNewPortNumber()
+	cloudPluginPort := getNewPortNumber()
+	orchestratorServer.pluginAddresses[exampleCloudName] = fmt.Sprintf("localhost:%d", cloudPluginPort)
+	orchestratorServer.localTagService = fmt.Sprintf("localhost:%d", tagServerPort)
+	fakeplugin.SetupFakePluginServer(cloudPluginPort)
+	faketagservice.SetupFakeTagServer(tagServerPort)
+
+	r := SetUpRouter()
+	r.Use(orchestratorServer.auditMiddleware)
+	r.POST(AddPermitListRulesURL, orchestratorServer.permitListRulesBulkAdd)
+	r.GET(GetPermitListRulesURL, orchestratorServer.permitListGet)
+
+	rules := []*paragliderpb.PermitListRule{{Name: "rulename", Tags: []string{faketagservice.ValidTagName}, Direction: paragliderpb.Direction_INBOUND, SrcPort: 1, DstPort: 2, Protocol: 1}}
+	body, _ := json.Marshal(rules)
+	name := faketagservice.ValidLastLevelTagName
+
+	// Rules added
+	url := fmt.Sprintf(GetFormatterString(AddPermitListRulesURL), defaultNamespace, exampleCloudName, name)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, url, bytes.NewBuffer(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	events := getAuditEvents(t, orchestratorServer)
+	require.Len(t, events, 1)
+	assert.Equal(t, AddPermitListRulesOperation, events[0].Action)
+	assert.Equal(t, AuditSucceeded, events[0].Status)
+	assert.Equal(t, defaultNamespace, events[0].Namespace)
+	assert.Equal(t, exampleCloudName, events[0].Cloud)
+	assert.Equal(t, name, events[0].Resource)
+	assert.JSONEq(t, string(body), string(events[0].Request))
+	require.Len(t, events[0].Resources, 1)
+	assert.Equal(t, AuditResource{Cloud: exampleCloudName, Namespace: defaultNamespace, Uri: faketagservice.TagUri, Method: "AddPermitListRules"}, events[0].Resources[0])
+
+	// Failed request
+	url = fmt.Sprintf(GetFormatterString(AddPermitListRulesURL), defaultNamespace, exampleCloudName, "badname")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, url, bytes.NewBuffer(body)))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	events = getAuditEvents(t, orchestratorServer)
+	require.Len(t, events, 2)
+	assert.Equal(t, AuditFailed, events[1].Status)
+	assert.NotEmpty(t, events[1].Error)
+	assert.Empty(t, events[1].Resources)
+
+	// Reads aren't audited
+	url = fmt.Sprintf(GetFormatterString(GetPermitListRulesURL), defaultNamespace, exampleCloudName, name)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, url, nil))
+	assert.Len(t, getAuditEvents(t, orchestratorServer), 2)
+}
+
+func TestAuditUnaryInterceptor(t *testing.T) {
+	orchestratorServer := newOrchestratorServer()
+	kvStorePort := getNewPortNumber()
+	fakekvstore.SetupFakeTagServer(kvStorePort)
+	orchestratorServer.localKVStoreService = fmt.Sprintf("localhost:%d", kvStorePort)
+
+	call := func(method string, req any, handler grpc.UnaryHandler) error {
+		ctx := context.WithValue(context.Background(), principalKey{}, "alice")
+		_, err := orchestratorServer.auditUnaryInterceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+
+	req := &paragliderpb.SetValueRequest{Key: fakekvstore.ValidKey, Value: fakekvstore.ValidValue, Cloud: exampleCloudName, Namespace: defaultNamespace}
+	err := call(paragliderpb.Controller_SetValue_FullMethodName, req, func(ctx context.Context, req any) (any, error) {
+		return orchestratorServer.SetValue(ctx, req.(*paragliderpb.SetValueRequest))
+	})
+	require.NoError(t, err)
+
+	req = &paragliderpb.SetValueRequest{Key: "invalidkey", Value: fakekvstore.ValidValue, Cloud: exampleCloudName, Namespace: defaultNamespace}
+	err = call(paragliderpb.Controller_SetValue_FullMethodName, req, func(ctx context.Context, req any) (any, error) {
+		return orchestratorServer.SetValue(ctx, req.(*paragliderpb.SetValueRequest))
+	})
+	require.Error(t, err)
+
+	// Reads aren't audited
+	err = call(paragliderpb.Controller_GetValue_FullMethodName, &paragliderpb.GetValueRequest{}, func(ctx context.Context, req any) (any, error) { return nil, nil })
+	require.NoError(t, err)
+
+	events := getAuditEvents(t, orchestratorServer)
+	require.Len(t, events, 2)
+	assert.Equal(t, SetValueAction, events[0].Action)
+	assert.Equal(t, "alice", events[0].Principal)
+	assert.Equal(t, defaultNamespace, events[0].Namespace)
+	assert.Equal(t, exampleCloudName, events[0].Cloud)
+	assert.Contains(t, string(events[0].Request), fakekvstore.ValidKey)
+	assert.Equal(t, AuditSucceeded, events[0].Status)
+	assert.Equal(t, AuditFailed, events[1].Status)
+}
+
+func TestListAuditEvents(t *testing.T) {
+	orchestratorServer := newOrchestratorServer()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, event := range []AuditEvent{
+		{Id: "1", Time: start.Add(2 * time.Hour), Principal: "alice", Action: AddPermitListRulesOperation, Namespace: defaultNamespace, Cloud: exampleCloudName, Resource: "vm-1", Request: json.RawMessage(`[{"name":"rule","tags":["web"]}]`), Status: AuditSucceeded,
+			Resources: []AuditResource{{Cloud: exampleCloudName, Namespace: defaultNamespace, Uri: "uri/vm-1", Method: "AddPermitListRules"}}},
+		{Id: "2", Time: start, Principal: "bob", Action: SetTagAction, Tag: "web", Status: AuditSucceeded},
+		{Id: "3", Time: start.Add(time.Hour), Principal: "alice", Action: DeleteTagAction, Tag: "db", Status: AuditFailed, Error: "not found"},
+	} {
+		orchestratorServer.recordAuditEvent(context.Background(), event)
+	}
+
+	r := SetUpRouter()
+	r.GET(AuditURL, orchestratorServer.listAuditEvents)
+
+	list := func(filter AuditFilter) []string {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, AuditURL+"?"+filter.Encode(), nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		events := []AuditEvent{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &events))
+		ids := []string{}
+		for _, event := range events {
+			ids = append(ids, event.Id)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"2", "3", "1"}, list(AuditFilter{}))
+	assert.Equal(t, []string{"3", "1"}, list(AuditFilter{Principal: "alice"}))
+	assert.Equal(t, []string{"2", "1"}, list(AuditFilter{Tag: "web"}))
+	assert.Equal(t, []string{"1"}, list(AuditFilter{Resource: "uri/vm-1"}))
+	assert.Equal(t, []string{"3"}, list(AuditFilter{Status: AuditFailed}))
+	assert.Equal(t, []string{"3", "1"}, list(AuditFilter{Since: start.Add(time.Minute)}))
+	assert.Equal(t, []string{"2", "3"}, list(AuditFilter{Until: start.Add(time.Hour)}))
+	assert.Equal(t, []string{"1"}, list(AuditFilter{Limit: 1}))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, AuditURL+"?"+AuditSinceQueryParam+"=yesterday", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
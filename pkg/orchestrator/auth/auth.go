@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+)
+
+// Roles which can be granted through role bindings (as used in the orchestrator config)
+const (
+	ReadOnlyRole   = "read-only"
+	RuleEditorRole = "rule-editor"
+	AdminRole      = "admin"
+)
+
+// Action is what a request does. Each role allows its own actions and the ones of the roles below it.
+type Action int
+
+const (
+	ReadAction      Action = iota // Reading permit lists, tags, reports, etc.
+	EditRulesAction               // Adding and deleting permit list rules
+	AdminAction                   // Everything else (e.g., creating resources, setting tags)
+)
+
+var roleActions = map[string]Action{
+	ReadOnlyRole:   ReadAction,
+	RuleEditorRole: EditRulesAction,
+	AdminRole:      AdminAction,
+}
+
+var ErrUnauthenticated = errors.New("no valid credentials provided")
+
+// Principal is an authenticated caller
+type Principal struct {
+	Name   string   `json:"name"`
+	Groups []string `json:"groups,omitempty"`
+	Method string   `json:"method"` // Authentication method ("token", "oidc" or "certificate")
+}
+
+// Credentials are what a caller presented with a request
+type Credentials struct {
+	BearerToken string
+	Certificate *x509.Certificate // Client certificate, only set once it has been verified against the client CAs
+}
+
+// Authenticator validates one kind of credentials. It returns nil without an error if the credentials are not of its kind.
+type Authenticator interface {
+	Authenticate(ctx context.Context, creds Credentials) (*Principal, error)
+}
+
+// Scope is what a request operates on. Requests without a namespace or tag operate on the whole controller.
+type Scope struct {
+	Namespace string
+	Tag       string
+}
+
+// Manager authenticates callers and checks what their role bindings allow them to do
+type Manager struct {
+	authenticators []Authenticator
+	bindings       []config.RoleBinding
+}
+
+// New creates a manager with the authentication methods and role bindings of the config
+func New(cfg config.Auth) (*Manager, error) {
+	m := &Manager{}
+	if len(cfg.Tokens) > 0 {
+		authenticator, err := NewTokenAuthenticator(cfg.Tokens)
+		if err != nil {
+			return nil, err
+		}
+		m.authenticators = append(m.authenticators, authenticator)
+	}
+	if cfg.OIDC.Issuer != "" {
+		authenticator, err := NewOIDCAuthenticator(cfg.OIDC)
+		if err != nil {
+			return nil, err
+		}
+		m.authenticators = append(m.authenticators, authenticator)
+	}
+	// Certificates are only set once verified (against the REST client CAs or the CAs of the gRPC links), so they are always accepted
+	m.authenticators = append(m.authenticators, &CertificateAuthenticator{})
+
+	for _, binding := range cfg.RoleBindings {
+		if _, ok := roleActions[binding.Role]; !ok {
+			return nil, fmt.Errorf("invalid role: %s", binding.Role)
+		}
+		if len(binding.Users) == 0 && len(binding.Groups) == 0 {
+			return nil, fmt.Errorf("role binding for %s must have users or groups", binding.Role)
+		}
+		for _, pattern := range binding.Tags {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid tag pattern %s: %w", pattern, err)
+			}
+		}
+		m.bindings = append(m.bindings, binding)
+	}
+	return m, nil
+}
+
+// Authenticate returns the principal the credentials belong to
+func (m *Manager) Authenticate(ctx context.Context, creds Credentials) (*Principal, error) {
+	var errs []error
+	for _, authenticator := range m.authenticators {
+		principal, err := authenticator.Authenticate(ctx, creds)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if principal != nil {
+			return principal, nil
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%w: %w", ErrUnauthenticated, errors.Join(errs...))
+	}
+	return nil, ErrUnauthenticated
+}
+
+// Authorize returns true if one of the principal's role bindings allows the action in the scope
+func (m *Manager) Authorize(principal *Principal, action Action, scope Scope) bool {
+	for _, binding := range m.bindings {
+		if roleActions[binding.Role] >= action && bindingMatchesPrincipal(binding, principal) && bindingMatchesScope(binding, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+func bindingMatchesPrincipal(binding config.RoleBinding, principal *Principal) bool {
+	if slices.Contains(binding.Users, "*") || slices.Contains(binding.Users, principal.Name) {
+		return true
+	}
+	for _, group := range principal.Groups {
+		if slices.Contains(binding.Groups, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// A binding restricted to namespaces (or tags) only applies to requests in one of them
+func bindingMatchesScope(binding config.RoleBinding, scope Scope) bool {
+	if len(binding.Namespaces) > 0 && !slices.Contains(binding.Namespaces, "*") && !slices.Contains(binding.Namespaces, scope.Namespace) {
+		return false
+	}
+	if len(binding.Tags) > 0 {
+		if scope.Tag == "" {
+			return false
+		}
+		return slices.ContainsFunc(binding.Tags, func(pattern string) bool {
+			matched, _ := path.Match(pattern, scope.Tag)
+			return matched
+		})
+	}
+	return true
+}
@@ -0,0 +1,184 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+)
+
+func TestTokenAuthentication(t *testing.T) {
+	manager, err := New(config.Auth{Tokens: []config.AuthToken{{Name: "alice", Token: "secret", Groups: []string{"dev"}}}})
+	require.NoError(t, err)
+
+	principal, err := manager.Authenticate(context.Background(), Credentials{BearerToken: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, &Principal{Name: "alice", Groups: []string{"dev"}, Method: "token"}, principal)
+
+	_, err = manager.Authenticate(context.Background(), Credentials{BearerToken: "wrong"})
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+	_, err = manager.Authenticate(context.Background(), Credentials{})
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+
+	// Tokens need a name
+	_, err = New(config.Auth{Tokens: []config.AuthToken{{Token: "secret"}}})
+	assert.Error(t, err)
+}
+
+func TestCertificateAuthentication(t *testing.T) {
+	authenticator := &CertificateAuthenticator{}
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "gcp-plugin", Organization: []string{"plugins"}}}
+
+	principal, err := authenticator.Authenticate(context.Background(), Credentials{Certificate: cert})
+	require.NoError(t, err)
+	assert.Equal(t, &Principal{Name: "gcp-plugin", Groups: []string{"plugins"}, Method: "certificate"}, principal)
+
+	principal, err = authenticator.Authenticate(context.Background(), Credentials{BearerToken: "secret"})
+	require.NoError(t, err)
+	assert.Nil(t, principal)
+}
+
+func TestOIDCAuthentication(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	// Fake issuer serving its discovery document and signing keys
+	mux := http.NewServeMux()
+	issuer := httptest.NewServer(mux)
+	defer issuer.Close()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"issuer": issuer.URL, "jwks_uri": issuer.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		jwk := jsonWebKey{
+			Kid: "key-1",
+			Kty: "RSA",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}
+		json.NewEncoder(w).Encode(map[string][]jsonWebKey{"keys": {jwk}})
+	})
+
+	authenticator, err := NewOIDCAuthenticator(config.OIDC{Issuer: issuer.URL, Audience: "paraglider", UsernameClaim: "email"})
+	require.NoError(t, err)
+
+	sign := func(claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "key-1"
+		signed, err := token.SignedString(key)
+		require.NoError(t, err)
+		return signed
+	}
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":    issuer.URL,
+			"aud":    "paraglider",
+			"exp":    time.Now().Add(time.Hour).Unix(),
+			"email":  "bob@example.com",
+			"groups": []string{"network-admins"},
+		}
+	}
+
+	principal, err := authenticator.Authenticate(context.Background(), Credentials{BearerToken: sign(validClaims())})
+	require.NoError(t, err)
+	assert.Equal(t, &Principal{Name: "bob@example.com", Groups: []string{"network-admins"}, Method: "oidc"}, principal)
+
+	// Wrong audience
+	claims := validClaims()
+	claims["aud"] = "other"
+	_, err = authenticator.Authenticate(context.Background(), Credentials{BearerToken: sign(claims)})
+	assert.Error(t, err)
+
+	// Expired
+	claims = validClaims()
+	claims["exp"] = time.Now().Add(-time.Hour).Unix()
+	_, err = authenticator.Authenticate(context.Background(), Credentials{BearerToken: sign(claims)})
+	assert.Error(t, err)
+
+	// Signed by another key
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, validClaims())
+	token.Header["kid"] = "key-1"
+	signed, err := token.SignedString(otherKey)
+	require.NoError(t, err)
+	_, err = authenticator.Authenticate(context.Background(), Credentials{BearerToken: signed})
+	assert.Error(t, err)
+
+	// Tokens which aren't JWTs are left to other authenticators
+	principal, err = authenticator.Authenticate(context.Background(), Credentials{BearerToken: "secret"})
+	require.NoError(t, err)
+	assert.Nil(t, principal)
+}
+
+func TestAuthorize(t *testing.T) {
+	manager, err := New(config.Auth{
+		RoleBindings: []config.RoleBinding{
+			{Role: AdminRole, Users: []string{"admin"}},
+			{Role: RuleEditorRole, Groups: []string{"dev"}, Namespaces: []string{"dev"}},
+			{Role: ReadOnlyRole, Users: []string{"*"}, Tags: []string{"default.*"}},
+		},
+	})
+	require.NoError(t, err)
+
+	admin := &Principal{Name: "admin"}
+	developer := &Principal{Name: "alice", Groups: []string{"dev"}}
+	other := &Principal{Name: "bob"}
+
+	// Admins can do anything anywhere
+	assert.True(t, manager.Authorize(admin, AdminAction, Scope{}))
+	assert.True(t, manager.Authorize(admin, EditRulesAction, Scope{Namespace: "prod", Tag: "web"}))
+
+	// Rule editors can edit rules and read in their namespaces
+	assert.True(t, manager.Authorize(developer, EditRulesAction, Scope{Namespace: "dev", Tag: "dev.gcp.vm"}))
+	assert.True(t, manager.Authorize(developer, ReadAction, Scope{Namespace: "dev"}))
+	assert.False(t, manager.Authorize(developer, AdminAction, Scope{Namespace: "dev"}))
+	assert.False(t, manager.Authorize(developer, EditRulesAction, Scope{Namespace: "prod"}))
+	assert.False(t, manager.Authorize(developer, ReadAction, Scope{}))
+
+	// Anyone can read the tags matching the pattern
+	assert.True(t, manager.Authorize(other, ReadAction, Scope{Tag: "default.gcp.vm"}))
+	assert.True(t, manager.Authorize(other, ReadAction, Scope{Namespace: "default", Tag: "default.gcp.vm"}))
+	assert.False(t, manager.Authorize(other, ReadAction, Scope{Tag: "web"}))
+	assert.False(t, manager.Authorize(other, ReadAction, Scope{Namespace: "default"}))
+	assert.False(t, manager.Authorize(other, EditRulesAction, Scope{Tag: "default.gcp.vm"}))
+
+	// Invalid bindings
+	_, err = New(config.Auth{RoleBindings: []config.RoleBinding{{Role: "owner", Users: []string{"admin"}}}})
+	assert.Error(t, err)
+	_, err = New(config.Auth{RoleBindings: []config.RoleBinding{{Role: AdminRole}}})
+	assert.Error(t, err)
+}
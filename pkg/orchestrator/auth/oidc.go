@@ -0,0 +1,257 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+)
+
+const (
+	defaultUsernameClaim = "sub"
+	defaultGroupsClaim   = "groups"
+	jwksRefreshInterval  = time.Minute // Unknown key IDs trigger a refresh of the signing keys at most this often
+	oidcRequestTimeout   = 10 * time.Second
+)
+
+var oidcSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// OIDCAuthenticator authenticates callers with JWTs issued by an OpenID Connect provider
+type OIDCAuthenticator struct {
+	cfg        config.OIDC
+	httpClient *http.Client
+
+	lock        sync.Mutex
+	keys        map[string]crypto.PublicKey // Signing keys of the issuer by key ID
+	refreshedAt time.Time
+}
+
+func NewOIDCAuthenticator(cfg config.OIDC) (*OIDCAuthenticator, error) {
+	if cfg.Issuer == "" || cfg.Audience == "" {
+		return nil, fmt.Errorf("OIDC authentication requires an issuer and an audience")
+	}
+	if cfg.UsernameClaim == "" {
+		cfg.UsernameClaim = defaultUsernameClaim
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = defaultGroupsClaim
+	}
+	return &OIDCAuthenticator{cfg: cfg, httpClient: &http.Client{Timeout: oidcRequestTimeout}}, nil
+}
+
+func (a *OIDCAuthenticator) Authenticate(ctx context.Context, creds Credentials) (*Principal, error) {
+	// Tokens which aren't JWTs are left to other authenticators
+	if strings.Count(creds.BearerToken, ".") != 2 {
+		return nil, nil
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(creds.BearerToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return a.getKey(ctx, kid)
+	}, jwt.WithIssuer(a.cfg.Issuer), jwt.WithAudience(a.cfg.Audience), jwt.WithValidMethods(oidcSigningMethods), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, fmt.Errorf("invalid OIDC token: %w", err)
+	}
+
+	name, _ := claims[a.cfg.UsernameClaim].(string)
+	if name == "" {
+		return nil, fmt.Errorf("OIDC token has no %s claim", a.cfg.UsernameClaim)
+	}
+	principal := &Principal{Name: name, Method: "oidc"}
+	switch groups := claims[a.cfg.GroupsClaim].(type) {
+	case string:
+		principal.Groups = []string{groups}
+	case []interface{}:
+		for _, group := range groups {
+			if group, ok := group.(string); ok {
+				principal.Groups = append(principal.Groups, group)
+			}
+		}
+	}
+	return principal, nil
+}
+
+// Returns the signing key with the given ID, refreshing the keys of the issuer if it isn't known yet
+func (a *OIDCAuthenticator) getKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if key, ok := a.lookupKey(kid); ok {
+		return key, nil
+	}
+	if time.Since(a.refreshedAt) < jwksRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %s", kid)
+	}
+
+	keys, err := a.fetchKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a.keys = keys
+	a.refreshedAt = time.Now()
+
+	if key, ok := a.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %s", kid)
+}
+
+// Tokens without a key ID can only be verified if the issuer has a single key (must be called with the lock held)
+func (a *OIDCAuthenticator) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(a.keys) == 1 {
+		for _, key := range a.keys {
+			return key, true
+		}
+	}
+	key, ok := a.keys[kid]
+	return key, ok
+}
+
+// Gets a JSON document from the issuer
+func (a *OIDCAuthenticator) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request to %s failed with status code %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// Fetches the signing keys of the issuer
+func (a *OIDCAuthenticator) fetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	jwksURL := a.cfg.JwksURL
+	if jwksURL == "" {
+		discovery := struct {
+			JwksURI string `json:"jwks_uri"`
+		}{}
+		if err := a.getJSON(ctx, strings.TrimSuffix(a.cfg.Issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+			return nil, fmt.Errorf("could not get OIDC discovery document: %w", err)
+		}
+		jwksURL = discovery.JwksURI
+	}
+
+	jwks := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{}
+	if err := a.getJSON(ctx, jwksURL, &jwks); err != nil {
+		return nil, fmt.Errorf("could not get OIDC signing keys: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := parseJSONWebKey(jwk)
+		if err != nil {
+			// Keys of unsupported types are skipped so that the others can still be used
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+func decodeBase64URL(value string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(bytes), nil
+}
+
+// Converts a JWK to a public key
+func parseJSONWebKey(jwk jsonWebKey) (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBase64URL(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBase64URL(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve: %s", jwk.Crv)
+		}
+		x, err := decodeBase64URL(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBase64URL(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve: %s", jwk.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(jwk.X, "="))
+		if err != nil {
+			return nil, err
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type: %s", jwk.Kty)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+)
+
+// TokenAuthenticator authenticates callers with static API tokens from the config
+type TokenAuthenticator struct {
+	tokens []config.AuthToken
+}
+
+func NewTokenAuthenticator(tokens []config.AuthToken) (*TokenAuthenticator, error) {
+	for _, token := range tokens {
+		if token.Name == "" || token.Token == "" {
+			return nil, fmt.Errorf("API tokens must have a name and a token")
+		}
+	}
+	return &TokenAuthenticator{tokens: tokens}, nil
+}
+
+func (a *TokenAuthenticator) Authenticate(ctx context.Context, creds Credentials) (*Principal, error) {
+	if creds.BearerToken == "" {
+		return nil, nil
+	}
+	for _, token := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(token.Token), []byte(creds.BearerToken)) == 1 {
+			return &Principal{Name: token.Name, Groups: token.Groups, Method: "token"}, nil
+		}
+	}
+	// The token may still be valid for another authenticator (e.g., an OIDC token)
+	return nil, nil
+}
+
+// CertificateAuthenticator authenticates callers with verified client certificates.
+// The principal is the certificate's common name and its groups are the certificate's organizations.
+type CertificateAuthenticator struct{}
+
+func (a *CertificateAuthenticator) Authenticate(ctx context.Context, creds Credentials) (*Principal, error) {
+	if creds.Certificate == nil {
+		return nil, nil
+	}
+	if creds.Certificate.Subject.CommonName == "" {
+		return nil, fmt.Errorf("client certificate has no common name")
+	}
+	return &Principal{Name: creds.Certificate.Subject.CommonName, Groups: creds.Certificate.Subject.Organization, Method: "certificate"}, nil
+}
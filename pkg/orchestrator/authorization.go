@@ -0,0 +1,207 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/auth"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+)
+
+const principalContextKey = "principal"
+
+// Key of the name of the principal which sent a gRPC call in the call's context
+type principalKey struct{}
+
+// Action each route requires. Routes which aren't listed require admin rights on the whole controller.
+var routeActions = map[string]auth.Action{
+	http.MethodGet + " " + GetPermitListRulesURL:          auth.ReadAction,
+	http.MethodPost + " " + AddPermitListRulesURL:         auth.EditRulesAction,
+	http.MethodPost + " " + PermitListRulePOSTURL:         auth.EditRulesAction,
+	http.MethodPut + " " + PermitListRulePUTURL:           auth.EditRulesAction,
+	http.MethodPost + " " + DeletePermitListRulesURL:      auth.EditRulesAction,
+	http.MethodDelete + " " + PermitListRulePUTURL:        auth.EditRulesAction,
+	http.MethodPut + " " + CreateResourcePUTURL:           auth.AdminAction,
+	http.MethodPost + " " + CreateOrAttachResourcePOSTURL: auth.AdminAction,
+	http.MethodDelete + " " + DeleteResourceURL:           auth.AdminAction,
+	http.MethodPost + " " + DetachResourceURL:             auth.AdminAction,
+	http.MethodPost + " " + RuleOnTagURL:                  auth.EditRulesAction,
+	http.MethodDelete + " " + RuleOnTagURL:                auth.EditRulesAction,
+	http.MethodGet + " " + GetTagURL:                      auth.ReadAction,
+	http.MethodPost + " " + ResolveTagURL:                 auth.ReadAction,
+	http.MethodPost + " " + SetTagURL:                     auth.AdminAction,
+	http.MethodDelete + " " + DeleteTagURL:                auth.AdminAction,
+	http.MethodDelete + " " + DeleteTagMemberURL:          auth.AdminAction,
+	http.MethodPost + " " + ApplyManifestURL:              auth.AdminAction,
+	http.MethodGet + " " + DriftURL:                       auth.ReadAction,
+	http.MethodPost + " " + CheckDriftURL:                 auth.AdminAction,
+	http.MethodGet + " " + ListPluginsURL:                 auth.ReadAction,
+}
+
+// Routes any authenticated principal may call. Their handlers only return (or act on) what the principal may access.
+var authenticatedRoutes = map[string]bool{
+	http.MethodGet + " " + ListNamespacesURL:   true,
+	http.MethodGet + " " + ListTagURL:          true,
+	http.MethodGet + " " + ListOperationsURL:   true,
+	http.MethodGet + " " + GetOperationURL:     true,
+	http.MethodPost + " " + CancelOperationURL: true,
+}
+
+// Returns what a request operates on. Requests on a resource are scoped to both its namespace and its tag.
+func requestScope(c *gin.Context) auth.Scope {
+	scope := auth.Scope{Namespace: c.Param("namespace"), Tag: c.Param("tag")}
+	if scope.Namespace != "" && c.Param("cloud") != "" && c.Param("resourceName") != "" {
+		scope.Tag = getTagName(scope.Namespace, c.Param("cloud"), c.Param("resourceName"))
+	}
+	return scope
+}
+
+// Returns the bearer token and verified client certificate of a request
+func credentialsFromRequest(r *http.Request) auth.Credentials {
+	creds := auth.Credentials{}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		creds.BearerToken = strings.TrimSpace(token)
+	}
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		creds.Certificate = r.TLS.VerifiedChains[0][0]
+	}
+	return creds
+}
+
+// Authenticates every request and checks that the principal's role allows the route in the request's scope
+func (s *ControllerServer) authMiddleware(c *gin.Context) {
+	principal, err := s.auth.Authenticate(c, credentialsFromRequest(c.Request))
+	if err != nil {
+		c.Header("WWW-Authenticate", "Bearer")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, createErrorResponse(err.Error()))
+		return
+	}
+	c.Set(principalContextKey, principal)
+
+	route := c.Request.Method + " " + c.FullPath()
+	if authenticatedRoutes[route] {
+		c.Next()
+		return
+	}
+	action, ok := routeActions[route]
+	scope := requestScope(c)
+	if !ok {
+		action = auth.AdminAction
+		scope = auth.Scope{}
+	}
+	if !s.authorized(c, action, scope) {
+		c.AbortWithStatusJSON(http.StatusForbidden, createErrorResponse(fmt.Sprintf("%s is not allowed to %s %s", principal.Name, c.Request.Method, c.Request.URL.Path)))
+		return
+	}
+	c.Next()
+}
+
+// Returns the principal which sent the request (nil if authentication is disabled)
+func getPrincipal(c *gin.Context) *auth.Principal {
+	if principal, ok := c.Get(principalContextKey); ok {
+		return principal.(*auth.Principal)
+	}
+	return nil
+}
+
+// Returns true if the principal which sent the request may perform the action in the scope (always true if authentication is disabled)
+func (s *ControllerServer) authorized(c *gin.Context, action auth.Action, scope auth.Scope) bool {
+	if s.auth == nil {
+		return true
+	}
+	principal := getPrincipal(c)
+	return principal != nil && s.auth.Authorize(principal, action, scope)
+}
+
+// Returns true if the principal which sent the request may perform the action on an operation.
+// Principals may always access the operations they started.
+func (s *ControllerServer) authorizedForOperation(c *gin.Context, action auth.Action, op Operation) bool {
+	if principal := getPrincipal(c); principal != nil && principal.Name == op.Principal {
+		return true
+	}
+	return s.authorized(c, action, auth.Scope{})
+}
+
+// Authenticates calls to the Controller gRPC service. Since these calls allocate shared resources
+// (e.g., address spaces, ASNs) for any namespace, they require admin rights on the whole controller.
+func (s *ControllerServer) authUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	creds := auth.Credentials{}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, value := range md.Get("authorization") {
+			if token, ok := strings.CutPrefix(value, "Bearer "); ok {
+				creds.BearerToken = strings.TrimSpace(token)
+			}
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.VerifiedChains) > 0 {
+			creds.Certificate = tlsInfo.State.VerifiedChains[0][0]
+		}
+	}
+
+	principal, err := s.auth.Authenticate(ctx, creds)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if !s.auth.Authorize(principal, auth.AdminAction, auth.Scope{}) {
+		return nil, status.Errorf(codes.PermissionDenied, "%s is not allowed to call %s", principal.Name, info.FullMethod)
+	}
+	return handler(context.WithValue(ctx, principalKey{}, principal.Name), req)
+}
+
+// Creates the TLS config of the REST server (nil if it serves plain HTTP).
+// Client certificates are verified if provided, but callers may authenticate with a token instead.
+func newRestTLSConfig(cfg config.MTLS) (*tls.Config, error) {
+	if cfg.CertFile == "" && cfg.KeyFile == "" {
+		if cfg.ClientCAFile != "" {
+			return nil, fmt.Errorf("client certificates can only be verified if the server has a certificate and key")
+		}
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not load server certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if cfg.ClientCAFile != "" {
+		caPEM, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read client CAs: %w", err)
+		}
+		tlsConfig.ClientCAs = x509.NewCertPool()
+		if !tlsConfig.ClientCAs.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.ClientCAFile)
+		}
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return tlsConfig, nil
+}
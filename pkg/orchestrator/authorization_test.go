@@ -0,0 +1,179 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	fakeplugin "github.com/paraglider-project/paraglider/pkg/fake/cloudplugin"
+	faketagservice "github.com/paraglider-project/paraglider/pkg/fake/tagservice"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/auth"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+)
+
+func newAuthTestServer(t *testing.T) *ControllerServer {
+	orchestratorServer := newOrchestratorServer()
+	orchestratorServer.config.Namespaces = map[string][]config.CloudDeployment{
+		defaultNamespace: {{Name: exampleCloudName, Deployment: "deployment"}},
+		"other":          {{Name: exampleCloudName, Deployment: "other-deployment"}},
+	}
+	var err error
+	orchestratorServer.auth, err = auth.New(config.Auth{
+		Tokens: []config.AuthToken{
+			{Name: "admin", Token: "admin-token"},
+			{Name: "alice", Token: "editor-token", Groups: []string{"dev"}},
+			{Name: "bob", Token: "viewer-token"},
+		},
+		RoleBindings: []config.RoleBinding{
+			{Role: auth.AdminRole, Users: []string{"admin"}},
+			{Role: auth.RuleEditorRole, Groups: []string{"dev"}, Namespaces: []string{defaultNamespace}},
+			{Role: auth.ReadOnlyRole, Users: []string{"bob"}, Namespaces: []string{defaultNamespace}},
+		},
+	})
+	require.NoError(t, err)
+	return orchestratorServer
+}
+
+func sendAuthenticatedRequest(r http.Handler, method string, url string, token string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, url, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	orchestratorServer := newAuthTestServer(t)
+	port := getNewPortNumber()
+	tagServerPort := getNewPortNumber()
+	orchestratorServer.pluginAddresses[exampleCloudName] = fmt.Sprintf("localhost:%d", port)
+	orchestratorServer.localTagService = fmt.Sprintf("localhost:%d", tagServerPort)
+	fakeplugin.SetupFakePluginServer(port)
+	faketagservice.SetupFakeTagServer(tagServerPort)
+
+	r := SetUpRouter()
+	r.Use(orchestratorServer.authMiddleware)
+	r.GET(GetPermitListRulesURL, orchestratorServer.permitListGet)
+	r.DELETE(DeleteResourceURL, orchestratorServer.resourceDelete)
+	r.GET(ListNamespacesURL, orchestratorServer.listNamespaces)
+	r.POST(CreateNamespaceURL, orchestratorServer.createNamespace)
+	r.GET(DriftURL, orchestratorServer.getDriftReport)
+
+	permitListURL := func(namespace string) string {
+		return fmt.Sprintf(GetFormatterString(GetPermitListRulesURL), namespace, exampleCloudName, faketagservice.ValidLastLevelTagName)
+	}
+
+	// Unauthenticated
+	w := sendAuthenticatedRequest(r, "GET", permitListURL(defaultNamespace), "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	w = sendAuthenticatedRequest(r, "GET", permitListURL(defaultNamespace), "wrong-token")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Roles are scoped to namespaces
+	w = sendAuthenticatedRequest(r, "GET", permitListURL(defaultNamespace), "editor-token")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = sendAuthenticatedRequest(r, "GET", permitListURL("other"), "editor-token")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = sendAuthenticatedRequest(r, "GET", permitListURL(defaultNamespace), "viewer-token")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Rule editors can't delete resources
+	deleteURL := fmt.Sprintf(GetFormatterString(DeleteResourceURL), defaultNamespace, exampleCloudName, "resource")
+	w = sendAuthenticatedRequest(r, "DELETE", deleteURL, "editor-token")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Controller-wide routes need a role which isn't scoped
+	orchestratorServer.drift.setReport(&DriftReport{})
+	w = sendAuthenticatedRequest(r, "GET", DriftURL, "viewer-token")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = sendAuthenticatedRequest(r, "GET", DriftURL, "admin-token")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Only the namespaces the principal may read are listed
+	w = sendAuthenticatedRequest(r, "GET", ListNamespacesURL, "editor-token")
+	require.Equal(t, http.StatusOK, w.Code)
+	var namespaces map[string][]config.CloudDeployment
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &namespaces))
+	assert.Len(t, namespaces, 1)
+	assert.Contains(t, namespaces, defaultNamespace)
+
+	// Any principal may list namespaces, but only controller-wide admins may create them
+	w = sendAuthenticatedRequest(r, "POST", CreateNamespaceURL, "editor-token")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAuthOperations(t *testing.T) {
+	orchestratorServer := newAuthTestServer(t)
+	r := SetUpRouter()
+	r.Use(orchestratorServer.authMiddleware)
+	r.GET(ListOperationsURL, orchestratorServer.listOperations)
+	r.GET(GetOperationURL, orchestratorServer.getOperation)
+
+	op := orchestratorServer.operations.start(AddPermitListRulesOperation, "alice", func(ctx context.Context) (any, error) {
+		return nil, nil
+	})
+	opURL := fmt.Sprintf(GetFormatterString(GetOperationURL), op.Id)
+
+	// Principals can see the operations they started, and admins can see all of them
+	w := sendAuthenticatedRequest(r, "GET", opURL, "editor-token")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = sendAuthenticatedRequest(r, "GET", opURL, "admin-token")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = sendAuthenticatedRequest(r, "GET", opURL, "viewer-token")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = sendAuthenticatedRequest(r, "GET", ListOperationsURL, "viewer-token")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, "[]", w.Body.String())
+}
+
+func TestAuthUnaryInterceptor(t *testing.T) {
+	orchestratorServer := newAuthTestServer(t)
+	info := &grpc.UnaryServerInfo{FullMethod: "/paraglider.Controller/FindUnusedAsn"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return "ok", nil
+	}
+	withToken := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	}
+
+	resp, err := orchestratorServer.authUnaryInterceptor(withToken("admin-token"), nil, info, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+
+	_, err = orchestratorServer.authUnaryInterceptor(withToken("editor-token"), nil, info, handler)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = orchestratorServer.authUnaryInterceptor(context.Background(), nil, info, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
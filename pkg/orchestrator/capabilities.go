@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
+)
+
+const (
+	icmpProtocol   int32 = 1
+	icmpv6Protocol int32 = 58
+)
+
+// VPN modes in order of preference when connecting two clouds
+var vpnModePreference = []paragliderpb.VpnMode{paragliderpb.VpnMode_BGP, paragliderpb.VpnMode_STATIC}
+
+// Get the capabilities of a cloud's plugin, asking the plugin the first time they're needed
+func (s *ControllerServer) getCapabilities(ctx context.Context, cloud string) (*paragliderpb.GetCapabilitiesResponse, error) {
+	s.pluginsLock.RLock()
+	state, ok := s.plugins[cloud]
+	if ok && state.reportedCapabilities != nil {
+		capabilities := state.reportedCapabilities
+		s.pluginsLock.RUnlock()
+		return capabilities, nil
+	}
+	s.pluginsLock.RUnlock()
+
+	address, ok := s.getPluginAddress(cloud)
+	if !ok {
+		return nil, fmt.Errorf("invalid cloud name: %s", cloud)
+	}
+	conn, err := s.pluginConns.Get(address)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to cloud plugin: %w", err)
+	}
+
+	client := paragliderpb.NewCloudPluginClient(conn)
+	capabilities, err := client.GetCapabilities(ctx, &paragliderpb.GetCapabilitiesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get capabilities of cloud plugin %s: %w", cloud, err)
+	}
+
+	s.pluginsLock.Lock()
+	defer s.pluginsLock.Unlock()
+	s.getPluginState(cloud).reportedCapabilities = capabilities
+	return capabilities, nil
+}
+
+// Pick the VPN mode to connect two clouds with, preferring BGP when both clouds support it
+func negotiateVpnMode(capabilitiesA, capabilitiesB *paragliderpb.GetCapabilitiesResponse) (paragliderpb.VpnMode, bool) {
+	for _, mode := range vpnModePreference {
+		if slices.Contains(capabilitiesA.VpnModes, mode) && slices.Contains(capabilitiesB.VpnModes, mode) {
+			return mode, true
+		}
+	}
+	return paragliderpb.VpnMode_VPN_MODE_UNSPECIFIED, false
+}
+
+// Check a cloud can create resources, and resources of the requested type if one is given (e.g., "cluster").
+// The type of a resource is otherwise only known to the plugin, which tells it from the description.
+func checkResourceTypeSupported(cloud string, resourceType string, capabilities *paragliderpb.GetCapabilitiesResponse) error {
+	if len(capabilities.ResourceTypes) == 0 {
+		return fmt.Errorf("cloud %s does not support creating resources", cloud)
+	}
+	if resourceType == "" {
+		return nil
+	}
+	value, ok := paragliderpb.ResourceType_value[strings.ToUpper(resourceType)]
+	if !ok || paragliderpb.ResourceType(value) == paragliderpb.ResourceType_RESOURCE_TYPE_UNSPECIFIED {
+		return fmt.Errorf("invalid resource type: %s", resourceType)
+	}
+	if !slices.Contains(capabilities.ResourceTypes, paragliderpb.ResourceType(value)) {
+		return fmt.Errorf("cloud %s does not support creating %s resources", cloud, strings.ToLower(resourceType))
+	}
+	return nil
+}
+
+// Check a rule only uses features the cloud supports. Tags are checked before they're resolved, so only IP/CIDR tags are considered.
+func checkRuleSupported(cloud string, rule *paragliderpb.PermitListRule, capabilities *paragliderpb.GetCapabilitiesResponse) error {
+	features := capabilities.GetRuleFeatures()
+	if (rule.Protocol == icmpProtocol || rule.Protocol == icmpv6Protocol) && !features.GetIcmp() {
+		return fmt.Errorf("rule %s: cloud %s does not support ICMP rules", rule.Name, cloud)
+	}
+	if rule.Action == paragliderpb.RuleAction_DENY && !features.GetDenyRules() {
+		return fmt.Errorf("rule %s: cloud %s does not support deny rules", rule.Name, cloud)
+	}
+	for _, ranges := range [][]*paragliderpb.PortRange{utils.SrcPortRanges(rule), utils.DstPortRanges(rule)} {
+		if len(ranges) > 1 && !features.GetPortLists() {
+			return fmt.Errorf("rule %s: cloud %s does not support rules with several ports or port ranges", rule.Name, cloud)
+		}
+		if len(ranges) == 1 && ranges[0].Start != ranges[0].End && !features.GetPortRanges() {
+			return fmt.Errorf("rule %s: cloud %s does not support port ranges", rule.Name, cloud)
+		}
+	}
+	for _, tag := range rule.Tags {
+		if !isIpAddrOrCidr(tag) {
+			continue
+		}
+		if utils.IsIPv6(tag) && !features.GetIpv6() {
+			return fmt.Errorf("rule %s: cloud %s does not support IPv6 targets", rule.Name, cloud)
+		}
+	}
+	return nil
+}
+
+// Check rules to be added to a resource's permit list are supported by its cloud and stay within the cloud's rule limit
+func (s *ControllerServer) checkRulesSupported(ctx context.Context, resource *ResourceInfo, pluginAddress string, rules []*paragliderpb.PermitListRule) error {
+	capabilities, err := s.getCapabilities(ctx, resource.cloud)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if err := checkRuleSupported(resource.cloud, rule, capabilities); err != nil {
+			return err
+		}
+	}
+
+	maxRules := capabilities.GetLimits().GetMaxPermitListRules()
+	if maxRules <= 0 {
+		return nil
+	}
+	permitList, err := s._permitListGet(ctx, resource.namespace, resource.uri, pluginAddress)
+	if err != nil {
+		return err
+	}
+	// Rules with the name of an existing rule replace it rather than adding to the permit list
+	ruleNames := make(map[string]bool)
+	for _, rule := range permitList.Rules {
+		ruleNames[rule.Name] = true
+	}
+	for _, rule := range rules {
+		ruleNames[rule.Name] = true
+	}
+	if len(ruleNames) > int(maxRules) {
+		return fmt.Errorf("cloud %s allows at most %d permit list rules per resource", resource.cloud, maxRules)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	fakeplugin "github.com/paraglider-project/paraglider/pkg/fake/cloudplugin"
+	faketagservice "github.com/paraglider-project/paraglider/pkg/fake/tagservice"
+	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
+)
+
+func TestGetCapabilities(t *testing.T) {
+	orchestratorServer := newOrchestratorServer()
+	port := getNewPortNumber()
+	orchestratorServer.pluginAddresses[exampleCloudName] = fmt.Sprintf("localhost:%d", port)
+	fakeplugin.SetupFakePluginServer(port)
+
+	capabilities, err := orchestratorServer.getCapabilities(context.Background(), exampleCloudName)
+	require.NoError(t, err)
+	assert.Equal(t, fakeplugin.Capabilities.VpnModes, capabilities.VpnModes)
+	assert.Same(t, capabilities, orchestratorServer.plugins[exampleCloudName].reportedCapabilities)
+
+	// Registering again clears the cached capabilities
+	_, err = orchestratorServer.RegisterPlugin(context.Background(), &paragliderpb.RegisterPluginRequest{Name: exampleCloudName, Address: fmt.Sprintf("localhost:%d", port)})
+	require.NoError(t, err)
+	assert.Nil(t, orchestratorServer.plugins[exampleCloudName].reportedCapabilities)
+
+	_, err = orchestratorServer.getCapabilities(context.Background(), "missing")
+	assert.Error(t, err)
+}
+
+func TestNegotiateVpnMode(t *testing.T) {
+	bgp := &paragliderpb.GetCapabilitiesResponse{VpnModes: []paragliderpb.VpnMode{paragliderpb.VpnMode_BGP}}
+	static := &paragliderpb.GetCapabilitiesResponse{VpnModes: []paragliderpb.VpnMode{paragliderpb.VpnMode_STATIC}}
+	both := &paragliderpb.GetCapabilitiesResponse{VpnModes: []paragliderpb.VpnMode{paragliderpb.VpnMode_STATIC, paragliderpb.VpnMode_BGP}}
+
+	mode, ok := negotiateVpnMode(both, both)
+	assert.True(t, ok)
+	assert.Equal(t, paragliderpb.VpnMode_BGP, mode)
+
+	mode, ok = negotiateVpnMode(static, both)
+	assert.True(t, ok)
+	assert.Equal(t, paragliderpb.VpnMode_STATIC, mode)
+
+	_, ok = negotiateVpnMode(bgp, static)
+	assert.False(t, ok)
+}
+
+func TestCheckRuleSupported(t *testing.T) {
+	capabilities := &paragliderpb.GetCapabilitiesResponse{RuleFeatures: &paragliderpb.RuleFeatures{}}
+
+	assert.NoError(t, checkRuleSupported(exampleCloudName, &paragliderpb.PermitListRule{Name: "tcp", Protocol: 6, Tags: []string{"10.0.0.0/16", "tag"}}, capabilities))
+	assert.Error(t, checkRuleSupported(exampleCloudName, &paragliderpb.PermitListRule{Name: "icmp", Protocol: 1, Tags: []string{"10.0.0.0/16"}}, capabilities))
+	assert.Error(t, checkRuleSupported(exampleCloudName, &paragliderpb.PermitListRule{Name: "ipv6", Protocol: 6, Tags: []string{"2001:db8::/32"}}, capabilities))
+
+	denyRule := &paragliderpb.PermitListRule{Name: "deny", Protocol: 6, Tags: []string{"10.0.0.5"}, Action: paragliderpb.RuleAction_DENY}
+	assert.Error(t, checkRuleSupported(exampleCloudName, denyRule, capabilities))
+	// Priorities only matter to clouds with deny rules, so allow rules with a priority are always supported
+	assert.NoError(t, checkRuleSupported(exampleCloudName, &paragliderpb.PermitListRule{Name: "prioritized", Protocol: 6, Tags: []string{"10.0.0.0/8"}, Priority: 1}, capabilities))
+
+	capabilities.RuleFeatures = &paragliderpb.RuleFeatures{Icmp: true, Ipv6: true, DenyRules: true}
+	assert.NoError(t, checkRuleSupported(exampleCloudName, &paragliderpb.PermitListRule{Name: "icmpv6", Protocol: 58, Tags: []string{"2001:db8::1"}}, capabilities))
+	assert.NoError(t, checkRuleSupported(exampleCloudName, denyRule, capabilities))
+
+	portRange := &paragliderpb.PermitListRule{Name: "range", Protocol: 6, DstPorts: []*paragliderpb.PortRange{{Start: 8000, End: 8100}}}
+	portList := &paragliderpb.PermitListRule{Name: "list", Protocol: 6, DstPorts: []*paragliderpb.PortRange{{Start: 80, End: 80}, {Start: 443, End: 443}}}
+	assert.Error(t, checkRuleSupported(exampleCloudName, portRange, capabilities))
+	assert.Error(t, checkRuleSupported(exampleCloudName, portList, capabilities))
+
+	capabilities.RuleFeatures.PortRanges = true
+	assert.NoError(t, checkRuleSupported(exampleCloudName, portRange, capabilities))
+	assert.Error(t, checkRuleSupported(exampleCloudName, portList, capabilities))
+
+	capabilities.RuleFeatures.PortLists = true
+	assert.NoError(t, checkRuleSupported(exampleCloudName, portList, capabilities))
+	// Lists of a single port aren't port lists
+	assert.NoError(t, checkRuleSupported(exampleCloudName, &paragliderpb.PermitListRule{Name: "single", Protocol: 6, DstPorts: []*paragliderpb.PortRange{{Start: 80, End: 80}}}, &paragliderpb.GetCapabilitiesResponse{}))
+}
+
+func TestCheckResourceTypeSupported(t *testing.T) {
+	capabilities := &paragliderpb.GetCapabilitiesResponse{ResourceTypes: []paragliderpb.ResourceType{paragliderpb.ResourceType_INSTANCE, paragliderpb.ResourceType_PRIVATE_ENDPOINT}}
+
+	assert.NoError(t, checkResourceTypeSupported(exampleCloudName, "", capabilities))
+	assert.NoError(t, checkResourceTypeSupported(exampleCloudName, "instance", capabilities))
+	assert.NoError(t, checkResourceTypeSupported(exampleCloudName, "private_endpoint", capabilities))
+	assert.ErrorContains(t, checkResourceTypeSupported(exampleCloudName, "cluster", capabilities), "does not support creating cluster resources")
+	assert.ErrorContains(t, checkResourceTypeSupported(exampleCloudName, "resource_type_unspecified", capabilities), "invalid resource type")
+	assert.ErrorContains(t, checkResourceTypeSupported(exampleCloudName, "database", capabilities), "invalid resource type")
+
+	// Clouds which report no resource types can't create any
+	assert.ErrorContains(t, checkResourceTypeSupported(exampleCloudName, "", &paragliderpb.GetCapabilitiesResponse{}), "does not support creating resources")
+}
+
+func TestPermitListRuleLimit(t *testing.T) {
+	orchestratorServer := newOrchestratorServer()
+	port := getNewPortNumber()
+	orchestratorServer.pluginAddresses[exampleCloudName] = fmt.Sprintf("localhost:%d", port)
+	fakeplugin.SetupFakePluginServer(port)
+	orchestratorServer.getPluginState(exampleCloudName).reportedCapabilities = &paragliderpb.GetCapabilitiesResponse{
+		RuleFeatures: &paragliderpb.RuleFeatures{Icmp: true},
+		Limits:       &paragliderpb.CapabilityLimits{MaxPermitListRules: 1},
+	}
+
+	// The fake plugin's permit list already contains fakeplugin.ExampleRule
+	resource := &ResourceInfo{name: "resource", uri: "uri", cloud: exampleCloudName, namespace: defaultNamespace}
+	replaced := &paragliderpb.PermitListRule{Name: fakeplugin.ExampleRule.Name, Tags: []string{"1.2.3.4"}, Protocol: 6}
+	_, err := orchestratorServer._permitListRulesAdd(context.Background(), &paragliderpb.AddPermitListRulesRequest{Rules: []*paragliderpb.PermitListRule{replaced}}, resource, orchestratorServer.pluginAddresses[exampleCloudName])
+	assert.NoError(t, err)
+
+	added := &paragliderpb.PermitListRule{Name: "other-rule", Tags: []string{"1.2.3.4"}, Protocol: 6}
+	_, err = orchestratorServer._permitListRulesAdd(context.Background(), &paragliderpb.AddPermitListRulesRequest{Rules: []*paragliderpb.PermitListRule{added}}, resource, orchestratorServer.pluginAddresses[exampleCloudName])
+	assert.ErrorContains(t, err, "at most 1 permit list rules")
+}
+
+func TestAttachResourceUnsupported(t *testing.T) {
+	orchestratorServer := newOrchestratorServer()
+	orchestratorServer.pluginAddresses[exampleCloudName] = "localhost:0"
+	orchestratorServer.getPluginState(exampleCloudName).reportedCapabilities = &paragliderpb.GetCapabilitiesResponse{AttachResource: false}
+	tagServerPort := getNewPortNumber()
+	orchestratorServer.localTagService = fmt.Sprintf("localhost:%d", tagServerPort)
+
+	r := SetUpRouter()
+	r.POST(CreateOrAttachResourcePOSTURL, orchestratorServer.handleCreateOrAttachResource)
+
+	jsonValue, _ := json.Marshal(&ResourceID{Id: "id"})
+	url := fmt.Sprintf(GetFormatterString(CreateOrAttachResourcePOSTURL), defaultNamespace, exampleCloudName)
+	req, _ := http.NewRequest("POST", url, bytes.NewBuffer(jsonValue))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "does not support attaching resources")
+}
+
+func TestCreateResourceUnsupported(t *testing.T) {
+	orchestratorServer := newOrchestratorServer()
+	orchestratorServer.pluginAddresses[exampleCloudName] = "localhost:0"
+	orchestratorServer.getPluginState(exampleCloudName).reportedCapabilities = &paragliderpb.GetCapabilitiesResponse{ResourceTypes: []paragliderpb.ResourceType{paragliderpb.ResourceType_INSTANCE}}
+	tagServerPort := getNewPortNumber()
+	orchestratorServer.localTagService = fmt.Sprintf("localhost:%d", tagServerPort)
+	faketagservice.SetupFakeTagServer(tagServerPort)
+
+	r := SetUpRouter()
+	r.POST(CreateOrAttachResourcePOSTURL, orchestratorServer.handleCreateOrAttachResource)
+
+	jsonValue, _ := json.Marshal(map[string]string{"name": "new-resource", "description": "description", "type": "cluster"})
+	url := fmt.Sprintf(GetFormatterString(CreateOrAttachResourcePOSTURL), defaultNamespace, exampleCloudName)
+	req, _ := http.NewRequest("POST", url, bytes.NewBuffer(jsonValue))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "does not support creating cluster resources")
+}
@@ -21,21 +21,130 @@ type CloudDeployment struct {
 	Deployment string `yaml:"deployment"`
 }
 
+// TLS configures mutual TLS on the gRPC links of a service. The service presents its certificate both to
+// its clients and to the services it calls, and only accepts peers with certificates signed by the CAs.
+type TLS struct {
+	CertFile string `yaml:"certFile"`
+	KeyFile  string `yaml:"keyFile"`
+	CAFile   string `yaml:"caFile"`
+}
+
+// Enabled returns true if any TLS file is configured
+func (t TLS) Enabled() bool {
+	return t.CertFile != "" || t.KeyFile != "" || t.CAFile != ""
+}
+
 type CloudPlugin struct {
 	Name string `yaml:"name"`
 	Host string `yaml:"host"`
 	Port string `yaml:"port"`
+	TLS  TLS    `yaml:"tls"`
 }
 
 type Server struct {
 	Port    string `yaml:"port"`
 	Host    string `yaml:"host"`
 	RpcPort string `yaml:"rpcPort"`
+	TLS     TLS    `yaml:"tls"` // Used by the Controller gRPC service and when calling the other services
 }
 
 type TagService struct {
 	Port string `yaml:"port"`
 	Host string `yaml:"host"`
+	TLS  TLS    `yaml:"tls"`
+}
+
+type AllocationStore struct {
+	Type string `yaml:"type"` // "memory" (default), "file" or "kvstore"
+	Path string `yaml:"path"` // Only used by the file store
+}
+
+// Supported storage types of the tag service and KV store
+const (
+	RedisStorageType = "redis"
+	BoltStorageType  = "bolt"
+)
+
+type Storage struct {
+	Type string `yaml:"type"` // "redis" (default) or "bolt"
+	Path string `yaml:"path"` // Directory of the database files, only used by the bolt storage
+}
+
+type DriftDetection struct {
+	Interval  string `yaml:"interval"`  // How often to check for drift (e.g., "10m"), disabled if empty
+	Reconcile bool   `yaml:"reconcile"` // Whether to re-apply the expected rules when drift is found
+}
+
+// Supported trace exporters
+const (
+	OTLPTraceExporter   = "otlp"
+	StdoutTraceExporter = "stdout"
+)
+
+type Tracing struct {
+	Exporter    string  `yaml:"exporter"`    // "otlp", "stdout" or empty to only propagate traces without exporting spans
+	Endpoint    string  `yaml:"endpoint"`    // host:port of the OTLP gRPC collector (defaults to localhost:4317)
+	Insecure    bool    `yaml:"insecure"`    // Whether to connect to the collector without TLS
+	SampleRatio float64 `yaml:"sampleRatio"` // Fraction of new traces which are sampled (defaults to 1)
+}
+
+// Supported log formats
+const (
+	TextLogFormat = "text"
+	JSONLogFormat = "json"
+)
+
+// Log destinations other than files
+const (
+	StderrLogDestination = "stderr"
+	StdoutLogDestination = "stdout"
+)
+
+type Logging struct {
+	Format      string `yaml:"format"`      // "text" (default) or "json"
+	Destination string `yaml:"destination"` // "stderr" (default), "stdout" or the path of a file to append to
+	Level       string `yaml:"level"`       // "debug", "info" (default), "warn" or "error"
+}
+
+type AuthToken struct {
+	Name   string   `yaml:"name"`   // Name of the principal the token authenticates
+	Token  string   `yaml:"token"`  // Secret sent by clients as "Authorization: Bearer <token>"
+	Groups []string `yaml:"groups"` // Groups the principal belongs to
+}
+
+type OIDC struct {
+	Issuer        string `yaml:"issuer"`        // Issuer URL (its discovery document is used to find the signing keys)
+	Audience      string `yaml:"audience"`      // Expected "aud" claim (usually the client ID)
+	JwksURL       string `yaml:"jwksURL"`       // Overrides the JWKS URL of the discovery document
+	UsernameClaim string `yaml:"usernameClaim"` // Claim holding the principal name (defaults to "sub")
+	GroupsClaim   string `yaml:"groupsClaim"`   // Claim holding the principal groups (defaults to "groups")
+}
+
+type MTLS struct {
+	CertFile     string `yaml:"certFile"`     // Certificate the REST server presents
+	KeyFile      string `yaml:"keyFile"`      // Key of the certificate the REST server presents
+	ClientCAFile string `yaml:"clientCAFile"` // CAs which sign client certificates
+}
+
+type RoleBinding struct {
+	Role       string   `yaml:"role"`       // "read-only", "rule-editor" or "admin"
+	Users      []string `yaml:"users"`      // Principal names the role is granted to ("*" for any principal)
+	Groups     []string `yaml:"groups"`     // Groups the role is granted to
+	Namespaces []string `yaml:"namespaces"` // Namespaces the role applies to (all if empty)
+	Tags       []string `yaml:"tags"`       // Patterns of the tags the role applies to (all if empty)
+}
+
+type Auth struct {
+	Tokens        []AuthToken   `yaml:"tokens"`
+	OIDC          OIDC          `yaml:"oidc"`
+	MTLS          MTLS          `yaml:"mtls"`
+	RoleBindings  []RoleBinding `yaml:"roleBindings"`
+	ControllerRpc bool          `yaml:"controllerRpc"` // Whether callers of the Controller gRPC service must authenticate too
+}
+
+// Enabled returns true if any authentication method is configured
+func (a Auth) Enabled() bool {
+	return len(a.Tokens) > 0 || a.OIDC.Issuer != "" || a.MTLS.ClientCAFile != ""
 }
 
 type Config struct {
@@ -45,9 +154,18 @@ type Config struct {
 	KVStore struct {
 		Port string `yaml:"port"`
 		Host string `yaml:"host"`
+		TLS  TLS    `yaml:"tls"`
 	} `yaml:"kvStore"`
 
-	Namespaces   map[string][]CloudDeployment `yaml:"namespaces"`
-	AddressSpace []string                     `yaml:"addressSpace"`
-	CloudPlugins []CloudPlugin                `yaml:"cloudPlugins"`
+	AllocationStore AllocationStore `yaml:"allocationStore"`
+	Storage         Storage         `yaml:"storage"`
+	DriftDetection  DriftDetection  `yaml:"driftDetection"`
+	Auth            Auth            `yaml:"auth"`
+	Tracing         Tracing         `yaml:"tracing"`
+	Logging         Logging         `yaml:"logging"`
+
+	Namespaces       map[string][]CloudDeployment `yaml:"namespaces"`
+	AddressSpace     []string                     `yaml:"addressSpace"`
+	Ipv6AddressSpace []string                     `yaml:"ipv6AddressSpace"` // Unique local IPv6 address spaces of dual-stack networks (IPv4-only networks if empty)
+	CloudPlugins     []CloudPlugin                `yaml:"cloudPlugins"`
 }
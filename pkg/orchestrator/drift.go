@@ -0,0 +1,515 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/netip"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/store"
+	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
+	tagservicepb "github.com/paraglider-project/paraglider/pkg/tag_service/tagservicepb"
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
+)
+
+const (
+	DriftURL            string = "/drift"
+	CheckDriftURL       string = "/drift/check"
+	ReconcileQueryParam string = "reconcile" // Drift checks with reconcile=true re-apply the expected rules
+)
+
+// How a rule drifted
+const (
+	RuleDriftChanged    string = "changed"    // The rule in the cloud has other settings or targets than expected
+	RuleDriftMissing    string = "missing"    // The rule was deleted from the cloud
+	RuleDriftUnexpected string = "unexpected" // The rule in the cloud wasn't added through the controller (or was deleted through it)
+)
+
+// RuleDrift is a rule of a resource which differs from the one added through the controller (with its tags resolved)
+type RuleDrift struct {
+	Name            string   `json:"name"`
+	Status          string   `json:"status"`
+	ChangedSettings []string `json:"changedSettings,omitempty"` // Settings other than the targets which differ (e.g., protocol)
+	ExpectedTargets []string `json:"expectedTargets"`
+	ActualTargets   []string `json:"actualTargets"`
+}
+
+// ResourceDrift holds the drifted rules of a resource, or the error which kept it from being checked or reconciled
+type ResourceDrift struct {
+	Namespace  string      `json:"namespace"`
+	Cloud      string      `json:"cloud"`
+	Uri        string      `json:"uri"`
+	Rules      []RuleDrift `json:"rules,omitempty"`
+	Reconciled bool        `json:"reconciled,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// DriftReport is the outcome of comparing the permit lists in the clouds with the expected ones.
+// Only resources which drifted (or couldn't be checked) are listed.
+type DriftReport struct {
+	CheckedAt        time.Time       `json:"checkedAt"`
+	CheckedResources int             `json:"checkedResources"`
+	Resources        []ResourceDrift `json:"resources,omitempty"`
+	// Set when the drift is reconciled on request
+	RequestNotices
+}
+
+// driftDetector keeps the latest drift report and the rules expected on each resource
+type driftDetector struct {
+	checkLock     sync.Mutex // Only one check runs at a time
+	lock          sync.Mutex
+	report        *DriftReport
+	expectedLock  sync.Mutex // Guards expectedRules
+	expectedRules map[expectedRulesKey]*expectedRules
+}
+
+// The permit list rules added to a resource through the controller (without their targets, which are resolved from their tags)
+type expectedRules struct {
+	Namespace string            `json:"namespace"`
+	Cloud     string            `json:"cloud"`
+	Uri       string            `json:"uri"`
+	Rules     []json.RawMessage `json:"rules"`
+	rules     map[string]*paragliderpb.PermitListRule
+	value     string // How the rules are recorded in the allocation store
+}
+
+// Expected rules are tracked per resource, so a resource whose rules were all deleted is still expected to have none
+type expectedRulesKey struct {
+	namespace string
+	uri       string
+}
+
+func (e *expectedRules) key() expectedRulesKey {
+	return expectedRulesKey{namespace: e.Namespace, uri: e.Uri}
+}
+
+func (d *driftDetector) getReport() *DriftReport {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.report
+}
+
+func (d *driftDetector) setReport(report *DriftReport) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.report = report
+}
+
+// Load the expected rules from the allocation store
+func (s *ControllerServer) loadExpectedRules(ctx context.Context) error {
+	values, err := s.allocations.List(ctx, store.ExpectedRulesKey)
+	if err != nil {
+		return err
+	}
+
+	s.drift.expectedLock.Lock()
+	defer s.drift.expectedLock.Unlock()
+	s.drift.expectedRules = make(map[expectedRulesKey]*expectedRules)
+	for _, value := range values {
+		expected := &expectedRules{value: value, rules: make(map[string]*paragliderpb.PermitListRule)}
+		if err := json.Unmarshal([]byte(value), expected); err != nil {
+			return fmt.Errorf("invalid stored expected rules %s: %w", value, err)
+		}
+		for _, data := range expected.Rules {
+			rule := &paragliderpb.PermitListRule{}
+			if err := protojson.Unmarshal(data, rule); err != nil {
+				return fmt.Errorf("invalid stored expected rule %s: %w", string(data), err)
+			}
+			expected.rules[rule.Name] = rule
+		}
+		s.drift.expectedRules[expected.key()] = expected
+	}
+	return nil
+}
+
+// Record the expected rules of a resource in place of the ones recorded so far (forgetting the resource if expected is nil).
+// The caller must hold expectedLock.
+func (s *ControllerServer) storeExpectedRules(ctx context.Context, key expectedRulesKey, expected *expectedRules) error {
+	if s.drift.expectedRules == nil {
+		s.drift.expectedRules = make(map[expectedRulesKey]*expectedRules)
+	}
+	if previous, ok := s.drift.expectedRules[key]; ok {
+		if err := s.allocations.Remove(ctx, store.ExpectedRulesKey, previous.value); err != nil {
+			return err
+		}
+		delete(s.drift.expectedRules, key)
+	}
+	if expected == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(expected.rules))
+	for name := range expected.rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	expected.Rules = []json.RawMessage{}
+	for _, name := range names {
+		data, err := protojson.Marshal(expected.rules[name])
+		if err != nil {
+			return err
+		}
+		expected.Rules = append(expected.Rules, data)
+	}
+	value, err := json.Marshal(expected)
+	if err != nil {
+		return err
+	}
+	expected.value = string(value)
+	if err := s.allocations.Add(ctx, store.ExpectedRulesKey, expected.value); err != nil {
+		return err
+	}
+	s.drift.expectedRules[key] = expected
+	return nil
+}
+
+// Record the rules just added to a resource as expected, replacing the rules with the same names
+func (s *ControllerServer) recordExpectedRules(ctx context.Context, resource *ResourceInfo, rules []*paragliderpb.PermitListRule) error {
+	s.drift.expectedLock.Lock()
+	defer s.drift.expectedLock.Unlock()
+	key := expectedRulesKey{namespace: resource.namespace, uri: resource.uri}
+	expected := &expectedRules{Namespace: resource.namespace, Cloud: resource.cloud, Uri: resource.uri, rules: make(map[string]*paragliderpb.PermitListRule)}
+	if previous, ok := s.drift.expectedRules[key]; ok {
+		maps.Copy(expected.rules, previous.rules)
+	}
+	for _, rule := range rules {
+		rule = proto.Clone(rule).(*paragliderpb.PermitListRule)
+		rule.Targets = nil
+		expected.rules[rule.Name] = rule
+	}
+	return s.storeExpectedRules(ctx, key, expected)
+}
+
+// Forget the expected rules deleted from a resource (the resource altogether if ruleNames is nil)
+func (s *ControllerServer) forgetExpectedRules(ctx context.Context, resource *ResourceInfo, ruleNames []string) error {
+	s.drift.expectedLock.Lock()
+	defer s.drift.expectedLock.Unlock()
+	key := expectedRulesKey{namespace: resource.namespace, uri: resource.uri}
+	previous, ok := s.drift.expectedRules[key]
+	if !ok {
+		return nil
+	}
+	if ruleNames == nil {
+		return s.storeExpectedRules(ctx, key, nil)
+	}
+	if !slices.ContainsFunc(ruleNames, func(name string) bool { return previous.rules[name] != nil }) {
+		return nil
+	}
+	expected := &expectedRules{Namespace: previous.Namespace, Cloud: previous.Cloud, Uri: previous.Uri, rules: maps.Clone(previous.rules)}
+	for _, name := range ruleNames {
+		delete(expected.rules, name)
+	}
+	return s.storeExpectedRules(ctx, key, expected)
+}
+
+// Forget the expected rules of every resource in a namespace (e.g., once it is deleted)
+func (s *ControllerServer) forgetNamespaceExpectedRules(ctx context.Context, namespace string) error {
+	s.drift.expectedLock.Lock()
+	defer s.drift.expectedLock.Unlock()
+	for key := range s.drift.expectedRules {
+		if key.namespace != namespace {
+			continue
+		}
+		if err := s.storeExpectedRules(ctx, key, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get the rules expected on a resource, or false if none were recorded (e.g., its rules were added before the controller recorded them)
+func (s *ControllerServer) getExpectedRules(namespace string, uri string) ([]*paragliderpb.PermitListRule, bool) {
+	s.drift.expectedLock.Lock()
+	defer s.drift.expectedLock.Unlock()
+	expected, ok := s.drift.expectedRules[expectedRulesKey{namespace: namespace, uri: uri}]
+	if !ok {
+		return nil, false
+	}
+	rules := make([]*paragliderpb.PermitListRule, 0, len(expected.rules))
+	for _, rule := range expected.rules {
+		rules = append(rules, proto.Clone(rule).(*paragliderpb.PermitListRule))
+	}
+	slices.SortFunc(rules, func(a, b *paragliderpb.PermitListRule) int { return strings.Compare(a.Name, b.Name) })
+	return rules, true
+}
+
+// Lists the settings other than the targets which differ between two rules. Only the settings every cloud keeps are compared,
+// so that a rule compares equal to the one read back from the cloud.
+func diffRuleSettings(expected *paragliderpb.PermitListRule, actual *paragliderpb.PermitListRule) []string {
+	expected = proto.Clone(expected).(*paragliderpb.PermitListRule)
+	actual = proto.Clone(actual).(*paragliderpb.PermitListRule)
+	utils.NormalizePorts(expected)
+	utils.NormalizePorts(actual)
+
+	changed := []string{}
+	if expected.Direction != actual.Direction {
+		changed = append(changed, "direction")
+	}
+	if expected.Protocol != actual.Protocol {
+		changed = append(changed, "protocol")
+	}
+	if expected.DstPort != actual.DstPort || !slices.EqualFunc(expected.DstPorts, actual.DstPorts, func(a, b *paragliderpb.PortRange) bool { return proto.Equal(a, b) }) {
+		changed = append(changed, "dstPorts")
+	}
+	if expected.Action != actual.Action {
+		changed = append(changed, "action")
+	}
+	if expected.Priority != actual.Priority {
+		changed = append(changed, "priority")
+	}
+	return changed
+}
+
+// Normalizes targets to prefixes so that an IP and its /32 (or /128) compare equal
+func normalizeTargets(targets []string) []string {
+	normalized := []string{}
+	for _, target := range targets {
+		if prefix, err := netip.ParsePrefix(target); err == nil {
+			target = prefix.Masked().String()
+		} else if addr, err := netip.ParseAddr(target); err == nil {
+			target = netip.PrefixFrom(addr, addr.BitLen()).String()
+		}
+		if !slices.Contains(normalized, target) {
+			normalized = append(normalized, target)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// Lists every resource known to the tag service (resource tags and subscribers) as subscriber names
+func (s *ControllerServer) listTrackedResources(ctx context.Context) ([]string, error) {
+	conn, err := s.serviceConns.Get(s.localTagService)
+	if err != nil {
+		return nil, fmt.Errorf("could not contact tag server: %s", err.Error())
+	}
+
+	client := tagservicepb.NewTagServiceClient(conn)
+	listResp, err := client.ListTags(ctx, &tagservicepb.ListTagsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("could not list tags: %s", err.Error())
+	}
+
+	resources := make(map[string]bool)
+	for _, tag := range listResp.Tags {
+		if isTagValid(tag) && tag.GetUri() != "" {
+			if namespace, cloud, _, err := parseTag(tag.Name); err == nil {
+				resources[createSubscriberName(namespace, cloud, tag.GetUri())] = true
+			}
+		}
+
+		subResp, err := client.GetSubscribers(ctx, &tagservicepb.GetSubscribersRequest{TagName: tag.Name})
+		if err != nil {
+			return nil, fmt.Errorf("could not get subscribers of %s: %s", tag.Name, err.Error())
+		}
+		for _, subscriber := range subResp.Subscribers {
+			resources[subscriber] = true
+		}
+	}
+
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Lists the resources with expected rules as subscriber names
+func (s *ControllerServer) listExpectedResources() []string {
+	s.drift.expectedLock.Lock()
+	defer s.drift.expectedLock.Unlock()
+	names := []string{}
+	for _, expected := range s.drift.expectedRules {
+		names = append(names, createSubscriberName(expected.Namespace, expected.Cloud, expected.Uri))
+	}
+	return names
+}
+
+// Compares the permit list of a resource with what its rules resolve to and re-applies the drifted rules if reconcile is set
+func (s *ControllerServer) checkResourceDrift(ctx context.Context, namespace string, cloud string, uri string, reconcile bool) ResourceDrift {
+	drift := ResourceDrift{Namespace: namespace, Cloud: cloud, Uri: uri}
+	cloudClient, ok := s.getPluginAddress(cloud)
+	if !ok {
+		drift.Error = fmt.Sprintf("invalid cloud name: %s", cloud)
+		return drift
+	}
+
+	permitList, err := s._permitListGet(ctx, namespace, uri, cloudClient)
+	if err != nil {
+		drift.Error = fmt.Sprintf("could not get permit list: %s", err.Error())
+		return drift
+	}
+
+	actualRules := make(map[string]*paragliderpb.PermitListRule)
+	for _, rule := range permitList.Rules {
+		actualRules[rule.Name] = rule
+	}
+	expectedRules, ok := s.getExpectedRules(namespace, uri)
+	if !ok {
+		// Without a record of the rules added, the rules in the cloud are expected as they are (only their targets are checked)
+		for _, rule := range permitList.Rules {
+			expected := proto.Clone(rule).(*paragliderpb.PermitListRule)
+			expected.Targets = nil
+			expectedRules = append(expectedRules, expected)
+		}
+	}
+
+	resource := &ResourceInfo{namespace: namespace, cloud: cloud, uri: uri}
+	rulesToAdd := []*paragliderpb.PermitListRule{}
+	for _, expected := range expectedRules {
+		resolved, err := s.resolvePermitListRules(ctx, []*paragliderpb.PermitListRule{proto.Clone(expected).(*paragliderpb.PermitListRule)}, resource, false)
+		if err != nil {
+			drift.Error = fmt.Sprintf("could not resolve rule %s: %s", expected.Name, err.Error())
+			return drift
+		}
+
+		ruleDrift := RuleDrift{Name: expected.Name, Status: RuleDriftChanged, ExpectedTargets: normalizeTargets(resolved[0].Targets), ActualTargets: []string{}}
+		actual, ok := actualRules[expected.Name]
+		if ok {
+			ruleDrift.ActualTargets = normalizeTargets(actual.Targets)
+			ruleDrift.ChangedSettings = diffRuleSettings(expected, actual)
+			if len(ruleDrift.ChangedSettings) == 0 && slices.Equal(ruleDrift.ExpectedTargets, ruleDrift.ActualTargets) {
+				continue
+			}
+		} else {
+			ruleDrift.Status = RuleDriftMissing
+		}
+		drift.Rules = append(drift.Rules, ruleDrift)
+		rulesToAdd = append(rulesToAdd, expected)
+	}
+
+	rulesToDelete := []string{}
+	for _, rule := range permitList.Rules {
+		if !slices.ContainsFunc(expectedRules, func(expected *paragliderpb.PermitListRule) bool { return expected.Name == rule.Name }) {
+			drift.Rules = append(drift.Rules, RuleDrift{Name: rule.Name, Status: RuleDriftUnexpected, ExpectedTargets: []string{}, ActualTargets: normalizeTargets(rule.Targets)})
+			rulesToDelete = append(rulesToDelete, rule.Name)
+		}
+	}
+
+	if reconcile && len(drift.Rules) > 0 {
+		setOperationProgress(ctx, fmt.Sprintf("Reconciling %s", uri))
+		if len(rulesToDelete) > 0 {
+			if err := s._permitListRulesDelete(ctx, resource, cloudClient, rulesToDelete); err != nil {
+				drift.Error = fmt.Sprintf("could not reconcile: %s", err.Error())
+				return drift
+			}
+		}
+		if len(rulesToAdd) > 0 {
+			request := &paragliderpb.AddPermitListRulesRequest{Rules: clearRuleTargets(rulesToAdd), Namespace: namespace, Resource: uri}
+			if _, err := s._permitListRulesAdd(ctx, request, resource, cloudClient); err != nil {
+				drift.Error = fmt.Sprintf("could not reconcile: %s", err.Error())
+				return drift
+			}
+		}
+		drift.Reconciled = true
+	}
+	return drift
+}
+
+// Check every tracked resource for drift (re-applying the expected rules if reconcile is set) and record the report
+func (s *ControllerServer) checkDrift(ctx context.Context, reconcile bool) (*DriftReport, error) {
+	s.drift.checkLock.Lock()
+	defer s.drift.checkLock.Unlock()
+
+	resources, err := s.listTrackedResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, resource := range s.listExpectedResources() {
+		if !slices.Contains(resources, resource) {
+			resources = append(resources, resource)
+		}
+	}
+	sort.Strings(resources)
+
+	report := &DriftReport{CheckedAt: time.Now(), CheckedResources: len(resources)}
+	for _, resource := range resources {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		namespace, cloud, uri := parseSubscriberName(resource)
+		setOperationProgress(ctx, fmt.Sprintf("Checking %s", uri))
+		drift := s.checkResourceDrift(ctx, namespace, cloud, uri, reconcile)
+		if len(drift.Rules) > 0 || drift.Error != "" {
+			report.Resources = append(report.Resources, drift)
+		}
+	}
+
+	s.drift.setReport(report)
+	return report, nil
+}
+
+// Periodically check for drift until the server exits
+func (s *ControllerServer) runDriftDetection(interval time.Duration, reconcile bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		// Reconciling changes permit lists on behalf of no one in particular, which is audited as well
+		record := newAuditRecord(context.Background(), CheckDriftOperation, gin.H{ReconcileQueryParam: reconcile})
+		report, err := s.checkDrift(context.WithValue(context.Background(), auditRecordKey{}, record), reconcile)
+		if err != nil {
+			slog.Error("Drift detection failed", "error", err)
+		}
+		if len(record.event.Resources) > 0 {
+			s.recordAuditEvent(context.Background(), record.finish(report, err))
+		}
+	}
+}
+
+// Get the latest drift report (checking for drift first if no check ran yet)
+func (s *ControllerServer) getDriftReport(c *gin.Context) {
+	report := s.drift.getReport()
+	if report == nil {
+		var err error
+		report, err = s.checkDrift(c, false)
+		if err != nil {
+			c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
+			return
+		}
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// Check for drift now, re-applying the expected rules with reconcile=true
+func (s *ControllerServer) checkDriftNow(c *gin.Context) {
+	reconcile, _ := strconv.ParseBool(c.Query(ReconcileQueryParam))
+	s.runOperation(c, CheckDriftOperation, func(ctx context.Context) (any, error) {
+		report, err := s.checkDrift(ctx, reconcile)
+		if err != nil {
+			return nil, err
+		}
+		// Copied since the notices of this check are only returned to its caller, not kept with the latest report
+		requestReport := *report
+		return &requestReport, nil
+	})
+}
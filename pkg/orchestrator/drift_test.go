@@ -0,0 +1,178 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	fakeplugin "github.com/paraglider-project/paraglider/pkg/fake/cloudplugin"
+	faketagservice "github.com/paraglider-project/paraglider/pkg/fake/tagservice"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/store"
+	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
+)
+
+func TestNormalizeTargets(t *testing.T) {
+	assert.Equal(t, []string{"1.2.3.4/32", "10.0.0.0/8"}, normalizeTargets([]string{"1.2.3.4", "10.1.2.3/8", "1.2.3.4/32"}))
+	assert.Equal(t, []string{"2001:db8::1/128"}, normalizeTargets([]string{"2001:db8::1"}))
+	assert.Equal(t, []string{}, normalizeTargets(nil))
+}
+
+func TestCheckDrift(t *testing.T) {
+	// Setup
+	orchestratorServer := newOrchestratorServer()
+	port := getNewPortNumber()
+	tagServerPort := getNewPortNumber()
+	orchestratorServer.localTagService = fmt.Sprintf("localhost:%d", tagServerPort)
+	orchestratorServer.pluginAddresses[faketagservice.SubscriberCloudName] = fmt.Sprintf("localhost:%d", port)
+
+	fakeplugin.SetupFakePluginServer(port)
+	faketagservice.SetupFakeTagServer(tagServerPort)
+
+	// Without a record of the rules added, the fake plugin's rules are expected as they are but come without targets, so they all drifted
+	report, err := orchestratorServer.checkDrift(context.Background(), false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.CheckedResources)
+	require.Len(t, report.Resources, 1)
+	drift := report.Resources[0]
+	assert.Equal(t, faketagservice.SubscriberNamespace, drift.Namespace)
+	assert.Equal(t, faketagservice.TagUri, drift.Uri)
+	assert.Empty(t, drift.Error)
+	assert.False(t, drift.Reconciled)
+	require.Len(t, drift.Rules, 1)
+	assert.Equal(t, fakeplugin.ExampleRule.Name, drift.Rules[0].Name)
+	assert.Equal(t, RuleDriftChanged, drift.Rules[0].Status)
+	assert.Empty(t, drift.Rules[0].ChangedSettings)
+	assert.Equal(t, []string{faketagservice.ResolvedTagIp + "/32"}, drift.Rules[0].ExpectedTargets)
+	assert.Empty(t, drift.Rules[0].ActualTargets)
+
+	r := SetUpRouter()
+	r.GET(DriftURL, orchestratorServer.getDriftReport)
+	r.POST(CheckDriftURL, orchestratorServer.checkDriftNow)
+
+	// The latest report is returned
+	req, _ := http.NewRequest("GET", DriftURL, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var latest DriftReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &latest))
+	assert.True(t, report.CheckedAt.Equal(latest.CheckedAt))
+
+	// Reconcile
+	req, _ = http.NewRequest("POST", CheckDriftURL+"?"+ReconcileQueryParam+"=true", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &latest))
+	require.Len(t, latest.Resources, 1)
+	assert.True(t, latest.Resources[0].Reconciled)
+	assert.Empty(t, latest.Resources[0].Error)
+
+	// Resources in clouds without a plugin can't be checked
+	delete(orchestratorServer.pluginAddresses, faketagservice.SubscriberCloudName)
+	report, err = orchestratorServer.checkDrift(context.Background(), false)
+	require.NoError(t, err)
+	require.Len(t, report.Resources, 1)
+	assert.NotEmpty(t, report.Resources[0].Error)
+}
+
+func TestCheckDriftExpectedRules(t *testing.T) {
+	// Setup
+	orchestratorServer := newOrchestratorServer()
+	port := getNewPortNumber()
+	tagServerPort := getNewPortNumber()
+	orchestratorServer.localTagService = fmt.Sprintf("localhost:%d", tagServerPort)
+	orchestratorServer.pluginAddresses[faketagservice.SubscriberCloudName] = fmt.Sprintf("localhost:%d", port)
+
+	fakeplugin.SetupFakePluginServer(port)
+	faketagservice.SetupFakeTagServer(tagServerPort)
+
+	// The rule in the cloud has another destination port than the one added, and another rule is missing from the cloud
+	ctx := context.Background()
+	resource := &ResourceInfo{namespace: faketagservice.SubscriberNamespace, cloud: faketagservice.SubscriberCloudName, uri: faketagservice.TagUri}
+	changedRule := proto.Clone(fakeplugin.ExampleRule).(*paragliderpb.PermitListRule)
+	changedRule.DstPort = 2
+	missingRule := &paragliderpb.PermitListRule{Name: "missing-rule", Tags: []string{"1.2.3.4"}, Direction: paragliderpb.Direction_OUTBOUND, SrcPort: -1, DstPort: 443, Protocol: 6}
+	require.NoError(t, orchestratorServer.recordExpectedRules(ctx, resource, []*paragliderpb.PermitListRule{changedRule, missingRule}))
+
+	report, err := orchestratorServer.checkDrift(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, report.Resources, 1)
+	drift := report.Resources[0]
+	require.Len(t, drift.Rules, 2)
+	assert.Equal(t, fakeplugin.ExampleRule.Name, drift.Rules[0].Name)
+	assert.Equal(t, RuleDriftChanged, drift.Rules[0].Status)
+	assert.Equal(t, []string{"dstPorts"}, drift.Rules[0].ChangedSettings)
+	assert.Equal(t, missingRule.Name, drift.Rules[1].Name)
+	assert.Equal(t, RuleDriftMissing, drift.Rules[1].Status)
+	assert.Equal(t, []string{"1.2.3.4/32"}, drift.Rules[1].ExpectedTargets)
+
+	// Rules deleted through the controller but still in the cloud are unexpected
+	require.NoError(t, orchestratorServer.forgetExpectedRules(ctx, resource, []string{fakeplugin.ExampleRule.Name}))
+	report, err = orchestratorServer.checkDrift(ctx, true)
+	require.NoError(t, err)
+	require.Len(t, report.Resources, 1)
+	drift = report.Resources[0]
+	require.Len(t, drift.Rules, 2)
+	assert.Equal(t, missingRule.Name, drift.Rules[0].Name)
+	assert.Equal(t, RuleDriftMissing, drift.Rules[0].Status)
+	assert.Equal(t, fakeplugin.ExampleRule.Name, drift.Rules[1].Name)
+	assert.Equal(t, RuleDriftUnexpected, drift.Rules[1].Status)
+	assert.True(t, drift.Reconciled)
+	assert.Empty(t, drift.Error)
+
+	// Reconciling keeps the expected rules, which are loaded from the store on startup
+	restartedServer := newOrchestratorServer()
+	restartedServer.allocations = orchestratorServer.allocations
+	require.NoError(t, restartedServer.loadExpectedRules(ctx))
+	expected, ok := restartedServer.getExpectedRules(resource.namespace, resource.uri)
+	require.True(t, ok)
+	require.Len(t, expected, 1)
+	assert.Equal(t, missingRule.Name, expected[0].Name)
+	assert.Equal(t, int32(443), expected[0].DstPort)
+	assert.Empty(t, expected[0].Targets)
+
+	// Deleting the resource forgets its expected rules
+	require.NoError(t, restartedServer.forgetExpectedRules(ctx, resource, nil))
+	_, ok = restartedServer.getExpectedRules(resource.namespace, resource.uri)
+	assert.False(t, ok)
+	stored, err := restartedServer.allocations.List(ctx, store.ExpectedRulesKey)
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+}
+
+func TestDiffRuleSettings(t *testing.T) {
+	rule := &paragliderpb.PermitListRule{Name: "rule", Direction: paragliderpb.Direction_INBOUND, SrcPort: 1234, DstPort: 80, Protocol: 6, ExpiresAt: 1000}
+
+	// Settings the clouds don't keep (e.g., source ports on GCP) are ignored
+	actual := &paragliderpb.PermitListRule{Name: "rule", Direction: paragliderpb.Direction_INBOUND, SrcPort: -1, DstPorts: []*paragliderpb.PortRange{{Start: 80, End: 80}}, Protocol: 6, Targets: []string{"1.2.3.4"}}
+	assert.Empty(t, diffRuleSettings(rule, actual))
+
+	actual = &paragliderpb.PermitListRule{Name: "rule", Direction: paragliderpb.Direction_OUTBOUND, DstPort: 81, Protocol: 17, Action: paragliderpb.RuleAction_DENY, Priority: 1}
+	assert.Equal(t, []string{"direction", "protocol", "dstPorts", "action", "priority"}, diffRuleSettings(rule, actual))
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/store"
+	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
+)
+
+const (
+	ExpirePermitListRuleAction string        = "ExpirePermitListRule" // Audit action of the deletion of an expired rule
+	ruleExpirationInterval     time.Duration = 30 * time.Second       // How often expired rules are looked for
+)
+
+// A permit list rule which is deleted once it expires
+type ruleExpiration struct {
+	Namespace string `json:"namespace"`
+	Cloud     string `json:"cloud"`
+	Resource  string `json:"resource,omitempty"` // Paraglider name of the resource (if known)
+	Uri       string `json:"uri"`
+	Rule      string `json:"rule"`
+	ExpiresAt int64  `json:"expiresAt"`
+	value     string // How the expiration is recorded in the allocation store
+}
+
+// Rule expirations are tracked per resource, so rules added to a tag get one for each resource within it
+type ruleExpirationKey struct {
+	namespace string
+	uri       string
+	rule      string
+}
+
+func (e *ruleExpiration) key() ruleExpirationKey {
+	return ruleExpirationKey{namespace: e.Namespace, uri: e.Uri, rule: e.Rule}
+}
+
+func (e *ruleExpiration) resourceInfo() *ResourceInfo {
+	return &ResourceInfo{name: e.Resource, uri: e.Uri, cloud: e.Cloud, namespace: e.Namespace}
+}
+
+// Turn the TTL of a rule into the time at which it expires
+func setRuleExpiration(rule *paragliderpb.PermitListRule, now time.Time) error {
+	if rule.ExpiresAt < 0 || rule.TtlSeconds < 0 {
+		return fmt.Errorf("rule %s has a negative expiration time or TTL", rule.Name)
+	}
+	if rule.TtlSeconds == 0 {
+		return nil
+	}
+	if rule.ExpiresAt != 0 {
+		return fmt.Errorf("rule %s sets both an expiration time and a TTL", rule.Name)
+	}
+	rule.ExpiresAt = now.Add(time.Duration(rule.TtlSeconds) * time.Second).Unix()
+	rule.TtlSeconds = 0
+	return nil
+}
+
+// Load the rule expirations from the allocation store
+func (s *ControllerServer) loadRuleExpirations(ctx context.Context) error {
+	values, err := s.allocations.List(ctx, store.RuleExpirationsKey)
+	if err != nil {
+		return err
+	}
+
+	s.ruleExpirationsLock.Lock()
+	defer s.ruleExpirationsLock.Unlock()
+	s.ruleExpirations = make(map[ruleExpirationKey]*ruleExpiration)
+	for _, value := range values {
+		expiration := &ruleExpiration{value: value}
+		if err := json.Unmarshal([]byte(value), expiration); err != nil {
+			return fmt.Errorf("invalid stored rule expiration %s: %w", value, err)
+		}
+		s.ruleExpirations[expiration.key()] = expiration
+	}
+	return nil
+}
+
+// Record when the rules just added to a resource expire, forgetting the expirations of rules replaced by ones which don't expire
+func (s *ControllerServer) recordRuleExpirations(ctx context.Context, resource *ResourceInfo, rules []*paragliderpb.PermitListRule) error {
+	s.ruleExpirationsLock.Lock()
+	defer s.ruleExpirationsLock.Unlock()
+	for _, rule := range rules {
+		expiration := &ruleExpiration{Namespace: resource.namespace, Cloud: resource.cloud, Resource: resource.name, Uri: resource.uri, Rule: rule.Name, ExpiresAt: rule.ExpiresAt}
+		if previous, ok := s.ruleExpirations[expiration.key()]; ok {
+			if previous.ExpiresAt == rule.ExpiresAt {
+				continue
+			}
+			if err := s.allocations.Remove(ctx, store.RuleExpirationsKey, previous.value); err != nil {
+				return err
+			}
+			delete(s.ruleExpirations, expiration.key())
+		}
+		if rule.ExpiresAt == 0 {
+			continue
+		}
+
+		value, err := json.Marshal(expiration)
+		if err != nil {
+			return err
+		}
+		expiration.value = string(value)
+		if err := s.allocations.Add(ctx, store.RuleExpirationsKey, expiration.value); err != nil {
+			return err
+		}
+		s.ruleExpirations[expiration.key()] = expiration
+		if !s.isAllocationStorePersistent() {
+			slog.WarnContext(ctx, "Rule expiration is lost when the controller restarts since the allocation store is kept in memory", "resource", resource.uri, "rule", rule.Name)
+			utils.AddWarning(ctx, resource.uri, fmt.Sprintf("rule %s is not deleted when it expires if the controller restarts before then since the allocation store is kept in memory (set allocationStore.type to file or kvstore)", rule.Name))
+		}
+	}
+	return nil
+}
+
+// Forget the expirations of rules deleted from a resource (every rule of the resource if ruleNames is nil)
+func (s *ControllerServer) forgetRuleExpirations(ctx context.Context, resource *ResourceInfo, ruleNames []string) error {
+	s.ruleExpirationsLock.Lock()
+	defer s.ruleExpirationsLock.Unlock()
+	for key, expiration := range s.ruleExpirations {
+		if key.namespace != resource.namespace || key.uri != resource.uri {
+			continue
+		}
+		if ruleNames != nil && !slices.Contains(ruleNames, key.rule) {
+			continue
+		}
+		if err := s.allocations.Remove(ctx, store.RuleExpirationsKey, expiration.value); err != nil {
+			return err
+		}
+		delete(s.ruleExpirations, key)
+	}
+	return nil
+}
+
+// Forget the expirations of every rule in a namespace (e.g., once it is deleted)
+func (s *ControllerServer) forgetNamespaceRuleExpirations(ctx context.Context, namespace string) error {
+	s.ruleExpirationsLock.Lock()
+	defer s.ruleExpirationsLock.Unlock()
+	for key, expiration := range s.ruleExpirations {
+		if key.namespace != namespace {
+			continue
+		}
+		if err := s.allocations.Remove(ctx, store.RuleExpirationsKey, expiration.value); err != nil {
+			return err
+		}
+		delete(s.ruleExpirations, key)
+	}
+	return nil
+}
+
+// Fill in when the rules of a resource expire, which the plugins don't keep track of
+func (s *ControllerServer) setRuleExpirationTimes(namespace string, uri string, rules []*paragliderpb.PermitListRule) {
+	s.ruleExpirationsLock.Lock()
+	defer s.ruleExpirationsLock.Unlock()
+	for _, rule := range rules {
+		if expiration, ok := s.ruleExpirations[ruleExpirationKey{namespace: namespace, uri: uri, rule: rule.Name}]; ok {
+			rule.ExpiresAt = expiration.ExpiresAt
+		}
+	}
+}
+
+// Get the rules which expired by now
+func (s *ControllerServer) getExpiredRules(now time.Time) []ruleExpiration {
+	s.ruleExpirationsLock.Lock()
+	defer s.ruleExpirationsLock.Unlock()
+	expired := []ruleExpiration{}
+	for _, expiration := range s.ruleExpirations {
+		if expiration.ExpiresAt <= now.Unix() {
+			expired = append(expired, *expiration)
+		}
+	}
+	return expired
+}
+
+// Delete the rules which expired by now. Rules which fail to be deleted are tried again the next time, and are only audited once they are gone.
+func (s *ControllerServer) expireRules(ctx context.Context, now time.Time) {
+	for _, expiration := range s.getExpiredRules(now) {
+		cloudClient, ok := s.getPluginAddress(expiration.Cloud)
+		if !ok {
+			slog.Error("Invalid cloud name for expired rule", "cloud", expiration.Cloud, "rule", expiration.Rule)
+			continue
+		}
+
+		// Expired rules are deleted on behalf of no one in particular, which is audited as well
+		record := newAuditRecord(ctx, ExpirePermitListRuleAction, expiration)
+		resource := expiration.resourceInfo()
+		err := s._permitListRulesDelete(context.WithValue(ctx, auditRecordKey{}, record), resource, cloudClient, []string{expiration.Rule})
+		if status.Code(err) == codes.NotFound {
+			// The resource (or rule) is already gone, so there is nothing left to delete
+			slog.Info("Expired permit list rule no longer exists", "rule", expiration.Rule, "resource", expiration.Uri, "error", err)
+			if err := s.forgetRuleExpirations(ctx, resource, []string{expiration.Rule}); err != nil {
+				slog.Error("Failed to forget expired permit list rule", "rule", expiration.Rule, "resource", expiration.Uri, "error", err)
+				continue
+			}
+		} else if err != nil {
+			slog.Error("Failed to delete expired permit list rule", "rule", expiration.Rule, "resource", expiration.Uri, "error", err)
+			continue
+		} else {
+			slog.Info("Deleted expired permit list rule", "rule", expiration.Rule, "resource", expiration.Uri)
+		}
+		s.recordAuditEvent(ctx, record.finish(nil, nil))
+	}
+}
+
+// Periodically delete the expired rules
+func (s *ControllerServer) runRuleExpirations(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.expireRules(context.Background(), time.Now())
+	}
+}
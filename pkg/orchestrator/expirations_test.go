@@ -0,0 +1,209 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	fakeplugin "github.com/paraglider-project/paraglider/pkg/fake/cloudplugin"
+	faketagservice "github.com/paraglider-project/paraglider/pkg/fake/tagservice"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/store"
+	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
+)
+
+func TestSetRuleExpiration(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	rule := &paragliderpb.PermitListRule{Name: "rule", TtlSeconds: 7200}
+	require.NoError(t, setRuleExpiration(rule, now))
+	assert.Equal(t, int64(8200), rule.ExpiresAt)
+	assert.Zero(t, rule.TtlSeconds)
+
+	rule = &paragliderpb.PermitListRule{Name: "rule", ExpiresAt: 2000}
+	require.NoError(t, setRuleExpiration(rule, now))
+	assert.Equal(t, int64(2000), rule.ExpiresAt)
+
+	rule = &paragliderpb.PermitListRule{Name: "rule"}
+	require.NoError(t, setRuleExpiration(rule, now))
+	assert.Zero(t, rule.ExpiresAt)
+
+	assert.Error(t, setRuleExpiration(&paragliderpb.PermitListRule{Name: "rule", ExpiresAt: 2000, TtlSeconds: 60}, now))
+	assert.Error(t, setRuleExpiration(&paragliderpb.PermitListRule{Name: "rule", TtlSeconds: -1}, now))
+	assert.Error(t, setRuleExpiration(&paragliderpb.PermitListRule{Name: "rule", ExpiresAt: -1}, now))
+}
+
+func TestRuleExpirations(t *testing.T) {
+	// Setup
+	orchestratorServer := newOrchestratorServer()
+	tagServerPort := getNewPortNumber()
+	cloudPluginPort := getNewPortNumber()
+	orchestratorServer.pluginAddresses[exampleCloudName] = fmt.Sprintf("localhost:%d", cloudPluginPort)
+	orchestratorServer.localTagService = fmt.Sprintf("localhost:%d", tagServerPort)
+
+	fakeplugin.SetupFakePluginServer(cloudPluginPort)
+	faketagservice.SetupFakeTagServer(tagServerPort)
+
+	ctx := context.Background()
+	resource := &ResourceInfo{name: faketagservice.ValidLastLevelTagName, uri: faketagservice.TagUri, cloud: exampleCloudName, namespace: defaultNamespace}
+	cloudClient := orchestratorServer.pluginAddresses[exampleCloudName]
+
+	// Add a rule which expires in an hour
+	rule := proto.Clone(fakeplugin.ExampleRule).(*paragliderpb.PermitListRule)
+	rule.TtlSeconds = 3600
+	request := &paragliderpb.AddPermitListRulesRequest{Rules: []*paragliderpb.PermitListRule{rule}, Namespace: resource.namespace, Resource: resource.uri}
+	noticesCtx, notices := utils.WithNotices(ctx)
+	_, err := orchestratorServer._permitListRulesAdd(noticesCtx, request, resource, cloudClient)
+	require.NoError(t, err)
+	// The allocation store is kept in memory, so the expiration wouldn't survive a restart
+	require.Len(t, notices.Warnings(), 1)
+	assert.Contains(t, notices.Warnings()[0].Message, rule.Name)
+
+	stored, err := orchestratorServer.allocations.List(ctx, store.RuleExpirationsKey)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+
+	// The expiration time is reported with the permit list
+	permitList, err := orchestratorServer._permitListGet(ctx, resource.namespace, resource.uri, cloudClient)
+	require.NoError(t, err)
+	require.Len(t, permitList.Rules, 1)
+	expiresAt := permitList.Rules[0].ExpiresAt
+	assert.InDelta(t, time.Now().Add(time.Hour).Unix(), expiresAt, 5)
+
+	// Re-adding the rule as it is reported (e.g., when reconciling drift) keeps its expiration
+	_, err = orchestratorServer._permitListRulesAdd(ctx, &paragliderpb.AddPermitListRulesRequest{Rules: permitList.Rules, Namespace: resource.namespace, Resource: resource.uri}, resource, cloudClient)
+	require.NoError(t, err)
+	stored, err = orchestratorServer.allocations.List(ctx, store.RuleExpirationsKey)
+	require.NoError(t, err)
+	assert.Len(t, stored, 1)
+
+	// Expirations are loaded from the store on startup
+	restartedServer := newOrchestratorServer()
+	restartedServer.pluginAddresses = orchestratorServer.pluginAddresses
+	restartedServer.localTagService = orchestratorServer.localTagService
+	restartedServer.allocations = orchestratorServer.allocations
+	require.NoError(t, restartedServer.loadRuleExpirations(ctx))
+	require.Len(t, restartedServer.ruleExpirations, 1)
+
+	// Nothing is deleted before the rule expires
+	restartedServer.expireRules(ctx, time.Unix(expiresAt-1, 0))
+	assert.Len(t, restartedServer.ruleExpirations, 1)
+
+	// The rule is deleted once it expires, which is audited
+	restartedServer.expireRules(ctx, time.Unix(expiresAt, 0))
+	assert.Empty(t, restartedServer.ruleExpirations)
+	stored, err = restartedServer.allocations.List(ctx, store.RuleExpirationsKey)
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+	events, err := restartedServer.allocations.Log(ctx, store.AuditEventsKey)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Contains(t, events[0], ExpirePermitListRuleAction)
+
+	// Re-adding an expiring rule without an expiration time keeps it forever
+	rule = proto.Clone(fakeplugin.ExampleRule).(*paragliderpb.PermitListRule)
+	rule.ExpiresAt = time.Now().Add(time.Hour).Unix()
+	_, err = orchestratorServer._permitListRulesAdd(ctx, &paragliderpb.AddPermitListRulesRequest{Rules: []*paragliderpb.PermitListRule{rule}}, resource, cloudClient)
+	require.NoError(t, err)
+	require.Len(t, orchestratorServer.ruleExpirations, 1)
+	rule = proto.Clone(fakeplugin.ExampleRule).(*paragliderpb.PermitListRule)
+	_, err = orchestratorServer._permitListRulesAdd(ctx, &paragliderpb.AddPermitListRulesRequest{Rules: []*paragliderpb.PermitListRule{rule}}, resource, cloudClient)
+	require.NoError(t, err)
+	assert.Empty(t, orchestratorServer.ruleExpirations)
+
+	// Invalid expirations are rejected
+	rule = proto.Clone(fakeplugin.ExampleRule).(*paragliderpb.PermitListRule)
+	rule.TtlSeconds = -1
+	_, err = orchestratorServer._permitListRulesAdd(ctx, &paragliderpb.AddPermitListRulesRequest{Rules: []*paragliderpb.PermitListRule{rule}}, resource, cloudClient)
+	assert.Error(t, err)
+}
+
+func TestExpireRulesFailures(t *testing.T) {
+	// Setup
+	orchestratorServer := newOrchestratorServer()
+	tagServerPort := getNewPortNumber()
+	cloudPluginPort := getNewPortNumber()
+	orchestratorServer.pluginAddresses[exampleCloudName] = fmt.Sprintf("localhost:%d", cloudPluginPort)
+	orchestratorServer.pluginAddresses["unreachable"] = fmt.Sprintf("localhost:%d", getNewPortNumber())
+	orchestratorServer.localTagService = fmt.Sprintf("localhost:%d", tagServerPort)
+
+	fakeplugin.SetupFakePluginServer(cloudPluginPort)
+	faketagservice.SetupFakeTagServer(tagServerPort)
+
+	ctx := context.Background()
+	rule := proto.Clone(fakeplugin.ExampleRule).(*paragliderpb.PermitListRule)
+	rule.ExpiresAt = 1000
+	missing := &ResourceInfo{name: "missing", uri: fakeplugin.MissingResourceUri, cloud: exampleCloudName, namespace: defaultNamespace}
+	unreachable := &ResourceInfo{name: "unreachable", uri: "unreachable-resource", cloud: "unreachable", namespace: defaultNamespace}
+	require.NoError(t, orchestratorServer.recordRuleExpirations(ctx, missing, []*paragliderpb.PermitListRule{rule}))
+	require.NoError(t, orchestratorServer.recordRuleExpirations(ctx, unreachable, []*paragliderpb.PermitListRule{rule}))
+
+	// Rules of resources which no longer exist are forgotten, while the others are tried again without being audited each time
+	for i := 0; i < 2; i++ {
+		orchestratorServer.expireRules(ctx, time.Unix(1000, 0))
+	}
+	require.Len(t, orchestratorServer.ruleExpirations, 1)
+	assert.Contains(t, orchestratorServer.ruleExpirations, ruleExpirationKey{namespace: defaultNamespace, uri: unreachable.uri, rule: rule.Name})
+	stored, err := orchestratorServer.allocations.List(ctx, store.RuleExpirationsKey)
+	require.NoError(t, err)
+	assert.Len(t, stored, 1)
+	events, err := orchestratorServer.allocations.Log(ctx, store.AuditEventsKey)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Contains(t, events[0], fakeplugin.MissingResourceUri)
+}
+
+func TestTagRuleExpirations(t *testing.T) {
+	// Setup
+	orchestratorServer := newOrchestratorServer()
+	tagServerPort := getNewPortNumber()
+	cloudPluginPort := getNewPortNumber()
+	orchestratorServer.pluginAddresses[exampleCloudName] = fmt.Sprintf("localhost:%d", cloudPluginPort)
+	orchestratorServer.localTagService = fmt.Sprintf("localhost:%d", tagServerPort)
+
+	fakeplugin.SetupFakePluginServer(cloudPluginPort)
+	faketagservice.SetupFakeTagServer(tagServerPort)
+
+	// Rules added to a tag expire on every resource within it
+	ctx := context.Background()
+	tag := getTagName(defaultNamespace, exampleCloudName, faketagservice.ValidTagName)
+	rule := proto.Clone(fakeplugin.ExampleRule).(*paragliderpb.PermitListRule)
+	rule.TtlSeconds = 60
+	require.NoError(t, orchestratorServer._permitListRuleAddTag(ctx, tag, rule))
+	require.Len(t, orchestratorServer.ruleExpirations, 1)
+	for key, expiration := range orchestratorServer.ruleExpirations {
+		assert.Equal(t, "uri/"+tag, key.uri)
+		assert.Equal(t, exampleCloudName, expiration.Cloud)
+		assert.Equal(t, faketagservice.ValidTagName, expiration.Resource)
+	}
+
+	// Deleting the rule from the tag forgets its expiration
+	require.NoError(t, orchestratorServer._permitListRulesDeleteTag(ctx, tag, []string{rule.Name}))
+	assert.Empty(t, orchestratorServer.ruleExpirations)
+	stored, err := orchestratorServer.allocations.List(ctx, store.RuleExpirationsKey)
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+}
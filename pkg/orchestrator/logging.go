@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
+)
+
+// Log fields of the URL params of REST requests
+var urlParamLogKeys = map[string]string{
+	"namespace":    utils.NamespaceLogKey,
+	"cloud":        utils.CloudLogKey,
+	"resourceName": utils.ResourceLogKey,
+	"ruleName":     utils.RulesLogKey,
+	"tag":          utils.TagLogKey,
+}
+
+// Adds the namespace, cloud, resource, rule and tag of a REST request to the log lines written while handling it
+func logFieldsMiddleware(c *gin.Context) {
+	args := []any{}
+	for _, param := range c.Params {
+		if key, ok := urlParamLogKeys[param.Key]; ok {
+			args = append(args, key, param.Value)
+		}
+	}
+	if len(args) > 0 {
+		c.Request = c.Request.WithContext(utils.WithLogAttrs(c.Request.Context(), args...))
+	}
+	c.Next()
+}
+
+// Logs the REST requests handled by the controller
+func requestLoggingMiddleware(c *gin.Context) {
+	start := time.Now()
+	c.Next()
+	route := c.FullPath()
+	if route == "" {
+		route = unmatchedRoute
+	}
+	level := slog.LevelInfo
+	if c.Writer.Status() >= 500 {
+		level = slog.LevelError
+	}
+	args := []any{utils.MethodLogKey, c.Request.Method, "route", route, "status", c.Writer.Status(), "duration", time.Since(start)}
+	if len(c.Errors) > 0 {
+		args = append(args, "error", c.Errors.String())
+	}
+	slog.Log(c.Request.Context(), level, "Handled request", args...)
+}
+
+// Carries the log fields of the request which started an operation over to the operation, which may outlive the request
+func logOperation(attrs []slog.Attr, opType string, fn operationFunc) operationFunc {
+	return func(ctx context.Context) (any, error) {
+		args := []any{"operation", opType}
+		for _, attr := range attrs {
+			args = append(args, attr)
+		}
+		ctx = utils.WithLogAttrs(ctx, args...)
+		result, err := fn(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "Operation failed", "error", err)
+		}
+		return result, err
+	}
+}
@@ -0,0 +1,63 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
+)
+
+func TestLogFieldsMiddleware(t *testing.T) {
+	r := SetUpRouter()
+	r.Use(logFieldsMiddleware)
+	var attrs []slog.Attr
+	r.DELETE(PermitListRulePUTURL, func(c *gin.Context) { attrs = utils.LogAttrs(c.Request.Context()) })
+
+	url := "/namespaces/" + defaultNamespace + "/clouds/" + exampleCloudName + "/resources/vm-1/rules/rule-1"
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, url, nil))
+
+	assert.ElementsMatch(t, []slog.Attr{
+		slog.String(utils.NamespaceLogKey, defaultNamespace),
+		slog.String(utils.CloudLogKey, exampleCloudName),
+		slog.String(utils.ResourceLogKey, "vm-1"),
+		slog.String(utils.RulesLogKey, "rule-1"),
+	}, attrs)
+}
+
+func TestLogOperation(t *testing.T) {
+	requestAttrs := []slog.Attr{slog.String(utils.NamespaceLogKey, defaultNamespace)}
+	var attrs []slog.Attr
+	fn := logOperation(requestAttrs, "test", func(ctx context.Context) (any, error) {
+		attrs = utils.LogAttrs(ctx)
+		return nil, nil
+	})
+
+	_, err := fn(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []slog.Attr{slog.String("operation", "test"), slog.String(utils.NamespaceLogKey, defaultNamespace)}, attrs)
+}
@@ -0,0 +1,399 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/auth"
+	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
+	tagservicepb "github.com/paraglider-project/paraglider/pkg/tag_service/tagservicepb"
+)
+
+const (
+	ApplyManifestURL string = "/namespaces/:namespace/apply"
+	DryRunQueryParam string = "dryRun" // Requests with dryRun=true only compute the changes to make
+)
+
+// Manifest is the desired state of the resources, tags and permit lists of a namespace
+type Manifest struct {
+	Namespace string             `json:"namespace,omitempty"` // Defaults to the namespace of the request
+	Resources []ManifestResource `json:"resources,omitempty"`
+	Tags      []ManifestTag      `json:"tags,omitempty"`
+}
+
+// ManifestResource is a resource along with its complete permit list. Resources which don't exist yet are created
+// from their description, otherwise the description is ignored.
+type ManifestResource struct {
+	Cloud       string                         `json:"cloud"`
+	Name        string                         `json:"name"`
+	Description json.RawMessage                `json:"description,omitempty"`
+	PermitList  []*paragliderpb.PermitListRule `json:"permitList"`
+}
+
+// ManifestTag is a tag along with its complete list of members (tags, IPs or CIDRs)
+type ManifestTag struct {
+	Name      string   `json:"name"`
+	ChildTags []string `json:"childTags"`
+}
+
+// ManifestDiff holds the changes needed to bring a namespace to the state of a manifest.
+// Resources and tags which are already up to date are left out.
+type ManifestDiff struct {
+	Resources []ResourceDiff `json:"resources,omitempty"`
+	Tags      []TagDiff      `json:"tags,omitempty"`
+	// Set once the changes are applied
+	RequestNotices
+}
+
+// ResourceDiff holds the changes to a resource. Rules which changed are added again in place of the current ones.
+type ResourceDiff struct {
+	Cloud         string                         `json:"cloud"`
+	Name          string                         `json:"name"`
+	Create        bool                           `json:"create,omitempty"`
+	RulesToAdd    []*paragliderpb.PermitListRule `json:"rulesToAdd,omitempty"`
+	RulesToDelete []string                       `json:"rulesToDelete,omitempty"`
+	description   json.RawMessage
+}
+
+// TagDiff holds the changes to the members of a tag
+type TagDiff struct {
+	Name            string   `json:"name"`
+	MembersToAdd    []string `json:"membersToAdd,omitempty"`
+	MembersToDelete []string `json:"membersToDelete,omitempty"`
+}
+
+// Empty returns true if there is nothing to change
+func (d *ManifestDiff) Empty() bool {
+	return len(d.Resources) == 0 && len(d.Tags) == 0
+}
+
+// ParseManifest parses a YAML or JSON manifest. Fields are named as in the JSON API, and unknown fields are rejected.
+func ParseManifest(data []byte) (*Manifest, error) {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("unable to parse manifest: %w", err)
+	}
+
+	// Go through JSON so that fields (e.g., the ones of permit list rules) are named the same as in the rest of the API
+	jsonData, err := json.Marshal(convertYAMLMaps(doc))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse manifest: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.DisallowUnknownFields()
+	manifest := &Manifest{}
+	if err := decoder.Decode(manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// Converts the map[interface{}]interface{} produced by the YAML parser to map[string]interface{} which JSON supports
+func convertYAMLMaps(value any) any {
+	switch v := value.(type) {
+	case map[any]any:
+		converted := make(map[string]any, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = convertYAMLMaps(item)
+		}
+		return converted
+	case []any:
+		for i, item := range v {
+			v[i] = convertYAMLMaps(item)
+		}
+	}
+	return value
+}
+
+// Returns true if a rule read back from the cloud is the same as a rule of a manifest. Only the settings the clouds keep are compared
+// (e.g., not the expiration time, which the orchestrator keeps track of, or the source ports, which some clouds ignore), along with
+// the tags since the targets are resolved from them.
+func permitListRulesEqual(current *paragliderpb.PermitListRule, desired *paragliderpb.PermitListRule) bool {
+	if len(diffRuleSettings(desired, current)) > 0 {
+		return false
+	}
+	currentTags := slices.Clone(current.Tags)
+	desiredTags := slices.Clone(desired.Tags)
+	slices.Sort(currentTags)
+	slices.Sort(desiredTags)
+	return slices.Equal(slices.Compact(currentTags), slices.Compact(desiredTags))
+}
+
+// Compute the rules to add and delete to go from the current permit list to the desired one
+func diffPermitList(current []*paragliderpb.PermitListRule, desired []*paragliderpb.PermitListRule) ([]*paragliderpb.PermitListRule, []string, error) {
+	currentRules := make(map[string]*paragliderpb.PermitListRule)
+	for _, rule := range current {
+		currentRules[rule.Name] = rule
+	}
+
+	desiredRules := make(map[string]bool)
+	rulesToAdd := []*paragliderpb.PermitListRule{}
+	rulesToDelete := []string{}
+	for _, rule := range desired {
+		if rule.Name == "" {
+			return nil, nil, fmt.Errorf("rules in a manifest must have a name")
+		}
+		if len(rule.Tags) == 0 {
+			return nil, nil, fmt.Errorf("rule %s contains no tags", rule.Name)
+		}
+		if desiredRules[rule.Name] {
+			return nil, nil, fmt.Errorf("rule %s is defined more than once", rule.Name)
+		}
+		desiredRules[rule.Name] = true
+
+		// Rules which changed are added again, which replaces them in the cloud
+		if currentRule, ok := currentRules[rule.Name]; ok && permitListRulesEqual(currentRule, rule) {
+			continue
+		}
+		rule = proto.Clone(rule).(*paragliderpb.PermitListRule)
+		rule.Targets = nil
+		rulesToAdd = append(rulesToAdd, rule)
+	}
+
+	for _, rule := range current {
+		if !desiredRules[rule.Name] {
+			rulesToDelete = append(rulesToDelete, rule.Name)
+		}
+	}
+	return rulesToAdd, rulesToDelete, nil
+}
+
+// Compute the members to add and delete to go from the current members of a tag to the desired ones
+func diffTagMembers(current []string, desired []string) ([]string, []string) {
+	currentMembers := make(map[string]bool)
+	for _, member := range current {
+		currentMembers[member] = true
+	}
+	desiredMembers := make(map[string]bool)
+	membersToAdd := []string{}
+	for _, member := range desired {
+		if !currentMembers[member] && !desiredMembers[member] {
+			membersToAdd = append(membersToAdd, member)
+		}
+		desiredMembers[member] = true
+	}
+	membersToDelete := []string{}
+	for _, member := range current {
+		if !desiredMembers[member] {
+			membersToDelete = append(membersToDelete, member)
+		}
+	}
+	return membersToAdd, membersToDelete
+}
+
+// Compute the changes needed to bring a namespace to the state of a manifest
+func (s *ControllerServer) diffManifest(ctx context.Context, namespace string, manifest *Manifest) (*ManifestDiff, error) {
+	conn, err := s.serviceConns.Get(s.localTagService)
+	if err != nil {
+		return nil, fmt.Errorf("could not contact tag server: %s", err.Error())
+	}
+
+	// Get the current tags
+	tagClient := tagservicepb.NewTagServiceClient(conn)
+	listResp, err := tagClient.ListTags(ctx, &tagservicepb.ListTagsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("could not list tags: %s", err.Error())
+	}
+	tags := make(map[string]*tagservicepb.TagMapping)
+	for _, tag := range listResp.Tags {
+		tags[tag.Name] = tag
+	}
+
+	diff := &ManifestDiff{}
+	seenResources := make(map[string]bool)
+	for _, resource := range manifest.Resources {
+		cloudClient, ok := s.getPluginAddress(resource.Cloud)
+		if !ok {
+			return nil, fmt.Errorf("invalid cloud name: %s", resource.Cloud)
+		}
+		tagName := getTagName(namespace, resource.Cloud, resource.Name)
+		if seenResources[tagName] {
+			return nil, fmt.Errorf("resource %s is defined more than once", tagName)
+		}
+		seenResources[tagName] = true
+
+		resourceDiff := ResourceDiff{Cloud: resource.Cloud, Name: resource.Name}
+		currentRules := []*paragliderpb.PermitListRule{}
+		if tag, ok := tags[tagName]; ok && isTagValid(tag) {
+			permitList, err := s._permitListGet(ctx, namespace, tag.GetUri(), cloudClient)
+			if err != nil {
+				return nil, fmt.Errorf("could not get permit list of %s: %s", tagName, err.Error())
+			}
+			currentRules = permitList.Rules
+		} else if len(resource.Description) > 0 {
+			resourceDiff.Create = true
+			resourceDiff.description = resource.Description
+		} else {
+			return nil, fmt.Errorf("resource %s does not exist and has no description to create it from", tagName)
+		}
+
+		resourceDiff.RulesToAdd, resourceDiff.RulesToDelete, err = diffPermitList(currentRules, resource.PermitList)
+		if err != nil {
+			return nil, fmt.Errorf("invalid permit list for %s: %s", tagName, err.Error())
+		}
+		if resourceDiff.Create || len(resourceDiff.RulesToAdd) > 0 || len(resourceDiff.RulesToDelete) > 0 {
+			diff.Resources = append(diff.Resources, resourceDiff)
+		}
+	}
+
+	seenTags := make(map[string]bool)
+	for _, tag := range manifest.Tags {
+		if tag.Name == "" {
+			return nil, fmt.Errorf("tags in a manifest must have a name")
+		}
+		if seenTags[tag.Name] {
+			return nil, fmt.Errorf("tag %s is defined more than once", tag.Name)
+		}
+		seenTags[tag.Name] = true
+
+		currentMembers := []string{}
+		if currentTag, ok := tags[tag.Name]; ok {
+			if isTagValid(currentTag) {
+				return nil, fmt.Errorf("tag %s belongs to a resource and cannot have members", tag.Name)
+			}
+			currentMembers = currentTag.ChildTags
+		}
+
+		tagDiff := TagDiff{Name: tag.Name}
+		tagDiff.MembersToAdd, tagDiff.MembersToDelete = diffTagMembers(currentMembers, tag.ChildTags)
+		if len(tagDiff.MembersToAdd) > 0 || len(tagDiff.MembersToDelete) > 0 {
+			diff.Tags = append(diff.Tags, tagDiff)
+		}
+	}
+
+	return diff, nil
+}
+
+// Make the changes in a manifest diff. Tags are updated first so that rules added afterwards resolve to their new members.
+func (s *ControllerServer) applyManifestDiff(ctx context.Context, namespace string, diff *ManifestDiff) error {
+	conn, err := s.serviceConns.Get(s.localTagService)
+	if err != nil {
+		return fmt.Errorf("could not contact tag server: %s", err.Error())
+	}
+	tagClient := tagservicepb.NewTagServiceClient(conn)
+
+	for _, tagDiff := range diff.Tags {
+		setOperationProgress(ctx, fmt.Sprintf("Updating tag %s", tagDiff.Name))
+		if len(tagDiff.MembersToAdd) > 0 {
+			_, err := tagClient.SetTag(ctx, &tagservicepb.SetTagRequest{Tag: &tagservicepb.TagMapping{Name: tagDiff.Name, ChildTags: tagDiff.MembersToAdd}})
+			if err != nil {
+				return err
+			}
+		}
+		for _, member := range tagDiff.MembersToDelete {
+			_, err := tagClient.DeleteTagMember(ctx, &tagservicepb.DeleteTagMemberRequest{ParentTag: tagDiff.Name, ChildTag: member})
+			if err != nil {
+				return err
+			}
+		}
+		if err := s.updateSubscribers(ctx, tagDiff.Name); err != nil {
+			return err
+		}
+	}
+
+	for _, resourceDiff := range diff.Resources {
+		cloudClient, _ := s.getPluginAddress(resourceDiff.Cloud)
+		resourceInfo := &ResourceInfo{name: resourceDiff.Name, cloud: resourceDiff.Cloud, namespace: namespace}
+		if resourceDiff.Create {
+			resourceResp, err := s.resourceCreate(ctx, resourceInfo, cloudClient, &paragliderpb.ResourceDescriptionString{Description: string(resourceDiff.description)})
+			if err != nil {
+				return err
+			}
+			resourceInfo.uri = resourceResp.Uri
+		} else {
+			resourceInfo.uri, err = s.getTagUri(ctx, getTagName(namespace, resourceDiff.Cloud, resourceDiff.Name))
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(resourceDiff.RulesToDelete) > 0 {
+			if err := s._permitListRulesDelete(ctx, resourceInfo, cloudClient, resourceDiff.RulesToDelete); err != nil {
+				return err
+			}
+		}
+		if len(resourceDiff.RulesToAdd) > 0 {
+			// Rules are resolved in place, so keep the ones of the diff (which is the result of the operation) untouched
+			rules := make([]*paragliderpb.PermitListRule, len(resourceDiff.RulesToAdd))
+			for i, rule := range resourceDiff.RulesToAdd {
+				rules[i] = proto.Clone(rule).(*paragliderpb.PermitListRule)
+			}
+			request := &paragliderpb.AddPermitListRulesRequest{Rules: rules, Namespace: namespace, Resource: resourceInfo.uri}
+			if _, err := s._permitListRulesAdd(ctx, request, resourceInfo, cloudClient); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Bring a namespace to the state described by a manifest, only changing what differs.
+// With dryRun=true, the changes are returned without being made.
+func (s *ControllerServer) applyManifest(c *gin.Context) {
+	namespace := c.Param("namespace")
+
+	var manifest Manifest
+	if err := c.BindJSON(&manifest); err != nil {
+		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
+		return
+	}
+	if manifest.Namespace != "" && manifest.Namespace != namespace {
+		c.AbortWithStatusJSON(400, createErrorResponse(fmt.Sprintf("manifest is for namespace %s but was applied to namespace %s", manifest.Namespace, namespace)))
+		return
+	}
+	// Tags aren't scoped to the namespace, so setting their members needs rights on each of them
+	for _, tag := range manifest.Tags {
+		if !s.authorized(c, auth.AdminAction, auth.Scope{Tag: tag.Name}) {
+			c.AbortWithStatusJSON(http.StatusForbidden, createErrorResponse(fmt.Sprintf("not allowed to set members of tag %s", tag.Name)))
+			return
+		}
+	}
+
+	if dryRun, _ := strconv.ParseBool(c.Query(DryRunQueryParam)); dryRun {
+		diff, err := s.diffManifest(c, namespace, &manifest)
+		if err != nil {
+			c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
+			return
+		}
+		c.JSON(http.StatusOK, diff)
+		return
+	}
+
+	s.runOperation(c, ApplyManifestOperation, func(ctx context.Context) (any, error) {
+		setOperationProgress(ctx, "Computing changes")
+		diff, err := s.diffManifest(ctx, namespace, &manifest)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.applyManifestDiff(ctx, namespace, diff); err != nil {
+			return nil, err
+		}
+		return diff, nil
+	})
+}
@@ -0,0 +1,204 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	fakeplugin "github.com/paraglider-project/paraglider/pkg/fake/cloudplugin"
+	faketagservice "github.com/paraglider-project/paraglider/pkg/fake/tagservice"
+	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
+)
+
+func TestParseManifest(t *testing.T) {
+	yamlManifest := `
+namespace: default
+resources:
+  - cloud: gcp
+    name: vm-1
+    description:
+      machine_type: e2-micro
+    permitList:
+      - name: allow-ssh
+        tags: ["10.0.0.0/8"]
+        direction: 0
+        src_port: -1
+        dst_port: 22
+        protocol: 6
+tags:
+  - name: web
+    childTags: ["default.gcp.vm-1"]
+`
+	manifest, err := ParseManifest([]byte(yamlManifest))
+	require.NoError(t, err)
+	assert.Equal(t, "default", manifest.Namespace)
+	require.Len(t, manifest.Resources, 1)
+	assert.JSONEq(t, `{"machine_type": "e2-micro"}`, string(manifest.Resources[0].Description))
+	require.Len(t, manifest.Resources[0].PermitList, 1)
+	rule := manifest.Resources[0].PermitList[0]
+	assert.Equal(t, "allow-ssh", rule.Name)
+	assert.Equal(t, int32(22), rule.DstPort)
+	assert.Equal(t, int32(-1), rule.SrcPort)
+	assert.Equal(t, []string{"default.gcp.vm-1"}, manifest.Tags[0].ChildTags)
+
+	// JSON manifests parse the same way
+	jsonManifest, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	fromJSON, err := ParseManifest(jsonManifest)
+	require.NoError(t, err)
+	assert.Equal(t, manifest.Tags, fromJSON.Tags)
+	assert.Equal(t, manifest.Resources[0].PermitList[0].DstPort, fromJSON.Resources[0].PermitList[0].DstPort)
+
+	// Unknown fields are rejected
+	_, err = ParseManifest([]byte("namespace: default\nresource: []\n"))
+	require.Error(t, err)
+	_, err = ParseManifest([]byte("namespace: [\n"))
+	require.Error(t, err)
+}
+
+func TestDiffPermitList(t *testing.T) {
+	current := []*paragliderpb.PermitListRule{
+		{Name: "unchanged", Tags: []string{"1.1.1.1"}, Targets: []string{"1.1.1.1"}, DstPort: 22, Protocol: 6},
+		{Name: "changed", Tags: []string{"1.1.1.1"}, DstPort: 80, Protocol: 6},
+		{Name: "removed", Tags: []string{"1.1.1.1"}, DstPort: 443, Protocol: 6},
+		{Name: "read-back", Tags: []string{"2.2.2.2", "1.1.1.1"}, SrcPort: -1, DstPorts: []*paragliderpb.PortRange{{Start: 80, End: 81}}, Protocol: 6, ExpiresAt: 2000},
+	}
+	desired := []*paragliderpb.PermitListRule{
+		{Name: "unchanged", Tags: []string{"1.1.1.1"}, DstPort: 22, Protocol: 6},
+		// Settings the clouds don't keep (e.g., source ports on GCP) don't make rules differ
+		{Name: "read-back", Tags: []string{"1.1.1.1", "2.2.2.2"}, SrcPort: 1234, DstPorts: []*paragliderpb.PortRange{{Start: 80, End: 80}, {Start: 81, End: 81}}, Protocol: 6, TtlSeconds: 60},
+		{Name: "changed", Tags: []string{"2.2.2.2"}, DstPort: 80, Protocol: 6},
+		{Name: "added", Tags: []string{"2.2.2.2"}, DstPort: 8080, Protocol: 6, Targets: []string{"ignored"}},
+	}
+
+	rulesToAdd, rulesToDelete, err := diffPermitList(current, desired)
+	require.NoError(t, err)
+	require.Len(t, rulesToAdd, 2)
+	assert.Equal(t, "changed", rulesToAdd[0].Name)
+	assert.Equal(t, "added", rulesToAdd[1].Name)
+	assert.Empty(t, rulesToAdd[1].Targets)
+	// Changed rules are only added again
+	assert.Equal(t, []string{"removed"}, rulesToDelete)
+
+	// Invalid rules
+	_, _, err = diffPermitList(current, []*paragliderpb.PermitListRule{{Tags: []string{"1.1.1.1"}}})
+	require.Error(t, err)
+	_, _, err = diffPermitList(current, []*paragliderpb.PermitListRule{{Name: "notags"}})
+	require.Error(t, err)
+	_, _, err = diffPermitList(current, []*paragliderpb.PermitListRule{desired[0], desired[0]})
+	require.Error(t, err)
+}
+
+func TestDiffTagMembers(t *testing.T) {
+	membersToAdd, membersToDelete := diffTagMembers([]string{"a", "b"}, []string{"b", "c", "c"})
+	assert.Equal(t, []string{"c"}, membersToAdd)
+	assert.Equal(t, []string{"a"}, membersToDelete)
+}
+
+func TestApplyManifest(t *testing.T) {
+	// Setup
+	orchestratorServer := newOrchestratorServer()
+	port := getNewPortNumber()
+	tagServerPort := getNewPortNumber()
+	orchestratorServer.localTagService = fmt.Sprintf("localhost:%d", tagServerPort)
+	orchestratorServer.pluginAddresses[faketagservice.SubscriberCloudName] = fmt.Sprintf("localhost:%d", port)
+
+	fakeplugin.SetupFakePluginServer(port)
+	faketagservice.SetupFakeTagServer(tagServerPort)
+
+	r := SetUpRouter()
+	r.POST(ApplyManifestURL, orchestratorServer.applyManifest)
+
+	namespace := faketagservice.SubscriberNamespace
+	cloud := faketagservice.SubscriberCloudName
+	newRule := &paragliderpb.PermitListRule{Name: "allow-ssh", Tags: []string{"1.1.1.1"}, DstPort: 22, SrcPort: -1, Protocol: 6}
+	manifest := Manifest{
+		Resources: []ManifestResource{
+			{Cloud: cloud, Name: faketagservice.ValidLastLevelTagName, PermitList: []*paragliderpb.PermitListRule{fakeplugin.ExampleRule, newRule}},
+			{Cloud: cloud, Name: "new-vm", Description: json.RawMessage(`{"name": "new-vm"}`), PermitList: []*paragliderpb.PermitListRule{newRule}},
+		},
+		Tags: []ManifestTag{
+			{Name: faketagservice.ValidTagName, ChildTags: []string{"child", "new-child"}},
+		},
+	}
+	url := fmt.Sprintf(GetFormatterString(ApplyManifestURL), namespace)
+	apply := func(manifest Manifest, query string) *httptest.ResponseRecorder {
+		jsonValue, _ := json.Marshal(manifest)
+		req, _ := http.NewRequest("POST", url+query, bytes.NewBuffer(jsonValue))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Dry run only reports the changes
+	w := apply(manifest, "?"+DryRunQueryParam+"=true")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var diff ManifestDiff
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &diff))
+	require.Len(t, diff.Resources, 2)
+	assert.False(t, diff.Resources[0].Create)
+	require.Len(t, diff.Resources[0].RulesToAdd, 1)
+	assert.Equal(t, newRule.Name, diff.Resources[0].RulesToAdd[0].Name)
+	assert.Empty(t, diff.Resources[0].RulesToDelete)
+	assert.True(t, diff.Resources[1].Create)
+	require.Len(t, diff.Tags, 1)
+	assert.Equal(t, []string{"new-child"}, diff.Tags[0].MembersToAdd)
+	// The resource tag is no longer a member
+	assert.Len(t, diff.Tags[0].MembersToDelete, 1)
+
+	// Apply the changes
+	w = apply(manifest, "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var applied ManifestDiff
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &applied))
+	assert.Equal(t, diff, applied)
+
+	// Rules missing from the manifest are deleted
+	w = apply(Manifest{Resources: []ManifestResource{{Cloud: cloud, Name: faketagservice.ValidLastLevelTagName}}}, "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &applied))
+	require.Len(t, applied.Resources, 1)
+	assert.Equal(t, []string{fakeplugin.ExampleRule.Name}, applied.Resources[0].RulesToDelete)
+
+	// Nothing to do
+	w = apply(Manifest{Resources: []ManifestResource{{Cloud: cloud, Name: faketagservice.ValidLastLevelTagName, PermitList: []*paragliderpb.PermitListRule{fakeplugin.ExampleRule}}}}, "?"+DryRunQueryParam+"=true")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `{}`, w.Body.String())
+
+	// Invalid manifests
+	invalidManifests := []Manifest{
+		{Namespace: "other"},
+		{Resources: []ManifestResource{{Cloud: "invalid", Name: faketagservice.ValidLastLevelTagName}}},
+		{Resources: []ManifestResource{{Cloud: cloud, Name: "missing"}}},
+		{Tags: []ManifestTag{{Name: namespace + "." + cloud + "." + faketagservice.ValidLastLevelTagName, ChildTags: []string{"child"}}}},
+		{Tags: []ManifestTag{{Name: "tag"}, {Name: "tag"}}},
+	}
+	for _, invalidManifest := range invalidManifests {
+		w = apply(invalidManifest, "")
+		assert.Equal(t, http.StatusBadRequest, w.Code, invalidManifest)
+	}
+}
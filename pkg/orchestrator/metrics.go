@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/status"
+
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/store"
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
+)
+
+const MetricsURL string = utils.MetricsPath
+
+// Operations whose tags are resolved, as recorded by the tag resolution fan-out metric
+const (
+	ruleTargetsFanout   = "rule_targets"    // Resolving the tags of permit list rules to IPs
+	tagRuleAddFanout    = "tag_rule_add"    // Adding a rule to the resources of a tag
+	tagRuleDeleteFanout = "tag_rule_delete" // Deleting rules from the resources of a tag
+)
+
+// Route label of REST requests which don't match any route
+const unmatchedRoute = "unmatched"
+
+var (
+	restRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "paraglider_rest_requests_total",
+		Help: "REST requests handled by the controller, by method, route and status code.",
+	}, []string{"method", "route", "code"})
+	restRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "paraglider_rest_request_duration_seconds",
+		Help:    "Time taken by the controller to handle REST requests, by method and route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+	pluginRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "paraglider_plugin_requests_total",
+		Help: "Calls from the controller to cloud plugins, by plugin, method and status code.",
+	}, []string{"plugin", "method", "code"})
+	pluginRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "paraglider_plugin_request_duration_seconds",
+		Help:    "Time taken by calls from the controller to cloud plugins (including retries), by plugin and method.",
+		Buckets: prometheus.ExponentialBuckets(0.05, 4, 9), // 50ms to ~55min, since creating a VPN gateway takes up to 45 minutes
+	}, []string{"plugin", "method"})
+	tagResolutionFanout = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "paraglider_tag_resolution_fanout",
+		Help:    "Number of IPs or resources a tag resolves to, by operation.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 11), // 1 to 1024
+	}, []string{"operation"})
+)
+
+// Gauges of the values allocated by the controller, by the allocation store key they're recorded under
+var allocationGauges = map[string]*prometheus.Desc{
+	store.AddressSpacesKey:         prometheus.NewDesc("paraglider_allocated_address_spaces", "Address blocks allocated by the controller.", nil, nil),
+	store.AsnsKey:                  prometheus.NewDesc("paraglider_allocated_asns", "ASNs allocated by the controller.", nil, nil),
+	store.BgpPeeringIpAddressesKey: prometheus.NewDesc("paraglider_allocated_bgp_peering_ip_addresses", "BGP peering IP addresses allocated by the controller.", nil, nil),
+}
+
+// The collector is registered once per process, while its store is set by every Setup (e.g., when tests run several
+// controllers one after the other)
+var allocationMetrics = &allocationCollector{}
+
+func init() {
+	utils.MetricsRegistry.MustRegister(restRequests, restRequestDuration, pluginRequests, pluginRequestDuration, tagResolutionFanout, allocationMetrics)
+}
+
+// Records the count and duration of the REST requests handled by the controller
+func restMetricsMiddleware(c *gin.Context) {
+	start := time.Now()
+	c.Next()
+	route := c.FullPath()
+	if route == "" {
+		route = unmatchedRoute
+	}
+	restRequests.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Inc()
+	restRequestDuration.WithLabelValues(c.Request.Method, route).Observe(time.Since(start).Seconds())
+}
+
+// Records the outcome and duration of a call to a plugin
+func observePluginCall(plugin string, fullMethod string, err error, duration time.Duration) {
+	_, method := utils.SplitMethodName(fullMethod)
+	pluginRequests.WithLabelValues(plugin, method, status.Code(err).String()).Inc()
+	pluginRequestDuration.WithLabelValues(plugin, method).Observe(duration.Seconds())
+}
+
+// Collects the number of allocated values from the allocation store whenever the metrics are scraped,
+// so the gauges are right even for allocations made before a restart
+type allocationCollector struct {
+	lock        sync.Mutex
+	allocations store.AllocationStore // Nothing is collected until it's set
+}
+
+func (a *allocationCollector) setStore(allocations store.AllocationStore) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.allocations = allocations
+}
+
+func (a *allocationCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range allocationGauges {
+		ch <- desc
+	}
+}
+
+func (a *allocationCollector) Collect(ch chan<- prometheus.Metric) {
+	a.lock.Lock()
+	allocations := a.allocations
+	a.lock.Unlock()
+	if allocations == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), serviceCallTimeout)
+	defer cancel()
+	for key, desc := range allocationGauges {
+		values, err := allocations.List(ctx, key)
+		if err != nil {
+			ch <- prometheus.NewInvalidMetric(desc, err)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(len(values)))
+	}
+}
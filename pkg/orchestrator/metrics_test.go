@@ -0,0 +1,91 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	fakeplugin "github.com/paraglider-project/paraglider/pkg/fake/cloudplugin"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/store"
+)
+
+func TestRestMetricsMiddleware(t *testing.T) {
+	r := SetUpRouter()
+	r.Use(restMetricsMiddleware)
+	r.GET(ListPluginsURL, func(c *gin.Context) { c.Status(http.StatusTeapot) })
+
+	matched := restRequests.WithLabelValues(http.MethodGet, ListPluginsURL, "418")
+	unmatched := restRequests.WithLabelValues(http.MethodGet, unmatchedRoute, "404")
+	matchedBefore, unmatchedBefore := testutil.ToFloat64(matched), testutil.ToFloat64(unmatched)
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, ListPluginsURL, nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	assert.Equal(t, matchedBefore+1, testutil.ToFloat64(matched))
+	assert.Equal(t, unmatchedBefore+1, testutil.ToFloat64(unmatched))
+}
+
+func TestPluginMetrics(t *testing.T) {
+	orchestratorServer := newOrchestratorServer()
+	port := getNewPortNumber()
+	orchestratorServer.pluginAddresses[exampleCloudName] = fmt.Sprintf("localhost:%d", port)
+	fakeplugin.SetupFakePluginServer(port)
+
+	requests := pluginRequests.WithLabelValues(exampleCloudName, "GetCapabilities", codes.OK.String())
+	before := testutil.ToFloat64(requests)
+
+	_, err := orchestratorServer.getCapabilities(context.Background(), exampleCloudName)
+	require.NoError(t, err)
+	assert.Equal(t, before+1, testutil.ToFloat64(requests))
+}
+
+func TestAllocationCollector(t *testing.T) {
+	allocations := store.NewMemoryStore()
+	require.NoError(t, allocations.Add(context.Background(), store.AddressSpacesKey, "10.0.0.0/16", "10.1.0.0/16"))
+	require.NoError(t, allocations.Add(context.Background(), store.AsnsKey, "64512"))
+
+	expected := `
+# HELP paraglider_allocated_address_spaces Address blocks allocated by the controller.
+# TYPE paraglider_allocated_address_spaces gauge
+paraglider_allocated_address_spaces 2
+# HELP paraglider_allocated_asns ASNs allocated by the controller.
+# TYPE paraglider_allocated_asns gauge
+paraglider_allocated_asns 1
+# HELP paraglider_allocated_bgp_peering_ip_addresses BGP peering IP addresses allocated by the controller.
+# TYPE paraglider_allocated_bgp_peering_ip_addresses gauge
+paraglider_allocated_bgp_peering_ip_addresses 0
+`
+	assert.NoError(t, testutil.CollectAndCompare(&allocationCollector{allocations: allocations}, strings.NewReader(expected)))
+
+	// The registered collector reports the store of the latest controller set up in the process
+	allocationMetrics.setStore(store.NewMemoryStore())
+	allocationMetrics.setStore(allocations)
+	assert.NoError(t, testutil.CollectAndCompare(allocationMetrics, strings.NewReader(expected)))
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/store"
+	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
+)
+
+const (
+	CreateNamespaceURL   string = "/namespaces"
+	DeleteNamespaceURL   string = "/namespaces/:namespace"
+	SetNamespaceCloudURL string = "/namespaces/:namespace/clouds/:cloud"
+	CascadeQueryParam    string = "cascade" // Namespace deletions with cascade=true also delete the namespace's infrastructure in the clouds
+)
+
+// Namespace names end up in cloud resource names (e.g., VPC names), so they are restricted to what every cloud accepts
+var namespaceNameRegex = regexp.MustCompile(`^[a-z][a-z0-9-]{0,19}$`)
+
+// Namespace is a namespace and the cloud deployments bound to it
+type Namespace struct {
+	Name   string                   `json:"name"`
+	Clouds []config.CloudDeployment `json:"clouds,omitempty"`
+}
+
+// Response of requests which create or change a namespace
+type NamespaceResponse struct {
+	Namespace
+	RequestNotices
+}
+
+// NamespaceCloud is the deployment bound to a cloud in a namespace
+type NamespaceCloud struct {
+	Deployment string `json:"deployment"`
+}
+
+// Load the namespaces created through the API from the allocation store. Namespaces in the config take precedence.
+func (s *ControllerServer) loadNamespaces(ctx context.Context) error {
+	values, err := s.allocations.List(ctx, store.NamespacesKey)
+	if err != nil {
+		return err
+	}
+
+	s.namespacesLock.Lock()
+	defer s.namespacesLock.Unlock()
+	if s.config.Namespaces == nil {
+		s.config.Namespaces = make(map[string][]config.CloudDeployment)
+	}
+	for _, value := range values {
+		namespace := Namespace{}
+		if err := json.Unmarshal([]byte(value), &namespace); err != nil {
+			return fmt.Errorf("invalid stored namespace %s: %w", value, err)
+		}
+		if _, ok := s.config.Namespaces[namespace.Name]; ok {
+			continue
+		}
+		s.config.Namespaces[namespace.Name] = namespace.Clouds
+		s.createdNamespaces[namespace.Name] = value
+	}
+	return nil
+}
+
+// Record a namespace created through the API, replacing what was stored for it before. Callers must hold namespacesLock.
+func (s *ControllerServer) storeNamespace(ctx context.Context, namespace Namespace) error {
+	value, err := json.Marshal(namespace)
+	if err != nil {
+		return err
+	}
+	if previous, ok := s.createdNamespaces[namespace.Name]; ok {
+		if err := s.allocations.Remove(ctx, store.NamespacesKey, previous); err != nil {
+			return err
+		}
+	}
+	if err := s.allocations.Add(ctx, store.NamespacesKey, string(value)); err != nil {
+		return err
+	}
+	s.createdNamespaces[namespace.Name] = string(value)
+	if s.config.Namespaces == nil {
+		s.config.Namespaces = make(map[string][]config.CloudDeployment)
+	}
+	s.config.Namespaces[namespace.Name] = namespace.Clouds
+	return nil
+}
+
+// Respond with a namespace created or changed through the API, warning that it is lost on restart if the allocation store is kept in memory
+func (s *ControllerServer) respondWithNamespace(c *gin.Context, namespace Namespace) {
+	resp := NamespaceResponse{Namespace: namespace}
+	if !s.isAllocationStorePersistent() {
+		slog.WarnContext(c, "Namespace is lost when the controller restarts since the allocation store is kept in memory", "namespace", namespace.Name)
+		resp.Warnings = []*paragliderpb.Notice{{
+			Resource: namespace.Name,
+			Message:  "namespace is lost when the controller restarts since the allocation store is kept in memory (set allocationStore.type to file or kvstore)",
+		}}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Check that a cloud deployment can be bound to a namespace
+func (s *ControllerServer) validateCloudDeployment(deployment config.CloudDeployment) error {
+	if _, ok := s.getPluginAddress(deployment.Name); !ok {
+		return fmt.Errorf("invalid cloud name: %s", deployment.Name)
+	}
+	if deployment.Deployment == "" {
+		return fmt.Errorf("missing deployment for cloud %s", deployment.Name)
+	}
+	return nil
+}
+
+// Create a namespace
+func (s *ControllerServer) createNamespace(c *gin.Context) {
+	var namespace Namespace
+	if err := c.BindJSON(&namespace); err != nil {
+		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
+		return
+	}
+	if !namespaceNameRegex.MatchString(namespace.Name) {
+		c.AbortWithStatusJSON(400, createErrorResponse(fmt.Sprintf("invalid namespace name %q: must start with a lowercase letter, contain only lowercase letters, digits and hyphens and be at most 20 characters long", namespace.Name)))
+		return
+	}
+	for i, deployment := range namespace.Clouds {
+		if err := s.validateCloudDeployment(deployment); err != nil {
+			c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
+			return
+		}
+		if slices.ContainsFunc(namespace.Clouds[:i], func(other config.CloudDeployment) bool { return other.Name == deployment.Name }) {
+			c.AbortWithStatusJSON(400, createErrorResponse(fmt.Sprintf("cloud %s is listed more than once", deployment.Name)))
+			return
+		}
+	}
+	if namespace.Clouds == nil {
+		namespace.Clouds = []config.CloudDeployment{}
+	}
+
+	s.namespacesLock.Lock()
+	defer s.namespacesLock.Unlock()
+	if _, ok := s.config.Namespaces[namespace.Name]; ok {
+		c.AbortWithStatusJSON(400, createErrorResponse(fmt.Sprintf("namespace %s already exists", namespace.Name)))
+		return
+	}
+	if err := s.storeNamespace(c, namespace); err != nil {
+		c.AbortWithStatusJSON(400, createErrorResponse(fmt.Sprintf("could not store namespace: %s", err.Error())))
+		return
+	}
+
+	s.respondWithNamespace(c, namespace)
+}
+
+// Bind a cloud deployment to a namespace (replacing the one bound before)
+func (s *ControllerServer) setNamespaceCloud(c *gin.Context) {
+	var namespaceCloud NamespaceCloud
+	if err := c.BindJSON(&namespaceCloud); err != nil {
+		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
+		return
+	}
+	deployment := config.CloudDeployment{Name: c.Param("cloud"), Deployment: namespaceCloud.Deployment}
+	if err := s.validateCloudDeployment(deployment); err != nil {
+		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
+		return
+	}
+
+	s.namespacesLock.Lock()
+	defer s.namespacesLock.Unlock()
+	namespace := Namespace{Name: c.Param("namespace")}
+	if err := s.checkNamespaceModifiable(namespace.Name); err != nil {
+		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
+		return
+	}
+	namespace.Clouds = slices.DeleteFunc(slices.Clone(s.config.Namespaces[namespace.Name]), func(other config.CloudDeployment) bool {
+		return other.Name == deployment.Name
+	})
+	namespace.Clouds = append(namespace.Clouds, deployment)
+	if err := s.storeNamespace(c, namespace); err != nil {
+		c.AbortWithStatusJSON(400, createErrorResponse(fmt.Sprintf("could not store namespace: %s", err.Error())))
+		return
+	}
+
+	s.respondWithNamespace(c, namespace)
+}
+
+// Namespaces from the config can't be changed through the API since they would come back on restart.
+// Callers must hold namespacesLock.
+func (s *ControllerServer) checkNamespaceModifiable(namespace string) error {
+	if _, ok := s.config.Namespaces[namespace]; !ok {
+		return fmt.Errorf("namespace %s does not exist", namespace)
+	}
+	if _, ok := s.createdNamespaces[namespace]; !ok {
+		return fmt.Errorf("namespace %s is defined in the config and can't be changed through the API", namespace)
+	}
+	return nil
+}
+
+// Delete the infrastructure Paraglider created for a namespace in a cloud
+func (s *ControllerServer) deleteNamespaceInCloud(ctx context.Context, namespace string, deployment config.CloudDeployment) error {
+	cloudClient, ok := s.getPluginAddress(deployment.Name)
+	if !ok {
+		return fmt.Errorf("invalid cloud name: %s", deployment.Name)
+	}
+	conn, err := s.pluginConns.Get(cloudClient)
+	if err != nil {
+		return err
+	}
+
+	client := paragliderpb.NewCloudPluginClient(conn)
+	_, err = client.DeleteNamespace(ctx, &paragliderpb.DeleteNamespaceRequest{
+		Deployment: &paragliderpb.ParagliderDeployment{Id: deployment.Deployment, Namespace: namespace},
+	})
+	if err != nil {
+		return fmt.Errorf("could not delete namespace in %s: %w", deployment.Name, err)
+	}
+	return nil
+}
+
+// Delete a namespace. With cascade=true, the VPCs, firewalls and VPN gateways Paraglider created for it are deleted too.
+func (s *ControllerServer) deleteNamespace(c *gin.Context) {
+	namespace := c.Param("namespace")
+	cascade, _ := strconv.ParseBool(c.Query(CascadeQueryParam))
+
+	s.namespacesLock.RLock()
+	err := s.checkNamespaceModifiable(namespace)
+	s.namespacesLock.RUnlock()
+	if err != nil {
+		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
+		return
+	}
+
+	s.runOperation(c, DeleteNamespaceOperation, func(ctx context.Context) (any, error) {
+		if cascade {
+			s.namespacesLock.RLock()
+			deployments := slices.Clone(s.config.Namespaces[namespace])
+			s.namespacesLock.RUnlock()
+			// Allocations which are still recorded (e.g., for the VPN gateways) are never handed out again, even once deleted
+			if err := s.releaseAllocations(ctx); err != nil {
+				slog.WarnContext(ctx, "Unable to release allocations", "error", err)
+			}
+			for _, deployment := range deployments {
+				setOperationProgress(ctx, fmt.Sprintf("Deleting namespace in %s", deployment.Name))
+				if err := s.deleteNamespaceInCloud(ctx, namespace, deployment); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		// The controller no longer keeps track of the rules left in the namespace
+		if err := s.forgetNamespaceRuleExpirations(ctx, namespace); err != nil {
+			return nil, fmt.Errorf("could not forget rule expirations: %w", err)
+		}
+		if err := s.forgetNamespaceExpectedRules(ctx, namespace); err != nil {
+			return nil, fmt.Errorf("could not forget expected rules: %w", err)
+		}
+
+		s.namespacesLock.Lock()
+		defer s.namespacesLock.Unlock()
+		if value, ok := s.createdNamespaces[namespace]; ok {
+			if err := s.allocations.Remove(ctx, store.NamespacesKey, value); err != nil {
+				return nil, fmt.Errorf("could not remove stored namespace: %w", err)
+			}
+			delete(s.createdNamespaces, namespace)
+			delete(s.config.Namespaces, namespace)
+		}
+		return gin.H{}, nil
+	})
+}
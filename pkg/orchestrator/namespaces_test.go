@@ -0,0 +1,176 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	fakeplugin "github.com/paraglider-project/paraglider/pkg/fake/cloudplugin"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/store"
+	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
+)
+
+func sendNamespaceRequest(r *gin.Engine, method string, url string, body any) *httptest.ResponseRecorder {
+	jsonValue, _ := json.Marshal(body)
+	req, _ := http.NewRequest(method, url, bytes.NewBuffer(jsonValue))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreateNamespace(t *testing.T) {
+	orchestratorServer := newOrchestratorServer()
+	orchestratorServer.pluginAddresses[exampleCloudName] = "localhost:0"
+	orchestratorServer.config.Namespaces = map[string][]config.CloudDeployment{
+		defaultNamespace: {{Name: exampleCloudName, Deployment: "deployment"}},
+	}
+
+	r := SetUpRouter()
+	r.POST(CreateNamespaceURL, orchestratorServer.createNamespace)
+	r.PUT(SetNamespaceCloudURL, orchestratorServer.setNamespaceCloud)
+	r.GET(ListNamespacesURL, orchestratorServer.listNamespaces)
+
+	// Create a namespace
+	namespace := Namespace{Name: "team-a", Clouds: []config.CloudDeployment{{Name: exampleCloudName, Deployment: "deployment-a"}}}
+	w := sendNamespaceRequest(r, "POST", CreateNamespaceURL, namespace)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	// The allocation store is kept in memory, so the namespace wouldn't survive a restart
+	var resp NamespaceResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, namespace, resp.Namespace)
+	require.Len(t, resp.Warnings, 1)
+	assert.Equal(t, "team-a", resp.Warnings[0].Resource)
+
+	req, _ := http.NewRequest("GET", ListNamespacesURL, nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var namespaces map[string][]config.CloudDeployment
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &namespaces))
+	assert.Equal(t, namespace.Clouds, namespaces["team-a"])
+	assert.Equal(t, "deployment-a", orchestratorServer.getCloudDeployment(exampleCloudName, "team-a"))
+
+	// The namespace is stored
+	stored, err := orchestratorServer.allocations.List(context.Background(), store.NamespacesKey)
+	require.NoError(t, err)
+	assert.Len(t, stored, 1)
+
+	// Invalid requests
+	for _, invalid := range []Namespace{
+		{Name: "team-a"},
+		{Name: defaultNamespace},
+		{Name: "Team.B"},
+		{Name: "team-b", Clouds: []config.CloudDeployment{{Name: "other-cloud", Deployment: "deployment"}}},
+		{Name: "team-b", Clouds: []config.CloudDeployment{{Name: exampleCloudName}}},
+		{Name: "team-b", Clouds: []config.CloudDeployment{{Name: exampleCloudName, Deployment: "a"}, {Name: exampleCloudName, Deployment: "b"}}},
+	} {
+		w = sendNamespaceRequest(r, "POST", CreateNamespaceURL, invalid)
+		assert.Equal(t, http.StatusBadRequest, w.Code, invalid.Name)
+	}
+
+	// Bind a new deployment to a cloud
+	url := fmt.Sprintf(GetFormatterString(SetNamespaceCloudURL), "team-a", exampleCloudName)
+	w = sendNamespaceRequest(r, "PUT", url, NamespaceCloud{Deployment: "deployment-b"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "deployment-b", orchestratorServer.getCloudDeployment(exampleCloudName, "team-a"))
+	stored, err = orchestratorServer.allocations.List(context.Background(), store.NamespacesKey)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Contains(t, stored[0], "deployment-b")
+
+	// Namespaces from the config and missing namespaces can't be changed
+	for _, name := range []string{defaultNamespace, "missing"} {
+		url := fmt.Sprintf(GetFormatterString(SetNamespaceCloudURL), name, exampleCloudName)
+		w = sendNamespaceRequest(r, "PUT", url, NamespaceCloud{Deployment: "deployment-b"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	}
+
+	// No warning is needed with a persistent allocation store
+	orchestratorServer.config.AllocationStore.Type = store.FileStoreType
+	w = sendNamespaceRequest(r, "PUT", url, NamespaceCloud{Deployment: "deployment-b"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	resp = NamespaceResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Empty(t, resp.Warnings)
+
+	// Namespaces are loaded from the store on startup
+	restartedServer := newOrchestratorServer()
+	restartedServer.allocations = orchestratorServer.allocations
+	require.NoError(t, restartedServer.loadNamespaces(context.Background()))
+	assert.Equal(t, "deployment-b", restartedServer.getCloudDeployment(exampleCloudName, "team-a"))
+}
+
+func TestDeleteNamespace(t *testing.T) {
+	orchestratorServer := newOrchestratorServer()
+	port := getNewPortNumber()
+	orchestratorServer.pluginAddresses[exampleCloudName] = fmt.Sprintf("localhost:%d", port)
+	orchestratorServer.config.Namespaces = map[string][]config.CloudDeployment{
+		defaultNamespace: {{Name: exampleCloudName, Deployment: "deployment"}},
+	}
+	fakeplugin.SetupFakePluginServer(port)
+
+	r := SetUpRouter()
+	r.POST(CreateNamespaceURL, orchestratorServer.createNamespace)
+	r.DELETE(DeleteNamespaceURL, orchestratorServer.deleteNamespace)
+
+	for _, name := range []string{"team-a", "team-b"} {
+		namespace := Namespace{Name: name, Clouds: []config.CloudDeployment{{Name: exampleCloudName, Deployment: "deployment-" + name}}}
+		w := sendNamespaceRequest(r, "POST", CreateNamespaceURL, namespace)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+
+	// Rules which expire are forgotten along with their namespace
+	rule := &paragliderpb.PermitListRule{Name: "rule", ExpiresAt: time.Now().Add(time.Hour).Unix()}
+	for _, name := range []string{"team-a", "team-b", defaultNamespace} {
+		resource := &ResourceInfo{name: "resource", uri: "uri", cloud: exampleCloudName, namespace: name}
+		require.NoError(t, orchestratorServer.recordRuleExpirations(context.Background(), resource, []*paragliderpb.PermitListRule{rule}))
+	}
+
+	// Delete without and with cascading to the clouds
+	w := sendNamespaceRequest(r, "DELETE", fmt.Sprintf(GetFormatterString(DeleteNamespaceURL), "team-a"), nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = sendNamespaceRequest(r, "DELETE", fmt.Sprintf(GetFormatterString(DeleteNamespaceURL), "team-b")+"?"+CascadeQueryParam+"=true", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	assert.NotContains(t, orchestratorServer.config.Namespaces, "team-a")
+	assert.NotContains(t, orchestratorServer.config.Namespaces, "team-b")
+	stored, err := orchestratorServer.allocations.List(context.Background(), store.NamespacesKey)
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+	require.Len(t, orchestratorServer.ruleExpirations, 1)
+	assert.Contains(t, orchestratorServer.ruleExpirations, ruleExpirationKey{namespace: defaultNamespace, uri: "uri", rule: rule.Name})
+
+	// Namespaces from the config and missing namespaces can't be deleted
+	for _, name := range []string{defaultNamespace, "team-a"} {
+		w = sendNamespaceRequest(r, "DELETE", fmt.Sprintf(GetFormatterString(DeleteNamespaceURL), name), nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	}
+	assert.Contains(t, orchestratorServer.config.Namespaces, defaultNamespace)
+}
@@ -0,0 +1,340 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/auth"
+	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
+)
+
+const (
+	ListOperationsURL  string = "/operations"
+	GetOperationURL    string = "/operations/:id"
+	CancelOperationURL string = "/operations/:id/cancel"
+	AsyncQueryParam    string = "async" // Requests with async=true are run as operations in the background
+	maxOperations      int    = 1000    // Finished operations beyond this many are forgotten (oldest first)
+)
+
+type OperationStatus string
+
+const (
+	OperationRunning   OperationStatus = "RUNNING"
+	OperationSucceeded OperationStatus = "SUCCEEDED"
+	OperationFailed    OperationStatus = "FAILED"
+	OperationCancelled OperationStatus = "CANCELLED"
+)
+
+// Types of long-running operations
+const (
+	CreateResourceOperation           = "CreateResource"
+	AttachResourceOperation           = "AttachResource"
+	DeleteResourceOperation           = "DeleteResource"
+	DetachResourceOperation           = "DetachResource"
+	AddPermitListRulesOperation       = "AddPermitListRules"
+	DeletePermitListRulesOperation    = "DeletePermitListRules"
+	AddTagPermitListRulesOperation    = "AddTagPermitListRules"
+	DeleteTagPermitListRulesOperation = "DeleteTagPermitListRules"
+	ApplyManifestOperation            = "ApplyManifest"
+	CheckDriftOperation               = "CheckDrift"
+	DeleteNamespaceOperation          = "DeleteNamespace"
+)
+
+// RequestNotices holds the warnings and side effects of a request, which are returned along with its result
+type RequestNotices struct {
+	Warnings    []*paragliderpb.Notice `json:"warnings,omitempty"`    // Parts of the request which were ignored or are suspicious
+	SideEffects []*paragliderpb.Notice `json:"sideEffects,omitempty"` // Changes made beyond the ones requested (e.g., VPN gateways created)
+}
+
+func (n *RequestNotices) setNotices(notices *utils.Notices) {
+	n.Warnings = notices.Warnings()
+	n.SideEffects = notices.SideEffects()
+}
+
+// Adds the notices of a request to its result. Requests which have no result of their own get one if there are notices.
+func setResultNotices(result any, notices *utils.Notices) any {
+	switch r := result.(type) {
+	case nil:
+		if len(notices.Warnings()) == 0 && len(notices.SideEffects()) == 0 {
+			return nil
+		}
+		requestNotices := &RequestNotices{}
+		requestNotices.setNotices(notices)
+		return requestNotices
+	case interface{ setNotices(*utils.Notices) }:
+		r.setNotices(notices)
+	default:
+		notices.SetResponseNotices(result)
+	}
+	return result
+}
+
+// Operation is a long-running request to the controller which runs in the background
+type Operation struct {
+	Id        string          `json:"id"`
+	Type      string          `json:"type"`
+	Status    OperationStatus `json:"status"`
+	Progress  string          `json:"progress,omitempty"` // Latest step the operation reached
+	Error     string          `json:"error,omitempty"`
+	Result    json.RawMessage `json:"result,omitempty"`    // Same body as the synchronous response of the request
+	Principal string          `json:"principal,omitempty"` // Who started the operation (if authentication is enabled)
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+	// Recorded even if the operation fails since side effects may have happened before it did
+	RequestNotices
+}
+
+// Done returns true if the operation has finished (successfully or not)
+func (op *Operation) Done() bool {
+	return op.Status != OperationRunning
+}
+
+// operationFunc does the work of an operation and returns its result (which is marshalled to JSON)
+type operationFunc func(ctx context.Context) (any, error)
+
+// operationTracker runs operations and keeps track of them in memory
+type operationTracker struct {
+	lock       sync.Mutex
+	operations map[string]*Operation
+	cancels    map[string]context.CancelFunc
+	order      []string // Operation IDs in the order they were started
+}
+
+func newOperationTracker() *operationTracker {
+	return &operationTracker{
+		operations: make(map[string]*Operation),
+		cancels:    make(map[string]context.CancelFunc),
+	}
+}
+
+type operationContextKey struct{}
+
+// Records the progress of the operation running with ctx (if any)
+func setOperationProgress(ctx context.Context, progress string) {
+	if op, ok := ctx.Value(operationContextKey{}).(*operationProgress); ok {
+		op.tracker.update(op.id, func(operation *Operation) {
+			operation.Progress = progress
+		})
+	}
+}
+
+type operationProgress struct {
+	tracker *operationTracker
+	id      string
+}
+
+// Starts fn in the background on behalf of principal and returns the new operation
+func (t *operationTracker) start(opType string, principal string, fn operationFunc) Operation {
+	now := time.Now()
+	op := &Operation{Id: uuid.New().String(), Type: opType, Status: OperationRunning, Principal: principal, CreatedAt: now, UpdatedAt: now}
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = context.WithValue(ctx, operationContextKey{}, &operationProgress{tracker: t, id: op.Id})
+	ctx, notices := utils.WithNotices(ctx)
+
+	t.lock.Lock()
+	t.operations[op.Id] = op
+	t.cancels[op.Id] = cancel
+	t.order = append(t.order, op.Id)
+	t.evict()
+	started := *op
+	t.lock.Unlock()
+
+	go func() {
+		result, err := fn(ctx)
+		t.finish(ctx, op.Id, result, err, notices)
+		cancel()
+	}()
+
+	return started
+}
+
+// Records the outcome of an operation
+func (t *operationTracker) finish(ctx context.Context, id string, result any, err error, notices *utils.Notices) {
+	var resultJSON []byte
+	if err == nil && result != nil {
+		resultJSON, err = json.Marshal(setResultNotices(result, notices))
+	}
+	t.update(id, func(op *Operation) {
+		op.setNotices(notices)
+		switch {
+		case err == nil:
+			op.Status = OperationSucceeded
+			op.Result = resultJSON
+		case ctx.Err() != nil:
+			op.Status = OperationCancelled
+			op.Error = err.Error()
+		default:
+			op.Status = OperationFailed
+			op.Error = err.Error()
+		}
+	})
+	t.lock.Lock()
+	delete(t.cancels, id)
+	t.lock.Unlock()
+}
+
+// Applies fn to an operation
+func (t *operationTracker) update(id string, fn func(*Operation)) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if op, ok := t.operations[id]; ok {
+		fn(op)
+		op.UpdatedAt = time.Now()
+	}
+}
+
+// Forgets the oldest finished operations once there are too many (must be called with the lock held)
+func (t *operationTracker) evict() {
+	excess := len(t.order) - maxOperations
+	kept := t.order[:0]
+	for _, id := range t.order {
+		if excess > 0 && t.operations[id].Done() {
+			delete(t.operations, id)
+			excess--
+			continue
+		}
+		kept = append(kept, id)
+	}
+	t.order = kept
+}
+
+func (t *operationTracker) get(id string) (Operation, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	op, ok := t.operations[id]
+	if !ok {
+		return Operation{}, false
+	}
+	return *op, true
+}
+
+func (t *operationTracker) list() []Operation {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	operations := make([]Operation, 0, len(t.order))
+	for _, id := range t.order {
+		operations = append(operations, *t.operations[id])
+	}
+	return operations
+}
+
+// Cancels a running operation. Cancellation is best effort: the operation stops at the next request it makes,
+// but changes which were already made in the clouds are not rolled back.
+func (t *operationTracker) cancel(id string) (Operation, error) {
+	t.lock.Lock()
+	op, ok := t.operations[id]
+	if !ok {
+		t.lock.Unlock()
+		return Operation{}, fmt.Errorf("operation %s not found", id)
+	}
+	cancel, running := t.cancels[id]
+	cancelled := *op
+	t.lock.Unlock()
+	if !running {
+		return Operation{}, fmt.Errorf("operation %s has already finished", id)
+	}
+	cancel()
+	return cancelled, nil
+}
+
+// Runs an operation in the background if the request asks for it (responding with the operation to poll)
+// or inline otherwise (responding with its result)
+func (s *ControllerServer) runOperation(c *gin.Context, opType string, fn operationFunc) {
+	fn = s.auditOperation(c, opType, fn)
+	fn = logOperation(utils.LogAttrs(c.Request.Context()), opType, fn)
+	fn = traceOperation(trace.SpanContextFromContext(c.Request.Context()), opType, fn)
+	if async, _ := strconv.ParseBool(c.Query(AsyncQueryParam)); async {
+		principal := ""
+		if p := getPrincipal(c); p != nil {
+			principal = p.Name
+		}
+		c.JSON(http.StatusAccepted, s.operations.start(opType, principal, fn))
+		return
+	}
+
+	// Requests which wait for their operation cancel it if the client goes away
+	ctx, notices := utils.WithNotices(c.Request.Context())
+	result, err := fn(ctx)
+	if err != nil {
+		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
+		return
+	}
+	if result = setResultNotices(result, notices); result != nil {
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// Records an operation as a span in the trace of the request which started it. Only the span context is taken from the
+// request since operations outlive requests when they run in the background.
+func traceOperation(requestSpan trace.SpanContext, opType string, fn operationFunc) operationFunc {
+	return func(ctx context.Context) (any, error) {
+		ctx, span := tracer.Start(trace.ContextWithSpanContext(ctx, requestSpan), opType)
+		defer span.End()
+		result, err := fn(ctx)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return result, err
+	}
+}
+
+// Get the status of an operation
+func (s *ControllerServer) getOperation(c *gin.Context) {
+	op, ok := s.operations.get(c.Param("id"))
+	if !ok || !s.authorizedForOperation(c, auth.ReadAction, op) {
+		c.AbortWithStatusJSON(404, createErrorResponse(fmt.Sprintf("operation %s not found", c.Param("id"))))
+		return
+	}
+	c.JSON(http.StatusOK, op)
+}
+
+// List all operations known to the controller (which the principal may see)
+func (s *ControllerServer) listOperations(c *gin.Context) {
+	operations := slices.DeleteFunc(s.operations.list(), func(op Operation) bool {
+		return !s.authorizedForOperation(c, auth.ReadAction, op)
+	})
+	c.JSON(http.StatusOK, operations)
+}
+
+// Cancel a running operation
+func (s *ControllerServer) cancelOperation(c *gin.Context) {
+	if op, ok := s.operations.get(c.Param("id")); !ok || !s.authorizedForOperation(c, auth.AdminAction, op) {
+		c.AbortWithStatusJSON(404, createErrorResponse(fmt.Sprintf("operation %s not found", c.Param("id"))))
+		return
+	}
+	op, err := s.operations.cancel(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, op)
+}
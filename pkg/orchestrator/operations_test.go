@@ -0,0 +1,295 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	fakeplugin "github.com/paraglider-project/paraglider/pkg/fake/cloudplugin"
+	faketagservice "github.com/paraglider-project/paraglider/pkg/fake/tagservice"
+	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
+)
+
+// Waits for an operation to finish and returns it
+func waitForOperation(t *testing.T, tracker *operationTracker, id string) Operation {
+	var op Operation
+	require.Eventually(t, func() bool {
+		var ok bool
+		op, ok = tracker.get(id)
+		require.True(t, ok)
+		return op.Done()
+	}, 5*time.Second, 10*time.Millisecond)
+	return op
+}
+
+func TestOperationTracker(t *testing.T) {
+	tracker := newOperationTracker()
+
+	// Successful operation
+	op := tracker.start(CreateResourceOperation, "", func(ctx context.Context) (any, error) {
+		setOperationProgress(ctx, "Halfway there")
+		return map[string]string{"name": "resource"}, nil
+	})
+	assert.Equal(t, CreateResourceOperation, op.Type)
+	op = waitForOperation(t, tracker, op.Id)
+	assert.Equal(t, OperationSucceeded, op.Status)
+	assert.Equal(t, "Halfway there", op.Progress)
+	assert.JSONEq(t, `{"name": "resource"}`, string(op.Result))
+	assert.Empty(t, op.Error)
+
+	// Failed operation
+	op = tracker.start(DeleteResourceOperation, "", func(ctx context.Context) (any, error) {
+		return nil, fmt.Errorf("plugin unavailable")
+	})
+	op = waitForOperation(t, tracker, op.Id)
+	assert.Equal(t, OperationFailed, op.Status)
+	assert.Equal(t, "plugin unavailable", op.Error)
+
+	// Cancelled operation
+	op = tracker.start(AttachResourceOperation, "", func(ctx context.Context) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	_, err := tracker.cancel(op.Id)
+	require.NoError(t, err)
+	op = waitForOperation(t, tracker, op.Id)
+	assert.Equal(t, OperationCancelled, op.Status)
+
+	// Finished operations can't be cancelled
+	_, err = tracker.cancel(op.Id)
+	require.Error(t, err)
+	_, err = tracker.cancel("missing")
+	require.Error(t, err)
+
+	// Operations are listed in the order they were started
+	operations := tracker.list()
+	require.Len(t, operations, 3)
+	assert.Equal(t, CreateResourceOperation, operations[0].Type)
+	assert.Equal(t, AttachResourceOperation, operations[2].Type)
+}
+
+func TestOperationTrackerEviction(t *testing.T) {
+	tracker := newOperationTracker()
+	for i := 0; i < maxOperations+1; i++ {
+		id := fmt.Sprintf("op-%d", i)
+		status := OperationSucceeded
+		if i == 0 {
+			status = OperationRunning
+		}
+		tracker.operations[id] = &Operation{Id: id, Status: status}
+		tracker.order = append(tracker.order, id)
+	}
+	tracker.evict()
+
+	// The oldest finished operation is forgotten, while the running one is kept
+	require.Len(t, tracker.order, maxOperations)
+	_, ok := tracker.get("op-0")
+	assert.True(t, ok)
+	_, ok = tracker.get("op-1")
+	assert.False(t, ok)
+}
+
+func TestCreateResourceAsync(t *testing.T) {
+	// Setup
+	orchestratorServer := newOrchestratorServer()
+	port := getNewPortNumber()
+	tagServerPort := getNewPortNumber()
+	orchestratorServer.localTagService = fmt.Sprintf("localhost:%d", tagServerPort)
+	orchestratorServer.pluginAddresses[exampleCloudName] = fmt.Sprintf("localhost:%d", port)
+
+	fakeplugin.SetupFakePluginServer(port)
+	faketagservice.SetupFakeTagServer(tagServerPort)
+
+	r := SetUpRouter()
+	r.PUT(CreateResourcePUTURL, orchestratorServer.handleCreateOrAttachResource)
+	r.GET(ListOperationsURL, orchestratorServer.listOperations)
+	r.GET(GetOperationURL, orchestratorServer.getOperation)
+	r.POST(CancelOperationURL, orchestratorServer.cancelOperation)
+
+	// Start the operation
+	resource := &paragliderpb.ResourceDescriptionString{Description: "description"}
+	jsonValue, _ := json.Marshal(resource)
+	url := fmt.Sprintf(GetFormatterString(CreateResourcePUTURL), defaultNamespace, exampleCloudName, "resource-name") + "?" + AsyncQueryParam + "=true"
+	req, _ := http.NewRequest("PUT", url, bytes.NewBuffer(jsonValue))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var op Operation
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &op))
+	assert.Equal(t, CreateResourceOperation, op.Type)
+
+	// Poll the operation until it finishes
+	require.Eventually(t, func() bool {
+		req, _ := http.NewRequest("GET", fmt.Sprintf(GetFormatterString(GetOperationURL), op.Id), nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &op))
+		return op.Done()
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, OperationSucceeded, op.Status, op.Error)
+	resourceResp := &paragliderpb.CreateResourceResponse{}
+	require.NoError(t, json.Unmarshal(op.Result, resourceResp))
+	assert.Equal(t, getTagName(defaultNamespace, exampleCloudName, "resource-name"), resourceResp.Name)
+
+	// List operations
+	req, _ = http.NewRequest("GET", ListOperationsURL, nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var operations []Operation
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &operations))
+	require.Len(t, operations, 1)
+	assert.Equal(t, op.Id, operations[0].Id)
+
+	// Finished operations can't be cancelled
+	req, _ = http.NewRequest("POST", fmt.Sprintf(GetFormatterString(CancelOperationURL), op.Id), nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Unknown operation
+	for _, method := range []string{"GET", "POST"} {
+		url := fmt.Sprintf(GetFormatterString(GetOperationURL), "missing")
+		if method == "POST" {
+			url = fmt.Sprintf(GetFormatterString(CancelOperationURL), "missing")
+		}
+		req, _ = http.NewRequest(method, url, nil)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	}
+}
+
+func TestRunOperationSync(t *testing.T) {
+	orchestratorServer := newOrchestratorServer()
+	r := SetUpRouter()
+	r.GET("/test", func(c *gin.Context) {
+		orchestratorServer.runOperation(c, CreateResourceOperation, func(ctx context.Context) (any, error) {
+			if c.Query("fail") != "" {
+				return nil, fmt.Errorf("failed")
+			}
+			return gin.H{"name": "resource"}, nil
+		})
+	})
+
+	// Without async=true the result is the response
+	req, _ := http.NewRequest("GET", "/test", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"name": "resource"}`, w.Body.String())
+
+	req, _ = http.NewRequest("GET", "/test?fail=true", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// No operations are recorded
+	assert.Empty(t, orchestratorServer.operations.list())
+
+	// The operation is cancelled along with the request
+	r.GET("/cancelled", func(c *gin.Context) {
+		orchestratorServer.runOperation(c, CreateResourceOperation, func(ctx context.Context) (any, error) {
+			return nil, ctx.Err()
+		})
+	})
+	reqCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ = http.NewRequestWithContext(reqCtx, "GET", "/cancelled", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), context.Canceled.Error())
+}
+
+func TestRequestNotices(t *testing.T) {
+	// Setup
+	orchestratorServer := newOrchestratorServer()
+	tagServerPort := getNewPortNumber()
+	cloudPluginPort := getNewPortNumber()
+	orchestratorServer.pluginAddresses[exampleCloudName] = fmt.Sprintf("localhost:%d", cloudPluginPort)
+	orchestratorServer.localTagService = fmt.Sprintf("localhost:%d", tagServerPort)
+
+	fakeplugin.SetupFakePluginServer(cloudPluginPort)
+	faketagservice.SetupFakeTagServer(tagServerPort)
+
+	r := SetUpRouter()
+	r.POST(AddPermitListRulesURL, orchestratorServer.permitListRulesBulkAdd)
+	r.GET(GetOperationURL, orchestratorServer.getOperation)
+
+	// The rule duplicates the one the resource already has and comes with targets
+	rule := proto.Clone(fakeplugin.ExampleRule).(*paragliderpb.PermitListRule)
+	rule.Name = "copy-rule"
+	rule.Targets = []string{"5.6.7.8"}
+	jsonValue, _ := json.Marshal([]*paragliderpb.PermitListRule{rule})
+	url := fmt.Sprintf(GetFormatterString(AddPermitListRulesURL), defaultNamespace, exampleCloudName, faketagservice.ValidLastLevelTagName)
+	expected := RequestNotices{Warnings: []*paragliderpb.Notice{
+		{Message: "targets for rule copy-rule ignored", Resource: faketagservice.TagUri},
+		{Message: "rule copy-rule duplicates existing rule example-rule", Resource: faketagservice.TagUri},
+	}}
+
+	// Synchronous requests respond with the notices
+	req, _ := http.NewRequest("POST", url, bytes.NewBuffer(jsonValue))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var notices RequestNotices
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &notices))
+	assert.Equal(t, len(expected.Warnings), len(notices.Warnings))
+	for i := range expected.Warnings {
+		assert.True(t, proto.Equal(expected.Warnings[i], notices.Warnings[i]), notices.Warnings[i])
+	}
+	assert.Empty(t, notices.SideEffects)
+
+	// Operations record them
+	req, _ = http.NewRequest("POST", url+"?"+AsyncQueryParam+"=true", bytes.NewBuffer(jsonValue))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var op Operation
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &op))
+	op = waitForOperation(t, orchestratorServer.operations, op.Id)
+	require.Equal(t, OperationSucceeded, op.Status, op.Error)
+	require.Len(t, op.Warnings, len(expected.Warnings))
+	for i := range expected.Warnings {
+		assert.True(t, proto.Equal(expected.Warnings[i], op.Warnings[i]), op.Warnings[i])
+	}
+
+	// Rules without anything to report get no response body
+	rule = proto.Clone(fakeplugin.ExampleRule).(*paragliderpb.PermitListRule)
+	jsonValue, _ = json.Marshal([]*paragliderpb.PermitListRule{rule})
+	req, _ = http.NewRequest("POST", url, bytes.NewBuffer(jsonValue))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
+}
@@ -20,26 +20,33 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"math/rand"
 	"net"
 	"net/http"
 	"net/netip"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"gopkg.in/yaml.v2"
 
 	"github.com/gin-gonic/gin"
 	"github.com/seancfoley/ipaddress-go/ipaddr"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
 	grpc "google.golang.org/grpc"
-	insecure "google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/paraglider-project/paraglider/pkg/kvstore/storepb"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/auth"
 	config "github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+	store "github.com/paraglider-project/paraglider/pkg/orchestrator/store"
 	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
 	tagservicepb "github.com/paraglider-project/paraglider/pkg/tag_service/tagservicepb"
 	utils "github.com/paraglider-project/paraglider/pkg/utils"
@@ -53,6 +60,8 @@ const (
 	DeletePermitListRulesURL      string = "/namespaces/:namespace/clouds/:cloud/resources/:resourceName/deleteRules"
 	CreateResourcePUTURL          string = "/namespaces/:namespace/clouds/:cloud/resources/:resourceName"
 	CreateOrAttachResourcePOSTURL string = "/namespaces/:namespace/clouds/:cloud/resources"
+	DeleteResourceURL             string = "/namespaces/:namespace/clouds/:cloud/resources/:resourceName"
+	DetachResourceURL             string = "/namespaces/:namespace/clouds/:cloud/resources/:resourceName/detach"
 	RuleOnTagURL                  string = "/tags/:tag/rules"
 	ListTagURL                    string = "/tags"
 	GetTagURL                     string = "/tags/:tag"
@@ -71,15 +80,30 @@ type Warning struct {
 
 type ControllerServer struct {
 	paragliderpb.UnimplementedControllerServer
-	pluginAddresses           map[string]string
-	usedAddressSpaces         []*paragliderpb.AddressSpaceMapping
-	usedAsns                  []uint32
-	usedBgpPeeringIpAddresses map[string][]string
-	localTagService           string
-	localKVStoreService       string
-	config                    config.Config
-	namespace                 string
-	addressRequest            sync.Mutex
+	pluginAddresses            map[string]string
+	plugins                    map[string]*pluginState // Health, version and capabilities of the plugins
+	pluginsLock                sync.RWMutex            // Guards pluginAddresses and plugins
+	usedAddressSpaces          []*paragliderpb.AddressSpaceMapping
+	usedAsns                   []uint32
+	usedBgpPeeringIpAddresses  map[string][]string
+	localTagService            string
+	localKVStoreService        string
+	config                     config.Config
+	createdNamespaces          map[string]string // Namespaces created through the API and how they are recorded in the allocation store
+	namespacesLock             sync.RWMutex      // Guards config.Namespaces and createdNamespaces
+	namespace                  string
+	allocations                store.AllocationStore // Records address spaces, ASNs and BGP peering IP addresses handed out to plugins
+	operations                 *operationTracker     // Long-running requests run in the background
+	drift                      driftDetector
+	auth                       *auth.Manager                    // nil if authentication is disabled
+	grpcCredentials            credentials.TransportCredentials // Used on the gRPC links with the other services (plaintext if nil)
+	pluginConns                *utils.ConnPool                  // Long-lived connections to the plugins
+	serviceConns               *utils.ConnPool                  // Long-lived connections to the tag service and KV store
+	addressRequest             sync.Mutex
+	asnRequest                 sync.Mutex
+	bgpPeeringIpAddressRequest sync.Mutex
+	ruleExpirations            map[ruleExpirationKey]*ruleExpiration // Rules deleted by the orchestrator once they expire
+	ruleExpirationsLock        sync.Mutex                            // Guards ruleExpirations
 }
 
 type ResourceInfo struct {
@@ -135,9 +159,16 @@ func checkAndCleanRule(rule *paragliderpb.PermitListRule) (*paragliderpb.PermitL
 	if len(rule.Tags) == 0 {
 		return nil, nil, fmt.Errorf("rule %s contains no tags", rule.Name)
 	}
+	if err := utils.ValidatePortRanges(rule); err != nil {
+		return nil, nil, err
+	}
+	if err := utils.ValidateRulePriority(rule); err != nil {
+		return nil, nil, err
+	}
+	utils.NormalizePorts(rule)
 	if len(rule.Targets) != 0 {
 		rule.Targets = []string{}
-		return rule, &Warning{Message: fmt.Sprintf("Warning: targets for rule %s ignored", rule.Name)}, nil
+		return rule, &Warning{Message: fmt.Sprintf("targets for rule %s ignored", rule.Name)}, nil
 	}
 	return rule, nil, nil
 }
@@ -173,16 +204,15 @@ func isTagValid(tag *tagservicepb.TagMapping) bool {
 }
 
 // Get the URI of a tag
-func (s *ControllerServer) getTagUri(tag string) (string, error) {
-	conn, err := grpc.NewClient(s.localTagService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+func (s *ControllerServer) getTagUri(ctx context.Context, tag string) (string, error) {
+	conn, err := s.serviceConns.Get(s.localTagService)
 	if err != nil {
 		return "", fmt.Errorf("could not contact tag server: %s", err.Error())
 	}
-	defer conn.Close()
 
 	// Send RPC to get tag
 	client := tagservicepb.NewTagServiceClient(conn)
-	response, err := client.GetTag(context.Background(), &tagservicepb.GetTagRequest{TagName: tag})
+	response, err := client.GetTag(ctx, &tagservicepb.GetTagRequest{TagName: tag})
 	if err != nil {
 		return "", fmt.Errorf("could not get tag: %s", err.Error())
 	}
@@ -200,13 +230,13 @@ func (s *ControllerServer) getAndValidateResourceURLParams(c *gin.Context, resol
 	namespace := c.Param("namespace")
 
 	// Ensure correct cloud name
-	cloudClient, ok := s.pluginAddresses[cloud]
+	cloudClient, ok := s.getPluginAddress(cloud)
 	if !ok {
 		return nil, "", fmt.Errorf("invalid cloud name: %s", cloud)
 	}
 
 	if resolveTag {
-		uri, err := s.getTagUri(getTagName(namespace, cloud, tag))
+		uri, err := s.getTagUri(c.Request.Context(), getTagName(namespace, cloud, tag))
 		if err != nil {
 			return nil, "", err
 		}
@@ -218,32 +248,34 @@ func (s *ControllerServer) getAndValidateResourceURLParams(c *gin.Context, resol
 }
 
 // Takes a set of permit list rules and returns the same list with all tags referenced in the original rules resolved to IPs
-func (s *ControllerServer) resolvePermitListRules(rules []*paragliderpb.PermitListRule, resource *ResourceInfo, subscribe bool) ([]*paragliderpb.PermitListRule, error) {
+func (s *ControllerServer) resolvePermitListRules(ctx context.Context, rules []*paragliderpb.PermitListRule, resource *ResourceInfo, subscribe bool) ([]*paragliderpb.PermitListRule, error) {
+	conn, err := s.serviceConns.Get(s.localTagService)
+	if err != nil {
+		return nil, fmt.Errorf("could not contact tag server: %s", err.Error())
+	}
+	client := tagservicepb.NewTagServiceClient(conn)
+
 	for _, rule := range rules {
 		// Check rule validity and clean fields
-		rule, _, err := checkAndCleanRule(rule) // TODO @smcclure20: use the warning and report it to the user
+		rule, warning, err := checkAndCleanRule(rule)
 		if err != nil {
 			return nil, fmt.Errorf("invalid rule: %s", err.Error())
 		}
+		if warning != nil {
+			utils.AddWarning(ctx, resource.uri, warning.Message)
+		}
 
 		for _, tag := range rule.Tags {
 			if !isIpAddrOrCidr(tag) {
-				conn, err := grpc.NewClient(s.localTagService, grpc.WithTransportCredentials(insecure.NewCredentials()))
-				if err != nil {
-					return nil, fmt.Errorf("could not contact tag server: %s", err.Error())
-				}
-				defer conn.Close()
-
 				// Send RPC to resolve tag
-				client := tagservicepb.NewTagServiceClient(conn)
-				resolvedTag, err := client.ResolveTag(context.Background(), &tagservicepb.ResolveTagRequest{TagName: tag})
+				resolvedTag, err := client.ResolveTag(ctx, &tagservicepb.ResolveTagRequest{TagName: tag})
 				if err != nil {
 					return nil, fmt.Errorf("could not resolve tag: %s", err.Error())
 				}
 
 				// Subscribe self to tag
 				if subscribe {
-					_, err := client.Subscribe(context.Background(),
+					_, err := client.Subscribe(ctx,
 						&tagservicepb.SubscribeRequest{Subscription: &tagservicepb.Subscription{TagName: tag,
 							Subscriber: createSubscriberName(resource.namespace, resource.cloud, resource.uri)}})
 					if err != nil {
@@ -251,7 +283,9 @@ func (s *ControllerServer) resolvePermitListRules(rules []*paragliderpb.PermitLi
 					}
 				}
 
-				rule.Targets = append(rule.Targets, getIPsFromResolvedTag(resolvedTag.Tags)...)
+				ips := getIPsFromResolvedTag(resolvedTag.Tags)
+				tagResolutionFanout.WithLabelValues(ruleTargetsFanout).Observe(float64(len(ips)))
+				rule.Targets = append(rule.Targets, ips...)
 			} else {
 				rule.Targets = append(rule.Targets, tag)
 			}
@@ -261,22 +295,22 @@ func (s *ControllerServer) resolvePermitListRules(rules []*paragliderpb.PermitLi
 }
 
 // Get permit list with ID from plugin
-func (s *ControllerServer) _permitListGet(namespace string, resourceId string, pluginAddress string) (*paragliderpb.GetPermitListResponse, error) {
+func (s *ControllerServer) _permitListGet(ctx context.Context, namespace string, resourceId string, pluginAddress string) (*paragliderpb.GetPermitListResponse, error) {
 	// Connect to the cloud plugin
-	conn, err := grpc.NewClient(pluginAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.pluginConns.Get(pluginAddress)
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
 
 	// Send the GetPermitList RPC
 	client := paragliderpb.NewCloudPluginClient(conn)
 	emptyresourceId := paragliderpb.GetPermitListRequest{Resource: resourceId, Namespace: namespace}
 
-	response, err := client.GetPermitList(context.Background(), &emptyresourceId)
+	response, err := client.GetPermitList(ctx, &emptyresourceId)
 	if err != nil {
 		return nil, err
 	}
+	s.setRuleExpirationTimes(namespace, resourceId, response.Rules)
 
 	return response, nil
 }
@@ -289,7 +323,7 @@ func (s *ControllerServer) permitListGet(c *gin.Context) {
 		return
 	}
 
-	response, err := s._permitListGet(resourceInfo.namespace, resourceInfo.uri, cloudClient)
+	response, err := s._permitListGet(c.Request.Context(), resourceInfo.namespace, resourceInfo.uri, cloudClient)
 	if err != nil {
 		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
 		return
@@ -299,30 +333,87 @@ func (s *ControllerServer) permitListGet(c *gin.Context) {
 }
 
 // Add rules to a resource specified in the permit list in the given cloud
-func (s *ControllerServer) _permitListRulesAdd(req *paragliderpb.AddPermitListRulesRequest, resource *ResourceInfo, pluginAddress string) (*paragliderpb.AddPermitListRulesResponse, error) {
+func (s *ControllerServer) _permitListRulesAdd(ctx context.Context, req *paragliderpb.AddPermitListRulesRequest, resource *ResourceInfo, pluginAddress string) (*paragliderpb.AddPermitListRulesResponse, error) {
+	if err := s.checkRulesSupported(ctx, resource, pluginAddress, req.Rules); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, rule := range req.Rules {
+		if err := setRuleExpiration(rule, now); err != nil {
+			return nil, fmt.Errorf("invalid rule: %s", err.Error())
+		}
+	}
+
 	// Resolve tags referenced in rules
-	rules, err := s.resolvePermitListRules(req.Rules, resource, true)
+	rules, err := s.resolvePermitListRules(ctx, req.Rules, resource, true)
 	if err != nil {
 		return nil, err
 	}
 	req.Rules = rules
+	duplicates := s.findDuplicateRules(ctx, resource, pluginAddress, req.Rules)
+
 	// Create connection to cloud plugin
-	conn, err := grpc.NewClient(pluginAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.pluginConns.Get(pluginAddress)
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
 
 	// Send RPC to create rules
+	setOperationProgress(ctx, "Adding permit list rules")
 	client := paragliderpb.NewCloudPluginClient(conn)
-	response, err := client.AddPermitListRules(context.Background(), req)
+	response, err := client.AddPermitListRules(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.recordRuleExpirations(ctx, resource, req.Rules); err != nil {
+		return nil, fmt.Errorf("could not record rule expirations: %w", err)
+	}
+	if err := s.recordExpectedRules(ctx, resource, req.Rules); err != nil {
+		return nil, fmt.Errorf("could not record expected rules: %w", err)
+	}
+	for _, rule := range req.Rules {
+		if duplicate, ok := duplicates[rule.Name]; ok {
+			utils.AddWarning(ctx, resource.uri, fmt.Sprintf("rule %s duplicates existing rule %s", rule.Name, duplicate))
+		}
+	}
 	return response, nil
 }
 
+// Find the rules being added which are the same as another rule of the resource (or of the request) under a different
+// name, returning the name of that rule for each. Only done if someone is told about them.
+func (s *ControllerServer) findDuplicateRules(ctx context.Context, resource *ResourceInfo, pluginAddress string, rules []*paragliderpb.PermitListRule) map[string]string {
+	duplicates := make(map[string]string)
+	if !utils.CollectsNotices(ctx) {
+		return duplicates
+	}
+	permitList, err := s._permitListGet(ctx, resource.namespace, resource.uri, pluginAddress)
+	if err != nil {
+		slog.WarnContext(ctx, "Could not get permit list to check for duplicate rules", "resource", resource.uri, "error", err)
+		return duplicates
+	}
+
+	// Rules are compared by what they permit rather than how they are named or how long they last
+	withoutName := func(rule *paragliderpb.PermitListRule) *paragliderpb.PermitListRule {
+		rule = proto.Clone(rule).(*paragliderpb.PermitListRule)
+		rule.Name = ""
+		rule.ExpiresAt = 0
+		rule.TtlSeconds = 0
+		return rule
+	}
+	existing := permitList.Rules
+	for _, rule := range rules {
+		for _, other := range existing {
+			if other.Name != rule.Name && permitListRulesEqual(withoutName(rule), withoutName(other)) {
+				duplicates[rule.Name] = other.Name
+				break
+			}
+		}
+		existing = append(existing, rule)
+	}
+	return duplicates
+}
+
 // Add permit list rules to specified resource
 func (s *ControllerServer) permitListRulesBulkAdd(c *gin.Context) {
 	resourceInfo, cloudClient, err := s.getAndValidateResourceURLParams(c, true)
@@ -340,11 +431,10 @@ func (s *ControllerServer) permitListRulesBulkAdd(c *gin.Context) {
 
 	request := &paragliderpb.AddPermitListRulesRequest{Rules: rules, Namespace: resourceInfo.namespace, Resource: resourceInfo.uri}
 
-	_, err = s._permitListRulesAdd(request, resourceInfo, cloudClient)
-	if err != nil {
-		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-		return
-	}
+	s.runOperation(c, AddPermitListRulesOperation, func(ctx context.Context) (any, error) {
+		_, err := s._permitListRulesAdd(ctx, request, resourceInfo, cloudClient)
+		return nil, err
+	})
 }
 
 // Add a single rule to a resource permit list
@@ -374,11 +464,10 @@ func (s *ControllerServer) permitListRuleAdd(c *gin.Context) {
 
 	request := &paragliderpb.AddPermitListRulesRequest{Rules: []*paragliderpb.PermitListRule{rule}, Namespace: resourceInfo.namespace, Resource: resourceInfo.uri}
 
-	_, err = s._permitListRulesAdd(request, resourceInfo, cloudClient)
-	if err != nil {
-		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-		return
-	}
+	s.runOperation(c, AddPermitListRulesOperation, func(ctx context.Context) (any, error) {
+		_, err := s._permitListRulesAdd(ctx, request, resourceInfo, cloudClient)
+		return nil, err
+	})
 }
 
 // Add permit list rules to all resources within a tag
@@ -392,60 +481,79 @@ func (s *ControllerServer) permitListRuleAddTag(c *gin.Context) {
 		return
 	}
 
+	s.runOperation(c, AddTagPermitListRulesOperation, func(ctx context.Context) (any, error) {
+		return nil, s._permitListRuleAddTag(ctx, tag, rule)
+	})
+}
+
+func (s *ControllerServer) _permitListRuleAddTag(ctx context.Context, tag string, rule *paragliderpb.PermitListRule) error {
+	if err := setRuleExpiration(rule, time.Now()); err != nil {
+		return fmt.Errorf("invalid rule: %s", err.Error())
+	}
+
 	// Resolve the tag to URIs
-	conn, err := grpc.NewClient(s.localTagService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.serviceConns.Get(s.localTagService)
 	if err != nil {
-		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-		return
+		return err
 	}
-	defer conn.Close()
 
 	// Send RPC to resolve tag
 	client := tagservicepb.NewTagServiceClient(conn)
-	resolvedTag, err := client.ResolveTag(context.Background(), &tagservicepb.ResolveTagRequest{TagName: tag})
+	resolvedTag, err := client.ResolveTag(ctx, &tagservicepb.ResolveTagRequest{TagName: tag})
 	if err != nil {
-		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-		return
+		return err
 	}
 
-	initializedClientConns := make(map[string]*grpc.ClientConn)
+	tagResolutionFanout.WithLabelValues(tagRuleAddFanout).Observe(float64(len(resolvedTag.Tags)))
+
+	// Check every cloud in the tag supports the rule before adding it anywhere
+	for _, mapping := range resolvedTag.Tags {
+		_, cloud, _, err := parseTag(mapping.Name)
+		if err != nil {
+			return err
+		}
+		capabilities, err := s.getCapabilities(ctx, cloud)
+		if err != nil {
+			return err
+		}
+		if err := checkRuleSupported(cloud, rule, capabilities); err != nil {
+			return err
+		}
+	}
 
 	// Add rule to each URI in the resolved tag
 	for _, mapping := range resolvedTag.Tags {
 		// Get the cloud and namespace from the tag
-		namespace, cloud, _, err := parseTag(mapping.Name)
+		namespace, cloud, name, err := parseTag(mapping.Name)
 		if err != nil {
-			c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-			return
+			return err
 		}
 
-		// Create  or get connection to cloud plugin
-		conn, ok := initializedClientConns[cloud]
+		cloudClientAddress, ok := s.getPluginAddress(cloud)
 		if !ok {
-			cloudClientAddress, ok := s.pluginAddresses[cloud]
-			if !ok {
-				c.AbortWithStatusJSON(400, createErrorResponse("invalid cloud name"))
-				return
-			}
-
-			conn, err = grpc.NewClient(cloudClientAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
-			if err != nil {
-				c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-				return
-			}
-			defer conn.Close()
-
-			initializedClientConns[cloud] = conn
+			return fmt.Errorf("invalid cloud name")
+		}
+		conn, err := s.pluginConns.Get(cloudClientAddress)
+		if err != nil {
+			return err
 		}
 
 		// Send RPC to add rule
+		setOperationProgress(ctx, fmt.Sprintf("Adding permit list rule to %s", mapping.Name))
 		client := paragliderpb.NewCloudPluginClient(conn)
-		_, err = client.AddPermitListRules(context.Background(), &paragliderpb.AddPermitListRulesRequest{Rules: []*paragliderpb.PermitListRule{rule}, Namespace: namespace, Resource: *mapping.Uri})
+		_, err = client.AddPermitListRules(ctx, &paragliderpb.AddPermitListRulesRequest{Rules: []*paragliderpb.PermitListRule{rule}, Namespace: namespace, Resource: *mapping.Uri})
 		if err != nil {
-			c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-			return
+			return err
+		}
+		resource := &ResourceInfo{name: name, uri: *mapping.Uri, cloud: cloud, namespace: namespace}
+		if err := s.recordRuleExpirations(ctx, resource, []*paragliderpb.PermitListRule{rule}); err != nil {
+			return fmt.Errorf("could not record rule expirations: %w", err)
+		}
+		if err := s.recordExpectedRules(ctx, resource, []*paragliderpb.PermitListRule{rule}); err != nil {
+			return fmt.Errorf("could not record expected rules: %w", err)
 		}
 	}
+	return nil
 }
 
 // Delete permit list rules to from resources within a tag
@@ -459,52 +567,60 @@ func (s *ControllerServer) permitListRuleDeleteTag(c *gin.Context) {
 		return
 	}
 
+	s.runOperation(c, DeleteTagPermitListRulesOperation, func(ctx context.Context) (any, error) {
+		return nil, s._permitListRulesDeleteTag(ctx, tag, rules)
+	})
+}
+
+func (s *ControllerServer) _permitListRulesDeleteTag(ctx context.Context, tag string, rules []string) error {
 	// Resolve the tag to URIs
-	conn, err := grpc.NewClient(s.localTagService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.serviceConns.Get(s.localTagService)
 	if err != nil {
-		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-		return
+		return err
 	}
-	defer conn.Close()
 
 	// Send RPC to resolve tag
 	client := tagservicepb.NewTagServiceClient(conn)
-	resolvedTag, err := client.ResolveTag(context.Background(), &tagservicepb.ResolveTagRequest{TagName: tag})
+	resolvedTag, err := client.ResolveTag(ctx, &tagservicepb.ResolveTagRequest{TagName: tag})
 	if err != nil {
-		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-		return
+		return err
 	}
+	tagResolutionFanout.WithLabelValues(tagRuleDeleteFanout).Observe(float64(len(resolvedTag.Tags)))
 
 	// Add rule to each URI in the resolved tag
 	for _, mapping := range resolvedTag.Tags {
 		// Get the cloud and namespace from the tag
-		namespace, cloud, _, err := parseTag(mapping.Name)
+		namespace, cloud, name, err := parseTag(mapping.Name)
 		if err != nil {
-			c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-			return
+			return err
 		}
 
 		// Create connection to cloud plugin
-		cloudClient, ok := s.pluginAddresses[cloud]
+		cloudClient, ok := s.getPluginAddress(cloud)
 		if !ok {
-			c.AbortWithStatusJSON(400, createErrorResponse("invalid cloud name"))
-			return
+			return fmt.Errorf("invalid cloud name")
 		}
-		conn, err := grpc.NewClient(cloudClient, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		conn, err := s.pluginConns.Get(cloudClient)
 		if err != nil {
-			c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-			return
+			return err
 		}
-		defer conn.Close()
 
 		// Send RPC to add rule
+		setOperationProgress(ctx, fmt.Sprintf("Deleting permit list rules from %s", mapping.Name))
 		client := paragliderpb.NewCloudPluginClient(conn)
-		_, err = client.DeletePermitListRules(context.Background(), &paragliderpb.DeletePermitListRulesRequest{RuleNames: rules, Namespace: namespace, Resource: *mapping.Uri})
+		_, err = client.DeletePermitListRules(ctx, &paragliderpb.DeletePermitListRulesRequest{RuleNames: rules, Namespace: namespace, Resource: *mapping.Uri})
 		if err != nil {
-			c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-			return
+			return err
+		}
+		resource := &ResourceInfo{name: name, uri: *mapping.Uri, cloud: cloud, namespace: namespace}
+		if err := s.forgetRuleExpirations(ctx, resource, rules); err != nil {
+			return err
+		}
+		if err := s.forgetExpectedRules(ctx, resource, rules); err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // Find the tags dereferenced between two versions of a permit list
@@ -540,7 +656,7 @@ func diffTagReferences(beforeList []*paragliderpb.PermitListRule, afterList []*p
 }
 
 // Check whether any tags have been dereferenced by the permit list and unsubscribe from any that have
-func (s *ControllerServer) checkAndUnsubscribe(resource *ResourceInfo, beforeList []*paragliderpb.PermitListRule, afterList []*paragliderpb.PermitListRule) error {
+func (s *ControllerServer) checkAndUnsubscribe(ctx context.Context, resource *ResourceInfo, beforeList []*paragliderpb.PermitListRule, afterList []*paragliderpb.PermitListRule) error {
 	// Find the dereferenced tags
 	tagsToUnsubscribe := diffTagReferences(beforeList, afterList)
 
@@ -550,16 +666,15 @@ func (s *ControllerServer) checkAndUnsubscribe(resource *ResourceInfo, beforeLis
 	}
 
 	// Dial the tag service
-	conn, err := grpc.NewClient(s.localTagService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.serviceConns.Get(s.localTagService)
 	if err != nil {
 		return err
 	}
-	defer conn.Close()
 	client := tagservicepb.NewTagServiceClient(conn)
 
 	// Send RPC to unsubscribe from each tag
 	for _, tag := range tagsToUnsubscribe {
-		_, err := client.Unsubscribe(context.Background(), &tagservicepb.UnsubscribeRequest{Subscription: &tagservicepb.Subscription{TagName: tag, Subscriber: createSubscriberName(resource.namespace, resource.cloud, resource.uri)}})
+		_, err := client.Unsubscribe(ctx, &tagservicepb.UnsubscribeRequest{Subscription: &tagservicepb.Subscription{TagName: tag, Subscriber: createSubscriberName(resource.namespace, resource.cloud, resource.uri)}})
 		if err != nil {
 			return err
 		}
@@ -568,135 +683,106 @@ func (s *ControllerServer) checkAndUnsubscribe(resource *ResourceInfo, beforeLis
 	return nil
 }
 
-// Delete permit list rules to specified resource
-func (s *ControllerServer) permitListRulesDelete(c *gin.Context) {
-	resourceInfo, cloudClient, err := s.getAndValidateResourceURLParams(c, true)
-	if err != nil {
-		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-		return
-	}
-
-	// Parse rules to delete
-	var ruleNames []string
-	if err := c.BindJSON(&ruleNames); err != nil {
-		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-		return
-	}
-
+// Delete permit list rules from a resource and unsubscribe it from the tags no longer referenced
+func (s *ControllerServer) _permitListRulesDelete(ctx context.Context, resourceInfo *ResourceInfo, cloudClient string, ruleNames []string) error {
 	// Create connection to cloud plugin
-	conn, err := grpc.NewClient(cloudClient, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.pluginConns.Get(cloudClient)
 	if err != nil {
-		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-		return
+		return err
 	}
-	defer conn.Close()
 	client := paragliderpb.NewCloudPluginClient(conn)
 
 	// First, get the original list
-	permitListBefore, err := client.GetPermitList(context.Background(), &paragliderpb.GetPermitListRequest{Resource: resourceInfo.uri, Namespace: resourceInfo.namespace})
+	permitListBefore, err := client.GetPermitList(ctx, &paragliderpb.GetPermitListRequest{Resource: resourceInfo.uri, Namespace: resourceInfo.namespace})
 	if err != nil {
-		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-		return
+		return err
 	}
 
 	// Send RPC to delete the rules
+	setOperationProgress(ctx, "Deleting permit list rules")
 	request := &paragliderpb.DeletePermitListRulesRequest{RuleNames: ruleNames, Namespace: resourceInfo.namespace, Resource: resourceInfo.uri}
-	_, err = client.DeletePermitListRules(context.Background(), request)
+	_, err = client.DeletePermitListRules(ctx, request)
 	if err != nil {
-		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-		return
+		return err
+	}
+	if err := s.forgetRuleExpirations(ctx, resourceInfo, ruleNames); err != nil {
+		return err
+	}
+	if err := s.forgetExpectedRules(ctx, resourceInfo, ruleNames); err != nil {
+		return err
 	}
 
 	// Then get the final list to tell which tags should be unsubscribed
-	permitListAfter, err := client.GetPermitList(context.Background(), &paragliderpb.GetPermitListRequest{Resource: resourceInfo.uri, Namespace: resourceInfo.namespace})
+	permitListAfter, err := client.GetPermitList(ctx, &paragliderpb.GetPermitListRequest{Resource: resourceInfo.uri, Namespace: resourceInfo.namespace})
 	if err != nil {
-		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-		return
+		return err
 	}
 
 	// Determine which tags have been dereferenced from the permit list and unsubscribe
 	// TODO @smcclure20: Have to do a permit list diff since there is no reverse lookup to see which tags a URI is subscribed to.
 	// 					 Supporting this will probably require a database migration (non-KV store)
-	if err := s.checkAndUnsubscribe(resourceInfo, permitListBefore.Rules, permitListAfter.Rules); err != nil {
-		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-		return
-	}
+	setOperationProgress(ctx, "Unsubscribing from dereferenced tags")
+	return s.checkAndUnsubscribe(ctx, resourceInfo, permitListBefore.Rules, permitListAfter.Rules)
 }
 
-// Delete a single rule from a resource permit list
-func (s *ControllerServer) permitListRuleDelete(c *gin.Context) {
+// Delete permit list rules to specified resource
+func (s *ControllerServer) permitListRulesDelete(c *gin.Context) {
 	resourceInfo, cloudClient, err := s.getAndValidateResourceURLParams(c, true)
 	if err != nil {
 		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
 		return
 	}
 
-	// Get rule name from URL
-	ruleName := c.Param("ruleName")
-	if ruleName == "" {
-		c.AbortWithStatusJSON(400, createErrorResponse("rule name not specified"))
-		return
-	}
-
-	// Create connection to cloud plugin
-	conn, err := grpc.NewClient(cloudClient, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
+	// Parse rules to delete
+	var ruleNames []string
+	if err := c.BindJSON(&ruleNames); err != nil {
 		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
 		return
 	}
-	defer conn.Close()
-	client := paragliderpb.NewCloudPluginClient(conn)
 
-	// First, get the original list
-	permitListBefore, err := client.GetPermitList(context.Background(), &paragliderpb.GetPermitListRequest{Resource: resourceInfo.uri, Namespace: resourceInfo.namespace})
-	if err != nil {
-		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-		return
-	}
+	s.runOperation(c, DeletePermitListRulesOperation, func(ctx context.Context) (any, error) {
+		return nil, s._permitListRulesDelete(ctx, resourceInfo, cloudClient, ruleNames)
+	})
+}
 
-	// Send RPC to delete the rules
-	request := &paragliderpb.DeletePermitListRulesRequest{RuleNames: []string{ruleName}, Namespace: resourceInfo.namespace, Resource: resourceInfo.uri}
-	_, err = client.DeletePermitListRules(context.Background(), request)
+// Delete a single rule from a resource permit list
+func (s *ControllerServer) permitListRuleDelete(c *gin.Context) {
+	resourceInfo, cloudClient, err := s.getAndValidateResourceURLParams(c, true)
 	if err != nil {
 		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
 		return
 	}
 
-	// Then get the final list to tell which tags should be unsubscribed
-	permitListAfter, err := client.GetPermitList(context.Background(), &paragliderpb.GetPermitListRequest{Resource: resourceInfo.uri, Namespace: resourceInfo.namespace})
-	if err != nil {
-		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
+	// Get rule name from URL
+	ruleName := c.Param("ruleName")
+	if ruleName == "" {
+		c.AbortWithStatusJSON(400, createErrorResponse("rule name not specified"))
 		return
 	}
 
-	// Determine which tags have been dereferenced from the permit list and unsubscribe
-	// TODO @smcclure20: Have to do a permit list diff since there is no reverse lookup to see which tags a URI is subscribed to.
-	// 					 Supporting this will probably require a database migration (non-KV store)
-	if err := s.checkAndUnsubscribe(resourceInfo, permitListBefore.Rules, permitListAfter.Rules); err != nil {
-		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-		return
-	}
+	s.runOperation(c, DeletePermitListRulesOperation, func(ctx context.Context) (any, error) {
+		return nil, s._permitListRulesDelete(ctx, resourceInfo, cloudClient, []string{ruleName})
+	})
 }
 
 // Get used address spaces from a specified cloud
-func (s *ControllerServer) getAddressSpaces(cloud string) ([]*paragliderpb.AddressSpaceMapping, error) {
+func (s *ControllerServer) getAddressSpaces(ctx context.Context, cloud string) ([]*paragliderpb.AddressSpaceMapping, error) {
 	// Ensure correct cloud name
-	cloudClient, ok := s.pluginAddresses[cloud]
+	cloudClient, ok := s.getPluginAddress(cloud)
 	if !ok {
 		return nil, errors.New("invalid cloud name")
 	}
 
 	// Connect to cloud plugin
-	conn, err := grpc.NewClient(cloudClient, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.pluginConns.Get(cloudClient)
 	if err != nil {
 		return nil, fmt.Errorf("unable to connect to cloud plugin: %s", err.Error())
 	}
-	defer conn.Close()
 
 	// Send the RPC to get the address spaces
 	client := paragliderpb.NewCloudPluginClient(conn)
 	req := &paragliderpb.GetUsedAddressSpacesRequest{Deployments: s.getParagliderDeployments(cloud)}
-	resp, err := client.GetUsedAddressSpaces(context.Background(), req)
+	resp, err := client.GetUsedAddressSpaces(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get used address spaces : %s", err.Error())
 	}
@@ -704,15 +790,17 @@ func (s *ControllerServer) getAddressSpaces(cloud string) ([]*paragliderpb.Addre
 }
 
 // Update local address space map by getting used address spaces from each cloud plugin
-func (s *ControllerServer) updateUsedAddressSpaces() error {
+func (s *ControllerServer) updateUsedAddressSpaces(ctx context.Context) error {
 	// Call each cloud to get address spaces used
-	for _, cloud := range s.config.CloudPlugins {
-		addressSpaceMappings, err := s.getAddressSpaces(cloud.Name)
+	usedAddressSpaces := []*paragliderpb.AddressSpaceMapping{}
+	for _, cloud := range s.getPluginNames() {
+		addressSpaceMappings, err := s.getAddressSpaces(ctx, cloud)
 		if err != nil {
 			return fmt.Errorf("could not retrieve address spaces for cloud %s (error: %s)", cloud, err.Error())
 		}
-		s.usedAddressSpaces = append(s.usedAddressSpaces, addressSpaceMappings...)
+		usedAddressSpaces = append(usedAddressSpaces, addressSpaceMappings...)
 	}
+	s.usedAddressSpaces = usedAddressSpaces
 	return nil
 }
 
@@ -720,7 +808,7 @@ func (s *ControllerServer) updateUsedAddressSpaces() error {
 func (s *ControllerServer) FindUnusedAddressSpaces(c context.Context, req *paragliderpb.FindUnusedAddressSpacesRequest) (*paragliderpb.FindUnusedAddressSpacesResponse, error) {
 	s.addressRequest.Lock()
 	defer s.addressRequest.Unlock()
-	err := s.updateUsedAddressSpaces()
+	err := s.updateUsedAddressSpaces(c)
 	if err != nil {
 		return nil, err
 	}
@@ -732,8 +820,17 @@ func (s *ControllerServer) FindUnusedAddressSpaces(c context.Context, req *parag
 		requestedAddressSpaces = []int32{int32(defaultSpaceRequest)}
 	}
 	respAddressSpaces := make([]string, len(requestedAddressSpaces))
+	// Address spaces handed out before may not have been materialized by the plugins yet
+	allocatedAddressSpaces, err := s.allocations.List(c, store.AddressSpacesKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get allocated address spaces: %w", err)
+	}
+	usedAddressSpaces := append([]*paragliderpb.AddressSpaceMapping{{AddressSpaces: allocatedAddressSpaces}}, s.usedAddressSpaces...)
+	if req.Ipv6 {
+		return s.findUnusedIpv6AddressSpaces(c, len(requestedAddressSpaces), usedAddressSpaces)
+	}
 	// Calculate the list of unused address space blocks available to be allocated
-	unusedBlocks := findUnusedBlocks(s.config.AddressSpace, s.usedAddressSpaces)
+	unusedBlocks := findUnusedBlocks(s.config.AddressSpace, usedAddressSpaces)
 	for i := 0; i < len(requestedAddressSpaces); i++ {
 		reqSize := int64(defaultSpaceRequest)
 		if requestedAddressSpaces[i] != 0 {
@@ -757,16 +854,31 @@ func (s *ControllerServer) FindUnusedAddressSpaces(c context.Context, req *parag
 		}
 		// Remove the allocated block from the set of available spaces
 		unusedBlocks = removeBlock(unusedBlocks, aBlock)
-		if err != nil {
-			return nil, err
-		}
+	}
+	if err := s.allocations.Add(c, store.AddressSpacesKey, respAddressSpaces...); err != nil {
+		return nil, fmt.Errorf("unable to record allocated address spaces: %w", err)
+	}
+	return &paragliderpb.FindUnusedAddressSpacesResponse{AddressSpaces: respAddressSpaces}, nil
+}
+
+// Get new IPv6 address blocks for dual-stack virtual networks, or none if the namespaces are IPv4-only
+func (s *ControllerServer) findUnusedIpv6AddressSpaces(c context.Context, count int, usedAddressSpaces []*paragliderpb.AddressSpaceMapping) (*paragliderpb.FindUnusedAddressSpacesResponse, error) {
+	if len(s.config.Ipv6AddressSpace) == 0 {
+		return &paragliderpb.FindUnusedAddressSpacesResponse{}, nil
+	}
+	respAddressSpaces, err := allocIpv6Blocks(findUnusedBlocks(s.config.Ipv6AddressSpace, usedAddressSpaces), count)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.allocations.Add(c, store.AddressSpacesKey, respAddressSpaces...); err != nil {
+		return nil, fmt.Errorf("unable to record allocated address spaces: %w", err)
 	}
 	return &paragliderpb.FindUnusedAddressSpacesResponse{AddressSpaces: respAddressSpaces}, nil
 }
 
 // Gets unused address spaces across all clouds
 func (s *ControllerServer) GetUsedAddressSpaces(c context.Context, _ *emptypb.Empty) (*paragliderpb.GetUsedAddressSpacesResponse, error) {
-	err := s.updateUsedAddressSpaces()
+	err := s.updateUsedAddressSpaces(c)
 	if err != nil {
 		return nil, err
 	}
@@ -778,41 +890,44 @@ func (s *ControllerServer) GetUsedAddressSpaces(c context.Context, _ *emptypb.Em
 }
 
 // Get used ASNs from a specified cloud
-func (s *ControllerServer) getUsedAsns(cloud string) (*paragliderpb.GetUsedAsnsResponse, error) {
+func (s *ControllerServer) getUsedAsns(ctx context.Context, cloud string) (*paragliderpb.GetUsedAsnsResponse, error) {
 	// Ensure correct cloud name
-	cloudClient, ok := s.pluginAddresses[cloud]
+	cloudClient, ok := s.getPluginAddress(cloud)
 	if !ok {
 		return nil, errors.New("Invalid cloud name")
 	}
 
 	// Connect to cloud plugin
-	conn, err := grpc.NewClient(cloudClient, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.pluginConns.Get(cloudClient)
 	if err != nil {
 		return nil, fmt.Errorf("Unable to connect to cloud plugin: %s", err.Error())
 	}
-	defer conn.Close()
 
 	// Send the RPC to get the ASNs
 	client := paragliderpb.NewCloudPluginClient(conn)
 	req := &paragliderpb.GetUsedAsnsRequest{Deployments: s.getParagliderDeployments(cloud)}
-	resp, err := client.GetUsedAsns(context.Background(), req)
+	resp, err := client.GetUsedAsns(ctx, req)
 
 	return resp, err
 }
 
-func (s *ControllerServer) updateUsedAsns() error {
-	for _, cloud := range s.config.CloudPlugins {
-		asnList, err := s.getUsedAsns(cloud.Name)
+func (s *ControllerServer) updateUsedAsns(ctx context.Context) error {
+	usedAsns := []uint32{}
+	for _, cloud := range s.getPluginNames() {
+		asnList, err := s.getUsedAsns(ctx, cloud)
 		if err != nil {
 			return fmt.Errorf("Could not retrieve address spaces for cloud %s (error: %s)", cloud, err.Error())
 		}
-		s.usedAsns = append(s.usedAsns, asnList.Asns...)
+		usedAsns = append(usedAsns, asnList.Asns...)
 	}
+	s.usedAsns = usedAsns
 	return nil
 }
 
 func (s *ControllerServer) FindUnusedAsn(c context.Context, _ *paragliderpb.FindUnusedAsnRequest) (*paragliderpb.FindUnusedAsnResponse, error) {
-	err := s.updateUsedAsns()
+	s.asnRequest.Lock()
+	defer s.asnRequest.Unlock()
+	err := s.updateUsedAsns(c)
 	if err != nil {
 		return nil, fmt.Errorf("unable to update used asns: %w", err)
 	}
@@ -821,6 +936,17 @@ func (s *ControllerServer) FindUnusedAsn(c context.Context, _ *paragliderpb.Find
 	for _, asn := range s.usedAsns {
 		usedAsns[asn] = true
 	}
+	allocatedAsns, err := s.allocations.List(c, store.AsnsKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get allocated asns: %w", err)
+	}
+	for _, asnString := range allocatedAsns {
+		asn, err := strconv.ParseUint(asnString, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse allocated asn %s: %w", asnString, err)
+		}
+		usedAsns[uint32(asn)] = true
+	}
 
 	// Find smallest unused ASN
 	var unusedAsn uint32 = 0
@@ -845,55 +971,68 @@ func (s *ControllerServer) FindUnusedAsn(c context.Context, _ *paragliderpb.Find
 		}
 	}
 
+	if err := s.allocations.Add(c, store.AsnsKey, strconv.FormatUint(uint64(unusedAsn), 10)); err != nil {
+		return nil, fmt.Errorf("unable to record allocated asn: %w", err)
+	}
+
 	resp := &paragliderpb.FindUnusedAsnResponse{Asn: unusedAsn}
 	return resp, nil
 }
 
 // Get used BGP peering IP addresses from a specified cloud
-func (s *ControllerServer) getUsedBgpPeeringIpAddresses(cloud string) (*paragliderpb.GetUsedBgpPeeringIpAddressesResponse, error) {
+func (s *ControllerServer) getUsedBgpPeeringIpAddresses(ctx context.Context, cloud string) (*paragliderpb.GetUsedBgpPeeringIpAddressesResponse, error) {
 	// Ensure correct cloud name
-	cloudClient, ok := s.pluginAddresses[cloud]
+	cloudClient, ok := s.getPluginAddress(cloud)
 	if !ok {
 		return nil, errors.New("Invalid cloud name")
 	}
 
 	// Connect to cloud plugin
-	conn, err := grpc.NewClient(cloudClient, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.pluginConns.Get(cloudClient)
 	if err != nil {
 		return nil, fmt.Errorf("Unable to connect to cloud plugin: %s", err.Error())
 	}
-	defer conn.Close()
 
 	// Send the RPC to get the BGP peering IP addresses
 	client := paragliderpb.NewCloudPluginClient(conn)
 	req := &paragliderpb.GetUsedBgpPeeringIpAddressesRequest{Deployments: s.getParagliderDeployments(cloud)}
-	resp, err := client.GetUsedBgpPeeringIpAddresses(context.Background(), req)
+	resp, err := client.GetUsedBgpPeeringIpAddresses(ctx, req)
 
 	return resp, err
 }
 
-func (s *ControllerServer) updateUsedBgpPeeringIpAddresses(namespace string) error {
-	for _, cloud := range s.config.CloudPlugins {
-		bgpPeeringIpAddressesList, err := s.getUsedBgpPeeringIpAddresses(cloud.Name)
+func (s *ControllerServer) updateUsedBgpPeeringIpAddresses(ctx context.Context, namespace string) error {
+	for _, cloud := range s.getPluginNames() {
+		bgpPeeringIpAddressesList, err := s.getUsedBgpPeeringIpAddresses(ctx, cloud)
 		if err != nil {
 			return fmt.Errorf("Could not retrieve address spaces for cloud %s (error: %s)", cloud, err.Error())
 		}
-		s.usedBgpPeeringIpAddresses[cloud.Name] = bgpPeeringIpAddressesList.IpAddresses
+		s.usedBgpPeeringIpAddresses[cloud] = bgpPeeringIpAddressesList.IpAddresses
 	}
 	return nil
 }
 
 // Not a public RPC (hence private) used by cloud plugins but follows the same pattern as FindUnusedAsn
 func (s *ControllerServer) findUnusedBgpPeeringIpAddresses(ctx context.Context, cloud1 string, cloud2 string, namespace string) ([]string, error) {
+	s.bgpPeeringIpAddressRequest.Lock()
+	defer s.bgpPeeringIpAddressRequest.Unlock()
 	// Retrieve all used peering IPs from all clouds
-	err := s.updateUsedBgpPeeringIpAddresses(namespace)
+	err := s.updateUsedBgpPeeringIpAddresses(ctx, namespace)
 	if err != nil {
 		return nil, fmt.Errorf("unable to update used BGP peering IP addresses: %w", err)
 	}
+	allocatedBgpPeeringIpAddresses, err := s.allocations.List(ctx, store.BgpPeeringIpAddressesKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get allocated BGP peering IP addresses: %w", err)
+	}
 
 	// Compile used ips into a map
 	usedBgpPeeringIpAddresses := make(map[string]bool)
+	allBgpPeeringIpAddresses := [][]string{allocatedBgpPeeringIpAddresses}
 	for _, cloudBgpPeeringIpAddresses := range s.usedBgpPeeringIpAddresses {
+		allBgpPeeringIpAddresses = append(allBgpPeeringIpAddresses, cloudBgpPeeringIpAddresses)
+	}
+	for _, cloudBgpPeeringIpAddresses := range allBgpPeeringIpAddresses {
 		for _, ipAddressString := range cloudBgpPeeringIpAddresses {
 			ipAddress, err := netip.ParseAddr(ipAddressString)
 			if err != nil {
@@ -910,6 +1049,10 @@ func (s *ControllerServer) findUnusedBgpPeeringIpAddresses(ctx context.Context,
 		// Azure has a more restrictive APIPA range
 		minIp = netip.MustParseAddr("169.254.21.1")
 		maxIp = netip.MustParseAddr("169.254.22.253")
+	} else if cloud1 == utils.AWS || cloud2 == utils.AWS {
+		// AWS reserves 169.254.0.0/30 through 169.254.5.0/30 and 169.254.169.252/30 for tunnel inside CIDRs
+		minIp = netip.MustParseAddr("169.254.6.1")
+		maxIp = netip.MustParseAddr("169.254.169.249")
 	} else {
 		minIp = netip.MustParseAddr("169.254.0.1")
 		maxIp = netip.MustParseAddr("169.254.255.253")
@@ -943,21 +1086,82 @@ func (s *ControllerServer) findUnusedBgpPeeringIpAddresses(ctx context.Context,
 	if i < requiredIps {
 		return nil, fmt.Errorf("unable to find the necessary number of unused subnets")
 	}
+	if err := s.allocations.Add(ctx, store.BgpPeeringIpAddressesKey, ips...); err != nil {
+		return nil, fmt.Errorf("unable to record allocated BGP peering IP addresses: %w", err)
+	}
 
 	return ips, nil
 }
 
+// Returns true if what is recorded in the allocation store (e.g., namespaces created through the API) survives restarts
+func (s *ControllerServer) isAllocationStorePersistent() bool {
+	return store.IsPersistent(s.config.AllocationStore.Type)
+}
+
+// Forget the allocations the clouds account for themselves. Allocations only need to be recorded until the plugins have
+// put them to use (e.g., as the address space of a VPC), after which the clouds report them as used until they're deleted.
+// This is done before deleting resources and namespaces, so that what they used can be handed out again afterwards.
+func (s *ControllerServer) releaseAllocations(ctx context.Context) error {
+	s.addressRequest.Lock()
+	err := s.updateUsedAddressSpaces(ctx)
+	if err == nil {
+		usedAddressSpaces := []string{}
+		for _, mapping := range s.usedAddressSpaces {
+			usedAddressSpaces = append(usedAddressSpaces, mapping.AddressSpaces...)
+		}
+		err = s.allocations.Remove(ctx, store.AddressSpacesKey, usedAddressSpaces...)
+	}
+	s.addressRequest.Unlock()
+	if err != nil {
+		return fmt.Errorf("unable to release address spaces: %w", err)
+	}
+
+	s.asnRequest.Lock()
+	err = s.updateUsedAsns(ctx)
+	if err == nil {
+		usedAsns := make([]string, len(s.usedAsns))
+		for i, asn := range s.usedAsns {
+			usedAsns[i] = strconv.FormatUint(uint64(asn), 10)
+		}
+		err = s.allocations.Remove(ctx, store.AsnsKey, usedAsns...)
+	}
+	s.asnRequest.Unlock()
+	if err != nil {
+		return fmt.Errorf("unable to release asns: %w", err)
+	}
+
+	s.bgpPeeringIpAddressRequest.Lock()
+	err = s.updateUsedBgpPeeringIpAddresses(ctx, "")
+	if err == nil {
+		usedBgpPeeringIpAddresses := []string{}
+		for _, cloudBgpPeeringIpAddresses := range s.usedBgpPeeringIpAddresses {
+			usedBgpPeeringIpAddresses = append(usedBgpPeeringIpAddresses, cloudBgpPeeringIpAddresses...)
+		}
+		err = s.allocations.Remove(ctx, store.BgpPeeringIpAddressesKey, usedBgpPeeringIpAddresses...)
+	}
+	s.bgpPeeringIpAddressRequest.Unlock()
+	if err != nil {
+		return fmt.Errorf("unable to release BGP peering IP addresses: %w", err)
+	}
+	return nil
+}
+
 // Generates a shared key for VPN connections
 func generateSharedKey() string {
 	const length = 24
 	// characters allowed in the random string
 	// '/' is prohibited as part of the pre-shared key for IBM VPN connections
-	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!@#$%^&*()"
+	// AWS only allows alphanumeric characters, periods and underscores as part of the pre-shared key
+	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789._"
 	generatedRunes := make([]rune, length)
 
 	for i := range generatedRunes {
 		generatedRunes[i] = rune(charset[rand.Intn(len(charset))])
 	}
+	// AWS pre-shared keys can't start with zero
+	for generatedRunes[0] == '0' {
+		generatedRunes[0] = rune(charset[rand.Intn(len(charset))])
+	}
 
 	return string(generatedRunes)
 }
@@ -965,6 +1169,8 @@ func generateSharedKey() string {
 // Gets the Paraglider deployment field of a cloud
 // TODO @seankimkdy: make this more efficient by using maps to maintain clouds in config?
 func (s *ControllerServer) getCloudDeployment(cloud, namespace string) string {
+	s.namespacesLock.RLock()
+	defer s.namespacesLock.RUnlock()
 	for ns, deployments := range s.config.Namespaces {
 		if ns == namespace {
 			for _, deployment := range deployments {
@@ -986,127 +1192,151 @@ func (s *ControllerServer) ConnectClouds(ctx context.Context, req *paragliderpb.
 		return nil, fmt.Errorf("must specify different clouds to connect")
 	}
 
-	// TODO @seankimkdy: cloudA and cloudB naming seems to be very prone to typos, so perhaps use another naming scheme[?
-	if utils.MatchCloudProviders(req.CloudA, req.CloudB, utils.AZURE, utils.GCP) || utils.MatchCloudProviders(req.CloudA, req.CloudB, utils.AZURE, utils.IBM) {
-		if req.CloudA == utils.IBM || req.CloudB == utils.IBM {
-			isBGPDisabledConnection = true
-		}
-		cloudAClientAddress, ok := s.pluginAddresses[req.CloudA]
-		if !ok {
-			return nil, fmt.Errorf("invalid cloud name: %s", req.CloudA)
-		}
-		cloudAConn, err := grpc.NewClient(cloudAClientAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
-		if err != nil {
-			return nil, fmt.Errorf("unable to connect to cloud plugin: %w", err)
+	// AWS always takes the first usable address of a tunnel's inside CIDR (i.e., cloud A's BGP peering IP address) and its
+	// gateway IP addresses are only known once VPN connections are created, so AWS is always made cloud A.
+	if req.CloudB == utils.AWS {
+		req = &paragliderpb.ConnectCloudsRequest{
+			CloudA:              req.CloudB,
+			CloudANamespace:     req.CloudBNamespace,
+			AddressSpacesCloudA: req.AddressSpacesCloudB,
+			CloudB:              req.CloudA,
+			CloudBNamespace:     req.CloudANamespace,
+			AddressSpacesCloudB: req.AddressSpacesCloudA,
 		}
-		defer cloudAConn.Close()
-		cloudAClient := paragliderpb.NewCloudPluginClient(cloudAConn)
+	}
 
-		cloudBClientAddress, ok := s.pluginAddresses[req.CloudB]
-		if !ok {
-			return nil, fmt.Errorf("invalid cloud name: %s", req.CloudA)
-		}
-		cloudBconn, err := grpc.NewClient(cloudBClientAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
-		if err != nil {
-			return nil, fmt.Errorf("unable to connect to cloud plugin: %w", err)
-		}
-		defer cloudAConn.Close()
-		cloudBClient := paragliderpb.NewCloudPluginClient(cloudBconn)
+	// TODO @seankimkdy: cloudA and cloudB naming seems to be very prone to typos, so perhaps use another naming scheme[?
+	// Connect the clouds with a VPN mode both of them support
+	cloudACapabilities, err := s.getCapabilities(ctx, req.CloudA)
+	if err != nil {
+		return nil, err
+	}
+	cloudBCapabilities, err := s.getCapabilities(ctx, req.CloudB)
+	if err != nil {
+		return nil, err
+	}
+	vpnMode, ok := negotiateVpnMode(cloudACapabilities, cloudBCapabilities)
+	if !ok {
+		return nil, fmt.Errorf("clouds %s and %s are not supported for multi-cloud connecting", req.CloudA, req.CloudB)
+	}
+	isBGPDisabledConnection = vpnMode == paragliderpb.VpnMode_STATIC
 
-		ctx := context.Background()
+	cloudAClientAddress, ok := s.getPluginAddress(req.CloudA)
+	if !ok {
+		return nil, fmt.Errorf("invalid cloud name: %s", req.CloudA)
+	}
+	cloudAConn, err := s.pluginConns.Get(cloudAClientAddress)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to cloud plugin: %w", err)
+	}
+	cloudAClient := paragliderpb.NewCloudPluginClient(cloudAConn)
 
-		// Get BGP peering IP addresses
-		bgpPeeringIpAddresses, err := s.findUnusedBgpPeeringIpAddresses(ctx, req.CloudA, req.CloudB, req.CloudANamespace)
-		if err != nil {
-			return nil, fmt.Errorf("unable to find unused bgp peering subnet")
-		}
-		cloudABgpPeeringIpAddresses := make([]string, len(bgpPeeringIpAddresses)/2)
-		cloudBBgpPeeringIpAddresses := make([]string, len(bgpPeeringIpAddresses)/2)
-		for i := 0; i < len(bgpPeeringIpAddresses)/2; i++ {
-			cloudABgpPeeringIpAddresses[i] = bgpPeeringIpAddresses[i*2]
-			cloudBBgpPeeringIpAddresses[i] = bgpPeeringIpAddresses[i*2+1]
-		}
-		if len(req.AddressSpacesCloudA) != 0 {
-			addressSpaceCloudA = req.AddressSpacesCloudA[0] // required by IBM to identify the VPN gateway that's being used
-		}
-		if len(req.AddressSpacesCloudB) != 0 {
-			addressSpaceCloudB = req.AddressSpacesCloudB[0] // required by IBM to identify the VPN gateway that's being used
-		}
-		cloudAParagliderDeployment := &paragliderpb.ParagliderDeployment{Id: s.getCloudDeployment(req.CloudA, req.CloudANamespace), Namespace: req.CloudANamespace}
-		cloudACreateVpnGatewayReq := &paragliderpb.CreateVpnGatewayRequest{
-			Deployment:            cloudAParagliderDeployment,
-			Cloud:                 req.CloudB,
-			BgpPeeringIpAddresses: cloudABgpPeeringIpAddresses,
-			AddressSpace:          addressSpaceCloudA,
-		}
-
-		// get CIDR of VPC/VNet in remote cloud (cloudB) containing the resource's IP.
-		// network address spaces of cloud A was provided by cloud A when it issued the connect cloud request,
-		// hence accessible by req.AddressSpacesCloudA.
-		cloudBParagliderDeployment := &paragliderpb.ParagliderDeployment{Id: s.getCloudDeployment(req.CloudB, req.CloudBNamespace), Namespace: req.CloudBNamespace}
-		if isBGPDisabledConnection {
-			res, err := cloudBClient.GetNetworkAddressSpaces(ctx, &paragliderpb.GetNetworkAddressSpacesRequest{Deployment: cloudBParagliderDeployment, AddressSpace: addressSpaceCloudB})
-			if err != nil {
-				return nil, err
-			}
-			cloudBNetworkAddressSpaces = res.AddressSpaces
-		}
+	cloudBClientAddress, ok := s.getPluginAddress(req.CloudB)
+	if !ok {
+		return nil, fmt.Errorf("invalid cloud name: %s", req.CloudA)
+	}
+	cloudBconn, err := s.pluginConns.Get(cloudBClientAddress)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to cloud plugin: %w", err)
+	}
+	cloudBClient := paragliderpb.NewCloudPluginClient(cloudBconn)
 
-		cloudACreateVpnGatewayResp, err := cloudAClient.CreateVpnGateway(ctx, cloudACreateVpnGatewayReq)
-		if err != nil {
-			return nil, fmt.Errorf("unable to create vpn gateway in cloud %s: %w", req.CloudA, err)
-		}
-		cloudBCreateVpnGatewayReq := &paragliderpb.CreateVpnGatewayRequest{
-			Deployment:            cloudBParagliderDeployment,
-			Cloud:                 req.CloudA,
-			BgpPeeringIpAddresses: cloudBBgpPeeringIpAddresses,
-			AddressSpace:          addressSpaceCloudB,
-		}
-		cloudBCreateVpnGatewayResp, err := cloudBClient.CreateVpnGateway(ctx, cloudBCreateVpnGatewayReq)
+	// Get BGP peering IP addresses
+	bgpPeeringIpAddresses, err := s.findUnusedBgpPeeringIpAddresses(ctx, req.CloudA, req.CloudB, req.CloudANamespace)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find unused bgp peering subnet")
+	}
+	cloudABgpPeeringIpAddresses := make([]string, len(bgpPeeringIpAddresses)/2)
+	cloudBBgpPeeringIpAddresses := make([]string, len(bgpPeeringIpAddresses)/2)
+	for i := 0; i < len(bgpPeeringIpAddresses)/2; i++ {
+		cloudABgpPeeringIpAddresses[i] = bgpPeeringIpAddresses[i*2]
+		cloudBBgpPeeringIpAddresses[i] = bgpPeeringIpAddresses[i*2+1]
+	}
+	if len(req.AddressSpacesCloudA) != 0 {
+		addressSpaceCloudA = req.AddressSpacesCloudA[0] // required by IBM to identify the VPN gateway that's being used
+	}
+	if len(req.AddressSpacesCloudB) != 0 {
+		addressSpaceCloudB = req.AddressSpacesCloudB[0] // required by IBM to identify the VPN gateway that's being used
+	}
+	cloudAParagliderDeployment := &paragliderpb.ParagliderDeployment{Id: s.getCloudDeployment(req.CloudA, req.CloudANamespace), Namespace: req.CloudANamespace}
+	cloudACreateVpnGatewayReq := &paragliderpb.CreateVpnGatewayRequest{
+		Deployment:            cloudAParagliderDeployment,
+		Cloud:                 req.CloudB,
+		BgpPeeringIpAddresses: cloudABgpPeeringIpAddresses,
+		AddressSpace:          addressSpaceCloudA,
+	}
+
+	// get CIDR of VPC/VNet in remote cloud (cloudB) containing the resource's IP.
+	// network address spaces of cloud A was provided by cloud A when it issued the connect cloud request,
+	// hence accessible by req.AddressSpacesCloudA.
+	cloudBParagliderDeployment := &paragliderpb.ParagliderDeployment{Id: s.getCloudDeployment(req.CloudB, req.CloudBNamespace), Namespace: req.CloudBNamespace}
+	if isBGPDisabledConnection {
+		res, err := cloudBClient.GetNetworkAddressSpaces(ctx, &paragliderpb.GetNetworkAddressSpacesRequest{Deployment: cloudBParagliderDeployment, AddressSpace: addressSpaceCloudB})
 		if err != nil {
-			return nil, fmt.Errorf("unable to create vpn gateway in cloud %s: %w", req.CloudB, err)
+			return nil, err
 		}
+		cloudBNetworkAddressSpaces = res.AddressSpaces
+	}
 
-		sharedKey := generateSharedKey()
+	cloudACreateVpnGatewayResp, err := cloudAClient.CreateVpnGateway(ctx, cloudACreateVpnGatewayReq)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create vpn gateway in cloud %s: %w", req.CloudA, err)
+	}
+	cloudBCreateVpnGatewayReq := &paragliderpb.CreateVpnGatewayRequest{
+		Deployment:            cloudBParagliderDeployment,
+		Cloud:                 req.CloudA,
+		BgpPeeringIpAddresses: cloudBBgpPeeringIpAddresses,
+		AddressSpace:          addressSpaceCloudB,
+	}
+	cloudBCreateVpnGatewayResp, err := cloudBClient.CreateVpnGateway(ctx, cloudBCreateVpnGatewayReq)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create vpn gateway in cloud %s: %w", req.CloudB, err)
+	}
 
-		cloudACreateVpnConnectionsReq := &paragliderpb.CreateVpnConnectionsRequest{
-			Deployment:         cloudAParagliderDeployment,
-			Cloud:              req.CloudB,
-			Asn:                cloudBCreateVpnGatewayResp.Asn,
-			GatewayIpAddresses: cloudBCreateVpnGatewayResp.GatewayIpAddresses,
-			BgpIpAddresses:     cloudBBgpPeeringIpAddresses,
-			SharedKey:          sharedKey,
-			RemoteAddresses:    cloudBNetworkAddressSpaces, // provides non BGP connections with remote address target
-			IsBgpDisabled:      isBGPDisabledConnection,    // informs cloud A that BGP is disabled on peer cloud
-			AddressSpace:       addressSpaceCloudA,         // Address space of a subnet/resource's IP in cloud A.
-		}
-		_, err = cloudAClient.CreateVpnConnections(ctx, cloudACreateVpnConnectionsReq)
-		if err != nil {
-			return nil, fmt.Errorf("unable to create vpn connections in cloud %s: %w", req.CloudA, err)
-		}
-		cloudBCreateVpnConnectionsReq := &paragliderpb.CreateVpnConnectionsRequest{
-			Deployment:         cloudBParagliderDeployment,
-			Cloud:              req.CloudA,
-			Asn:                cloudACreateVpnGatewayResp.Asn,
-			GatewayIpAddresses: cloudACreateVpnGatewayResp.GatewayIpAddresses,
-			BgpIpAddresses:     cloudABgpPeeringIpAddresses,
-			SharedKey:          sharedKey,
-			RemoteAddresses:    req.AddressSpacesCloudA, // provides non BGP connections with remote address target
-			IsBgpDisabled:      isBGPDisabledConnection, // informs cloud B that BGP is disabled on peer cloud
-			AddressSpace:       addressSpaceCloudB,      // Address space of a subnet/resource's IP in cloud B.
-		}
-		_, err = cloudBClient.CreateVpnConnections(ctx, cloudBCreateVpnConnectionsReq)
-		if err != nil {
-			return nil, fmt.Errorf("unable to create vpn connections in cloud %s: %w", req.CloudB, err)
-		}
-		return &paragliderpb.ConnectCloudsResponse{}, nil
+	sharedKey := generateSharedKey()
+
+	cloudACreateVpnConnectionsReq := &paragliderpb.CreateVpnConnectionsRequest{
+		Deployment:         cloudAParagliderDeployment,
+		Cloud:              req.CloudB,
+		Asn:                cloudBCreateVpnGatewayResp.Asn,
+		GatewayIpAddresses: cloudBCreateVpnGatewayResp.GatewayIpAddresses,
+		BgpIpAddresses:     cloudBBgpPeeringIpAddresses,
+		SharedKey:          sharedKey,
+		RemoteAddresses:    cloudBNetworkAddressSpaces, // provides non BGP connections with remote address target
+		IsBgpDisabled:      isBGPDisabledConnection,    // informs cloud A that BGP is disabled on peer cloud
+		AddressSpace:       addressSpaceCloudA,         // Address space of a subnet/resource's IP in cloud A.
+	}
+	cloudACreateVpnConnectionsResp, err := cloudAClient.CreateVpnConnections(ctx, cloudACreateVpnConnectionsReq)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create vpn connections in cloud %s: %w", req.CloudA, err)
+	}
+	cloudAGatewayIpAddresses := cloudACreateVpnGatewayResp.GatewayIpAddresses
+	if len(cloudACreateVpnConnectionsResp.GatewayIpAddresses) > 0 {
+		cloudAGatewayIpAddresses = cloudACreateVpnConnectionsResp.GatewayIpAddresses
+	}
+	cloudBCreateVpnConnectionsReq := &paragliderpb.CreateVpnConnectionsRequest{
+		Deployment:         cloudBParagliderDeployment,
+		Cloud:              req.CloudA,
+		Asn:                cloudACreateVpnGatewayResp.Asn,
+		GatewayIpAddresses: cloudAGatewayIpAddresses,
+		BgpIpAddresses:     cloudABgpPeeringIpAddresses,
+		SharedKey:          sharedKey,
+		RemoteAddresses:    req.AddressSpacesCloudA, // provides non BGP connections with remote address target
+		IsBgpDisabled:      isBGPDisabledConnection, // informs cloud B that BGP is disabled on peer cloud
+		AddressSpace:       addressSpaceCloudB,      // Address space of a subnet/resource's IP in cloud B.
 	}
-	return nil, fmt.Errorf("clouds %s and %s are not supported for multi-cloud connecting", req.CloudA, req.CloudB)
+	_, err = cloudBClient.CreateVpnConnections(ctx, cloudBCreateVpnConnectionsReq)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create vpn connections in cloud %s: %w", req.CloudB, err)
+	}
+	return &paragliderpb.ConnectCloudsResponse{}, nil
 }
 
 // Gets all deployments (in Paraglider) format for a given cloud
 func (s *ControllerServer) getParagliderDeployments(cloud string) []*paragliderpb.ParagliderDeployment {
 	pgDeployments := []*paragliderpb.ParagliderDeployment{}
+	s.namespacesLock.RLock()
+	defer s.namespacesLock.RUnlock()
 	for namespace, cloudDeployments := range s.config.Namespaces {
 		for _, cloudDeployment := range cloudDeployments {
 			if cloudDeployment.Name == cloud {
@@ -1144,15 +1374,47 @@ func (s *ControllerServer) handleCreateOrAttachResource(c *gin.Context) {
 			resourceInfo.name = resourceToCreate.Name
 		}
 
-		s.resourceCreate(c, resourceInfo, cloudClient, &resourceToCreate)
+		// The type of the resource can be given along with its description to check the cloud supports it
+		var createOptions struct {
+			Type string `json:"type"`
+		}
+		if err := c.ShouldBindBodyWithJSON(&createOptions); err != nil {
+			c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
+			return
+		}
+		capabilities, err := s.getCapabilities(c.Request.Context(), resourceInfo.cloud)
+		if err != nil {
+			c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
+			return
+		}
+		if err := checkResourceTypeSupported(resourceInfo.cloud, createOptions.Type, capabilities); err != nil {
+			c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
+			return
+		}
+
+		s.runOperation(c, CreateResourceOperation, func(ctx context.Context) (any, error) {
+			return s.resourceCreate(ctx, resourceInfo, cloudClient, &resourceToCreate)
+		})
 	} else if err := c.ShouldBindBodyWithJSON(&resourceToAttach); err == nil && resourceToAttach.Id != "" {
 		if c.Request.Method != "POST" {
 			c.AbortWithStatusJSON(400, createErrorResponse("Only POST method is allowed for attaching resources"))
 			return
 		}
 
+		capabilities, err := s.getCapabilities(c.Request.Context(), resourceInfo.cloud)
+		if err != nil {
+			c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
+			return
+		}
+		if !capabilities.AttachResource {
+			c.AbortWithStatusJSON(400, createErrorResponse(fmt.Sprintf("cloud %s does not support attaching resources", resourceInfo.cloud)))
+			return
+		}
+
 		resourceInfo.uri = resourceToAttach.Id
-		s.resourceAttach(c, resourceInfo, cloudClient)
+		s.runOperation(c, AttachResourceOperation, func(ctx context.Context) (any, error) {
+			return s.resourceAttach(ctx, resourceInfo, cloudClient)
+		})
 	} else {
 		c.AbortWithStatusJSON(400, createErrorResponse("Invalid request body"))
 	}
@@ -1167,15 +1429,14 @@ func (s *ControllerServer) preCreateOrAttach(c *gin.Context, resourceInfo *Resou
 		return fmt.Errorf("unable to create tag name")
 	}
 
-	conn, err := grpc.NewClient(s.localTagService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.serviceConns.Get(s.localTagService)
 	if err != nil {
 		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
 		return err
 	}
-	defer conn.Close()
 
 	client := tagservicepb.NewTagServiceClient(conn)
-	resp, err := client.GetTag(context.Background(), &tagservicepb.GetTagRequest{TagName: tagName})
+	resp, err := client.GetTag(c.Request.Context(), &tagservicepb.GetTagRequest{TagName: tagName})
 	if err == nil && isTagValid(resp.Tag) {
 		c.AbortWithStatusJSON(400, createErrorResponse("Tag already exists"))
 		return err
@@ -1185,123 +1446,208 @@ func (s *ControllerServer) preCreateOrAttach(c *gin.Context, resourceInfo *Resou
 }
 
 // Create resource in specified cloud region
-func (s *ControllerServer) resourceCreate(c *gin.Context, resourceInfo *ResourceInfo, cloudClient string, resourceToCreate *paragliderpb.ResourceDescriptionString) {
+func (s *ControllerServer) resourceCreate(ctx context.Context, resourceInfo *ResourceInfo, cloudClient string, resourceToCreate *paragliderpb.ResourceDescriptionString) (*paragliderpb.CreateResourceResponse, error) {
 	// Create connection to cloud plugin
-	conn, err := grpc.NewClient(cloudClient, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.pluginConns.Get(cloudClient)
 	if err != nil {
-		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-		return
+		return nil, err
 	}
-	defer conn.Close()
 
 	// Send RPC to create the resource
+	setOperationProgress(ctx, "Creating resource")
 	resource := paragliderpb.CreateResourceRequest{
 		Deployment:  &paragliderpb.ParagliderDeployment{Id: s.getCloudDeployment(resourceInfo.cloud, resourceInfo.namespace), Namespace: resourceInfo.namespace},
 		Name:        resourceInfo.name,
 		Description: []byte(resourceToCreate.Description),
 	}
 	client := paragliderpb.NewCloudPluginClient(conn)
-	resourceResp, err := client.CreateResource(context.Background(), &resource)
+	resourceResp, err := client.CreateResource(ctx, &resource)
 	if err != nil {
-		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-		return
+		return nil, err
 	}
 
 	// Set Paraglider tag
-	tagName := s.createTag(c, resourceInfo, resourceResp.Uri, resourceResp.Ip)
-	if tagName == "" {
-		return
+	setOperationProgress(ctx, "Tagging resource")
+	tagName, err := s.createTag(ctx, resourceInfo, resourceResp.Uri, resourceResp.Ip)
+	if err != nil {
+		return nil, err
 	}
 
 	resourceResp.Name = tagName
-	c.JSON(http.StatusOK, resourceResp)
+	return resourceResp, nil
 }
 
-func (s *ControllerServer) resourceAttach(c *gin.Context, resourceInfo *ResourceInfo, cloudClient string) {
+func (s *ControllerServer) resourceAttach(ctx context.Context, resourceInfo *ResourceInfo, cloudClient string) (*paragliderpb.AttachResourceResponse, error) {
 	// Create connection to cloud plugin
-	conn, err := grpc.NewClient(cloudClient, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.pluginConns.Get(cloudClient)
 	if err != nil {
-		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-		return
+		return nil, err
 	}
-	defer conn.Close()
 
 	// Send RPC to attach resource
+	setOperationProgress(ctx, "Attaching resource")
 	attachResourceReq := paragliderpb.AttachResourceRequest{
 		Namespace: resourceInfo.namespace,
 		Resource:  resourceInfo.uri,
 	}
 	client := paragliderpb.NewCloudPluginClient(conn)
-	attachResourceResp, err := client.AttachResource(context.Background(), &attachResourceReq)
+	attachResourceResp, err := client.AttachResource(ctx, &attachResourceReq)
 	if err != nil {
-		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-		return
+		return nil, err
 	}
 
 	// Set Paraglider tag
+	setOperationProgress(ctx, "Tagging resource")
 	resourceInfo.name = attachResourceResp.Name
-	tagName := s.createTag(c, resourceInfo, attachResourceResp.Uri, attachResourceResp.Ip)
-	if tagName == "" {
-		return
+	tagName, err := s.createTag(ctx, resourceInfo, attachResourceResp.Uri, attachResourceResp.Ip)
+	if err != nil {
+		return nil, err
 	}
 
 	attachResourceResp.Name = tagName
-	c.JSON(http.StatusOK, attachResourceResp)
+	return attachResourceResp, nil
 }
 
-func (s *ControllerServer) createTag(c *gin.Context, resourceInfo *ResourceInfo, uri string, ip string) string {
-	conn, err := grpc.NewClient(s.localTagService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+func (s *ControllerServer) createTag(ctx context.Context, resourceInfo *ResourceInfo, uri string, ip string) (string, error) {
+	conn, err := s.serviceConns.Get(s.localTagService)
 	if err != nil {
-		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
-		return ""
+		return "", err
 	}
-	defer conn.Close()
 
 	tagName := getTagName(resourceInfo.namespace, resourceInfo.cloud, resourceInfo.name)
 	tagClient := tagservicepb.NewTagServiceClient(conn)
-	_, err = tagClient.SetTag(context.Background(), &tagservicepb.SetTagRequest{Tag: &tagservicepb.TagMapping{Name: tagName, Uri: &uri, Ip: &ip}})
+	_, err = tagClient.SetTag(ctx, &tagservicepb.SetTagRequest{Tag: &tagservicepb.TagMapping{Name: tagName, Uri: &uri, Ip: &ip}})
+	if err != nil {
+		return "", err // TODO @smcclure20: change this to a warning?
+	}
+
+	return tagName, nil
+}
+
+// Delete a resource, remove its tag, and unsubscribe it from all tags referenced in its permit list
+func (s *ControllerServer) resourceDelete(c *gin.Context) {
+	s.removeResource(c, false)
+}
+
+// Detach a resource from Paraglider while leaving it running in the cloud
+func (s *ControllerServer) resourceDetach(c *gin.Context) {
+	s.removeResource(c, true)
+}
+
+// Delete or detach a resource, then remove its tag and unsubscribe it from all tags referenced in its permit list
+func (s *ControllerServer) removeResource(c *gin.Context, detach bool) {
+	resourceInfo, cloudClient, err := s.getAndValidateResourceURLParams(c, true)
 	if err != nil {
-		c.AbortWithStatusJSON(400, createErrorResponse(err.Error())) // TODO @smcclure20: change this to a warning?
-		return ""
+		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
+		return
 	}
 
-	return tagName
+	opType := DeleteResourceOperation
+	if detach {
+		opType = DetachResourceOperation
+	}
+	s.runOperation(c, opType, func(ctx context.Context) (any, error) {
+		if err := s._removeResource(ctx, resourceInfo, cloudClient, detach); err != nil {
+			return nil, err
+		}
+		return &RequestNotices{}, nil
+	})
+}
+
+func (s *ControllerServer) _removeResource(ctx context.Context, resourceInfo *ResourceInfo, cloudClient string, detach bool) error {
+	// Create connection to cloud plugin
+	conn, err := s.pluginConns.Get(cloudClient)
+	if err != nil {
+		return err
+	}
+	client := paragliderpb.NewCloudPluginClient(conn)
+
+	// Get the permit list before removal to tell which tags should be unsubscribed
+	permitList, err := client.GetPermitList(ctx, &paragliderpb.GetPermitListRequest{Resource: resourceInfo.uri, Namespace: resourceInfo.namespace})
+	if err != nil {
+		return err
+	}
+
+	// Send RPC to detach or delete the resource
+	if detach {
+		setOperationProgress(ctx, "Detaching resource")
+		_, err = client.DetachResource(ctx, &paragliderpb.DetachResourceRequest{Resource: resourceInfo.uri, Namespace: resourceInfo.namespace})
+	} else {
+		// Allocations which are still recorded are never handed out again, even once the resource using them is gone
+		if err := s.releaseAllocations(ctx); err != nil {
+			slog.WarnContext(ctx, "Unable to release allocations", "error", err)
+		}
+		setOperationProgress(ctx, "Deleting resource")
+		_, err = client.DeleteResource(ctx, &paragliderpb.DeleteResourceRequest{Resource: resourceInfo.uri, Namespace: resourceInfo.namespace})
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.forgetRuleExpirations(ctx, resourceInfo, nil); err != nil {
+		return err
+	}
+	if err := s.forgetExpectedRules(ctx, resourceInfo, nil); err != nil {
+		return err
+	}
+
+	// Every tag referenced by the permit list is now dereferenced
+	setOperationProgress(ctx, "Removing tag")
+	if err := s.checkAndUnsubscribe(ctx, resourceInfo, permitList.Rules, []*paragliderpb.PermitListRule{}); err != nil {
+		return err
+	}
+
+	// Remove the resource's tag and update anyone referencing it
+	tagConn, err := s.serviceConns.Get(s.localTagService)
+	if err != nil {
+		return err
+	}
+
+	tagName := getTagName(resourceInfo.namespace, resourceInfo.cloud, resourceInfo.name)
+	tagClient := tagservicepb.NewTagServiceClient(tagConn)
+	_, err = tagClient.DeleteTag(ctx, &tagservicepb.DeleteTagRequest{TagName: tagName})
+	if err != nil {
+		return err
+	}
+	return s.updateSubscribers(ctx, tagName)
 }
 
 // List all tags from local tag service
 func (s *ControllerServer) listTags(c *gin.Context) {
 	// Call listTags locally
-	conn, err := grpc.NewClient(s.localTagService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.serviceConns.Get(s.localTagService)
 	if err != nil {
 		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
 		return
 	}
-	defer conn.Close()
 
 	// Send RPC to list tags
 	client := tagservicepb.NewTagServiceClient(conn)
-	response, err := client.ListTags(context.Background(), &tagservicepb.ListTagsRequest{})
+	response, err := client.ListTags(c.Request.Context(), &tagservicepb.ListTagsRequest{})
 	if err != nil {
 		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
+		return
 	}
 
-	c.JSON(http.StatusOK, response.Tags)
+	// Only list the tags the principal may read
+	tags := slices.DeleteFunc(response.Tags, func(tag *tagservicepb.TagMapping) bool {
+		return !s.authorized(c, auth.ReadAction, auth.Scope{Tag: tag.Name})
+	})
+	c.JSON(http.StatusOK, tags)
 }
 
 // Get tag from local tag service
 func (s *ControllerServer) getTag(c *gin.Context) {
 	// Call getTag locally
-	conn, err := grpc.NewClient(s.localTagService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.serviceConns.Get(s.localTagService)
 	if err != nil {
 		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
 		return
 	}
-	defer conn.Close()
 
 	// Send RPC to get tag
 	tag := c.Param("tag")
 	client := tagservicepb.NewTagServiceClient(conn)
-	response, err := client.GetTag(context.Background(), &tagservicepb.GetTagRequest{TagName: tag})
+	response, err := client.GetTag(c.Request.Context(), &tagservicepb.GetTagRequest{TagName: tag})
 	if err != nil {
 		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
 		return
@@ -1313,17 +1659,16 @@ func (s *ControllerServer) getTag(c *gin.Context) {
 // Resolve tag down to IP/URI(s) from local tag service
 func (s *ControllerServer) resolveTag(c *gin.Context) {
 	// Call resolveTag locally
-	conn, err := grpc.NewClient(s.localTagService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.serviceConns.Get(s.localTagService)
 	if err != nil {
 		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
 		return
 	}
-	defer conn.Close()
 
 	// Send RPC to get tag
 	tag := c.Param("tag")
 	client := tagservicepb.NewTagServiceClient(conn)
-	response, err := client.ResolveTag(context.Background(), &tagservicepb.ResolveTagRequest{TagName: tag})
+	response, err := client.ResolveTag(c.Request.Context(), &tagservicepb.ResolveTagRequest{TagName: tag})
 	if err != nil {
 		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
 		return
@@ -1341,16 +1686,15 @@ func clearRuleTargets(rules []*paragliderpb.PermitListRule) []*paragliderpb.Perm
 }
 
 // Update subscribers to a tag about membership changes
-func (s *ControllerServer) updateSubscribers(tag string) error {
+func (s *ControllerServer) updateSubscribers(ctx context.Context, tag string) error {
 	// Get the subscribers to the tag
-	conn, err := grpc.NewClient(s.localTagService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.serviceConns.Get(s.localTagService)
 	if err != nil {
 		return err
 	}
-	defer conn.Close()
 
 	client := tagservicepb.NewTagServiceClient(conn)
-	response, err := client.GetSubscribers(context.Background(), &tagservicepb.GetSubscribersRequest{TagName: tag})
+	response, err := client.GetSubscribers(ctx, &tagservicepb.GetSubscribersRequest{TagName: tag})
 	if err != nil {
 		return err
 	}
@@ -1358,20 +1702,28 @@ func (s *ControllerServer) updateSubscribers(tag string) error {
 	// For each subscriber, get the current permit list, clear target fields, and re-apply the resolved rules
 	for _, subscriber := range response.Subscribers {
 		namespace, cloud, uri := parseSubscriberName(subscriber)
-		cloudClient, ok := s.pluginAddresses[cloud]
+		cloudClient, ok := s.getPluginAddress(cloud)
 		if !ok {
 			return fmt.Errorf("invalid cloud name in subscriber name %s for tag %s", subscriber, tag)
 		}
 
-		getResp, err := s._permitListGet(namespace, uri, cloudClient)
+		getResp, err := s._permitListGet(ctx, namespace, uri, cloudClient)
 		if err != nil {
 			return err
 		}
 
+		// Rules are re-applied as they were added, so that changes made to them in the cloud are still reported as drift
 		rules := clearRuleTargets(getResp.Rules)
+		if expectedRules, ok := s.getExpectedRules(namespace, uri); ok {
+			for i, rule := range rules {
+				if j := slices.IndexFunc(expectedRules, func(expected *paragliderpb.PermitListRule) bool { return expected.Name == rule.Name }); j >= 0 {
+					rules[i] = expectedRules[j]
+				}
+			}
+		}
 
 		addRequest := &paragliderpb.AddPermitListRulesRequest{Rules: rules, Namespace: namespace, Resource: uri}
-		_, err = s._permitListRulesAdd(addRequest, &ResourceInfo{namespace: namespace, cloud: cloud, uri: uri}, cloudClient)
+		_, err = s._permitListRulesAdd(ctx, addRequest, &ResourceInfo{namespace: namespace, cloud: cloud, uri: uri}, cloudClient)
 		if err != nil {
 			return err
 		}
@@ -1390,21 +1742,20 @@ func (s *ControllerServer) setTag(c *gin.Context) {
 	}
 
 	// Call SetTag
-	conn, err := grpc.NewClient(s.localTagService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.serviceConns.Get(s.localTagService)
 	if err != nil {
 		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
 		return
 	}
-	defer conn.Close()
 
 	client := tagservicepb.NewTagServiceClient(conn)
-	_, err = client.SetTag(context.Background(), &tagservicepb.SetTagRequest{Tag: &tag})
+	_, err = client.SetTag(c.Request.Context(), &tagservicepb.SetTagRequest{Tag: &tag})
 	if err != nil {
 		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
 		return
 	}
 	// Look up subscribers and re-resolve the tag
-	if err := s.updateSubscribers(tag.Name); err != nil {
+	if err := s.updateSubscribers(c.Request.Context(), tag.Name); err != nil {
 		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
 		return
 	}
@@ -1417,15 +1768,14 @@ func (s *ControllerServer) deleteTag(c *gin.Context) {
 	tagName := c.Param("tag")
 
 	// Call DeleteTag
-	conn, err := grpc.NewClient(s.localTagService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.serviceConns.Get(s.localTagService)
 	if err != nil {
 		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
 		return
 	}
-	defer conn.Close()
 
 	client := tagservicepb.NewTagServiceClient(conn)
-	_, err = client.DeleteTag(context.Background(), &tagservicepb.DeleteTagRequest{TagName: tagName})
+	_, err = client.DeleteTag(c.Request.Context(), &tagservicepb.DeleteTagRequest{TagName: tagName})
 	if err != nil {
 		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
 		return
@@ -1433,7 +1783,7 @@ func (s *ControllerServer) deleteTag(c *gin.Context) {
 
 	// Look up subscribers and re-resolve the tags
 	// Note that deleting the tag does not remove it from the list, but it does resolve to nothing
-	if err := s.updateSubscribers(tagName); err != nil {
+	if err := s.updateSubscribers(c.Request.Context(), tagName); err != nil {
 		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
 		return
 	}
@@ -1448,22 +1798,21 @@ func (s *ControllerServer) deleteTagMember(c *gin.Context) {
 	tag := &tagservicepb.TagMapping{Name: parentTag, ChildTags: []string{memberTag}}
 
 	// Call DeleteTagMember
-	conn, err := grpc.NewClient(s.localTagService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.serviceConns.Get(s.localTagService)
 	if err != nil {
 		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
 		return
 	}
-	defer conn.Close()
 
 	client := tagservicepb.NewTagServiceClient(conn)
-	_, err = client.DeleteTagMember(context.Background(), &tagservicepb.DeleteTagMemberRequest{ParentTag: parentTag, ChildTag: memberTag})
+	_, err = client.DeleteTagMember(c.Request.Context(), &tagservicepb.DeleteTagMemberRequest{ParentTag: parentTag, ChildTag: memberTag})
 	if err != nil {
 		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
 		return
 	}
 
 	// Look up subscribers and re-resolve the tag
-	if err := s.updateSubscribers(tag.Name); err != nil {
+	if err := s.updateSubscribers(c.Request.Context(), tag.Name); err != nil {
 		c.AbortWithStatusJSON(400, createErrorResponse(err.Error()))
 		return
 	}
@@ -1473,16 +1822,24 @@ func (s *ControllerServer) deleteTagMember(c *gin.Context) {
 
 // List all configured namespaces
 func (s *ControllerServer) listNamespaces(c *gin.Context) {
-	c.JSON(http.StatusOK, s.config.Namespaces)
+	// Only list the namespaces the principal may read
+	namespaces := make(map[string][]config.CloudDeployment)
+	s.namespacesLock.RLock()
+	defer s.namespacesLock.RUnlock()
+	for namespace, deployments := range s.config.Namespaces {
+		if s.authorized(c, auth.ReadAction, auth.Scope{Namespace: namespace}) {
+			namespaces[namespace] = deployments
+		}
+	}
+	c.JSON(http.StatusOK, namespaces)
 }
 
 // Get a value from the KV store
 func (s *ControllerServer) GetValue(c context.Context, req *paragliderpb.GetValueRequest) (*paragliderpb.GetValueResponse, error) {
-	conn, err := grpc.NewClient(s.localKVStoreService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.serviceConns.Get(s.localKVStoreService)
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
 
 	client := storepb.NewKVStoreClient(conn)
 
@@ -1495,11 +1852,10 @@ func (s *ControllerServer) GetValue(c context.Context, req *paragliderpb.GetValu
 
 // Set a value in the KV store
 func (s *ControllerServer) SetValue(c context.Context, req *paragliderpb.SetValueRequest) (*paragliderpb.SetValueResponse, error) {
-	conn, err := grpc.NewClient(s.localKVStoreService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.serviceConns.Get(s.localKVStoreService)
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
 
 	client := storepb.NewKVStoreClient(conn)
 
@@ -1512,17 +1868,16 @@ func (s *ControllerServer) SetValue(c context.Context, req *paragliderpb.SetValu
 
 // Delete a value in the KV store
 func (s *ControllerServer) DeleteValue(c context.Context, req *paragliderpb.DeleteValueRequest) (*paragliderpb.DeleteValueResponse, error) {
-	conn, err := grpc.NewClient(s.localKVStoreService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := s.serviceConns.Get(s.localKVStoreService)
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
 
 	client := storepb.NewKVStoreClient(conn)
 
 	_, err = client.Delete(c, &storepb.DeleteRequest{Key: req.Key, Namespace: req.Namespace, Cloud: req.Cloud})
 	if err != nil {
-		fmt.Printf("Error getting KV store service connection: %s\n", err.Error())
+		slog.ErrorContext(c, "Failed to delete value from KV store", "key", req.Key, "error", err)
 		return nil, err
 	}
 	return &paragliderpb.DeleteValueResponse{}, nil
@@ -1533,7 +1888,7 @@ func SetupWithFile(configPath string, background bool) {
 	// Read the config
 	f, err := os.Open(configPath)
 	if err != nil {
-		fmt.Println(err.Error())
+		slog.Error("Failed to open config file", "path", configPath, "error", err)
 	}
 	defer f.Close()
 
@@ -1541,7 +1896,7 @@ func SetupWithFile(configPath string, background bool) {
 	decoder := yaml.NewDecoder(f)
 	err = decoder.Decode(&cfg)
 	if err != nil {
-		fmt.Println(err.Error())
+		slog.Error("Failed to decode config file", "path", configPath, "error", err)
 	}
 
 	Setup(cfg, background)
@@ -1549,16 +1904,74 @@ func SetupWithFile(configPath string, background bool) {
 
 // Setup and run the server
 func Setup(cfg config.Config, background bool) {
+	if err := utils.SetupLogging(cfg.Logging); err != nil {
+		slog.Error("Failed to setup logging", "error", err)
+		return
+	}
+	shutdownTracing, err := utils.SetupTracing(context.Background(), tracingServiceName, cfg.Tracing)
+	if err != nil {
+		slog.Error("Failed to setup tracing", "error", err)
+		return
+	}
+
 	// Populate server info
 	server := ControllerServer{
 		config:                    cfg,
 		pluginAddresses:           make(map[string]string),
+		plugins:                   make(map[string]*pluginState),
 		usedBgpPeeringIpAddresses: make(map[string][]string),
 		namespace:                 "default",
+		createdNamespaces:         make(map[string]string),
+		ruleExpirations:           make(map[ruleExpirationKey]*ruleExpiration),
+		operations:                newOperationTracker(),
 	}
 	server.localTagService = cfg.TagService.Host + ":" + cfg.TagService.Port
 	server.localKVStoreService = cfg.KVStore.Host + ":" + cfg.KVStore.Port
 
+	server.grpcCredentials, err = utils.LoadTLSCredentials(cfg.Server.TLS)
+	if err != nil {
+		slog.Error("Failed to setup TLS", "error", err)
+		return
+	}
+
+	server.setupConnPools()
+
+	allocations, err := store.New(cfg.AllocationStore.Type, cfg.AllocationStore.Path, server.localKVStoreService, server.serviceConns)
+	if err != nil {
+		slog.Error("Failed to setup allocation store", "error", err)
+		return
+	}
+	server.allocations = allocations
+	if !store.IsPersistent(cfg.AllocationStore.Type) {
+		slog.Warn("The allocation store is kept in memory, so address spaces, ASNs and BGP peering IP addresses may be handed out twice after the controller restarts. Set allocationStore.type to file or kvstore to persist them", "type", cfg.AllocationStore.Type)
+		slog.Warn("The audit log is kept in the allocation store, so it is lost when the controller restarts. Set allocationStore.type to file or kvstore to persist it", "type", cfg.AllocationStore.Type)
+	}
+	if err := server.loadNamespaces(context.Background()); err != nil {
+		slog.Error("Failed to load namespaces", "error", err)
+		return
+	}
+	if err := server.loadRuleExpirations(context.Background()); err != nil {
+		slog.Error("Failed to load rule expirations", "error", err)
+		return
+	}
+	if err := server.loadExpectedRules(context.Background()); err != nil {
+		slog.Error("Failed to load expected rules", "error", err)
+		return
+	}
+
+	if cfg.Auth.Enabled() {
+		server.auth, err = auth.New(cfg.Auth)
+		if err != nil {
+			slog.Error("Failed to setup authentication", "error", err)
+			return
+		}
+	}
+	restTLSConfig, err := newRestTLSConfig(cfg.Auth.MTLS)
+	if err != nil {
+		slog.Error("Failed to setup TLS", "error", err)
+		return
+	}
+
 	for _, c := range cfg.CloudPlugins {
 		server.pluginAddresses[c.Name] = c.Host + ":" + c.Port
 	}
@@ -1568,27 +1981,52 @@ func Setup(cfg config.Config, background bool) {
 		server.config.AddressSpace = []string{defaultAddressSpace}
 	}
 
+	// Periodically check the permit lists in the clouds for drift
+	if cfg.DriftDetection.Interval != "" {
+		interval, err := time.ParseDuration(cfg.DriftDetection.Interval)
+		if err != nil || interval <= 0 {
+			slog.Error("Invalid drift detection interval", "interval", cfg.DriftDetection.Interval)
+			return
+		}
+		go server.runDriftDetection(interval, cfg.DriftDetection.Reconcile)
+	}
+
+	// Delete the rules added with an expiration time once they expire
+	go server.runRuleExpirations(ruleExpirationInterval)
+
 	// Setup GRPC server
 	lis, err := net.Listen("tcp", cfg.Server.Host+":"+cfg.Server.RpcPort)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to listen: %v", err)
+		slog.Error("Failed to listen", "error", err)
+	}
+	interceptors := []grpc.UnaryServerInterceptor{utils.MetricsServerInterceptor, utils.LogFieldsServerInterceptor()}
+	if server.auth != nil && cfg.Auth.ControllerRpc {
+		interceptors = append(interceptors, server.authUnaryInterceptor)
 	}
-	grpcServer := grpc.NewServer()
+	interceptors = append(interceptors, server.auditUnaryInterceptor, utils.NoticesServerInterceptor)
+	grpcServer := grpc.NewServer(grpc.Creds(server.grpcCredentials), grpc.ChainUnaryInterceptor(interceptors...), utils.TracingServerOption())
 	paragliderpb.RegisterControllerServer(grpcServer, &server)
+	allocationMetrics.setStore(server.allocations)
 
 	go func() {
 		if err := grpcServer.Serve(lis); err != nil {
-			fmt.Println(err.Error())
+			slog.Error("Controller gRPC server stopped", "error", err)
 		}
 	}()
 
 	// Setup URL router
-	router := gin.Default()
+	router := gin.New()
+	router.Use(gin.Recovery(), otelgin.Middleware(tracingServiceName), restMetricsMiddleware, requestLoggingMiddleware, logFieldsMiddleware, server.auditMiddleware)
 	router.GET("/ping", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"message": "pong",
 		})
 	})
+	router.GET(MetricsURL, gin.WrapH(utils.MetricsHandler()))
+	// Routes registered from here on require authentication (if enabled)
+	if server.auth != nil {
+		router.Use(server.authMiddleware)
+	}
 	router.GET(GetPermitListRulesURL, server.permitListGet)
 	router.POST(AddPermitListRulesURL, server.permitListRulesBulkAdd)
 	router.POST(PermitListRulePOSTURL, server.permitListRuleAdd)
@@ -1597,6 +2035,8 @@ func Setup(cfg config.Config, background bool) {
 	router.DELETE(PermitListRulePUTURL, server.permitListRuleDelete)
 	router.PUT(CreateResourcePUTURL, server.handleCreateOrAttachResource)
 	router.POST(CreateOrAttachResourcePOSTURL, server.handleCreateOrAttachResource)
+	router.DELETE(DeleteResourceURL, server.resourceDelete)
+	router.POST(DetachResourceURL, server.resourceDetach)
 	router.POST(RuleOnTagURL, server.permitListRuleAddTag)
 	router.DELETE(RuleOnTagURL, server.permitListRuleDeleteTag)
 	router.GET(ListTagURL, server.listTags)
@@ -1606,19 +2046,36 @@ func Setup(cfg config.Config, background bool) {
 	router.DELETE(DeleteTagURL, server.deleteTag)
 	router.DELETE(DeleteTagMemberURL, server.deleteTagMember)
 	router.GET(ListNamespacesURL, server.listNamespaces)
+	router.GET(ListPluginsURL, server.listPlugins)
+	router.POST(CreateNamespaceURL, server.createNamespace)
+	router.PUT(SetNamespaceCloudURL, server.setNamespaceCloud)
+	router.DELETE(DeleteNamespaceURL, server.deleteNamespace)
+	router.POST(ApplyManifestURL, server.applyManifest)
+	router.GET(ListOperationsURL, server.listOperations)
+	router.GET(GetOperationURL, server.getOperation)
+	router.POST(CancelOperationURL, server.cancelOperation)
+	router.GET(DriftURL, server.getDriftReport)
+	router.POST(CheckDriftURL, server.checkDriftNow)
+	router.GET(AuditURL, server.listAuditEvents)
 
 	// Run server
-	if background {
-		go func() {
-			err = router.Run(cfg.Server.Host + ":" + cfg.Server.Port)
-			if err != nil {
-				fmt.Println(err.Error())
-			}
-		}()
-	} else {
-		err = router.Run(cfg.Server.Host + ":" + cfg.Server.Port)
+	httpServer := &http.Server{Addr: cfg.Server.Host + ":" + cfg.Server.Port, Handler: router, TLSConfig: restTLSConfig}
+	run := func() {
+		if restTLSConfig != nil {
+			err = httpServer.ListenAndServeTLS("", "")
+		} else {
+			err = httpServer.ListenAndServe()
+		}
 		if err != nil {
-			fmt.Println(err.Error())
+			slog.Error("Controller REST server stopped", "error", err)
+		}
+		if err := shutdownTracing(context.Background()); err != nil {
+			slog.Error("Failed to flush traces", "error", err)
 		}
 	}
+	if background {
+		go run()
+	} else {
+		run()
+	}
 }
@@ -26,14 +26,18 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strconv"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	config "github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+	store "github.com/paraglider-project/paraglider/pkg/orchestrator/store"
 	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
 	tagservicepb "github.com/paraglider-project/paraglider/pkg/tag_service/tagservicepb"
 
@@ -63,8 +67,13 @@ func newOrchestratorServer() *ControllerServer {
 		pluginAddresses:           make(map[string]string),
 		usedBgpPeeringIpAddresses: make(map[string][]string),
 		namespace:                 defaultNamespace,
+		createdNamespaces:         make(map[string]string),
+		ruleExpirations:           make(map[ruleExpirationKey]*ruleExpiration),
 		config:                    config.Config{AddressSpace: []string{defaultAddressSpace}},
+		allocations:               store.NewMemoryStore(),
+		operations:                newOperationTracker(),
 	}
+	s.setupConnPools()
 	return s
 }
 
@@ -540,6 +549,100 @@ func TestPermitListRuleDelete(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestDeleteResource(t *testing.T) {
+	// Setup
+	orchestratorServer := newOrchestratorServer()
+	tagServerPort := getNewPortNumber()
+	cloudPluginPort := getNewPortNumber()
+	orchestratorServer.pluginAddresses[exampleCloudName] = fmt.Sprintf("localhost:%d", cloudPluginPort)
+	orchestratorServer.localTagService = fmt.Sprintf("localhost:%d", tagServerPort)
+
+	fakeplugin.SetupFakePluginServer(cloudPluginPort)
+	faketagservice.SetupFakeTagServer(tagServerPort)
+
+	orchestratorServer.config.CloudPlugins = []config.CloudPlugin{{Name: exampleCloudName, Host: "localhost", Port: strconv.Itoa(cloudPluginPort)}}
+	err := orchestratorServer.allocations.Add(context.Background(), store.AddressSpacesKey, fakeplugin.AddressSpaceAddress)
+	require.NoError(t, err)
+
+	r := SetUpRouter()
+	r.DELETE(DeleteResourceURL, orchestratorServer.resourceDelete)
+
+	// Well-formed request
+	name := faketagservice.ValidLastLevelTagName
+
+	url := fmt.Sprintf(GetFormatterString(DeleteResourceURL), defaultNamespace, exampleCloudName, name)
+	req, _ := http.NewRequest("DELETE", url, nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	// The address space used by the plugin is no longer recorded, so it can be handed out again once deleted
+	allocatedAddressSpaces, err := orchestratorServer.allocations.List(context.Background(), store.AddressSpacesKey)
+	require.NoError(t, err)
+	assert.Empty(t, allocatedAddressSpaces)
+
+	// Invalid resource name
+	badName := "badname"
+
+	url = fmt.Sprintf(GetFormatterString(DeleteResourceURL), defaultNamespace, exampleCloudName, badName)
+	req, _ = http.NewRequest("DELETE", url, nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Bad cloud name
+	url = fmt.Sprintf(GetFormatterString(DeleteResourceURL), defaultNamespace, "wrong", name)
+	req, _ = http.NewRequest("DELETE", url, nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDetachResource(t *testing.T) {
+	// Setup
+	orchestratorServer := newOrchestratorServer()
+	tagServerPort := getNewPortNumber()
+	cloudPluginPort := getNewPortNumber()
+	orchestratorServer.pluginAddresses[exampleCloudName] = fmt.Sprintf("localhost:%d", cloudPluginPort)
+	orchestratorServer.localTagService = fmt.Sprintf("localhost:%d", tagServerPort)
+
+	fakeplugin.SetupFakePluginServer(cloudPluginPort)
+	faketagservice.SetupFakeTagServer(tagServerPort)
+
+	r := SetUpRouter()
+	r.POST(DetachResourceURL, orchestratorServer.resourceDetach)
+
+	// Well-formed request
+	name := faketagservice.ValidLastLevelTagName
+
+	url := fmt.Sprintf(GetFormatterString(DetachResourceURL), defaultNamespace, exampleCloudName, name)
+	req, _ := http.NewRequest("POST", url, nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Invalid resource name
+	badName := "badname"
+
+	url = fmt.Sprintf(GetFormatterString(DetachResourceURL), defaultNamespace, exampleCloudName, badName)
+	req, _ = http.NewRequest("POST", url, nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Bad cloud name
+	url = fmt.Sprintf(GetFormatterString(DetachResourceURL), defaultNamespace, "wrong", name)
+	req, _ = http.NewRequest("POST", url, nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestCreateResourcePost(t *testing.T) {
 	// Setup
 	orchestratorServer := newOrchestratorServer()
@@ -709,12 +812,12 @@ func TestGetAddressSpaces(t *testing.T) {
 	fakeplugin.SetupFakePluginServer(port)
 
 	// Well-formed call
-	addressSpaceMappings, _ := orchestratorServer.getAddressSpaces(exampleCloudName)
+	addressSpaceMappings, _ := orchestratorServer.getAddressSpaces(context.Background(), exampleCloudName)
 	assert.Len(t, addressSpaceMappings, 1)
 	assert.Equal(t, addressSpaceMappings[0].AddressSpaces[0], fakeplugin.AddressSpaceAddress)
 
 	// Bad cloud name
-	emptyList, err := orchestratorServer.getAddressSpaces("wrong")
+	emptyList, err := orchestratorServer.getAddressSpaces(context.Background(), "wrong")
 	require.NotNil(t, err)
 
 	require.Nil(t, emptyList)
@@ -727,75 +830,88 @@ func TestUpdateUsedAddressSpacesMap(t *testing.T) {
 
 	fakeplugin.SetupFakePluginServer(port)
 
-	// Valid cloud list
-	cloud := config.CloudPlugin{Name: exampleCloudName, Host: "localhost", Port: strconv.Itoa(port)}
-	orchestratorServer.config = config.Config{CloudPlugins: []config.CloudPlugin{cloud}}
-	err := orchestratorServer.updateUsedAddressSpaces()
+	// Plugins are queried whether they're configured or registered themselves
+	err := orchestratorServer.updateUsedAddressSpaces(context.Background())
 	require.Nil(t, err)
 	assert.Len(t, orchestratorServer.usedAddressSpaces, 1)
 	assert.Equal(t, orchestratorServer.usedAddressSpaces[0].AddressSpaces[0], fakeplugin.AddressSpaceAddress)
 
-	// Invalid cloud list
-	cloud = config.CloudPlugin{Name: "wrong", Host: "localhost", Port: strconv.Itoa(port)}
-	orchestratorServer.config = config.Config{CloudPlugins: []config.CloudPlugin{cloud}}
-	err = orchestratorServer.updateUsedAddressSpaces()
+	// Repeated updates replace rather than accumulate
+	err = orchestratorServer.updateUsedAddressSpaces(context.Background())
+	require.Nil(t, err)
+	assert.Len(t, orchestratorServer.usedAddressSpaces, 1)
+
+	// Unreachable plugin
+	orchestratorServer.pluginAddresses["wrong"] = fmt.Sprintf("localhost:%d", getNewPortNumber())
+	err = orchestratorServer.updateUsedAddressSpaces(context.Background())
 
 	require.NotNil(t, err)
 }
 
 func TestFindUnusedAddressSpaces(t *testing.T) {
-	orchestratorServer := newOrchestratorServer()
+	ctx := context.Background()
+
 	// No entries in address space map
-	resp, err := orchestratorServer.FindUnusedAddressSpaces(context.Background(), &paragliderpb.FindUnusedAddressSpacesRequest{})
+	orchestratorServer := newOrchestratorServer()
+	resp, err := orchestratorServer.FindUnusedAddressSpaces(ctx, &paragliderpb.FindUnusedAddressSpacesRequest{})
 	require.Nil(t, err)
 	assert.Equal(t, resp.AddressSpaces[0], "10.0.0.0/16")
 
-	// Next entry
-	orchestratorServer.usedAddressSpaces = []*paragliderpb.AddressSpaceMapping{
-		{
-			AddressSpaces: []string{"10.0.0.0/16"},
-			Cloud:         exampleCloudName,
-			Namespace:     defaultNamespace,
-		},
-	}
-	resp, err = orchestratorServer.FindUnusedAddressSpaces(context.Background(), &paragliderpb.FindUnusedAddressSpacesRequest{})
+	// Next entry (address spaces handed out before remain allocated)
+	resp, err = orchestratorServer.FindUnusedAddressSpaces(ctx, &paragliderpb.FindUnusedAddressSpacesRequest{})
 	require.Nil(t, err)
 	assert.Equal(t, resp.AddressSpaces[0], "10.1.0.0/16")
 
-	// Account for all namespaces
-	orchestratorServer.usedAddressSpaces = []*paragliderpb.AddressSpaceMapping{
-		{
-			AddressSpaces: []string{"10.0.0.0/16"},
-			Cloud:         exampleCloudName,
-			Namespace:     defaultNamespace,
-		},
-		{
-			AddressSpaces: []string{"10.1.0.0/16"},
-			Cloud:         exampleCloudName,
-			Namespace:     "otherNamespace",
-		},
-	}
-	resp, err = orchestratorServer.FindUnusedAddressSpaces(context.Background(), &paragliderpb.FindUnusedAddressSpacesRequest{})
+	// Account for both address spaces used by plugins and allocated ones
+	orchestratorServer = newOrchestratorServer()
+	port := getNewPortNumber()
+	orchestratorServer.pluginAddresses[exampleCloudName] = fmt.Sprintf("localhost:%d", port)
+	fakeplugin.SetupFakePluginServer(port)
+	orchestratorServer.config.CloudPlugins = []config.CloudPlugin{{Name: exampleCloudName, Host: "localhost", Port: strconv.Itoa(port)}}
+	err = orchestratorServer.allocations.Add(ctx, store.AddressSpacesKey, "10.1.0.0/16")
+	require.Nil(t, err)
+	resp, err = orchestratorServer.FindUnusedAddressSpaces(ctx, &paragliderpb.FindUnusedAddressSpacesRequest{})
 	require.Nil(t, err)
 	assert.Equal(t, resp.AddressSpaces[0], "10.2.0.0/16")
+	allocatedAddressSpaces, err := orchestratorServer.allocations.List(ctx, store.AddressSpacesKey)
+	require.Nil(t, err)
+	assert.ElementsMatch(t, []string{"10.1.0.0/16", "10.2.0.0/16"}, allocatedAddressSpaces)
 
 	// Multiple spaces
-	orchestratorServer.usedAddressSpaces = []*paragliderpb.AddressSpaceMapping{}
-	resp, err = orchestratorServer.FindUnusedAddressSpaces(context.Background(), &paragliderpb.FindUnusedAddressSpacesRequest{Sizes: []int32{200, 1000, 4}})
+	orchestratorServer = newOrchestratorServer()
+	resp, err = orchestratorServer.FindUnusedAddressSpaces(ctx, &paragliderpb.FindUnusedAddressSpacesRequest{Sizes: []int32{200, 1000, 4}})
 	require.Nil(t, err)
 	assert.Equal(t, resp.AddressSpaces[0], "10.0.0.0/24")
 	assert.Equal(t, resp.AddressSpaces[1], "10.1.0.0/22")
 	assert.Equal(t, resp.AddressSpaces[2], "10.1.4.0/30")
 
 	// Out of addresses
-	orchestratorServer.usedAddressSpaces = []*paragliderpb.AddressSpaceMapping{
-		{
-			AddressSpaces: []string{"10.0.0.0/10", "10.64.0.0/10", "10.128.0.0/10", "10.192.0.0/10"},
-			Cloud:         exampleCloudName,
-			Namespace:     defaultNamespace,
-		},
-	}
-	_, err = orchestratorServer.FindUnusedAddressSpaces(context.Background(), &paragliderpb.FindUnusedAddressSpacesRequest{})
+	orchestratorServer = newOrchestratorServer()
+	err = orchestratorServer.allocations.Add(ctx, store.AddressSpacesKey, "10.0.0.0/10", "10.64.0.0/10", "10.128.0.0/10", "10.192.0.0/10")
+	require.Nil(t, err)
+	_, err = orchestratorServer.FindUnusedAddressSpaces(ctx, &paragliderpb.FindUnusedAddressSpacesRequest{})
+	require.NotNil(t, err)
+
+	// No IPv6 address space configured
+	orchestratorServer = newOrchestratorServer()
+	resp, err = orchestratorServer.FindUnusedAddressSpaces(ctx, &paragliderpb.FindUnusedAddressSpacesRequest{Ipv6: true})
+	require.Nil(t, err)
+	assert.Empty(t, resp.AddressSpaces)
+
+	// IPv6 address spaces
+	orchestratorServer = newOrchestratorServer()
+	orchestratorServer.config.Ipv6AddressSpace = []string{"fd20:a::/46"}
+	err = orchestratorServer.allocations.Add(ctx, store.AddressSpacesKey, "fd20:a::/48")
+	require.Nil(t, err)
+	resp, err = orchestratorServer.FindUnusedAddressSpaces(ctx, &paragliderpb.FindUnusedAddressSpacesRequest{Ipv6: true, Sizes: []int32{0, 0}})
+	require.Nil(t, err)
+	assert.ElementsMatch(t, []string{"fd20:a:1::/48", "fd20:a:2::/48"}, resp.AddressSpaces)
+	resp, err = orchestratorServer.FindUnusedAddressSpaces(ctx, &paragliderpb.FindUnusedAddressSpacesRequest{Ipv6: true})
+	require.Nil(t, err)
+	assert.Equal(t, []string{"fd20:a:3::/48"}, resp.AddressSpaces)
+
+	// Out of IPv6 addresses
+	_, err = orchestratorServer.FindUnusedAddressSpaces(ctx, &paragliderpb.FindUnusedAddressSpacesRequest{Ipv6: true})
 	require.NotNil(t, err)
 }
 
@@ -808,12 +924,12 @@ func TestGetUsedAsns(t *testing.T) {
 	fakeplugin.SetupFakePluginServer(port)
 
 	// Well-formed call
-	resp, err := orchestratorServer.getUsedAsns(exampleCloudName)
+	resp, err := orchestratorServer.getUsedAsns(context.Background(), exampleCloudName)
 	require.NoError(t, err)
 	assert.ElementsMatch(t, []uint32{fakeplugin.Asn}, resp.Asns)
 
 	// Bad cloud name
-	_, err = orchestratorServer.getUsedAsns("wrong")
+	_, err = orchestratorServer.getUsedAsns(context.Background(), "wrong")
 	require.Error(t, err)
 }
 
@@ -824,46 +940,61 @@ func TestUpdateUsedAsns(t *testing.T) {
 
 	fakeplugin.SetupFakePluginServer(port)
 
-	// Valid cloud list
-	cloud := config.CloudPlugin{Name: exampleCloudName, Host: "localhost", Port: strconv.Itoa(port)}
-	orchestratorServer.config = config.Config{CloudPlugins: []config.CloudPlugin{cloud}}
-	err := orchestratorServer.updateUsedAsns()
+	// Plugins are queried whether they're configured or registered themselves
+	err := orchestratorServer.updateUsedAsns(context.Background())
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uint32{fakeplugin.Asn}, orchestratorServer.usedAsns)
+
+	// Repeated updates replace rather than accumulate
+	err = orchestratorServer.updateUsedAsns(context.Background())
 	require.NoError(t, err)
 	require.ElementsMatch(t, []uint32{fakeplugin.Asn}, orchestratorServer.usedAsns)
 
-	// Invalid cloud list
-	cloud = config.CloudPlugin{Name: "wrong", Host: "localhost", Port: strconv.Itoa(port)}
-	orchestratorServer.config = config.Config{CloudPlugins: []config.CloudPlugin{cloud}}
-	err = orchestratorServer.updateUsedAsns()
+	// Unreachable plugin
+	orchestratorServer.pluginAddresses["wrong"] = fmt.Sprintf("localhost:%d", getNewPortNumber())
+	err = orchestratorServer.updateUsedAsns(context.Background())
 	require.Error(t, err)
 }
 
 func TestFindUnusedAsn(t *testing.T) {
-	orchestratorServer := newOrchestratorServer()
 	ctx := context.Background()
 
 	// Typical case
-	orchestratorServer.usedAsns = []uint32{64512}
+	orchestratorServer := newOrchestratorServer()
+	err := orchestratorServer.allocations.Add(ctx, store.AsnsKey, "64512")
+	require.NoError(t, err)
 	asn, err := orchestratorServer.FindUnusedAsn(ctx, &paragliderpb.FindUnusedAsnRequest{})
 	require.NoError(t, err)
 	require.Equal(t, uint32(64513), asn.Asn)
 
-	// Gap in usedAsns
-	orchestratorServer.usedAsns = []uint32{64512, 64514}
+	// ASNs handed out before remain allocated
+	asn, err = orchestratorServer.FindUnusedAsn(ctx, &paragliderpb.FindUnusedAsnRequest{})
+	require.NoError(t, err)
+	require.Equal(t, uint32(64514), asn.Asn)
+
+	// Gap in used ASNs (one used by a plugin and one allocated)
+	orchestratorServer = newOrchestratorServer()
+	port := getNewPortNumber()
+	orchestratorServer.pluginAddresses[exampleCloudName] = fmt.Sprintf("localhost:%d", port)
+	fakeplugin.SetupFakePluginServer(port)
+	orchestratorServer.config.CloudPlugins = []config.CloudPlugin{{Name: exampleCloudName, Host: "localhost", Port: strconv.Itoa(port)}}
+	err = orchestratorServer.allocations.Add(ctx, store.AsnsKey, "64514")
+	require.NoError(t, err)
 	asn, err = orchestratorServer.FindUnusedAsn(ctx, &paragliderpb.FindUnusedAsnRequest{})
 	require.NoError(t, err)
 	require.Equal(t, uint32(64513), asn.Asn)
 
-	// No entries in asn map
-	orchestratorServer.usedAsns = []uint32{}
+	// No used ASNs
+	orchestratorServer = newOrchestratorServer()
 	asn, err = orchestratorServer.FindUnusedAsn(ctx, &paragliderpb.FindUnusedAsnRequest{})
 	require.NoError(t, err)
 	require.Equal(t, uint32(64512), asn.Asn)
 
 	// 4-bit ASN
-	orchestratorServer.usedAsns = make([]uint32, MAX_PRIVATE_ASN_2BYTE-MIN_PRIVATE_ASN_2BYTE+1)
+	orchestratorServer = newOrchestratorServer()
 	for i := MIN_PRIVATE_ASN_2BYTE; i <= MAX_PRIVATE_ASN_2BYTE; i++ {
-		orchestratorServer.usedAsns[i-MIN_PRIVATE_ASN_2BYTE] = i
+		err = orchestratorServer.allocations.Add(ctx, store.AsnsKey, strconv.FormatUint(uint64(i), 10))
+		require.NoError(t, err)
 	}
 	asn, err = orchestratorServer.FindUnusedAsn(ctx, &paragliderpb.FindUnusedAsnRequest{})
 	require.NoError(t, err)
@@ -879,12 +1010,12 @@ func TestGetUsedBgpPeeringIpAddresses(t *testing.T) {
 	fakeplugin.SetupFakePluginServer(port)
 
 	// Well-formed call
-	resp, err := orchestratorServer.getUsedBgpPeeringIpAddresses(exampleCloudName)
+	resp, err := orchestratorServer.getUsedBgpPeeringIpAddresses(context.Background(), exampleCloudName)
 	require.NoError(t, err)
 	assert.ElementsMatch(t, fakeplugin.BgpPeeringIpAddresses, resp.IpAddresses)
 
 	// Bad cloud name
-	_, err = orchestratorServer.getUsedBgpPeeringIpAddresses("wrong")
+	_, err = orchestratorServer.getUsedBgpPeeringIpAddresses(context.Background(), "wrong")
 	require.Error(t, err)
 }
 
@@ -895,32 +1026,35 @@ func TestUpdateUsedBgpPeeringIpAddresses(t *testing.T) {
 
 	fakeplugin.SetupFakePluginServer(port)
 
-	// Valid cloud list
-	cloud := config.CloudPlugin{Name: exampleCloudName, Host: "localhost", Port: strconv.Itoa(port)}
-	orchestratorServer.config = config.Config{CloudPlugins: []config.CloudPlugin{cloud}}
-	err := orchestratorServer.updateUsedBgpPeeringIpAddresses(defaultNamespace)
+	// Plugins are queried whether they're configured or registered themselves
+	err := orchestratorServer.updateUsedBgpPeeringIpAddresses(context.Background(), defaultNamespace)
 	require.NoError(t, err)
 	require.ElementsMatch(t, fakeplugin.BgpPeeringIpAddresses, orchestratorServer.usedBgpPeeringIpAddresses[exampleCloudName])
 
-	// Invalid cloud list
-	cloud = config.CloudPlugin{Name: "wrong", Host: "localhost", Port: strconv.Itoa(port)}
-	orchestratorServer.config = config.Config{CloudPlugins: []config.CloudPlugin{cloud}}
-	err = orchestratorServer.updateUsedBgpPeeringIpAddresses(defaultNamespace)
+	// Unreachable plugin
+	orchestratorServer.pluginAddresses["wrong"] = fmt.Sprintf("localhost:%d", getNewPortNumber())
+	err = orchestratorServer.updateUsedBgpPeeringIpAddresses(context.Background(), defaultNamespace)
 	require.Error(t, err)
 }
 
 func TestFindUnusedBgpPeeringSubnets(t *testing.T) {
-	orchestratorServer := newOrchestratorServer()
 	ctx := context.Background()
 
 	// Typical case between Azure and GCP
+	orchestratorServer := newOrchestratorServer()
 	orchestratorServer.usedBgpPeeringIpAddresses[utils.AZURE] = []string{"169.254.21.1", "169.254.21.5"}
 	orchestratorServer.usedBgpPeeringIpAddresses[utils.GCP] = []string{"169.254.21.2", "169.254.21.6"}
 	subnets, err := orchestratorServer.findUnusedBgpPeeringIpAddresses(ctx, utils.AZURE, utils.GCP, defaultNamespace)
 	require.NoError(t, err)
 	require.ElementsMatch(t, []string{"169.254.21.9", "169.254.21.10", "169.254.21.13", "169.254.21.14"}, subnets)
 
+	// BGP peering IP addresses handed out before remain allocated
+	subnets, err = orchestratorServer.findUnusedBgpPeeringIpAddresses(ctx, utils.AZURE, utils.GCP, defaultNamespace)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"169.254.21.17", "169.254.21.18", "169.254.21.21", "169.254.21.22"}, subnets)
+
 	// Gap in usedBgpPeeringIpAddresses
+	orchestratorServer = newOrchestratorServer()
 	orchestratorServer.usedBgpPeeringIpAddresses[utils.AZURE] = []string{"169.254.21.1", "169.254.22.1"}
 	orchestratorServer.usedBgpPeeringIpAddresses[utils.GCP] = []string{"169.254.21.2", "169.254.22.2"}
 	subnets, err = orchestratorServer.findUnusedBgpPeeringIpAddresses(ctx, utils.AZURE, utils.GCP, defaultNamespace)
@@ -928,20 +1062,78 @@ func TestFindUnusedBgpPeeringSubnets(t *testing.T) {
 	require.ElementsMatch(t, []string{"169.254.21.5", "169.254.21.6", "169.254.21.9", "169.254.21.10"}, subnets)
 
 	// No entries in bgp peering map
-	orchestratorServer.usedBgpPeeringIpAddresses[utils.AZURE] = []string{}
-	orchestratorServer.usedBgpPeeringIpAddresses[utils.GCP] = []string{}
+	orchestratorServer = newOrchestratorServer()
 	subnets, err = orchestratorServer.findUnusedBgpPeeringIpAddresses(ctx, utils.AZURE, utils.GCP, defaultNamespace)
 	require.NoError(t, err)
 	require.ElementsMatch(t, []string{"169.254.21.1", "169.254.21.2", "169.254.21.5", "169.254.21.6"}, subnets)
 
-	// Different spaces
-	orchestratorServer.usedBgpPeeringIpAddresses[utils.AZURE] = []string{"169.254.21.1", "169.254.21.9"}
+	// Different spaces (used by plugins and allocated)
+	orchestratorServer = newOrchestratorServer()
+	orchestratorServer.usedBgpPeeringIpAddresses[utils.AZURE] = []string{"169.254.21.1"}
 	orchestratorServer.usedBgpPeeringIpAddresses[utils.GCP] = []string{"169.254.21.2", "169.254.21.5"}
+	err = orchestratorServer.allocations.Add(ctx, store.BgpPeeringIpAddressesKey, "169.254.21.9", "169.254.21.10")
+	require.NoError(t, err)
 	subnets, err = orchestratorServer.findUnusedBgpPeeringIpAddresses(ctx, utils.AZURE, utils.GCP, defaultNamespace)
 	require.NoError(t, err)
 	require.ElementsMatch(t, []string{"169.254.21.13", "169.254.21.14", "169.254.21.17", "169.254.21.18"}, subnets)
 }
 
+func TestReleaseAllocations(t *testing.T) {
+	orchestratorServer := newOrchestratorServer()
+	port := getNewPortNumber()
+	orchestratorServer.pluginAddresses[exampleCloudName] = fmt.Sprintf("localhost:%d", port)
+	orchestratorServer.config.CloudPlugins = []config.CloudPlugin{{Name: exampleCloudName, Host: "localhost", Port: strconv.Itoa(port)}}
+	fakeplugin.SetupFakePluginServer(port)
+	ctx := context.Background()
+
+	// The plugin reports the first values as used, the others haven't been put to use yet
+	err := orchestratorServer.allocations.Add(ctx, store.AddressSpacesKey, fakeplugin.AddressSpaceAddress, "10.1.0.0/16")
+	require.NoError(t, err)
+	err = orchestratorServer.allocations.Add(ctx, store.AsnsKey, strconv.Itoa(fakeplugin.Asn), "64513")
+	require.NoError(t, err)
+	err = orchestratorServer.allocations.Add(ctx, store.BgpPeeringIpAddressesKey, append(slices.Clone(fakeplugin.BgpPeeringIpAddresses), "169.254.21.5")...)
+	require.NoError(t, err)
+
+	err = orchestratorServer.releaseAllocations(ctx)
+	require.NoError(t, err)
+
+	allocatedAddressSpaces, err := orchestratorServer.allocations.List(ctx, store.AddressSpacesKey)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.1.0.0/16"}, allocatedAddressSpaces)
+	allocatedAsns, err := orchestratorServer.allocations.List(ctx, store.AsnsKey)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"64513"}, allocatedAsns)
+	allocatedBgpPeeringIpAddresses, err := orchestratorServer.allocations.List(ctx, store.BgpPeeringIpAddressesKey)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"169.254.21.5"}, allocatedBgpPeeringIpAddresses)
+}
+
+func TestListTags(t *testing.T) {
+	orchestratorServer := newOrchestratorServer()
+	tagServerPort := getNewPortNumber()
+	orchestratorServer.localTagService = fmt.Sprintf("localhost:%d", tagServerPort)
+
+	r := SetUpRouter()
+	r.GET(ListTagURL, orchestratorServer.listTags)
+
+	// Tag service unavailable
+	req, _ := http.NewRequest("GET", ListTagURL, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Well-formed request
+	faketagservice.SetupFakeTagServer(tagServerPort)
+	req, _ = http.NewRequest("GET", ListTagURL, nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var tags []*tagservicepb.TagMapping
+	err := json.Unmarshal(w.Body.Bytes(), &tags)
+	require.NoError(t, err)
+	assert.NotEmpty(t, tags)
+}
+
 func TestGetTag(t *testing.T) {
 	orchestratorServer := newOrchestratorServer()
 	tagServerPort := getNewPortNumber()
@@ -1194,7 +1386,7 @@ func TestResolvePermitListRules(t *testing.T) {
 	expectedRulesList := []*paragliderpb.PermitListRule{expectedRule}
 	resource := &ResourceInfo{uri: "uri", cloud: exampleCloudName, namespace: defaultNamespace}
 
-	resolvedRules, err := orchestratorServer.resolvePermitListRules(rulesList, resource, false)
+	resolvedRules, err := orchestratorServer.resolvePermitListRules(context.Background(), rulesList, resource, false)
 	assert.Nil(t, err)
 	assert.Equal(t, expectedRulesList, resolvedRules)
 }
@@ -1256,6 +1448,39 @@ func TestCheckAndCleanRule(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotNil(t, warning)
 	assert.Equal(t, []string{}, cleanRule.Targets)
+
+	// Rule with port ranges
+	rangeRule := &paragliderpb.PermitListRule{
+		Name:     "rulename",
+		Tags:     []string{"2.3.4.5"},
+		SrcPort:  -1,
+		DstPorts: []*paragliderpb.PortRange{{Start: 8000, End: 8100}, {Start: 80, End: 80}},
+		Protocol: 6}
+
+	cleanRule, _, err = checkAndCleanRule(rangeRule)
+	assert.Nil(t, err)
+	assert.Equal(t, []*paragliderpb.PortRange{{Start: 80, End: 80}, {Start: 8000, End: 8100}}, cleanRule.DstPorts)
+
+	rangeRule.DstPorts = []*paragliderpb.PortRange{{Start: 8100, End: 8000}}
+	_, _, err = checkAndCleanRule(rangeRule)
+	assert.NotNil(t, err)
+
+	// Deny rule with a priority
+	denyRule := &paragliderpb.PermitListRule{
+		Name:     "rulename",
+		Tags:     []string{"10.0.0.5"},
+		SrcPort:  -1,
+		DstPort:  -1,
+		Protocol: 6,
+		Action:   paragliderpb.RuleAction_DENY,
+		Priority: 1}
+
+	_, _, err = checkAndCleanRule(denyRule)
+	assert.Nil(t, err)
+
+	denyRule.Priority = utils.MaxRulePriority + 1
+	_, _, err = checkAndCleanRule(denyRule)
+	assert.NotNil(t, err)
 }
 
 func TestIsIpAddrOrCidr(t *testing.T) {
@@ -1334,7 +1559,7 @@ func TestCheckAndUnsubscribe(t *testing.T) {
 		},
 	}
 
-	err := orchestratorServer.checkAndUnsubscribe(&resource, beforePermitList, afterPermitList)
+	err := orchestratorServer.checkAndUnsubscribe(context.Background(), &resource, beforePermitList, afterPermitList)
 	assert.Nil(t, err)
 }
 
@@ -1380,38 +1605,31 @@ func TestUpdateSubscribers(t *testing.T) {
 	faketagservice.SetupFakeTagServer(tagServerPort)
 	faketagservice.SubscriberCloudName = exampleCloudName
 
-	err := orchestratorServer.updateSubscribers(faketagservice.ValidTagName)
+	err := orchestratorServer.updateSubscribers(context.Background(), faketagservice.ValidTagName)
 	assert.Nil(t, err)
 }
 
 func TestGetUsedAddressSpaces(t *testing.T) {
 	orchestratorServer := newOrchestratorServer()
-
-	gcp_address_spaces := []string{"10.0.0.0/16", "10.1.0.0/16"}
-	azure_address_spaces := []string{"10.2.0.0/16", "10.3.0.0/16"}
-	orchestratorServer.usedAddressSpaces = []*paragliderpb.AddressSpaceMapping{
-		{
-			AddressSpaces: gcp_address_spaces,
-			Cloud:         utils.GCP,
-			Namespace:     defaultNamespace,
-		},
-		{
-			AddressSpaces: azure_address_spaces,
-			Cloud:         utils.AZURE,
-			Namespace:     "otherNamespace",
-		},
-	}
+	port := getNewPortNumber()
+	orchestratorServer.pluginAddresses[exampleCloudName] = fmt.Sprintf("localhost:%d", port)
+	fakeplugin.SetupFakePluginServer(port)
+	orchestratorServer.config.CloudPlugins = []config.CloudPlugin{{Name: exampleCloudName, Host: "localhost", Port: strconv.Itoa(port)}}
 	orchestratorServer.config.Namespaces = map[string][]config.CloudDeployment{
-		defaultNamespace: {{Name: utils.GCP, Deployment: "deployment1"}},
-		"otherNamespace": {{Name: utils.AZURE, Deployment: "deployment2"}},
+		"fakenamespace": {{Name: "fakecloud", Deployment: "deployment1"}},
+	}
+	expectedAddressSpaceMappings := []*paragliderpb.AddressSpaceMapping{
+		{AddressSpaces: []string{fakeplugin.AddressSpaceAddress}, Cloud: "fakecloud", Namespace: "fakenamespace", Deployment: proto.String("deployment1")},
 	}
 
 	getUsedAddressSpacesResp, err := orchestratorServer.GetUsedAddressSpaces(context.Background(), &emptypb.Empty{})
 	require.Nil(t, err)
-	assert.ElementsMatch(t, getUsedAddressSpacesResp.AddressSpaceMappings, []*paragliderpb.AddressSpaceMapping{
-		{AddressSpaces: gcp_address_spaces, Cloud: utils.GCP, Namespace: defaultNamespace, Deployment: proto.String("deployment1")},
-		{AddressSpaces: azure_address_spaces, Cloud: utils.AZURE, Namespace: "otherNamespace", Deployment: proto.String("deployment2")},
-	})
+	assert.ElementsMatch(t, getUsedAddressSpacesResp.AddressSpaceMappings, expectedAddressSpaceMappings)
+
+	// Used address spaces must not accumulate across calls
+	getUsedAddressSpacesResp, err = orchestratorServer.GetUsedAddressSpaces(context.Background(), &emptypb.Empty{})
+	require.Nil(t, err)
+	assert.ElementsMatch(t, getUsedAddressSpacesResp.AddressSpaceMappings, expectedAddressSpaceMappings)
 }
 
 func TestGetTagUri(t *testing.T) {
@@ -1422,12 +1640,12 @@ func TestGetTagUri(t *testing.T) {
 	faketagservice.SetupFakeTagServer(tagServerPort)
 
 	// Valid last level tag
-	uri, err := orchestratorServer.getTagUri(faketagservice.ValidLastLevelTagName)
+	uri, err := orchestratorServer.getTagUri(context.Background(), faketagservice.ValidLastLevelTagName)
 	require.Nil(t, err)
 	assert.Equal(t, faketagservice.TagUri, uri)
 
 	// invalid last level tag
-	uri, err = orchestratorServer.getTagUri("invalidtag")
+	uri, err = orchestratorServer.getTagUri(context.Background(), "invalidtag")
 	require.NotNil(t, err)
 	assert.Equal(t, "", uri)
 }
@@ -1441,7 +1659,7 @@ func TestGetAndValidateResourceURLParams(t *testing.T) {
 
 	faketagservice.SetupFakeTagServer(tagServerPort)
 
-	ctx := gin.Context{}
+	ctx := gin.Context{Request: httptest.NewRequest(http.MethodGet, "/", nil)}
 	ctx.Params = gin.Params{gin.Param{Key: "namespace", Value: defaultNamespace}, gin.Param{Key: "cloud", Value: exampleCloudName}, gin.Param{Key: "resourceName", Value: faketagservice.ValidLastLevelTagName}}
 
 	expectedResourceInfo := &ResourceInfo{uri: faketagservice.TagUri, name: faketagservice.ValidLastLevelTagName, cloud: exampleCloudName, namespace: defaultNamespace}
@@ -1480,10 +1698,11 @@ func TestGetValue(t *testing.T) {
 	require.Nil(t, err)
 	assert.Equal(t, resp.Value, fakekvstore.ValidValue)
 
-	// Non-existent key
+	// Non-existent key (plugins rely on the NotFound code being passed on)
 	resp, err = orchestratorServer.GetValue(context.Background(), &paragliderpb.GetValueRequest{Key: "invalidkey", Cloud: exampleCloudName, Namespace: defaultNamespace})
 	require.NotNil(t, err)
 	require.Nil(t, resp)
+	assert.Equal(t, codes.NotFound, status.Code(err))
 }
 
 func TestSetValue(t *testing.T) {
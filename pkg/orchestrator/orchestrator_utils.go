@@ -17,6 +17,8 @@ limitations under the License.
 package orchestrator
 
 import (
+	"fmt"
+
 	"github.com/seancfoley/ipaddress-go/ipaddr"
 
 	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
@@ -36,6 +38,28 @@ func allocBlock(addressSpace *ipaddr.IPAddress, blockSize int64) *ipaddr.IPAddre
 	return allocator.AllocateSize(uint64(blockSize))
 }
 
+// Prefix length of the IPv6 address spaces handed out, which is the size of the IPv6 ranges of VPCs/VNets
+const ipv6AddressSpacePrefixLen = 48
+
+// Allocates IPv6 address spaces from the unused IPv6 blocks
+func allocIpv6Blocks(unusedBlocks []*ipaddr.IPAddress, count int) ([]string, error) {
+	var allocator ipaddr.PrefixBlockAllocator[*ipaddr.IPAddress]
+	for _, block := range unusedBlocks {
+		if block != nil && block.IsIPv6() {
+			allocator.AddAvailable(block)
+		}
+	}
+	addressSpaces := make([]string, count)
+	for i := range addressSpaces {
+		block := allocator.AllocateBitLen(ipaddr.IPv6BitCount - ipv6AddressSpacePrefixLen)
+		if block == nil {
+			return nil, fmt.Errorf("unable to find free IPv6 address space")
+		}
+		addressSpaces[i] = block.String()
+	}
+	return addressSpaces, nil
+}
+
 func removeBlock(addressSpaces []*ipaddr.IPAddress, block *ipaddr.IPAddress) []*ipaddr.IPAddress {
 	var blockList []*ipaddr.IPAddress
 	for _, availSpace := range addressSpaces {
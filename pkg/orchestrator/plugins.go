@@ -0,0 +1,277 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
+)
+
+const (
+	ListPluginsURL          string        = "/plugins"
+	pluginHeartbeatInterval time.Duration = 10 * time.Second
+	pluginHeartbeatTimeout  time.Duration = 3 * pluginHeartbeatInterval // Registered plugins not heard from for this long are unhealthy
+	pluginCallTimeout       time.Duration = time.Hour                   // Creating a VPN gateway takes up to 45 minutes in some clouds
+	pluginReadCallTimeout   time.Duration = time.Minute
+	serviceCallTimeout      time.Duration = 10 * time.Second // Calls to the tag service and the KV store
+	callAttempts            int           = 3                // Attempts of calls which can be retried when the callee is unavailable
+)
+
+// Plugin RPCs which only read state, so they are retried and get a shorter deadline
+var pluginReadMethods = []string{
+	paragliderpb.CloudPlugin_GetUsedAddressSpaces_FullMethodName,
+	paragliderpb.CloudPlugin_GetUsedAsns_FullMethodName,
+	paragliderpb.CloudPlugin_GetUsedBgpPeeringIpAddresses_FullMethodName,
+	paragliderpb.CloudPlugin_GetPermitList_FullMethodName,
+	paragliderpb.CloudPlugin_GetNetworkAddressSpaces_FullMethodName,
+	paragliderpb.CloudPlugin_GetCapabilities_FullMethodName,
+}
+
+type PluginStatus string
+
+const (
+	PluginHealthy   PluginStatus = "HEALTHY"
+	PluginUnhealthy PluginStatus = "UNHEALTHY"
+	PluginUnknown   PluginStatus = "UNKNOWN" // Plugins from the config which haven't been called yet
+)
+
+// Plugin is a cloud plugin known to the controller, either from the config or because it registered itself
+type Plugin struct {
+	Name         string       `json:"name"`
+	Address      string       `json:"address"`
+	Version      string       `json:"version,omitempty"`
+	Capabilities []string     `json:"capabilities,omitempty"`
+	Registered   bool         `json:"registered"`
+	Status       PluginStatus `json:"status"`
+	LastSeen     *time.Time   `json:"lastSeen,omitempty"` // Last heartbeat or successful call
+	LastError    string       `json:"lastError,omitempty"`
+}
+
+// What the controller tracks about a plugin besides its address
+type pluginState struct {
+	version      string
+	capabilities []string
+	registered   bool
+	lastSeen     time.Time
+	lastError    string
+	lastErrorAt  time.Time
+
+	reportedCapabilities *paragliderpb.GetCapabilitiesResponse // Cached until the plugin registers again
+}
+
+func (p *pluginState) status(now time.Time) PluginStatus {
+	if p.lastErrorAt.After(p.lastSeen) {
+		return PluginUnhealthy
+	}
+	if p.lastSeen.IsZero() {
+		return PluginUnknown
+	}
+	if p.registered && now.Sub(p.lastSeen) > pluginHeartbeatTimeout {
+		return PluginUnhealthy
+	}
+	return PluginHealthy
+}
+
+// Get the address of a cloud's plugin
+func (s *ControllerServer) getPluginAddress(cloud string) (string, bool) {
+	s.pluginsLock.RLock()
+	defer s.pluginsLock.RUnlock()
+	address, ok := s.pluginAddresses[cloud]
+	return address, ok
+}
+
+// Get the names of the plugins known to the controller, whether they are configured or registered themselves
+func (s *ControllerServer) getPluginNames() []string {
+	s.pluginsLock.RLock()
+	defer s.pluginsLock.RUnlock()
+	names := make([]string, 0, len(s.pluginAddresses))
+	for cloud := range s.pluginAddresses {
+		names = append(names, cloud)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get the state of a plugin, creating it if needed. Callers must hold pluginsLock.
+func (s *ControllerServer) getPluginState(cloud string) *pluginState {
+	if s.plugins == nil {
+		s.plugins = make(map[string]*pluginState)
+	}
+	state, ok := s.plugins[cloud]
+	if !ok {
+		state = &pluginState{}
+		s.plugins[cloud] = state
+	}
+	return state
+}
+
+// Record the outcome of a call to the plugin at address
+func (s *ControllerServer) recordPluginCall(address string, err error) (string, bool) {
+	s.pluginsLock.Lock()
+	defer s.pluginsLock.Unlock()
+	for cloud, pluginAddress := range s.pluginAddresses {
+		if pluginAddress != address {
+			continue
+		}
+		state := s.getPluginState(cloud)
+		if err == nil {
+			state.lastSeen = time.Now()
+		} else {
+			state.lastError = err.Error()
+			state.lastErrorAt = time.Now()
+		}
+		return cloud, true
+	}
+	return "", false
+}
+
+// Get the name of the plugin at address
+func (s *ControllerServer) getPluginName(address string) (string, bool) {
+	s.pluginsLock.RLock()
+	defer s.pluginsLock.RUnlock()
+	for cloud, pluginAddress := range s.pluginAddresses {
+		if pluginAddress == address {
+			return cloud, true
+		}
+	}
+	return "", false
+}
+
+// Intercepts calls to a plugin to track its health and metrics, to record the resources it changes in the audit log and
+// the notices it returns for the caller, and to turn connection failures into errors which name the plugin
+func (s *ControllerServer) pluginInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	plugin, ok := s.getPluginName(cc.Target())
+	if !ok {
+		plugin = cc.Target()
+	}
+	observePluginCall(plugin, method, err, time.Since(start))
+
+	if status.Code(err) == codes.Unavailable {
+		if cloud, ok := s.recordPluginCall(cc.Target(), err); ok {
+			return status.Errorf(codes.Unavailable, "cloud plugin %s is unavailable at %s", cloud, cc.Target())
+		}
+		return err
+	}
+	if err == nil {
+		s.recordPluginCall(cc.Target(), nil)
+		recordAuditResource(ctx, plugin, method, req, reply)
+		utils.AddResponseNotices(ctx, reply)
+	}
+	return err
+}
+
+// Set up the pools of connections to the plugins and to the tag service and KV store
+func (s *ControllerServer) setupConnPools() {
+	readPolicy := utils.CallPolicy{Timeout: pluginReadCallTimeout, MaxAttempts: callAttempts}
+	methodPolicies := make(map[string]utils.CallPolicy)
+	for _, method := range pluginReadMethods {
+		methodPolicies[method] = readPolicy
+	}
+	s.pluginConns = utils.NewConnPool(s.grpcCredentials, utils.CallPolicy{Timeout: pluginCallTimeout}, methodPolicies, s.pluginInterceptor)
+	s.serviceConns = utils.NewConnPool(s.grpcCredentials, utils.CallPolicy{Timeout: serviceCallTimeout, MaxAttempts: callAttempts}, nil)
+}
+
+// Get the address of a plugin from the config
+func (s *ControllerServer) getConfiguredPluginAddress(name string) (string, bool) {
+	for _, plugin := range s.config.CloudPlugins {
+		if plugin.Name == name {
+			return plugin.Host + ":" + plugin.Port, true
+		}
+	}
+	return "", false
+}
+
+// Register a plugin (replacing the address of a plugin with the same name unless its address comes from the config).
+// Registrations are only authenticated if auth.controllerRpc is set, so the config is the way to pin the address of a plugin.
+func (s *ControllerServer) RegisterPlugin(c context.Context, req *paragliderpb.RegisterPluginRequest) (*paragliderpb.RegisterPluginResponse, error) {
+	if req.Name == "" || req.Address == "" {
+		return nil, status.Error(codes.InvalidArgument, "plugin name and address are required")
+	}
+	if _, port, err := net.SplitHostPort(req.Address); err != nil || port == "0" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid plugin address %s", req.Address)
+	}
+
+	s.pluginsLock.Lock()
+	defer s.pluginsLock.Unlock()
+	if configured, ok := s.getConfiguredPluginAddress(req.Name); ok {
+		if configured != req.Address {
+			slog.WarnContext(c, "Keeping the address of the plugin from the config instead of the registered one", "plugin", req.Name, "address", configured, "registeredAddress", req.Address)
+		}
+	} else {
+		if previous, ok := s.pluginAddresses[req.Name]; ok && previous != req.Address {
+			s.pluginConns.Remove(previous)
+		}
+		s.pluginAddresses[req.Name] = req.Address
+	}
+	state := s.getPluginState(req.Name)
+	*state = pluginState{version: req.Version, capabilities: slices.Clone(req.Capabilities), registered: true, lastSeen: time.Now()}
+
+	return &paragliderpb.RegisterPluginResponse{HeartbeatIntervalSeconds: int32(pluginHeartbeatInterval / time.Second)}, nil
+}
+
+// Record a heartbeat of a registered plugin
+func (s *ControllerServer) PluginHeartbeat(c context.Context, req *paragliderpb.PluginHeartbeatRequest) (*paragliderpb.PluginHeartbeatResponse, error) {
+	s.pluginsLock.Lock()
+	defer s.pluginsLock.Unlock()
+	state, ok := s.plugins[req.Name]
+	if !ok || !state.registered {
+		return nil, status.Errorf(codes.NotFound, "plugin %s is not registered", req.Name)
+	}
+	state.lastSeen = time.Now()
+	return &paragliderpb.PluginHeartbeatResponse{}, nil
+}
+
+// List the plugins known to the controller along with their health
+func (s *ControllerServer) listPlugins(c *gin.Context) {
+	s.pluginsLock.RLock()
+	defer s.pluginsLock.RUnlock()
+
+	now := time.Now()
+	plugins := []Plugin{}
+	for cloud, address := range s.pluginAddresses {
+		plugin := Plugin{Name: cloud, Address: address, Status: PluginUnknown}
+		if state, ok := s.plugins[cloud]; ok {
+			plugin.Version = state.version
+			plugin.Capabilities = state.capabilities
+			plugin.Registered = state.registered
+			plugin.Status = state.status(now)
+			plugin.LastError = state.lastError
+			if !state.lastSeen.IsZero() {
+				lastSeen := state.lastSeen
+				plugin.LastSeen = &lastSeen
+			}
+		}
+		plugins = append(plugins, plugin)
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	c.JSON(http.StatusOK, plugins)
+}
@@ -0,0 +1,140 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	fakeplugin "github.com/paraglider-project/paraglider/pkg/fake/cloudplugin"
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
+)
+
+func getPlugins(t *testing.T, orchestratorServer *ControllerServer) map[string]Plugin {
+	r := SetUpRouter()
+	r.GET(ListPluginsURL, orchestratorServer.listPlugins)
+	req, _ := http.NewRequest("GET", ListPluginsURL, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var plugins []Plugin
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &plugins))
+	pluginsByName := make(map[string]Plugin)
+	for _, plugin := range plugins {
+		pluginsByName[plugin.Name] = plugin
+	}
+	return pluginsByName
+}
+
+func TestPluginStatus(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, PluginUnknown, (&pluginState{}).status(now))
+	assert.Equal(t, PluginHealthy, (&pluginState{lastSeen: now.Add(-time.Hour)}).status(now))
+	assert.Equal(t, PluginHealthy, (&pluginState{registered: true, lastSeen: now}).status(now))
+	assert.Equal(t, PluginUnhealthy, (&pluginState{registered: true, lastSeen: now.Add(-pluginHeartbeatTimeout - time.Second)}).status(now))
+	assert.Equal(t, PluginUnhealthy, (&pluginState{lastSeen: now.Add(-time.Second), lastErrorAt: now}).status(now))
+	assert.Equal(t, PluginHealthy, (&pluginState{lastSeen: now, lastErrorAt: now.Add(-time.Second)}).status(now))
+}
+
+func TestRegisterPlugin(t *testing.T) {
+	orchestratorServer := newOrchestratorServer()
+	orchestratorServer.pluginAddresses[exampleCloudName] = "localhost:0"
+	ctx := context.Background()
+
+	// Heartbeats of unknown plugins are rejected so that they register again
+	_, err := orchestratorServer.PluginHeartbeat(ctx, &paragliderpb.PluginHeartbeatRequest{Name: "other"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = orchestratorServer.RegisterPlugin(ctx, &paragliderpb.RegisterPluginRequest{Name: "other"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	// Plugins started without a port have nothing listening at their address
+	_, err = orchestratorServer.RegisterPlugin(ctx, &paragliderpb.RegisterPluginRequest{Name: "other", Address: "localhost:0"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = orchestratorServer.RegisterPlugin(ctx, &paragliderpb.RegisterPluginRequest{Name: "other", Address: "localhost"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	resp, err := orchestratorServer.RegisterPlugin(ctx, &paragliderpb.RegisterPluginRequest{Name: "other", Address: "localhost:1234", Version: "v1", Capabilities: []string{"CreateResource"}})
+	require.NoError(t, err)
+	assert.Equal(t, int32(pluginHeartbeatInterval/time.Second), resp.HeartbeatIntervalSeconds)
+	_, err = orchestratorServer.PluginHeartbeat(ctx, &paragliderpb.PluginHeartbeatRequest{Name: "other"})
+	require.NoError(t, err)
+
+	address, ok := orchestratorServer.getPluginAddress("other")
+	assert.True(t, ok)
+	assert.Equal(t, "localhost:1234", address)
+
+	plugins := getPlugins(t, orchestratorServer)
+	require.Len(t, plugins, 2)
+	assert.Equal(t, PluginUnknown, plugins[exampleCloudName].Status)
+	assert.False(t, plugins[exampleCloudName].Registered)
+	other := plugins["other"]
+	assert.Equal(t, PluginHealthy, other.Status)
+	assert.True(t, other.Registered)
+	assert.Equal(t, "v1", other.Version)
+	assert.Equal(t, []string{"CreateResource"}, other.Capabilities)
+	assert.NotNil(t, other.LastSeen)
+}
+
+func TestRegisterPluginConfiguredAddress(t *testing.T) {
+	orchestratorServer := newOrchestratorServer()
+	orchestratorServer.config.CloudPlugins = []config.CloudPlugin{{Name: exampleCloudName, Host: "plugin.example.com", Port: "1000"}}
+	orchestratorServer.pluginAddresses[exampleCloudName] = "plugin.example.com:1000"
+
+	// The address from the config is kept, but the plugin is still registered
+	_, err := orchestratorServer.RegisterPlugin(context.Background(), &paragliderpb.RegisterPluginRequest{Name: exampleCloudName, Address: "localhost:1000", Version: "v1"})
+	require.NoError(t, err)
+
+	address, ok := orchestratorServer.getPluginAddress(exampleCloudName)
+	assert.True(t, ok)
+	assert.Equal(t, "plugin.example.com:1000", address)
+	plugin := getPlugins(t, orchestratorServer)[exampleCloudName]
+	assert.True(t, plugin.Registered)
+	assert.Equal(t, "v1", plugin.Version)
+}
+
+func TestPluginUnavailable(t *testing.T) {
+	orchestratorServer := newOrchestratorServer()
+	port := getNewPortNumber()
+	orchestratorServer.pluginAddresses[exampleCloudName] = fmt.Sprintf("localhost:%d", port)
+
+	// Nothing listens on the plugin's port yet
+	_, err := orchestratorServer.getAddressSpaces(context.Background(), exampleCloudName)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("cloud plugin %s is unavailable", exampleCloudName))
+	plugin := getPlugins(t, orchestratorServer)[exampleCloudName]
+	assert.Equal(t, PluginUnhealthy, plugin.Status)
+	assert.NotEmpty(t, plugin.LastError)
+
+	// The plugin is healthy again once a call succeeds
+	fakeplugin.SetupFakePluginServer(port)
+	_, err = orchestratorServer.getAddressSpaces(context.Background(), exampleCloudName)
+	require.NoError(t, err)
+	assert.Equal(t, PluginHealthy, getPlugins(t, orchestratorServer)[exampleCloudName].Status)
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// fileStore keeps allocations in a JSON file on disk. Logs are kept in files of their own next to it (e.g., allocations-audit-events.jsonl for
+// allocations.json), which hold one JSON string per line so that appending to them doesn't require rewriting them.
+type fileStore struct {
+	lock sync.Mutex
+	path string
+}
+
+func NewFileStore(path string) (*fileStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path is required for the file allocation store")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create directory for allocation store: %w", err)
+	}
+	f := &fileStore{path: path}
+	// Make sure the file is readable before it's used
+	if _, err := f.read(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Reads all allocations from the file (a missing file holds no allocations)
+func (f *fileStore) read() (map[string][]string, error) {
+	allocations := make(map[string][]string)
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return allocations, nil
+		}
+		return nil, fmt.Errorf("unable to read allocation store: %w", err)
+	}
+	if err := json.Unmarshal(data, &allocations); err != nil {
+		return nil, fmt.Errorf("unable to parse allocation store: %w", err)
+	}
+	return allocations, nil
+}
+
+// Writes all allocations to the file. A temporary file is renamed over the existing one so that a crash never leaves a partially written file.
+func (f *fileStore) write(allocations map[string][]string) error {
+	data, err := json.MarshalIndent(allocations, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to marshal allocations: %w", err)
+	}
+	tmpPath := f.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("unable to write allocation store: %w", err)
+	}
+	if err := os.Rename(tmpPath, f.path); err != nil {
+		return fmt.Errorf("unable to write allocation store: %w", err)
+	}
+	return nil
+}
+
+func (f *fileStore) List(ctx context.Context, key string) ([]string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	allocations, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+	return allocations[key], nil
+}
+
+func (f *fileStore) Add(ctx context.Context, key string, values ...string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	allocations, err := f.read()
+	if err != nil {
+		return err
+	}
+	allocations[key] = addValues(allocations[key], values)
+	return f.write(allocations)
+}
+
+func (f *fileStore) Remove(ctx context.Context, key string, values ...string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	allocations, err := f.read()
+	if err != nil {
+		return err
+	}
+	allocations[key] = removeValues(allocations[key], values)
+	return f.write(allocations)
+}
+
+// Gets the path of the file holding the log kept under key
+func (f *fileStore) logPath(key string) string {
+	return strings.TrimSuffix(f.path, filepath.Ext(f.path)) + "-" + key + ".jsonl"
+}
+
+func (f *fileStore) Append(ctx context.Context, key string, values ...string) error {
+	var data []byte
+	for _, value := range values {
+		line, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("unable to marshal log value: %w", err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	file, err := os.OpenFile(f.logPath(key), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("unable to open log: %w", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("unable to append to log: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("unable to append to log: %w", err)
+	}
+	return nil
+}
+
+func (f *fileStore) Log(ctx context.Context, key string) ([]string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	data, err := os.ReadFile(f.logPath(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("unable to read log: %w", err)
+	}
+
+	// A crash while appending may leave a partially written last line, which is skipped
+	lines := strings.Split(string(data), "\n")
+	values := make([]string, 0, len(lines)-1)
+	for _, line := range lines[:len(lines)-1] {
+		var value string
+		if err := json.Unmarshal([]byte(line), &value); err != nil {
+			return nil, fmt.Errorf("unable to parse log: %w", err)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	storepb "github.com/paraglider-project/paraglider/pkg/kvstore/storepb"
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	kvStoreNamespace = "paraglider"
+	kvStoreCloud     = "orchestrator"
+	kvStoreKeyPrefix = "allocations/"
+	logReadBatchSize = 500 // Values of a log read per request to the KV store
+)
+
+// kvStore keeps allocations in the (Redis-backed) KV store service. Each key is stored as a JSON list of values.
+// Logs are stored with one KV store key per value (<key>/<index>), along with their length (<key>/length), so that appending to them doesn't
+// require rewriting them. They are read back in batches of values.
+type kvStore struct {
+	lock    sync.Mutex
+	address string
+	conns   *utils.ConnPool
+}
+
+func NewKVStore(address string, conns *utils.ConnPool) *kvStore {
+	return &kvStore{address: address, conns: conns}
+}
+
+// Gets the allocations recorded under key (a missing key holds no allocations)
+func (k *kvStore) get(ctx context.Context, client storepb.KVStoreClient, key string) ([]string, error) {
+	resp, err := client.Get(ctx, &storepb.GetRequest{Key: kvStoreKeyPrefix + key, Cloud: kvStoreCloud, Namespace: kvStoreNamespace})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("unable to get allocations from kv store: %w", err)
+	}
+	allocations := []string{}
+	if err := json.Unmarshal([]byte(resp.Value), &allocations); err != nil {
+		return nil, fmt.Errorf("unable to parse allocations: %w", err)
+	}
+	return allocations, nil
+}
+
+// Sets the allocations recorded under key
+func (k *kvStore) set(ctx context.Context, client storepb.KVStoreClient, key string, allocations []string) error {
+	data, err := json.Marshal(allocations)
+	if err != nil {
+		return fmt.Errorf("unable to marshal allocations: %w", err)
+	}
+	_, err = client.Set(ctx, &storepb.SetRequest{Key: kvStoreKeyPrefix + key, Value: string(data), Cloud: kvStoreCloud, Namespace: kvStoreNamespace})
+	if err != nil {
+		return fmt.Errorf("unable to set allocations in kv store: %w", err)
+	}
+	return nil
+}
+
+// Applies update to the allocations recorded under key
+func (k *kvStore) update(ctx context.Context, key string, update func([]string) []string) error {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	conn, err := k.conns.Get(k.address)
+	if err != nil {
+		return fmt.Errorf("unable to connect to kv store: %w", err)
+	}
+	client := storepb.NewKVStoreClient(conn)
+
+	allocations, err := k.get(ctx, client, key)
+	if err != nil {
+		return err
+	}
+	return k.set(ctx, client, key, update(allocations))
+}
+
+func (k *kvStore) List(ctx context.Context, key string) ([]string, error) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	conn, err := k.conns.Get(k.address)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to kv store: %w", err)
+	}
+	return k.get(ctx, storepb.NewKVStoreClient(conn), key)
+}
+
+func (k *kvStore) Add(ctx context.Context, key string, values ...string) error {
+	return k.update(ctx, key, func(allocations []string) []string {
+		return addValues(allocations, values)
+	})
+}
+
+func (k *kvStore) Remove(ctx context.Context, key string, values ...string) error {
+	return k.update(ctx, key, func(allocations []string) []string {
+		return removeValues(allocations, values)
+	})
+}
+
+// Gets the number of values appended to the log kept under key (a missing log holds no values)
+func (k *kvStore) getLogLength(ctx context.Context, client storepb.KVStoreClient, key string) (int, error) {
+	resp, err := client.Get(ctx, &storepb.GetRequest{Key: kvStoreKeyPrefix + key + "/length", Cloud: kvStoreCloud, Namespace: kvStoreNamespace})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("unable to get log length from kv store: %w", err)
+	}
+	length, err := strconv.Atoi(resp.Value)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse log length: %w", err)
+	}
+	return length, nil
+}
+
+func (k *kvStore) Append(ctx context.Context, key string, values ...string) error {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	conn, err := k.conns.Get(k.address)
+	if err != nil {
+		return fmt.Errorf("unable to connect to kv store: %w", err)
+	}
+	client := storepb.NewKVStoreClient(conn)
+
+	length, err := k.getLogLength(ctx, client, key)
+	if err != nil {
+		return err
+	}
+	// The values are set before the length, so that a failure never leaves the log with missing values
+	for i, value := range values {
+		_, err := client.Set(ctx, &storepb.SetRequest{Key: fmt.Sprintf("%s%s/%d", kvStoreKeyPrefix, key, length+i), Value: value, Cloud: kvStoreCloud, Namespace: kvStoreNamespace})
+		if err != nil {
+			return fmt.Errorf("unable to append to log in kv store: %w", err)
+		}
+	}
+	_, err = client.Set(ctx, &storepb.SetRequest{Key: kvStoreKeyPrefix + key + "/length", Value: strconv.Itoa(length + len(values)), Cloud: kvStoreCloud, Namespace: kvStoreNamespace})
+	if err != nil {
+		return fmt.Errorf("unable to set log length in kv store: %w", err)
+	}
+	return nil
+}
+
+func (k *kvStore) Log(ctx context.Context, key string) ([]string, error) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	conn, err := k.conns.Get(k.address)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to kv store: %w", err)
+	}
+	client := storepb.NewKVStoreClient(conn)
+
+	length, err := k.getLogLength(ctx, client, key)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, 0, length)
+	for start := 0; start < length; start += logReadBatchSize {
+		keys := make([]string, 0, logReadBatchSize)
+		for i := start; i < min(start+logReadBatchSize, length); i++ {
+			keys = append(keys, fmt.Sprintf("%s%s/%d", kvStoreKeyPrefix, key, i))
+		}
+		resp, err := client.GetMany(ctx, &storepb.GetManyRequest{Keys: keys, Cloud: kvStoreCloud, Namespace: kvStoreNamespace})
+		if err != nil {
+			return nil, fmt.Errorf("unable to get log from kv store: %w", err)
+		}
+		for _, logKey := range keys {
+			value, ok := resp.Values[logKey]
+			if !ok {
+				return nil, fmt.Errorf("log entry %s is missing from kv store", logKey)
+			}
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
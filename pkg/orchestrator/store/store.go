@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
+)
+
+// Keys under which the orchestrator records its allocations
+const (
+	AddressSpacesKey         = "address-spaces"
+	AsnsKey                  = "asns"
+	BgpPeeringIpAddressesKey = "bgp-peering-ip-addresses"
+	NamespacesKey            = "namespaces"       // Namespaces created through the API (JSON-encoded)
+	AuditEventsKey           = "audit-events"     // Audit log of state-changing requests (JSON-encoded), kept as a log (see Append)
+	RuleExpirationsKey       = "rule-expirations" // When permit list rules added with an expiration time must be deleted (JSON-encoded)
+	ExpectedRulesKey         = "expected-rules"   // Permit list rules added to each resource, which drift is checked against (JSON-encoded)
+)
+
+// Supported allocation store types (as used in the orchestrator config)
+const (
+	MemoryStoreType  = "memory"
+	FileStoreType    = "file"
+	KVStoreStoreType = "kvstore"
+)
+
+// AllocationStore persists the values handed out by the orchestrator (e.g., address spaces, ASNs, BGP peering IP addresses)
+// so that they are not handed out again, even across restarts.
+type AllocationStore interface {
+	// List returns all values recorded under key
+	List(ctx context.Context, key string) ([]string, error)
+	// Add records values under key, ignoring the ones which are already recorded
+	Add(ctx context.Context, key string, values ...string) error
+	// Remove deletes values recorded under key
+	Remove(ctx context.Context, key string, values ...string) error
+	// Append adds values to the end of the log kept under key. Logs are only ever appended to, so values aren't checked for duplicates.
+	Append(ctx context.Context, key string, values ...string) error
+	// Log returns the values appended to the log kept under key, in the order they were appended
+	Log(ctx context.Context, key string) ([]string, error)
+}
+
+// New creates an allocation store of the given type.
+// path is only used by the file store and kvStoreAddress (and kvStoreConns) are only used by the KV store.
+func New(storeType string, path string, kvStoreAddress string, kvStoreConns *utils.ConnPool) (AllocationStore, error) {
+	switch storeType {
+	case "", MemoryStoreType:
+		return NewMemoryStore(), nil
+	case FileStoreType:
+		return NewFileStore(path)
+	case KVStoreStoreType:
+		return NewKVStore(kvStoreAddress, kvStoreConns), nil
+	}
+	return nil, fmt.Errorf("invalid allocation store type: %s", storeType)
+}
+
+// IsPersistent returns true if allocation stores of the given type outlive the orchestrator
+func IsPersistent(storeType string) bool {
+	return storeType == FileStoreType || storeType == KVStoreStoreType
+}
+
+// Adds values to a list of allocations without duplicates
+func addValues(allocations []string, values []string) []string {
+	for _, value := range values {
+		if !slices.Contains(allocations, value) {
+			allocations = append(allocations, value)
+		}
+	}
+	return allocations
+}
+
+// Removes values from a list of allocations
+func removeValues(allocations []string, values []string) []string {
+	return slices.DeleteFunc(allocations, func(allocation string) bool {
+		return slices.Contains(values, allocation)
+	})
+}
+
+// memoryStore keeps allocations in memory, so they are lost when the orchestrator restarts
+type memoryStore struct {
+	lock        sync.Mutex
+	allocations map[string][]string
+	logs        map[string][]string
+}
+
+func NewMemoryStore() *memoryStore {
+	return &memoryStore{allocations: make(map[string][]string), logs: make(map[string][]string)}
+}
+
+func (m *memoryStore) List(ctx context.Context, key string) ([]string, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return slices.Clone(m.allocations[key]), nil
+}
+
+func (m *memoryStore) Add(ctx context.Context, key string, values ...string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.allocations[key] = addValues(m.allocations[key], values)
+	return nil
+}
+
+func (m *memoryStore) Remove(ctx context.Context, key string, values ...string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.allocations[key] = removeValues(m.allocations[key], values)
+	return nil
+}
+
+func (m *memoryStore) Append(ctx context.Context, key string, values ...string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.logs[key] = append(m.logs[key], values...)
+	return nil
+}
+
+func (m *memoryStore) Log(ctx context.Context, key string) ([]string, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return slices.Clone(m.logs[key]), nil
+}
@@ -0,0 +1,233 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	kvstore "github.com/paraglider-project/paraglider/pkg/kvstore"
+	storepb "github.com/paraglider-project/paraglider/pkg/kvstore/storepb"
+	utils "github.com/paraglider-project/paraglider/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// In-memory KV store service which mimics the Redis-backed one
+type fakeKVStoreServer struct {
+	storepb.UnimplementedKVStoreServer
+	lock     sync.Mutex
+	values   map[string]string
+	requests int // Number of requests served
+}
+
+func (f *fakeKVStoreServer) Get(ctx context.Context, req *storepb.GetRequest) (*storepb.GetResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.requests++
+	value, ok := f.values[kvstore.GetFullKey(req.Key, req.Cloud, req.Namespace)]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "key %s not found", req.Key)
+	}
+	return &storepb.GetResponse{Value: value}, nil
+}
+
+func (f *fakeKVStoreServer) GetMany(ctx context.Context, req *storepb.GetManyRequest) (*storepb.GetManyResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.requests++
+	resp := &storepb.GetManyResponse{Values: make(map[string]string)}
+	for _, key := range req.Keys {
+		if value, ok := f.values[kvstore.GetFullKey(key, req.Cloud, req.Namespace)]; ok {
+			resp.Values[key] = value
+		}
+	}
+	return resp, nil
+}
+
+func (f *fakeKVStoreServer) Set(ctx context.Context, req *storepb.SetRequest) (*storepb.SetResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.requests++
+	f.values[kvstore.GetFullKey(req.Key, req.Cloud, req.Namespace)] = req.Value
+	return &storepb.SetResponse{}, nil
+}
+
+func setupFakeKVStoreServer(t *testing.T) (string, *fakeKVStoreServer) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	grpcServer := grpc.NewServer()
+	server := &fakeKVStoreServer{values: make(map[string]string)}
+	storepb.RegisterKVStoreServer(grpcServer, server)
+	utils.RegisterHealthService(grpcServer)
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+	t.Cleanup(grpcServer.Stop)
+	return lis.Addr().String(), server
+}
+
+// Exercises the behavior every allocation store must have
+func testAllocationStore(t *testing.T, s AllocationStore) {
+	ctx := context.Background()
+
+	// Empty key
+	values, err := s.List(ctx, AsnsKey)
+	require.NoError(t, err)
+	assert.Empty(t, values)
+
+	// Add values (duplicates are ignored)
+	require.NoError(t, s.Add(ctx, AsnsKey, "64512", "64513"))
+	require.NoError(t, s.Add(ctx, AsnsKey, "64513", "64514"))
+	values, err = s.List(ctx, AsnsKey)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"64512", "64513", "64514"}, values)
+
+	// Keys are independent
+	values, err = s.List(ctx, AddressSpacesKey)
+	require.NoError(t, err)
+	assert.Empty(t, values)
+
+	// Remove values
+	require.NoError(t, s.Remove(ctx, AsnsKey, "64513"))
+	values, err = s.List(ctx, AsnsKey)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"64512", "64514"}, values)
+
+	// Logs keep every value appended, in order
+	values, err = s.Log(ctx, AuditEventsKey)
+	require.NoError(t, err)
+	assert.Empty(t, values)
+	require.NoError(t, s.Append(ctx, AuditEventsKey, `{"action":"a"}`, "line\nbreak"))
+	require.NoError(t, s.Append(ctx, AuditEventsKey, `{"action":"a"}`))
+	values, err = s.Log(ctx, AuditEventsKey)
+	require.NoError(t, err)
+	assert.Equal(t, []string{`{"action":"a"}`, "line\nbreak", `{"action":"a"}`}, values)
+
+	// Logs are independent of allocations
+	values, err = s.List(ctx, AuditEventsKey)
+	require.NoError(t, err)
+	assert.Empty(t, values)
+}
+
+func TestMemoryStore(t *testing.T) {
+	testAllocationStore(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "allocations.json")
+	s, err := NewFileStore(path)
+	require.NoError(t, err)
+	testAllocationStore(t, s)
+
+	// Allocations survive a restart
+	s, err = NewFileStore(path)
+	require.NoError(t, err)
+	values, err := s.List(context.Background(), AsnsKey)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"64512", "64514"}, values)
+	values, err = s.Log(context.Background(), AuditEventsKey)
+	require.NoError(t, err)
+	assert.Len(t, values, 3)
+
+	// A partially written last line is skipped
+	logFile, err := os.OpenFile(filepath.Join(filepath.Dir(path), "allocations-audit-events.jsonl"), os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = logFile.WriteString(`"partial`)
+	require.NoError(t, err)
+	require.NoError(t, logFile.Close())
+	values, err = s.Log(context.Background(), AuditEventsKey)
+	require.NoError(t, err)
+	assert.Len(t, values, 3)
+
+	// Missing path
+	_, err = NewFileStore("")
+	require.Error(t, err)
+
+	// Corrupted file
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0o600))
+	_, err = NewFileStore(path)
+	require.Error(t, err)
+}
+
+func TestKVStore(t *testing.T) {
+	address, _ := setupFakeKVStoreServer(t)
+	testAllocationStore(t, NewKVStore(address, utils.NewConnPool(nil, utils.CallPolicy{}, nil)))
+
+	// Allocations survive a restart
+	s := NewKVStore(address, utils.NewConnPool(nil, utils.CallPolicy{}, nil))
+	values, err := s.List(context.Background(), AsnsKey)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"64512", "64514"}, values)
+	values, err = s.Log(context.Background(), AuditEventsKey)
+	require.NoError(t, err)
+	assert.Len(t, values, 3)
+}
+
+func TestKVStoreLogBatches(t *testing.T) {
+	address, server := setupFakeKVStoreServer(t)
+	s := NewKVStore(address, utils.NewConnPool(nil, utils.CallPolicy{}, nil))
+	ctx := context.Background()
+	appended := make([]string, 2*logReadBatchSize+1)
+	for i := range appended {
+		appended[i] = strconv.Itoa(i)
+	}
+	require.NoError(t, s.Append(ctx, AuditEventsKey, appended...))
+
+	// The length and then the values in batches are read rather than each value on its own
+	server.lock.Lock()
+	server.requests = 0
+	server.lock.Unlock()
+	values, err := s.Log(ctx, AuditEventsKey)
+	require.NoError(t, err)
+	assert.Equal(t, appended, values)
+	server.lock.Lock()
+	defer server.lock.Unlock()
+	assert.Equal(t, 4, server.requests)
+}
+
+func TestNew(t *testing.T) {
+	s, err := New("", "", "", nil)
+	require.NoError(t, err)
+	assert.IsType(t, &memoryStore{}, s)
+
+	s, err = New(FileStoreType, filepath.Join(t.TempDir(), "allocations.json"), "", nil)
+	require.NoError(t, err)
+	assert.IsType(t, &fileStore{}, s)
+
+	s, err = New(KVStoreStoreType, "", "localhost:1234", nil)
+	require.NoError(t, err)
+	assert.IsType(t, &kvStore{}, s)
+
+	_, err = New("invalid", "", "", nil)
+	require.Error(t, err)
+
+	assert.False(t, IsPersistent(""))
+	assert.False(t, IsPersistent(MemoryStoreType))
+	assert.True(t, IsPersistent(FileStoreType))
+	assert.True(t, IsPersistent(KVStoreStoreType))
+}
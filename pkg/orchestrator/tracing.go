@@ -0,0 +1,27 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"go.opentelemetry.io/otel"
+)
+
+// Name the controller's spans are exported under
+const tracingServiceName = "paraglider-controller"
+
+// Tracer of the spans of controller operations. The spans of REST requests and gRPC calls come from their middleware.
+var tracer = otel.Tracer("github.com/paraglider-project/paraglider/pkg/orchestrator")
@@ -0,0 +1,57 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestTraceOperation(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	// The operation's span belongs to the trace of the request even though the operation doesn't run with its context
+	_, requestSpan := provider.Tracer("test").Start(context.Background(), "request")
+	requestSpan.End()
+	fn := traceOperation(requestSpan.SpanContext(), "rule_add", func(ctx context.Context) (any, error) {
+		return nil, errors.New("failed")
+	})
+	_, err := fn(context.Background())
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	operationSpan := spans[1]
+	assert.Equal(t, "rule_add", operationSpan.Name())
+	assert.Equal(t, requestSpan.SpanContext().TraceID(), operationSpan.SpanContext().TraceID())
+	assert.Equal(t, requestSpan.SpanContext().SpanID(), operationSpan.Parent().SpanID())
+	assert.Equal(t, codes.Error, operationSpan.Status().Code)
+}
@@ -0,0 +1,345 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tagservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	redis "github.com/redis/go-redis/v9"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Types of keys as reported by Store.Type (these match the ones of Redis)
+const (
+	noneType = "none"
+	hashType = "hash"
+	setType  = "set"
+)
+
+// ErrWrongType is returned by a Store when operating on a key holding a different type
+var ErrWrongType = errors.New("operation against a key holding the wrong kind of value")
+
+// Store is the database backing the tag service. It follows the semantics of Redis: leaf tags are stored as hashes
+// while parent tags and subscriptions are stored as sets. Hashes and sets are deleted once they become empty.
+type Store interface {
+	// Type returns "hash", "set" or "none" if the key doesn't exist
+	Type(ctx context.Context, key string) (string, error)
+	// Keys returns all keys
+	Keys(ctx context.Context) ([]string, error)
+
+	SMembers(ctx context.Context, key string) ([]string, error)
+	SAdd(ctx context.Context, key string, members ...string) error
+	SRem(ctx context.Context, key string, members ...string) error
+
+	HExists(ctx context.Context, key string, field string) (bool, error)
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+	HKeys(ctx context.Context, key string) ([]string, error)
+	HSet(ctx context.Context, key string, values map[string]string) error
+	HDel(ctx context.Context, key string, fields ...string) error
+
+	// FlushAll deletes all keys
+	FlushAll(ctx context.Context) error
+	Close() error
+}
+
+// redisStore keeps tags in a Redis database
+type redisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) *redisStore {
+	return &redisStore{client: client}
+}
+
+func (r *redisStore) Type(ctx context.Context, key string) (string, error) {
+	return r.client.Type(ctx, key).Result()
+}
+
+func (r *redisStore) Keys(ctx context.Context) ([]string, error) {
+	return r.client.Keys(ctx, "*").Result()
+}
+
+func (r *redisStore) SMembers(ctx context.Context, key string) ([]string, error) {
+	return r.client.SMembers(ctx, key).Result()
+}
+
+func (r *redisStore) SAdd(ctx context.Context, key string, members ...string) error {
+	return r.client.SAdd(ctx, key, members).Err()
+}
+
+func (r *redisStore) SRem(ctx context.Context, key string, members ...string) error {
+	return r.client.SRem(ctx, key, members).Err()
+}
+
+func (r *redisStore) HExists(ctx context.Context, key string, field string) (bool, error) {
+	return r.client.HExists(ctx, key, field).Result()
+}
+
+func (r *redisStore) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return r.client.HGetAll(ctx, key).Result()
+}
+
+func (r *redisStore) HKeys(ctx context.Context, key string) ([]string, error) {
+	return r.client.HKeys(ctx, key).Result()
+}
+
+func (r *redisStore) HSet(ctx context.Context, key string, values map[string]string) error {
+	return r.client.HSet(ctx, key, values).Err()
+}
+
+func (r *redisStore) HDel(ctx context.Context, key string, fields ...string) error {
+	return r.client.HDel(ctx, key, fields...).Err()
+}
+
+func (r *redisStore) FlushAll(ctx context.Context) error {
+	return r.client.FlushAll(ctx).Err()
+}
+
+func (r *redisStore) Close() error {
+	return r.client.Close()
+}
+
+// boltStore keeps tags in an embedded bbolt database file, so no external database process is needed.
+// Every hash and set is a nested bucket (whose keys are the fields or members) under the top-level bucket of its type.
+type boltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (or creates) the bbolt database at path
+func NewBoltStore(path string) (*boltStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path is required for the bolt store")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create directory for bolt store: %w", err)
+	}
+	// The timeout keeps a second process opening the same file from hanging forever on the file lock
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open bolt store %s: %w", path, err)
+	}
+	b := &boltStore{db: db}
+	if err := db.Update(b.createBuckets); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to initialize bolt store: %w", err)
+	}
+	return b, nil
+}
+
+func (b *boltStore) createBuckets(tx *bolt.Tx) error {
+	for _, keyType := range []string{hashType, setType} {
+		if _, err := tx.CreateBucketIfNotExists([]byte(keyType)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the type of key
+func (b *boltStore) keyType(tx *bolt.Tx, key string) string {
+	for _, keyType := range []string{hashType, setType} {
+		if tx.Bucket([]byte(keyType)).Bucket([]byte(key)) != nil {
+			return keyType
+		}
+	}
+	return noneType
+}
+
+// Returns the bucket of key if it holds keyType, nil if key doesn't exist or ErrWrongType if it holds another type
+func (b *boltStore) bucket(tx *bolt.Tx, key string, keyType string) (*bolt.Bucket, error) {
+	actualType := b.keyType(tx, key)
+	if actualType == noneType {
+		return nil, nil
+	}
+	if actualType != keyType {
+		return nil, fmt.Errorf("%s: %w", key, ErrWrongType)
+	}
+	return tx.Bucket([]byte(keyType)).Bucket([]byte(key)), nil
+}
+
+// Sets entries (creating the bucket of key if needed)
+func (b *boltStore) put(key string, keyType string, entries map[string]string) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := b.bucket(tx, key, keyType)
+		if err != nil {
+			return err
+		}
+		if bucket == nil {
+			bucket, err = tx.Bucket([]byte(keyType)).CreateBucket([]byte(key))
+			if err != nil {
+				return err
+			}
+		}
+		for k, v := range entries {
+			if err := bucket.Put([]byte(k), []byte(v)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Deletes entries (and the bucket of key once it's empty)
+func (b *boltStore) delete(key string, keyType string, entries []string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := b.bucket(tx, key, keyType)
+		if err != nil || bucket == nil {
+			return err
+		}
+		for _, k := range entries {
+			if err := bucket.Delete([]byte(k)); err != nil {
+				return err
+			}
+		}
+		if k, _ := bucket.Cursor().First(); k == nil {
+			return tx.Bucket([]byte(keyType)).DeleteBucket([]byte(key))
+		}
+		return nil
+	})
+}
+
+// Gets all entries
+func (b *boltStore) getAll(key string, keyType string) (map[string]string, error) {
+	entries := make(map[string]string)
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket, err := b.bucket(tx, key, keyType)
+		if err != nil || bucket == nil {
+			return err
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			entries[string(k)] = string(v)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (b *boltStore) Type(ctx context.Context, key string) (string, error) {
+	keyType := noneType
+	err := b.db.View(func(tx *bolt.Tx) error {
+		keyType = b.keyType(tx, key)
+		return nil
+	})
+	return keyType, err
+}
+
+func (b *boltStore) Keys(ctx context.Context) ([]string, error) {
+	keys := []string{}
+	err := b.db.View(func(tx *bolt.Tx) error {
+		for _, keyType := range []string{hashType, setType} {
+			err := tx.Bucket([]byte(keyType)).ForEach(func(k, v []byte) error {
+				keys = append(keys, string(k))
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (b *boltStore) SMembers(ctx context.Context, key string) ([]string, error) {
+	entries, err := b.getAll(key, setType)
+	if err != nil {
+		return nil, err
+	}
+	members := []string{}
+	for member := range entries {
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+func (b *boltStore) SAdd(ctx context.Context, key string, members ...string) error {
+	entries := make(map[string]string)
+	for _, member := range members {
+		entries[member] = ""
+	}
+	return b.put(key, setType, entries)
+}
+
+func (b *boltStore) SRem(ctx context.Context, key string, members ...string) error {
+	return b.delete(key, setType, members)
+}
+
+func (b *boltStore) HExists(ctx context.Context, key string, field string) (bool, error) {
+	exists := false
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket, err := b.bucket(tx, key, hashType)
+		if err != nil || bucket == nil {
+			return err
+		}
+		exists = bucket.Get([]byte(field)) != nil
+		return nil
+	})
+	return exists, err
+}
+
+func (b *boltStore) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return b.getAll(key, hashType)
+}
+
+func (b *boltStore) HKeys(ctx context.Context, key string) ([]string, error) {
+	entries, err := b.getAll(key, hashType)
+	if err != nil {
+		return nil, err
+	}
+	fields := []string{}
+	for field := range entries {
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func (b *boltStore) HSet(ctx context.Context, key string, values map[string]string) error {
+	return b.put(key, hashType, values)
+}
+
+func (b *boltStore) HDel(ctx context.Context, key string, fields ...string) error {
+	return b.delete(key, hashType, fields)
+}
+
+func (b *boltStore) FlushAll(ctx context.Context) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		for _, keyType := range []string{hashType, setType} {
+			if err := tx.DeleteBucket([]byte(keyType)); err != nil {
+				return err
+			}
+		}
+		return b.createBuckets(tx)
+	})
+}
+
+func (b *boltStore) Close() error {
+	return b.db.Close()
+}
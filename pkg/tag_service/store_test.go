@@ -0,0 +1,146 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tagservice
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tagservicepb "github.com/paraglider-project/paraglider/pkg/tag_service/tagservicepb"
+)
+
+func TestBoltStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "db", "tags.db")
+	store, err := NewBoltStore(path)
+	require.NoError(t, err)
+
+	// Missing key
+	keyType, err := store.Type(ctx, "parent")
+	require.NoError(t, err)
+	assert.Equal(t, noneType, keyType)
+	members, err := store.SMembers(ctx, "parent")
+	require.NoError(t, err)
+	assert.Empty(t, members)
+
+	// Sets
+	require.NoError(t, store.SAdd(ctx, "parent", "child1", "child2"))
+	require.NoError(t, store.SAdd(ctx, "parent", "child2"))
+	keyType, err = store.Type(ctx, "parent")
+	require.NoError(t, err)
+	assert.Equal(t, setType, keyType)
+	members, err = store.SMembers(ctx, "parent")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"child1", "child2"}, members)
+
+	// Hashes
+	require.NoError(t, store.HSet(ctx, "leaf", map[string]string{"uri": "uri", "ip": "ip"}))
+	keyType, err = store.Type(ctx, "leaf")
+	require.NoError(t, err)
+	assert.Equal(t, hashType, keyType)
+	exists, err := store.HExists(ctx, "leaf", "uri")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	values, err := store.HGetAll(ctx, "leaf")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"uri": "uri", "ip": "ip"}, values)
+	fields, err := store.HKeys(ctx, "leaf")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"uri", "ip"}, fields)
+
+	// Using a key as the wrong type
+	require.ErrorIs(t, store.SAdd(ctx, "leaf", "child"), ErrWrongType)
+	_, err = store.HExists(ctx, "parent", "uri")
+	require.ErrorIs(t, err, ErrWrongType)
+
+	keys, err := store.Keys(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"parent", "leaf"}, keys)
+
+	// Keys survive a restart
+	require.NoError(t, store.Close())
+	store, err = NewBoltStore(path)
+	require.NoError(t, err)
+	members, err = store.SMembers(ctx, "parent")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"child1", "child2"}, members)
+
+	// Empty sets and hashes are deleted
+	require.NoError(t, store.SRem(ctx, "parent", "child1", "child2"))
+	require.NoError(t, store.HDel(ctx, "leaf", "uri", "ip"))
+	keys, err = store.Keys(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	// Flush
+	require.NoError(t, store.SAdd(ctx, "parent", "child"))
+	require.NoError(t, store.FlushAll(ctx))
+	keys, err = store.Keys(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+	require.NoError(t, store.Close())
+
+	// Missing path
+	_, err = NewBoltStore("")
+	require.Error(t, err)
+}
+
+func TestServerWithBoltStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewBoltStore(filepath.Join(t.TempDir(), "tags.db"))
+	require.NoError(t, err)
+	defer store.Close()
+	server := newServer(store)
+
+	leaf := &tagservicepb.TagMapping{Name: "leaf", Uri: &uriVal, Ip: &ipVal}
+	_, err = server.SetTag(ctx, &tagservicepb.SetTagRequest{Tag: leaf})
+	require.NoError(t, err)
+	parent := &tagservicepb.TagMapping{Name: "parent", ChildTags: []string{"leaf"}}
+	_, err = server.SetTag(ctx, &tagservicepb.SetTagRequest{Tag: parent})
+	require.NoError(t, err)
+
+	// Leaf tags can't be set twice
+	_, err = server.SetTag(ctx, &tagservicepb.SetTagRequest{Tag: leaf})
+	require.Error(t, err)
+
+	getResp, err := server.GetTag(ctx, &tagservicepb.GetTagRequest{TagName: "parent"})
+	require.NoError(t, err)
+	assert.Equal(t, parent, getResp.Tag)
+
+	resolveResp, err := server.ResolveTag(ctx, &tagservicepb.ResolveTagRequest{TagName: "parent"})
+	require.NoError(t, err)
+	require.Len(t, resolveResp.Tags, 1)
+	assert.Equal(t, leaf, resolveResp.Tags[0])
+
+	listResp, err := server.ListTags(ctx, &tagservicepb.ListTagsRequest{})
+	require.NoError(t, err)
+	assert.Len(t, listResp.Tags, 2)
+
+	_, err = server.DeleteTag(ctx, &tagservicepb.DeleteTagRequest{TagName: "parent"})
+	require.NoError(t, err)
+	_, err = server.DeleteTag(ctx, &tagservicepb.DeleteTagRequest{TagName: "leaf"})
+	require.NoError(t, err)
+	listResp, err = server.ListTags(ctx, &tagservicepb.ListTagsRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, listResp.Tags)
+}
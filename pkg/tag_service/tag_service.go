@@ -19,12 +19,14 @@ package tagservice
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
 	"net/netip"
+	"os"
 	"os/exec"
 	"strings"
 
+	config "github.com/paraglider-project/paraglider/pkg/orchestrator/config"
 	tagservicepb "github.com/paraglider-project/paraglider/pkg/tag_service/tagservicepb"
 	utils "github.com/paraglider-project/paraglider/pkg/utils"
 	redis "github.com/redis/go-redis/v9"
@@ -34,7 +36,7 @@ import (
 
 type tagServiceServer struct {
 	tagservicepb.UnimplementedTagServiceServer
-	client *redis.Client
+	store Store
 }
 
 func getSubscriptionKey(tagName string) string {
@@ -55,7 +57,7 @@ func isIpAddrOrCidr(value string) bool {
 // Determines if a tag is a descendent of another tag
 func (s *tagServiceServer) isDescendent(c context.Context, tag string, potentialChild string) (bool, error) {
 	// Only do SMEMBERS if the tag is a set, otherwise it cannot be a parent
-	valType, err := s.client.Type(c, tag).Result()
+	valType, err := s.store.Type(c, tag)
 	if err != nil {
 		return false, fmt.Errorf("isDescendent TYPE %s: %v", tag, err)
 	}
@@ -63,7 +65,7 @@ func (s *tagServiceServer) isDescendent(c context.Context, tag string, potential
 		return false, nil
 	}
 
-	childrenTags, err := s.client.SMembers(c, tag).Result()
+	childrenTags, err := s.store.SMembers(c, tag)
 	if err != nil {
 		return false, fmt.Errorf("isDescendent %s: %v", tag, err)
 	}
@@ -93,7 +95,7 @@ func isLeafTagMapping(tag *tagservicepb.TagMapping) (bool, error) {
 }
 
 func (s *tagServiceServer) isLeafTag(c context.Context, tag string) (bool, error) {
-	recordType, err := s.client.Type(c, tag).Result()
+	recordType, err := s.store.Type(c, tag)
 	if err != nil {
 		return false, fmt.Errorf("isLeafTag TYPE %s: %v", tag, err)
 	}
@@ -102,7 +104,7 @@ func (s *tagServiceServer) isLeafTag(c context.Context, tag string) (bool, error
 
 // Record tag by storing mapping to URI and IP
 func (s *tagServiceServer) _setLeafTag(c context.Context, tag *tagservicepb.TagMapping) error {
-	exists, err := s.client.HExists(c, tag.Name, "uri").Result()
+	exists, err := s.store.HExists(c, tag.Name, "uri")
 	if err != nil {
 		return err
 	}
@@ -119,7 +121,7 @@ func (s *tagServiceServer) _setLeafTag(c context.Context, tag *tagservicepb.TagM
 		ip = *tag.Ip
 	}
 
-	err = s.client.HSet(c, tag.Name, map[string]string{"uri": uri, "ip": ip}).Err()
+	err = s.store.HSet(c, tag.Name, map[string]string{"uri": uri, "ip": ip})
 	if err != nil {
 		return err
 	}
@@ -154,7 +156,7 @@ func (s *tagServiceServer) SetTag(c context.Context, req *tagservicepb.SetTagReq
 	}
 
 	// Add the tags
-	err = s.client.SAdd(c, req.Tag.Name, req.Tag.ChildTags).Err()
+	err = s.store.SAdd(c, req.Tag.Name, req.Tag.ChildTags...)
 	if err != nil {
 		return &tagservicepb.SetTagResponse{}, fmt.Errorf("SetTag: %v", err)
 	}
@@ -172,7 +174,7 @@ func (s *tagServiceServer) GetTag(c context.Context, req *tagservicepb.GetTagReq
 
 	// If it is, retrieve the hash record
 	if isLeaf {
-		info, err := s.client.HGetAll(c, req.TagName).Result()
+		info, err := s.store.HGetAll(c, req.TagName)
 		if err != nil {
 			return nil, fmt.Errorf("GetTag %s: %v", req.TagName, err)
 		}
@@ -182,7 +184,7 @@ func (s *tagServiceServer) GetTag(c context.Context, req *tagservicepb.GetTagReq
 	}
 
 	// Otherwise, retrieve set of child tags
-	childrenTags, err := s.client.SMembers(c, req.TagName).Result()
+	childrenTags, err := s.store.SMembers(c, req.TagName)
 	if err != nil {
 		return nil, fmt.Errorf("GetTag %s: %v", req.TagName, err)
 	}
@@ -199,7 +201,7 @@ func (s *tagServiceServer) _resolveTags(c context.Context, tags []string, resolv
 			resolvedTags = append(resolvedTags, ipTag)
 		} else {
 			// Get the tag record type since may be hash (if name value) or set (if parent tag)
-			valType, err := s.client.Type(c, tag).Result()
+			valType, err := s.store.Type(c, tag)
 			if err != nil {
 				return nil, fmt.Errorf("ResolveTag TYPE %s: %v", tag, err)
 			}
@@ -207,7 +209,7 @@ func (s *tagServiceServer) _resolveTags(c context.Context, tags []string, resolv
 			if valType == "none" { // The tag is not present
 				continue
 			} else if valType == "hash" { // The tag is a name record
-				info, err := s.client.HGetAll(c, tag).Result()
+				info, err := s.store.HGetAll(c, tag)
 				if err != nil {
 					return nil, fmt.Errorf("ResolveTag HGETALL %s: %v", tag, err)
 				}
@@ -215,7 +217,7 @@ func (s *tagServiceServer) _resolveTags(c context.Context, tags []string, resolv
 				ip := info["ip"]
 				resolvedTags = append(resolvedTags, &tagservicepb.TagMapping{Name: tag, Uri: &uri, Ip: &ip})
 			} else { // The tag has children that may also need resolved
-				childrenTags, err := s.client.SMembers(c, tag).Result()
+				childrenTags, err := s.store.SMembers(c, tag)
 				if err != nil {
 					return nil, fmt.Errorf("ResolveTag SMEMBERS %s: %v", tag, err)
 				}
@@ -245,12 +247,15 @@ func (s *tagServiceServer) ResolveTag(c context.Context, req *tagservicepb.Resol
 // Resolve a list of tags into all base-level IPs
 func (s *tagServiceServer) ListTags(c context.Context, req *tagservicepb.ListTagsRequest) (*tagservicepb.ListTagsResponse, error) {
 	var resolvedTagList []*tagservicepb.TagMapping
-	tags := s.client.Keys(c, "*").Val()
+	tags, err := s.store.Keys(c)
+	if err != nil {
+		return nil, fmt.Errorf("ListTags: %v", err)
+	}
 	for _, tag := range tags {
 		resp, err := s.GetTag(c, &tagservicepb.GetTagRequest{TagName: tag})
 		if err != nil {
 			// Ignore errors
-			utils.Log.Printf("Failed to get tag mapping of %s: %v\n", tag, err)
+			slog.WarnContext(c, "Failed to get tag mapping", utils.TagLogKey, tag, "error", err)
 			continue
 		}
 		resolvedTagList = append(resolvedTagList, resp.Tag)
@@ -261,7 +266,7 @@ func (s *tagServiceServer) ListTags(c context.Context, req *tagservicepb.ListTag
 
 // Delete a member of a tag
 func (s *tagServiceServer) DeleteTagMember(c context.Context, req *tagservicepb.DeleteTagMemberRequest) (*tagservicepb.DeleteTagMemberResponse, error) {
-	err := s.client.SRem(c, req.ParentTag, req.ChildTag).Err()
+	err := s.store.SRem(c, req.ParentTag, req.ChildTag)
 	if err != nil {
 		return &tagservicepb.DeleteTagMemberResponse{}, fmt.Errorf("DeleteTagMember %s: %v", req.ParentTag, err)
 	}
@@ -270,12 +275,12 @@ func (s *tagServiceServer) DeleteTagMember(c context.Context, req *tagservicepb.
 
 // Delete a leaf record for a tag
 func (s *tagServiceServer) _deleteLeafTag(c context.Context, tag *tagservicepb.TagMapping) error {
-	keys, err := s.client.HKeys(c, tag.Name).Result()
+	keys, err := s.store.HKeys(c, tag.Name)
 	if err != nil {
 		return err
 	}
 
-	err = s.client.HDel(c, tag.Name, keys...).Err()
+	err = s.store.HDel(c, tag.Name, keys...)
 	if err != nil {
 		return err
 	}
@@ -298,12 +303,12 @@ func (s *tagServiceServer) DeleteTag(c context.Context, req *tagservicepb.Delete
 	}
 
 	// Delete all children in mapping
-	childrenTags, err := s.client.SMembers(c, req.TagName).Result()
+	childrenTags, err := s.store.SMembers(c, req.TagName)
 	if err != nil {
 		return &tagservicepb.DeleteTagResponse{}, fmt.Errorf("DeleteTag %s: %v", req.TagName, err)
 	}
 
-	err = s.client.SRem(c, req.TagName, childrenTags).Err()
+	err = s.store.SRem(c, req.TagName, childrenTags...)
 	if err != nil {
 		return &tagservicepb.DeleteTagResponse{}, fmt.Errorf("DeleteTag %s: %v", req.TagName, err)
 	}
@@ -312,7 +317,7 @@ func (s *tagServiceServer) DeleteTag(c context.Context, req *tagservicepb.Delete
 
 // Subscribe to a tag
 func (s *tagServiceServer) Subscribe(c context.Context, req *tagservicepb.SubscribeRequest) (*tagservicepb.SubscribeResponse, error) {
-	err := s.client.SAdd(c, getSubscriptionKey(req.Subscription.TagName), req.Subscription.Subscriber).Err()
+	err := s.store.SAdd(c, getSubscriptionKey(req.Subscription.TagName), req.Subscription.Subscriber)
 	if err != nil {
 		return &tagservicepb.SubscribeResponse{}, fmt.Errorf("Subscribe: %v", err)
 	}
@@ -322,7 +327,7 @@ func (s *tagServiceServer) Subscribe(c context.Context, req *tagservicepb.Subscr
 
 // Unsubscribe from a tag
 func (s *tagServiceServer) Unsubscribe(c context.Context, req *tagservicepb.UnsubscribeRequest) (*tagservicepb.UnsubscribeResponse, error) {
-	err := s.client.SRem(c, getSubscriptionKey(req.Subscription.TagName), req.Subscription.Subscriber).Err()
+	err := s.store.SRem(c, getSubscriptionKey(req.Subscription.TagName), req.Subscription.Subscriber)
 	if err != nil {
 		return &tagservicepb.UnsubscribeResponse{}, fmt.Errorf("Unsubscribe: %v", err)
 	}
@@ -332,7 +337,7 @@ func (s *tagServiceServer) Unsubscribe(c context.Context, req *tagservicepb.Unsu
 
 // Get all subscribers to a tag
 func (s *tagServiceServer) GetSubscribers(c context.Context, req *tagservicepb.GetSubscribersRequest) (*tagservicepb.GetSubscribersResponse, error) {
-	subs, err := s.client.SMembers(c, getSubscriptionKey(req.TagName)).Result()
+	subs, err := s.store.SMembers(c, getSubscriptionKey(req.TagName))
 	if err != nil {
 		return nil, fmt.Errorf("GetSubscribers: %v", err)
 	}
@@ -341,13 +346,13 @@ func (s *tagServiceServer) GetSubscribers(c context.Context, req *tagservicepb.G
 }
 
 // Create a server for the tag service
-func newServer(database *redis.Client) *tagServiceServer {
-	s := &tagServiceServer{client: database}
+func newServer(store Store) *tagServiceServer {
+	s := &tagServiceServer{store: store}
 	return s
 }
 
-// Setup and run the server
-func Setup(dbPort int, serverPort int, clearKeys bool) {
+// Setup and run the server backed by the Redis database on dbPort (starting redis-server if it's not already running)
+func Setup(dbPort int, serverPort int, clearKeys bool, tlsConfig config.TLS) {
 	// Start the Redis server if it's not already running
 	pgrepCmd := exec.Command("pgrep", "redis-server")
 	if err := pgrepCmd.Run(); err != nil {
@@ -355,10 +360,10 @@ func Setup(dbPort int, serverPort int, clearKeys bool) {
 			// According to man pgrep, exit status 1 means "no processes matched or none of them could be signalled"
 			redisServerCmd := exec.Command("redis-server")
 			if err := redisServerCmd.Start(); err != nil {
-				fmt.Println("Failed to start redis server")
+				slog.Error("Failed to start redis server", "error", err)
 			}
 		} else {
-			fmt.Printf("Failed to check if redis-server is already running: %v\n", err.Error())
+			slog.Error("Failed to check if redis-server is already running", "error", err)
 		}
 	}
 
@@ -367,23 +372,52 @@ func Setup(dbPort int, serverPort int, clearKeys bool) {
 		Password: "", // no password set
 		DB:       0,  // use default DB
 	})
+	if err := serve(NewRedisStore(client), serverPort, clearKeys, tlsConfig); err != nil {
+		slog.Error("Failed to serve", "error", err)
+		os.Exit(1)
+	}
+}
+
+// Setup and run the server backed by the embedded bbolt database at path
+func SetupWithBoltStore(path string, serverPort int, clearKeys bool, tlsConfig config.TLS) error {
+	store, err := NewBoltStore(path)
+	if err != nil {
+		return err
+	}
+	if err := serve(store, serverPort, clearKeys, tlsConfig); err != nil {
+		store.Close()
+		return err
+	}
+	return nil
+}
+
+func serve(store Store, serverPort int, clearKeys bool, tlsConfig config.TLS) error {
 	if clearKeys {
-		fmt.Println("Flushed all keys")
-		client.FlushAll(context.Background())
+		if err := store.FlushAll(context.Background()); err != nil {
+			return fmt.Errorf("failed to flush keys: %w", err)
+		}
+		slog.Info("Flushed all keys")
+	}
+
+	creds, err := utils.LoadTLSCredentials(tlsConfig)
+	if err != nil {
+		return fmt.Errorf("failed to setup TLS: %w", err)
 	}
 
 	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", serverPort))
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		return fmt.Errorf("failed to listen: %w", err)
 	}
-	var opts []grpc.ServerOption
+	opts := []grpc.ServerOption{grpc.Creds(creds), grpc.ChainUnaryInterceptor(utils.MetricsServerInterceptor, utils.LogFieldsServerInterceptor()), utils.TracingServerOption()}
 	grpcServer := grpc.NewServer(opts...)
-	tagservicepb.RegisterTagServiceServer(grpcServer, newServer(client))
-	fmt.Printf("Serving TagService at localhost:%d\n", serverPort)
+	tagservicepb.RegisterTagServiceServer(grpcServer, newServer(store))
+	utils.RegisterHealthService(grpcServer)
+	slog.Info("Serving TagService", "address", lis.Addr().String())
 	go func() {
-		err = grpcServer.Serve(lis)
+		err := grpcServer.Serve(lis)
 		if err != nil {
-			fmt.Println(err.Error())
+			slog.Error("TagService stopped", "error", err)
 		}
 	}()
+	return nil
 }
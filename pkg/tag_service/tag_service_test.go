@@ -37,7 +37,7 @@ var (
 )
 
 func newTagServiceServer(database *redis.Client) *tagServiceServer {
-	s := &tagServiceServer{client: database}
+	s := &tagServiceServer{store: NewRedisStore(database)}
 	return s
 }
 
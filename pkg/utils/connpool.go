@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// Round robin (over the single address of a pool entry) is used since it takes the health of the server into account,
+// so calls fail fast while the server reports it isn't serving
+const healthCheckedServiceConfig = `{"loadBalancingConfig": [{"round_robin": {}}], "healthCheckConfig": {"serviceName": ""}}`
+
+const (
+	retryInitialBackoff = 100 * time.Millisecond
+	retryMaxBackoff     = 2 * time.Second
+)
+
+// CallPolicy is the deadline and retry policy of calls made over pooled connections
+type CallPolicy struct {
+	Timeout     time.Duration // Deadline of a call (including its retries) unless the caller set an earlier one, 0 for none
+	MaxAttempts int           // Attempts made for a call failing with UNAVAILABLE, 0 or 1 to never retry
+}
+
+// ConnPool keeps a long-lived, health-checked connection per address instead of connecting for every call.
+// Connections are shared by concurrent callers and must not be closed by them.
+type ConnPool struct {
+	credentials    credentials.TransportCredentials
+	policy         CallPolicy
+	methodPolicies map[string]CallPolicy // By full method name (e.g., "/paragliderpb.CloudPlugin/GetPermitList")
+	interceptors   []grpc.UnaryClientInterceptor
+	lock           sync.Mutex
+	conns          map[string]*grpc.ClientConn
+}
+
+// NewConnPool creates a pool whose calls follow policy unless methodPolicies has one for the method. The interceptors
+// run around the policy, so they see the outcome of a call after its retries.
+func NewConnPool(creds credentials.TransportCredentials, policy CallPolicy, methodPolicies map[string]CallPolicy, interceptors ...grpc.UnaryClientInterceptor) *ConnPool {
+	return &ConnPool{
+		credentials:    creds,
+		policy:         policy,
+		methodPolicies: methodPolicies,
+		interceptors:   interceptors,
+		conns:          make(map[string]*grpc.ClientConn),
+	}
+}
+
+// Get returns the connection to address, connecting if there isn't one yet
+func (p *ConnPool) Get(address string) (*grpc.ClientConn, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if conn, ok := p.conns[address]; ok && conn.GetState() != connectivity.Shutdown {
+		// Reconnect right away rather than waiting out the backoff, in case the server came back in the meantime
+		if conn.GetState() == connectivity.TransientFailure {
+			conn.ResetConnectBackoff()
+		}
+		return conn, nil
+	}
+
+	interceptors := append(append([]grpc.UnaryClientInterceptor{}, p.interceptors...), p.policyInterceptor)
+	conn, err := grpc.NewClient(address,
+		DialCredentials(p.credentials),
+		grpc.WithDefaultServiceConfig(healthCheckedServiceConfig),
+		grpc.WithChainUnaryInterceptor(interceptors...),
+		TracingDialOption(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	p.conns[address] = conn
+	return conn, nil
+}
+
+// Remove closes the connection to address (e.g., because a plugin moved to another address)
+func (p *ConnPool) Remove(address string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if conn, ok := p.conns[address]; ok {
+		conn.Close()
+		delete(p.conns, address)
+	}
+}
+
+// Close closes all connections of the pool
+func (p *ConnPool) Close() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for address, conn := range p.conns {
+		conn.Close()
+		delete(p.conns, address)
+	}
+}
+
+func (p *ConnPool) methodPolicy(method string) CallPolicy {
+	if policy, ok := p.methodPolicies[method]; ok {
+		return policy
+	}
+	return p.policy
+}
+
+// Applies the deadline and retries of the method's policy to a call
+func (p *ConnPool) policyInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	policy := p.methodPolicy(method)
+	if policy.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, policy.Timeout)
+		defer cancel()
+	}
+
+	backoff := retryInitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if status.Code(err) != codes.Unavailable || attempt >= policy.MaxAttempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, retryMaxBackoff)
+	}
+}
+
+// RegisterHealthService registers the standard gRPC health service on a server so pooled clients can check it's serving
+func RegisterHealthService(server *grpc.Server) {
+	healthpb.RegisterHealthServer(server, health.NewServer())
+}
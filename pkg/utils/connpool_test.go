@@ -0,0 +1,103 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
+)
+
+// Controller whose heartbeats fail as unavailable a number of times and whose registrations never finish in time
+type flakyController struct {
+	paragliderpb.UnimplementedControllerServer
+	lock     sync.Mutex
+	failures int
+	calls    int
+}
+
+func (s *flakyController) PluginHeartbeat(ctx context.Context, req *paragliderpb.PluginHeartbeatRequest) (*paragliderpb.PluginHeartbeatResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.calls++
+	if s.calls <= s.failures {
+		return nil, status.Error(codes.Unavailable, "unavailable")
+	}
+	return &paragliderpb.PluginHeartbeatResponse{}, nil
+}
+
+func (s *flakyController) RegisterPlugin(ctx context.Context, req *paragliderpb.RegisterPluginRequest) (*paragliderpb.RegisterPluginResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestConnPool(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	controller := &flakyController{failures: 2}
+	server := grpc.NewServer()
+	paragliderpb.RegisterControllerServer(server, controller)
+	RegisterHealthService(server)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	intercepted := 0
+	interceptor := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		intercepted++
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	pool := NewConnPool(nil, CallPolicy{MaxAttempts: 3}, map[string]CallPolicy{
+		paragliderpb.Controller_RegisterPlugin_FullMethodName: {Timeout: 50 * time.Millisecond},
+	}, interceptor)
+	defer pool.Close()
+
+	// Connections are reused
+	conn, err := pool.Get(lis.Addr().String())
+	require.NoError(t, err)
+	sameConn, err := pool.Get(lis.Addr().String())
+	require.NoError(t, err)
+	assert.Same(t, conn, sameConn)
+
+	// Unavailable calls are retried, and interceptors only see the final outcome
+	client := paragliderpb.NewControllerClient(conn)
+	_, err = client.PluginHeartbeat(context.Background(), &paragliderpb.PluginHeartbeatRequest{Name: "cloud"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, controller.calls)
+	assert.Equal(t, 1, intercepted)
+
+	// Calls fail once they run past the deadline of their policy
+	_, err = client.RegisterPlugin(context.Background(), &paragliderpb.RegisterPluginRequest{Name: "cloud"})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	// Removed connections are replaced by new ones
+	pool.Remove(lis.Addr().String())
+	newConn, err := pool.Get(lis.Addr().String())
+	require.NoError(t, err)
+	assert.NotSame(t, conn, newConn)
+}
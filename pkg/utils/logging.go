@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+
+	"google.golang.org/grpc"
+
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
+)
+
+// Keys of the request-scoped fields of log lines
+const (
+	MethodLogKey    = "method"
+	NamespaceLogKey = "namespace"
+	CloudLogKey     = "cloud"
+	ResourceLogKey  = "resource"
+	RulesLogKey     = "rules"
+	TagLogKey       = "tag"
+)
+
+type logAttrsKey struct{}
+
+func init() {
+	// Log lines carry the fields of their context even before (or without) SetupLogging
+	slog.SetDefault(slog.New(contextHandler{slog.NewTextHandler(os.Stderr, nil)}))
+}
+
+// SetupLogging makes the default slog logger write log lines in the configured format, destination and level
+func SetupLogging(loggingConfig config.Logging) error {
+	var level slog.Level
+	if loggingConfig.Level != "" {
+		if err := level.UnmarshalText([]byte(loggingConfig.Level)); err != nil {
+			return fmt.Errorf("invalid log level: %s", loggingConfig.Level)
+		}
+	}
+	if loggingConfig.Format != "" && loggingConfig.Format != config.TextLogFormat && loggingConfig.Format != config.JSONLogFormat {
+		return fmt.Errorf("invalid log format: %s", loggingConfig.Format)
+	}
+
+	var w io.Writer
+	switch loggingConfig.Destination {
+	case "", config.StderrLogDestination:
+		w = os.Stderr
+	case config.StdoutLogDestination:
+		w = os.Stdout
+	default:
+		// The file stays open for the lifetime of the process
+		file, err := os.OpenFile(loggingConfig.Destination, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("unable to open log file: %w", err)
+		}
+		w = file
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	if loggingConfig.Format == config.JSONLogFormat {
+		handler = slog.NewJSONHandler(w, opts)
+	} else {
+		handler = slog.NewTextHandler(w, opts)
+	}
+	slog.SetDefault(slog.New(contextHandler{handler}))
+	return nil
+}
+
+// WithLogAttrs returns a context whose log lines carry the given fields (as key-value pairs or slog.Attrs)
+// in addition to the fields of ctx
+func WithLogAttrs(ctx context.Context, args ...any) context.Context {
+	attrs := append(slices.Clip(LogAttrs(ctx)), slog.Group("", args...).Value.Group()...)
+	return context.WithValue(ctx, logAttrsKey{}, attrs)
+}
+
+// LogAttrs returns the fields added to the log lines of ctx
+func LogAttrs(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(logAttrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+// Adds the fields of the context of a log line to it
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.AddAttrs(LogAttrs(ctx)...)
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// LogFieldsServerInterceptor adds the method and the namespace, resource and rules of the requests handled by a
+// gRPC server to the log lines written while handling them, along with any given fields (e.g., the plugin's cloud)
+func LogFieldsServerInterceptor(fields ...any) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		args := append([]any{MethodLogKey, info.FullMethod}, fields...)
+		args = append(args, requestLogFields(req)...)
+		return handler(WithLogAttrs(ctx, args...), req)
+	}
+}
+
+// Gets the fields identifying what a request is about, for the fields its message has
+func requestLogFields(req any) []any {
+	fields := []any{}
+	if r, ok := req.(interface{ GetNamespace() string }); ok && r.GetNamespace() != "" {
+		fields = append(fields, NamespaceLogKey, r.GetNamespace())
+	}
+	if r, ok := req.(interface {
+		GetDeployment() *paragliderpb.ParagliderDeployment
+	}); ok && r.GetDeployment() != nil {
+		fields = append(fields, NamespaceLogKey, r.GetDeployment().Namespace)
+	}
+	if r, ok := req.(interface{ GetResource() string }); ok && r.GetResource() != "" {
+		fields = append(fields, ResourceLogKey, r.GetResource())
+	}
+	if r, ok := req.(interface {
+		GetRules() []*paragliderpb.PermitListRule
+	}); ok && len(r.GetRules()) > 0 {
+		names := make([]string, len(r.GetRules()))
+		for i, rule := range r.GetRules() {
+			names[i] = rule.Name
+		}
+		fields = append(fields, RulesLogKey, names)
+	}
+	if r, ok := req.(interface{ GetRuleNames() []string }); ok && len(r.GetRuleNames()) > 0 {
+		fields = append(fields, RulesLogKey, r.GetRuleNames())
+	}
+	return fields
+}
@@ -0,0 +1,127 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
+)
+
+// Reads the JSON log lines written to a file
+func readLogLines(t *testing.T, path string) []map[string]any {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	lines := []map[string]any{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := map[string]any{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestSetupLogging(t *testing.T) {
+	defaultLogger := slog.Default()
+	defer slog.SetDefault(defaultLogger)
+
+	assert.Error(t, SetupLogging(config.Logging{Level: "verbose"}))
+	assert.Error(t, SetupLogging(config.Logging{Format: "xml"}))
+	assert.Error(t, SetupLogging(config.Logging{Destination: filepath.Join(t.TempDir(), "missing", "paraglider.log")}))
+	require.NoError(t, SetupLogging(config.Logging{}))
+
+	path := filepath.Join(t.TempDir(), "paraglider.log")
+	require.NoError(t, SetupLogging(config.Logging{Format: config.JSONLogFormat, Destination: path, Level: "warn"}))
+
+	ctx := WithLogAttrs(context.Background(), NamespaceLogKey, "default", CloudLogKey, GCP)
+	ctx = WithLogAttrs(ctx, slog.String(ResourceLogKey, "vm-1"))
+	slog.InfoContext(ctx, "Filtered out")
+	slog.WarnContext(ctx, "Written", "key", "value")
+	slog.Error("Written without fields")
+
+	lines := readLogLines(t, path)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "WARN", lines[0]["level"])
+	assert.Equal(t, "Written", lines[0]["msg"])
+	assert.Equal(t, "value", lines[0]["key"])
+	assert.Equal(t, "default", lines[0][NamespaceLogKey])
+	assert.Equal(t, GCP, lines[0][CloudLogKey])
+	assert.Equal(t, "vm-1", lines[0][ResourceLogKey])
+	assert.NotContains(t, lines[1], NamespaceLogKey)
+}
+
+func TestWithLogAttrs(t *testing.T) {
+	parent := WithLogAttrs(context.Background(), NamespaceLogKey, "default")
+	child := WithLogAttrs(parent, CloudLogKey, AZURE)
+	sibling := WithLogAttrs(parent, CloudLogKey, IBM)
+
+	// Fields added to a context don't leak into the contexts derived from the same parent
+	assert.Equal(t, []slog.Attr{slog.String(NamespaceLogKey, "default")}, LogAttrs(parent))
+	assert.Equal(t, []slog.Attr{slog.String(NamespaceLogKey, "default"), slog.String(CloudLogKey, AZURE)}, LogAttrs(child))
+	assert.Equal(t, []slog.Attr{slog.String(NamespaceLogKey, "default"), slog.String(CloudLogKey, IBM)}, LogAttrs(sibling))
+	assert.Empty(t, LogAttrs(context.Background()))
+}
+
+func TestLogFieldsServerInterceptor(t *testing.T) {
+	interceptor := LogFieldsServerInterceptor(CloudLogKey, GCP)
+	info := &grpc.UnaryServerInfo{FullMethod: paragliderpb.CloudPlugin_AddPermitListRules_FullMethodName}
+
+	var fields map[string]any
+	handler := func(ctx context.Context, req any) (any, error) {
+		fields = map[string]any{}
+		for _, attr := range LogAttrs(ctx) {
+			fields[attr.Key] = attr.Value.Any()
+		}
+		return nil, nil
+	}
+
+	_, err := interceptor(context.Background(), &paragliderpb.AddPermitListRulesRequest{
+		Namespace: "default",
+		Resource:  "vm-1",
+		Rules:     []*paragliderpb.PermitListRule{{Name: "rule-1"}, {Name: "rule-2"}},
+	}, info, handler)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		MethodLogKey:    paragliderpb.CloudPlugin_AddPermitListRules_FullMethodName,
+		CloudLogKey:     GCP,
+		NamespaceLogKey: "default",
+		ResourceLogKey:  "vm-1",
+		RulesLogKey:     []string{"rule-1", "rule-2"},
+	}, fields)
+
+	_, err = interceptor(context.Background(), &paragliderpb.CreateResourceRequest{
+		Deployment: &paragliderpb.ParagliderDeployment{Id: "project", Namespace: "other"},
+	}, info, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "other", fields[NamespaceLogKey])
+	assert.NotContains(t, fields, RulesLogKey)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Path the metrics are served at
+const MetricsPath = "/metrics"
+
+// MetricsRegistry holds the metrics of all services running in the process, so a single endpoint exposes them all
+// when the services are started together
+var MetricsRegistry = prometheus.NewRegistry()
+
+var (
+	grpcServerRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "paraglider_grpc_server_requests_total",
+		Help: "gRPC requests handled, by service, method and status code.",
+	}, []string{"service", "method", "code"})
+	grpcServerRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "paraglider_grpc_server_request_duration_seconds",
+		Help:    "Time taken to handle gRPC requests, by service and method.",
+		Buckets: prometheus.ExponentialBuckets(0.005, 4, 10), // 5ms to ~22min, since plugins wait on long-running cloud operations
+	}, []string{"service", "method"})
+)
+
+func init() {
+	MetricsRegistry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		grpcServerRequests,
+		grpcServerRequestDuration,
+	)
+}
+
+// SplitMethodName splits a full gRPC method name (e.g., "/paragliderpb.CloudPlugin/GetPermitList") into its service and method
+func SplitMethodName(fullMethod string) (string, string) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return "unknown", service
+	}
+	return service, method
+}
+
+// MetricsServerInterceptor records the count and duration of the unary calls handled by a gRPC server
+func MetricsServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	service, method := SplitMethodName(info.FullMethod)
+	grpcServerRequests.WithLabelValues(service, method, status.Code(err).String()).Inc()
+	grpcServerRequestDuration.WithLabelValues(service, method).Observe(time.Since(start).Seconds())
+	return resp, err
+}
+
+// MetricsHandler serves the metrics of MetricsRegistry in the Prometheus exposition format
+func MetricsHandler() http.Handler {
+	return promhttp.HandlerFor(MetricsRegistry, promhttp.HandlerOpts{Registry: MetricsRegistry})
+}
+
+// ServeMetrics serves the metrics at MetricsPath on address (e.g., ":9090") in the background
+func ServeMetrics(address string) error {
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle(MetricsPath, MetricsHandler())
+	go func() {
+		if err := http.Serve(lis, mux); err != nil {
+			slog.Error("Metrics server stopped", "address", address, "error", err)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,71 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
+)
+
+func TestSplitMethodName(t *testing.T) {
+	service, method := SplitMethodName(paragliderpb.CloudPlugin_GetPermitList_FullMethodName)
+	assert.Equal(t, "paragliderpb.CloudPlugin", service)
+	assert.Equal(t, "GetPermitList", method)
+
+	service, method = SplitMethodName("malformed")
+	assert.Equal(t, "unknown", service)
+	assert.Equal(t, "malformed", method)
+}
+
+func TestMetricsServerInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: paragliderpb.Controller_PluginHeartbeat_FullMethodName}
+	failing := func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	succeeding := func(ctx context.Context, req any) (any, error) {
+		return &paragliderpb.PluginHeartbeatResponse{}, nil
+	}
+
+	_, err := MetricsServerInterceptor(context.Background(), nil, info, failing)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	resp, err := MetricsServerInterceptor(context.Background(), nil, info, succeeding)
+	require.NoError(t, err)
+	assert.NotNil(t, resp)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(grpcServerRequests.WithLabelValues("paragliderpb.Controller", "PluginHeartbeat", codes.NotFound.String())))
+	assert.Equal(t, 1.0, testutil.ToFloat64(grpcServerRequests.WithLabelValues("paragliderpb.Controller", "PluginHeartbeat", codes.OK.String())))
+
+	// The calls are exposed by the metrics handler
+	w := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, MetricsPath, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `paraglider_grpc_server_requests_total{code="NotFound",method="PluginHeartbeat",service="paragliderpb.Controller"} 1`)
+	assert.Contains(t, w.Body.String(), "paraglider_grpc_server_request_duration_seconds_count")
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
+)
+
+// Names of the fields of the responses which carry notices
+const (
+	warningsField    protoreflect.Name = "warnings"
+	sideEffectsField protoreflect.Name = "side_effects"
+)
+
+// Notices collects the warnings and side effects of a request, which are returned to its caller
+type Notices struct {
+	lock        sync.Mutex
+	warnings    []*paragliderpb.Notice
+	sideEffects []*paragliderpb.Notice
+}
+
+type noticesKey struct{}
+
+// WithNotices returns a context which collects the notices of the request it belongs to
+func WithNotices(ctx context.Context) (context.Context, *Notices) {
+	notices := &Notices{}
+	return context.WithValue(ctx, noticesKey{}, notices), notices
+}
+
+func getNotices(ctx context.Context) *Notices {
+	notices, _ := ctx.Value(noticesKey{}).(*Notices)
+	return notices
+}
+
+// CollectsNotices returns true if the notices of the request ctx belongs to are returned to someone
+func CollectsNotices(ctx context.Context) bool {
+	return getNotices(ctx) != nil
+}
+
+// AddWarning records a warning for the caller of the request ctx belongs to (if anyone collects its notices)
+func AddWarning(ctx context.Context, resource string, message string) {
+	if notices := getNotices(ctx); notices != nil {
+		notices.add(&notices.warnings, &paragliderpb.Notice{Message: message, Resource: resource})
+	}
+}
+
+// AddSideEffect records a side effect (e.g., infrastructure created) for the caller of the request ctx belongs to
+func AddSideEffect(ctx context.Context, resource string, message string) {
+	if notices := getNotices(ctx); notices != nil {
+		notices.add(&notices.sideEffects, &paragliderpb.Notice{Message: message, Resource: resource})
+	}
+}
+
+// AddResponseNotices records the notices of a response received from another service (e.g., a plugin)
+func AddResponseNotices(ctx context.Context, response any) {
+	notices := getNotices(ctx)
+	if notices == nil {
+		return
+	}
+	if r, ok := response.(interface{ GetWarnings() []*paragliderpb.Notice }); ok {
+		for _, notice := range r.GetWarnings() {
+			notices.add(&notices.warnings, notice)
+		}
+	}
+	if r, ok := response.(interface{ GetSideEffects() []*paragliderpb.Notice }); ok {
+		for _, notice := range r.GetSideEffects() {
+			notices.add(&notices.sideEffects, notice)
+		}
+	}
+}
+
+// Adds a notice unless the same one was already recorded (e.g., by another rule of the same request)
+func (n *Notices) add(list *[]*paragliderpb.Notice, notice *paragliderpb.Notice) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if !slices.ContainsFunc(*list, func(other *paragliderpb.Notice) bool { return proto.Equal(other, notice) }) {
+		*list = append(*list, notice)
+	}
+}
+
+// Warnings returns the warnings recorded so far
+func (n *Notices) Warnings() []*paragliderpb.Notice {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return slices.Clone(n.warnings)
+}
+
+// SideEffects returns the side effects recorded so far
+func (n *Notices) SideEffects() []*paragliderpb.Notice {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return slices.Clone(n.sideEffects)
+}
+
+// SetResponseNotices sets the warnings and side effects of a response to the ones recorded (responses without these
+// fields are left as they are)
+func (n *Notices) SetResponseNotices(response any) {
+	message, ok := response.(proto.Message)
+	if !ok || !message.ProtoReflect().IsValid() {
+		return
+	}
+	setNoticesField(message.ProtoReflect(), warningsField, n.Warnings())
+	setNoticesField(message.ProtoReflect(), sideEffectsField, n.SideEffects())
+}
+
+func setNoticesField(message protoreflect.Message, name protoreflect.Name, notices []*paragliderpb.Notice) {
+	field := message.Descriptor().Fields().ByName(name)
+	if field == nil || !field.IsList() || field.Message() == nil || field.Message().FullName() != (&paragliderpb.Notice{}).ProtoReflect().Descriptor().FullName() {
+		return
+	}
+	if len(notices) == 0 {
+		message.Clear(field)
+		return
+	}
+	list := message.NewField(field).List()
+	for _, notice := range notices {
+		list.Append(protoreflect.ValueOfMessage(notice.ProtoReflect()))
+	}
+	message.Set(field, protoreflect.ValueOfList(list))
+}
+
+// NoticesServerInterceptor returns the warnings and side effects recorded while handling the requests of a gRPC server
+// in their responses
+func NoticesServerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, notices := WithNotices(ctx)
+	resp, err := handler(ctx, req)
+	if err == nil {
+		// Keep the notices the handler put in the response itself
+		AddResponseNotices(ctx, resp)
+		notices.SetResponseNotices(resp)
+	}
+	return resp, err
+}
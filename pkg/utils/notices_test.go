@@ -0,0 +1,88 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
+)
+
+func TestNotices(t *testing.T) {
+	// Nothing is recorded without a collector
+	ctx := context.Background()
+	assert.False(t, CollectsNotices(ctx))
+	AddWarning(ctx, "resource", "ignored")
+
+	ctx, notices := WithNotices(ctx)
+	assert.True(t, CollectsNotices(ctx))
+	AddWarning(ctx, "resource", "targets for rule a ignored")
+	AddWarning(ctx, "resource", "targets for rule a ignored")
+	AddSideEffect(ctx, "gateway", "created VPN gateway gateway")
+
+	// Notices of responses from other services are merged, dropping duplicates
+	AddResponseNotices(ctx, &paragliderpb.ConnectCloudsResponse{
+		Warnings:    []*paragliderpb.Notice{{Message: "targets for rule a ignored", Resource: "resource"}},
+		SideEffects: []*paragliderpb.Notice{{Message: "created VNet peering peering"}},
+	})
+	AddResponseNotices(ctx, &paragliderpb.GetPermitListResponse{})
+
+	require.Len(t, notices.Warnings(), 1)
+	assert.Equal(t, "targets for rule a ignored", notices.Warnings()[0].Message)
+	require.Len(t, notices.SideEffects(), 2)
+	assert.Equal(t, "gateway", notices.SideEffects()[0].Resource)
+	assert.Equal(t, "created VNet peering peering", notices.SideEffects()[1].Message)
+
+	// Responses with notices get them, others are left as they are
+	resp := &paragliderpb.AddPermitListRulesResponse{}
+	notices.SetResponseNotices(resp)
+	assert.Len(t, resp.Warnings, 1)
+	assert.Len(t, resp.SideEffects, 2)
+
+	permitList := &paragliderpb.GetPermitListResponse{}
+	notices.SetResponseNotices(permitList)
+	assert.True(t, proto.Equal(&paragliderpb.GetPermitListResponse{}, permitList))
+	notices.SetResponseNotices(nil)
+}
+
+func TestNoticesServerInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/paragliderpb.CloudPlugin/CreateVpnGateway"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		AddSideEffect(ctx, "gateway", "created VPN gateway gateway")
+		return &paragliderpb.CreateVpnGatewayResponse{
+			Asn:      64512,
+			Warnings: []*paragliderpb.Notice{{Message: "set by the handler"}},
+		}, nil
+	}
+
+	resp, err := NoticesServerInterceptor(context.Background(), &paragliderpb.CreateVpnGatewayRequest{}, info, handler)
+	require.NoError(t, err)
+	gatewayResp := resp.(*paragliderpb.CreateVpnGatewayResponse)
+	assert.Equal(t, uint32(64512), gatewayResp.Asn)
+	require.Len(t, gatewayResp.Warnings, 1)
+	assert.Equal(t, "set by the handler", gatewayResp.Warnings[0].Message)
+	require.Len(t, gatewayResp.SideEffects, 1)
+	assert.Equal(t, "created VPN gateway gateway", gatewayResp.SideEffects[0].Message)
+}
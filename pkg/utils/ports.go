@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
+)
+
+const (
+	PortAny int32 = -1 // Port of rules which apply to all ports
+	maxPort int32 = 65535
+)
+
+// SrcPortRanges returns the source ports of a rule as port ranges, or nil if the rule applies to all source ports
+func SrcPortRanges(rule *paragliderpb.PermitListRule) []*paragliderpb.PortRange {
+	return portRanges(rule.SrcPort, rule.SrcPorts)
+}
+
+// DstPortRanges returns the destination ports of a rule as port ranges, or nil if the rule applies to all destination ports
+func DstPortRanges(rule *paragliderpb.PermitListRule) []*paragliderpb.PortRange {
+	return portRanges(rule.DstPort, rule.DstPorts)
+}
+
+func portRanges(port int32, ranges []*paragliderpb.PortRange) []*paragliderpb.PortRange {
+	if len(ranges) > 0 {
+		return MergePortRanges(ranges)
+	}
+	if port == PortAny {
+		return nil
+	}
+	return []*paragliderpb.PortRange{{Start: port, End: port}}
+}
+
+// MergePortRanges sorts port ranges and merges the ones which overlap or are adjacent. Ranges covering all ports are returned as nil.
+func MergePortRanges(ranges []*paragliderpb.PortRange) []*paragliderpb.PortRange {
+	sorted := make([]*paragliderpb.PortRange, len(ranges))
+	for i, r := range ranges {
+		sorted[i] = &paragliderpb.PortRange{Start: r.Start, End: r.End}
+	}
+	slices.SortFunc(sorted, func(a, b *paragliderpb.PortRange) int { return int(a.Start - b.Start) })
+
+	merged := []*paragliderpb.PortRange{}
+	for _, r := range sorted {
+		if last := len(merged) - 1; last >= 0 && r.Start <= merged[last].End+1 {
+			merged[last].End = max(merged[last].End, r.End)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	if len(merged) == 1 && merged[0].Start <= 0 && merged[0].End >= maxPort {
+		return nil
+	}
+	return merged
+}
+
+// PortRangesEqual returns whether two lists of port ranges are the same
+func PortRangesEqual(a, b []*paragliderpb.PortRange) bool {
+	return slices.EqualFunc(a, b, func(x, y *paragliderpb.PortRange) bool { return x.Start == y.Start && x.End == y.End })
+}
+
+// SetSrcPortRanges sets the source ports of a rule (nil for all ports), using src_port whenever a single port or all ports are allowed
+func SetSrcPortRanges(rule *paragliderpb.PermitListRule, ranges []*paragliderpb.PortRange) {
+	rule.SrcPort, rule.SrcPorts = canonicalPorts(ranges)
+}
+
+// SetDstPortRanges sets the destination ports of a rule (nil for all ports), using dst_port whenever a single port or all ports are allowed
+func SetDstPortRanges(rule *paragliderpb.PermitListRule, ranges []*paragliderpb.PortRange) {
+	rule.DstPort, rule.DstPorts = canonicalPorts(ranges)
+}
+
+func canonicalPorts(ranges []*paragliderpb.PortRange) (int32, []*paragliderpb.PortRange) {
+	ranges = MergePortRanges(ranges)
+	switch {
+	case len(ranges) == 0:
+		return PortAny, nil
+	case len(ranges) == 1 && ranges[0].Start == ranges[0].End:
+		return ranges[0].Start, nil
+	default:
+		return 0, ranges
+	}
+}
+
+// NormalizePorts rewrites the ports of a rule in the form rules read back from the clouds take, so the two compare equal
+func NormalizePorts(rule *paragliderpb.PermitListRule) {
+	SetSrcPortRanges(rule, SrcPortRanges(rule))
+	SetDstPortRanges(rule, DstPortRanges(rule))
+}
+
+// ValidatePortRanges checks the port ranges of a rule are well-formed
+func ValidatePortRanges(rule *paragliderpb.PermitListRule) error {
+	for _, r := range slices.Concat(rule.SrcPorts, rule.DstPorts) {
+		if r.Start < 0 || r.End > maxPort || r.Start > r.End {
+			return fmt.Errorf("rule %s: invalid port range %d-%d", rule.Name, r.Start, r.End)
+		}
+	}
+	return nil
+}
+
+// FormatPortRange formats a port range as the clouds do (e.g., "80" or "8000-8100")
+func FormatPortRange(r *paragliderpb.PortRange) string {
+	if r.Start == r.End {
+		return strconv.Itoa(int(r.Start))
+	}
+	return fmt.Sprintf("%d-%d", r.Start, r.End)
+}
+
+// ParsePortRange parses a port range formatted by FormatPortRange
+func ParsePortRange(value string) (*paragliderpb.PortRange, error) {
+	startValue, endValue, isRange := strings.Cut(value, "-")
+	start, err := strconv.Atoi(startValue)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port range %q", value)
+	}
+	end := start
+	if isRange {
+		if end, err = strconv.Atoi(endValue); err != nil {
+			return nil, fmt.Errorf("invalid port range %q", value)
+		}
+	}
+	return &paragliderpb.PortRange{Start: int32(start), End: int32(end)}, nil
+}
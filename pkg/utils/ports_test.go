@@ -0,0 +1,78 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
+)
+
+func TestPortRanges(t *testing.T) {
+	rule := &paragliderpb.PermitListRule{SrcPort: PortAny, DstPort: 443}
+	assert.Nil(t, SrcPortRanges(rule))
+	assert.Equal(t, []*paragliderpb.PortRange{{Start: 443, End: 443}}, DstPortRanges(rule))
+
+	// The ranges take precedence over the single port
+	rule.DstPorts = []*paragliderpb.PortRange{{Start: 8000, End: 8100}, {Start: 80, End: 80}, {Start: 8050, End: 8200}, {Start: 81, End: 81}}
+	assert.Equal(t, []*paragliderpb.PortRange{{Start: 80, End: 81}, {Start: 8000, End: 8200}}, DstPortRanges(rule))
+
+	rule.DstPorts = []*paragliderpb.PortRange{{Start: 0, End: 1000}, {Start: 1001, End: 65535}}
+	assert.Nil(t, DstPortRanges(rule))
+}
+
+func TestNormalizePorts(t *testing.T) {
+	rule := &paragliderpb.PermitListRule{
+		SrcPorts: []*paragliderpb.PortRange{{Start: 22, End: 22}},
+		DstPort:  7,
+		DstPorts: []*paragliderpb.PortRange{{Start: 443, End: 443}, {Start: 80, End: 80}},
+	}
+	NormalizePorts(rule)
+	assert.Equal(t, int32(22), rule.SrcPort)
+	assert.Nil(t, rule.SrcPorts)
+	assert.Equal(t, int32(0), rule.DstPort)
+	assert.Equal(t, []*paragliderpb.PortRange{{Start: 80, End: 80}, {Start: 443, End: 443}}, rule.DstPorts)
+
+	SetDstPortRanges(rule, nil)
+	assert.Equal(t, PortAny, rule.DstPort)
+	assert.Nil(t, rule.DstPorts)
+}
+
+func TestValidatePortRanges(t *testing.T) {
+	assert.NoError(t, ValidatePortRanges(&paragliderpb.PermitListRule{DstPorts: []*paragliderpb.PortRange{{Start: 0, End: 65535}}}))
+	assert.Error(t, ValidatePortRanges(&paragliderpb.PermitListRule{DstPorts: []*paragliderpb.PortRange{{Start: 100, End: 99}}}))
+	assert.Error(t, ValidatePortRanges(&paragliderpb.PermitListRule{SrcPorts: []*paragliderpb.PortRange{{Start: 1, End: 65536}}}))
+}
+
+func TestParsePortRange(t *testing.T) {
+	for _, r := range []*paragliderpb.PortRange{{Start: 80, End: 80}, {Start: 8000, End: 8100}} {
+		parsed, err := ParsePortRange(FormatPortRange(r))
+		require.NoError(t, err)
+		assert.Equal(t, r, parsed)
+	}
+	assert.Equal(t, "8000-8100", FormatPortRange(&paragliderpb.PortRange{Start: 8000, End: 8100}))
+
+	_, err := ParsePortRange("http")
+	assert.Error(t, err)
+	_, err = ParsePortRange("80-")
+	assert.Error(t, err)
+}
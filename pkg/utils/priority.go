@@ -0,0 +1,55 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+
+	paragliderpb "github.com/paraglider-project/paraglider/pkg/paragliderpb"
+)
+
+// MaxRulePriority is the largest relative priority a rule can have. Rules with lower priorities are evaluated first.
+const MaxRulePriority int32 = 9
+
+// NumRuleRanks is the number of distinct positions a rule can take in the evaluation order (see RuleRank)
+const NumRuleRanks = 2 * (MaxRulePriority + 1)
+
+// ValidateRulePriority checks the action and priority of a rule are well-formed
+func ValidateRulePriority(rule *paragliderpb.PermitListRule) error {
+	if _, ok := paragliderpb.RuleAction_name[int32(rule.Action)]; !ok {
+		return fmt.Errorf("rule %s: invalid action %d", rule.Name, rule.Action)
+	}
+	if rule.Priority < 0 || rule.Priority > MaxRulePriority {
+		return fmt.Errorf("rule %s: priority %d is not between 0 and %d", rule.Name, rule.Priority, MaxRulePriority)
+	}
+	return nil
+}
+
+// RuleRank returns the position of a rule in the evaluation order, from 0 to NumRuleRanks-1.
+// Rules are evaluated by increasing priority and, for rules with the same priority, deny rules come before allow rules.
+func RuleRank(rule *paragliderpb.PermitListRule) int32 {
+	rank := 2 * rule.Priority
+	if rule.Action != paragliderpb.RuleAction_DENY {
+		rank++
+	}
+	return rank
+}
+
+// PriorityOfRank returns the relative priority of rules at the given position in the evaluation order
+func PriorityOfRank(rank int32) int32 {
+	return rank / 2
+}
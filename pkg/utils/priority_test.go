@@ -0,0 +1,58 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
+)
+
+func TestValidateRulePriority(t *testing.T) {
+	rule := &paragliderpb.PermitListRule{Name: "rule", Action: paragliderpb.RuleAction_DENY, Priority: MaxRulePriority}
+	require.NoError(t, ValidateRulePriority(rule))
+
+	rule.Priority = MaxRulePriority + 1
+	assert.Error(t, ValidateRulePriority(rule))
+	rule.Priority = -1
+	assert.Error(t, ValidateRulePriority(rule))
+
+	rule.Priority = 0
+	rule.Action = paragliderpb.RuleAction(5)
+	assert.Error(t, ValidateRulePriority(rule))
+}
+
+func TestRuleRank(t *testing.T) {
+	allow := &paragliderpb.PermitListRule{Priority: 1}
+	deny := &paragliderpb.PermitListRule{Priority: 1, Action: paragliderpb.RuleAction_DENY}
+	lowerDeny := &paragliderpb.PermitListRule{Priority: 2, Action: paragliderpb.RuleAction_DENY}
+
+	// Deny rules win ties, but not over rules with a lower priority
+	assert.Less(t, RuleRank(deny), RuleRank(allow))
+	assert.Less(t, RuleRank(allow), RuleRank(lowerDeny))
+	assert.Equal(t, int32(0), RuleRank(&paragliderpb.PermitListRule{Action: paragliderpb.RuleAction_DENY}))
+	assert.Equal(t, NumRuleRanks-1, RuleRank(&paragliderpb.PermitListRule{Priority: MaxRulePriority}))
+
+	for _, rule := range []*paragliderpb.PermitListRule{allow, deny, lowerDeny} {
+		assert.Equal(t, rule.Priority, PriorityOfRank(RuleRank(rule)))
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
+)
+
+// How long plugins wait before trying to register again after a failure
+var registrationRetryInterval = 10 * time.Second
+
+// GetPluginAddresses returns the address a cloud plugin listens on and the address it registers with the controller.
+// Without an advertised address, the plugin is only reachable on localhost. Otherwise, it listens on all interfaces
+// since the controller reaches it from another machine.
+func GetPluginAddresses(port int, advertiseAddress string) (listenAddress string, registeredAddress string) {
+	if advertiseAddress == "" {
+		address := fmt.Sprintf("localhost:%d", port)
+		return address, address
+	}
+	return fmt.Sprintf(":%d", port), advertiseAddress
+}
+
+// RegisterPlugin registers a cloud plugin with the controller and sends heartbeats until ctx is done.
+// The plugin registers again whenever the controller doesn't know it anymore (e.g., after the controller restarted).
+func RegisterPlugin(ctx context.Context, controllerAddress string, creds credentials.TransportCredentials, registration *paragliderpb.RegisterPluginRequest) {
+	conn, err := grpc.NewClient(controllerAddress, DialCredentials(creds), TracingDialOption())
+	if err != nil {
+		slog.ErrorContext(ctx, "Unable to connect to controller to register plugin", "plugin", registration.Name, "error", err)
+		return
+	}
+	defer conn.Close()
+	client := paragliderpb.NewControllerClient(conn)
+
+	registered := false
+	interval := registrationRetryInterval
+	for {
+		if !registered {
+			resp, err := client.RegisterPlugin(ctx, registration)
+			if err != nil {
+				slog.WarnContext(ctx, "Unable to register plugin", "plugin", registration.Name, "error", err)
+				interval = registrationRetryInterval
+			} else {
+				registered = true
+				if resp.HeartbeatIntervalSeconds > 0 {
+					interval = time.Duration(resp.HeartbeatIntervalSeconds) * time.Second
+				}
+			}
+		} else {
+			_, err := client.PluginHeartbeat(ctx, &paragliderpb.PluginHeartbeatRequest{Name: registration.Name})
+			if status.Code(err) == codes.NotFound {
+				registered = false
+				continue
+			}
+			if err != nil {
+				slog.WarnContext(ctx, "Unable to send heartbeat of plugin", "plugin", registration.Name, "error", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
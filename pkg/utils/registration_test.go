@@ -0,0 +1,111 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
+)
+
+// Controller which forgets registered plugins after every heartbeat
+type registrationController struct {
+	paragliderpb.UnimplementedControllerServer
+	lock          sync.Mutex
+	registrations []*paragliderpb.RegisterPluginRequest
+	heartbeats    int
+	registered    bool
+}
+
+func (s *registrationController) RegisterPlugin(ctx context.Context, req *paragliderpb.RegisterPluginRequest) (*paragliderpb.RegisterPluginResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.registrations = append(s.registrations, req)
+	s.registered = true
+	return &paragliderpb.RegisterPluginResponse{}, nil
+}
+
+func (s *registrationController) PluginHeartbeat(ctx context.Context, req *paragliderpb.PluginHeartbeatRequest) (*paragliderpb.PluginHeartbeatResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !s.registered {
+		return nil, status.Error(codes.NotFound, "not registered")
+	}
+	s.heartbeats++
+	s.registered = false
+	return &paragliderpb.PluginHeartbeatResponse{}, nil
+}
+
+func TestRegisterPlugin(t *testing.T) {
+	registrationRetryInterval = 10 * time.Millisecond
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	controller := &registrationController{}
+	server := grpc.NewServer()
+	paragliderpb.RegisterControllerServer(server, controller)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	registration := &paragliderpb.RegisterPluginRequest{Name: "cloud", Address: "localhost:1234", Version: "v1", Capabilities: []string{"CreateResource"}}
+	go func() {
+		RegisterPlugin(ctx, lis.Addr().String(), nil, registration)
+		close(done)
+	}()
+
+	// The plugin registers again after the controller forgot it
+	require.Eventually(t, func() bool {
+		controller.lock.Lock()
+		defer controller.lock.Unlock()
+		return len(controller.registrations) >= 2 && controller.heartbeats >= 1
+	}, 5*time.Second, 10*time.Millisecond)
+	controller.lock.Lock()
+	assert.Equal(t, "localhost:1234", controller.registrations[0].Address)
+	assert.Equal(t, []string{"CreateResource"}, controller.registrations[0].Capabilities)
+	controller.lock.Unlock()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("registration did not stop")
+	}
+}
+
+func TestGetPluginAddresses(t *testing.T) {
+	listenAddress, registeredAddress := GetPluginAddresses(1000, "")
+	assert.Equal(t, "localhost:1000", listenAddress)
+	assert.Equal(t, "localhost:1000", registeredAddress)
+
+	listenAddress, registeredAddress = GetPluginAddresses(1000, "plugin.example.com:2000")
+	assert.Equal(t, ":1000", listenAddress)
+	assert.Equal(t, "plugin.example.com:2000", registeredAddress)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+)
+
+// LoadTLSCredentials loads the credentials of a service's gRPC links. The same credentials are used by the service's
+// server (which requires client certificates) and by its clients. Without TLS configured, the links are in plaintext.
+func LoadTLSCredentials(cfg config.TLS) (credentials.TransportCredentials, error) {
+	if !cfg.Enabled() {
+		return insecure.NewCredentials(), nil
+	}
+	if cfg.CertFile == "" || cfg.KeyFile == "" || cfg.CAFile == "" {
+		return nil, fmt.Errorf("TLS requires a certificate, a key and a CA file")
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not load certificate: %w", err)
+	}
+	caPEM, err := os.ReadFile(cfg.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read CAs: %w", err)
+	}
+	cas := x509.NewCertPool()
+	if !cas.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates found in %s", cfg.CAFile)
+	}
+
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      cas,
+		ClientCAs:    cas,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}), nil
+}
+
+// DialCredentials returns the dial option for creds (plaintext if creds is nil)
+func DialCredentials(creds credentials.TransportCredentials) grpc.DialOption {
+	if creds == nil {
+		creds = insecure.NewCredentials()
+	}
+	return grpc.WithTransportCredentials(creds)
+}
@@ -0,0 +1,164 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	file string
+}
+
+func writePEM(t *testing.T, path string, blockType string, der []byte) {
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600))
+}
+
+func newTestCA(t *testing.T, dir string, name string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	file := filepath.Join(dir, name+".pem")
+	writePEM(t, file, "CERTIFICATE", der)
+	return &testCA{cert: cert, key: key, file: file}
+}
+
+// Issues a certificate valid for localhost and returns the TLS config of a service using it
+func (ca *testCA) issue(t *testing.T, dir string, name string) config.TLS {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	tlsConfig := config.TLS{CertFile: filepath.Join(dir, name+".crt"), KeyFile: filepath.Join(dir, name+".key"), CAFile: ca.file}
+	writePEM(t, tlsConfig.CertFile, "CERTIFICATE", der)
+	writePEM(t, tlsConfig.KeyFile, "EC PRIVATE KEY", keyDER)
+	return tlsConfig
+}
+
+func TestLoadTLSCredentials(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "ca")
+
+	// Plaintext without TLS configured
+	creds, err := LoadTLSCredentials(config.TLS{})
+	require.NoError(t, err)
+	require.Equal(t, "insecure", creds.Info().SecurityProtocol)
+
+	// Partial configurations are rejected
+	_, err = LoadTLSCredentials(config.TLS{CertFile: "cert.pem"})
+	require.Error(t, err)
+
+	// Missing files
+	_, err = LoadTLSCredentials(config.TLS{CertFile: "missing.crt", KeyFile: "missing.key", CAFile: ca.file})
+	require.Error(t, err)
+
+	// CA file without certificates
+	tlsConfig := ca.issue(t, dir, "server")
+	tlsConfig.CAFile = tlsConfig.KeyFile
+	_, err = LoadTLSCredentials(tlsConfig)
+	require.Error(t, err)
+
+	tlsConfig.CAFile = ca.file
+	creds, err = LoadTLSCredentials(tlsConfig)
+	require.NoError(t, err)
+	require.Equal(t, "tls", creds.Info().SecurityProtocol)
+}
+
+func TestTLSCredentialsMutualAuthentication(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "ca")
+	otherCA := newTestCA(t, dir, "other-ca")
+
+	serverCreds, err := LoadTLSCredentials(ca.issue(t, dir, "server"))
+	require.NoError(t, err)
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(grpc.Creds(serverCreds))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(lis)
+	defer server.Stop()
+
+	check := func(opt grpc.DialOption) error {
+		conn, err := grpc.NewClient(lis.Addr().String(), opt)
+		require.NoError(t, err)
+		defer conn.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		return err
+	}
+
+	// Clients with a certificate from the same CA are accepted
+	clientCreds, err := LoadTLSCredentials(ca.issue(t, dir, "client"))
+	require.NoError(t, err)
+	require.NoError(t, check(DialCredentials(clientCreds)))
+
+	// Plaintext clients and clients with certificates from other CAs are not
+	require.Error(t, check(DialCredentials(nil)))
+	otherCreds, err := LoadTLSCredentials(otherCA.issue(t, dir, "other-client"))
+	require.NoError(t, err)
+	require.Error(t, check(DialCredentials(otherCreds)))
+}
@@ -0,0 +1,102 @@
+//go:build unit
+
+/*
+Copyright 2024 The Paraglider Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc"
+
+	"github.com/paraglider-project/paraglider/pkg/orchestrator/config"
+	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
+)
+
+// Controller which remembers the span context of the last heartbeat it handled
+type tracedController struct {
+	paragliderpb.UnimplementedControllerServer
+	spanContext trace.SpanContext
+}
+
+func (s *tracedController) PluginHeartbeat(ctx context.Context, req *paragliderpb.PluginHeartbeatRequest) (*paragliderpb.PluginHeartbeatResponse, error) {
+	s.spanContext = trace.SpanContextFromContext(ctx)
+	return &paragliderpb.PluginHeartbeatResponse{}, nil
+}
+
+func TestSetupTracing(t *testing.T) {
+	// Without an exporter, traces are only propagated
+	shutdown, err := SetupTracing(context.Background(), "test", config.Tracing{})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	shutdown, err = SetupTracing(context.Background(), "test", config.Tracing{Exporter: config.StdoutTraceExporter})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+
+	_, err = SetupTracing(context.Background(), "test", config.Tracing{Exporter: "invalid"})
+	assert.Error(t, err)
+}
+
+func TestTracingPropagation(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	controller := &tracedController{}
+	server := grpc.NewServer(TracingServerOption())
+	paragliderpb.RegisterControllerServer(server, controller)
+	RegisterHealthService(server)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	pool := NewConnPool(nil, CallPolicy{}, nil)
+	defer pool.Close()
+	conn, err := pool.Get(lis.Addr().String())
+	require.NoError(t, err)
+
+	ctx, span := provider.Tracer("test").Start(context.Background(), "parent")
+	_, err = paragliderpb.NewControllerClient(conn).PluginHeartbeat(ctx, &paragliderpb.PluginHeartbeatRequest{Name: "cloud"})
+	require.NoError(t, err)
+	span.End()
+
+	// The server continues the trace of the client, whose call is recorded as a child of the caller's span
+	assert.Equal(t, span.SpanContext().TraceID(), controller.spanContext.TraceID())
+	var clientSpan sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		if s.SpanKind() == trace.SpanKindClient {
+			clientSpan = s
+		}
+	}
+	require.NotNil(t, clientSpan)
+	assert.Equal(t, "paragliderpb.Controller/PluginHeartbeat", clientSpan.Name())
+	assert.Equal(t, span.SpanContext().SpanID(), clientSpan.Parent().SpanID())
+}
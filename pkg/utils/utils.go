@@ -18,7 +18,6 @@ package utils
 
 import (
 	"fmt"
-	"log"
 	"net"
 	"net/netip"
 	"os"
@@ -27,10 +26,6 @@ import (
 	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
 )
 
-var (
-	Log *log.Logger
-)
-
 // Cloud names
 // TODO @seankimkdy: turn these into its own type and use enums
 const (
@@ -40,20 +35,16 @@ const (
 	AWS   = "aws"
 )
 
-// Private address spaces as defined in RFC 1918
+// Private address spaces as defined in RFC 1918, and unique local IPv6 addresses as defined in RFC 4193
 var privateAddressSpaces = []netip.Prefix{
 	netip.MustParsePrefix("10.0.0.0/8"),
 	netip.MustParsePrefix("172.16.0.0/12"),
 	netip.MustParsePrefix("192.168.0.0/16"),
+	netip.MustParsePrefix("fc00::/7"),
 }
 
-func init() {
-	file, err := os.Create("paraglider.log")
-	if err != nil {
-		log.Fatalf("error opening file: %v", err)
-	}
-	Log = log.New(file, "", log.LstdFlags|log.Lshortfile)
-}
+// Prefix length of the IPv6 subnets of VPCs/VNets (the only length Azure and AWS allow)
+const ipv6SubnetPrefixLen = 64
 
 // Checks if a Paraglider permit list rule tag (either an address or address space) is contained within an address space.
 func IsPermitListRuleTagInAddressSpace(permitListRuleTag string, addressSpaces []string) (bool, error) {
@@ -108,6 +99,45 @@ func IsIpAddressPrivate(addressString string) (bool, error) {
 	return false, nil
 }
 
+// Checks if an IP address or address space is an IPv6 one
+func IsIPv6(addressString string) bool {
+	if strings.Contains(addressString, "/") {
+		prefix, err := netip.ParsePrefix(addressString)
+		return err == nil && !prefix.Addr().Unmap().Is4()
+	}
+	addr, err := netip.ParseAddr(addressString)
+	return err == nil && !addr.Unmap().Is4()
+}
+
+// Checks if the targets of a permit list rule are IPv6 addresses. Rules can't mix IPv4 and IPv6 targets since
+// the clouds need the resource's address of the same version in the underlying rule.
+func IsPermitListRuleIPv6(permitListRule *paragliderpb.PermitListRule) (bool, error) {
+	ipv4, ipv6 := false, false
+	for _, target := range permitListRule.Targets {
+		if IsIPv6(target) {
+			ipv6 = true
+		} else {
+			ipv4 = true
+		}
+	}
+	if ipv4 && ipv6 {
+		return false, fmt.Errorf("rule %s mixes IPv4 and IPv6 targets", permitListRule.Name)
+	}
+	return ipv6, nil
+}
+
+// Returns the first IPv6 subnet (a /64) of an IPv6 address space
+func GetIPv6SubnetPrefix(addressSpace string) (string, error) {
+	prefix, err := netip.ParsePrefix(addressSpace)
+	if err != nil {
+		return "", err
+	}
+	if prefix.Addr().Is4() || prefix.Bits() > ipv6SubnetPrefixLen {
+		return "", fmt.Errorf("%s is not an IPv6 address space of at least /%d", addressSpace, ipv6SubnetPrefixLen)
+	}
+	return netip.PrefixFrom(prefix.Masked().Addr(), ipv6SubnetPrefixLen).String(), nil
+}
+
 type PeeringCloudInfo struct {
 	Cloud      string
 	Namespace  string
@@ -169,7 +199,8 @@ func MatchCloudProviders(cloud1, cloud2, target1, target2 string) bool {
 
 // Returns the number of VPN connections needed between cloud1 and cloud2
 func GetNumVpnConnections(cloud1, cloud2 string) int {
-	if MatchCloudProviders(cloud1, cloud2, AZURE, GCP) || MatchCloudProviders(cloud1, cloud2, AZURE, IBM) {
+	if MatchCloudProviders(cloud1, cloud2, AZURE, GCP) || MatchCloudProviders(cloud1, cloud2, AZURE, IBM) ||
+		MatchCloudProviders(cloud1, cloud2, AWS, GCP) || MatchCloudProviders(cloud1, cloud2, AWS, AZURE) {
 		return 2
 	}
 	return 1
@@ -22,6 +22,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/paraglider-project/paraglider/pkg/paragliderpb"
 )
 
 // TODO @praveingk: Expand tests of SDK functions
@@ -68,3 +70,59 @@ func TestCIDROverlap(t *testing.T) {
 	require.False(t, res4)
 	require.False(t, res5)
 }
+
+func TestGetNumVpnConnections(t *testing.T) {
+	require.Equal(t, 2, GetNumVpnConnections(AZURE, GCP))
+	require.Equal(t, 2, GetNumVpnConnections(IBM, AZURE))
+	require.Equal(t, 2, GetNumVpnConnections(AWS, GCP))
+	require.Equal(t, 2, GetNumVpnConnections(AZURE, AWS))
+	require.Equal(t, 1, GetNumVpnConnections(GCP, IBM))
+}
+
+func TestIsIpAddressPrivate(t *testing.T) {
+	for address, private := range map[string]bool{
+		"10.1.2.3":         true,
+		"192.168.0.0/24":   true,
+		"8.8.8.8":          false,
+		"fd20:a:b::1":      true,
+		"fd20:a:b::/48":    true,
+		"2001:4860::8888":  false,
+		"2600:1f14::/56":   false,
+		"fe80::1":          false,
+		"fc00:1234::/32":   true,
+		"100.64.0.1":       false,
+		"fdff:ffff::1/128": true,
+	} {
+		isPrivate, err := IsIpAddressPrivate(address)
+		require.NoError(t, err)
+		require.Equal(t, private, isPrivate, address)
+	}
+}
+
+func TestIsPermitListRuleIPv6(t *testing.T) {
+	ipv6, err := IsPermitListRuleIPv6(&paragliderpb.PermitListRule{Targets: []string{"10.0.0.0/8", "1.2.3.4"}})
+	require.NoError(t, err)
+	require.False(t, ipv6)
+
+	ipv6, err = IsPermitListRuleIPv6(&paragliderpb.PermitListRule{Targets: []string{"fd20:a::/48", "2001:db8::1"}})
+	require.NoError(t, err)
+	require.True(t, ipv6)
+
+	_, err = IsPermitListRuleIPv6(&paragliderpb.PermitListRule{Targets: []string{"10.0.0.0/8", "fd20:a::/48"}})
+	require.Error(t, err)
+}
+
+func TestGetIPv6SubnetPrefix(t *testing.T) {
+	prefix, err := GetIPv6SubnetPrefix("fd20:a:b::/48")
+	require.NoError(t, err)
+	require.Equal(t, "fd20:a:b::/64", prefix)
+
+	prefix, err = GetIPv6SubnetPrefix("2600:1f14:abc:de00::/56")
+	require.NoError(t, err)
+	require.Equal(t, "2600:1f14:abc:de00::/64", prefix)
+
+	_, err = GetIPv6SubnetPrefix("10.0.0.0/16")
+	require.Error(t, err)
+	_, err = GetIPv6SubnetPrefix("fd20:a:b:c:d::/80")
+	require.Error(t, err)
+}